@@ -0,0 +1,127 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/initialize"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	filesCmd.AddCommand(filesMigrateCmd)
+	filesCmd.AddCommand(filesDedupCmd)
+	filesCmd.AddCommand(filesEncryptCmd)
+	filesCleanupCmd.Flags().BoolVarP(&filesCleanupDryRunFlag, "dry-run", "d", false, "Only show how many files would be deleted.")
+	filesCleanupCmd.Flags().IntVarP(&filesCleanupGracePeriodFlag, "grace-period", "g", -1, "How many hours a file needs to be unused before it is deleted. Defaults to files.cleanup.graceperiod.")
+	filesCmd.AddCommand(filesCleanupCmd)
+	rootCmd.AddCommand(filesCmd)
+}
+
+var filesCmd = &cobra.Command{
+	Use:   "files",
+	Short: "Manage the files stored by Vikunja.",
+}
+
+var filesMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Copy all files from the local basepath into the configured file storage, for example s3.",
+	PreRun: func(_ *cobra.Command, _ []string) {
+		initialize.FullInitWithoutAsync()
+	},
+	Run: func(_ *cobra.Command, _ []string) {
+		migrated, err := files.MigrateLocalFiles()
+		if err != nil {
+			log.Fatalf("Error migrating files: %s", err)
+		}
+		log.Infof("Migrated %d files.", migrated)
+	},
+}
+
+var filesDedupCmd = &cobra.Command{
+	Use:   "dedup",
+	Short: "Hash all files stored before content hashes were introduced so that files with identical content share it.",
+	PreRun: func(_ *cobra.Command, _ []string) {
+		initialize.FullInitWithoutAsync()
+	},
+	Run: func(_ *cobra.Command, _ []string) {
+		deduplicated, err := files.DeduplicateLegacyFiles()
+		if err != nil {
+			log.Fatalf("Error deduplicating files: %s", err)
+		}
+		log.Infof("Deduplicated %d files.", deduplicated)
+	},
+}
+
+var filesEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt all files which were stored before files.encryption was configured.",
+	PreRun: func(_ *cobra.Command, _ []string) {
+		initialize.FullInitWithoutAsync()
+	},
+	Run: func(_ *cobra.Command, _ []string) {
+		encrypted, err := files.EncryptExistingFiles()
+		if err != nil {
+			log.Fatalf("Error encrypting files: %s", err)
+		}
+		log.Infof("Encrypted %d files.", encrypted)
+	},
+}
+
+var (
+	filesCleanupDryRunFlag      bool
+	filesCleanupGracePeriodFlag int
+)
+
+var filesCleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Delete all files which are not used by any attachment, project background, project icon, avatar or data export.",
+	PreRun: func(_ *cobra.Command, _ []string) {
+		initialize.FullInitWithoutAsync()
+	},
+	Run: func(_ *cobra.Command, _ []string) {
+		gracePeriod := filesCleanupGracePeriodFlag
+		if gracePeriod < 0 {
+			gracePeriod = config.FilesCleanupGracePeriod.GetInt()
+		}
+
+		s := db.NewSession()
+		defer s.Close()
+
+		result, err := models.CleanupOrphanedFiles(s, time.Duration(gracePeriod)*time.Hour, filesCleanupDryRunFlag)
+		if err != nil {
+			_ = s.Rollback()
+			log.Fatalf("Error cleaning up files: %s", err)
+		}
+		if err := s.Commit(); err != nil {
+			log.Fatalf("Error cleaning up files: %s", err)
+		}
+
+		if filesCleanupDryRunFlag {
+			log.Infof("Would delete %d orphaned files and reclaim %s.", result.Deleted, result.HumanReadableReclaimed())
+			return
+		}
+		log.Infof("Deleted %d orphaned files, reclaimed %s.", result.Deleted, result.HumanReadableReclaimed())
+	},
+}
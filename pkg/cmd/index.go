@@ -33,35 +33,50 @@ var indexPartialFlag bool
 
 var indexCmd = &cobra.Command{
 	Use:   "index",
-	Short: "Reindex all of Vikunja's data into Typesense. This will remove any existing index.",
+	Short: "Reindex all tasks into the configured search backend. This will remove any existing index.",
 	PreRun: func(_ *cobra.Command, _ []string) {
 		initialize.FullInitWithoutAsync()
 	},
 	Run: func(_ *cobra.Command, _ []string) {
-		if config.TypesenseURL.GetString() == "" {
+		backend := config.SearchBackend.GetString()
+
+		if backend == models.SearchBackendTypesense && config.TypesenseURL.GetString() == "" {
 			log.Error("Typesense not configured")
 			return
 		}
-
-		err := models.CreateTypesenseCollections()
-		if err != nil {
-			log.Criticalf("Could not create Typesense collections: %s", err.Error())
+		if backend == models.SearchBackendMeilisearch && config.MeilisearchURL.GetString() == "" {
+			log.Error("Meilisearch not configured")
 			return
 		}
+
 		if indexPartialFlag {
-			log.Infof("Indexing changed tasks… This may take a while.")
-			err = models.SyncUpdatedTasksIntoTypesense()
+			if backend != models.SearchBackendTypesense {
+				log.Errorf("Partial indexing is only supported with Typesense")
+				return
+			}
+
+			err := models.CreateTypesenseCollections()
 			if err != nil {
-				log.Criticalf("Could not reindex all changed tasks into Typesense: %s", err.Error())
+				log.Criticalf("Could not create Typesense collections: %s", err.Error())
 				return
 			}
-		} else {
-			log.Infof("Indexing all tasks… This may take a while.")
-			err = models.ReindexAllTasks()
+
+			log.Infof("Indexing changed tasks… This may take a while.")
+			err = models.SyncUpdatedTasksIntoTypesense()
 			if err != nil {
-				log.Criticalf("Could not reindex all tasks into Typesense: %s", err.Error())
+				log.Criticalf("Could not reindex all changed tasks into Typesense: %s", err.Error())
 				return
 			}
+
+			log.Infof("Done!")
+			return
+		}
+
+		log.Infof("Indexing all tasks into the %s search backend… This may take a while.", backend)
+		err := models.GetSearchBackend().Reindex()
+		if err != nil {
+			log.Criticalf("Could not reindex all tasks: %s", err.Error())
+			return
 		}
 
 		log.Infof("Done!")
@@ -0,0 +1,159 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/initialize"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	roleFlagTitle        string
+	roleFlagDescription  string
+	roleFlagCapabilities []string
+)
+
+func init() {
+	capabilities := make([]string, 0, len(models.AllCapabilities))
+	for _, c := range models.AllCapabilities {
+		capabilities = append(capabilities, string(c))
+	}
+
+	roleCreateCmd.Flags().StringVarP(&roleFlagTitle, "title", "t", "", "The title of the new role.")
+	_ = roleCreateCmd.MarkFlagRequired("title")
+	roleCreateCmd.Flags().StringVarP(&roleFlagDescription, "description", "d", "", "A description of the new role. Optional.")
+	roleCreateCmd.Flags().StringSliceVarP(&roleFlagCapabilities, "capabilities", "c", []string{}, "A comma separated list of capabilities the role grants. Available: "+strings.Join(capabilities, ", "))
+	_ = roleCreateCmd.MarkFlagRequired("capabilities")
+
+	roleCmd.AddCommand(roleListCmd, roleCreateCmd, roleDeleteCmd)
+	rootCmd.AddCommand(roleCmd)
+}
+
+var roleCmd = &cobra.Command{
+	Use:   "role",
+	Short: "Manage the roles which can be assigned to project shares.",
+}
+
+var roleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Shows a list of all roles.",
+	PreRun: func(_ *cobra.Command, _ []string) {
+		initialize.FullInit()
+	},
+	Run: func(_ *cobra.Command, _ []string) {
+		s := db.NewSession()
+		defer s.Close()
+
+		roles, err := models.ListRoles(s)
+		if err != nil {
+			_ = s.Rollback()
+			log.Fatalf("Error getting roles: %s", err)
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{
+			"ID",
+			"Title",
+			"Capabilities",
+		})
+
+		for _, r := range roles {
+			capabilities := make([]string, 0, len(r.Capabilities))
+			for _, c := range r.Capabilities {
+				capabilities = append(capabilities, string(c))
+			}
+			table.Append([]string{
+				strconv.FormatInt(r.ID, 10),
+				r.Title,
+				strings.Join(capabilities, ", "),
+			})
+		}
+
+		table.Render()
+	},
+}
+
+var roleCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a new role.",
+	PreRun: func(_ *cobra.Command, _ []string) {
+		initialize.FullInit()
+	},
+	Run: func(_ *cobra.Command, _ []string) {
+		s := db.NewSession()
+		defer s.Close()
+
+		role := &models.Role{
+			Title:       roleFlagTitle,
+			Description: roleFlagDescription,
+		}
+		for _, c := range roleFlagCapabilities {
+			role.Capabilities = append(role.Capabilities, models.Capability(strings.TrimSpace(c)))
+		}
+
+		err := models.CreateRole(s, role)
+		if err != nil {
+			_ = s.Rollback()
+			log.Fatalf("Error creating the role: %s", err)
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Fatalf("Error saving everything: %s", err)
+		}
+
+		fmt.Printf("\nRole was created successfully with id %d.\n", role.ID)
+	},
+}
+
+var roleDeleteCmd = &cobra.Command{
+	Use:   "delete [role id]",
+	Short: "Delete a role. All shares which had this role keep their right, but lose the capabilities of the role.",
+	Args:  cobra.ExactArgs(1),
+	PreRun: func(_ *cobra.Command, _ []string) {
+		initialize.FullInit()
+	},
+	Run: func(_ *cobra.Command, args []string) {
+		s := db.NewSession()
+		defer s.Close()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid role id: %s", err)
+		}
+
+		err = models.DeleteRole(s, id)
+		if err != nil {
+			_ = s.Rollback()
+			log.Fatalf("Error deleting the role: %s", err)
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Fatalf("Error saving everything: %s", err)
+		}
+
+		fmt.Println("Role deleted successfully.")
+	},
+}
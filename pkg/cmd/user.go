@@ -47,6 +47,7 @@ var (
 	userFlagDisableUser           bool
 	userFlagDeleteNow             bool
 	userFlagDeleteConfirm         bool
+	userFlagAdmin                 bool
 )
 
 func init() {
@@ -62,6 +63,7 @@ func init() {
 	userUpdateCmd.Flags().StringVarP(&userFlagUsername, "username", "u", "", "The new username of the user.")
 	userUpdateCmd.Flags().StringVarP(&userFlagEmail, "email", "e", "", "The new email address of the user.")
 	userUpdateCmd.Flags().StringVarP(&userFlagAvatar, "avatar-provider", "a", "", "The new avatar provider of the new user.")
+	userUpdateCmd.Flags().BoolVar(&userFlagAdmin, "admin", false, "Make the user an instance admin. Use --admin=false to revoke it.")
 
 	// Reset PW flags
 	userResetPasswordCmd.Flags().BoolVarP(&userFlagResetPasswordDirectly, "direct", "d", false, "If provided, reset the password directly instead of sending the user a reset mail.")
@@ -151,6 +153,7 @@ var userListCmd = &cobra.Command{
 			"Username",
 			"Email",
 			"Status",
+			"Admin",
 			"Created",
 			"Updated",
 		})
@@ -161,6 +164,7 @@ var userListCmd = &cobra.Command{
 				u.Username,
 				u.Email,
 				u.Status.String(),
+				strconv.FormatBool(u.IsAdmin),
 				u.Created.Format(time.RFC3339),
 				u.Updated.Format(time.RFC3339),
 			})
@@ -217,7 +221,7 @@ var userUpdateCmd = &cobra.Command{
 	PreRun: func(_ *cobra.Command, _ []string) {
 		initialize.FullInit()
 	},
-	Run: func(_ *cobra.Command, args []string) {
+	Run: func(cmd *cobra.Command, args []string) {
 		s := db.NewSession()
 		defer s.Close()
 
@@ -239,6 +243,14 @@ var userUpdateCmd = &cobra.Command{
 			log.Fatalf("Error updating the user: %s", err)
 		}
 
+		if cmd.Flags().Changed("admin") {
+			err = user.SetUserAdmin(s, u, userFlagAdmin)
+			if err != nil {
+				_ = s.Rollback()
+				log.Fatalf("Error updating the user: %s", err)
+			}
+		}
+
 		if err := s.Commit(); err != nil {
 			log.Fatalf("Error saving everything: %s", err)
 		}
@@ -38,42 +38,65 @@ type Key string
 // These constants hold all config value keys
 const (
 	// #nosec
-	ServiceJWTSecret       Key = `service.JWTSecret`
-	ServiceJWTTTL          Key = `service.jwtttl`
-	ServiceJWTTTLLong      Key = `service.jwtttllong`
-	ServiceInterface       Key = `service.interface`
-	ServiceUnixSocket      Key = `service.unixsocket`
-	ServiceUnixSocketMode  Key = `service.unixsocketmode`
-	ServicePublicURL       Key = `service.publicurl`
-	ServiceEnableCaldav    Key = `service.enablecaldav`
-	ServiceRootpath        Key = `service.rootpath`
-	ServiceMaxItemsPerPage Key = `service.maxitemsperpage`
-	ServiceDemoMode        Key = `service.demomode`
+	ServiceJWTSecret           Key = `service.JWTSecret`
+	ServiceJWTTTL              Key = `service.jwtttl`
+	ServiceJWTTTLLong          Key = `service.jwtttllong`
+	ServiceJWTTTLImpersonation Key = `service.jwtttlimpersonation`
+	ServiceInterface           Key = `service.interface`
+	ServiceUnixSocket          Key = `service.unixsocket`
+	ServiceUnixSocketMode      Key = `service.unixsocketmode`
+	ServicePublicURL           Key = `service.publicurl`
+	ServiceEnableCaldav        Key = `service.enablecaldav`
+	ServiceRootpath            Key = `service.rootpath`
+	ServiceMaxItemsPerPage     Key = `service.maxitemsperpage`
+	ServiceDemoMode            Key = `service.demomode`
 	// Deprecated: Use metrics.enabled
 	ServiceEnableMetrics         Key = `service.enablemetrics`
 	ServiceMotd                  Key = `service.motd`
 	ServiceEnableLinkSharing     Key = `service.enablelinksharing`
 	ServiceEnableRegistration    Key = `service.enableregistration`
 	ServiceEnableTaskAttachments Key = `service.enabletaskattachments`
+	ServiceFetchLinkMetadata     Key = `service.fetchlinkmetadata`
 	ServiceTimeZone              Key = `service.timezone`
 	ServiceEnableTaskComments    Key = `service.enabletaskcomments`
 	ServiceEnableTotp            Key = `service.enabletotp`
+	ServiceEnableWebAuthn        Key = `service.enablewebauthn`
 	ServiceTestingtoken          Key = `service.testingtoken`
 	ServiceEnableEmailReminders  Key = `service.enableemailreminders`
+	ServiceOverdueTasksThreshold Key = `service.overduetasksthreshold`
 	ServiceEnableUserDeletion    Key = `service.enableuserdeletion`
 	ServiceMaxAvatarSize         Key = `service.maxavatarsize`
 	ServiceAllowIconChanges      Key = `service.allowiconchanges`
 	ServiceCustomLogoURL         Key = `service.customlogourl`
 	ServiceEnablePublicTeams     Key = `service.enablepublicteams`
+	ServiceEnablePublicProjects  Key = `service.enablepublicprojects`
+
+	ServiceNotificationBatchWindow   Key = `service.notificationbatchwindow`
+	ServiceNotificationRetentionDays Key = `service.notificationretentiondays`
+	ServicePDFExportFont             Key = `service.pdfexportfont`
 
 	SentryEnabled         Key = `sentry.enabled`
 	SentryDsn             Key = `sentry.dsn`
 	SentryFrontendEnabled Key = `sentry.frontendenabled`
 	SentryFrontendDsn     Key = `sentry.frontenddsn`
 
-	AuthLocalEnabled    Key = `auth.local.enabled`
-	AuthOpenIDEnabled   Key = `auth.openid.enabled`
-	AuthOpenIDProviders Key = `auth.openid.providers`
+	AuthLocalEnabled             Key = `auth.local.enabled`
+	AuthOpenIDEnabled            Key = `auth.openid.enabled`
+	AuthOpenIDProviders          Key = `auth.openid.providers`
+	AuthLdapEnabled              Key = `auth.ldap.enabled`
+	AuthLdapHost                 Key = `auth.ldap.host`
+	AuthLdapPort                 Key = `auth.ldap.port`
+	AuthLdapUseTLS               Key = `auth.ldap.usetls`
+	AuthLdapVerifyTLS            Key = `auth.ldap.verifytls`
+	AuthLdapBaseDN               Key = `auth.ldap.basedn`
+	AuthLdapBindDN               Key = `auth.ldap.binddn`
+	AuthLdapBindPassword         Key = `auth.ldap.bindpassword`
+	AuthLdapUserFilter           Key = `auth.ldap.userfilter`
+	AuthLdapGroupSyncEnabled     Key = `auth.ldap.groupsyncenabled`
+	AuthLdapGroupSyncFilter      Key = `auth.ldap.groupsyncfilter`
+	AuthLdapAttributeUsername    Key = `auth.ldap.attribute.username`
+	AuthLdapAttributeEmail       Key = `auth.ldap.attribute.email`
+	AuthLdapAttributeDisplayname Key = `auth.ldap.attribute.displayname`
 
 	LegalImprintURL Key = `legal.imprinturl`
 	LegalPrivacyURL Key = `legal.privacyurl`
@@ -97,6 +120,11 @@ const (
 	TypesenseURL     Key = `typesense.url`
 	TypesenseAPIKey  Key = `typesense.apikey`
 
+	SearchBackend Key = `search.backend`
+
+	MeilisearchURL    Key = `meilisearch.url`
+	MeilisearchAPIKey Key = `meilisearch.apikey`
+
 	MailerEnabled       Key = `mailer.enabled`
 	MailerHost          Key = `mailer.host`
 	MailerPort          Key = `mailer.port`
@@ -134,8 +162,34 @@ const (
 	RateLimitStore             Key = `ratelimit.store`
 	RateLimitNoAuthRoutesLimit Key = `ratelimit.noauthlimit`
 
-	FilesBasePath Key = `files.basepath`
-	FilesMaxSize  Key = `files.maxsize`
+	FilesBasePath                Key = `files.basepath`
+	FilesMaxSize                 Key = `files.maxsize`
+	FilesType                    Key = `files.type`
+	FilesS3Endpoint              Key = `files.s3.endpoint`
+	FilesS3Bucket                Key = `files.s3.bucket`
+	FilesS3Region                Key = `files.s3.region`
+	FilesS3AccessKey             Key = `files.s3.accesskey`
+	FilesS3SecretKey             Key = `files.s3.secretkey`
+	FilesS3UsePathStyle          Key = `files.s3.usepathstyle`
+	FilesS3PresignedDownloads    Key = `files.s3.presigneddownloads`
+	FilesS3PresignedDownloadsTTL Key = `files.s3.presigneddownloadsttl`
+	FilesAntivirusType           Key = `files.antivirus.type`
+	FilesAntivirusClamAVAddress  Key = `files.antivirus.clamavaddress`
+	FilesAntivirusCommand        Key = `files.antivirus.command`
+	FilesAntivirusTimeout        Key = `files.antivirus.timeout`
+	FilesAntivirusAction         Key = `files.antivirus.action`
+	FilesAntivirusAdminEmail     Key = `files.antivirus.adminemail`
+	FilesPreviewsPDFCommand      Key = `files.previews.pdfcommand`
+	FilesPreviewsMaxTextLength   Key = `files.previews.maxtextlength`
+	FilesCleanupEnabled          Key = `files.cleanup.enabled`
+	FilesCleanupGracePeriod      Key = `files.cleanup.graceperiod`
+	FilesEncryptionType          Key = `files.encryption.type`
+	FilesEncryptionKey           Key = `files.encryption.key`
+	FilesEncryptionKMSKeyID      Key = `files.encryption.kms.keyid`
+	FilesEncryptionKMSRegion     Key = `files.encryption.kms.region`
+	FilesEncryptionKMSEndpoint   Key = `files.encryption.kms.endpoint`
+	FilesEncryptionKMSAccessKey  Key = `files.encryption.kms.accesskey`
+	FilesEncryptionKMSSecretKey  Key = `files.encryption.kms.secretkey`
 
 	MigrationTodoistEnable             Key = `migration.todoist.enable`
 	MigrationTodoistClientID           Key = `migration.todoist.clientid`
@@ -179,10 +233,54 @@ const (
 	DefaultSettingsTimezone                    Key = `defaultsettings.timezone`
 	DefaultSettingsOverdueTaskRemindersTime    Key = `defaultsettings.overdue_tasks_reminders_time`
 
-	WebhooksEnabled        Key = `webhooks.enabled`
-	WebhooksTimeoutSeconds Key = `webhooks.timeoutseconds`
-	WebhooksProxyURL       Key = `webhooks.proxyurl`
-	WebhooksProxyPassword  Key = `webhooks.proxypassword`
+	WebhooksEnabled               Key = `webhooks.enabled`
+	WebhooksTimeoutSeconds        Key = `webhooks.timeoutseconds`
+	WebhooksMaxRetries            Key = `webhooks.maxretries`
+	WebhooksDeliveryRetentionDays Key = `webhooks.deliveryretentiondays`
+	WebhooksProxyURL              Key = `webhooks.proxyurl`
+	WebhooksProxyPassword         Key = `webhooks.proxypassword`
+
+	MatrixHomeserver Key = `matrix.homeserver`
+
+	TelegramEnabled  Key = `telegram.enabled`
+	TelegramBotToken Key = `telegram.bottoken`
+	TelegramSecret   Key = `telegram.secret`
+
+	WebPushEnabled         Key = `webpush.enabled`
+	WebPushVAPIDPublicKey  Key = `webpush.vapidpublickey`
+	WebPushVAPIDPrivateKey Key = `webpush.vapidprivatekey`
+	WebPushSubject         Key = `webpush.subject`
+
+	TaskFormsEnabled          Key = `taskforms.enabled`
+	TaskFormsRateLimit        Key = `taskforms.ratelimit`
+	TaskFormsCaptchaSiteKey   Key = `taskforms.captcha.sitekey`
+	TaskFormsCaptchaSecret    Key = `taskforms.captcha.secret`
+	TaskFormsCaptchaVerifyURL Key = `taskforms.captcha.verifyurl`
+
+	EmailIntakeEnabled Key = `emailintake.enabled`
+	EmailIntakeDomain  Key = `emailintake.domain`
+	EmailIntakeSecret  Key = `emailintake.secret`
+	EmailIntakeMaxSize Key = `emailintake.maxsize`
+
+	SCIMEnabled     Key = `scim.enabled`
+	SCIMToken       Key = `scim.token`
+	SCIMIssuer      Key = `scim.issuer`
+	SCIMDeleteUsers Key = `scim.deleteusers`
+
+	QuotasMaxOpenTasks              Key = `quotas.maxopentasks`
+	QuotasMaxAttachmentsSize        Key = `quotas.maxattachmentssize`
+	QuotasMaxAttachmentsSizePerUser Key = `quotas.maxattachmentssizeperuser`
+	QuotasMaxProjectsPerUser        Key = `quotas.maxprojectsperuser`
+	QuotasMaxTasksPerUser           Key = `quotas.maxtasksperuser`
+	QuotasMaxLinkSharesPerUser      Key = `quotas.maxlinksharesperuser`
+
+	PasswordPolicyMinLength        Key = `passwordpolicy.minlength`
+	PasswordPolicyDisallowUsername Key = `passwordpolicy.disallowusername`
+	PasswordPolicyCheckBreached    Key = `passwordpolicy.checkbreached`
+	PasswordPolicyBreachedCheckURL Key = `passwordpolicy.breachedcheckurl`
+
+	LoginNotificationsEnabled     Key = `loginnotifications.enabled`
+	LoginNotificationsLocationURL Key = `loginnotifications.locationurl`
 )
 
 // GetString returns a string config value
@@ -291,8 +389,9 @@ func InitDefaultConfig() {
 
 	// Service
 	ServiceJWTSecret.setDefault(random)
-	ServiceJWTTTL.setDefault(259200)      // 72 hours
-	ServiceJWTTTLLong.setDefault(2592000) // 30 days
+	ServiceJWTTTL.setDefault(259200)            // 72 hours
+	ServiceJWTTTLLong.setDefault(2592000)       // 30 days
+	ServiceJWTTTLImpersonation.setDefault(3600) // 1 hour
 	ServiceInterface.setDefault(":3456")
 	ServiceUnixSocket.setDefault("")
 	ServicePublicURL.setDefault("")
@@ -305,15 +404,22 @@ func InitDefaultConfig() {
 	ServiceEnableLinkSharing.setDefault(true)
 	ServiceEnableRegistration.setDefault(true)
 	ServiceEnableTaskAttachments.setDefault(true)
+	ServiceFetchLinkMetadata.setDefault(true)
 	ServiceTimeZone.setDefault("GMT")
 	ServiceEnableTaskComments.setDefault(true)
 	ServiceEnableTotp.setDefault(true)
+	ServiceEnableWebAuthn.setDefault(true)
 	ServiceEnableEmailReminders.setDefault(true)
+	ServiceOverdueTasksThreshold.setDefault(0)
+	ServiceNotificationBatchWindow.setDefault(60)
+	ServiceNotificationRetentionDays.setDefault(0)
+	ServicePDFExportFont.setDefault("")
 	ServiceEnableUserDeletion.setDefault(true)
 	ServiceMaxAvatarSize.setDefault(1024)
 	ServiceDemoMode.setDefault(false)
 	ServiceAllowIconChanges.setDefault(true)
 	ServiceEnablePublicTeams.setDefault(false)
+	ServiceEnablePublicProjects.setDefault(false)
 
 	// Sentry
 	SentryDsn.setDefault("https://440eedc957d545a795c17bbaf477497c@o1047380.ingest.sentry.io/4504254983634944")
@@ -322,6 +428,16 @@ func InitDefaultConfig() {
 	// Auth
 	AuthLocalEnabled.setDefault(true)
 	AuthOpenIDEnabled.setDefault(false)
+	AuthLdapEnabled.setDefault(false)
+	AuthLdapPort.setDefault(389)
+	AuthLdapUseTLS.setDefault(false)
+	AuthLdapVerifyTLS.setDefault(true)
+	AuthLdapUserFilter.setDefault("(&(objectclass=person)(uid=%[1]s))")
+	AuthLdapGroupSyncEnabled.setDefault(false)
+	AuthLdapGroupSyncFilter.setDefault("(&(|(objectclass=groupOfNames)(objectclass=group))(member=%[1]s))")
+	AuthLdapAttributeUsername.setDefault("uid")
+	AuthLdapAttributeEmail.setDefault("mail")
+	AuthLdapAttributeDisplayname.setDefault("displayName")
 
 	// Database
 	DatabaseType.setDefault("sqlite")
@@ -340,8 +456,12 @@ func InitDefaultConfig() {
 	DatabaseTLS.setDefault("false")
 
 	// Typesense
+	// Deprecated, only read to set search.backend for old configs
 	TypesenseEnabled.setDefault(false)
 
+	// Search
+	SearchBackend.setDefault("database")
+
 	// Mailer
 	MailerEnabled.setDefault(false)
 	MailerHost.setDefault("")
@@ -382,6 +502,23 @@ func InitDefaultConfig() {
 	// Files
 	FilesBasePath.setDefault("files")
 	FilesMaxSize.setDefault("20MB")
+	FilesType.setDefault("local")
+	FilesS3Region.setDefault("us-east-1")
+	FilesS3UsePathStyle.setDefault(false)
+	FilesS3PresignedDownloads.setDefault(false)
+	FilesS3PresignedDownloadsTTL.setDefault(3600)
+	FilesAntivirusType.setDefault("")
+	FilesAntivirusClamAVAddress.setDefault("tcp://127.0.0.1:3310")
+	FilesAntivirusCommand.setDefault("")
+	FilesAntivirusTimeout.setDefault(60)
+	FilesAntivirusAction.setDefault("reject")
+	FilesAntivirusAdminEmail.setDefault("")
+	FilesPreviewsPDFCommand.setDefault("pdftoppm")
+	FilesPreviewsMaxTextLength.setDefault(65536)
+	FilesCleanupEnabled.setDefault(true)
+	FilesCleanupGracePeriod.setDefault(24)
+	FilesEncryptionType.setDefault("")
+	FilesEncryptionKMSRegion.setDefault("us-east-1")
 	// Cors
 	CorsEnable.setDefault(false)
 	CorsOrigins.setDefault([]string{"*"})
@@ -407,6 +544,37 @@ func InitDefaultConfig() {
 	// Webhook
 	WebhooksEnabled.setDefault(true)
 	WebhooksTimeoutSeconds.setDefault(30)
+	WebhooksMaxRetries.setDefault(3)
+	WebhooksDeliveryRetentionDays.setDefault(30)
+	// Telegram
+	TelegramEnabled.setDefault(false)
+	// Web push
+	WebPushEnabled.setDefault(false)
+	// Task forms
+	TaskFormsEnabled.setDefault(true)
+	TaskFormsRateLimit.setDefault(5)
+	TaskFormsCaptchaVerifyURL.setDefault("https://api.hcaptcha.com/siteverify")
+	// Email intake
+	EmailIntakeEnabled.setDefault(false)
+	EmailIntakeMaxSize.setDefault("25MB")
+	// SCIM
+	SCIMEnabled.setDefault(false)
+	SCIMDeleteUsers.setDefault(false)
+	// Quotas
+	QuotasMaxOpenTasks.setDefault(0)
+	QuotasMaxAttachmentsSize.setDefault("0")
+	QuotasMaxAttachmentsSizePerUser.setDefault("0")
+	QuotasMaxProjectsPerUser.setDefault(0)
+	QuotasMaxTasksPerUser.setDefault(0)
+	QuotasMaxLinkSharesPerUser.setDefault(0)
+	// Password policy
+	PasswordPolicyMinLength.setDefault(8)
+	PasswordPolicyDisallowUsername.setDefault(false)
+	PasswordPolicyCheckBreached.setDefault(false)
+	PasswordPolicyBreachedCheckURL.setDefault("https://api.pwnedpasswords.com/range/")
+	// Login notifications
+	LoginNotificationsEnabled.setDefault(true)
+	LoginNotificationsLocationURL.setDefault("")
 }
 
 // InitConfig initializes the config, sets defaults etc.
@@ -453,6 +621,12 @@ func InitConfig() {
 		log.Info("No config file found, using default or config from environment variables.")
 	}
 
+	// Typesense could be enabled on its own before there were search backends
+	if TypesenseEnabled.GetBool() {
+		log.Warning("typesense.enabled is deprecated, set search.backend to typesense instead.")
+		SearchBackend.Set("typesense")
+	}
+
 	if RateLimitStore.GetString() == "keyvalue" {
 		RateLimitStore.Set(KeyvalueType.GetString())
 	}
@@ -0,0 +1,243 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package files
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/notifications"
+
+	"code.vikunja.io/web"
+)
+
+// clamd accepts streams in chunks, each prefixed with its length
+const clamavChunkSize = 64 * 1024
+
+type virusScanner interface {
+	// scan returns the name of the virus found in the content or an empty string if the content is clean
+	scan(content *os.File) (signature string, err error)
+}
+
+func getVirusScanner() (virusScanner, error) {
+	timeout := time.Duration(config.FilesAntivirusTimeout.GetInt()) * time.Second
+
+	switch config.FilesAntivirusType.GetString() {
+	case "":
+		return nil, nil
+	case "clamav":
+		return &clamavScanner{address: config.FilesAntivirusClamAVAddress.GetString(), timeout: timeout}, nil
+	case "command":
+		return &commandScanner{command: config.FilesAntivirusCommand.GetString(), timeout: timeout}, nil
+	default:
+		return nil, fmt.Errorf("unknown antivirus type %s", config.FilesAntivirusType.GetString())
+	}
+}
+
+// Scan checks content for viruses before it is processed any further.
+// Files created with Create and its variants are scanned anyway, this is only needed for uploads which are
+// converted before they are stored.
+func Scan(content io.Reader, name string, a web.Auth) error {
+	if config.FilesAntivirusType.GetString() == "" {
+		return nil
+	}
+
+	tmp, hash, err := bufferContent(content)
+	if err != nil {
+		return err
+	}
+	defer removeBufferedContent(tmp)
+
+	return scanContent(tmp, hash, name, a)
+}
+
+// scanContent checks the buffered content of an upload with the configured virus scanner. Infected content is
+// quarantined if configured and reported to the admin.
+func scanContent(content *os.File, hash, name string, a web.Auth) (err error) {
+	scanner, err := getVirusScanner()
+	if err != nil || scanner == nil {
+		return err
+	}
+
+	signature, err := scanner.scan(content)
+	if _, seekErr := content.Seek(0, io.SeekStart); err == nil {
+		err = seekErr
+	}
+	if err != nil {
+		return fmt.Errorf("could not scan %s for viruses: %w", name, err)
+	}
+	if signature == "" {
+		return nil
+	}
+
+	log.Warningf("Rejected upload %s by user %d because it is infected with %s", name, a.GetID(), signature)
+
+	quarantinedAt := ""
+	if config.FilesAntivirusAction.GetString() == "quarantine" {
+		quarantinedAt = config.FilesBasePath.GetString() + "/quarantine/" + hash
+		err = writeFile(fs, quarantinedAt, content)
+		if err != nil {
+			log.Errorf("Could not quarantine infected upload %s: %s", name, err)
+			quarantinedAt = ""
+		}
+	}
+
+	adminEmail := config.FilesAntivirusAdminEmail.GetString()
+	if adminEmail != "" {
+		err = notifications.Notify(&antivirusAdmin{email: adminEmail}, &InfectedFileNotification{
+			FileName:      name,
+			Signature:     signature,
+			UserID:        a.GetID(),
+			QuarantinedAt: quarantinedAt,
+		})
+		if err != nil {
+			log.Errorf("Could not notify the admin about infected upload %s: %s", name, err)
+		}
+	}
+
+	return ErrFileIsInfected{Name: name, Signature: signature}
+}
+
+type clamavScanner struct {
+	address string
+	timeout time.Duration
+}
+
+func (c *clamavScanner) scan(content *os.File) (signature string, err error) {
+	address, err := url.Parse(c.address)
+	if err != nil {
+		return "", err
+	}
+	network, host := "tcp", address.Host
+	if address.Scheme == "unix" {
+		network, host = "unix", address.Path
+	}
+
+	conn, err := net.DialTimeout(network, host, c.timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	err = conn.SetDeadline(time.Now().Add(c.timeout))
+	if err != nil {
+		return "", err
+	}
+
+	_, err = conn.Write([]byte("zINSTREAM\x00"))
+	if err != nil {
+		return "", err
+	}
+
+	chunk := make([]byte, clamavChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := content.Read(chunk)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err = conn.Write(size); err != nil {
+				return "", err
+			}
+			if _, err = conn.Write(chunk[:n]); err != nil {
+				return "", err
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return "", readErr
+		}
+	}
+
+	// A chunk without content ends the stream
+	_, err = conn.Write([]byte{0, 0, 0, 0})
+	if err != nil {
+		return "", err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return parseClamdReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamdReply reads replies like "stream: OK" or "stream: Eicar-Signature FOUND"
+func parseClamdReply(reply string) (signature string, err error) {
+	result := strings.TrimPrefix(reply, "stream: ")
+	if result == "OK" {
+		return "", nil
+	}
+	if strings.HasSuffix(result, " FOUND") {
+		return strings.TrimSuffix(result, " FOUND"), nil
+	}
+	return "", fmt.Errorf("clamd replied with an error: %s", reply)
+}
+
+type commandScanner struct {
+	command string
+	timeout time.Duration
+}
+
+func (c *commandScanner) scan(content *os.File) (signature string, err error) {
+	args := strings.Fields(c.command)
+	if len(args) == 0 {
+		return "", errors.New("files.antivirus.command is empty")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	args = append(args, content.Name())
+	output, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput()
+	if err == nil {
+		return "", nil
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	// Scanners in the style of clamscan print "<path>: <signature> FOUND" for infected files
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasSuffix(line, " FOUND") {
+			continue
+		}
+		line = strings.TrimSuffix(line, " FOUND")
+		if i := strings.LastIndex(line, ": "); i != -1 {
+			line = line[i+2:]
+		}
+		return line, nil
+	}
+
+	return "unknown", nil
+}
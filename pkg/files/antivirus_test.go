@@ -0,0 +1,142 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package files
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+func TestScan_Command(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "scan.sh")
+	err := os.WriteFile(script, []byte(`#!/bin/sh
+if grep -q EICAR "$1"; then
+	echo "$1: Eicar-Test-Signature FOUND"
+	exit 1
+fi
+echo "$1: OK"
+`), 0o755)
+	require.NoError(t, err)
+
+	config.FilesAntivirusType.Set("command")
+	config.FilesAntivirusCommand.Set(script)
+	defer config.FilesAntivirusType.Set("")
+	ta := &testauth{id: 1}
+
+	t.Run("clean", func(t *testing.T) {
+		initFixtures(t)
+		_, err := Create(strings.NewReader("clean content"), "clean.txt", 13, ta)
+		require.NoError(t, err)
+	})
+	t.Run("infected", func(t *testing.T) {
+		initFixtures(t)
+		_, err := Create(strings.NewReader(eicar), "infected.txt", uint64(len(eicar)), ta)
+		require.Error(t, err)
+		assert.True(t, IsErrFileIsInfected(err))
+		assert.Equal(t, "Eicar-Test-Signature", err.(ErrFileIsInfected).Signature)
+	})
+	t.Run("quarantine", func(t *testing.T) {
+		initFixtures(t)
+		config.FilesAntivirusAction.Set("quarantine")
+		defer config.FilesAntivirusAction.Set("reject")
+
+		tmp, hash, err := bufferContent(strings.NewReader(eicar))
+		require.NoError(t, err)
+		removeBufferedContent(tmp)
+
+		err = Scan(strings.NewReader(eicar), "infected.txt", ta)
+		require.Error(t, err)
+		assert.True(t, IsErrFileIsInfected(err))
+
+		_, err = FileStat(config.FilesBasePath.GetString() + "/quarantine/" + hash)
+		require.NoError(t, err)
+	})
+}
+
+func TestScan_ClamAV(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	// A minimal clamd which only understands INSTREAM
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			r := bufio.NewReader(conn)
+			_, _ = r.ReadString(0)
+			content := []byte{}
+			for {
+				size := make([]byte, 4)
+				if _, err := io.ReadFull(r, size); err != nil {
+					break
+				}
+				length := binary.BigEndian.Uint32(size)
+				if length == 0 {
+					break
+				}
+				chunk := make([]byte, length)
+				if _, err := io.ReadFull(r, chunk); err != nil {
+					break
+				}
+				content = append(content, chunk...)
+			}
+			reply := "stream: OK\x00"
+			if strings.Contains(string(content), "EICAR") {
+				reply = "stream: Eicar-Signature FOUND\x00"
+			}
+			_, _ = conn.Write([]byte(reply))
+			_ = conn.Close()
+		}
+	}()
+
+	config.FilesAntivirusType.Set("clamav")
+	config.FilesAntivirusClamAVAddress.Set("tcp://" + listener.Addr().String())
+	defer config.FilesAntivirusType.Set("")
+	ta := &testauth{id: 1}
+
+	t.Run("clean", func(t *testing.T) {
+		err := Scan(strings.NewReader("clean content"), "clean.txt", ta)
+		require.NoError(t, err)
+	})
+	t.Run("infected", func(t *testing.T) {
+		err := Scan(strings.NewReader(eicar), "infected.txt", ta)
+		require.Error(t, err)
+		assert.True(t, IsErrFileIsInfected(err))
+		assert.Equal(t, "Eicar-Signature", err.(ErrFileIsInfected).Signature)
+	})
+}
+
+func TestParseClamdReply(t *testing.T) {
+	_, err := parseClamdReply("INSTREAM size limit exceeded. ERROR")
+	require.Error(t, err)
+}
@@ -0,0 +1,249 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package files
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/metrics"
+	"code.vikunja.io/api/pkg/modules/keyvalue"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// fileContent counts how many files use the stored content with a hash. Files sharing content can be created while
+// another one is deleted. The row is locked while the count changes, this makes sure the content is never removed
+// while another file starts using it.
+type fileContent struct {
+	Sha256         string `xorm:"char(64) not null pk"`
+	ReferenceCount int64  `xorm:"bigint not null default 0"`
+}
+
+// TableName is the table name for the file contents table
+func (fileContent) TableName() string {
+	return "file_contents"
+}
+
+func addContentReference(s *xorm.Session, hash string) error {
+	updated, err := s.
+		Where("sha256 = ?", hash).
+		Incr("reference_count").
+		Update(&fileContent{})
+	if err != nil || updated > 0 {
+		return err
+	}
+
+	_, err = s.Insert(&fileContent{Sha256: hash, ReferenceCount: 1})
+	return err
+}
+
+// removeContentReference returns true if no file uses the content anymore
+func removeContentReference(s *xorm.Session, hash string) (unused bool, err error) {
+	_, err = s.
+		Where("sha256 = ?", hash).
+		Decr("reference_count").
+		Update(&fileContent{})
+	if err != nil {
+		return false, err
+	}
+
+	content := &fileContent{}
+	exists, err := s.Where("sha256 = ?", hash).Get(content)
+	if err != nil {
+		return false, err
+	}
+	if exists && content.ReferenceCount > 0 {
+		return false, nil
+	}
+
+	_, err = s.Where("sha256 = ?", hash).Delete(&fileContent{})
+	return true, err
+}
+
+// contentFileName returns where content with that hash is stored. The first two characters of the hash are used as
+// directory to avoid putting all files into one directory.
+func contentFileName(hash string) string {
+	return config.FilesBasePath.GetString() + "/content/" + hash[:2] + "/" + hash
+}
+
+// bufferContent writes the content into a temporary file while hashing it. The hash needs to be known before the
+// content can be stored, but uploads can only be read once.
+func bufferContent(content io.Reader) (tmp *os.File, hash string, err error) {
+	tmp, err = os.CreateTemp("", "vikunja-file-")
+	if err != nil {
+		return nil, "", err
+	}
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, h), content)
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		removeBufferedContent(tmp)
+		return nil, "", err
+	}
+
+	return tmp, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func removeBufferedContent(tmp *os.File) {
+	_ = tmp.Close()
+	_ = os.Remove(tmp.Name())
+}
+
+// saveContent stores the content of a file unless another file with the same content was stored already
+func (f *File) saveContent(content io.Reader) error {
+	_, err := afs.Stat(f.getFileName())
+	if err == nil {
+		return keyvalue.IncrBy(metrics.FilesCountKey, 1)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return f.Save(content)
+}
+
+// Copy creates a new file with the same content. The file needs to be loaded already.
+// Only the metadata is copied, both files share the stored content. Files stored before hashes were introduced are
+// copied completely, the copy is hashed.
+func (f *File) Copy(s *xorm.Session, a web.Auth) (file *File, err error) {
+	if f.Sha256 == "" {
+		err = f.LoadFileByID()
+		if err != nil {
+			return nil, err
+		}
+		defer f.File.Close()
+
+		return CreateWithMimeAndSession(s, f.File, f.Name, f.Size, a, f.Mime, false)
+	}
+
+	file = &File{
+		Name:        f.Name,
+		Mime:        f.Mime,
+		Size:        f.Size,
+		Sha256:      f.Sha256,
+		CreatedByID: a.GetID(),
+	}
+	_, err = s.Insert(file)
+	if err != nil {
+		return nil, err
+	}
+	err = addContentReference(s, file.Sha256)
+	if err != nil {
+		return nil, err
+	}
+
+	// The original could have been deleted together with its content in the meantime
+	_, err = afs.Stat(file.getFileName())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrFileDoesNotExist{FileID: f.ID}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return file, keyvalue.IncrBy(metrics.FilesCountKey, 1)
+}
+
+// DeduplicateLegacyFiles hashes all files which were stored before hashes were introduced and moves their content to
+// the deduplicated storage. Files with identical content then share it.
+func DeduplicateLegacyFiles() (deduplicated int, err error) {
+	legacyFiles := []*File{}
+	err = x.
+		Where("sha256 IS NULL OR sha256 = ''").
+		OrderBy("id asc").
+		Find(&legacyFiles)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, file := range legacyFiles {
+		legacyName := file.getFileName()
+		src, err := afs.Open(legacyName)
+		if errors.Is(err, os.ErrNotExist) {
+			log.Warningf("Skipping file %d because it does not exist at %s", file.ID, legacyName)
+			continue
+		}
+		if err != nil {
+			return deduplicated, err
+		}
+
+		content, hash, err := bufferContent(src)
+		_ = src.Close()
+		if err != nil {
+			return deduplicated, err
+		}
+
+		file.Sha256 = hash
+		err = file.deduplicate(content)
+		removeBufferedContent(content)
+		if err != nil {
+			return deduplicated, err
+		}
+
+		err = afs.Remove(legacyName)
+		if err != nil {
+			log.Errorf("Could not remove the old content of file %d at %s: %s", file.ID, legacyName, err)
+		}
+
+		deduplicated++
+		log.Infof("Deduplicated file %d (%d/%d)", file.ID, deduplicated, len(legacyFiles))
+	}
+
+	return deduplicated, nil
+}
+
+func (f *File) deduplicate(content io.Reader) (err error) {
+	s := x.NewSession()
+	defer s.Close()
+
+	err = s.Begin()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("id = ?", f.ID).Cols("sha256").Update(f)
+	if err != nil {
+		_ = s.Rollback()
+		return err
+	}
+	err = addContentReference(s, f.Sha256)
+	if err != nil {
+		_ = s.Rollback()
+		return err
+	}
+
+	_, err = afs.Stat(f.getFileName())
+	if errors.Is(err, os.ErrNotExist) {
+		err = writeFile(fs, f.getFileName(), content)
+	}
+	if err != nil {
+		_ = s.Rollback()
+		return err
+	}
+
+	return s.Commit()
+}
@@ -0,0 +1,137 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package files
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreate_Deduplication(t *testing.T) {
+	ta := &testauth{id: 1}
+
+	t.Run("identical content is stored once", func(t *testing.T) {
+		initFixtures(t)
+
+		first, err := Create(strings.NewReader("same content"), "first.txt", 12, ta)
+		require.NoError(t, err)
+		second, err := Create(strings.NewReader("same content"), "second.txt", 12, ta)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, first.ID, second.ID)
+		assert.Len(t, first.Sha256, 64)
+		assert.Equal(t, first.Sha256, second.Sha256)
+		assert.Equal(t, first.getFileName(), second.getFileName())
+
+		// The content stays until the last file using it is deleted
+		require.NoError(t, first.Delete())
+		_, err = FileStat(second.getFileName())
+		require.NoError(t, err)
+		db.AssertExists(t, "file_contents", map[string]interface{}{
+			"sha256":          second.Sha256,
+			"reference_count": 1,
+		}, false)
+
+		require.NoError(t, second.Delete())
+		_, err = FileStat(second.getFileName())
+		require.Error(t, err)
+		db.AssertMissing(t, "file_contents", map[string]interface{}{
+			"sha256": second.Sha256,
+		})
+	})
+	t.Run("load by id only", func(t *testing.T) {
+		initFixtures(t)
+
+		created, err := Create(strings.NewReader("some content"), "file.txt", 12, ta)
+		require.NoError(t, err)
+
+		f := &File{ID: created.ID}
+		require.NoError(t, f.LoadFileByID())
+		defer f.File.Close()
+		content, err := io.ReadAll(f.File)
+		require.NoError(t, err)
+		assert.Equal(t, "some content", string(content))
+	})
+}
+
+func TestFile_Copy(t *testing.T) {
+	ta := &testauth{id: 2}
+
+	t.Run("shares the content", func(t *testing.T) {
+		initFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		original, err := Create(strings.NewReader("copied content"), "file.txt", 14, ta)
+		require.NoError(t, err)
+
+		copied, err := original.Copy(s, ta)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		db.AssertExists(t, "file_contents", map[string]interface{}{
+			"sha256":          original.Sha256,
+			"reference_count": 2,
+		}, false)
+		assert.NotEqual(t, original.ID, copied.ID)
+		assert.Equal(t, original.Sha256, copied.Sha256)
+		assert.Equal(t, "file.txt", copied.Name)
+		assert.Equal(t, int64(2), copied.CreatedByID)
+	})
+	t.Run("legacy file", func(t *testing.T) {
+		initFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		original := &File{ID: 1}
+		require.NoError(t, original.LoadFileMetaByID())
+
+		copied, err := original.Copy(s, ta)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		assert.Len(t, copied.Sha256, 64)
+
+		require.NoError(t, copied.LoadFileByID())
+		defer copied.File.Close()
+		content, err := io.ReadAll(copied.File)
+		require.NoError(t, err)
+		assert.Equal(t, "testfile1", string(content))
+	})
+}
+
+func TestDeduplicateLegacyFiles(t *testing.T) {
+	initFixtures(t)
+
+	deduplicated, err := DeduplicateLegacyFiles()
+	require.NoError(t, err)
+	assert.Equal(t, 1, deduplicated)
+
+	f := &File{ID: 1}
+	require.NoError(t, f.LoadFileMetaByID())
+	assert.Len(t, f.Sha256, 64)
+
+	require.NoError(t, f.LoadFileByID())
+	defer f.File.Close()
+	content, err := io.ReadAll(f.File)
+	require.NoError(t, err)
+	assert.Equal(t, "testfile1", string(content))
+}
@@ -0,0 +1,519 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package files
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+
+	"github.com/spf13/afero"
+)
+
+// Files are encrypted with envelope encryption: every file gets its own random data key, which is stored next to the
+// content after encrypting it with the key from the config or a key in AWS KMS. The content is split into chunks
+// which are encrypted separately, so that parts of a file can be read without decrypting all of it.
+//
+// An encrypted file looks like this:
+//
+//	magic | length of the encrypted data key (uint16) | encrypted data key | chunk 0 | chunk 1 | ...
+//
+// Every chunk holds up to encryptionChunkSize bytes of content and is encrypted with AES-256-GCM. The nonce is the
+// number of the chunk and the last chunk is marked through the additional data, which makes it impossible to
+// reorder or cut off chunks without noticing.
+
+const (
+	encryptionChunkSize = 64 << 10
+	encryptionMagic     = "VKJENC01"
+	dataKeySize         = 32
+)
+
+var errEncryptedFileReadOnly = errors.New("encrypted files can only be read or written completely")
+
+// keyWrapper encrypts and decrypts the data keys of files
+type keyWrapper interface {
+	wrapKey(key []byte) ([]byte, error)
+	unwrapKey(wrapped []byte) ([]byte, error)
+}
+
+func newKeyWrapper() (keyWrapper, error) {
+	switch config.FilesEncryptionType.GetString() {
+	case "key":
+		key, err := base64.StdEncoding.DecodeString(config.FilesEncryptionKey.GetString())
+		if err != nil {
+			return nil, fmt.Errorf("files.encryption.key is not valid base64: %w", err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("files.encryption.key must be 32 bytes long, it is %d bytes long", len(key))
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		return &localKeyWrapper{aead: aead}, nil
+	case "kms":
+		return newKMSKeyWrapper()
+	default:
+		return nil, fmt.Errorf("unknown file encryption type %s", config.FilesEncryptionType.GetString())
+	}
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// localKeyWrapper encrypts data keys with the key from the config
+type localKeyWrapper struct {
+	aead cipher.AEAD
+}
+
+func (w *localKeyWrapper) wrapKey(key []byte) ([]byte, error) {
+	nonce := make([]byte, w.aead.NonceSize())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return w.aead.Seal(nonce, nonce, key, nil), nil
+}
+
+func (w *localKeyWrapper) unwrapKey(wrapped []byte) ([]byte, error) {
+	if len(wrapped) < w.aead.NonceSize() {
+		return nil, errors.New("encrypted data key is too short")
+	}
+	nonce, ciphertext := wrapped[:w.aead.NonceSize()], wrapped[w.aead.NonceSize():]
+	return w.aead.Open(nil, nonce, ciphertext, nil)
+}
+
+func chunkNonce(aead cipher.AEAD, chunk int64) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], uint64(chunk))
+	return nonce
+}
+
+func chunkAdditionalData(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// encryptedFs encrypts everything written to the underlying storage. Files which were stored before encryption was
+// enabled are still read as they are.
+type encryptedFs struct {
+	afero.Fs
+	keys keyWrapper
+}
+
+func (efs *encryptedFs) Name() string {
+	return "encrypted " + efs.Fs.Name()
+}
+
+func (efs *encryptedFs) Create(name string) (afero.File, error) {
+	key := make([]byte, dataKeySize)
+	_, err := rand.Read(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := efs.keys.wrapKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := efs.Fs.Create(name)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, len(encryptionMagic)+2+len(wrapped))
+	header = append(header, encryptionMagic...)
+	header = binary.BigEndian.AppendUint16(header, uint16(len(wrapped)))
+	header = append(header, wrapped...)
+	_, err = file.Write(header)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return &encryptedWriteFile{File: file, aead: aead}, nil
+}
+
+func (efs *encryptedFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		return efs.Open(name)
+	}
+	if flag&os.O_CREATE == 0 || flag&os.O_TRUNC == 0 {
+		return nil, &os.PathError{Op: "open", Path: name, Err: errEncryptedFileReadOnly}
+	}
+	return efs.Create(name)
+}
+
+func (efs *encryptedFs) Open(name string) (afero.File, error) {
+	file, err := efs.Fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := hasEncryptionHeader(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if !encrypted {
+		return file, nil
+	}
+
+	decrypted, err := efs.openEncrypted(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, &os.PathError{Op: "open", Path: name, Err: err}
+	}
+	return decrypted, nil
+}
+
+func (efs *encryptedFs) openEncrypted(file afero.File) (*encryptedReadFile, error) {
+	length := make([]byte, 2)
+	_, err := file.ReadAt(length, int64(len(encryptionMagic)))
+	if err != nil {
+		return nil, err
+	}
+	wrapped := make([]byte, binary.BigEndian.Uint16(length))
+	_, err = file.ReadAt(wrapped, int64(len(encryptionMagic)+2))
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := efs.keys.unwrapKey(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt the data key: %w", err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	dataOffset := int64(len(encryptionMagic) + 2 + len(wrapped))
+	sealedChunkSize := int64(encryptionChunkSize + aead.Overhead())
+	body := info.Size() - dataOffset
+	chunks := (body + sealedChunkSize - 1) / sealedChunkSize
+	if body < int64(aead.Overhead()) || body-(chunks-1)*sealedChunkSize < int64(aead.Overhead()) {
+		return nil, errors.New("encrypted file is truncated")
+	}
+
+	return &encryptedReadFile{
+		File:       file,
+		aead:       aead,
+		info:       info,
+		dataOffset: dataOffset,
+		chunks:     chunks,
+		size:       body - chunks*int64(aead.Overhead()),
+		loaded:     -1,
+	}, nil
+}
+
+// Stat returns the size of the decrypted content for encrypted files
+func (efs *encryptedFs) Stat(name string) (os.FileInfo, error) {
+	file, err := efs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return file.Stat()
+}
+
+func hasEncryptionHeader(file afero.File) (bool, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+	if info.IsDir() || info.Size() < int64(len(encryptionMagic)) {
+		return false, nil
+	}
+
+	magic := make([]byte, len(encryptionMagic))
+	_, err = file.ReadAt(magic, 0)
+	if err != nil {
+		return false, err
+	}
+	return string(magic) == encryptionMagic, nil
+}
+
+// encryptedWriteFile encrypts everything written to it. The last chunk is only written when the file is closed.
+type encryptedWriteFile struct {
+	afero.File
+	aead   cipher.AEAD
+	chunk  int64
+	buffer []byte
+}
+
+func (f *encryptedWriteFile) writeChunk(content []byte, last bool) error {
+	sealed := f.aead.Seal(nil, chunkNonce(f.aead, f.chunk), content, chunkAdditionalData(last))
+	f.chunk++
+	_, err := f.File.Write(sealed)
+	return err
+}
+
+func (f *encryptedWriteFile) Write(p []byte) (n int, err error) {
+	f.buffer = append(f.buffer, p...)
+	// A full chunk is kept until more content arrives because only then it is clear it is not the last one
+	for len(f.buffer) > encryptionChunkSize {
+		err = f.writeChunk(f.buffer[:encryptionChunkSize], false)
+		if err != nil {
+			return 0, err
+		}
+		f.buffer = append(f.buffer[:0], f.buffer[encryptionChunkSize:]...)
+	}
+	return len(p), nil
+}
+
+func (f *encryptedWriteFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+func (f *encryptedWriteFile) WriteAt([]byte, int64) (int, error) {
+	return 0, errEncryptedFileReadOnly
+}
+
+func (f *encryptedWriteFile) Read([]byte) (int, error) {
+	return 0, errEncryptedFileReadOnly
+}
+
+func (f *encryptedWriteFile) ReadAt([]byte, int64) (int, error) {
+	return 0, errEncryptedFileReadOnly
+}
+
+func (f *encryptedWriteFile) Seek(int64, int) (int64, error) {
+	return 0, errEncryptedFileReadOnly
+}
+
+func (f *encryptedWriteFile) Truncate(int64) error {
+	return errEncryptedFileReadOnly
+}
+
+func (f *encryptedWriteFile) Close() error {
+	err := f.writeChunk(f.buffer, true)
+	if err != nil {
+		_ = f.File.Close()
+		return err
+	}
+	return f.File.Close()
+}
+
+// encryptedReadFile decrypts the chunks of a file when they are read
+type encryptedReadFile struct {
+	afero.File
+	aead       cipher.AEAD
+	info       os.FileInfo
+	dataOffset int64
+	chunks     int64
+	size       int64
+	offset     int64
+
+	loaded  int64
+	content []byte
+}
+
+func (f *encryptedReadFile) loadChunk(chunk int64) error {
+	if f.loaded == chunk {
+		return nil
+	}
+
+	sealedChunkSize := int64(encryptionChunkSize + f.aead.Overhead())
+	sealed := make([]byte, min(sealedChunkSize, f.info.Size()-f.dataOffset-chunk*sealedChunkSize))
+	_, err := io.ReadFull(io.NewSectionReader(f.File, f.dataOffset+chunk*sealedChunkSize, int64(len(sealed))), sealed)
+	if err != nil {
+		return err
+	}
+
+	content, err := f.aead.Open(f.content[:0], chunkNonce(f.aead, chunk), sealed, chunkAdditionalData(chunk == f.chunks-1))
+	if err != nil {
+		f.loaded = -1
+		return fmt.Errorf("could not decrypt chunk %d of %s: %w", chunk, f.File.Name(), err)
+	}
+	f.content = content
+	f.loaded = chunk
+	return nil
+}
+
+func (f *encryptedReadFile) ReadAt(p []byte, off int64) (n int, err error) {
+	for n < len(p) {
+		if off >= f.size {
+			return n, io.EOF
+		}
+		chunk := off / encryptionChunkSize
+		err = f.loadChunk(chunk)
+		if err != nil {
+			return n, err
+		}
+		copied := copy(p[n:], f.content[off-chunk*encryptionChunkSize:])
+		n += copied
+		off += int64(copied)
+	}
+	return n, nil
+}
+
+func (f *encryptedReadFile) Read(p []byte) (n int, err error) {
+	if f.offset >= f.size {
+		return 0, io.EOF
+	}
+	n, err = f.ReadAt(p, f.offset)
+	f.offset += int64(n)
+	if errors.Is(err, io.EOF) && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+func (f *encryptedReadFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *encryptedReadFile) Stat() (os.FileInfo, error) {
+	return &decryptedFileInfo{FileInfo: f.info, size: f.size}, nil
+}
+
+func (f *encryptedReadFile) Write([]byte) (int, error) {
+	return 0, errEncryptedFileReadOnly
+}
+
+func (f *encryptedReadFile) WriteString(string) (int, error) {
+	return 0, errEncryptedFileReadOnly
+}
+
+func (f *encryptedReadFile) WriteAt([]byte, int64) (int, error) {
+	return 0, errEncryptedFileReadOnly
+}
+
+func (f *encryptedReadFile) Truncate(int64) error {
+	return errEncryptedFileReadOnly
+}
+
+type decryptedFileInfo struct {
+	os.FileInfo
+	size int64
+}
+
+func (fi *decryptedFileInfo) Size() int64 {
+	return fi.size
+}
+
+// EncryptExistingFiles encrypts the content of all files which were stored before encryption was enabled.
+// Thumbnails and previews of these files are removed, they are generated again encrypted when they are requested.
+func EncryptExistingFiles() (encrypted int, err error) {
+	efs, is := fs.(*encryptedFs)
+	if !is {
+		return 0, errors.New("files.encryption.type is not set, configure the encryption first")
+	}
+
+	allFiles := []*File{}
+	err = x.OrderBy("id asc").Find(&allFiles)
+	if err != nil {
+		return 0, err
+	}
+
+	// Deduplicated files share their content, it only needs to be encrypted once
+	seen := make(map[string]bool, len(allFiles))
+	for _, file := range allFiles {
+		file.deleteThumbnails()
+		file.deletePreview()
+
+		name := file.getFileName()
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		done, err := encryptStoredFile(efs, name)
+		if errors.Is(err, os.ErrNotExist) {
+			log.Warningf("Skipping file %d because it does not exist at %s", file.ID, name)
+			continue
+		}
+		if err != nil {
+			return encrypted, err
+		}
+		if !done {
+			continue
+		}
+
+		encrypted++
+		log.Infof("Encrypted file %d", file.ID)
+	}
+
+	return encrypted, nil
+}
+
+// encryptStoredFile replaces the content at name with its encrypted version, unless it is already encrypted
+func encryptStoredFile(efs *encryptedFs, name string) (encrypted bool, err error) {
+	src, err := efs.Fs.Open(name)
+	if err != nil {
+		return false, err
+	}
+
+	isEncrypted, err := hasEncryptionHeader(src)
+	if err != nil || isEncrypted {
+		_ = src.Close()
+		return false, err
+	}
+
+	// The content is read completely before it is overwritten at the same place
+	content, _, err := bufferContent(src)
+	_ = src.Close()
+	if err != nil {
+		return false, err
+	}
+	defer removeBufferedContent(content)
+
+	err = writeFile(efs, name, content)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
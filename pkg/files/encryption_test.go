@@ -0,0 +1,211 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package files
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func initEncryptedTestFileHandler(t *testing.T) *encryptedFs {
+	aead, err := newAEAD(bytes.Repeat([]byte{42}, 32))
+	require.NoError(t, err)
+
+	efs := &encryptedFs{Fs: afero.NewMemMapFs(), keys: &localKeyWrapper{aead: aead}}
+	fs = efs
+	afs = &afero.Afero{Fs: fs}
+	t.Cleanup(InitTestFileHandler)
+	return efs
+}
+
+func TestEncryptedFs(t *testing.T) {
+	// Spans multiple chunks, with the last one only partly filled
+	content := bytes.Repeat([]byte("0123456789"), encryptionChunkSize/4)
+
+	t.Run("round trip", func(t *testing.T) {
+		efs := initEncryptedTestFileHandler(t)
+		require.NoError(t, writeFile(fs, "files/test", bytes.NewReader(content)))
+
+		raw, err := afero.ReadFile(efs.Fs, "files/test")
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(raw), encryptionMagic))
+		assert.NotContains(t, string(raw), "0123456789")
+
+		stored, err := afs.ReadFile("files/test")
+		require.NoError(t, err)
+		assert.Equal(t, content, stored)
+
+		info, err := afs.Stat("files/test")
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(content)), info.Size())
+	})
+	t.Run("read parts", func(t *testing.T) {
+		initEncryptedTestFileHandler(t)
+		require.NoError(t, writeFile(fs, "files/test", bytes.NewReader(content)))
+
+		file, err := afs.Open("files/test")
+		require.NoError(t, err)
+		defer file.Close()
+
+		// Across the border of the first two chunks
+		part := make([]byte, 20)
+		_, err = file.ReadAt(part, encryptionChunkSize-10)
+		require.NoError(t, err)
+		assert.Equal(t, content[encryptionChunkSize-10:encryptionChunkSize+10], part)
+
+		_, err = file.Seek(-5, io.SeekEnd)
+		require.NoError(t, err)
+		rest, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Equal(t, "56789", string(rest))
+	})
+	t.Run("empty file", func(t *testing.T) {
+		initEncryptedTestFileHandler(t)
+		require.NoError(t, writeFile(fs, "files/empty", bytes.NewReader(nil)))
+
+		stored, err := afs.ReadFile("files/empty")
+		require.NoError(t, err)
+		assert.Empty(t, stored)
+	})
+	t.Run("unencrypted files", func(t *testing.T) {
+		efs := initEncryptedTestFileHandler(t)
+		require.NoError(t, afero.WriteFile(efs.Fs, "files/plain", []byte("plain content"), 0o644))
+
+		stored, err := afs.ReadFile("files/plain")
+		require.NoError(t, err)
+		assert.Equal(t, "plain content", string(stored))
+	})
+	t.Run("modified content", func(t *testing.T) {
+		efs := initEncryptedTestFileHandler(t)
+		require.NoError(t, writeFile(fs, "files/test", bytes.NewReader(content)))
+
+		raw, err := afero.ReadFile(efs.Fs, "files/test")
+		require.NoError(t, err)
+		raw[len(raw)-100] ^= 1
+		require.NoError(t, afero.WriteFile(efs.Fs, "files/test", raw, 0o644))
+
+		_, err = afs.ReadFile("files/test")
+		require.Error(t, err)
+	})
+	t.Run("cut off content", func(t *testing.T) {
+		efs := initEncryptedTestFileHandler(t)
+		require.NoError(t, writeFile(fs, "files/test", bytes.NewReader(content)))
+
+		// Remove the last chunk, the chunk before is not marked as last one
+		raw, err := afero.ReadFile(efs.Fs, "files/test")
+		require.NoError(t, err)
+		lastChunk := (len(content)%encryptionChunkSize + 16)
+		require.NoError(t, afero.WriteFile(efs.Fs, "files/test", raw[:len(raw)-lastChunk], 0o644))
+
+		_, err = afs.ReadFile("files/test")
+		require.Error(t, err)
+	})
+}
+
+func TestEncryptExistingFiles(t *testing.T) {
+	initFixtures(t)
+	plain := fs
+	efs := initEncryptedTestFileHandler(t)
+	// The fixture file was stored before encryption was enabled
+	efs.Fs = plain
+
+	encrypted, err := EncryptExistingFiles()
+	require.NoError(t, err)
+	assert.Equal(t, 1, encrypted)
+
+	raw, err := afero.ReadFile(plain, config.FilesBasePath.GetString()+"/1")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(raw), encryptionMagic))
+
+	file := &File{ID: 1}
+	require.NoError(t, file.LoadFileByID())
+	defer file.File.Close()
+	stored, err := io.ReadAll(file.File)
+	require.NoError(t, err)
+	assert.Equal(t, "testfile1", string(stored))
+
+	// Running it again does not encrypt the files twice
+	encrypted, err = EncryptExistingFiles()
+	require.NoError(t, err)
+	assert.Equal(t, 0, encrypted)
+}
+
+func TestKMSKeyWrapper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		// "Encrypts" by reversing the key, which is enough to see that the key went through the kms
+		reverse := func(b []byte) []byte {
+			reversed := make([]byte, len(b))
+			for i := range b {
+				reversed[len(b)-1-i] = b[i]
+			}
+			return reversed
+		}
+
+		request := &struct {
+			KeyID          string `json:"KeyId"`
+			Plaintext      []byte `json:"Plaintext"`
+			CiphertextBlob []byte `json:"CiphertextBlob"`
+		}{}
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.Encrypt":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+			assert.Equal(t, "test-key", request.KeyID)
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"CiphertextBlob": reverse(request.Plaintext)})
+		case "TrentService.Decrypt":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"Plaintext": reverse(request.CiphertextBlob)})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"InvalidAction","message":"unknown action"}`))
+		}
+	}))
+	defer server.Close()
+
+	config.FilesEncryptionKMSKeyID.Set("test-key")
+	config.FilesEncryptionKMSEndpoint.Set(server.URL)
+	config.FilesEncryptionKMSAccessKey.Set("access")
+	config.FilesEncryptionKMSSecretKey.Set("secret")
+	defer config.FilesEncryptionKMSKeyID.Set("")
+	defer config.FilesEncryptionKMSEndpoint.Set("")
+
+	keys, err := newKMSKeyWrapper()
+	require.NoError(t, err)
+
+	wrapped, err := keys.wrapKey([]byte("data key"))
+	require.NoError(t, err)
+	assert.Equal(t, "yek atad", string(wrapped))
+
+	unwrapped, err := keys.unwrapKey(wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "data key", string(unwrapped))
+}
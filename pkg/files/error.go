@@ -66,3 +66,68 @@ func IsErrFileIsNotUnsplashFile(err error) bool {
 	_, ok := err.(ErrFileIsNotUnsplashFile)
 	return ok
 }
+
+// ErrFileIsNotAnImage defines an error where a file can't be decoded as image
+type ErrFileIsNotAnImage struct {
+	FileID int64
+}
+
+// Error is the error implementation of ErrFileIsNotAnImage
+func (err ErrFileIsNotAnImage) Error() string {
+	return fmt.Sprintf("file is not an image [FileID: %d]", err.FileID)
+}
+
+// IsErrFileIsNotAnImage checks if an error is ErrFileIsNotAnImage
+func IsErrFileIsNotAnImage(err error) bool {
+	_, ok := err.(ErrFileIsNotAnImage)
+	return ok
+}
+
+// ErrInvalidThumbnailSize defines an error where a thumbnail size does not exist
+type ErrInvalidThumbnailSize struct {
+	Size string
+}
+
+// Error is the error implementation of ErrInvalidThumbnailSize
+func (err ErrInvalidThumbnailSize) Error() string {
+	return fmt.Sprintf("invalid thumbnail size [Size: %s]", err.Size)
+}
+
+// IsErrInvalidThumbnailSize checks if an error is ErrInvalidThumbnailSize
+func IsErrInvalidThumbnailSize(err error) bool {
+	_, ok := err.(ErrInvalidThumbnailSize)
+	return ok
+}
+
+// ErrFileIsInfected defines an error where the virus scanner found something in a file
+type ErrFileIsInfected struct {
+	Name      string
+	Signature string
+}
+
+// Error is the error implementation of ErrFileIsInfected
+func (err ErrFileIsInfected) Error() string {
+	return fmt.Sprintf("file is infected [Name: %s, Signature: %s]", err.Name, err.Signature)
+}
+
+// IsErrFileIsInfected checks if an error is ErrFileIsInfected
+func IsErrFileIsInfected(err error) bool {
+	_, ok := err.(ErrFileIsInfected)
+	return ok
+}
+
+// ErrFileHasNoPreview defines an error where no preview can be generated for a file
+type ErrFileHasNoPreview struct {
+	FileID int64
+}
+
+// Error is the error implementation of ErrFileHasNoPreview
+func (err ErrFileHasNoPreview) Error() string {
+	return fmt.Sprintf("file has no preview [FileID: %d]", err.FileID)
+}
+
+// IsErrFileHasNoPreview checks if an error is ErrFileHasNoPreview
+func IsErrFileHasNoPreview(err error) bool {
+	_, ok := err.(ErrFileHasNoPreview)
+	return ok
+}
@@ -17,7 +17,9 @@
 package files
 
 import (
+	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"code.vikunja.io/api/pkg/config"
@@ -35,10 +37,51 @@ var afs *afero.Afero
 
 // InitFileHandler creates a new file handler for the file backend we want to use
 func InitFileHandler() {
-	fs = afero.NewOsFs()
+	switch config.FilesType.GetString() {
+	case "s3":
+		s3fs, err := newS3Fs()
+		if err != nil {
+			log.Fatalf("Could not initialize s3 file storage: %s", err)
+		}
+		fs = s3fs
+	default:
+		fs = afero.NewOsFs()
+	}
+	if config.FilesEncryptionType.GetString() != "" {
+		keys, err := newKeyWrapper()
+		if err != nil {
+			log.Fatalf("Could not initialize file encryption: %s", err)
+		}
+		fs = &encryptedFs{Fs: fs, keys: keys}
+	}
 	afs = &afero.Afero{Fs: fs}
 }
 
+// writeFile stores the content under the given name. Unlike afero.WriteReader, it does not ignore errors when
+// closing the file, which is when the s3 storage finishes the upload.
+func writeFile(storage afero.Fs, name string, content io.Reader) (err error) {
+	dir, _ := filepath.Split(name)
+	if dir != "" {
+		err = storage.MkdirAll(dir, 0o777)
+		if err != nil {
+			return err
+		}
+	}
+
+	file, err := storage.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, content)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
 // InitTestFileHandler initializes a new memory file system for testing
 func InitTestFileHandler() {
 	fs = afero.NewMemMapFs()
@@ -73,7 +116,7 @@ func InitTests() {
 		log.Fatal(err)
 	}
 
-	err = db.InitTestFixtures("files")
+	err = db.InitTestFixtures("files", "file_contents")
 	if err != nil {
 		log.Fatal(err)
 	}
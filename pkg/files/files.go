@@ -19,6 +19,7 @@ package files
 import (
 	"errors"
 	"io"
+	"mime"
 	"os"
 	"strconv"
 	"time"
@@ -41,6 +42,9 @@ type File struct {
 	Name string `xorm:"text not null" json:"name"`
 	Mime string `xorm:"text null" json:"mime"`
 	Size uint64 `xorm:"bigint not null" json:"size"`
+	// The sha256 hash of the file content. Files with the same content share it in the storage.
+	// Files stored before hashes were introduced don't have one, their content is stored by their id.
+	Sha256 string `xorm:"char(64) null INDEX" json:"-"`
 
 	Created     time.Time `xorm:"created" json:"created"`
 	CreatedByID int64     `xorm:"bigint not null" json:"-"`
@@ -57,15 +61,45 @@ func (File) TableName() string {
 }
 
 func (f *File) getFileName() string {
+	if f.Sha256 != "" {
+		return contentFileName(f.Sha256)
+	}
 	return config.FilesBasePath.GetString() + "/" + strconv.FormatInt(f.ID, 10)
 }
 
 // LoadFileByID returns a file by its ID
 func (f *File) LoadFileByID() (err error) {
+	// Where the content is stored depends on the hash, which is not known if only the id was set
+	if f.Sha256 == "" {
+		_, err = x.Table("files").Where("id = ?", f.ID).Cols("sha256").Get(&f.Sha256)
+		if err != nil {
+			// The column does not exist yet when older migrations load files
+			log.Debugf("Could not get the hash of file %d, assuming it is stored by its id: %s", f.ID, err)
+		}
+	}
+
 	f.File, err = afs.Open(f.getFileName())
 	return
 }
 
+// PresignedURL returns a temporary url the file can be downloaded from directly, without passing it through Vikunja.
+// It returns an empty string if the file storage does not support that or pre-signed downloads are disabled.
+func (f *File) PresignedURL() string {
+	s3fs, is := fs.(*s3Fs)
+	if !is || !config.FilesS3PresignedDownloads.GetBool() {
+		return ""
+	}
+
+	ttl := time.Duration(config.FilesS3PresignedDownloadsTTL.GetInt64()) * time.Second
+	disposition := mime.FormatMediaType("inline", map[string]string{"filename": f.Name})
+	presigned, err := s3fs.client.presignGetObject(s3Key(f.getFileName()), ttl, disposition, f.Mime)
+	if err != nil {
+		log.Errorf("Could not create a pre-signed url for file %d: %s", f.ID, err)
+		return ""
+	}
+	return presigned
+}
+
 // LoadFileMetaByID loads everything about a file without loading the actual file
 func (f *File) LoadFileMetaByID() (err error) {
 	exists, err := x.Where("id = ?", f.ID).Get(f)
@@ -93,21 +127,42 @@ func CreateWithMime(f io.Reader, realname string, realsize uint64, a web.Auth, m
 	return
 }
 
-func CreateWithMimeAndSession(s *xorm.Session, f io.Reader, realname string, realsize uint64, a web.Auth, mime string, checkFileSizeLimit bool) (file *File, err error) {
-	// Get and parse the configured file size
+// CheckFileSize returns ErrFileIsTooLarge if a file of that size can't be stored because of the configured limit
+func CheckFileSize(size uint64) error {
 	var maxSize datasize.ByteSize
-	err = maxSize.UnmarshalText([]byte(config.FilesMaxSize.GetString()))
+	err := maxSize.UnmarshalText([]byte(config.FilesMaxSize.GetString()))
+	if err != nil {
+		return err
+	}
+	if size > maxSize.Bytes() {
+		return ErrFileIsTooLarge{Size: size}
+	}
+	return nil
+}
+
+func CreateWithMimeAndSession(s *xorm.Session, f io.Reader, realname string, realsize uint64, a web.Auth, mime string, checkFileSizeLimit bool) (file *File, err error) {
+	if checkFileSizeLimit {
+		err = CheckFileSize(realsize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	content, hash, err := bufferContent(f)
 	if err != nil {
 		return nil, err
 	}
-	if realsize > maxSize.Bytes() && checkFileSizeLimit {
-		return nil, ErrFileIsTooLarge{Size: realsize}
+	defer removeBufferedContent(content)
+
+	err = scanContent(content, hash, realname, a)
+	if err != nil {
+		return nil, err
 	}
 
-	// We first insert the file into the db to get it's ID
 	file = &File{
 		Name:        realname,
 		Size:        realsize,
+		Sha256:      hash,
 		CreatedByID: a.GetID(),
 		Mime:        mime,
 	}
@@ -116,9 +171,12 @@ func CreateWithMimeAndSession(s *xorm.Session, f io.Reader, realname string, rea
 	if err != nil {
 		return
 	}
+	err = addContentReference(s, hash)
+	if err != nil {
+		return
+	}
 
-	// Save the file to storage with its new ID as path
-	err = file.Save(f)
+	err = file.saveContent(content)
 	return
 }
 
@@ -127,26 +185,58 @@ func (f *File) Delete() (err error) {
 	s := db.NewSession()
 	defer s.Close()
 
-	deleted, err := s.Where("id = ?", f.ID).Delete(f)
+	err = s.Begin()
+	if err != nil {
+		return err
+	}
+
+	stored := &File{}
+	exists, err := s.Where("id = ?", f.ID).Get(stored)
 	if err != nil {
 		_ = s.Rollback()
 		return err
 	}
-	if deleted == 0 {
+	if !exists {
 		_ = s.Rollback()
 		return ErrFileDoesNotExist{FileID: f.ID}
 	}
+	f.Sha256 = stored.Sha256
 
-	err = afs.Remove(f.getFileName())
+	_, err = s.Where("id = ?", f.ID).Delete(&File{})
 	if err != nil {
-		var perr *os.PathError
-		if errors.As(err, &perr) {
+		_ = s.Rollback()
+		return err
+	}
+
+	f.deleteThumbnails()
+	f.deletePreview()
+
+	// Other files could still use the content
+	unused := true
+	if f.Sha256 != "" {
+		unused, err = removeContentReference(s, f.Sha256)
+		if err != nil {
+			_ = s.Rollback()
+			return err
+		}
+	}
+
+	if unused {
+		err = afs.Remove(f.getFileName())
+		if err != nil {
+			var perr *os.PathError
+			if !errors.As(err, &perr) {
+				_ = s.Rollback()
+				return err
+			}
+
 			// Don't fail when removing the file failed
 			log.Errorf("Error deleting file %d: %w", f.ID, err)
-			return s.Commit()
 		}
+	}
 
-		_ = s.Rollback()
+	err = s.Commit()
+	if err != nil {
 		return err
 	}
 
@@ -155,7 +245,7 @@ func (f *File) Delete() (err error) {
 
 // Save saves a file to storage
 func (f *File) Save(fcontent io.Reader) (err error) {
-	err = afs.WriteReader(f.getFileName(), fcontent)
+	err = writeFile(fs, f.getFileName(), fcontent)
 	if err != nil {
 		return
 	}
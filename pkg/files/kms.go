@@ -0,0 +1,79 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package files
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// kmsKeyWrapper encrypts data keys with a key in AWS KMS, or any service implementing its Encrypt and Decrypt
+// actions. The key itself never leaves the KMS.
+type kmsKeyWrapper struct {
+	client *kms.Client
+	keyID  string
+}
+
+func newKMSKeyWrapper() (*kmsKeyWrapper, error) {
+	if config.FilesEncryptionKMSKeyID.GetString() == "" {
+		return nil, errors.New("files.encryption.kms.keyid must be set")
+	}
+
+	options := kms.Options{
+		Region:      config.FilesEncryptionKMSRegion.GetString(),
+		Credentials: staticCredentials(config.FilesEncryptionKMSAccessKey.GetString(), config.FilesEncryptionKMSSecretKey.GetString()),
+		HTTPClient:  &http.Client{Timeout: 30 * time.Second},
+	}
+	// Without an endpoint the sdk uses the one of AWS for the region
+	if endpoint := config.FilesEncryptionKMSEndpoint.GetString(); endpoint != "" {
+		options.BaseEndpoint = aws.String(endpoint)
+	}
+
+	return &kmsKeyWrapper{
+		client: kms.New(options),
+		keyID:  config.FilesEncryptionKMSKeyID.GetString(),
+	}, nil
+}
+
+func (w *kmsKeyWrapper) wrapKey(key []byte) ([]byte, error) {
+	out, err := w.client.Encrypt(context.Background(), &kms.EncryptInput{
+		KeyId:     aws.String(w.keyID),
+		Plaintext: key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.CiphertextBlob, nil
+}
+
+func (w *kmsKeyWrapper) unwrapKey(wrapped []byte) ([]byte, error) {
+	out, err := w.client.Decrypt(context.Background(), &kms.DecryptInput{
+		KeyId:          aws.String(w.keyID),
+		CiphertextBlob: wrapped,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
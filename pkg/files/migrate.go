@@ -0,0 +1,74 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package files
+
+import (
+	"errors"
+	"os"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+
+	"github.com/spf13/afero"
+)
+
+// MigrateLocalFiles copies all files from the basepath on disk into the configured file storage.
+// Files which don't exist on disk are skipped, the local files are left untouched.
+func MigrateLocalFiles() (migrated int, err error) {
+	if config.FilesType.GetString() == "local" {
+		return 0, errors.New("files.type is local, configure the storage to migrate the files to first")
+	}
+
+	// Local files stored while encryption was already enabled need to be decrypted first, they are encrypted again
+	// in the new storage
+	var from afero.Fs = afero.NewOsFs()
+	if efs, is := fs.(*encryptedFs); is {
+		from = &encryptedFs{Fs: from, keys: efs.keys}
+	}
+
+	return migrateFiles(from, fs)
+}
+
+func migrateFiles(from afero.Fs, to afero.Fs) (migrated int, err error) {
+	allFiles := []*File{}
+	err = x.OrderBy("id asc").Find(&allFiles)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, file := range allFiles {
+		src, err := from.Open(file.getFileName())
+		if errors.Is(err, os.ErrNotExist) {
+			log.Warningf("Skipping file %d because it does not exist at %s", file.ID, file.getFileName())
+			continue
+		}
+		if err != nil {
+			return migrated, err
+		}
+
+		err = writeFile(to, file.getFileName(), src)
+		_ = src.Close()
+		if err != nil {
+			return migrated, err
+		}
+
+		migrated++
+		log.Infof("Migrated file %d (%d/%d)", file.ID, migrated, len(allFiles))
+	}
+
+	return migrated, nil
+}
@@ -0,0 +1,80 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package files
+
+import (
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/i18n"
+	"code.vikunja.io/api/pkg/notifications"
+)
+
+// antivirusAdmin is the address configured to receive reports about infected uploads
+type antivirusAdmin struct {
+	email string
+}
+
+// RouteForMail returns the configured email address
+func (a *antivirusAdmin) RouteForMail() (string, error) {
+	return a.email, nil
+}
+
+// RouteForDB returns 0 because the admin is not a user and notifications for them are not saved
+func (a *antivirusAdmin) RouteForDB() int64 {
+	return 0
+}
+
+// ShouldNotify always returns true, the admin asked for these reports by configuring their address
+func (a *antivirusAdmin) ShouldNotify() (bool, error) {
+	return true, nil
+}
+
+// Lang returns the default language of the instance
+func (a *antivirusAdmin) Lang() string {
+	return config.DefaultSettingsLanguage.GetString()
+}
+
+// InfectedFileNotification represents a InfectedFileNotification notification
+type InfectedFileNotification struct {
+	FileName  string
+	Signature string
+	UserID    int64
+	// Where a copy of the file was kept in the file storage, empty if it was discarded.
+	QuarantinedAt string
+}
+
+// ToMail returns the mail notification for InfectedFileNotification
+func (n *InfectedFileNotification) ToMail(lang string) *notifications.Mail {
+	mail := notifications.NewMail().
+		Subject(i18n.T(lang, "notifications.file.infected.subject")).
+		Greeting(i18n.T(lang, "notifications.file.infected.greeting")).
+		Line(i18n.T(lang, "notifications.file.infected.message", n.Signature, n.FileName, n.UserID))
+
+	if n.QuarantinedAt != "" {
+		return mail.Line(i18n.T(lang, "notifications.file.infected.quarantined", n.QuarantinedAt))
+	}
+	return mail.Line(i18n.T(lang, "notifications.file.infected.discarded"))
+}
+
+// ToDB returns the InfectedFileNotification notification in a format which can be saved in the db
+func (n *InfectedFileNotification) ToDB() interface{} {
+	return nil
+}
+
+// Name returns the name of the notification
+func (n *InfectedFileNotification) Name() string {
+	return "file.infected"
+}
@@ -0,0 +1,174 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package files
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+const (
+	// The width and height in pixels the first page of a pdf is scaled to fit into
+	pdfPreviewSize    = 800
+	pdfPreviewTimeout = 30 * time.Second
+)
+
+func previewName(fileID int64) string {
+	return config.FilesBasePath.GetString() + "/previews/" + strconv.FormatInt(fileID, 10) + ".png"
+}
+
+// Preview returns a preview of a document which clients can show instead of downloading the whole file.
+// For pdfs this is the first page rendered as png, which is stored once it was generated. Plain text and markdown
+// files are returned as text, cut off after the configured length.
+func (f *File) Preview() (preview io.ReadSeekCloser, mime string, err error) {
+	src, err := afs.Open(f.getFileName())
+	if err != nil {
+		return nil, "", err
+	}
+	defer src.Close()
+
+	detected, err := mimetype.DetectReader(src)
+	if err != nil {
+		return nil, "", err
+	}
+	_, err = src.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch {
+	case detected.Is("application/pdf"):
+		return f.pdfPreview(src)
+	// Only plain text is returned as it is, html or svg files could contain scripts which would run when a
+	// client displays the preview.
+	case detected.Is("text/plain"):
+		return f.textPreview(src, detected.String())
+	}
+
+	return nil, "", ErrFileHasNoPreview{FileID: f.ID}
+}
+
+func (f *File) textPreview(src io.Reader, mime string) (preview io.ReadSeekCloser, _ string, err error) {
+	maxLength := config.FilesPreviewsMaxTextLength.GetInt()
+	content, err := io.ReadAll(io.LimitReader(src, int64(maxLength)))
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Don't cut off a multibyte character in the middle
+	if len(content) == maxLength {
+		for i := len(content); i > 0 && i > len(content)-utf8.UTFMax; i-- {
+			if utf8.RuneStart(content[i-1]) {
+				if !utf8.FullRune(content[i-1:]) {
+					content = content[:i-1]
+				}
+				break
+			}
+		}
+	}
+
+	extension := strings.ToLower(filepath.Ext(f.Name))
+	if extension == ".md" || extension == ".markdown" {
+		mime = strings.Replace(mime, "text/plain", "text/markdown", 1)
+	}
+
+	return &bytesReadSeekCloser{bytes.NewReader(content)}, mime, nil
+}
+
+func (f *File) pdfPreview(src io.Reader) (preview io.ReadSeekCloser, mime string, err error) {
+	preview, err = afs.Open(previewName(f.ID))
+	if err == nil {
+		return preview, "image/png", nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, "", err
+	}
+
+	command := config.FilesPreviewsPDFCommand.GetString()
+	if command == "" {
+		return nil, "", ErrFileHasNoPreview{FileID: f.ID}
+	}
+
+	log.Debugf("Preview of file %d does not exist yet, generating it", f.ID)
+
+	// The pdf needs to be a local file for the command, even if the files are stored somewhere else
+	dir, err := os.MkdirTemp("", "vikunja-preview-")
+	if err != nil {
+		return nil, "", err
+	}
+	defer os.RemoveAll(dir)
+
+	input, err := os.Create(filepath.Join(dir, "input.pdf"))
+	if err != nil {
+		return nil, "", err
+	}
+	_, err = io.Copy(input, src)
+	_ = input.Close()
+	if err != nil {
+		return nil, "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pdfPreviewTimeout)
+	defer cancel()
+
+	output := filepath.Join(dir, "preview")
+	args := append(strings.Fields(command),
+		"-png", "-f", "1", "-l", "1", "-singlefile",
+		"-scale-to", strconv.Itoa(pdfPreviewSize),
+		input.Name(), output,
+	)
+	out, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput()
+	if err != nil {
+		// Most likely a broken pdf, clients should show the same as for files without a preview then
+		log.Errorf("Could not generate preview of file %d: %s: %s", f.ID, err, strings.TrimSpace(string(out)))
+		return nil, "", ErrFileHasNoPreview{FileID: f.ID}
+	}
+
+	content, err := os.ReadFile(output + ".png")
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = writeFile(fs, previewName(f.ID), bytes.NewReader(content))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &bytesReadSeekCloser{bytes.NewReader(content)}, "image/png", nil
+}
+
+// deletePreview removes the preview which was generated for a file, if there is one
+func (f *File) deletePreview() {
+	err := afs.Remove(previewName(f.ID))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Errorf("Error deleting preview of file %d: %s", f.ID, err)
+	}
+}
@@ -0,0 +1,119 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package files
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_Preview(t *testing.T) {
+	t.Run("markdown", func(t *testing.T) {
+		initFixtures(t)
+
+		f := &File{ID: 2, Name: "readme.md"}
+		require.NoError(t, afero.WriteReader(afs, f.getFileName(), strings.NewReader("# Lorem\n\nIpsum")))
+
+		preview, mime, err := f.Preview()
+		require.NoError(t, err)
+		defer preview.Close()
+		assert.Equal(t, "text/markdown; charset=utf-8", mime)
+
+		content, err := io.ReadAll(preview)
+		require.NoError(t, err)
+		assert.Equal(t, "# Lorem\n\nIpsum", string(content))
+	})
+	t.Run("text is cut off", func(t *testing.T) {
+		initFixtures(t)
+		config.FilesPreviewsMaxTextLength.Set(7)
+		defer config.FilesPreviewsMaxTextLength.Set(65536)
+
+		f := &File{ID: 2, Name: "text.txt"}
+		require.NoError(t, afero.WriteReader(afs, f.getFileName(), strings.NewReader("Lorem äpsum")))
+
+		preview, _, err := f.Preview()
+		require.NoError(t, err)
+		defer preview.Close()
+
+		// The ä is two bytes long and would be cut in half
+		content, err := io.ReadAll(preview)
+		require.NoError(t, err)
+		assert.Equal(t, "Lorem ", string(content))
+	})
+	t.Run("pdf", func(t *testing.T) {
+		initFixtures(t)
+
+		script := filepath.Join(t.TempDir(), "pdftoppm.sh")
+		err := os.WriteFile(script, []byte(`#!/bin/sh
+for last; do :; done
+printf 'rendered page' > "$last.png"
+`), 0o755)
+		require.NoError(t, err)
+		config.FilesPreviewsPDFCommand.Set(script)
+		defer config.FilesPreviewsPDFCommand.Set("pdftoppm")
+
+		f := &File{ID: 2, Name: "document.pdf"}
+		require.NoError(t, afero.WriteReader(afs, f.getFileName(), strings.NewReader("%PDF-1.4\n%%EOF\n")))
+
+		preview, mime, err := f.Preview()
+		require.NoError(t, err)
+		defer preview.Close()
+		assert.Equal(t, "image/png", mime)
+
+		content, err := io.ReadAll(preview)
+		require.NoError(t, err)
+		assert.Equal(t, "rendered page", string(content))
+
+		_, err = FileStat(previewName(2))
+		require.NoError(t, err)
+
+		f.deletePreview()
+		_, err = FileStat(previewName(2))
+		require.Error(t, err)
+	})
+	t.Run("pdf previews disabled", func(t *testing.T) {
+		initFixtures(t)
+		config.FilesPreviewsPDFCommand.Set("")
+		defer config.FilesPreviewsPDFCommand.Set("pdftoppm")
+
+		f := &File{ID: 2, Name: "document.pdf"}
+		require.NoError(t, afero.WriteReader(afs, f.getFileName(), strings.NewReader("%PDF-1.4\n%%EOF\n")))
+
+		_, _, err := f.Preview()
+		require.Error(t, err)
+		assert.True(t, IsErrFileHasNoPreview(err))
+	})
+	t.Run("html", func(t *testing.T) {
+		initFixtures(t)
+
+		f := &File{ID: 2, Name: "page.html"}
+		require.NoError(t, afero.WriteReader(afs, f.getFileName(), strings.NewReader("<html><script>alert(1)</script></html>")))
+
+		_, _, err := f.Preview()
+		require.Error(t, err)
+		assert.True(t, IsErrFileHasNoPreview(err))
+	})
+}
@@ -0,0 +1,352 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package files
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/spf13/afero"
+)
+
+// Parts of a multipart upload must be at least 5MB, except for the last one.
+const s3PartSize = 8 << 20
+
+var errS3Unsupported = errors.New("operation not supported by the s3 file storage")
+
+// s3Fs is a minimal afero.Fs on top of an s3 bucket. It only supports what the file handling needs:
+// reading, writing and deleting whole files.
+type s3Fs struct {
+	client *s3Client
+}
+
+func newS3Fs() (*s3Fs, error) {
+	endpoint, err := url.Parse(config.FilesS3Endpoint.GetString())
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, errors.New("files.s3.endpoint must be a full url like https://s3.amazonaws.com")
+	}
+	if config.FilesS3Bucket.GetString() == "" {
+		return nil, errors.New("files.s3.bucket must be set")
+	}
+
+	return &s3Fs{
+		client: newS3Client(&s3ClientOptions{
+			endpoint:  endpoint.String(),
+			bucket:    config.FilesS3Bucket.GetString(),
+			region:    config.FilesS3Region.GetString(),
+			accessKey: config.FilesS3AccessKey.GetString(),
+			secretKey: config.FilesS3SecretKey.GetString(),
+			pathStyle: config.FilesS3UsePathStyle.GetBool(),
+			http:      &http.Client{},
+		}),
+	}, nil
+}
+
+func s3Key(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+func s3PathError(op, name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &os.PathError{Op: op, Path: name, Err: err}
+}
+
+func (sfs *s3Fs) Name() string {
+	return "s3"
+}
+
+func (sfs *s3Fs) Create(name string) (afero.File, error) {
+	return &s3File{
+		client:   sfs.client,
+		name:     name,
+		key:      s3Key(name),
+		writable: true,
+		modTime:  time.Now(),
+	}, nil
+}
+
+// Mkdir does nothing since there are no directories in a bucket
+func (sfs *s3Fs) Mkdir(string, os.FileMode) error {
+	return nil
+}
+
+// MkdirAll does nothing since there are no directories in a bucket
+func (sfs *s3Fs) MkdirAll(string, os.FileMode) error {
+	return nil
+}
+
+func (sfs *s3Fs) Open(name string) (afero.File, error) {
+	key := s3Key(name)
+	size, modTime, err := sfs.client.headObject(key)
+	if err != nil {
+		return nil, s3PathError("open", name, err)
+	}
+
+	return &s3File{
+		client:  sfs.client,
+		name:    name,
+		key:     key,
+		size:    size,
+		modTime: modTime,
+	}, nil
+}
+
+func (sfs *s3Fs) OpenFile(name string, flag int, _ os.FileMode) (afero.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC) != 0 {
+		return sfs.Create(name)
+	}
+	return sfs.Open(name)
+}
+
+func (sfs *s3Fs) Remove(name string) error {
+	return s3PathError("remove", name, sfs.client.deleteObject(s3Key(name)))
+}
+
+func (sfs *s3Fs) RemoveAll(name string) error {
+	return sfs.Remove(name)
+}
+
+func (sfs *s3Fs) Rename(oldname, newname string) error {
+	return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: errS3Unsupported}
+}
+
+func (sfs *s3Fs) Stat(name string) (os.FileInfo, error) {
+	size, modTime, err := sfs.client.headObject(s3Key(name))
+	if err != nil {
+		return nil, s3PathError("stat", name, err)
+	}
+	return &s3FileInfo{name: path.Base(name), size: size, modTime: modTime}, nil
+}
+
+func (sfs *s3Fs) Chmod(string, os.FileMode) error {
+	return nil
+}
+
+func (sfs *s3Fs) Chown(string, int, int) error {
+	return nil
+}
+
+func (sfs *s3Fs) Chtimes(string, time.Time, time.Time) error {
+	return nil
+}
+
+type s3FileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func (fi *s3FileInfo) Name() string       { return fi.name }
+func (fi *s3FileInfo) Size() int64        { return fi.size }
+func (fi *s3FileInfo) Mode() os.FileMode  { return 0644 }
+func (fi *s3FileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *s3FileInfo) IsDir() bool        { return false }
+func (fi *s3FileInfo) Sys() interface{}   { return nil }
+
+// s3File is either an object opened for reading or a new object being written.
+// Reads are streamed from the bucket, seeking simply starts a new request at the new offset.
+// Writes are buffered and uploaded in parts once the buffer is full, the upload is completed when closing the file.
+type s3File struct {
+	client  *s3Client
+	name    string
+	key     string
+	size    int64
+	modTime time.Time
+
+	offset int64
+	body   io.ReadCloser
+
+	writable bool
+	buf      bytes.Buffer
+	uploadID string
+	parts    []types.CompletedPart
+}
+
+func (f *s3File) Name() string {
+	return f.name
+}
+
+func (f *s3File) Read(p []byte) (n int, err error) {
+	if f.writable {
+		return 0, s3PathError("read", f.name, syscall.EBADF)
+	}
+	if f.offset >= f.size {
+		return 0, io.EOF
+	}
+
+	if f.body == nil {
+		f.body, err = f.client.getObject(f.key, f.offset, -1)
+		if err != nil {
+			return 0, s3PathError("read", f.name, err)
+		}
+	}
+
+	n, err = f.body.Read(p)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *s3File) ReadAt(p []byte, off int64) (n int, err error) {
+	if f.writable {
+		return 0, s3PathError("read", f.name, syscall.EBADF)
+	}
+	if off >= f.size {
+		return 0, io.EOF
+	}
+
+	body, err := f.client.getObject(f.key, off, off+int64(len(p))-1)
+	if err != nil {
+		return 0, s3PathError("read", f.name, err)
+	}
+	defer body.Close()
+
+	n, err = io.ReadFull(body, p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (f *s3File) Seek(offset int64, whence int) (int64, error) {
+	if f.writable {
+		return 0, s3PathError("seek", f.name, errS3Unsupported)
+	}
+
+	var newOffset int64
+	switch whence {
+	case io.SeekStart:
+		newOffset = offset
+	case io.SeekCurrent:
+		newOffset = f.offset + offset
+	case io.SeekEnd:
+		newOffset = f.size + offset
+	}
+	if newOffset < 0 {
+		return 0, s3PathError("seek", f.name, syscall.EINVAL)
+	}
+
+	if newOffset != f.offset && f.body != nil {
+		_ = f.body.Close()
+		f.body = nil
+	}
+	f.offset = newOffset
+	return newOffset, nil
+}
+
+func (f *s3File) Write(p []byte) (n int, err error) {
+	if !f.writable {
+		return 0, s3PathError("write", f.name, syscall.EBADF)
+	}
+
+	n, _ = f.buf.Write(p)
+	f.size += int64(n)
+	if f.buf.Len() >= s3PartSize {
+		err = f.uploadPart()
+	}
+	return n, err
+}
+
+func (f *s3File) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+func (f *s3File) WriteAt([]byte, int64) (int, error) {
+	return 0, s3PathError("write", f.name, errS3Unsupported)
+}
+
+func (f *s3File) uploadPart() (err error) {
+	if f.uploadID == "" {
+		f.uploadID, err = f.client.createMultipartUpload(f.key)
+		if err != nil {
+			return s3PathError("write", f.name, err)
+		}
+	}
+
+	part, err := f.client.uploadPart(f.key, f.uploadID, int32(len(f.parts)+1), f.buf.Bytes())
+	if err != nil {
+		_ = f.client.abortMultipartUpload(f.key, f.uploadID)
+		return s3PathError("write", f.name, err)
+	}
+
+	f.parts = append(f.parts, part)
+	f.buf.Reset()
+	return nil
+}
+
+// Close finishes the upload of a written file. Files smaller than one part are uploaded in a single request.
+func (f *s3File) Close() (err error) {
+	if !f.writable {
+		if f.body != nil {
+			err = f.body.Close()
+			f.body = nil
+		}
+		return err
+	}
+
+	if f.uploadID == "" {
+		return s3PathError("close", f.name, f.client.putObject(f.key, f.buf.Bytes()))
+	}
+
+	if f.buf.Len() > 0 {
+		err = f.uploadPart()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = f.client.completeMultipartUpload(f.key, f.uploadID, f.parts)
+	if err != nil {
+		_ = f.client.abortMultipartUpload(f.key, f.uploadID)
+	}
+	return s3PathError("close", f.name, err)
+}
+
+func (f *s3File) Stat() (os.FileInfo, error) {
+	return &s3FileInfo{name: path.Base(f.name), size: f.size, modTime: f.modTime}, nil
+}
+
+func (f *s3File) Sync() error {
+	return nil
+}
+
+func (f *s3File) Truncate(int64) error {
+	return s3PathError("truncate", f.name, errS3Unsupported)
+}
+
+func (f *s3File) Readdir(int) ([]os.FileInfo, error) {
+	return nil, s3PathError("readdir", f.name, syscall.ENOTDIR)
+}
+
+func (f *s3File) Readdirnames(int) ([]string, error) {
+	return nil, s3PathError("readdir", f.name, syscall.ENOTDIR)
+}
@@ -0,0 +1,189 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package files
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// s3Client wraps the few object operations of the aws sdk the file storage needs.
+// This works with AWS as well as all the s3-compatible services like MinIO, Garage or Ceph.
+type s3Client struct {
+	client *s3.Client
+	bucket string
+}
+
+type s3ClientOptions struct {
+	endpoint  string
+	bucket    string
+	region    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	http      *http.Client
+}
+
+func newS3Client(opts *s3ClientOptions) *s3Client {
+	client := s3.New(s3.Options{
+		BaseEndpoint: aws.String(opts.endpoint),
+		Region:       opts.region,
+		Credentials:  staticCredentials(opts.accessKey, opts.secretKey),
+		UsePathStyle: opts.pathStyle,
+		HTTPClient:   opts.http,
+	})
+	return &s3Client{client: client, bucket: opts.bucket}
+}
+
+func staticCredentials(accessKey, secretKey string) aws.CredentialsProvider {
+	return aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: accessKey, SecretAccessKey: secretKey}, nil
+	})
+}
+
+// s3Error turns a missing object into os.ErrNotExist, like every other afero.Fs does.
+func s3Error(err error) error {
+	var responseErr *awshttp.ResponseError
+	if errors.As(err, &responseErr) && responseErr.HTTPStatusCode() == http.StatusNotFound {
+		return os.ErrNotExist
+	}
+	return err
+}
+
+func (c *s3Client) headObject(key string) (size int64, modTime time.Time, err error) {
+	out, err := c.client.HeadObject(context.Background(), &s3.HeadObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return 0, time.Time{}, s3Error(err)
+	}
+
+	return aws.ToInt64(out.ContentLength), aws.ToTime(out.LastModified), nil
+}
+
+// getObject returns the content of an object starting at offset. If end is not negative, only the content up to and
+// including end is returned.
+func (c *s3Client) getObject(key string, offset, end int64) (io.ReadCloser, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	}
+	switch {
+	case end >= 0:
+		input.Range = aws.String("bytes=" + strconv.FormatInt(offset, 10) + "-" + strconv.FormatInt(end, 10))
+	case offset > 0:
+		input.Range = aws.String("bytes=" + strconv.FormatInt(offset, 10) + "-")
+	}
+
+	out, err := c.client.GetObject(context.Background(), input)
+	if err != nil {
+		return nil, s3Error(err)
+	}
+	return out.Body, nil
+}
+
+func (c *s3Client) putObject(key string, content []byte) error {
+	_, err := c.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(content),
+	})
+	return s3Error(err)
+}
+
+func (c *s3Client) deleteObject(key string) error {
+	_, err := c.client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	})
+	return s3Error(err)
+}
+
+func (c *s3Client) createMultipartUpload(key string) (uploadID string, err error) {
+	out, err := c.client.CreateMultipartUpload(context.Background(), &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return "", s3Error(err)
+	}
+	return aws.ToString(out.UploadId), nil
+}
+
+func (c *s3Client) uploadPart(key, uploadID string, partNumber int32, content []byte) (part types.CompletedPart, err error) {
+	out, err := c.client.UploadPart(context.Background(), &s3.UploadPartInput{
+		Bucket:     aws.String(c.bucket),
+		Key:        aws.String(key),
+		UploadId:   aws.String(uploadID),
+		PartNumber: aws.Int32(partNumber),
+		Body:       bytes.NewReader(content),
+	})
+	if err != nil {
+		return part, s3Error(err)
+	}
+	return types.CompletedPart{PartNumber: aws.Int32(partNumber), ETag: out.ETag}, nil
+}
+
+func (c *s3Client) completeMultipartUpload(key, uploadID string, parts []types.CompletedPart) error {
+	_, err := c.client.CompleteMultipartUpload(context.Background(), &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(c.bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	return s3Error(err)
+}
+
+func (c *s3Client) abortMultipartUpload(key, uploadID string) error {
+	_, err := c.client.AbortMultipartUpload(context.Background(), &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(c.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	return s3Error(err)
+}
+
+// presignGetObject returns a url which allows downloading an object without credentials until it expires.
+// The content disposition and type are what s3 will send with the object when downloading it through that url.
+func (c *s3Client) presignGetObject(key string, expiry time.Duration, contentDisposition, contentType string) (string, error) {
+	input := &s3.GetObjectInput{
+		Bucket:                     aws.String(c.bucket),
+		Key:                        aws.String(key),
+		ResponseContentDisposition: aws.String(contentDisposition),
+	}
+	if contentType != "" {
+		input.ResponseContentType = aws.String(contentType)
+	}
+
+	presigned, err := s3.NewPresignClient(c.client).PresignGetObject(context.Background(), input, s3.WithPresignExpires(expiry))
+	if err != nil {
+		return "", err
+	}
+	return presigned.URL, nil
+}
@@ -0,0 +1,123 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package files
+
+import (
+	"bytes"
+	"errors"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+
+	"github.com/disintegration/imaging"
+)
+
+// ThumbnailSizes are the sizes thumbnails of images can be requested in. Thumbnails fit into a square with the
+// size as width and height in pixels, images which are already smaller are not scaled up.
+var ThumbnailSizes = map[string]int{
+	"small":  150,
+	"medium": 400,
+	"large":  800,
+}
+
+// Opaque thumbnails are stored as jpeg because they are a lot smaller, png is only used to keep transparency.
+var thumbnailFormats = map[string]string{
+	"jpg": "image/jpeg",
+	"png": "image/png",
+}
+
+func thumbnailName(fileID int64, size, extension string) string {
+	return config.FilesBasePath.GetString() + "/thumbnails/" + strconv.FormatInt(fileID, 10) + "_" + size + "." + extension
+}
+
+// Thumbnail returns a scaled down version of an image file. Thumbnails are generated the first time they are
+// requested and stored in the file storage, so that they only need to be generated once.
+func (f *File) Thumbnail(size string) (thumbnail io.ReadSeekCloser, mime string, err error) {
+	dimension, exists := ThumbnailSizes[size]
+	if !exists {
+		return nil, "", ErrInvalidThumbnailSize{Size: size}
+	}
+
+	for extension, mime := range thumbnailFormats {
+		thumbnail, err = afs.Open(thumbnailName(f.ID, size, extension))
+		if err == nil {
+			return thumbnail, mime, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, "", err
+		}
+	}
+
+	log.Debugf("Thumbnail of file %d in size %s does not exist yet, generating it", f.ID, size)
+
+	src, err := afs.Open(f.getFileName())
+	if err != nil {
+		return nil, "", err
+	}
+	defer src.Close()
+
+	img, err := imaging.Decode(src, imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, "", ErrFileIsNotAnImage{FileID: f.ID}
+	}
+
+	resized := imaging.Fit(img, dimension, dimension, imaging.Lanczos)
+	buf := &bytes.Buffer{}
+	extension := "jpg"
+	if resized.Opaque() {
+		err = jpeg.Encode(buf, resized, &jpeg.Options{Quality: 85})
+	} else {
+		extension = "png"
+		err = png.Encode(buf, resized)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	content := buf.Bytes()
+	err = writeFile(fs, thumbnailName(f.ID, size, extension), bytes.NewReader(content))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &bytesReadSeekCloser{bytes.NewReader(content)}, thumbnailFormats[extension], nil
+}
+
+// deleteThumbnails removes all thumbnails which were generated for a file
+func (f *File) deleteThumbnails() {
+	for size := range ThumbnailSizes {
+		for extension := range thumbnailFormats {
+			err := afs.Remove(thumbnailName(f.ID, size, extension))
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Errorf("Error deleting thumbnail %s of file %d: %s", size, f.ID, err)
+			}
+		}
+	}
+}
+
+type bytesReadSeekCloser struct {
+	*bytes.Reader
+}
+
+func (bytesReadSeekCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,78 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package files
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_Thumbnail(t *testing.T) {
+	t.Run("generates and stores the thumbnail", func(t *testing.T) {
+		initFixtures(t)
+
+		img := image.NewRGBA(image.Rect(0, 0, 1000, 500))
+		for x := 0; x < 1000; x++ {
+			for y := 0; y < 500; y++ {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			}
+		}
+		buf := &bytes.Buffer{}
+		require.NoError(t, png.Encode(buf, img))
+
+		f := &File{ID: 2}
+		require.NoError(t, afero.WriteReader(afs, f.getFileName(), buf))
+
+		thumbnail, mime, err := f.Thumbnail("small")
+		require.NoError(t, err)
+		defer thumbnail.Close()
+		assert.Equal(t, "image/jpeg", mime)
+
+		decoded, _, err := image.Decode(thumbnail)
+		require.NoError(t, err)
+		assert.Equal(t, 150, decoded.Bounds().Dx())
+		assert.Equal(t, 75, decoded.Bounds().Dy())
+
+		_, err = FileStat(thumbnailName(2, "small", "jpg"))
+		require.NoError(t, err)
+
+		f.deleteThumbnails()
+		_, err = FileStat(thumbnailName(2, "small", "jpg"))
+		require.Error(t, err)
+	})
+	t.Run("not an image", func(t *testing.T) {
+		initFixtures(t)
+
+		_, _, err := (&File{ID: 1}).Thumbnail("small")
+		require.Error(t, err)
+		assert.True(t, IsErrFileIsNotAnImage(err))
+	})
+	t.Run("invalid size", func(t *testing.T) {
+		initFixtures(t)
+
+		_, _, err := (&File{ID: 1}).Thumbnail("huge")
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidThumbnailSize(err))
+	})
+}
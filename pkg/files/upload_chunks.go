@@ -0,0 +1,116 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package files
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/config"
+
+	"github.com/spf13/afero"
+)
+
+// Chunks of resumable uploads are kept in the file storage until all of them arrived and they are assembled into
+// a regular file.
+
+func uploadChunksDir(uploadID int64) string {
+	return config.FilesBasePath.GetString() + "/uploads/" + strconv.FormatInt(uploadID, 10)
+}
+
+func uploadChunkName(uploadID int64, chunk int) string {
+	return uploadChunksDir(uploadID) + "/" + strconv.Itoa(chunk)
+}
+
+// SaveUploadChunk stores a chunk of a resumable upload
+func SaveUploadChunk(uploadID int64, chunk int, content io.Reader) error {
+	return writeFile(fs, uploadChunkName(uploadID, chunk), content)
+}
+
+// DeleteUploadChunk removes a single chunk of a resumable upload
+func DeleteUploadChunk(uploadID int64, chunk int) error {
+	err := afs.Remove(uploadChunkName(uploadID, chunk))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// DeleteUploadChunks removes all chunks of a resumable upload
+func DeleteUploadChunks(uploadID int64, chunks int) error {
+	for i := 0; i < chunks; i++ {
+		err := DeleteUploadChunk(uploadID, i)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Only the local storage has directories, it does not matter if that fails
+	_ = afs.Remove(uploadChunksDir(uploadID))
+	return nil
+}
+
+type uploadChunksReader struct {
+	uploadID int64
+	chunks   int
+	current  int
+	file     afero.File
+}
+
+// OpenUploadChunks returns a reader over the content of all chunks of an upload, in order.
+// Only one chunk is opened at a time.
+func OpenUploadChunks(uploadID int64, chunks int) io.ReadCloser {
+	return &uploadChunksReader{
+		uploadID: uploadID,
+		chunks:   chunks,
+	}
+}
+
+func (r *uploadChunksReader) Read(p []byte) (n int, err error) {
+	for r.current < r.chunks {
+		if r.file == nil {
+			r.file, err = afs.Open(uploadChunkName(r.uploadID, r.current))
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		n, err = r.file.Read(p)
+		if err == io.EOF {
+			_ = r.file.Close()
+			r.file = nil
+			r.current++
+			err = nil
+		}
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+
+	return 0, io.EOF
+}
+
+func (r *uploadChunksReader) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
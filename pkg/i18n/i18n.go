@@ -0,0 +1,151 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"code.vikunja.io/api/pkg/log"
+)
+
+// DefaultLanguage is used for every text which is not translated into the language of a user
+const DefaultLanguage = "en"
+
+//go:embed lang/*.json
+var files embed.FS
+
+var (
+	// All translations, by language and then by key
+	translations map[string]map[string]string
+	loadOnce     sync.Once
+)
+
+// Init loads all translation files. Texts are only translated after this was called, translating before loads them
+// on demand.
+func Init() {
+	loadOnce.Do(func() {
+		var err error
+		translations, err = load()
+		if err != nil {
+			log.Fatalf("Could not load translations: %s", err)
+		}
+		log.Debugf("Loaded translations for %d languages", len(translations))
+	})
+}
+
+func load() (map[string]map[string]string, error) {
+	entries, err := files.ReadDir("lang")
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := make(map[string]map[string]string, len(entries))
+	for _, entry := range entries {
+		content, err := files.ReadFile(path.Join("lang", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var raw map[string]interface{}
+		err = json.Unmarshal(content, &raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid translation file %s: %w", entry.Name(), err)
+		}
+
+		texts := make(map[string]string)
+		flatten("", raw, texts)
+		loaded[strings.TrimSuffix(entry.Name(), ".json")] = texts
+	}
+
+	return loaded, nil
+}
+
+// flatten turns nested translation objects into dotted keys like notifications.task.reminder.subject
+func flatten(prefix string, raw map[string]interface{}, texts map[string]string) {
+	for key, value := range raw {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case string:
+			texts[key] = v
+		case map[string]interface{}:
+			flatten(key, v, texts)
+		}
+	}
+}
+
+// fallbackChain returns the languages a text is looked up in, in order. For de-CH that is de-CH, de, any other
+// German variant and finally the default language.
+func fallbackChain(lang string) []string {
+	chain := []string{}
+	if lang != "" {
+		chain = append(chain, lang)
+	}
+
+	base, _, hasRegion := strings.Cut(lang, "-")
+	if hasRegion {
+		chain = append(chain, base)
+	}
+	if base != "" {
+		variants := []string{}
+		for available := range translations {
+			if strings.HasPrefix(available, base+"-") && available != lang {
+				variants = append(variants, available)
+			}
+		}
+		sort.Strings(variants)
+		chain = append(chain, variants...)
+	}
+
+	return append(chain, DefaultLanguage)
+}
+
+// T returns the text for key in the language lang, formatted with params like fmt.Sprintf. Translations can
+// reorder the params with explicit argument indexes like %[2]s. If the key is not translated into lang, it falls
+// back to related languages and the default language. If no translation has the key, the key itself is returned.
+func T(lang, key string, params ...interface{}) string {
+	Init()
+
+	for _, l := range fallbackChain(lang) {
+		text, has := translations[l][key]
+		if !has {
+			continue
+		}
+		if len(params) == 0 {
+			return text
+		}
+		return fmt.Sprintf(text, params...)
+	}
+
+	log.Warningf("Missing translation for %s", key)
+	return key
+}
+
+// Has returns whether the key is translated into the default language, which every key should be.
+func Has(key string) bool {
+	Init()
+
+	_, has := translations[DefaultLanguage][key]
+	return has
+}
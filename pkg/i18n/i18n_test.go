@@ -0,0 +1,57 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package i18n
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestT(t *testing.T) {
+	t.Run("default language", func(t *testing.T) {
+		assert.Equal(t, "Hi user1,", T("en", "notifications.common.greeting", "user1"))
+	})
+	t.Run("translated", func(t *testing.T) {
+		assert.Equal(t, "Hallo user1,", T("de-DE", "notifications.common.greeting", "user1"))
+	})
+	t.Run("falls back to another variant of the language", func(t *testing.T) {
+		assert.Equal(t, "Hallo user1,", T("de-CH", "notifications.common.greeting", "user1"))
+	})
+	t.Run("falls back to the default language", func(t *testing.T) {
+		assert.Equal(t, "Hi user1,", T("xx-XX", "notifications.common.greeting", "user1"))
+		assert.Equal(t, "Hi user1,", T("", "notifications.common.greeting", "user1"))
+	})
+	t.Run("reordered params", func(t *testing.T) {
+		assert.Equal(t, "Reminder for \"Task\" (Project)", T("en", "notifications.task.reminder.subject", "Task", "Project"))
+	})
+	t.Run("missing key", func(t *testing.T) {
+		assert.Equal(t, "does.not.exist", T("en", "does.not.exist"))
+	})
+}
+
+func TestFallbackChain(t *testing.T) {
+	Init()
+	assert.Equal(t, []string{"de-CH", "de", "de-DE", "en"}, fallbackChain("de-CH"))
+	assert.Equal(t, []string{"en"}, fallbackChain(""))
+}
+
+func TestHumanizeDuration(t *testing.T) {
+	assert.Equal(t, "one day and 3 hours", HumanizeDuration("en", 27*time.Hour))
+	assert.Equal(t, "2 Wochen", HumanizeDuration("de-DE", 14*24*time.Hour))
+}
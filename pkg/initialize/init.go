@@ -23,6 +23,7 @@ import (
 	"code.vikunja.io/api/pkg/cron"
 	"code.vikunja.io/api/pkg/events"
 	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/i18n"
 	"code.vikunja.io/api/pkg/log"
 	"code.vikunja.io/api/pkg/mail"
 	"code.vikunja.io/api/pkg/migration"
@@ -30,6 +31,7 @@ import (
 	"code.vikunja.io/api/pkg/modules/auth/openid"
 	"code.vikunja.io/api/pkg/modules/keyvalue"
 	migrationHandler "code.vikunja.io/api/pkg/modules/migration/handler"
+	"code.vikunja.io/api/pkg/notifications"
 	"code.vikunja.io/api/pkg/red"
 	"code.vikunja.io/api/pkg/user"
 )
@@ -65,6 +67,9 @@ func InitEngines() {
 func FullInitWithoutAsync() {
 	LightInit()
 
+	// Load the translations for notifications
+	i18n.Init()
+
 	// Initialize the files handler
 	files.InitFileHandler()
 
@@ -77,6 +82,9 @@ func FullInitWithoutAsync() {
 	// Init Typesense
 	models.InitTypesense()
 
+	// Init Meilisearch
+	models.InitMeilisearch()
+
 	// Start the mail daemon
 	mail.StartMailDaemon()
 }
@@ -90,10 +98,21 @@ func FullInit() {
 	cron.Init()
 	models.RegisterReminderCron()
 	models.RegisterOverdueReminderCron()
+	models.RegisterUsedTaskActionTokenCleanupCron()
+	models.RegisterDigestCron()
+	notifications.RegisterDeferredNotificationsCron(user.GetNotifiableByID)
+	notifications.RegisterNotificationRetentionCron()
+	models.RegisterPriorityAgingCron()
+	models.RegisterSLAEscalationCron()
+	models.RegisterTaskPositionRebalancingCron()
 	user.RegisterTokenCleanupCron()
+	user.RegisterSessionCleanupCron()
 	user.RegisterDeletionNotificationCron()
 	models.RegisterUserDeletionCron()
 	models.RegisterOldExportCleanupCron()
+	models.RegisterTaskAttachmentUploadCleanupCron()
+	models.RegisterOrphanedFilesCleanupCron()
+	models.RegisterWebhookDeliveryCron()
 	openid.CleanupSavedOpenIDProviders()
 	openid.RegisterEmptyOpenIDTeamCleanupCron()
 
@@ -0,0 +1,197 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package integrations
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/api/pkg/notifications"
+	apiv1 "code.vikunja.io/api/pkg/routes/api/v1"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// adminRequestWithSession runs an admin request about a user who is logged in. Every test request loads the
+// fixtures again, so the session of the user has to be created after that.
+func adminRequestWithSession(t *testing.T, handler echo.HandlerFunc, payload string, userID int64) (*user.Session, error) {
+	e, err := setupTestEnv()
+	require.NoError(t, err)
+
+	s := db.NewSession()
+	defer s.Close()
+	session, err := user.CreateSession(s, &user.User{ID: userID}, "Firefox", "127.0.0.1", false, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	c, _ := createRequest(e, http.MethodPost, payload, nil, map[string]string{"user": strconv.FormatInt(userID, 10)})
+	addUserTokenToContext(t, &testuser17, c)
+	return session, handler(c)
+}
+
+func TestAdminUsers(t *testing.T) {
+	t.Run("non admin", func(t *testing.T) {
+		_, err := newTestRequestWithUser(t, http.MethodGet, apiv1.RequireInstanceAdmin(apiv1.AdminListUsers), &testuser15, "", nil, nil)
+		require.Error(t, err)
+		assertHandlerErrorCode(t, err, user.ErrCodeUserIsNotAdmin)
+	})
+	t.Run("list", func(t *testing.T) {
+		rec, err := newTestRequestWithUser(t, http.MethodGet, apiv1.RequireInstanceAdmin(apiv1.AdminListUsers), &testuser17, "", nil, nil)
+		require.NoError(t, err)
+		assert.Contains(t, rec.Body.String(), `"username":"user2"`)
+		assert.Contains(t, rec.Body.String(), `"email":"user2@example.com"`)
+	})
+	t.Run("search", func(t *testing.T) {
+		rec, err := newTestRequestWithUser(t, http.MethodGet, apiv1.RequireInstanceAdmin(apiv1.AdminListUsers), &testuser17, "", url.Values{"s": []string{"user15"}}, nil)
+		require.NoError(t, err)
+		assert.Contains(t, rec.Body.String(), `"username":"user15"`)
+		assert.NotContains(t, rec.Body.String(), `"username":"user2"`)
+	})
+	t.Run("disable and enable", func(t *testing.T) {
+		session, err := adminRequestWithSession(t, apiv1.AdminSetUserStatus, `{"disabled":true}`, 2)
+		require.NoError(t, err)
+		db.AssertExists(t, "users", map[string]interface{}{
+			"id":     2,
+			"status": user.StatusDisabled,
+		}, false)
+		db.AssertExists(t, "revoked_sessions", map[string]interface{}{
+			"session_id": session.ID,
+		}, false)
+		db.AssertMissing(t, "api_tokens", map[string]interface{}{
+			"owner_id": 2,
+		})
+
+		_, err = newTestRequestWithUser(t, http.MethodPost, apiv1.AdminSetUserStatus, &testuser17, `{"disabled":false}`, nil, map[string]string{"user": "2"})
+		require.NoError(t, err)
+		db.AssertExists(t, "users", map[string]interface{}{
+			"id":     2,
+			"status": user.StatusActive,
+		}, false)
+	})
+	t.Run("disable self", func(t *testing.T) {
+		_, err := newTestRequestWithUser(t, http.MethodPost, apiv1.AdminSetUserStatus, &testuser17, `{"disabled":true}`, nil, map[string]string{"user": "17"})
+		require.Error(t, err)
+		assertHandlerErrorCode(t, err, user.ErrCodeAdminCannotManageSelf)
+	})
+	t.Run("reset password", func(t *testing.T) {
+		session, err := adminRequestWithSession(t, apiv1.AdminResetUserPassword, `{"password":"12345678"}`, 2)
+		require.NoError(t, err)
+		db.AssertExists(t, "revoked_sessions", map[string]interface{}{
+			"session_id": session.ID,
+		}, false)
+
+		s := db.NewSession()
+		defer s.Close()
+		_, err = user.CheckUserCredentials(s, &user.Login{Username: "user2", Password: "12345678"})
+		require.NoError(t, err)
+	})
+	t.Run("change email", func(t *testing.T) {
+		rec, err := newTestRequestWithUser(t, http.MethodPost, apiv1.AdminUpdateUserEmail, &testuser17, `{"email":"new@example.com"}`, nil, map[string]string{"user": "2"})
+		require.NoError(t, err)
+		assert.Contains(t, rec.Body.String(), `"email":"new@example.com"`)
+		db.AssertExists(t, "users", map[string]interface{}{
+			"id":    2,
+			"email": "new@example.com",
+		}, false)
+	})
+	t.Run("storage", func(t *testing.T) {
+		rec, err := newTestRequestWithUser(t, http.MethodGet, apiv1.AdminGetUserStorage, &testuser17, "", nil, map[string]string{"user": "1"})
+		require.NoError(t, err)
+		assert.Contains(t, rec.Body.String(), `"used":`)
+	})
+	t.Run("delete with transfer", func(t *testing.T) {
+		notifications.Fake()
+		_, err := newTestRequestWithUser(t, http.MethodDelete, apiv1.AdminDeleteUser, &testuser17, "", url.Values{"transfer_to": []string{"2"}}, map[string]string{"user": "6"})
+		require.NoError(t, err)
+		db.AssertMissing(t, "users", map[string]interface{}{"id": 6})
+		// Project 24 would be deleted without the transfer because only user 6 had access to it
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":       24,
+			"owner_id": 2,
+		}, false)
+	})
+}
+
+func TestAdminImpersonateUser(t *testing.T) {
+	t.Run("admin", func(t *testing.T) {
+		_, err := newTestRequestWithUser(t, http.MethodPost, apiv1.AdminImpersonateUser, &testuser17, "", nil, map[string]string{"user": "17"})
+		require.Error(t, err)
+		assertHandlerErrorCode(t, err, user.ErrCodeCannotImpersonateUser)
+	})
+	t.Run("impersonate", func(t *testing.T) {
+		notifications.Fake()
+		rec, err := newTestRequestWithUser(t, http.MethodPost, apiv1.AdminImpersonateUser, &testuser17, "", nil, map[string]string{"user": "2"})
+		require.NoError(t, err)
+		notifications.AssertSent(t, &user.ImpersonationStartedNotification{})
+		db.AssertExists(t, "audit_log", map[string]interface{}{
+			"user_id":         2,
+			"impersonator_id": 17,
+			"action":          models.AuditActionImpersonationStarted,
+		}, false)
+
+		token := &auth.Token{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), token))
+
+		// Send requests with the token through all routes and middlewares
+		e, err := setupTestEnv()
+		require.NoError(t, err)
+		request := func(method, path string) int {
+			req := httptest.NewRequest(method, path, nil)
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+token.Token)
+			res := httptest.NewRecorder()
+			e.ServeHTTP(res, req)
+			return res.Code
+		}
+
+		assert.Equal(t, http.StatusOK, request(http.MethodGet, "/api/v1/projects"))
+		db.AssertExists(t, "audit_log", map[string]interface{}{
+			"user_id":         2,
+			"impersonator_id": 17,
+			"action":          models.AuditActionImpersonatedRequest,
+			"method":          http.MethodGet,
+			"path":            "/api/v1/projects",
+			"status_code":     http.StatusOK,
+		}, false)
+
+		assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "/api/v1/projects/9999999"))
+		db.AssertExists(t, "audit_log", map[string]interface{}{
+			"user_id":         2,
+			"impersonator_id": 17,
+			"action":          models.AuditActionImpersonatedRequest,
+			"path":            "/api/v1/projects/9999999",
+			"status_code":     http.StatusNotFound,
+		}, false)
+
+		assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "/api/v1/user/password"))
+		assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "/api/v1/user/token"))
+		assert.Equal(t, http.StatusForbidden, request(http.MethodPut, "/api/v1/user/settings/push-subscriptions"))
+		assert.Equal(t, http.StatusForbidden, request(http.MethodPut, "/api/v1/user/settings/telegram"))
+		assert.Equal(t, http.StatusForbidden, request(http.MethodPut, "/api/v1/projects/3/shares"))
+		assert.Equal(t, http.StatusForbidden, request(http.MethodPut, "/api/v1/projects/3/webhooks"))
+		assert.Equal(t, http.StatusForbidden, request(http.MethodPut, "/api/v1/projects/3/forms"))
+	})
+}
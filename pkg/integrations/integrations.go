@@ -58,6 +58,13 @@ var (
 		Password: "$2a$14$dcadBoMBL9jQoOcZK8Fju.cy0Ptx2oZECkKLnaa8ekRoTFe1w7To.",
 		Email:    "user15@example.com",
 	}
+	// testuser17 is an instance admin
+	testuser17 = user.User{
+		ID:       17,
+		Username: "user17",
+		Password: "$2a$14$dcadBoMBL9jQoOcZK8Fju.cy0Ptx2oZECkKLnaa8ekRoTFe1w7To.",
+		Email:    "user17@example.com",
+	}
 )
 
 func setupTestEnv() (e *echo.Echo, err error) {
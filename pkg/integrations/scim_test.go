@@ -0,0 +1,229 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package integrations
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/routes/scim"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scimRequest(t *testing.T, e *echo.Echo, method string, handler echo.HandlerFunc, payload string, queryParams url.Values, urlParams map[string]string) *httptest.ResponseRecorder {
+	c, rec := createRequest(e, method, payload, queryParams, urlParams)
+	require.NoError(t, handler(c))
+	return rec
+}
+
+func TestSCIMUsers(t *testing.T) {
+	t.Run("create", func(t *testing.T) {
+		e, err := setupTestEnv()
+		require.NoError(t, err)
+		rec := scimRequest(t, e, http.MethodPost, scim.CreateUser, `{"schemas":["urn:ietf:params:scim:schemas:core:2.0:User"],"userName":"provisioned","name":{"givenName":"Pro","familyName":"Visioned"},"emails":[{"value":"provisioned@example.com","primary":true}],"active":true}`, nil, nil)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		created := &scim.User{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), created))
+		assert.Equal(t, "provisioned", created.UserName)
+		assert.Equal(t, "Pro Visioned", created.DisplayName)
+		require.NotNil(t, created.Active)
+		assert.True(t, *created.Active)
+		db.AssertExists(t, "users", map[string]interface{}{
+			"username": "provisioned",
+			"email":    "provisioned@example.com",
+			"name":     "Pro Visioned",
+			"issuer":   user.IssuerLocal,
+		}, false)
+	})
+	t.Run("create with existing username", func(t *testing.T) {
+		e, err := setupTestEnv()
+		require.NoError(t, err)
+		rec := scimRequest(t, e, http.MethodPost, scim.CreateUser, `{"userName":"user1","emails":[{"value":"someone@example.com"}]}`, nil, nil)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"scimType":"uniqueness"`)
+	})
+	t.Run("filter by username", func(t *testing.T) {
+		e, err := setupTestEnv()
+		require.NoError(t, err)
+		rec := scimRequest(t, e, http.MethodGet, scim.GetUsers, ``, url.Values{"filter": []string{`userName eq "user1"`}}, nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"totalResults":1`)
+		assert.Contains(t, rec.Body.String(), `"userName":"user1"`)
+		assert.Contains(t, rec.Body.String(), `"value":"user1@example.com"`)
+	})
+	t.Run("unsupported filter", func(t *testing.T) {
+		e, err := setupTestEnv()
+		require.NoError(t, err)
+		rec := scimRequest(t, e, http.MethodGet, scim.GetUsers, ``, url.Values{"filter": []string{`userName co "user"`}}, nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"scimType":"invalidFilter"`)
+	})
+	t.Run("nonexistent user", func(t *testing.T) {
+		e, err := setupTestEnv()
+		require.NoError(t, err)
+		rec := scimRequest(t, e, http.MethodGet, scim.GetUser, ``, nil, map[string]string{"id": "9999"})
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("deactivate and reactivate", func(t *testing.T) {
+		e, err := setupTestEnv()
+		require.NoError(t, err)
+		rec := scimRequest(t, e, http.MethodPatch, scim.PatchUser, `{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"Replace","path":"active","value":"False"}]}`, nil, map[string]string{"id": "3"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"active":false`)
+		db.AssertExists(t, "users", map[string]interface{}{
+			"id":     3,
+			"status": user.StatusDisabled,
+		}, false)
+		// Projects of deactivated users are kept
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":       4,
+			"owner_id": 3,
+		}, false)
+
+		rec = scimRequest(t, e, http.MethodPatch, scim.PatchUser, `{"Operations":[{"op":"replace","value":{"active":true}}]}`, nil, map[string]string{"id": "3"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		db.AssertExists(t, "users", map[string]interface{}{
+			"id":     3,
+			"status": user.StatusActive,
+		}, false)
+	})
+	t.Run("deactivating logs the user out", func(t *testing.T) {
+		e, err := setupTestEnv()
+		require.NoError(t, err)
+		s := db.NewSession()
+		defer s.Close()
+		session, err := user.CreateSession(s, &user.User{ID: 2}, "Firefox", "127.0.0.1", false, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		rec := scimRequest(t, e, http.MethodPatch, scim.PatchUser, `{"Operations":[{"op":"replace","path":"active","value":false}]}`, nil, map[string]string{"id": "2"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		db.AssertMissing(t, "user_sessions", map[string]interface{}{
+			"id": session.ID,
+		})
+		db.AssertExists(t, "revoked_sessions", map[string]interface{}{
+			"session_id": session.ID,
+		}, false)
+		db.AssertMissing(t, "api_tokens", map[string]interface{}{
+			"owner_id": 2,
+		})
+	})
+	t.Run("delete only deactivates", func(t *testing.T) {
+		e, err := setupTestEnv()
+		require.NoError(t, err)
+		rec := scimRequest(t, e, http.MethodDelete, scim.DeleteUser, ``, nil, map[string]string{"id": "3"})
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		db.AssertExists(t, "users", map[string]interface{}{
+			"id":     3,
+			"status": user.StatusDisabled,
+		}, false)
+	})
+}
+
+func TestSCIMGroups(t *testing.T) {
+	createGroup := func(t *testing.T, e *echo.Echo) *scim.Group {
+		rec := scimRequest(t, e, http.MethodPost, scim.CreateGroup, `{"schemas":["urn:ietf:params:scim:schemas:core:2.0:Group"],"displayName":"Engineering","externalId":"eng","members":[{"value":"1"}]}`, nil, nil)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		group := &scim.Group{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), group))
+		return group
+	}
+
+	t.Run("create", func(t *testing.T) {
+		e, err := setupTestEnv()
+		require.NoError(t, err)
+		group := createGroup(t, e)
+		assert.Equal(t, "Engineering", group.DisplayName)
+		require.Len(t, group.Members, 1)
+		assert.Equal(t, "1", group.Members[0].Value)
+		db.AssertExists(t, "teams", map[string]interface{}{
+			"id":      group.ID,
+			"name":    "Engineering",
+			"oidc_id": "eng",
+			"issuer":  "scim",
+		}, false)
+		db.AssertExists(t, "team_members", map[string]interface{}{
+			"team_id": group.ID,
+			"user_id": 1,
+		}, false)
+	})
+	t.Run("create with nonexistent member", func(t *testing.T) {
+		e, err := setupTestEnv()
+		require.NoError(t, err)
+		rec := scimRequest(t, e, http.MethodPost, scim.CreateGroup, `{"displayName":"Engineering","members":[{"value":"9999"}]}`, nil, nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("add and remove members", func(t *testing.T) {
+		e, err := setupTestEnv()
+		require.NoError(t, err)
+		group := createGroup(t, e)
+
+		rec := scimRequest(t, e, http.MethodPatch, scim.PatchGroup, `{"Operations":[{"op":"add","path":"members","value":[{"value":"2"}]},{"op":"remove","path":"members[value eq \"1\"]"}]}`, nil, map[string]string{"id": group.ID})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		db.AssertExists(t, "team_members", map[string]interface{}{
+			"team_id": group.ID,
+			"user_id": 2,
+		}, false)
+		db.AssertMissing(t, "team_members", map[string]interface{}{
+			"team_id": group.ID,
+			"user_id": 1,
+		})
+	})
+	t.Run("replace", func(t *testing.T) {
+		e, err := setupTestEnv()
+		require.NoError(t, err)
+		group := createGroup(t, e)
+
+		rec := scimRequest(t, e, http.MethodPut, scim.ReplaceGroup, `{"displayName":"Engineering Team","members":[{"value":"3"}]}`, nil, map[string]string{"id": group.ID})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"displayName":"Engineering Team"`)
+		db.AssertMissing(t, "team_members", map[string]interface{}{
+			"team_id": group.ID,
+			"user_id": 1,
+		})
+		db.AssertExists(t, "team_members", map[string]interface{}{
+			"team_id": group.ID,
+			"user_id": 3,
+		}, false)
+	})
+	t.Run("delete", func(t *testing.T) {
+		e, err := setupTestEnv()
+		require.NoError(t, err)
+		group := createGroup(t, e)
+
+		rec := scimRequest(t, e, http.MethodDelete, scim.DeleteGroup, ``, nil, map[string]string{"id": group.ID})
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		db.AssertMissing(t, "teams", map[string]interface{}{
+			"id": group.ID,
+		})
+	})
+	t.Run("teams not created through scim are not accessible", func(t *testing.T) {
+		e, err := setupTestEnv()
+		require.NoError(t, err)
+		rec := scimRequest(t, e, http.MethodGet, scim.GetGroup, ``, nil, map[string]string{"id": "1"})
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -30,7 +30,7 @@ import (
 func TestUserChangePassword(t *testing.T) {
 	t.Run("Normal test", func(t *testing.T) {
 		rec, err := newTestRequestWithUser(t, http.MethodPost, apiv1.UserChangePassword, &testuser1, `{
-  "new_password": "12345",
+  "new_password": "12345678",
   "old_password": "1234"
 }`, nil, nil)
 		require.NoError(t, err)
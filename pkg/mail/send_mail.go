@@ -31,6 +31,7 @@ import (
 type Opts struct {
 	From        string
 	To          string
+	ReplyTo     string
 	Subject     string
 	Message     string
 	HTMLMessage string
@@ -82,6 +83,9 @@ func getMessage(opts *Opts) *mail.Msg {
 	}
 	_ = m.From(opts.From)
 	_ = m.To(opts.To)
+	if opts.ReplyTo != "" {
+		_ = m.ReplyTo(opts.ReplyTo)
+	}
 	m.Subject(opts.Subject)
 
 	for _, h := range opts.Headers {
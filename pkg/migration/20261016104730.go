@@ -0,0 +1,49 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type taskDescriptionRevisions20261016104730 struct {
+	ID          int64     `xorm:"bigint autoincr not null unique pk"`
+	TaskID      int64     `xorm:"bigint not null INDEX"`
+	Description string    `xorm:"longtext null"`
+	CreatedByID int64     `xorm:"bigint not null"`
+	Created     time.Time `xorm:"created not null"`
+}
+
+func (taskDescriptionRevisions20261016104730) TableName() string {
+	return "task_description_revisions"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261016104730",
+		Description: "Create task description revisions table",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(taskDescriptionRevisions20261016104730{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,66 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type taskSLARules20261016112205 struct {
+	ID           int64     `xorm:"bigint autoincr not null unique pk"`
+	ProjectID    int64     `xorm:"bigint not null INDEX"`
+	Title        string    `xorm:"varchar(250) not null"`
+	Priority     int64     `xorm:"bigint not null default 0"`
+	MaxDuration  int64     `xorm:"bigint not null"`
+	BumpPriority bool      `xorm:"not null default false"`
+	NotifyAdmins bool      `xorm:"not null default false"`
+	LabelID      int64     `xorm:"bigint not null default 0"`
+	CreatedByID  int64     `xorm:"bigint not null"`
+	Created      time.Time `xorm:"created not null"`
+	Updated      time.Time `xorm:"updated not null"`
+}
+
+func (taskSLARules20261016112205) TableName() string {
+	return "task_sla_rules"
+}
+
+type taskSLABreaches20261016112205 struct {
+	ID      int64     `xorm:"bigint autoincr not null unique pk"`
+	TaskID  int64     `xorm:"bigint not null unique(task_rule)"`
+	RuleID  int64     `xorm:"bigint not null unique(task_rule)"`
+	Created time.Time `xorm:"created not null"`
+}
+
+func (taskSLABreaches20261016112205) TableName() string {
+	return "task_sla_breaches"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261016112205",
+		Description: "Create task sla rules and breaches tables",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(taskSLARules20261016112205{}, taskSLABreaches20261016112205{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
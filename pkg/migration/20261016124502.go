@@ -0,0 +1,56 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type tasks20261016124502 struct {
+	ApprovalStatus int `xorm:"int not null default 0"`
+}
+
+func (tasks20261016124502) TableName() string {
+	return "tasks"
+}
+
+type projectApprovers20261016124502 struct {
+	ID        int64     `xorm:"bigint autoincr not null unique pk"`
+	ProjectID int64     `xorm:"bigint not null unique(project_user)"`
+	UserID    int64     `xorm:"bigint not null unique(project_user)"`
+	Created   time.Time `xorm:"created not null"`
+}
+
+func (projectApprovers20261016124502) TableName() string {
+	return "project_approvers"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261016124502",
+		Description: "Add task approvals",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(tasks20261016124502{}, projectApprovers20261016124502{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
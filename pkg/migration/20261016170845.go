@@ -0,0 +1,54 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type webhookDeliveries20261016170845 struct {
+	ID          int64     `xorm:"bigint autoincr not null unique pk"`
+	WebhookID   int64     `xorm:"bigint not null INDEX"`
+	EventName   string    `xorm:"varchar(100) not null"`
+	Attempt     int       `xorm:"not null default 1"`
+	StatusCode  int       `xorm:"null"`
+	Error       string    `xorm:"text null"`
+	Success     bool      `xorm:"not null default false"`
+	Payload     string    `xorm:"longtext null"`
+	NextRetryAt time.Time `xorm:"DATETIME null INDEX 'next_retry_at'"`
+	Created     time.Time `xorm:"created not null"`
+}
+
+func (webhookDeliveries20261016170845) TableName() string {
+	return "webhook_deliveries"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261016170845",
+		Description: "Add webhook delivery logs",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(webhookDeliveries20261016170845{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return tx.DropTables(webhookDeliveries20261016170845{})
+		},
+	})
+}
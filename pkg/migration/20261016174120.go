@@ -0,0 +1,66 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type roles20261016174120 struct {
+	ID           int64     `xorm:"bigint autoincr not null unique pk"`
+	Title        string    `xorm:"varchar(250) not null"`
+	Description  string    `xorm:"longtext null"`
+	Capabilities []string  `xorm:"JSON not null"`
+	Created      time.Time `xorm:"created not null"`
+	Updated      time.Time `xorm:"updated not null"`
+}
+
+func (roles20261016174120) TableName() string {
+	return "roles"
+}
+
+type usersProjects20261016174120 struct {
+	RoleID int64 `xorm:"bigint null INDEX"`
+}
+
+func (usersProjects20261016174120) TableName() string {
+	return "users_projects"
+}
+
+type teamProjects20261016174120 struct {
+	RoleID int64 `xorm:"bigint null INDEX"`
+}
+
+func (teamProjects20261016174120) TableName() string {
+	return "team_projects"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261016174120",
+		Description: "Add custom roles for project shares",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(roles20261016174120{}, usersProjects20261016174120{}, teamProjects20261016174120{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,100 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type projects20261016221412 struct {
+	ID                    int64 `xorm:"bigint autoincr not null unique pk"`
+	ParentProjectID       int64 `xorm:"bigint INDEX null"`
+	IsArchived            bool  `xorm:"not null default false"`
+	ArchivedWithProjectID int64 `xorm:"bigint INDEX null"`
+}
+
+func (projects20261016221412) TableName() string {
+	return "projects"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261016221412",
+		Description: "Archive child projects of archived projects",
+		Migrate: func(tx *xorm.Engine) error {
+			err := tx.Sync2(projects20261016221412{})
+			if err != nil {
+				return err
+			}
+
+			projects := []*projects20261016221412{}
+			err = tx.Find(&projects)
+			if err != nil {
+				return err
+			}
+
+			children := make(map[int64][]*projects20261016221412)
+			for _, p := range projects {
+				children[p.ParentProjectID] = append(children[p.ParentProjectID], p)
+			}
+
+			// Child projects which are archived on their own archive their own children, that's why the
+			// walk stops there.
+			var archiveChildren func(parentID, archivedWithID int64) error
+			archiveChildren = func(parentID, archivedWithID int64) error {
+				for _, child := range children[parentID] {
+					if child.IsArchived {
+						continue
+					}
+
+					child.IsArchived = true
+					child.ArchivedWithProjectID = archivedWithID
+					_, err := tx.
+						Where("id = ?", child.ID).
+						Cols("is_archived", "archived_with_project_id").
+						Update(child)
+					if err != nil {
+						return err
+					}
+
+					err = archiveChildren(child.ID, archivedWithID)
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			for _, p := range projects {
+				if !p.IsArchived || p.ArchivedWithProjectID != 0 {
+					continue
+				}
+
+				err = archiveChildren(p.ID, p.ID)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
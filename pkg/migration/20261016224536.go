@@ -0,0 +1,58 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type projectViews20261016224536 struct {
+	SwimlaneMode int `xorm:"default 0"`
+}
+
+func (projectViews20261016224536) TableName() string {
+	return "project_views"
+}
+
+type taskSwimlanePositions20261016224536 struct {
+	TaskID        int64   `xorm:"bigint not null index"`
+	ProjectViewID int64   `xorm:"bigint not null index"`
+	SwimlaneID    int64   `xorm:"bigint not null"`
+	Position      float64 `xorm:"double not null"`
+}
+
+func (taskSwimlanePositions20261016224536) TableName() string {
+	return "task_swimlane_positions"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261016224536",
+		Description: "Add swimlanes to kanban views",
+		Migrate: func(tx *xorm.Engine) error {
+			err := tx.Sync2(projectViews20261016224536{})
+			if err != nil {
+				return err
+			}
+			return tx.Sync2(taskSwimlanePositions20261016224536{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
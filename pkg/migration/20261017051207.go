@@ -0,0 +1,53 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type projectViewPreferences20261017051207 struct {
+	ID               int64            `xorm:"bigint autoincr not null unique pk"`
+	UserID           int64            `xorm:"bigint not null unique(user_project)"`
+	ProjectID        int64            `xorm:"bigint not null unique(user_project) INDEX"`
+	ActiveViewID     int64            `xorm:"bigint null"`
+	SortBy           []string         `xorm:"json null"`
+	OrderBy          []string         `xorm:"json null"`
+	CollapsedBuckets []int64          `xorm:"json null"`
+	ColumnWidths     map[string]int64 `xorm:"json null"`
+	Updated          time.Time        `xorm:"updated not null"`
+}
+
+func (projectViewPreferences20261017051207) TableName() string {
+	return "project_view_preferences"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261017051207",
+		Description: "Add per user project view preferences",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(projectViewPreferences20261017051207{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,53 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type inboxItems20261017060318 struct {
+	ID        int64     `xorm:"bigint autoincr not null unique pk"`
+	UserID    int64     `xorm:"bigint not null INDEX"`
+	Kind      string    `xorm:"varchar(50) not null"`
+	TaskID    int64     `xorm:"bigint null INDEX"`
+	CommentID int64     `xorm:"bigint null"`
+	ProjectID int64     `xorm:"bigint null INDEX"`
+	DoerID    int64     `xorm:"bigint null"`
+	ReadAt    time.Time `xorm:"datetime null"`
+	Created   time.Time `xorm:"created not null"`
+}
+
+func (inboxItems20261017060318) TableName() string {
+	return "inbox_items"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261017060318",
+		Description: "Add inbox items",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(inboxItems20261017060318{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
@@ -0,0 +1,59 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type labelGroups20261017071530 struct {
+	ID          int64     `xorm:"bigint autoincr not null unique pk"`
+	Title       string    `xorm:"varchar(250) not null"`
+	Description string    `xorm:"longtext null"`
+	Exclusive   bool      `xorm:"bool not null default false"`
+	CreatedByID int64     `xorm:"bigint not null INDEX"`
+	Created     time.Time `xorm:"created not null"`
+	Updated     time.Time `xorm:"updated not null"`
+}
+
+func (labelGroups20261017071530) TableName() string {
+	return "label_groups"
+}
+
+type labels20261017071530 struct {
+	GroupID int64 `xorm:"bigint null INDEX"`
+}
+
+func (labels20261017071530) TableName() string {
+	return "labels"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261017071530",
+		Description: "Add label groups",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(labelGroups20261017071530{}, labels20261017071530{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
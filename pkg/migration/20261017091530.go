@@ -0,0 +1,55 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type taskAttachmentUploads20261017091530 struct {
+	ID          int64     `xorm:"bigint autoincr not null unique pk"`
+	TaskID      int64     `xorm:"bigint not null INDEX"`
+	CommentID   int64     `xorm:"bigint null default 0"`
+	FileName    string    `xorm:"text not null"`
+	Size        uint64    `xorm:"bigint not null"`
+	Received    uint64    `xorm:"bigint not null default 0"`
+	Chunks      int       `xorm:"int not null default 0"`
+	CreatedByID int64     `xorm:"bigint not null"`
+	Expires     time.Time `xorm:"not null"`
+	Created     time.Time `xorm:"created not null"`
+	Updated     time.Time `xorm:"updated not null"`
+}
+
+func (taskAttachmentUploads20261017091530) TableName() string {
+	return "task_attachment_uploads"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261017091530",
+		Description: "Add resumable task attachment uploads",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(taskAttachmentUploads20261017091530{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return tx.DropTables(taskAttachmentUploads20261017091530{})
+		},
+	})
+}
@@ -0,0 +1,61 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type taskAttachments20261017093210 struct {
+	Version int64 `xorm:"bigint not null default 1"`
+}
+
+func (taskAttachments20261017093210) TableName() string {
+	return "task_attachments"
+}
+
+type taskAttachmentVersions20261017093210 struct {
+	ID           int64     `xorm:"bigint autoincr not null unique pk"`
+	AttachmentID int64     `xorm:"bigint not null INDEX"`
+	Version      int64     `xorm:"bigint not null"`
+	FileID       int64     `xorm:"bigint not null"`
+	Replaced     time.Time `xorm:"created not null"`
+}
+
+func (taskAttachmentVersions20261017093210) TableName() string {
+	return "task_attachment_versions"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261017093210",
+		Description: "Add versions of task attachments",
+		Migrate: func(tx *xorm.Engine) error {
+			err := tx.Sync2(taskAttachments20261017093210{})
+			if err != nil {
+				return err
+			}
+			return tx.Sync2(taskAttachmentVersions20261017093210{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return tx.DropTables(taskAttachmentVersions20261017093210{})
+		},
+	})
+}
@@ -0,0 +1,47 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type userNotificationPreferences20261017100512 struct {
+	ID           int64  `xorm:"bigint autoincr not null unique pk"`
+	UserID       int64  `xorm:"bigint not null INDEX"`
+	Notification string `xorm:"varchar(250) not null"`
+	Channel      string `xorm:"varchar(50) not null"`
+	Enabled      bool   `xorm:"bool not null default true"`
+}
+
+func (userNotificationPreferences20261017100512) TableName() string {
+	return "user_notification_preferences"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261017100512",
+		Description: "Add user notification preferences",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(userNotificationPreferences20261017100512{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
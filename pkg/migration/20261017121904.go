@@ -0,0 +1,51 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type pushSubscriptions20261017121904 struct {
+	ID         int64     `xorm:"bigint autoincr not null unique pk"`
+	UserID     int64     `xorm:"bigint not null INDEX"`
+	Endpoint   string    `xorm:"text not null"`
+	P256dh     string    `xorm:"varchar(255) not null"`
+	Auth       string    `xorm:"varchar(255) not null"`
+	DeviceName string    `xorm:"varchar(250) null"`
+	Created    time.Time `xorm:"created not null"`
+}
+
+func (pushSubscriptions20261017121904) TableName() string {
+	return "push_subscriptions"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261017121904",
+		Description: "Add push subscriptions",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(pushSubscriptions20261017121904{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
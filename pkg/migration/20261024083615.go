@@ -0,0 +1,53 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type auditLog20261024083615 struct {
+	ID             int64     `xorm:"bigint autoincr not null unique pk"`
+	UserID         int64     `xorm:"bigint not null index"`
+	ImpersonatorID int64     `xorm:"bigint not null index"`
+	Action         string    `xorm:"varchar(50) not null"`
+	Method         string    `xorm:"varchar(10) null"`
+	Path           string    `xorm:"text null"`
+	StatusCode     int       `xorm:"int null"`
+	IPAddress      string    `xorm:"varchar(255) null"`
+	Created        time.Time `xorm:"created not null index"`
+}
+
+func (auditLog20261024083615) TableName() string {
+	return "audit_log"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261024083615",
+		Description: "Add audit log table",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(auditLog20261024083615{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return tx.DropTables(auditLog20261024083615{})
+		},
+	})
+}
@@ -0,0 +1,51 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type fileContents20261026102317 struct {
+	Sha256         string `xorm:"char(64) not null pk"`
+	ReferenceCount int64  `xorm:"bigint not null default 0"`
+}
+
+func (fileContents20261026102317) TableName() string {
+	return "file_contents"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261026102317",
+		Description: "Count how many files use stored content",
+		Migrate: func(tx *xorm.Engine) error {
+			err := tx.Sync2(fileContents20261026102317{})
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Exec("INSERT INTO file_contents (sha256, reference_count) " +
+				"SELECT sha256, COUNT(*) FROM files WHERE sha256 IS NOT NULL AND sha256 != '' GROUP BY sha256")
+			return err
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return nil
+		},
+	})
+}
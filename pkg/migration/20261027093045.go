@@ -0,0 +1,46 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"time"
+
+	"src.techknowlogick.com/xormigrate"
+	"xorm.io/xorm"
+)
+
+type usedTaskActionTokens20261027093045 struct {
+	TokenID   string    `xorm:"varchar(36) not null unique pk"`
+	ExpiresAt time.Time `xorm:"datetime not null index"`
+}
+
+func (usedTaskActionTokens20261027093045) TableName() string {
+	return "used_task_action_tokens"
+}
+
+func init() {
+	migrations = append(migrations, &xormigrate.Migration{
+		ID:          "20261027093045",
+		Description: "Add used task action tokens table to make task action links single use",
+		Migrate: func(tx *xorm.Engine) error {
+			return tx.Sync2(usedTaskActionTokens20261027093045{})
+		},
+		Rollback: func(tx *xorm.Engine) error {
+			return tx.DropTables(usedTaskActionTokens20261027093045{})
+		},
+	})
+}
@@ -20,6 +20,8 @@ import (
 	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/hex"
+	"slices"
+	"strconv"
 	"time"
 
 	"xorm.io/builder"
@@ -28,6 +30,7 @@ import (
 	"code.vikunja.io/api/pkg/utils"
 
 	"code.vikunja.io/web"
+	"github.com/labstack/echo/v4"
 	"golang.org/x/crypto/pbkdf2"
 	"xorm.io/xorm"
 )
@@ -49,6 +52,10 @@ type APIToken struct {
 	Permissions APIPermissions `xorm:"json not null" json:"permissions" valid:"required"`
 	// The date when this key expires.
 	ExpiresAt time.Time `xorm:"not null" json:"expires_at" valid:"required"`
+	// If true, the token can only be used to read data, regardless of its permissions.
+	ReadOnly bool `xorm:"not null default false" json:"read_only"`
+	// The ids of the projects this token is limited to. If empty, the token can access all projects its owner has access to. A token limited to projects can only be used with routes about one of these projects or a task in one of them.
+	ProjectIDs []int64 `xorm:"json null" json:"project_ids"`
 
 	// A timestamp when this api key was created. You cannot change this value.
 	Created time.Time `xorm:"created not null" json:"created"`
@@ -106,6 +113,16 @@ func (t *APIToken) Create(s *xorm.Session, a web.Auth) (err error) {
 		return err
 	}
 
+	for _, projectID := range t.ProjectIDs {
+		can, _, err := (&Project{ID: projectID}).CanRead(s, a)
+		if err != nil {
+			return err
+		}
+		if !can {
+			return ErrGenericForbidden{}
+		}
+	}
+
 	_, err = s.Insert(t)
 	return err
 }
@@ -170,6 +187,12 @@ func (t *APIToken) Delete(s *xorm.Session, a web.Auth) (err error) {
 	return err
 }
 
+// DeleteAllAPITokensOfUser removes all api tokens of a user so they can't be used anymore.
+func DeleteAllAPITokensOfUser(s *xorm.Session, userID int64) (err error) {
+	_, err = s.Where("owner_id = ?", userID).Delete(&APIToken{})
+	return err
+}
+
 // GetTokenFromTokenString returns the full token object from the original token string.
 func GetTokenFromTokenString(s *xorm.Session, token string) (apiToken *APIToken, err error) {
 	lastEight := token[len(token)-8:]
@@ -189,3 +212,45 @@ func GetTokenFromTokenString(s *xorm.Session, token string) (apiToken *APIToken,
 
 	return nil, &ErrAPITokenInvalid{}
 }
+
+// CanAccessRouteProject checks if the project of the current route is one of the projects the token is limited to.
+// Routes which are not about a single project or task can't be used by a token limited to projects.
+func (t *APIToken) CanAccessRouteProject(s *xorm.Session, c echo.Context) (can bool, err error) {
+	if len(t.ProjectIDs) == 0 {
+		return true, nil
+	}
+
+	projectParam := c.Param("project")
+	if projectParam == "" {
+		projectParam = c.Param("projectid")
+	}
+	if projectParam != "" {
+		projectID, err := strconv.ParseInt(projectParam, 10, 64)
+		if err != nil {
+			return false, nil
+		}
+		return slices.Contains(t.ProjectIDs, projectID), nil
+	}
+
+	taskParam := c.Param("projecttask")
+	if taskParam == "" {
+		taskParam = c.Param("task")
+	}
+	if taskParam == "" {
+		return false, nil
+	}
+
+	taskID, err := strconv.ParseInt(taskParam, 10, 64)
+	if err != nil {
+		return false, nil
+	}
+	task, err := GetTaskByIDSimple(s, taskID)
+	if err != nil {
+		if IsErrTaskDoesNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return slices.Contains(t.ProjectIDs, task.ProjectID), nil
+}
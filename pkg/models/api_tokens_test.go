@@ -17,11 +17,14 @@
 package models
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"code.vikunja.io/api/pkg/db"
 	"code.vikunja.io/api/pkg/user"
 
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -93,6 +96,134 @@ func TestAPIToken_Create(t *testing.T) {
 		err := token.Create(s, u)
 		require.NoError(t, err)
 	})
+	t.Run("limited to a project", func(t *testing.T) {
+		u := &user.User{ID: 1}
+		token := &APIToken{ProjectIDs: []int64{1}}
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		err := token.Create(s, u)
+		require.NoError(t, err)
+	})
+	t.Run("limited to a project without access", func(t *testing.T) {
+		u := &user.User{ID: 2}
+		token := &APIToken{ProjectIDs: []int64{1}}
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		err := token.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+}
+
+func TestAPIToken_CanAccessRouteProject(t *testing.T) {
+	newContext := func(param, value string) echo.Context {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+		if param != "" {
+			c.SetParamNames(param)
+			c.SetParamValues(value)
+		}
+		return c
+	}
+	token := &APIToken{ProjectIDs: []int64{1}}
+
+	t.Run("not limited", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		can, err := (&APIToken{}).CanAccessRouteProject(s, newContext("", ""))
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("project route", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		can, err := token.CanAccessRouteProject(s, newContext("project", "1"))
+		require.NoError(t, err)
+		assert.True(t, can)
+		can, err = token.CanAccessRouteProject(s, newContext("project", "2"))
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("task route", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		can, err := token.CanAccessRouteProject(s, newContext("projecttask", "1"))
+		require.NoError(t, err)
+		assert.True(t, can)
+		can, err = token.CanAccessRouteProject(s, newContext("task", "13"))
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("route without project", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		db.LoadAndAssertFixtures(t)
+
+		can, err := token.CanAccessRouteProject(s, newContext("", ""))
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestAPIToken_ProjectScope(t *testing.T) {
+	// User 1 owns projects 1 and 21, the token is only allowed to access project 1
+	u := &user.User{ID: 1, APITokenProjectIDs: []int64{1}}
+
+	t.Run("projects", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		can, _, err := (&Project{ID: 1}).CanRead(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		can, _, err = (&Project{ID: 21}).CanRead(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+		can, err = (&Project{ID: 1, ParentProjectID: 21}).CanUpdate(s, u)
+		require.Error(t, err)
+		assert.False(t, can)
+		can, err = (&Project{}).CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("moving a task to another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		can, err := (&Task{ID: 1, ProjectID: 21}).CanUpdate(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+		assert.False(t, can)
+	})
+	t.Run("relation to a task in another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		can, err := (&TaskRelation{TaskID: 1, OtherTaskID: 35, RelationKind: RelationKindRelated}).CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("moving a task from another project in a bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		can, err := (&TaskBucket{TaskID: 35, BucketID: 1, ProjectID: 1, ProjectViewID: 4}).CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
 }
 
 func TestAPIToken_GetTokenFromTokenString(t *testing.T) {
@@ -0,0 +1,96 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/log"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// These are all actions which end up in the audit log
+const (
+	AuditActionImpersonationStarted = "impersonation.started"
+	AuditActionImpersonatedRequest  = "impersonation.request"
+)
+
+// AuditLogEntry records an action an instance admin did on behalf of another user.
+type AuditLogEntry struct {
+	// The unique, numeric id of this entry.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id"`
+	// The id of the user the action was performed as.
+	UserID int64 `xorm:"bigint not null index" json:"user_id"`
+	// The id of the admin who impersonated the user.
+	ImpersonatorID int64 `xorm:"bigint not null index" json:"impersonator_id"`
+	// What happened, for example "impersonation.started" or "impersonation.request".
+	Action string `xorm:"varchar(50) not null" json:"action"`
+	// The http method and path of the request, if the action was a request.
+	Method string `xorm:"varchar(10) null" json:"method"`
+	Path   string `xorm:"text null" json:"path"`
+	// The http status code the request was answered with.
+	StatusCode int `xorm:"int null" json:"status_code"`
+	// The ip address the action came from.
+	IPAddress string `xorm:"varchar(255) null" json:"ip_address"`
+	// A timestamp when this action happened.
+	Created time.Time `xorm:"created not null index" json:"created"`
+}
+
+// TableName holds the table name for the audit log
+func (*AuditLogEntry) TableName() string {
+	return "audit_log"
+}
+
+// CreateAuditLogEntry saves an entry to the audit log
+func CreateAuditLogEntry(s *xorm.Session, entry *AuditLogEntry) (err error) {
+	entry.ID = 0
+	_, err = s.Insert(entry)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("[Audit] %s: admin %d acting as user %d %s %s", entry.Action, entry.ImpersonatorID, entry.UserID, entry.Method, entry.Path)
+	return nil
+}
+
+// GetAuditLogEntries returns the audit log, newest entries first. If a user id is provided, only entries of
+// actions performed as or by that user are returned.
+func GetAuditLogEntries(s *xorm.Session, userID int64, page, perPage int) (entries []*AuditLogEntry, totalCount int64, err error) {
+	var cond builder.Cond = builder.NewCond()
+	if userID != 0 {
+		cond = builder.Or(
+			builder.Eq{"user_id": userID},
+			builder.Eq{"impersonator_id": userID},
+		)
+	}
+
+	entries = []*AuditLogEntry{}
+	limit, start := getLimitFromPageIndex(page, perPage)
+	query := s.Where(cond).OrderBy("created desc, id desc")
+	if limit > 0 {
+		query = query.Limit(limit, start)
+	}
+	err = query.Find(&entries)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	totalCount, err = s.Where(cond).Count(&AuditLogEntry{})
+	return entries, totalCount, err
+}
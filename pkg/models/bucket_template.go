@@ -0,0 +1,242 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// BucketTemplate is a bucket which is created in the kanban view of every new project below the project it
+// belongs to, instead of the default "Backlog" bucket.
+type BucketTemplate struct {
+	// The unique, numeric id of this bucket template.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"bucketTemplate"`
+	// The project whose child projects get this bucket.
+	ProjectID int64 `xorm:"bigint not null index" json:"project_id" param:"project"`
+	// The title of the bucket.
+	Title string `xorm:"text not null" valid:"required" minLength:"1" json:"title"`
+	// How many tasks can be in the bucket at the same time max.
+	Limit int64 `xorm:"default 0" json:"limit" minimum:"0" valid:"range(0|9223372036854775807)"`
+	// The position of the bucket in the new kanban view. Buckets are created in ascending order of their position.
+	Position float64 `xorm:"double null" json:"position"`
+	// If true, the bucket is used as the done bucket of the new kanban view. Only one template of a project can be
+	// the done bucket.
+	IsDoneBucket bool `xorm:"not null default false" json:"is_done_bucket"`
+
+	// A timestamp when this bucket template was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this bucket template was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for bucket templates
+func (*BucketTemplate) TableName() string {
+	return "bucket_templates"
+}
+
+// Create creates a new bucket template
+// @Summary Create a bucket template
+// @Description Adds a bucket to the set of buckets every new child project of this project starts with in its kanban view. Once a project has bucket templates, new child projects don't get a "Backlog" bucket anymore. Projects further down the tree use the templates of their closest parent which has some.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param template body models.BucketTemplate true "The bucket template"
+// @Success 201 {object} models.BucketTemplate "The created bucket template."
+// @Failure 400 {object} web.HTTPError "Invalid bucket template provided."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/bucket-templates [put]
+func (bt *BucketTemplate) Create(s *xorm.Session, _ web.Auth) (err error) {
+	bt.ID = 0
+	err = bt.unsetOtherDoneBuckets(s)
+	if err != nil {
+		return
+	}
+
+	_, err = s.Insert(bt)
+	return
+}
+
+// ReadAll returns all bucket templates of a project
+// @Summary Get all bucket templates of a project
+// @Description Returns the buckets every new child project of this project starts with, sorted by their position.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Success 200 {array} models.BucketTemplate "The bucket templates"
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/bucket-templates [get]
+func (bt *BucketTemplate) ReadAll(s *xorm.Session, a web.Auth, _ string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	can, _, err := bt.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	templates := []*BucketTemplate{}
+	err = s.
+		Where("project_id = ?", bt.ProjectID).
+		OrderBy("position asc, id asc").
+		Find(&templates)
+	return templates, len(templates), int64(len(templates)), err
+}
+
+// Update updates a bucket template
+// @Summary Update a bucket template
+// @Description Updates a bucket template. Projects which were already created are not changed.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param bucketTemplate path int true "Bucket template ID"
+// @Param template body models.BucketTemplate true "The bucket template"
+// @Success 200 {object} models.BucketTemplate "The updated bucket template."
+// @Failure 400 {object} web.HTTPError "Invalid bucket template provided."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/bucket-templates/{bucketTemplate} [post]
+func (bt *BucketTemplate) Update(s *xorm.Session, _ web.Auth) (err error) {
+	err = bt.unsetOtherDoneBuckets(s)
+	if err != nil {
+		return
+	}
+
+	_, err = s.
+		Where("id = ? AND project_id = ?", bt.ID, bt.ProjectID).
+		Cols("title", "limit", "position", "is_done_bucket").
+		Update(bt)
+	return
+}
+
+// Delete removes a bucket template
+// @Summary Delete a bucket template
+// @Description Removes a bucket template. Projects which were already created are not changed.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param bucketTemplate path int true "Bucket template ID"
+// @Success 200 {object} models.Message "The bucket template was deleted."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/bucket-templates/{bucketTemplate} [delete]
+func (bt *BucketTemplate) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.
+		Where("id = ? AND project_id = ?", bt.ID, bt.ProjectID).
+		Delete(&BucketTemplate{})
+	return
+}
+
+func (bt *BucketTemplate) unsetOtherDoneBuckets(s *xorm.Session) (err error) {
+	if !bt.IsDoneBucket {
+		return nil
+	}
+
+	_, err = s.
+		Where("project_id = ? AND id != ?", bt.ProjectID, bt.ID).
+		Cols("is_done_bucket").
+		Update(&BucketTemplate{IsDoneBucket: false})
+	return
+}
+
+// getBucketTemplatesForNewProject returns the bucket templates of the closest parent of a project which has some.
+func getBucketTemplatesForNewProject(s *xorm.Session, project *Project) (templates []*BucketTemplate, err error) {
+	if project.ParentProjectID == 0 {
+		return nil, nil
+	}
+
+	parents, err := GetAllParentProjects(s, project.ParentProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	parentIDs := make([]int64, 0, len(parents))
+	for id := range parents {
+		parentIDs = append(parentIDs, id)
+	}
+
+	allTemplates := []*BucketTemplate{}
+	err = s.
+		In("project_id", parentIDs).
+		OrderBy("position asc, id asc").
+		Find(&allTemplates)
+	if err != nil || len(allTemplates) == 0 {
+		return nil, err
+	}
+
+	templatesByProject := make(map[int64][]*BucketTemplate)
+	for _, t := range allTemplates {
+		templatesByProject[t.ProjectID] = append(templatesByProject[t.ProjectID], t)
+	}
+
+	for id := project.ParentProjectID; id != 0; {
+		if ts, has := templatesByProject[id]; has {
+			return ts, nil
+		}
+		parent, has := parents[id]
+		if !has {
+			break
+		}
+		id = parent.ParentProjectID
+	}
+
+	return nil, nil
+}
+
+// createBucketsFromTemplates creates one bucket per template in a kanban view.
+func (p *ProjectView) createBucketsFromTemplates(s *xorm.Session, templates []*BucketTemplate, a web.Auth) (err error) {
+	for _, t := range templates {
+		b := &Bucket{
+			ProjectViewID: p.ID,
+			Title:         t.Title,
+			Limit:         t.Limit,
+			Position:      t.Position,
+		}
+		err = b.Create(s, a)
+		if err != nil {
+			return err
+		}
+
+		if t.IsDoneBucket {
+			p.DoneBucketID = b.ID
+		}
+	}
+
+	if p.DoneBucketID == 0 {
+		return nil
+	}
+
+	_, err = s.
+		Where("id = ?", p.ID).
+		Cols("done_bucket_id").
+		Update(p)
+	return
+}
@@ -0,0 +1,109 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func TestBucketTemplate(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	getKanbanBuckets := func(t *testing.T, s *xorm.Session, project *Project) (*ProjectView, []*Bucket) {
+		var kanban *ProjectView
+		for _, v := range project.Views {
+			if v.ViewKind == ProjectViewKindKanban {
+				kanban = v
+			}
+		}
+		require.NotNil(t, kanban)
+
+		buckets := []*Bucket{}
+		err := s.Where("project_view_id = ?", kanban.ID).OrderBy("position asc").Find(&buckets)
+		require.NoError(t, err)
+		return kanban, buckets
+	}
+
+	t.Run("new child projects get the buckets", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		for i, title := range []string{"To Do", "Doing", "Done"} {
+			bt := &BucketTemplate{
+				ProjectID:    1,
+				Title:        title,
+				Position:     float64(i + 1),
+				IsDoneBucket: title == "Done",
+			}
+			err := bt.Create(s, u)
+			require.NoError(t, err)
+		}
+
+		child := &Project{Title: "Child", ParentProjectID: 1}
+		err := child.Create(s, u)
+		require.NoError(t, err)
+
+		kanban, buckets := getKanbanBuckets(t, s, child)
+		require.Len(t, buckets, 3)
+		assert.Equal(t, "To Do", buckets[0].Title)
+		assert.Equal(t, "Doing", buckets[1].Title)
+		assert.Equal(t, "Done", buckets[2].Title)
+		db.AssertExists(t, "project_views", map[string]interface{}{
+			"id":             kanban.ID,
+			"done_bucket_id": buckets[2].ID,
+		}, false)
+
+		// Projects further down use the templates of their closest parent
+		grandChild := &Project{Title: "Grandchild", ParentProjectID: child.ID}
+		err = grandChild.Create(s, u)
+		require.NoError(t, err)
+
+		_, buckets = getKanbanBuckets(t, s, grandChild)
+		assert.Len(t, buckets, 3)
+	})
+	t.Run("projects without templates get a backlog", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		child := &Project{Title: "Child", ParentProjectID: 1}
+		err := child.Create(s, u)
+		require.NoError(t, err)
+
+		_, buckets := getKanbanBuckets(t, s, child)
+		require.Len(t, buckets, 1)
+		assert.Equal(t, "Backlog", buckets[0].Title)
+	})
+	t.Run("only admins can manage templates", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		bt := &BucketTemplate{ProjectID: 1, Title: "To Do"}
+		can, err := bt.CanCreate(s, &user.User{ID: 2})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
@@ -80,8 +80,15 @@ func (bt *BulkTask) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /tasks/bulk [post]
 func (bt *BulkTask) Update(s *xorm.Session, a web.Auth) (err error) {
+	// Who completed a task is set per task below and must not come from the request
+	bt.Task.DoneBy = nil
+	bt.Task.DoneVia = ""
+
 	for _, oldtask := range bt.Tasks {
 
+		markedDone := !oldtask.Done && bt.Task.Done
+		markedUndone := oldtask.Done && !bt.Task.Done
+
 		// When a repeating task is marked as done, we update all deadlines and reminders and set it as undone
 		updateDone(oldtask, &bt.Task)
 
@@ -102,6 +109,13 @@ func (bt *BulkTask) Update(s *xorm.Session, a web.Auth) (err error) {
 			oldtask.Done = false
 		}
 
+		if markedDone {
+			oldtask.setDoneBy(a)
+		}
+		if markedUndone {
+			oldtask.setDoneBy(nil)
+		}
+
 		_, err = s.ID(oldtask.ID).
 			Cols("title",
 				"description",
@@ -111,11 +125,18 @@ func (bt *BulkTask) Update(s *xorm.Session, a web.Auth) (err error) {
 				"repeat_after",
 				"priority",
 				"start_date",
-				"end_date").
+				"end_date",
+				"done_by_id",
+				"done_via").
 			Update(oldtask)
 		if err != nil {
 			return err
 		}
+
+		err = resetReminderTriggers(s, oldtask.ID)
+		if err != nil {
+			return err
+		}
 	}
 
 	return
@@ -0,0 +1,452 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/api/pkg/utils"
+
+	"code.vikunja.io/web"
+	"github.com/google/uuid"
+	"xorm.io/xorm"
+)
+
+// The maximum number of tasks which can be created with one bulk request
+const maxBulkCreateTasks = 1000
+
+// How many tasks are inserted with one statement
+const bulkInsertBatchSize = 100
+
+// BulkTaskCreate creates many tasks in one project at once
+type BulkTaskCreate struct {
+	// The project to create the tasks in
+	ProjectID int64 `json:"-" param:"project"`
+	// The tasks to create. Labels and assignees can be passed with each task, only their ids are used.
+	Tasks []*Task `json:"tasks"`
+	// One result per task, in the same order as the tasks were passed.
+	Results []*BulkTaskCreateResult `json:"results"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// BulkTaskCreateResult holds the outcome of creating a single task in a bulk request
+type BulkTaskCreateResult struct {
+	// The position of the task in the request
+	Index int `json:"index"`
+	// The created task, if it could be created.
+	Task *Task `json:"task,omitempty"`
+	// Why the task could not be created.
+	Error *web.HTTPError `json:"error,omitempty"`
+}
+
+// CanCreate checks if a user can create tasks in the project
+func (btc *BulkTaskCreate) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	p := &Project{ID: btc.ProjectID}
+	return p.CanWrite(s, a)
+}
+
+// Create creates many tasks at once
+// @Summary Create many tasks at once
+// @Description Creates all passed tasks in the project in one transaction. Every task is checked on its own, the response contains the created task or the reason why it could not be created for each of them. Labels and assignees can be passed inline with each task.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project ID"
+// @Param tasks body models.BulkTaskCreate true "The tasks to create"
+// @Success 201 {object} models.BulkTaskCreate "The result for each task."
+// @Failure 400 {object} web.HTTPError "No or too many tasks provided."
+// @Failure 403 {object} web.HTTPError "The user does not have write access to the project"
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/tasks/bulk [put]
+func (btc *BulkTaskCreate) Create(s *xorm.Session, a web.Auth) (err error) {
+	if len(btc.Tasks) == 0 {
+		return ErrBulkTasksNeedAtLeastOne{}
+	}
+	if len(btc.Tasks) > maxBulkCreateTasks {
+		return ErrTooManyBulkTasks{Count: len(btc.Tasks), Max: maxBulkCreateTasks}
+	}
+
+	project, err := GetProjectSimpleByID(s, btc.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	err = project.checkIsFrozen(s)
+	if err != nil {
+		return err
+	}
+
+	createdBy, err := GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return err
+	}
+
+	checker := &bulkTaskChecker{
+		project:           project,
+		auth:              a,
+		labels:            make(map[int64]*Label),
+		users:             make(map[int64]*user.User),
+		approvalRequested: make(map[*Task]bool),
+	}
+
+	btc.Results = make([]*BulkTaskCreateResult, 0, len(btc.Tasks))
+	tasks := make([]*Task, 0, len(btc.Tasks))
+	for i, t := range btc.Tasks {
+		result := &BulkTaskCreateResult{Index: i}
+		btc.Results = append(btc.Results, result)
+
+		t.ProjectID = project.ID
+		err = checker.check(s, t)
+		if err != nil {
+			httpErr, is := err.(web.HTTPErrorProcessor)
+			if !is {
+				return err
+			}
+			e := httpErr.HTTPError()
+			result.Error = &e
+			continue
+		}
+
+		result.Task = t
+		tasks = append(tasks, t)
+	}
+
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	err = insertBulkTasks(s, project, tasks, createdBy)
+	if err != nil {
+		return err
+	}
+
+	err = insertPositionsAndBucketsForNewTasks(s, project.ID, tasks)
+	if err != nil {
+		return err
+	}
+
+	labelTasks := []*LabelTask{}
+	for _, t := range tasks {
+		labels := make([]*Label, 0, len(t.Labels))
+		for _, l := range t.Labels {
+			labelTasks = append(labelTasks, &LabelTask{TaskID: t.ID, LabelID: l.ID})
+			labels = append(labels, checker.labels[l.ID])
+		}
+		t.Labels = labels
+	}
+	if len(labelTasks) > 0 {
+		_, err = s.Insert(&labelTasks)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, t := range tasks {
+		assignees := make([]*user.User, 0, len(t.Assignees))
+		for _, assignee := range t.Assignees {
+			err = t.addNewAssigneeByID(s, assignee.ID, project, a)
+			if err != nil {
+				return err
+			}
+			assignees = append(assignees, checker.users[assignee.ID])
+		}
+		t.setTaskAssignees(assignees)
+
+		if len(t.Reminders) > 0 {
+			err = t.updateReminders(s, t)
+			if err != nil {
+				return err
+			}
+		}
+
+		// The labels passed with the task were added already and win over default ones of the same exclusive group
+		err = project.TaskDefaults.applyAfterCreate(s, t, project, a)
+		if err != nil {
+			return err
+		}
+
+		t.setIdentifier(project)
+
+		if t.IsFavorite {
+			err = addToFavorites(s, t.ID, createdBy, FavoriteKindTask)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = events.Dispatch(&TaskCreatedEvent{
+			Task: t,
+			Doer: createdBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		if checker.approvalRequested[t] {
+			err = events.Dispatch(&TaskApprovalRequestedEvent{
+				Task: t,
+				Doer: createdBy,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return updateProjectLastUpdated(s, project)
+}
+
+// insertBulkTasks inserts the checked tasks of a bulk request, using one insert for every bulkInsertBatchSize tasks
+// to stay below the limit of parameters the databases allow for one statement.
+func insertBulkTasks(s *xorm.Session, project *Project, tasks []*Task, createdBy *user.User) (err error) {
+	nextIndex, err := getNextTaskIndex(s, project.ID)
+	if err != nil {
+		return err
+	}
+
+	for i, t := range tasks {
+		t.CreatedByID = createdBy.ID
+		t.CreatedBy = createdBy
+		t.Index = nextIndex + int64(i)
+		t.HexColor = utils.NormalizeHex(t.HexColor)
+		// The uid is always generated by the server. The new ids are looked up through it, a uid passed by the
+		// client could belong to another task of the request or to an existing one.
+		t.UID = uuid.NewString()
+	}
+
+	for start := 0; start < len(tasks); start += bulkInsertBatchSize {
+		end := start + bulkInsertBatchSize
+		if end > len(tasks) {
+			end = len(tasks)
+		}
+		batch := tasks[start:end]
+
+		_, err = s.Insert(&batch)
+		if err != nil {
+			return err
+		}
+
+		// Inserting many rows at once does not give us the ids of the new tasks, so we need to look them up.
+		uids := make([]string, 0, len(batch))
+		for _, t := range batch {
+			uids = append(uids, t.UID)
+		}
+		created := []*Task{}
+		err = s.
+			Where("project_id = ?", project.ID).
+			In("uid", uids).
+			Cols("id", "uid").
+			Find(&created)
+		if err != nil {
+			return err
+		}
+		idsByUID := make(map[string]int64, len(created))
+		for _, t := range created {
+			idsByUID[t.UID] = t.ID
+		}
+		for _, t := range batch {
+			t.ID = idsByUID[t.UID]
+		}
+	}
+
+	return nil
+}
+
+// insertPositionsAndBucketsForNewTasks puts new tasks into all views of their project, using one insert for all tasks.
+func insertPositionsAndBucketsForNewTasks(s *xorm.Session, projectID int64, tasks []*Task) (err error) {
+	views, err := getViewsForProject(s, projectID)
+	if err != nil {
+		return err
+	}
+
+	positions := []*TaskPosition{}
+	taskBuckets := []*TaskBucket{}
+
+	for _, view := range views {
+		var defaultBucketID int64
+		manualBuckets := view.ViewKind == ProjectViewKindKanban && view.BucketConfigurationMode == BucketConfigurationModeManual
+		if manualBuckets {
+			defaultBucketID, err = getDefaultBucketID(s, view)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, t := range tasks {
+			if manualBuckets {
+				bucketID := defaultBucketID
+				if t.Done && view.DoneBucketID != 0 {
+					bucketID = view.DoneBucketID
+				}
+				taskBuckets = append(taskBuckets, &TaskBucket{
+					BucketID:      bucketID,
+					TaskID:        t.ID,
+					ProjectViewID: view.ID,
+				})
+			}
+
+			positions = append(positions, &TaskPosition{
+				TaskID:        t.ID,
+				ProjectViewID: view.ID,
+				Position:      calculateDefaultPosition(t.Index, t.Position),
+			})
+		}
+	}
+
+	if len(positions) > 0 {
+		_, err = s.Insert(&positions)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(taskBuckets) > 0 {
+		_, err = s.Insert(&taskBuckets)
+	}
+	return
+}
+
+// bulkTaskChecker validates tasks of a bulk request before any of them is created.
+// It remembers labels and users it has already checked since most tasks of an import share them.
+type bulkTaskChecker struct {
+	project *Project
+	auth    web.Auth
+	labels  map[int64]*Label
+	users   map[int64]*user.User
+	// How many of the checked tasks are undone, they count against the open task quota of the project.
+	openTasks int64
+	// How many tasks were checked successfully, they count against the task quota of the user.
+	tasks             int64
+	approvalRequested map[*Task]bool
+}
+
+func (c *bulkTaskChecker) check(s *xorm.Session, t *Task) error {
+	t.ID = 0
+	t.Votes = 0
+	t.ApprovalStatus = TaskApprovalStatusNone
+
+	if t.Title == "" {
+		return ErrTaskCannotBeEmpty{}
+	}
+
+	c.project.TaskDefaults.applyBeforeCreate(t)
+
+	// Catch invalid reminders before the task is inserted, they would fail the whole request afterwards
+	for _, r := range t.Reminders {
+		if err := r.validateRepeat(0); err != nil {
+			return err
+		}
+	}
+	if err := updateRelativeReminderDates(t); err != nil {
+		return err
+	}
+
+	// Passing the same label or assignee twice for a task would otherwise fail when inserting it the second time
+	labels := make([]*Label, 0, len(t.Labels))
+	seenLabels := make(map[int64]bool, len(t.Labels))
+	for _, l := range t.Labels {
+		if !seenLabels[l.ID] {
+			seenLabels[l.ID] = true
+			labels = append(labels, l)
+		}
+	}
+	t.Labels = labels
+
+	assignees := make([]*user.User, 0, len(t.Assignees))
+	seenAssignees := make(map[int64]bool, len(t.Assignees))
+	for _, u := range t.Assignees {
+		if !seenAssignees[u.ID] {
+			seenAssignees[u.ID] = true
+			assignees = append(assignees, u)
+		}
+	}
+	t.Assignees = assignees
+
+	for _, l := range t.Labels {
+		if _, checked := c.labels[l.ID]; checked {
+			continue
+		}
+
+		label, err := getLabelByIDSimple(s, l.ID)
+		if err != nil {
+			return err
+		}
+		has, _, err := label.hasAccessToLabel(s, c.auth)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ErrUserHasNoAccessToLabel{LabelID: l.ID, UserID: c.auth.GetID()}
+		}
+		c.labels[l.ID] = label
+	}
+
+	labelIDs := make([]int64, 0, len(t.Labels))
+	for _, l := range t.Labels {
+		labelIDs = append(labelIDs, l.ID)
+	}
+	if err := checkExclusiveLabelGroups(s, labelIDs); err != nil {
+		return err
+	}
+
+	for _, assignee := range t.Assignees {
+		if _, checked := c.users[assignee.ID]; checked {
+			continue
+		}
+
+		u, err := user.GetUserByID(s, assignee.ID)
+		if err != nil {
+			return err
+		}
+		canRead, _, err := c.project.CanRead(s, u)
+		if err != nil {
+			return err
+		}
+		if !canRead {
+			return ErrUserDoesNotHaveAccessToProject{c.project.ID, assignee.ID}
+		}
+		c.users[assignee.ID] = u
+	}
+
+	// Tasks created as done in projects with approvers are only done once one of them approved it
+	requested, err := t.updateApprovalStatus(s, &Task{ProjectID: t.ProjectID}, c.auth)
+	if err != nil {
+		return err
+	}
+
+	// The quotas are checked last so that tasks which fail for another reason don't count against them
+	openTasks := c.openTasks
+	if !t.Done {
+		openTasks++
+		err = c.project.checkOpenTaskQuotaFor(s, openTasks)
+		if err != nil {
+			return err
+		}
+	}
+	err = checkUserTaskQuotaFor(s, c.auth, c.tasks+1)
+	if err != nil {
+		return err
+	}
+
+	c.openTasks = openTasks
+	c.tasks++
+	c.approvalRequested[t] = requested
+	return nil
+}
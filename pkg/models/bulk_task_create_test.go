@@ -0,0 +1,234 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strconv"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkTaskCreate_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		btc := &BulkTaskCreate{
+			ProjectID: 1,
+			Tasks: []*Task{
+				{
+					Title:     "bulk one",
+					Labels:    []*Label{{ID: 1}, {ID: 1}},
+					Assignees: []*user.User{{ID: 1}},
+				},
+				{
+					Title: "",
+				},
+				{
+					Title:  "bulk three",
+					Labels: []*Label{{ID: 3}},
+				},
+				{
+					Title: "bulk four",
+					Done:  true,
+				},
+			},
+		}
+		can, err := btc.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = btc.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		require.Len(t, btc.Results, 4)
+		require.NotNil(t, btc.Results[0].Task)
+		assert.Nil(t, btc.Results[0].Error)
+		assert.NotZero(t, btc.Results[0].Task.ID)
+		require.NotNil(t, btc.Results[1].Error)
+		assert.Equal(t, ErrCodeTaskCannotBeEmpty, btc.Results[1].Error.Code)
+		require.NotNil(t, btc.Results[2].Error)
+		assert.Equal(t, ErrCodeUserHasNoAccessToLabel, btc.Results[2].Error.Code)
+		require.NotNil(t, btc.Results[3].Task)
+		assert.Equal(t, btc.Results[0].Task.Index+1, btc.Results[3].Task.Index)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":         btc.Results[0].Task.ID,
+			"title":      "bulk one",
+			"project_id": 1,
+		}, false)
+		db.AssertExists(t, "label_tasks", map[string]interface{}{
+			"task_id":  btc.Results[0].Task.ID,
+			"label_id": 1,
+		}, false)
+		db.AssertExists(t, "task_assignees", map[string]interface{}{
+			"task_id": btc.Results[0].Task.ID,
+			"user_id": 1,
+		}, false)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":    btc.Results[3].Task.ID,
+			"title": "bulk four",
+			"done":  true,
+		}, false)
+		db.AssertMissing(t, "tasks", map[string]interface{}{
+			"title": "bulk three",
+		})
+	})
+	t.Run("uids are generated by the server", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		btc := &BulkTaskCreate{
+			ProjectID: 1,
+			Tasks: []*Task{
+				{Title: "bulk one", UID: "same"},
+				{Title: "bulk two", UID: "same"},
+			},
+		}
+		err := btc.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		require.Len(t, btc.Results, 2)
+		require.NotNil(t, btc.Results[0].Task)
+		require.NotNil(t, btc.Results[1].Task)
+		assert.NotEqual(t, btc.Results[0].Task.ID, btc.Results[1].Task.ID)
+		assert.NotEqual(t, "same", btc.Results[0].Task.UID)
+		assert.NotEqual(t, btc.Results[0].Task.UID, btc.Results[1].Task.UID)
+		db.AssertMissing(t, "tasks", map[string]interface{}{
+			"uid": "same",
+		})
+	})
+	t.Run("invalid reminder", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		btc := &BulkTaskCreate{
+			ProjectID: 1,
+			Tasks: []*Task{
+				{
+					Title:     "bulk one",
+					Reminders: []*TaskReminder{{RelativePeriod: -3600}},
+				},
+			},
+		}
+		err := btc.Create(s, u)
+		require.NoError(t, err)
+
+		require.Len(t, btc.Results, 1)
+		require.NotNil(t, btc.Results[0].Error)
+		assert.Equal(t, ErrCodeReminderRelativeToMissing, btc.Results[0].Error.Code)
+		db.AssertMissing(t, "tasks", map[string]interface{}{
+			"title": "bulk one",
+		})
+	})
+	t.Run("more tasks than one insert takes", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tasks := make([]*Task, bulkInsertBatchSize+10)
+		for i := range tasks {
+			tasks[i] = &Task{Title: "bulk " + strconv.Itoa(i)}
+		}
+		btc := &BulkTaskCreate{ProjectID: 1, Tasks: tasks}
+		err := btc.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		ids := make(map[int64]bool, len(tasks))
+		for i, result := range btc.Results {
+			require.NotNil(t, result.Task)
+			assert.NotZero(t, result.Task.ID)
+			ids[result.Task.ID] = true
+			db.AssertExists(t, "tasks", map[string]interface{}{
+				"id":    result.Task.ID,
+				"title": "bulk " + strconv.Itoa(i),
+			}, false)
+		}
+		assert.Len(t, ids, len(tasks))
+	})
+	t.Run("quota counts the tasks of the request", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 1 created 24 tasks
+		config.QuotasMaxTasksPerUser.Set(25)
+		defer config.QuotasMaxTasksPerUser.Set(0)
+
+		btc := &BulkTaskCreate{
+			ProjectID: 1,
+			Tasks: []*Task{
+				{Title: "bulk one"},
+				{Title: "bulk two"},
+			},
+		}
+		err := btc.Create(s, u)
+		require.NoError(t, err)
+
+		require.Len(t, btc.Results, 2)
+		require.NotNil(t, btc.Results[0].Task)
+		require.NotNil(t, btc.Results[1].Error)
+		assert.Equal(t, ErrCodeUserTaskQuotaExceeded, btc.Results[1].Error.Code)
+	})
+	t.Run("no tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&BulkTaskCreate{ProjectID: 1}).Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrBulkTasksNeedAtLeastOne(err))
+	})
+	t.Run("too many tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tasks := make([]*Task, maxBulkCreateTasks+1)
+		for i := range tasks {
+			tasks[i] = &Task{Title: "task"}
+		}
+		err := (&BulkTaskCreate{ProjectID: 1, Tasks: tasks}).Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTooManyBulkTasks(err))
+	})
+	t.Run("no write access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		can, err := (&BulkTaskCreate{ProjectID: 1}).CanCreate(s, &user.User{ID: 2})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
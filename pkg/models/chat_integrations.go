@@ -0,0 +1,333 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/user"
+
+	"code.vikunja.io/web"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+const (
+	ChatIntegrationTypeSlack      = "slack"
+	ChatIntegrationTypeMattermost = "mattermost"
+	ChatIntegrationTypeMatrix     = "matrix"
+)
+
+// ChatIntegration posts messages to a chat service. Integrations of a user receive the user's notifications,
+// integrations of a project receive what happens with the tasks in the project.
+type ChatIntegration struct {
+	// The unique, numeric id of this chat integration.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"chatintegration"`
+	// The chat service, either `slack`, `mattermost` or `matrix`.
+	Type string `xorm:"varchar(50) not null" json:"type" valid:"required"`
+	// The url of the incoming webhook the messages are posted to. Only used for Slack and Mattermost.
+	WebhookURL string `xorm:"text not null" json:"webhook_url" valid:"url"`
+	// The id of the matrix room the messages are sent to, for example `!abc:matrix.org`. Only used for Matrix.
+	RoomID string `xorm:"varchar(255) null" json:"room_id"`
+	// The access token of the matrix user who sends the messages. The user must have joined the room. Only used for Matrix.
+	AccessToken string `xorm:"text null" json:"access_token"`
+	// The project this integration belongs to. 0 for integrations of a user.
+	ProjectID int64 `xorm:"bigint not null default 0 INDEX" json:"project_id" param:"project"`
+	UserID    int64 `xorm:"bigint not null default 0 INDEX" json:"-"`
+
+	// A timestamp when this integration was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for chat integrations
+func (*ChatIntegration) TableName() string {
+	return "chat_integrations"
+}
+
+// Create adds a chat integration to the current user or a project
+// @Summary Add a chat integration
+// @Description Posts messages to a Slack or Mattermost incoming webhook or a Matrix room. Integrations of a project receive what happens with its tasks, integrations created through the user settings receive the notifications of the user.
+// @tags chat
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project ID"
+// @Param integration body models.ChatIntegration true "The chat integration"
+// @Success 201 {object} models.ChatIntegration "The created chat integration."
+// @Failure 400 {object} web.HTTPError "Invalid chat integration provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/chat-integrations [put]
+// @Router /user/settings/chat-integrations [put]
+func (ci *ChatIntegration) Create(s *xorm.Session, a web.Auth) (err error) {
+	switch ci.Type {
+	case ChatIntegrationTypeSlack, ChatIntegrationTypeMattermost:
+		if !strings.HasPrefix(ci.WebhookURL, "http") {
+			return InvalidFieldError([]string{"webhook_url"})
+		}
+		ci.RoomID = ""
+		ci.AccessToken = ""
+	case ChatIntegrationTypeMatrix:
+		if config.MatrixHomeserver.GetString() == "" {
+			return ErrInvalidChatIntegrationType{Type: ci.Type}
+		}
+		if ci.RoomID == "" || ci.AccessToken == "" {
+			return InvalidFieldError([]string{"room_id", "access_token"})
+		}
+		ci.WebhookURL = ""
+	default:
+		return ErrInvalidChatIntegrationType{Type: ci.Type}
+	}
+
+	ci.ID = 0
+	ci.UserID = 0
+	if ci.ProjectID == 0 {
+		ci.UserID = a.GetID()
+	}
+
+	_, err = s.Insert(ci)
+	return
+}
+
+// ReadAll returns all chat integrations of the current user or a project
+// @Summary Get all chat integrations
+// @Description Returns all chat integrations of a project or, through the user settings, of the current user.
+// @tags chat
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project ID"
+// @Success 200 {array} models.ChatIntegration "The chat integrations"
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/chat-integrations [get]
+// @Router /user/settings/chat-integrations [get]
+func (ci *ChatIntegration) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	// The webhook urls allow anyone to post into the chat, only those who can change them see them
+	can, err := ci.canDoChatIntegration(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	cond := builder.Eq{"project_id": ci.ProjectID}
+	if ci.ProjectID == 0 {
+		cond["user_id"] = a.GetID()
+	}
+
+	integrations := []*ChatIntegration{}
+	err = s.
+		Where(cond).
+		Limit(getLimitFromPageIndex(page, perPage)).
+		OrderBy("id asc").
+		Find(&integrations)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	total, err := s.Where(cond).Count(&ChatIntegration{})
+	return integrations, len(integrations), total, err
+}
+
+// Delete removes a chat integration
+// @Summary Delete a chat integration
+// @tags chat
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project ID"
+// @Param integrationID path int true "Chat integration ID"
+// @Success 200 {object} models.Message "Successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The chat integration does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/chat-integrations/{integrationID} [delete]
+// @Router /user/settings/chat-integrations/{integrationID} [delete]
+func (ci *ChatIntegration) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.Where("id = ?", ci.ID).Delete(&ChatIntegration{})
+	return
+}
+
+func (ci *ChatIntegration) send(msg *notifications.ChatMessage) error {
+	switch ci.Type {
+	case ChatIntegrationTypeSlack:
+		return notifications.PostChatWebhook(getWebhookHTTPClient(), ci.WebhookURL, msg.Slack())
+	case ChatIntegrationTypeMatrix:
+		return notifications.SendMatrixMessage(getWebhookHTTPClient(), config.MatrixHomeserver.GetString(), ci.AccessToken, ci.RoomID, msg)
+	default:
+		return notifications.PostChatWebhook(getWebhookHTTPClient(), ci.WebhookURL, msg.Markdown())
+	}
+}
+
+// chatIntegrationTransport sends the notifications of a user to the chat integrations of the user
+type chatIntegrationTransport struct{}
+
+func (*chatIntegrationTransport) Channel() notifications.Channel {
+	return notifications.ChannelWebhook
+}
+
+func (*chatIntegrationTransport) Handles(_ notifications.Notification) bool {
+	return true
+}
+
+func (*chatIntegrationTransport) Send(notifiable notifications.Notifiable, notification notifications.Notification) (err error) {
+	s := db.NewSession()
+	defer s.Close()
+
+	integrations := []*ChatIntegration{}
+	err = s.
+		Where("user_id = ? AND project_id = 0", notifiable.RouteForDB()).
+		Find(&integrations)
+	if err != nil || len(integrations) == 0 {
+		return err
+	}
+
+	msg := notifications.NewChatMessage(notification, notifiable.Lang())
+	if msg == nil {
+		return nil
+	}
+
+	for _, integration := range integrations {
+		err = integration.send(msg)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterChatIntegrations sends notifications and task events to chat integrations
+func RegisterChatIntegrations() {
+	notifications.RegisterTransport(&chatIntegrationTransport{})
+
+	for _, event := range []events.Event{
+		&TaskCreatedEvent{},
+		&TaskAssigneeCreatedEvent{},
+		&TaskCommentCreatedEvent{},
+		&TaskMarkedDoneEvent{},
+	} {
+		events.RegisterListener(event.Name(), &ChatIntegrationListener{EventName: event.Name()})
+	}
+}
+
+// ChatIntegrationListener posts task events to the chat integrations of the task's project and its parents
+type ChatIntegrationListener struct {
+	EventName string
+}
+
+// Name defines the name for the ChatIntegrationListener listener
+func (l *ChatIntegrationListener) Name() string {
+	return "chat.integration.listener"
+}
+
+func chatMessageForTaskEvent(task *Task, doer *user.User, line string) *notifications.ChatMessage {
+	actor := "Someone"
+	if doer != nil {
+		actor = doer.GetName()
+	}
+	mail := notifications.NewMail().
+		Subject(task.Title + " (" + task.GetFullIdentifier() + ")").
+		Line("**" + actor + "** " + line)
+	return notifications.NewChatMessageFromMail(mail.Action("View Task", task.GetFrontendURL()))
+}
+
+func (l *ChatIntegrationListener) chatMessage(payload []byte) (task *Task, msg *notifications.ChatMessage, err error) {
+	switch l.EventName {
+	case (&TaskCreatedEvent{}).Name():
+		event := &TaskCreatedEvent{}
+		err = json.Unmarshal(payload, event)
+		if err != nil {
+			return
+		}
+		return event.Task, chatMessageForTaskEvent(event.Task, event.Doer, "created this task."), nil
+	case (&TaskAssigneeCreatedEvent{}).Name():
+		event := &TaskAssigneeCreatedEvent{}
+		err = json.Unmarshal(payload, event)
+		if err != nil {
+			return
+		}
+		return event.Task, chatMessageForTaskEvent(event.Task, event.Doer, "assigned **"+event.Assignee.GetName()+"** to this task."), nil
+	case (&TaskCommentCreatedEvent{}).Name():
+		event := &TaskCommentCreatedEvent{}
+		err = json.Unmarshal(payload, event)
+		if err != nil {
+			return
+		}
+		msg = chatMessageForTaskEvent(event.Task, event.Doer, "commented:")
+		comment := notifications.NewChatMessageFromMail(notifications.NewMail().HTML(event.Comment.Comment))
+		msg.Lines = append(msg.Lines, comment.Lines...)
+		return event.Task, msg, nil
+	case (&TaskMarkedDoneEvent{}).Name():
+		event := &TaskMarkedDoneEvent{}
+		err = json.Unmarshal(payload, event)
+		if err != nil {
+			return
+		}
+		return event.Task, chatMessageForTaskEvent(event.Task, event.Doer, "marked this task as done."), nil
+	}
+	return nil, nil, nil
+}
+
+// Handle is executed when the event ChatIntegrationListener listens on is fired
+func (l *ChatIntegrationListener) Handle(msg *message.Message) (err error) {
+	task, chatMessage, err := l.chatMessage(msg.Payload)
+	if err != nil || task == nil {
+		return err
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	parents, err := GetAllParentProjects(s, task.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	projectIDs := []int64{task.ProjectID}
+	for _, p := range parents {
+		if p.IsArchived {
+			return nil
+		}
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	integrations := []*ChatIntegration{}
+	err = s.In("project_id", projectIDs).Find(&integrations)
+	if err != nil {
+		return err
+	}
+
+	for _, integration := range integrations {
+		// A broken integration should not keep the others from getting the message
+		err = integration.send(chatMessage)
+		if err != nil {
+			log.Errorf("Could not post event %s to chat integration %d: %s", l.EventName, integration.ID, err)
+		}
+	}
+	return nil
+}
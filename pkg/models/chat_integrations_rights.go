@@ -0,0 +1,58 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanCreate checks if the user can add a chat integration to the project or themselves
+func (ci *ChatIntegration) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	return ci.canDoChatIntegration(s, a)
+}
+
+// CanDelete checks if the user can remove a chat integration
+func (ci *ChatIntegration) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	existing := &ChatIntegration{}
+	exists, err := s.Where("id = ?", ci.ID).Get(existing)
+	if err != nil {
+		return false, err
+	}
+	// The integration must belong to the project in the route, or to the user for integrations of a user
+	if !exists || existing.ProjectID != ci.ProjectID {
+		return false, ErrChatIntegrationDoesNotExist{ID: ci.ID}
+	}
+	if existing.ProjectID == 0 && existing.UserID != a.GetID() {
+		return false, ErrChatIntegrationDoesNotExist{ID: ci.ID}
+	}
+
+	return existing.canDoChatIntegration(s, a)
+}
+
+func (ci *ChatIntegration) canDoChatIntegration(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, isShareAuth := a.(*LinkSharing); isShareAuth {
+		return false, nil
+	}
+
+	if ci.ProjectID == 0 {
+		return true, nil
+	}
+
+	p := &Project{ID: ci.ProjectID}
+	return p.CanUpdate(s, a)
+}
@@ -0,0 +1,178 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatIntegration_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("user integration", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ci := &ChatIntegration{Type: ChatIntegrationTypeMattermost, WebhookURL: "https://mattermost.example.com/hooks/new"}
+		err := ci.Create(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		db.AssertExists(t, "chat_integrations", map[string]interface{}{
+			"id":         ci.ID,
+			"project_id": 0,
+			"user_id":    1,
+		}, false)
+	})
+	t.Run("invalid type", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ci := &ChatIntegration{Type: "irc", WebhookURL: "https://example.com"}
+		err := ci.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidChatIntegrationType(err))
+	})
+	t.Run("matrix", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ci := &ChatIntegration{Type: ChatIntegrationTypeMatrix, RoomID: "!room:example.com", AccessToken: "token"}
+		err := ci.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidChatIntegrationType(err))
+
+		config.MatrixHomeserver.Set("https://matrix.example.com")
+		defer config.MatrixHomeserver.Set("")
+
+		ci = &ChatIntegration{Type: ChatIntegrationTypeMatrix, RoomID: "!room:example.com"}
+		err = ci.Create(s, u)
+		require.Error(t, err)
+
+		ci = &ChatIntegration{Type: ChatIntegrationTypeMatrix, RoomID: "!room:example.com", AccessToken: "token", WebhookURL: "https://example.com"}
+		err = ci.Create(s, u)
+		require.NoError(t, err)
+		assert.Empty(t, ci.WebhookURL)
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ci := &ChatIntegration{ProjectID: 1, Type: ChatIntegrationTypeSlack, WebhookURL: "https://example.com"}
+		can, err := ci.CanCreate(s, &LinkSharing{ID: 1, ProjectID: 1})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestChatIntegration_ReadAll(t *testing.T) {
+	t.Run("user integrations", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ci := &ChatIntegration{}
+		result, _, _, err := ci.ReadAll(s, &user.User{ID: 1}, "", 1, 50)
+		require.NoError(t, err)
+		integrations := result.([]*ChatIntegration)
+		require.Len(t, integrations, 1)
+		assert.Equal(t, int64(1), integrations[0].ID)
+	})
+	t.Run("project integrations", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ci := &ChatIntegration{ProjectID: 3}
+		result, _, _, err := ci.ReadAll(s, &user.User{ID: 3}, "", 1, 50)
+		require.NoError(t, err)
+		integrations := result.([]*ChatIntegration)
+		require.Len(t, integrations, 1)
+		assert.Equal(t, int64(2), integrations[0].ID)
+	})
+}
+
+func TestChatIntegration_CanDelete(t *testing.T) {
+	t.Run("other project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ci := &ChatIntegration{ID: 2, ProjectID: 0}
+		_, err := ci.CanDelete(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrChatIntegrationDoesNotExist(err))
+	})
+	t.Run("other user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ci := &ChatIntegration{ID: 1, ProjectID: 0}
+		_, err := ci.CanDelete(s, &user.User{ID: 2})
+		require.Error(t, err)
+		assert.True(t, IsErrChatIntegrationDoesNotExist(err))
+	})
+}
+
+func TestChatIntegrationListener(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+	}))
+	t.Cleanup(server.Close)
+
+	s := db.NewSession()
+	_, err := s.Insert(&ChatIntegration{Type: ChatIntegrationTypeSlack, WebhookURL: server.URL, ProjectID: 1})
+	require.NoError(t, err)
+	require.NoError(t, s.Commit())
+	s.Close()
+
+	event := &TaskCreatedEvent{
+		Task: &Task{ID: 1, ProjectID: 1, Title: "task #1", Index: 1},
+		Doer: &user.User{ID: 1, Username: "user1"},
+	}
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	listener := &ChatIntegrationListener{EventName: event.Name()}
+	err = listener.Handle(message.NewMessage("1", payload))
+	require.NoError(t, err)
+
+	assert.Contains(t, received["text"], "*task #1 (#1)*")
+	assert.Contains(t, received["text"], "*user1* created this task.")
+	assert.Contains(t, received["text"], "tasks/1|View Task>")
+}
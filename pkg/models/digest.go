@@ -0,0 +1,323 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"sort"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/api/pkg/utils"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// The maximum number of activities in a digest, older activities are left out
+const digestActivityLimit = 50
+
+type digestPeriod struct {
+	digest *DigestNotification
+	// The start of the day the digest is sent on, in the time zone of the user. Tasks due before are overdue.
+	start time.Time
+	// Tasks due before this are due in the period of the digest
+	end time.Time
+	// Activities after this are included in the digest
+	since time.Time
+}
+
+// getDueDigestPeriods returns the digests of all users who want to get theirs in the minute starting at now
+func getDueDigestPeriods(s *xorm.Session, now time.Time) (periods map[int64]*digestPeriod, err error) {
+	users := []*user.User{}
+	err = s.
+		Where("digest_frequency != ? AND status = ?", user.DigestFrequencyNone, user.StatusActive).
+		Find(&users)
+	if err != nil {
+		return
+	}
+
+	periods = make(map[int64]*digestPeriod)
+	tzs := make(map[string]*time.Location)
+	for _, u := range users {
+		if u.Timezone == "" {
+			u.Timezone = config.GetTimeZone().String()
+		}
+
+		tz, exists := tzs[u.Timezone]
+		if !exists {
+			tz, err = time.LoadLocation(u.Timezone)
+			if err != nil {
+				return nil, err
+			}
+			tzs[u.Timezone] = tz
+		}
+
+		tm, err := time.Parse("15:04", u.DigestTime)
+		if err != nil {
+			return nil, err
+		}
+
+		local := now.In(tz)
+		digestTime := time.Date(local.Year(), local.Month(), local.Day(), tm.Hour(), tm.Minute(), 0, 0, tz)
+		if !digestTime.Equal(now) {
+			continue
+		}
+
+		// Weekly digests are sent on the first day of the user's week
+		days := 1
+		if u.DigestFrequency == user.DigestFrequencyWeekly {
+			if int(local.Weekday()) != u.WeekStart%7 {
+				continue
+			}
+			days = 7
+		}
+
+		start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, tz)
+		since := now.AddDate(0, 0, -days)
+		if u.DigestLastSent.After(since) {
+			since = u.DigestLastSent
+		}
+
+		periods[u.ID] = &digestPeriod{
+			digest: &DigestNotification{
+				User:      u,
+				Frequency: u.DigestFrequency,
+				Location:  tz,
+			},
+			start: start,
+			end:   start.AddDate(0, 0, days),
+			since: since,
+		}
+	}
+
+	return periods, nil
+}
+
+func addTasksToDigests(s *xorm.Session, periods map[int64]*digestPeriod, userIDs []int64) error {
+	var end time.Time
+	for _, p := range periods {
+		if p.end.After(end) {
+			end = p.end
+		}
+	}
+
+	var tasks []*Task
+	err := s.
+		Where("due_date is not null AND due_date < ? AND projects.is_archived = false", end.In(config.GetTimeZone()).Format(dbTimeFormat)).
+		Join("LEFT", "projects", "projects.id = tasks.project_id").
+		And("done = false").
+		Find(&tasks)
+	if err != nil || len(tasks) == 0 {
+		return err
+	}
+
+	taskIDs := make([]int64, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+
+	taskUsers, err := getTaskUsersForTasks(s, taskIDs, builder.In("users.id", userIDs))
+	if err != nil {
+		return err
+	}
+
+	// A user can be creator and assignee of the same task
+	seen := make(map[int64]map[int64]bool)
+	for _, tu := range taskUsers {
+		p, has := periods[tu.User.ID]
+		if !has {
+			continue
+		}
+		if seen[tu.User.ID] == nil {
+			seen[tu.User.ID] = make(map[int64]bool)
+		}
+		if seen[tu.User.ID][tu.Task.ID] {
+			continue
+		}
+		seen[tu.User.ID][tu.Task.ID] = true
+
+		switch {
+		case tu.Task.DueDate.Before(p.start):
+			p.digest.OverdueTasks = append(p.digest.OverdueTasks, tu.Task)
+		case tu.Task.DueDate.Before(p.end):
+			p.digest.DueTasks = append(p.digest.DueTasks, tu.Task)
+		}
+	}
+
+	for _, p := range periods {
+		sort.Slice(p.digest.DueTasks, func(i, j int) bool {
+			return p.digest.DueTasks[i].DueDate.Before(p.digest.DueTasks[j].DueDate)
+		})
+		sort.Slice(p.digest.OverdueTasks, func(i, j int) bool {
+			return p.digest.OverdueTasks[i].DueDate.Before(p.digest.OverdueTasks[j].DueDate)
+		})
+	}
+
+	return nil
+}
+
+func addActivitiesToDigests(s *xorm.Session, periods map[int64]*digestPeriod, userIDs []int64) error {
+	subscriptions := []*Subscription{}
+	err := s.
+		Where("entity_type = ?", SubscriptionEntityProject).
+		In("user_id", userIDs).
+		Find(&subscriptions)
+	if err != nil {
+		return err
+	}
+
+	projectIDs := make(map[int64][]int64)
+	for _, sub := range subscriptions {
+		// The user might have lost access to the project since they subscribed
+		can, _, err := (&Project{ID: sub.EntityID}).CanRead(s, periods[sub.UserID].digest.User)
+		if err != nil && !IsErrProjectDoesNotExist(err) {
+			return err
+		}
+		if can {
+			projectIDs[sub.UserID] = append(projectIDs[sub.UserID], sub.EntityID)
+		}
+	}
+
+	actorIDs := []int64{}
+	for userID, ids := range projectIDs {
+		p := periods[userID]
+		activities := []*ProjectActivity{}
+		err = s.
+			In("project_id", ids).
+			And("created > ?", p.since.In(config.GetTimeZone()).Format(dbTimeFormat)).
+			And("actor_id != ?", userID).
+			OrderBy("created desc, id desc").
+			Limit(digestActivityLimit).
+			Find(&activities)
+		if err != nil {
+			return err
+		}
+
+		p.digest.Activities = activities
+		for _, activity := range activities {
+			actorIDs = append(actorIDs, activity.ActorID)
+		}
+	}
+
+	actors, err := getUsersOrLinkSharesFromIDs(s, actorIDs)
+	if err != nil {
+		return err
+	}
+	for _, p := range periods {
+		for _, activity := range p.digest.Activities {
+			activity.Actor = actors[activity.ActorID]
+		}
+	}
+
+	return nil
+}
+
+// getDueDigests returns the digest notifications to send in the minute starting at now. Digests without any
+// tasks or activities are left out.
+func getDueDigests(s *xorm.Session, now time.Time) (digests []*DigestNotification, err error) {
+	now = utils.GetTimeWithoutSeconds(now)
+
+	periods, err := getDueDigestPeriods(s, now)
+	if err != nil || len(periods) == 0 {
+		return nil, err
+	}
+
+	userIDs := make([]int64, 0, len(periods))
+	for id := range periods {
+		userIDs = append(userIDs, id)
+	}
+
+	err = addTasksToDigests(s, periods, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addActivitiesToDigests(s, periods, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	projectIDs := []int64{}
+	for _, p := range periods {
+		for _, task := range append(p.digest.DueTasks, p.digest.OverdueTasks...) {
+			projectIDs = append(projectIDs, task.ProjectID)
+		}
+		for _, activity := range p.digest.Activities {
+			projectIDs = append(projectIDs, activity.ProjectID)
+		}
+	}
+	projects, err := GetProjectsMapByIDs(s, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range periods {
+		if len(p.digest.DueTasks) == 0 && len(p.digest.OverdueTasks) == 0 && len(p.digest.Activities) == 0 {
+			continue
+		}
+		p.digest.Projects = projects
+		digests = append(digests, p.digest)
+	}
+
+	return digests, nil
+}
+
+// RegisterDigestCron registers a function which sends the daily and weekly digests of all users who opted in
+func RegisterDigestCron() {
+	if !config.MailerEnabled.GetBool() {
+		log.Info("Mailer is disabled, not sending digests per mail")
+		return
+	}
+
+	err := cron.Schedule("* * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		now := time.Now()
+		digests, err := getDueDigests(s, now)
+		if err != nil {
+			log.Errorf("[Digest] Could not get digests: %s", err)
+			return
+		}
+
+		log.Debugf("[Digest] Sending digests to %d users", len(digests))
+
+		for _, digest := range digests {
+			err = notifications.Notify(digest.User, digest)
+			if err != nil {
+				log.Errorf("[Digest] Could not notify user %d: %s", digest.User.ID, err)
+				continue
+			}
+
+			digest.User.DigestLastSent = now
+			_, err = s.Where("id = ?", digest.User.ID).
+				Cols("digest_last_sent").
+				Update(digest.User)
+			if err != nil {
+				log.Errorf("[Digest] Could not update the last digest date of user %d: %s", digest.User.ID, err)
+			}
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register digest cron: %s", err)
+	}
+}
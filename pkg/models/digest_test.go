@@ -0,0 +1,105 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDueDigests(t *testing.T) {
+	setDigest := func(t *testing.T, frequency, digestTime string) {
+		s := db.NewSession()
+		defer s.Close()
+		_, err := s.
+			Where("id = ?", 1).
+			Cols("digest_frequency", "digest_time", "timezone").
+			Update(&user.User{DigestFrequency: frequency, DigestTime: digestTime, Timezone: "UTC"})
+		require.NoError(t, err)
+	}
+
+	t.Run("no digests enabled", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		now, err := time.Parse(time.RFC3339Nano, "2018-12-01T08:00:00Z")
+		require.NoError(t, err)
+		digests, err := getDueDigests(s, now)
+		require.NoError(t, err)
+		assert.Empty(t, digests)
+	})
+	t.Run("daily digest", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		setDigest(t, user.DigestFrequencyDaily, "08:00")
+		s := db.NewSession()
+		defer s.Close()
+
+		now, err := time.Parse(time.RFC3339Nano, "2018-12-01T08:00:00Z")
+		require.NoError(t, err)
+		digests, err := getDueDigests(s, now)
+		require.NoError(t, err)
+		require.Len(t, digests, 1)
+		assert.Equal(t, int64(1), digests[0].User.ID)
+
+		var task5Due bool
+		for _, task := range digests[0].DueTasks {
+			if task.ID == 5 {
+				task5Due = true
+			}
+		}
+		var task6Overdue bool
+		for _, task := range digests[0].OverdueTasks {
+			if task.ID == 6 {
+				task6Overdue = true
+			}
+		}
+		assert.Truef(t, task5Due, "expected task 5 to be due but was not")
+		assert.Truef(t, task6Overdue, "expected task 6 to be overdue but was not")
+	})
+	t.Run("not the digest time of the user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		setDigest(t, user.DigestFrequencyDaily, "09:30")
+		s := db.NewSession()
+		defer s.Close()
+
+		now, err := time.Parse(time.RFC3339Nano, "2018-12-01T08:00:00Z")
+		require.NoError(t, err)
+		digests, err := getDueDigests(s, now)
+		require.NoError(t, err)
+		assert.Empty(t, digests)
+	})
+	t.Run("weekly digest on another day", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		setDigest(t, user.DigestFrequencyWeekly, "08:00")
+		s := db.NewSession()
+		defer s.Close()
+
+		// 2018-12-01 is a saturday, user 1 starts their week on sunday
+		now, err := time.Parse(time.RFC3339Nano, "2018-12-01T08:00:00Z")
+		require.NoError(t, err)
+		digests, err := getDueDigests(s, now)
+		require.NoError(t, err)
+		assert.Empty(t, digests)
+	})
+}
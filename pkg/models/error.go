@@ -267,6 +267,113 @@ func (err ErrProjectIsArchived) HTTPError() web.HTTPError {
 	return web.HTTPError{HTTPCode: http.StatusPreconditionFailed, Code: ErrCodeProjectIsArchived, Message: "This project is archived. Editing or creating new tasks is not possible."}
 }
 
+// ErrProjectIsFrozen represents an error, where a project is frozen
+type ErrProjectIsFrozen struct {
+	ProjectID int64
+}
+
+// IsErrProjectIsFrozen checks if an error is a project is frozen error.
+func IsErrProjectIsFrozen(err error) bool {
+	_, ok := err.(ErrProjectIsFrozen)
+	return ok
+}
+
+func (err ErrProjectIsFrozen) Error() string {
+	return fmt.Sprintf("Project is frozen [ProjectID: %d]", err.ProjectID)
+}
+
+// ErrCodeProjectIsFrozen holds the unique world-error code of this error
+const ErrCodeProjectIsFrozen = 3022
+
+// HTTPError holds the http error description
+func (err ErrProjectIsFrozen) HTTPError() web.HTTPError {
+	return web.HTTPError{HTTPCode: http.StatusPreconditionFailed, Code: ErrCodeProjectIsFrozen, Message: "This project is frozen. Tasks can only be read and commented on."}
+}
+
+// ErrProjectOpenTaskQuotaExceeded represents an error where a project already has as many undone tasks as it may have
+type ErrProjectOpenTaskQuotaExceeded struct {
+	ProjectID int64
+	Limit     int64
+}
+
+// IsErrProjectOpenTaskQuotaExceeded checks if an error is ErrProjectOpenTaskQuotaExceeded.
+func IsErrProjectOpenTaskQuotaExceeded(err error) bool {
+	_, ok := err.(*ErrProjectOpenTaskQuotaExceeded)
+	return ok
+}
+
+func (err *ErrProjectOpenTaskQuotaExceeded) Error() string {
+	return fmt.Sprintf("Project open task quota exceeded [ProjectID: %d, Limit: %d]", err.ProjectID, err.Limit)
+}
+
+// ErrCodeProjectOpenTaskQuotaExceeded holds the unique world-error code of this error
+const ErrCodeProjectOpenTaskQuotaExceeded = 3023
+
+// HTTPError holds the http error description
+func (err *ErrProjectOpenTaskQuotaExceeded) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeProjectOpenTaskQuotaExceeded,
+		Message:  fmt.Sprintf("This project has reached its limit of %d undone tasks. Mark some tasks as done or ask a project admin to raise the limit.", err.Limit),
+	}
+}
+
+// ErrProjectAttachmentQuotaExceeded represents an error where a new attachment would exceed the attachment size limit of a project
+type ErrProjectAttachmentQuotaExceeded struct {
+	ProjectID int64
+	Limit     int64
+	Used      int64
+}
+
+// IsErrProjectAttachmentQuotaExceeded checks if an error is ErrProjectAttachmentQuotaExceeded.
+func IsErrProjectAttachmentQuotaExceeded(err error) bool {
+	_, ok := err.(*ErrProjectAttachmentQuotaExceeded)
+	return ok
+}
+
+func (err *ErrProjectAttachmentQuotaExceeded) Error() string {
+	return fmt.Sprintf("Project attachment quota exceeded [ProjectID: %d, Limit: %d, Used: %d]", err.ProjectID, err.Limit, err.Used)
+}
+
+// ErrCodeProjectAttachmentQuotaExceeded holds the unique world-error code of this error
+const ErrCodeProjectAttachmentQuotaExceeded = 3024
+
+// HTTPError holds the http error description
+func (err *ErrProjectAttachmentQuotaExceeded) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeProjectAttachmentQuotaExceeded,
+		Message:  fmt.Sprintf("The attachments of this project would exceed its limit of %d bytes, %d bytes are already used.", err.Limit, err.Used),
+	}
+}
+
+// ErrInvalidProjectIcon represents an error where an uploaded project icon is not an image which can be read
+type ErrInvalidProjectIcon struct {
+	ProjectID int64
+}
+
+// IsErrInvalidProjectIcon checks if an error is ErrInvalidProjectIcon.
+func IsErrInvalidProjectIcon(err error) bool {
+	_, ok := err.(*ErrInvalidProjectIcon)
+	return ok
+}
+
+func (err *ErrInvalidProjectIcon) Error() string {
+	return fmt.Sprintf("Project icon is not a valid image [ProjectID: %d]", err.ProjectID)
+}
+
+// ErrCodeInvalidProjectIcon holds the unique world-error code of this error
+const ErrCodeInvalidProjectIcon = 3025
+
+// HTTPError holds the http error description
+func (err *ErrInvalidProjectIcon) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidProjectIcon,
+		Message:  "The uploaded icon is not a supported image. Use a png, jpeg or gif file.",
+	}
+}
+
 // ErrProjectCannotBelongToAPseudoParentProject represents an error where a project cannot belong to a pseudo project
 type ErrProjectCannotBelongToAPseudoParentProject struct {
 	ProjectID       int64
@@ -439,6 +546,226 @@ func (err *ErrProjectViewDoesNotExist) HTTPError() web.HTTPError {
 	}
 }
 
+// ErrInvalidPriorityAgingThreshold represents an error where a priority aging threshold of a project is invalid
+type ErrInvalidPriorityAgingThreshold struct {
+	ProjectID int64
+	BeforeDue int64
+	Priority  int64
+}
+
+// IsErrInvalidPriorityAgingThreshold checks if an error is ErrInvalidPriorityAgingThreshold.
+func IsErrInvalidPriorityAgingThreshold(err error) bool {
+	_, ok := err.(*ErrInvalidPriorityAgingThreshold)
+	return ok
+}
+
+func (err *ErrInvalidPriorityAgingThreshold) Error() string {
+	return fmt.Sprintf("Invalid priority aging threshold [ProjectID: %d, BeforeDue: %d, Priority: %d]", err.ProjectID, err.BeforeDue, err.Priority)
+}
+
+// ErrCodeInvalidPriorityAgingThreshold holds the unique world-error code of this error
+const ErrCodeInvalidPriorityAgingThreshold = 3015
+
+// HTTPError holds the http error description
+func (err *ErrInvalidPriorityAgingThreshold) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidPriorityAgingThreshold,
+		Message:  "A priority aging threshold needs a priority between 1 and 5 and cannot be a negative time before the due date.",
+	}
+}
+
+// ErrProjectIsNotATemplate represents an error where a project is used as a template without being one
+type ErrProjectIsNotATemplate struct {
+	ProjectID int64
+}
+
+// IsErrProjectIsNotATemplate checks if an error is ErrProjectIsNotATemplate.
+func IsErrProjectIsNotATemplate(err error) bool {
+	_, ok := err.(*ErrProjectIsNotATemplate)
+	return ok
+}
+
+func (err *ErrProjectIsNotATemplate) Error() string {
+	return fmt.Sprintf("Project is not a template [ProjectID: %d]", err.ProjectID)
+}
+
+// ErrCodeProjectIsNotATemplate holds the unique world-error code of this error
+const ErrCodeProjectIsNotATemplate = 3016
+
+// HTTPError holds the http error description
+func (err *ErrProjectIsNotATemplate) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeProjectIsNotATemplate,
+		Message:  "This project is not a template.",
+	}
+}
+
+// ErrProjectDuplicateJobDoesNotExist represents an error where a project duplication job does not exist
+type ErrProjectDuplicateJobDoesNotExist struct {
+	JobID string
+}
+
+// IsErrProjectDuplicateJobDoesNotExist checks if an error is ErrProjectDuplicateJobDoesNotExist.
+func IsErrProjectDuplicateJobDoesNotExist(err error) bool {
+	_, ok := err.(*ErrProjectDuplicateJobDoesNotExist)
+	return ok
+}
+
+func (err *ErrProjectDuplicateJobDoesNotExist) Error() string {
+	return fmt.Sprintf("Project duplicate job does not exist [JobID: %s]", err.JobID)
+}
+
+// ErrCodeProjectDuplicateJobDoesNotExist holds the unique world-error code of this error
+const ErrCodeProjectDuplicateJobDoesNotExist = 3017
+
+// HTTPError holds the http error description
+func (err *ErrProjectDuplicateJobDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeProjectDuplicateJobDoesNotExist,
+		Message:  "This project duplication job does not exist.",
+	}
+}
+
+// ErrCannotMergeProjectIntoItself represents an error where a project should be merged into itself or one of its children
+type ErrCannotMergeProjectIntoItself struct {
+	ProjectID       int64
+	TargetProjectID int64
+}
+
+// IsErrCannotMergeProjectIntoItself checks if an error is ErrCannotMergeProjectIntoItself.
+func IsErrCannotMergeProjectIntoItself(err error) bool {
+	_, ok := err.(*ErrCannotMergeProjectIntoItself)
+	return ok
+}
+
+func (err *ErrCannotMergeProjectIntoItself) Error() string {
+	return fmt.Sprintf("Project cannot be merged into itself or one of its children [ProjectID: %d, TargetProjectID: %d]", err.ProjectID, err.TargetProjectID)
+}
+
+// ErrCodeCannotMergeProjectIntoItself holds the unique world-error code of this error
+const ErrCodeCannotMergeProjectIntoItself = 3018
+
+// HTTPError holds the http error description
+func (err *ErrCannotMergeProjectIntoItself) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeCannotMergeProjectIntoItself,
+		Message:  "A project cannot be merged into itself or one of its child projects.",
+	}
+}
+
+// ErrPublicProjectsDisabled represents an error where a project should be made public but public projects are disabled
+type ErrPublicProjectsDisabled struct{}
+
+// IsErrPublicProjectsDisabled checks if an error is ErrPublicProjectsDisabled.
+func IsErrPublicProjectsDisabled(err error) bool {
+	_, ok := err.(*ErrPublicProjectsDisabled)
+	return ok
+}
+
+func (err *ErrPublicProjectsDisabled) Error() string {
+	return "Public projects are disabled"
+}
+
+// ErrCodePublicProjectsDisabled holds the unique world-error code of this error
+const ErrCodePublicProjectsDisabled = 3019
+
+// HTTPError holds the http error description
+func (err *ErrPublicProjectsDisabled) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodePublicProjectsDisabled,
+		Message:  "Public projects are disabled on this instance.",
+	}
+}
+
+// ErrProjectTransferDoesNotExist represents an error where a project has no pending transfer
+type ErrProjectTransferDoesNotExist struct {
+	ProjectID int64
+}
+
+// IsErrProjectTransferDoesNotExist checks if an error is ErrProjectTransferDoesNotExist.
+func IsErrProjectTransferDoesNotExist(err error) bool {
+	_, ok := err.(*ErrProjectTransferDoesNotExist)
+	return ok
+}
+
+func (err *ErrProjectTransferDoesNotExist) Error() string {
+	return fmt.Sprintf("Project transfer does not exist [ProjectID: %d]", err.ProjectID)
+}
+
+// ErrCodeProjectTransferDoesNotExist holds the unique world-error code of this error
+const ErrCodeProjectTransferDoesNotExist = 3020
+
+// HTTPError holds the http error description
+func (err *ErrProjectTransferDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeProjectTransferDoesNotExist,
+		Message:  "There is no pending transfer for this project.",
+	}
+}
+
+// ErrCannotTransferProjectToOwner represents an error where a project should be transferred to its current owner
+type ErrCannotTransferProjectToOwner struct {
+	ProjectID int64
+	UserID    int64
+}
+
+// IsErrCannotTransferProjectToOwner checks if an error is ErrCannotTransferProjectToOwner.
+func IsErrCannotTransferProjectToOwner(err error) bool {
+	_, ok := err.(*ErrCannotTransferProjectToOwner)
+	return ok
+}
+
+func (err *ErrCannotTransferProjectToOwner) Error() string {
+	return fmt.Sprintf("Project is already owned by this user [ProjectID: %d, UserID: %d]", err.ProjectID, err.UserID)
+}
+
+// ErrCodeCannotTransferProjectToOwner holds the unique world-error code of this error
+const ErrCodeCannotTransferProjectToOwner = 3021
+
+// HTTPError holds the http error description
+func (err *ErrCannotTransferProjectToOwner) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeCannotTransferProjectToOwner,
+		Message:  "The project is already owned by this user.",
+	}
+}
+
+// ErrUserProjectQuotaExceeded represents an error where a user can't create another project because they
+// reached the limit of projects per user
+type ErrUserProjectQuotaExceeded struct {
+	UserID int64
+	Limit  int64
+}
+
+// IsErrUserProjectQuotaExceeded checks if an error is ErrUserProjectQuotaExceeded.
+func IsErrUserProjectQuotaExceeded(err error) bool {
+	_, ok := err.(*ErrUserProjectQuotaExceeded)
+	return ok
+}
+
+func (err *ErrUserProjectQuotaExceeded) Error() string {
+	return fmt.Sprintf("User project quota exceeded [UserID: %d, Limit: %d]", err.UserID, err.Limit)
+}
+
+// ErrCodeUserProjectQuotaExceeded holds the unique world-error code of this error
+const ErrCodeUserProjectQuotaExceeded = 3026
+
+// HTTPError holds the http error description
+func (err *ErrUserProjectQuotaExceeded) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeUserProjectQuotaExceeded,
+		Message:  fmt.Sprintf("You have reached your limit of %d projects. Delete a project to create a new one.", err.Limit),
+	}
+}
+
 // ==============
 // Task errors
 // ==============
@@ -1133,126 +1460,739 @@ func (err ErrMustHaveProjectViewToSortByPosition) HTTPError() web.HTTPError {
 	}
 }
 
-// ============
-// Team errors
-// ============
-
-// ErrTeamNameCannotBeEmpty represents an error where a team name is empty.
-type ErrTeamNameCannotBeEmpty struct {
-	TeamID int64
+// ErrReminderRepeatIntervalInvalid represents an error where a reminder has a repeat interval which is too short
+type ErrReminderRepeatIntervalInvalid struct {
+	TaskID         int64
+	RepeatInterval int64
 }
 
-// IsErrTeamNameCannotBeEmpty checks if an error is a ErrTeamNameCannotBeEmpty.
-func IsErrTeamNameCannotBeEmpty(err error) bool {
-	_, ok := err.(ErrTeamNameCannotBeEmpty)
+// IsErrReminderRepeatIntervalInvalid checks if an error is ErrReminderRepeatIntervalInvalid.
+func IsErrReminderRepeatIntervalInvalid(err error) bool {
+	_, ok := err.(ErrReminderRepeatIntervalInvalid)
 	return ok
 }
 
-func (err ErrTeamNameCannotBeEmpty) Error() string {
-	return fmt.Sprintf("Team name cannot be empty [Team ID: %d]", err.TeamID)
+func (err ErrReminderRepeatIntervalInvalid) Error() string {
+	return fmt.Sprintf("Task [TaskID: %v] has a reminder with an invalid repeat interval [RepeatInterval: %v]", err.TaskID, err.RepeatInterval)
 }
 
-// ErrCodeTeamNameCannotBeEmpty holds the unique world-error code of this error
-const ErrCodeTeamNameCannotBeEmpty = 6001
+// ErrCodeReminderRepeatIntervalInvalid holds the unique world-error code of this error
+const ErrCodeReminderRepeatIntervalInvalid = 4027
 
 // HTTPError holds the http error description
-func (err ErrTeamNameCannotBeEmpty) HTTPError() web.HTTPError {
-	return web.HTTPError{HTTPCode: http.StatusBadRequest, Code: ErrCodeTeamNameCannotBeEmpty, Message: "The team name cannot be empty"}
+func (err ErrReminderRepeatIntervalInvalid) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeReminderRepeatIntervalInvalid,
+		Message:  "A repeating reminder must repeat at most once per minute and at least once per year.",
+	}
 }
 
-type ErrTeamDoesNotExist struct {
-	TeamID int64
+// ErrTaskDescriptionRevisionDoesNotExist represents an error where a task description revision does not exist
+type ErrTaskDescriptionRevisionDoesNotExist struct {
+	ID     int64
+	TaskID int64
 }
 
-// IsErrTeamDoesNotExist checks if an error is ErrTeamDoesNotExist.
-func IsErrTeamDoesNotExist(err error) bool {
-	_, ok := err.(ErrTeamDoesNotExist)
+// IsErrTaskDescriptionRevisionDoesNotExist checks if an error is ErrTaskDescriptionRevisionDoesNotExist.
+func IsErrTaskDescriptionRevisionDoesNotExist(err error) bool {
+	_, ok := err.(ErrTaskDescriptionRevisionDoesNotExist)
 	return ok
 }
 
-func (err ErrTeamDoesNotExist) Error() string {
-	return fmt.Sprintf("Team does not exist [Team ID: %d]", err.TeamID)
+func (err ErrTaskDescriptionRevisionDoesNotExist) Error() string {
+	return fmt.Sprintf("Task description revision does not exist [ID: %d, TaskID: %d]", err.ID, err.TaskID)
 }
 
-// ErrCodeTeamDoesNotExist holds the unique world-error code of this error
-const ErrCodeTeamDoesNotExist = 6002
+// ErrCodeTaskDescriptionRevisionDoesNotExist holds the unique world-error code of this error
+const ErrCodeTaskDescriptionRevisionDoesNotExist = 4028
 
 // HTTPError holds the http error description
-func (err ErrTeamDoesNotExist) HTTPError() web.HTTPError {
-	return web.HTTPError{HTTPCode: http.StatusNotFound, Code: ErrCodeTeamDoesNotExist, Message: "This team does not exist."}
+func (err ErrTaskDescriptionRevisionDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeTaskDescriptionRevisionDoesNotExist,
+		Message:  "This task description revision does not exist.",
+	}
 }
 
-// ErrTeamAlreadyHasAccess represents an error where a team already has access to a project
-type ErrTeamAlreadyHasAccess struct {
-	TeamID int64
-	ID     int64
+// ErrInvalidTaskExportFormat represents an error where a task should be exported in an unknown format
+type ErrInvalidTaskExportFormat struct {
+	Format string
 }
 
-// IsErrTeamAlreadyHasAccess checks if an error is ErrTeamAlreadyHasAccess.
-func IsErrTeamAlreadyHasAccess(err error) bool {
-	_, ok := err.(ErrTeamAlreadyHasAccess)
+// IsErrInvalidTaskExportFormat checks if an error is ErrInvalidTaskExportFormat.
+func IsErrInvalidTaskExportFormat(err error) bool {
+	_, ok := err.(ErrInvalidTaskExportFormat)
 	return ok
 }
 
-func (err ErrTeamAlreadyHasAccess) Error() string {
-	return fmt.Sprintf("Team already has access. [Team ID: %d, ID: %d]", err.TeamID, err.ID)
+func (err ErrInvalidTaskExportFormat) Error() string {
+	return fmt.Sprintf("Invalid task export format [Format: %s]", err.Format)
 }
 
-// ErrCodeTeamAlreadyHasAccess holds the unique world-error code of this error
-const ErrCodeTeamAlreadyHasAccess = 6004
+// ErrCodeInvalidTaskExportFormat holds the unique world-error code of this error
+const ErrCodeInvalidTaskExportFormat = 4029
 
 // HTTPError holds the http error description
-func (err ErrTeamAlreadyHasAccess) HTTPError() web.HTTPError {
-	return web.HTTPError{HTTPCode: http.StatusConflict, Code: ErrCodeTeamAlreadyHasAccess, Message: "This team already has access."}
+func (err ErrInvalidTaskExportFormat) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidTaskExportFormat,
+		Message:  "The export format is invalid. Supported formats are markdown and pdf.",
+	}
 }
 
-// ErrUserIsMemberOfTeam represents an error where a user is already member of a team.
-type ErrUserIsMemberOfTeam struct {
-	TeamID int64
-	UserID int64
+// ErrTaskCommentAlreadyConverted represents an error where a task comment was already converted into a task
+type ErrTaskCommentAlreadyConverted struct {
+	CommentID       int64
+	ConvertedTaskID int64
 }
 
-// IsErrUserIsMemberOfTeam checks if an error is ErrUserIsMemberOfTeam.
-func IsErrUserIsMemberOfTeam(err error) bool {
-	_, ok := err.(ErrUserIsMemberOfTeam)
+// IsErrTaskCommentAlreadyConverted checks if an error is ErrTaskCommentAlreadyConverted.
+func IsErrTaskCommentAlreadyConverted(err error) bool {
+	_, ok := err.(ErrTaskCommentAlreadyConverted)
 	return ok
 }
 
-func (err ErrUserIsMemberOfTeam) Error() string {
-	return fmt.Sprintf("User is already a member of that team. [Team ID: %d, User ID: %d]", err.TeamID, err.UserID)
+func (err ErrTaskCommentAlreadyConverted) Error() string {
+	return fmt.Sprintf("Task comment was already converted into a task [CommentID: %d, ConvertedTaskID: %d]", err.CommentID, err.ConvertedTaskID)
 }
 
-// ErrCodeUserIsMemberOfTeam holds the unique world-error code of this error
-const ErrCodeUserIsMemberOfTeam = 6005
+// ErrCodeTaskCommentAlreadyConverted holds the unique world-error code of this error
+const ErrCodeTaskCommentAlreadyConverted = 4030
 
 // HTTPError holds the http error description
-func (err ErrUserIsMemberOfTeam) HTTPError() web.HTTPError {
-	return web.HTTPError{HTTPCode: http.StatusConflict, Code: ErrCodeUserIsMemberOfTeam, Message: "This user is already a member of that team."}
+func (err ErrTaskCommentAlreadyConverted) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusConflict,
+		Code:     ErrCodeTaskCommentAlreadyConverted,
+		Message:  "This comment was already converted into a task.",
+	}
 }
 
-// ErrCannotDeleteLastTeamMember represents an error where a user wants to delete the last member of a team (probably himself)
-type ErrCannotDeleteLastTeamMember struct {
-	TeamID int64
-	UserID int64
+// ErrTooManyBulkTasks represents an error where too many tasks were passed to be created at once.
+type ErrTooManyBulkTasks struct {
+	Count int
+	Max   int
 }
 
-// IsErrCannotDeleteLastTeamMember checks if an error is ErrCannotDeleteLastTeamMember.
-func IsErrCannotDeleteLastTeamMember(err error) bool {
-	_, ok := err.(ErrCannotDeleteLastTeamMember)
+// IsErrTooManyBulkTasks checks if an error is ErrTooManyBulkTasks.
+func IsErrTooManyBulkTasks(err error) bool {
+	_, ok := err.(ErrTooManyBulkTasks)
 	return ok
 }
 
-func (err ErrCannotDeleteLastTeamMember) Error() string {
-	return fmt.Sprintf("Cannot delete last team member. [Team ID: %d, User ID: %d]", err.TeamID, err.UserID)
+func (err ErrTooManyBulkTasks) Error() string {
+	return fmt.Sprintf("Too many tasks to create at once [Count: %d, Max: %d]", err.Count, err.Max)
 }
 
-// ErrCodeCannotDeleteLastTeamMember holds the unique world-error code of this error
-const ErrCodeCannotDeleteLastTeamMember = 6006
+// ErrCodeTooManyBulkTasks holds the unique world-error code of this error
+const ErrCodeTooManyBulkTasks = 4031
 
 // HTTPError holds the http error description
-func (err ErrCannotDeleteLastTeamMember) HTTPError() web.HTTPError {
-	return web.HTTPError{HTTPCode: http.StatusBadRequest, Code: ErrCodeCannotDeleteLastTeamMember, Message: "You cannot delete the last member of a team."}
-}
+func (err ErrTooManyBulkTasks) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeTooManyBulkTasks,
+		Message:  fmt.Sprintf("You can only create up to %d tasks at once.", err.Max),
+	}
+}
+
+// ErrTaskIsNotPendingApproval represents an error where a task should be approved or rejected which does not wait for approval.
+type ErrTaskIsNotPendingApproval struct {
+	TaskID int64
+}
+
+// IsErrTaskIsNotPendingApproval checks if an error is ErrTaskIsNotPendingApproval.
+func IsErrTaskIsNotPendingApproval(err error) bool {
+	_, ok := err.(ErrTaskIsNotPendingApproval)
+	return ok
+}
+
+func (err ErrTaskIsNotPendingApproval) Error() string {
+	return fmt.Sprintf("Task is not pending approval [TaskID: %d]", err.TaskID)
+}
+
+// ErrCodeTaskIsNotPendingApproval holds the unique world-error code of this error
+const ErrCodeTaskIsNotPendingApproval = 4032
+
+// HTTPError holds the http error description
+func (err ErrTaskIsNotPendingApproval) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeTaskIsNotPendingApproval,
+		Message:  "This task is not waiting for approval.",
+	}
+}
+
+// ErrInvalidSearchQuery represents an error where a search query could not be parsed
+type ErrInvalidSearchQuery struct {
+	Query  string
+	Reason string
+}
+
+// IsErrInvalidSearchQuery checks if an error is ErrInvalidSearchQuery.
+func IsErrInvalidSearchQuery(err error) bool {
+	_, ok := err.(ErrInvalidSearchQuery)
+	return ok
+}
+
+func (err ErrInvalidSearchQuery) Error() string {
+	return fmt.Sprintf("Search query is invalid [Query: %s, Reason: %s]", err.Query, err.Reason)
+}
+
+// ErrCodeInvalidSearchQuery holds the unique world-error code of this error
+const ErrCodeInvalidSearchQuery = 4033
+
+// HTTPError holds the http error description
+func (err ErrInvalidSearchQuery) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidSearchQuery,
+		Message:  fmt.Sprintf("The search query '%s' is invalid: %s", err.Query, err.Reason),
+	}
+}
+
+// ErrInvalidTaskSearchIn represents an error where tasks should be searched in something which can't be searched
+type ErrInvalidTaskSearchIn struct {
+	SearchIn string
+}
+
+// IsErrInvalidTaskSearchIn checks if an error is ErrInvalidTaskSearchIn.
+func IsErrInvalidTaskSearchIn(err error) bool {
+	_, ok := err.(ErrInvalidTaskSearchIn)
+	return ok
+}
+
+func (err ErrInvalidTaskSearchIn) Error() string {
+	return fmt.Sprintf("Tasks can't be searched in this [SearchIn: %s]", err.SearchIn)
+}
+
+// ErrCodeInvalidTaskSearchIn holds the unique world-error code of this error
+const ErrCodeInvalidTaskSearchIn = 4034
+
+// HTTPError holds the http error description
+func (err ErrInvalidTaskSearchIn) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidTaskSearchIn,
+		Message:  fmt.Sprintf("Tasks can't be searched in '%s', only in comments or attachments.", err.SearchIn),
+	}
+}
+
+// ErrTaskAttachmentUploadDoesNotExist represents an error where a resumable attachment upload does not exist
+type ErrTaskAttachmentUploadDoesNotExist struct {
+	UploadID int64
+	TaskID   int64
+}
+
+// IsErrTaskAttachmentUploadDoesNotExist checks if an error is ErrTaskAttachmentUploadDoesNotExist.
+func IsErrTaskAttachmentUploadDoesNotExist(err error) bool {
+	_, ok := err.(ErrTaskAttachmentUploadDoesNotExist)
+	return ok
+}
+
+func (err ErrTaskAttachmentUploadDoesNotExist) Error() string {
+	return fmt.Sprintf("Task attachment upload does not exist [UploadID: %d, TaskID: %d]", err.UploadID, err.TaskID)
+}
+
+// ErrCodeTaskAttachmentUploadDoesNotExist holds the unique world-error code of this error
+const ErrCodeTaskAttachmentUploadDoesNotExist = 4035
+
+// HTTPError holds the http error description
+func (err ErrTaskAttachmentUploadDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeTaskAttachmentUploadDoesNotExist,
+		Message:  "This upload does not exist or has expired.",
+	}
+}
+
+// ErrTaskAttachmentUploadOffsetMismatch represents an error where a chunk does not start where the upload currently ends
+type ErrTaskAttachmentUploadOffsetMismatch struct {
+	UploadID int64
+	Expected uint64
+	Given    uint64
+}
+
+// IsErrTaskAttachmentUploadOffsetMismatch checks if an error is ErrTaskAttachmentUploadOffsetMismatch.
+func IsErrTaskAttachmentUploadOffsetMismatch(err error) bool {
+	_, ok := err.(ErrTaskAttachmentUploadOffsetMismatch)
+	return ok
+}
+
+func (err ErrTaskAttachmentUploadOffsetMismatch) Error() string {
+	return fmt.Sprintf("Task attachment upload chunk has the wrong offset [UploadID: %d, Expected: %d, Given: %d]", err.UploadID, err.Expected, err.Given)
+}
+
+// ErrCodeTaskAttachmentUploadOffsetMismatch holds the unique world-error code of this error
+const ErrCodeTaskAttachmentUploadOffsetMismatch = 4036
+
+// HTTPError holds the http error description
+func (err ErrTaskAttachmentUploadOffsetMismatch) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusConflict,
+		Code:     ErrCodeTaskAttachmentUploadOffsetMismatch,
+		Message:  fmt.Sprintf("The chunk needs to start at offset %d.", err.Expected),
+	}
+}
+
+// ErrTaskAttachmentUploadChunkTooLarge represents an error where a chunk goes beyond the announced size of the upload
+type ErrTaskAttachmentUploadChunkTooLarge struct {
+	UploadID int64
+	Size     uint64
+}
+
+// IsErrTaskAttachmentUploadChunkTooLarge checks if an error is ErrTaskAttachmentUploadChunkTooLarge.
+func IsErrTaskAttachmentUploadChunkTooLarge(err error) bool {
+	_, ok := err.(ErrTaskAttachmentUploadChunkTooLarge)
+	return ok
+}
+
+func (err ErrTaskAttachmentUploadChunkTooLarge) Error() string {
+	return fmt.Sprintf("Task attachment upload chunk exceeds the upload size [UploadID: %d, Size: %d]", err.UploadID, err.Size)
+}
+
+// ErrCodeTaskAttachmentUploadChunkTooLarge holds the unique world-error code of this error
+const ErrCodeTaskAttachmentUploadChunkTooLarge = 4037
+
+// HTTPError holds the http error description
+func (err ErrTaskAttachmentUploadChunkTooLarge) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeTaskAttachmentUploadChunkTooLarge,
+		Message:  fmt.Sprintf("The chunk goes beyond the size of the upload, which is %d bytes.", err.Size),
+	}
+}
+
+// ErrTaskAttachmentIsNotAnImage represents an error where a thumbnail is requested for an attachment which is no image
+type ErrTaskAttachmentIsNotAnImage struct {
+	AttachmentID int64
+}
+
+// IsErrTaskAttachmentIsNotAnImage checks if an error is ErrTaskAttachmentIsNotAnImage.
+func IsErrTaskAttachmentIsNotAnImage(err error) bool {
+	_, ok := err.(ErrTaskAttachmentIsNotAnImage)
+	return ok
+}
+
+func (err ErrTaskAttachmentIsNotAnImage) Error() string {
+	return fmt.Sprintf("Task attachment is not an image [AttachmentID: %d]", err.AttachmentID)
+}
+
+// ErrCodeTaskAttachmentIsNotAnImage holds the unique world-error code of this error
+const ErrCodeTaskAttachmentIsNotAnImage = 4038
+
+// HTTPError holds the http error description
+func (err ErrTaskAttachmentIsNotAnImage) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeTaskAttachmentIsNotAnImage,
+		Message:  "This attachment is not an image, there is no thumbnail for it.",
+	}
+}
+
+// ErrInvalidTaskAttachmentThumbnailSize represents an error where a thumbnail is requested in a size which does not exist
+type ErrInvalidTaskAttachmentThumbnailSize struct {
+	Size string
+}
+
+// IsErrInvalidTaskAttachmentThumbnailSize checks if an error is ErrInvalidTaskAttachmentThumbnailSize.
+func IsErrInvalidTaskAttachmentThumbnailSize(err error) bool {
+	_, ok := err.(ErrInvalidTaskAttachmentThumbnailSize)
+	return ok
+}
+
+func (err ErrInvalidTaskAttachmentThumbnailSize) Error() string {
+	return fmt.Sprintf("Invalid task attachment thumbnail size [Size: %s]", err.Size)
+}
+
+// ErrCodeInvalidTaskAttachmentThumbnailSize holds the unique world-error code of this error
+const ErrCodeInvalidTaskAttachmentThumbnailSize = 4039
+
+// HTTPError holds the http error description
+func (err ErrInvalidTaskAttachmentThumbnailSize) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidTaskAttachmentThumbnailSize,
+		Message:  fmt.Sprintf("The thumbnail size '%s' does not exist, it must be small, medium or large.", err.Size),
+	}
+}
+
+// ErrTaskAttachmentVersionDoesNotExist represents an error where a version of an attachment does not exist
+type ErrTaskAttachmentVersionDoesNotExist struct {
+	AttachmentID int64
+	Version      int64
+}
+
+// IsErrTaskAttachmentVersionDoesNotExist checks if an error is ErrTaskAttachmentVersionDoesNotExist.
+func IsErrTaskAttachmentVersionDoesNotExist(err error) bool {
+	_, ok := err.(ErrTaskAttachmentVersionDoesNotExist)
+	return ok
+}
+
+func (err ErrTaskAttachmentVersionDoesNotExist) Error() string {
+	return fmt.Sprintf("Task attachment version does not exist [AttachmentID: %d, Version: %d]", err.AttachmentID, err.Version)
+}
+
+// ErrCodeTaskAttachmentVersionDoesNotExist holds the unique world-error code of this error
+const ErrCodeTaskAttachmentVersionDoesNotExist = 4040
+
+// HTTPError holds the http error description
+func (err ErrTaskAttachmentVersionDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeTaskAttachmentVersionDoesNotExist,
+		Message:  "This version of the attachment does not exist.",
+	}
+}
+
+// ErrInvalidTaskAttachmentLinkURL represents an error where the url of a link attachment is not a valid http url
+type ErrInvalidTaskAttachmentLinkURL struct {
+	URL string
+}
+
+// IsErrInvalidTaskAttachmentLinkURL checks if an error is ErrInvalidTaskAttachmentLinkURL.
+func IsErrInvalidTaskAttachmentLinkURL(err error) bool {
+	_, ok := err.(ErrInvalidTaskAttachmentLinkURL)
+	return ok
+}
+
+func (err ErrInvalidTaskAttachmentLinkURL) Error() string {
+	return fmt.Sprintf("Task attachment link url is invalid [URL: %s]", err.URL)
+}
+
+// ErrCodeInvalidTaskAttachmentLinkURL holds the unique world-error code of this error
+const ErrCodeInvalidTaskAttachmentLinkURL = 4041
+
+// HTTPError holds the http error description
+func (err ErrInvalidTaskAttachmentLinkURL) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidTaskAttachmentLinkURL,
+		Message:  "The link must be a valid http or https url.",
+	}
+}
+
+// ErrTaskAttachmentIsALink represents an error where a file operation is done on a link attachment
+type ErrTaskAttachmentIsALink struct {
+	AttachmentID int64
+}
+
+// IsErrTaskAttachmentIsALink checks if an error is ErrTaskAttachmentIsALink.
+func IsErrTaskAttachmentIsALink(err error) bool {
+	_, ok := err.(ErrTaskAttachmentIsALink)
+	return ok
+}
+
+func (err ErrTaskAttachmentIsALink) Error() string {
+	return fmt.Sprintf("Task attachment is a link [AttachmentID: %d]", err.AttachmentID)
+}
+
+// ErrCodeTaskAttachmentIsALink holds the unique world-error code of this error
+const ErrCodeTaskAttachmentIsALink = 4042
+
+// HTTPError holds the http error description
+func (err ErrTaskAttachmentIsALink) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeTaskAttachmentIsALink,
+		Message:  "This attachment is a link and does not have a file.",
+	}
+}
+
+// ErrTaskAttachmentIsInfected represents an error where the virus scanner found something in an uploaded attachment
+type ErrTaskAttachmentIsInfected struct {
+	Name      string
+	Signature string
+}
+
+// IsErrTaskAttachmentIsInfected checks if an error is ErrTaskAttachmentIsInfected.
+func IsErrTaskAttachmentIsInfected(err error) bool {
+	_, ok := err.(ErrTaskAttachmentIsInfected)
+	return ok
+}
+
+func (err ErrTaskAttachmentIsInfected) Error() string {
+	return fmt.Sprintf("Task attachment is infected [Name: %s, Signature: %s]", err.Name, err.Signature)
+}
+
+// ErrCodeTaskAttachmentIsInfected holds the unique world-error code of this error
+const ErrCodeTaskAttachmentIsInfected = 4043
+
+// HTTPError holds the http error description
+func (err ErrTaskAttachmentIsInfected) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusUnprocessableEntity,
+		Code:     ErrCodeTaskAttachmentIsInfected,
+		Message:  fmt.Sprintf("The file %s was rejected because the virus scanner found %s in it.", err.Name, err.Signature),
+	}
+}
+
+// ErrUserAttachmentQuotaExceeded represents an error where a new attachment would exceed the attachment size limit of a user
+type ErrUserAttachmentQuotaExceeded struct {
+	UserID int64
+	Limit  int64
+	Used   int64
+}
+
+// IsErrUserAttachmentQuotaExceeded checks if an error is ErrUserAttachmentQuotaExceeded.
+func IsErrUserAttachmentQuotaExceeded(err error) bool {
+	_, ok := err.(*ErrUserAttachmentQuotaExceeded)
+	return ok
+}
+
+func (err *ErrUserAttachmentQuotaExceeded) Error() string {
+	return fmt.Sprintf("User attachment quota exceeded [UserID: %d, Limit: %d, Used: %d]", err.UserID, err.Limit, err.Used)
+}
+
+// ErrCodeUserAttachmentQuotaExceeded holds the unique world-error code of this error
+const ErrCodeUserAttachmentQuotaExceeded = 4044
+
+// HTTPError holds the http error description
+func (err *ErrUserAttachmentQuotaExceeded) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeUserAttachmentQuotaExceeded,
+		Message:  fmt.Sprintf("Your attachments would exceed your limit of %d bytes, %d bytes are already used.", err.Limit, err.Used),
+	}
+}
+
+// ErrTaskAttachmentHasNoPreview represents an error where a preview is requested for an attachment which is not a document with a preview
+type ErrTaskAttachmentHasNoPreview struct {
+	AttachmentID int64
+}
+
+// IsErrTaskAttachmentHasNoPreview checks if an error is ErrTaskAttachmentHasNoPreview.
+func IsErrTaskAttachmentHasNoPreview(err error) bool {
+	_, ok := err.(ErrTaskAttachmentHasNoPreview)
+	return ok
+}
+
+func (err ErrTaskAttachmentHasNoPreview) Error() string {
+	return fmt.Sprintf("Task attachment has no preview [AttachmentID: %d]", err.AttachmentID)
+}
+
+// ErrCodeTaskAttachmentHasNoPreview holds the unique world-error code of this error
+const ErrCodeTaskAttachmentHasNoPreview = 4045
+
+// HTTPError holds the http error description
+func (err ErrTaskAttachmentHasNoPreview) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeTaskAttachmentHasNoPreview,
+		Message:  "There is no preview for this attachment, only pdfs, plain text and markdown files have one.",
+	}
+}
+
+// ErrUserTaskQuotaExceeded represents an error where a user can't create another task because they reached the
+// limit of tasks per user
+type ErrUserTaskQuotaExceeded struct {
+	UserID int64
+	Limit  int64
+}
+
+// IsErrUserTaskQuotaExceeded checks if an error is ErrUserTaskQuotaExceeded.
+func IsErrUserTaskQuotaExceeded(err error) bool {
+	_, ok := err.(*ErrUserTaskQuotaExceeded)
+	return ok
+}
+
+func (err *ErrUserTaskQuotaExceeded) Error() string {
+	return fmt.Sprintf("User task quota exceeded [UserID: %d, Limit: %d]", err.UserID, err.Limit)
+}
+
+// ErrCodeUserTaskQuotaExceeded holds the unique world-error code of this error
+const ErrCodeUserTaskQuotaExceeded = 4046
+
+// HTTPError holds the http error description
+func (err *ErrUserTaskQuotaExceeded) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeUserTaskQuotaExceeded,
+		Message:  fmt.Sprintf("You have reached your limit of %d tasks. Delete some tasks to create new ones.", err.Limit),
+	}
+}
+
+// ErrReminderRepeatBackoffInvalid represents an error where a reminder has a repeat backoff or maximum repeat
+// interval outside of the allowed range
+type ErrReminderRepeatBackoffInvalid struct {
+	TaskID            int64
+	RepeatBackoff     float64
+	RepeatMaxInterval int64
+}
+
+// IsErrReminderRepeatBackoffInvalid checks if an error is ErrReminderRepeatBackoffInvalid.
+func IsErrReminderRepeatBackoffInvalid(err error) bool {
+	_, ok := err.(ErrReminderRepeatBackoffInvalid)
+	return ok
+}
+
+func (err ErrReminderRepeatBackoffInvalid) Error() string {
+	return fmt.Sprintf("Task [TaskID: %v] has a reminder with an invalid repeat backoff [RepeatBackoff: %v, RepeatMaxInterval: %v]", err.TaskID, err.RepeatBackoff, err.RepeatMaxInterval)
+}
+
+// ErrCodeReminderRepeatBackoffInvalid holds the unique world-error code of this error
+const ErrCodeReminderRepeatBackoffInvalid = 4047
+
+// HTTPError holds the http error description
+func (err ErrReminderRepeatBackoffInvalid) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeReminderRepeatBackoffInvalid,
+		Message:  "The repeat backoff of a reminder must be between 1 and 10 and its maximum repeat interval must not be shorter than the repeat interval or longer than a year.",
+	}
+}
+
+// ErrSearchHasTooManyResults represents an error where a search matches more tasks than the search backend
+// is able to return
+type ErrSearchHasTooManyResults struct {
+	Limit int64
+}
+
+// IsErrSearchHasTooManyResults checks if an error is ErrSearchHasTooManyResults.
+func IsErrSearchHasTooManyResults(err error) bool {
+	_, ok := err.(*ErrSearchHasTooManyResults)
+	return ok
+}
+
+func (err *ErrSearchHasTooManyResults) Error() string {
+	return fmt.Sprintf("Search has too many results [Limit: %d]", err.Limit)
+}
+
+// ErrCodeSearchHasTooManyResults holds the unique world-error code of this error
+const ErrCodeSearchHasTooManyResults = 4048
+
+// HTTPError holds the http error description
+func (err *ErrSearchHasTooManyResults) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeSearchHasTooManyResults,
+		Message:  fmt.Sprintf("The search matches more than %d tasks, please search for something more specific.", err.Limit),
+	}
+}
+
+// ============
+// Team errors
+// ============
+
+// ErrTeamNameCannotBeEmpty represents an error where a team name is empty.
+type ErrTeamNameCannotBeEmpty struct {
+	TeamID int64
+}
+
+// IsErrTeamNameCannotBeEmpty checks if an error is a ErrTeamNameCannotBeEmpty.
+func IsErrTeamNameCannotBeEmpty(err error) bool {
+	_, ok := err.(ErrTeamNameCannotBeEmpty)
+	return ok
+}
+
+func (err ErrTeamNameCannotBeEmpty) Error() string {
+	return fmt.Sprintf("Team name cannot be empty [Team ID: %d]", err.TeamID)
+}
+
+// ErrCodeTeamNameCannotBeEmpty holds the unique world-error code of this error
+const ErrCodeTeamNameCannotBeEmpty = 6001
+
+// HTTPError holds the http error description
+func (err ErrTeamNameCannotBeEmpty) HTTPError() web.HTTPError {
+	return web.HTTPError{HTTPCode: http.StatusBadRequest, Code: ErrCodeTeamNameCannotBeEmpty, Message: "The team name cannot be empty"}
+}
+
+type ErrTeamDoesNotExist struct {
+	TeamID int64
+}
+
+// IsErrTeamDoesNotExist checks if an error is ErrTeamDoesNotExist.
+func IsErrTeamDoesNotExist(err error) bool {
+	_, ok := err.(ErrTeamDoesNotExist)
+	return ok
+}
+
+func (err ErrTeamDoesNotExist) Error() string {
+	return fmt.Sprintf("Team does not exist [Team ID: %d]", err.TeamID)
+}
+
+// ErrCodeTeamDoesNotExist holds the unique world-error code of this error
+const ErrCodeTeamDoesNotExist = 6002
+
+// HTTPError holds the http error description
+func (err ErrTeamDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{HTTPCode: http.StatusNotFound, Code: ErrCodeTeamDoesNotExist, Message: "This team does not exist."}
+}
+
+// ErrTeamAlreadyHasAccess represents an error where a team already has access to a project
+type ErrTeamAlreadyHasAccess struct {
+	TeamID int64
+	ID     int64
+}
+
+// IsErrTeamAlreadyHasAccess checks if an error is ErrTeamAlreadyHasAccess.
+func IsErrTeamAlreadyHasAccess(err error) bool {
+	_, ok := err.(ErrTeamAlreadyHasAccess)
+	return ok
+}
+
+func (err ErrTeamAlreadyHasAccess) Error() string {
+	return fmt.Sprintf("Team already has access. [Team ID: %d, ID: %d]", err.TeamID, err.ID)
+}
+
+// ErrCodeTeamAlreadyHasAccess holds the unique world-error code of this error
+const ErrCodeTeamAlreadyHasAccess = 6004
+
+// HTTPError holds the http error description
+func (err ErrTeamAlreadyHasAccess) HTTPError() web.HTTPError {
+	return web.HTTPError{HTTPCode: http.StatusConflict, Code: ErrCodeTeamAlreadyHasAccess, Message: "This team already has access."}
+}
+
+// ErrUserIsMemberOfTeam represents an error where a user is already member of a team.
+type ErrUserIsMemberOfTeam struct {
+	TeamID int64
+	UserID int64
+}
+
+// IsErrUserIsMemberOfTeam checks if an error is ErrUserIsMemberOfTeam.
+func IsErrUserIsMemberOfTeam(err error) bool {
+	_, ok := err.(ErrUserIsMemberOfTeam)
+	return ok
+}
+
+func (err ErrUserIsMemberOfTeam) Error() string {
+	return fmt.Sprintf("User is already a member of that team. [Team ID: %d, User ID: %d]", err.TeamID, err.UserID)
+}
+
+// ErrCodeUserIsMemberOfTeam holds the unique world-error code of this error
+const ErrCodeUserIsMemberOfTeam = 6005
+
+// HTTPError holds the http error description
+func (err ErrUserIsMemberOfTeam) HTTPError() web.HTTPError {
+	return web.HTTPError{HTTPCode: http.StatusConflict, Code: ErrCodeUserIsMemberOfTeam, Message: "This user is already a member of that team."}
+}
+
+// ErrCannotDeleteLastTeamMember represents an error where a user wants to delete the last member of a team (probably himself)
+type ErrCannotDeleteLastTeamMember struct {
+	TeamID int64
+	UserID int64
+}
+
+// IsErrCannotDeleteLastTeamMember checks if an error is ErrCannotDeleteLastTeamMember.
+func IsErrCannotDeleteLastTeamMember(err error) bool {
+	_, ok := err.(ErrCannotDeleteLastTeamMember)
+	return ok
+}
+
+func (err ErrCannotDeleteLastTeamMember) Error() string {
+	return fmt.Sprintf("Cannot delete last team member. [Team ID: %d, User ID: %d]", err.TeamID, err.UserID)
+}
+
+// ErrCodeCannotDeleteLastTeamMember holds the unique world-error code of this error
+const ErrCodeCannotDeleteLastTeamMember = 6006
+
+// HTTPError holds the http error description
+func (err ErrCannotDeleteLastTeamMember) HTTPError() web.HTTPError {
+	return web.HTTPError{HTTPCode: http.StatusBadRequest, Code: ErrCodeCannotDeleteLastTeamMember, Message: "You cannot delete the last member of a team."}
+}
 
 // ErrTeamDoesNotHaveAccessToProject represents an error, where the Team is not the owner of that Project (used i.e. when deleting a Project)
 type ErrTeamDoesNotHaveAccessToProject struct {
@@ -1465,6 +2405,114 @@ func (err ErrUserHasNoAccessToLabel) HTTPError() web.HTTPError {
 	}
 }
 
+// ErrLabelHasMultipleOwners represents an error where a label should belong to a team and a project at the same time
+type ErrLabelHasMultipleOwners struct{}
+
+// IsErrLabelHasMultipleOwners checks if an error is ErrLabelHasMultipleOwners.
+func IsErrLabelHasMultipleOwners(err error) bool {
+	_, ok := err.(ErrLabelHasMultipleOwners)
+	return ok
+}
+
+func (err ErrLabelHasMultipleOwners) Error() string {
+	return "Label can't belong to a team and a project at the same time"
+}
+
+// ErrCodeLabelHasMultipleOwners holds the unique world-error code of this error
+const ErrCodeLabelHasMultipleOwners = 8004
+
+// HTTPError holds the http error description
+func (err ErrLabelHasMultipleOwners) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeLabelHasMultipleOwners,
+		Message:  "A label can belong to either a team or a project, not both.",
+	}
+}
+
+// ErrLabelGroupDoesNotExist represents an error where a label group does not exist
+type ErrLabelGroupDoesNotExist struct {
+	GroupID int64
+}
+
+// IsErrLabelGroupDoesNotExist checks if an error is ErrLabelGroupDoesNotExist.
+func IsErrLabelGroupDoesNotExist(err error) bool {
+	_, ok := err.(ErrLabelGroupDoesNotExist)
+	return ok
+}
+
+func (err ErrLabelGroupDoesNotExist) Error() string {
+	return fmt.Sprintf("Label group does not exist [GroupID: %v]", err.GroupID)
+}
+
+// ErrCodeLabelGroupDoesNotExist holds the unique world-error code of this error
+const ErrCodeLabelGroupDoesNotExist = 8005
+
+// HTTPError holds the http error description
+func (err ErrLabelGroupDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeLabelGroupDoesNotExist,
+		Message:  "This label group does not exist.",
+	}
+}
+
+// ErrLabelGroupIsExclusive represents an error where a task would get more than one label of an exclusive group
+type ErrLabelGroupIsExclusive struct {
+	GroupID      int64
+	LabelID      int64
+	OtherLabelID int64
+}
+
+// IsErrLabelGroupIsExclusive checks if an error is ErrLabelGroupIsExclusive.
+func IsErrLabelGroupIsExclusive(err error) bool {
+	_, ok := err.(ErrLabelGroupIsExclusive)
+	return ok
+}
+
+func (err ErrLabelGroupIsExclusive) Error() string {
+	return fmt.Sprintf("Only one label of an exclusive group can be on a task [GroupID: %v, LabelID: %v, OtherLabelID: %v]", err.GroupID, err.LabelID, err.OtherLabelID)
+}
+
+// ErrCodeLabelGroupIsExclusive holds the unique world-error code of this error
+const ErrCodeLabelGroupIsExclusive = 8006
+
+// HTTPError holds the http error description
+func (err ErrLabelGroupIsExclusive) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeLabelGroupIsExclusive,
+		Message:  "The task already has another label of this group and only one label of the group is allowed per task.",
+	}
+}
+
+// ErrCannotMergeLabelIntoItself represents an error where a label should be merged into itself
+type ErrCannotMergeLabelIntoItself struct {
+	LabelID int64
+}
+
+// IsErrCannotMergeLabelIntoItself checks if an error is ErrCannotMergeLabelIntoItself.
+func IsErrCannotMergeLabelIntoItself(err error) bool {
+	_, ok := err.(ErrCannotMergeLabelIntoItself)
+	return ok
+}
+
+func (err ErrCannotMergeLabelIntoItself) Error() string {
+	return fmt.Sprintf("Label cannot be merged into itself [LabelID: %v]", err.LabelID)
+}
+
+// ErrCodeCannotMergeLabelIntoItself holds the unique world-error code of this error
+const ErrCodeCannotMergeLabelIntoItself = 8007
+
+// HTTPError holds the http error description
+func (err ErrCannotMergeLabelIntoItself) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeCannotMergeLabelIntoItself,
+		Message:  "A label cannot be merged into itself.",
+	}
+}
+
 // ========
 // Rights
 // ========
@@ -1496,6 +2544,60 @@ func (err ErrInvalidRight) HTTPError() web.HTTPError {
 	}
 }
 
+// ErrRoleDoesNotExist represents an error where a role does not exist
+type ErrRoleDoesNotExist struct {
+	RoleID int64
+}
+
+// IsErrRoleDoesNotExist checks if an error is ErrRoleDoesNotExist.
+func IsErrRoleDoesNotExist(err error) bool {
+	_, ok := err.(*ErrRoleDoesNotExist)
+	return ok
+}
+
+func (err *ErrRoleDoesNotExist) Error() string {
+	return fmt.Sprintf("Role does not exist [RoleID: %d]", err.RoleID)
+}
+
+// ErrCodeRoleDoesNotExist holds the unique world-error code of this error
+const ErrCodeRoleDoesNotExist = 9002
+
+// HTTPError holds the http error description
+func (err *ErrRoleDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeRoleDoesNotExist,
+		Message:  "This role does not exist.",
+	}
+}
+
+// ErrInvalidCapability represents an error where a role capability is unknown
+type ErrInvalidCapability struct {
+	Capability Capability
+}
+
+// IsErrInvalidCapability checks if an error is ErrInvalidCapability.
+func IsErrInvalidCapability(err error) bool {
+	_, ok := err.(*ErrInvalidCapability)
+	return ok
+}
+
+func (err *ErrInvalidCapability) Error() string {
+	return fmt.Sprintf("Capability invalid [Capability: %s]", err.Capability)
+}
+
+// ErrCodeInvalidCapability holds the unique world-error code of this error
+const ErrCodeInvalidCapability = 9003
+
+// HTTPError holds the http error description
+func (err *ErrInvalidCapability) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidCapability,
+		Message:  "The capability is invalid.",
+	}
+}
+
 // ========
 // Kanban
 // ========
@@ -1641,6 +2743,176 @@ func (err *ErrOnlyOneDoneBucketPerProject) HTTPError() web.HTTPError {
 	}
 }
 
+// ErrProjectViewHasNoSwimlanes represents an error where a swimlane position is saved for a view without swimlanes.
+type ErrProjectViewHasNoSwimlanes struct {
+	ProjectViewID int64
+}
+
+// IsErrProjectViewHasNoSwimlanes checks if an error is ErrProjectViewHasNoSwimlanes.
+func IsErrProjectViewHasNoSwimlanes(err error) bool {
+	_, ok := err.(*ErrProjectViewHasNoSwimlanes)
+	return ok
+}
+
+func (err *ErrProjectViewHasNoSwimlanes) Error() string {
+	return fmt.Sprintf("Project view has no swimlanes [ProjectViewID: %d]", err.ProjectViewID)
+}
+
+// ErrCodeProjectViewHasNoSwimlanes holds the unique world-error code of this error
+const ErrCodeProjectViewHasNoSwimlanes = 10006
+
+// HTTPError holds the http error description
+func (err *ErrProjectViewHasNoSwimlanes) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeProjectViewHasNoSwimlanes,
+		Message:  "This project view is not a kanban view grouped into swimlanes.",
+	}
+}
+
+// ErrInvalidBucketAction represents an error where a bucket action is not valid.
+type ErrInvalidBucketAction struct {
+	Kind    BucketActionKind
+	Message string
+}
+
+// IsErrInvalidBucketAction checks if an error is ErrInvalidBucketAction.
+func IsErrInvalidBucketAction(err error) bool {
+	_, ok := err.(*ErrInvalidBucketAction)
+	return ok
+}
+
+func (err *ErrInvalidBucketAction) Error() string {
+	return fmt.Sprintf("Bucket action is invalid [Kind: %s, Message: %s]", err.Kind, err.Message)
+}
+
+// ErrCodeInvalidBucketAction holds the unique world-error code of this error
+const ErrCodeInvalidBucketAction = 10007
+
+// HTTPError holds the http error description
+func (err *ErrInvalidBucketAction) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidBucketAction,
+		Message:  "The bucket action is invalid: " + err.Message,
+	}
+}
+
+// ErrBucketIsArchived represents an error where a task is moved into an archived bucket.
+type ErrBucketIsArchived struct {
+	BucketID int64
+}
+
+// IsErrBucketIsArchived checks if an error is ErrBucketIsArchived.
+func IsErrBucketIsArchived(err error) bool {
+	_, ok := err.(*ErrBucketIsArchived)
+	return ok
+}
+
+func (err *ErrBucketIsArchived) Error() string {
+	return fmt.Sprintf("Bucket is archived [BucketID: %d]", err.BucketID)
+}
+
+// ErrCodeBucketIsArchived holds the unique world-error code of this error
+const ErrCodeBucketIsArchived = 10008
+
+// HTTPError holds the http error description
+func (err *ErrBucketIsArchived) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeBucketIsArchived,
+		Message:  "This bucket is archived. Restore it to move tasks into it.",
+	}
+}
+
+// ErrCannotArchiveDefaultBucket represents an error where the default or done bucket of a view is archived.
+type ErrCannotArchiveDefaultBucket struct {
+	BucketID      int64
+	ProjectViewID int64
+}
+
+// IsErrCannotArchiveDefaultBucket checks if an error is ErrCannotArchiveDefaultBucket.
+func IsErrCannotArchiveDefaultBucket(err error) bool {
+	_, ok := err.(*ErrCannotArchiveDefaultBucket)
+	return ok
+}
+
+func (err *ErrCannotArchiveDefaultBucket) Error() string {
+	return fmt.Sprintf("Cannot archive the default or done bucket of a view [BucketID: %d, ProjectViewID: %d]", err.BucketID, err.ProjectViewID)
+}
+
+// ErrCodeCannotArchiveDefaultBucket holds the unique world-error code of this error
+const ErrCodeCannotArchiveDefaultBucket = 10009
+
+// HTTPError holds the http error description
+func (err *ErrCannotArchiveDefaultBucket) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeCannotArchiveDefaultBucket,
+		Message:  "You cannot archive the default or done bucket of a view.",
+	}
+}
+
+// ErrBucketAssigneeLimitExceeded represents an error where a task is moved into a bucket which already holds the
+// maximum number of tasks for one of its assignees.
+type ErrBucketAssigneeLimitExceeded struct {
+	BucketID int64
+	Limit    int64
+	TaskID   int64
+	UserID   int64
+}
+
+// IsErrBucketAssigneeLimitExceeded checks if an error is ErrBucketAssigneeLimitExceeded.
+func IsErrBucketAssigneeLimitExceeded(err error) bool {
+	_, ok := err.(*ErrBucketAssigneeLimitExceeded)
+	return ok
+}
+
+func (err *ErrBucketAssigneeLimitExceeded) Error() string {
+	return fmt.Sprintf("Cannot add a task to this bucket because it would exceed the limit per assignee [BucketID: %d, Limit: %d, TaskID: %d, UserID: %d]", err.BucketID, err.Limit, err.TaskID, err.UserID)
+}
+
+// ErrCodeBucketAssigneeLimitExceeded holds the unique world-error code of this error
+const ErrCodeBucketAssigneeLimitExceeded = 10010
+
+// HTTPError holds the http error description
+func (err *ErrBucketAssigneeLimitExceeded) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeBucketAssigneeLimitExceeded,
+		Message:  "You cannot add the task to this bucket as one of its assignees already has as many tasks in it as allowed.",
+	}
+}
+
+// ErrNotAllowedToMoveTaskInBucket represents an error where a user moves a task into or out of a bucket which
+// requires a higher right than the user has.
+type ErrNotAllowedToMoveTaskInBucket struct {
+	BucketID int64
+	TaskID   int64
+}
+
+// IsErrNotAllowedToMoveTaskInBucket checks if an error is ErrNotAllowedToMoveTaskInBucket.
+func IsErrNotAllowedToMoveTaskInBucket(err error) bool {
+	_, ok := err.(*ErrNotAllowedToMoveTaskInBucket)
+	return ok
+}
+
+func (err *ErrNotAllowedToMoveTaskInBucket) Error() string {
+	return fmt.Sprintf("Not allowed to move the task into or out of this bucket [BucketID: %d, TaskID: %d]", err.BucketID, err.TaskID)
+}
+
+// ErrCodeNotAllowedToMoveTaskInBucket holds the unique world-error code of this error
+const ErrCodeNotAllowedToMoveTaskInBucket = 10011
+
+// HTTPError holds the http error description
+func (err *ErrNotAllowedToMoveTaskInBucket) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusForbidden,
+		Code:     ErrCodeNotAllowedToMoveTaskInBucket,
+		Message:  "You are not allowed to move tasks into or out of this bucket.",
+	}
+}
+
 // =============
 // Saved Filters
 // =============
@@ -1650,53 +2922,137 @@ type ErrSavedFilterDoesNotExist struct {
 	SavedFilterID int64
 }
 
-// IsErrSavedFilterDoesNotExist checks if an error is ErrSavedFilterDoesNotExist.
-func IsErrSavedFilterDoesNotExist(err error) bool {
-	_, ok := err.(ErrSavedFilterDoesNotExist)
+// IsErrSavedFilterDoesNotExist checks if an error is ErrSavedFilterDoesNotExist.
+func IsErrSavedFilterDoesNotExist(err error) bool {
+	_, ok := err.(ErrSavedFilterDoesNotExist)
+	return ok
+}
+
+func (err ErrSavedFilterDoesNotExist) Error() string {
+	return fmt.Sprintf("Saved filter does not exist [SavedFilterID: %d]", err.SavedFilterID)
+}
+
+// ErrCodeSavedFilterDoesNotExist holds the unique world-error code of this error
+const ErrCodeSavedFilterDoesNotExist = 11001
+
+// HTTPError holds the http error description
+func (err ErrSavedFilterDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeSavedFilterDoesNotExist,
+		Message:  "This saved filter does not exist.",
+	}
+}
+
+// ErrSavedFilterNotAvailableForLinkShare represents an error where a kanban bucket does not exist
+type ErrSavedFilterNotAvailableForLinkShare struct {
+	SavedFilterID int64
+	LinkShareID   int64
+}
+
+// IsErrSavedFilterNotAvailableForLinkShare checks if an error is ErrSavedFilterNotAvailableForLinkShare.
+func IsErrSavedFilterNotAvailableForLinkShare(err error) bool {
+	_, ok := err.(ErrSavedFilterNotAvailableForLinkShare)
+	return ok
+}
+
+func (err ErrSavedFilterNotAvailableForLinkShare) Error() string {
+	return fmt.Sprintf("Saved filters are not available for link shares [SavedFilterID: %d, LinkShareID: %d]", err.SavedFilterID, err.LinkShareID)
+}
+
+// ErrCodeSavedFilterNotAvailableForLinkShare holds the unique world-error code of this error
+const ErrCodeSavedFilterNotAvailableForLinkShare = 11002
+
+// HTTPError holds the http error description
+func (err ErrSavedFilterNotAvailableForLinkShare) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeSavedFilterNotAvailableForLinkShare,
+		Message:  "Saved filters are not available for link shares.",
+	}
+}
+
+// ErrSavedFilterShareDoesNotExist represents an error where a saved filter share does not exist
+type ErrSavedFilterShareDoesNotExist struct {
+	ShareID int64
+}
+
+// IsErrSavedFilterShareDoesNotExist checks if an error is ErrSavedFilterShareDoesNotExist.
+func IsErrSavedFilterShareDoesNotExist(err error) bool {
+	_, ok := err.(ErrSavedFilterShareDoesNotExist)
+	return ok
+}
+
+func (err ErrSavedFilterShareDoesNotExist) Error() string {
+	return fmt.Sprintf("Saved filter share does not exist [ShareID: %d]", err.ShareID)
+}
+
+// ErrCodeSavedFilterShareDoesNotExist holds the unique world-error code of this error
+const ErrCodeSavedFilterShareDoesNotExist = 11003
+
+// HTTPError holds the http error description
+func (err ErrSavedFilterShareDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeSavedFilterShareDoesNotExist,
+		Message:  "The saved filter share does not exist.",
+	}
+}
+
+// ErrSavedFilterAlreadyShared represents an error where a saved filter is already shared with a team or project
+type ErrSavedFilterAlreadyShared struct {
+	SavedFilterID int64
+	TeamID        int64
+	ProjectID     int64
+}
+
+// IsErrSavedFilterAlreadyShared checks if an error is ErrSavedFilterAlreadyShared.
+func IsErrSavedFilterAlreadyShared(err error) bool {
+	_, ok := err.(ErrSavedFilterAlreadyShared)
 	return ok
 }
 
-func (err ErrSavedFilterDoesNotExist) Error() string {
-	return fmt.Sprintf("Saved filter does not exist [SavedFilterID: %d]", err.SavedFilterID)
+func (err ErrSavedFilterAlreadyShared) Error() string {
+	return fmt.Sprintf("Saved filter is already shared [SavedFilterID: %d, TeamID: %d, ProjectID: %d]", err.SavedFilterID, err.TeamID, err.ProjectID)
 }
 
-// ErrCodeSavedFilterDoesNotExist holds the unique world-error code of this error
-const ErrCodeSavedFilterDoesNotExist = 11001
+// ErrCodeSavedFilterAlreadyShared holds the unique world-error code of this error
+const ErrCodeSavedFilterAlreadyShared = 11004
 
 // HTTPError holds the http error description
-func (err ErrSavedFilterDoesNotExist) HTTPError() web.HTTPError {
+func (err ErrSavedFilterAlreadyShared) HTTPError() web.HTTPError {
 	return web.HTTPError{
-		HTTPCode: http.StatusNotFound,
-		Code:     ErrCodeSavedFilterDoesNotExist,
-		Message:  "This saved filter does not exist.",
+		HTTPCode: http.StatusConflict,
+		Code:     ErrCodeSavedFilterAlreadyShared,
+		Message:  "The saved filter is already shared with this team or project.",
 	}
 }
 
-// ErrSavedFilterNotAvailableForLinkShare represents an error where a kanban bucket does not exist
-type ErrSavedFilterNotAvailableForLinkShare struct {
-	SavedFilterID int64
-	LinkShareID   int64
+// ErrInvalidSavedFilterShare represents an error where a saved filter share does not have exactly one target
+type ErrInvalidSavedFilterShare struct {
+	TeamID    int64
+	ProjectID int64
 }
 
-// IsErrSavedFilterNotAvailableForLinkShare checks if an error is ErrSavedFilterNotAvailableForLinkShare.
-func IsErrSavedFilterNotAvailableForLinkShare(err error) bool {
-	_, ok := err.(ErrSavedFilterNotAvailableForLinkShare)
+// IsErrInvalidSavedFilterShare checks if an error is ErrInvalidSavedFilterShare.
+func IsErrInvalidSavedFilterShare(err error) bool {
+	_, ok := err.(ErrInvalidSavedFilterShare)
 	return ok
 }
 
-func (err ErrSavedFilterNotAvailableForLinkShare) Error() string {
-	return fmt.Sprintf("Saved filters are not available for link shares [SavedFilterID: %d, LinkShareID: %d]", err.SavedFilterID, err.LinkShareID)
+func (err ErrInvalidSavedFilterShare) Error() string {
+	return fmt.Sprintf("Saved filter share must have either a team or a project [TeamID: %d, ProjectID: %d]", err.TeamID, err.ProjectID)
 }
 
-// ErrCodeSavedFilterNotAvailableForLinkShare holds the unique world-error code of this error
-const ErrCodeSavedFilterNotAvailableForLinkShare = 11002
+// ErrCodeInvalidSavedFilterShare holds the unique world-error code of this error
+const ErrCodeInvalidSavedFilterShare = 11005
 
 // HTTPError holds the http error description
-func (err ErrSavedFilterNotAvailableForLinkShare) HTTPError() web.HTTPError {
+func (err ErrInvalidSavedFilterShare) HTTPError() web.HTTPError {
 	return web.HTTPError{
-		HTTPCode: http.StatusPreconditionFailed,
-		Code:     ErrCodeSavedFilterNotAvailableForLinkShare,
-		Message:  "Saved filters are not available for link shares.",
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidSavedFilterShare,
+		Message:  "A saved filter can be shared either with a team or with a project.",
 	}
 }
 
@@ -1844,6 +3200,35 @@ func (err ErrLinkShareTokenInvalid) HTTPError() web.HTTPError {
 	}
 }
 
+// ErrUserLinkShareQuotaExceeded represents an error where a user can't create another link share because they
+// reached the limit of link shares per user
+type ErrUserLinkShareQuotaExceeded struct {
+	UserID int64
+	Limit  int64
+}
+
+// IsErrUserLinkShareQuotaExceeded checks if an error is ErrUserLinkShareQuotaExceeded.
+func IsErrUserLinkShareQuotaExceeded(err error) bool {
+	_, ok := err.(*ErrUserLinkShareQuotaExceeded)
+	return ok
+}
+
+func (err *ErrUserLinkShareQuotaExceeded) Error() string {
+	return fmt.Sprintf("User link share quota exceeded [UserID: %d, Limit: %d]", err.UserID, err.Limit)
+}
+
+// ErrCodeUserLinkShareQuotaExceeded holds the unique world-error code of this error
+const ErrCodeUserLinkShareQuotaExceeded = 13004
+
+// HTTPError holds the http error description
+func (err *ErrUserLinkShareQuotaExceeded) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeUserLinkShareQuotaExceeded,
+		Message:  fmt.Sprintf("You have reached your limit of %d link shares. Delete a link share to create a new one.", err.Limit),
+	}
+}
+
 // ================
 // API Token Errors
 // ================
@@ -1901,3 +3286,394 @@ func (err ErrInvalidAPITokenPermission) HTTPError() web.HTTPError {
 		Message:  fmt.Sprintf("The permission %s of group %s is invalid.", err.Permission, err.Group),
 	}
 }
+
+// ================
+// Task Form Errors
+// ================
+
+// ErrTaskFormDoesNotExist represents an error where a task form does not exist
+type ErrTaskFormDoesNotExist struct {
+	ID   int64
+	Hash string
+}
+
+// IsErrTaskFormDoesNotExist checks if an error is ErrTaskFormDoesNotExist.
+func IsErrTaskFormDoesNotExist(err error) bool {
+	_, ok := err.(*ErrTaskFormDoesNotExist)
+	return ok
+}
+
+func (err *ErrTaskFormDoesNotExist) Error() string {
+	return fmt.Sprintf("Task form does not exist [ID: %d, Hash: %s]", err.ID, err.Hash)
+}
+
+// ErrCodeTaskFormDoesNotExist holds the unique world-error code of this error
+const ErrCodeTaskFormDoesNotExist = 15001
+
+// HTTPError holds the http error description
+func (err *ErrTaskFormDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeTaskFormDoesNotExist,
+		Message:  "This task form does not exist.",
+	}
+}
+
+// ErrTaskFormCaptchaInvalid represents an error where the captcha of a form submission could not be verified
+type ErrTaskFormCaptchaInvalid struct {
+	FormID int64
+}
+
+// IsErrTaskFormCaptchaInvalid checks if an error is ErrTaskFormCaptchaInvalid.
+func IsErrTaskFormCaptchaInvalid(err error) bool {
+	_, ok := err.(*ErrTaskFormCaptchaInvalid)
+	return ok
+}
+
+func (err *ErrTaskFormCaptchaInvalid) Error() string {
+	return fmt.Sprintf("Task form captcha is invalid [FormID: %d]", err.FormID)
+}
+
+// ErrCodeTaskFormCaptchaInvalid holds the unique world-error code of this error
+const ErrCodeTaskFormCaptchaInvalid = 15002
+
+// HTTPError holds the http error description
+func (err *ErrTaskFormCaptchaInvalid) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeTaskFormCaptchaInvalid,
+		Message:  "The captcha is missing or invalid.",
+	}
+}
+
+// ErrTaskFormCaptchaNotConfigured represents an error where a form requires a captcha but none is configured
+type ErrTaskFormCaptchaNotConfigured struct{}
+
+// IsErrTaskFormCaptchaNotConfigured checks if an error is ErrTaskFormCaptchaNotConfigured.
+func IsErrTaskFormCaptchaNotConfigured(err error) bool {
+	_, ok := err.(*ErrTaskFormCaptchaNotConfigured)
+	return ok
+}
+
+func (err *ErrTaskFormCaptchaNotConfigured) Error() string {
+	return "Task form requires a captcha but no captcha secret is configured"
+}
+
+// ErrCodeTaskFormCaptchaNotConfigured holds the unique world-error code of this error
+const ErrCodeTaskFormCaptchaNotConfigured = 15003
+
+// HTTPError holds the http error description
+func (err *ErrTaskFormCaptchaNotConfigured) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeTaskFormCaptchaNotConfigured,
+		Message:  "Captchas are not configured on this instance.",
+	}
+}
+
+// ===============
+// Task SLA Errors
+// ===============
+
+// ErrTaskSLARuleDoesNotExist represents an error where a task sla rule does not exist
+type ErrTaskSLARuleDoesNotExist struct {
+	ID int64
+}
+
+// IsErrTaskSLARuleDoesNotExist checks if an error is ErrTaskSLARuleDoesNotExist.
+func IsErrTaskSLARuleDoesNotExist(err error) bool {
+	_, ok := err.(*ErrTaskSLARuleDoesNotExist)
+	return ok
+}
+
+func (err *ErrTaskSLARuleDoesNotExist) Error() string {
+	return fmt.Sprintf("Task sla rule does not exist [ID: %d]", err.ID)
+}
+
+// ErrCodeTaskSLARuleDoesNotExist holds the unique world-error code of this error
+const ErrCodeTaskSLARuleDoesNotExist = 16001
+
+// HTTPError holds the http error description
+func (err *ErrTaskSLARuleDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeTaskSLARuleDoesNotExist,
+		Message:  "This sla rule does not exist.",
+	}
+}
+
+// ErrTaskSLARuleHasNoAction represents an error where a task sla rule would not do anything when it is breached
+type ErrTaskSLARuleHasNoAction struct{}
+
+// IsErrTaskSLARuleHasNoAction checks if an error is ErrTaskSLARuleHasNoAction.
+func IsErrTaskSLARuleHasNoAction(err error) bool {
+	_, ok := err.(*ErrTaskSLARuleHasNoAction)
+	return ok
+}
+
+func (err *ErrTaskSLARuleHasNoAction) Error() string {
+	return "Task sla rule has no escalation action"
+}
+
+// ErrCodeTaskSLARuleHasNoAction holds the unique world-error code of this error
+const ErrCodeTaskSLARuleHasNoAction = 16002
+
+// HTTPError holds the http error description
+func (err *ErrTaskSLARuleHasNoAction) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeTaskSLARuleHasNoAction,
+		Message:  "An sla rule needs at least one escalation action.",
+	}
+}
+
+// ===================
+// Email Intake Errors
+// ===================
+
+// ErrProjectEmailIntakeDoesNotExist represents an error where a project has no email intake address
+type ErrProjectEmailIntakeDoesNotExist struct {
+	ProjectID int64
+}
+
+// IsErrProjectEmailIntakeDoesNotExist checks if an error is ErrProjectEmailIntakeDoesNotExist.
+func IsErrProjectEmailIntakeDoesNotExist(err error) bool {
+	_, ok := err.(*ErrProjectEmailIntakeDoesNotExist)
+	return ok
+}
+
+func (err *ErrProjectEmailIntakeDoesNotExist) Error() string {
+	return fmt.Sprintf("Project email intake does not exist [ProjectID: %d]", err.ProjectID)
+}
+
+// ErrCodeProjectEmailIntakeDoesNotExist holds the unique world-error code of this error
+const ErrCodeProjectEmailIntakeDoesNotExist = 17001
+
+// HTTPError holds the http error description
+func (err *ErrProjectEmailIntakeDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeProjectEmailIntakeDoesNotExist,
+		Message:  "This project does not have an email intake address.",
+	}
+}
+
+// ErrEmailIntakeNotConfigured represents an error where email intake is enabled but no domain or secret is configured
+type ErrEmailIntakeNotConfigured struct{}
+
+// IsErrEmailIntakeNotConfigured checks if an error is ErrEmailIntakeNotConfigured.
+func IsErrEmailIntakeNotConfigured(err error) bool {
+	_, ok := err.(*ErrEmailIntakeNotConfigured)
+	return ok
+}
+
+func (err *ErrEmailIntakeNotConfigured) Error() string {
+	return "Email intake domain or secret is not configured"
+}
+
+// ErrCodeEmailIntakeNotConfigured holds the unique world-error code of this error
+const ErrCodeEmailIntakeNotConfigured = 17002
+
+// HTTPError holds the http error description
+func (err *ErrEmailIntakeNotConfigured) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeEmailIntakeNotConfigured,
+		Message:  "Email intake is not configured on this instance.",
+	}
+}
+
+// ErrInvalidIntakeEmail represents an error where a forwarded mail could not be turned into a task
+type ErrInvalidIntakeEmail struct {
+	Reason string
+}
+
+// IsErrInvalidIntakeEmail checks if an error is ErrInvalidIntakeEmail.
+func IsErrInvalidIntakeEmail(err error) bool {
+	_, ok := err.(*ErrInvalidIntakeEmail)
+	return ok
+}
+
+func (err *ErrInvalidIntakeEmail) Error() string {
+	return fmt.Sprintf("Invalid intake email [Reason: %s]", err.Reason)
+}
+
+// ErrCodeInvalidIntakeEmail holds the unique world-error code of this error
+const ErrCodeInvalidIntakeEmail = 17003
+
+// HTTPError holds the http error description
+func (err *ErrInvalidIntakeEmail) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidIntakeEmail,
+		Message:  "The email could not be processed: " + err.Reason,
+	}
+}
+
+// ============
+// Inbox errors
+// ============
+
+// ErrInboxItemDoesNotExist represents an error where an inbox item does not exist
+type ErrInboxItemDoesNotExist struct {
+	ItemID int64
+}
+
+// IsErrInboxItemDoesNotExist checks if an error is ErrInboxItemDoesNotExist.
+func IsErrInboxItemDoesNotExist(err error) bool {
+	_, ok := err.(ErrInboxItemDoesNotExist)
+	return ok
+}
+
+func (err ErrInboxItemDoesNotExist) Error() string {
+	return fmt.Sprintf("Inbox item does not exist [ItemID: %d]", err.ItemID)
+}
+
+// ErrCodeInboxItemDoesNotExist holds the unique world-error code of this error
+const ErrCodeInboxItemDoesNotExist = 18001
+
+// HTTPError holds the http error description
+func (err ErrInboxItemDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeInboxItemDoesNotExist,
+		Message:  "This inbox item does not exist.",
+	}
+}
+
+// =======================
+// Chat integration errors
+// =======================
+
+// ErrChatIntegrationDoesNotExist represents an error where a chat integration does not exist
+type ErrChatIntegrationDoesNotExist struct {
+	ID int64
+}
+
+// IsErrChatIntegrationDoesNotExist checks if an error is ErrChatIntegrationDoesNotExist.
+func IsErrChatIntegrationDoesNotExist(err error) bool {
+	_, ok := err.(ErrChatIntegrationDoesNotExist)
+	return ok
+}
+
+func (err ErrChatIntegrationDoesNotExist) Error() string {
+	return fmt.Sprintf("Chat integration does not exist [ID: %d]", err.ID)
+}
+
+// ErrCodeChatIntegrationDoesNotExist holds the unique world-error code of this error
+const ErrCodeChatIntegrationDoesNotExist = 19001
+
+// HTTPError holds the http error description
+func (err ErrChatIntegrationDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeChatIntegrationDoesNotExist,
+		Message:  "This chat integration does not exist.",
+	}
+}
+
+// ErrInvalidChatIntegrationType represents an error where a chat integration uses a service which is not supported
+type ErrInvalidChatIntegrationType struct {
+	Type string
+}
+
+// IsErrInvalidChatIntegrationType checks if an error is ErrInvalidChatIntegrationType.
+func IsErrInvalidChatIntegrationType(err error) bool {
+	_, ok := err.(ErrInvalidChatIntegrationType)
+	return ok
+}
+
+func (err ErrInvalidChatIntegrationType) Error() string {
+	return fmt.Sprintf("Invalid chat integration type [Type: %s]", err.Type)
+}
+
+// ErrCodeInvalidChatIntegrationType holds the unique world-error code of this error
+const ErrCodeInvalidChatIntegrationType = 19002
+
+// HTTPError holds the http error description
+func (err ErrInvalidChatIntegrationType) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidChatIntegrationType,
+		Message:  fmt.Sprintf("The chat service %s is not supported.", err.Type),
+	}
+}
+
+// ErrTelegramChatDoesNotExist represents an error where a user has not linked a telegram chat
+type ErrTelegramChatDoesNotExist struct {
+	UserID int64
+}
+
+// IsErrTelegramChatDoesNotExist checks if an error is ErrTelegramChatDoesNotExist.
+func IsErrTelegramChatDoesNotExist(err error) bool {
+	_, ok := err.(ErrTelegramChatDoesNotExist)
+	return ok
+}
+
+func (err ErrTelegramChatDoesNotExist) Error() string {
+	return fmt.Sprintf("Telegram chat does not exist [UserID: %d]", err.UserID)
+}
+
+// ErrCodeTelegramChatDoesNotExist holds the unique world-error code of this error
+const ErrCodeTelegramChatDoesNotExist = 19003
+
+// HTTPError holds the http error description
+func (err ErrTelegramChatDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeTelegramChatDoesNotExist,
+		Message:  "You have not linked a telegram account yet.",
+	}
+}
+
+// ErrPushSubscriptionDoesNotExist represents an error where a push subscription does not exist
+type ErrPushSubscriptionDoesNotExist struct {
+	ID int64
+}
+
+// IsErrPushSubscriptionDoesNotExist checks if an error is ErrPushSubscriptionDoesNotExist.
+func IsErrPushSubscriptionDoesNotExist(err error) bool {
+	_, ok := err.(ErrPushSubscriptionDoesNotExist)
+	return ok
+}
+
+func (err ErrPushSubscriptionDoesNotExist) Error() string {
+	return fmt.Sprintf("Push subscription does not exist [ID: %d]", err.ID)
+}
+
+// ErrCodePushSubscriptionDoesNotExist holds the unique world-error code of this error
+const ErrCodePushSubscriptionDoesNotExist = 19004
+
+// HTTPError holds the http error description
+func (err ErrPushSubscriptionDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodePushSubscriptionDoesNotExist,
+		Message:  "This push subscription does not exist.",
+	}
+}
+
+// ErrInvalidTaskActionToken represents an error where a task action link is invalid or expired
+type ErrInvalidTaskActionToken struct{}
+
+// IsErrInvalidTaskActionToken checks if an error is ErrInvalidTaskActionToken.
+func IsErrInvalidTaskActionToken(err error) bool {
+	_, ok := err.(*ErrInvalidTaskActionToken)
+	return ok
+}
+
+func (err *ErrInvalidTaskActionToken) Error() string {
+	return "Task action token is invalid"
+}
+
+// ErrCodeInvalidTaskActionToken holds the unique world-error code of this error
+const ErrCodeInvalidTaskActionToken = 19005
+
+// HTTPError holds the http error description
+func (err *ErrInvalidTaskActionToken) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusBadRequest,
+		Code:     ErrCodeInvalidTaskActionToken,
+		Message:  "This link is invalid or has expired.",
+	}
+}
@@ -166,6 +166,89 @@ func (t *TaskRelationDeletedEvent) Name() string {
 	return "task.relation.deleted"
 }
 
+// TaskApprovalRequestedEvent represents an event where a task was marked as done and now waits for approval
+type TaskApprovalRequestedEvent struct {
+	Task *Task      `json:"task"`
+	Doer *user.User `json:"doer"`
+}
+
+// Name defines the name for TaskApprovalRequestedEvent
+func (t *TaskApprovalRequestedEvent) Name() string {
+	return "task.approval.requested"
+}
+
+// TaskApprovedEvent represents an event where an approver approved a task being done
+type TaskApprovedEvent struct {
+	Task   *Task      `json:"task"`
+	Doer   *user.User `json:"doer"`
+	Reason string     `json:"reason"`
+}
+
+// Name defines the name for TaskApprovedEvent
+func (t *TaskApprovedEvent) Name() string {
+	return "task.approval.approved"
+}
+
+// TaskRejectedEvent represents an event where an approver rejected a task being done
+type TaskRejectedEvent struct {
+	Task   *Task      `json:"task"`
+	Doer   *user.User `json:"doer"`
+	Reason string     `json:"reason"`
+}
+
+// Name defines the name for TaskRejectedEvent
+func (t *TaskRejectedEvent) Name() string {
+	return "task.approval.rejected"
+}
+
+// TaskPriorityAgedEvent represents an event where the priority of a task was raised because its due date came closer
+type TaskPriorityAgedEvent struct {
+	Task        *Task `json:"task"`
+	OldPriority int64 `json:"old_priority"`
+	NewPriority int64 `json:"new_priority"`
+}
+
+// Name defines the name for TaskPriorityAgedEvent
+func (t *TaskPriorityAgedEvent) Name() string {
+	return "task.priority.aged"
+}
+
+// TaskMarkedDoneEvent represents an event where a task has been marked as done
+type TaskMarkedDoneEvent struct {
+	Task *Task      `json:"task"`
+	Doer *user.User `json:"doer"`
+}
+
+// Name defines the name for TaskMarkedDoneEvent
+func (t *TaskMarkedDoneEvent) Name() string {
+	return "task.done"
+}
+
+// TaskMovedToBucketEvent represents an event where a task has been moved into another kanban bucket
+type TaskMovedToBucketEvent struct {
+	Task   *Task      `json:"task"`
+	Bucket *Bucket    `json:"bucket"`
+	Doer   *user.User `json:"doer"`
+}
+
+// Name defines the name for TaskMovedToBucketEvent
+func (t *TaskMovedToBucketEvent) Name() string {
+	return "task.bucket.moved"
+}
+
+// TaskBucketActionsExecutedEvent represents an event where the actions of a bucket ran on a task moved into it
+type TaskBucketActionsExecutedEvent struct {
+	Task    *Task           `json:"task"`
+	Bucket  *Bucket         `json:"bucket"`
+	Actions []*BucketAction `json:"actions"`
+	Doer    *user.User      `json:"doer"`
+}
+
+// Name defines the name for TaskBucketActionsExecutedEvent
+func (t *TaskBucketActionsExecutedEvent) Name() string {
+	return "task.bucket.actions.executed"
+}
+
 ////////////////////
 // Project Events //
 ////////////////////
@@ -203,6 +286,18 @@ func (p *ProjectDeletedEvent) Name() string {
 	return "project.deleted"
 }
 
+// ProjectTransferRequestedEvent represents an event where the owner of a project wants to hand it over to another user
+type ProjectTransferRequestedEvent struct {
+	Project *Project   `json:"project"`
+	From    *user.User `json:"from"`
+	To      *user.User `json:"to"`
+}
+
+// Name defines the name for ProjectTransferRequestedEvent
+func (p *ProjectTransferRequestedEvent) Name() string {
+	return "project.transfer.requested"
+}
+
 ////////////////////
 // Sharing Events //
 ////////////////////
@@ -269,6 +364,18 @@ func (t *TeamDeletedEvent) Name() string {
 	return "team.deleted"
 }
 
+// ProjectDuplicationRequestedEvent represents a ProjectDuplicationRequestedEvent event
+type ProjectDuplicationRequestedEvent struct {
+	Job       *ProjectDuplicateJob `json:"job"`
+	Duplicate *ProjectDuplicate    `json:"duplicate"`
+	Doer      *user.User           `json:"doer"`
+}
+
+// Name defines the name for ProjectDuplicationRequestedEvent
+func (t *ProjectDuplicationRequestedEvent) Name() string {
+	return "project.duplication.requested"
+}
+
 // UserDataExportRequestedEvent represents a UserDataExportRequestedEvent event
 type UserDataExportRequestedEvent struct {
 	User *user.User `json:"user"`
@@ -36,6 +36,7 @@ import (
 	"code.vikunja.io/api/pkg/user"
 	"code.vikunja.io/api/pkg/utils"
 	"code.vikunja.io/api/pkg/version"
+	"code.vikunja.io/web"
 	"xorm.io/xorm"
 )
 
@@ -78,6 +79,11 @@ func ExportUserData(s *xorm.Session, u *user.User) (err error) {
 	if err != nil {
 		return err
 	}
+	// Icon files
+	err = exportProjectIcons(s, u, dumpWriter)
+	if err != nil {
+		return err
+	}
 	// Vikunja Version
 	err = utils.WriteBytesToZip("VERSION", []byte(version.Version), dumpWriter)
 	if err != nil {
@@ -143,6 +149,12 @@ func exportProjectsAndTasks(s *xorm.Session, u *user.User, wr *zip.Writer) (task
 		return
 	}
 
+	return writeProjectsAndTasksToZip(s, u, rawProjects, wr)
+}
+
+// writeProjectsAndTasksToZip writes the projects with all their views, tasks, comments, buckets and positions
+// into the data.json file of an export.
+func writeProjectsAndTasksToZip(s *xorm.Session, a web.Auth, rawProjects []*Project, wr *zip.Writer) (taskIDs []int64, err error) {
 	projects := []*ProjectWithTasksAndBuckets{}
 	projectsMap := make(map[int64]*ProjectWithTasksAndBuckets, len(rawProjects))
 	projectIDs := []int64{}
@@ -170,7 +182,7 @@ func exportProjectsAndTasks(s *xorm.Session, u *user.User, wr *zip.Writer) (task
 		viewIDs = append(viewIDs, v.ID)
 	}
 
-	tasks, _, _, err := getTasksForProjects(s, rawProjects, u, &taskSearchOptions{
+	tasks, _, _, err := getTasksForProjects(s, rawProjects, a, &taskSearchOptions{
 		page:    0,
 		perPage: -1,
 	}, nil)
@@ -296,6 +308,9 @@ func exportTaskAttachments(s *xorm.Session, wr *zip.Writer, taskIDs []int64) (er
 
 	attachmentFiles := make(map[int64]io.ReadCloser)
 	for _, ta := range tas {
+		if ta.IsLink() || ta.File == nil {
+			continue
+		}
 		err = ta.File.LoadFileByID()
 		if err != nil {
 			var pathError *fs.PathError
@@ -336,6 +351,25 @@ func exportProjectBackgrounds(s *xorm.Session, u *user.User, wr *zip.Writer) (er
 		return err
 	}
 
+	return writeProjectBackgroundsToZip(projects, wr)
+}
+
+func exportProjectIcons(s *xorm.Session, u *user.User, wr *zip.Writer) (err error) {
+	projects, _, _, err := getRawProjectsForUser(
+		s,
+		&projectOptions{
+			user: u,
+			page: -1,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return writeProjectIconsToZip(projects, wr)
+}
+
+func writeProjectBackgroundsToZip(projects []*Project, wr *zip.Writer) (err error) {
 	backgroundFiles := make(map[int64]io.ReadCloser)
 	for _, l := range projects {
 		if l.BackgroundFileID == 0 {
@@ -0,0 +1,154 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/log"
+
+	"github.com/c2h5oh/datasize"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// fileReferences holds all columns which reference a file. A file which is not referenced in any of them is orphaned.
+var fileReferences = []struct {
+	table  string
+	column string
+}{
+	{"task_attachments", "file_id"},
+	{"task_attachment_versions", "file_id"},
+	{"projects", "background_file_id"},
+	{"projects", "icon_file_id"},
+	{"users", "avatar_file_id"},
+	{"users", "export_file_id"},
+}
+
+// OrphanedFilesCleanupResult holds what a cleanup of orphaned files deleted
+type OrphanedFilesCleanupResult struct {
+	// The number of deleted files.
+	Deleted int
+	// How many bytes were freed in the file storage. Files which shared their content with files which are still
+	// used don't free anything.
+	ReclaimedBytes uint64
+}
+
+func getOrphanedFiles(s *xorm.Session, createdBefore time.Time) (orphans []*files.File, err error) {
+	var cond builder.Cond = builder.Lt{"created": createdBefore}
+	for _, ref := range fileReferences {
+		cond = cond.And(builder.NotIn("id",
+			builder.
+				Select(ref.column).
+				From(ref.table).
+				Where(builder.NotNull{ref.column}),
+		))
+	}
+
+	orphans = []*files.File{}
+	err = s.Where(cond).OrderBy("id asc").Find(&orphans)
+	return
+}
+
+// CleanupOrphanedFiles deletes all files which are not used anymore and were created before the grace period.
+// With dryRun, the files are only counted.
+func CleanupOrphanedFiles(s *xorm.Session, gracePeriod time.Duration, dryRun bool) (result *OrphanedFilesCleanupResult, err error) {
+	orphans, err := getOrphanedFiles(s, time.Now().Add(-gracePeriod))
+	if err != nil {
+		return nil, err
+	}
+
+	result = &OrphanedFilesCleanupResult{}
+	// How many files still use the content with a hash, the content is only freed with the last of them
+	contentReferences := make(map[string]int64)
+
+	for _, f := range orphans {
+		reclaimed := true
+		if f.Sha256 != "" {
+			if _, counted := contentReferences[f.Sha256]; !counted {
+				contentReferences[f.Sha256], err = s.Where("sha256 = ?", f.Sha256).Count(&files.File{})
+				if err != nil {
+					return nil, err
+				}
+			}
+			contentReferences[f.Sha256]--
+			reclaimed = contentReferences[f.Sha256] <= 0
+		}
+
+		if !dryRun {
+			err = f.Delete()
+			if err != nil && !files.IsErrFileDoesNotExist(err) {
+				return nil, err
+			}
+
+			_, err = s.Where("file_id = ?", f.ID).Delete(&UnsplashPhoto{})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		result.Deleted++
+		if reclaimed {
+			result.ReclaimedBytes += f.Size
+		}
+	}
+
+	return result, nil
+}
+
+// HumanReadableReclaimed returns the reclaimed space like "1.5 MB"
+func (r *OrphanedFilesCleanupResult) HumanReadableReclaimed() string {
+	return datasize.ByteSize(r.ReclaimedBytes).HumanReadable()
+}
+
+// RegisterOrphanedFilesCleanupCron deletes files which are not used anymore once a day
+func RegisterOrphanedFilesCleanupCron() {
+	if !config.FilesCleanupEnabled.GetBool() {
+		return
+	}
+
+	const logPrefix = "[Orphaned Files Cleanup Cron] "
+
+	err := cron.Schedule("30 3 * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		gracePeriod := time.Duration(config.FilesCleanupGracePeriod.GetInt()) * time.Hour
+		result, err := CleanupOrphanedFiles(s, gracePeriod, false)
+		if err != nil {
+			log.Errorf(logPrefix+"Could not clean up orphaned files: %s", err)
+			_ = s.Rollback()
+			return
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf(logPrefix+"Could not commit: %s", err)
+			return
+		}
+
+		if result.Deleted > 0 {
+			log.Infof(logPrefix+"Deleted %d orphaned files, reclaimed %s", result.Deleted, result.HumanReadableReclaimed())
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register orphaned files cleanup cron: %s", err)
+	}
+}
@@ -0,0 +1,99 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanupOrphanedFiles(t *testing.T) {
+	u := &user.User{ID: 1}
+	// Makes files created just now count as old enough
+	const noGracePeriod = -time.Hour
+
+	t.Run("dry run", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		orphan, err := files.Create(strings.NewReader("orphaned"), "orphan.txt", 8, u)
+		require.NoError(t, err)
+
+		result, err := CleanupOrphanedFiles(s, noGracePeriod, true)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Deleted)
+		assert.Equal(t, uint64(8), result.ReclaimedBytes)
+
+		require.NoError(t, s.Commit())
+		db.AssertExists(t, "files", map[string]interface{}{
+			"id": orphan.ID,
+		}, false)
+	})
+	t.Run("deletes only orphaned files", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		orphan, err := files.Create(strings.NewReader("orphaned"), "orphan.txt", 8, u)
+		require.NoError(t, err)
+		ta := &TaskAttachment{TaskID: 1}
+		err = ta.NewAttachment(s, io.NopCloser(strings.NewReader("orphaned")), "used.txt", 8, u)
+		require.NoError(t, err)
+
+		result, err := CleanupOrphanedFiles(s, noGracePeriod, false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Deleted)
+		// The content is still used by the attachment
+		assert.Equal(t, uint64(0), result.ReclaimedBytes)
+
+		require.NoError(t, s.Commit())
+		db.AssertMissing(t, "files", map[string]interface{}{
+			"id": orphan.ID,
+		})
+		db.AssertExists(t, "files", map[string]interface{}{
+			"id": ta.FileID,
+		}, false)
+		db.AssertExists(t, "files", map[string]interface{}{
+			"id": 1,
+		}, false)
+	})
+	t.Run("keeps new files", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := files.Create(strings.NewReader("orphaned"), "orphan.txt", 8, u)
+		require.NoError(t, err)
+
+		result, err := CleanupOrphanedFiles(s, time.Hour, false)
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Deleted)
+	})
+}
@@ -0,0 +1,330 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// InboxItemKind is the reason something ended up in the inbox of a user
+type InboxItemKind string
+
+const (
+	// The user was assigned to a task
+	InboxItemKindAssigned InboxItemKind = "assigned"
+	// The user was mentioned in the description of a task or in a comment
+	InboxItemKindMentioned InboxItemKind = "mentioned"
+	// A project was shared with the user or with one of their teams
+	InboxItemKindProjectShared InboxItemKind = "project_shared"
+)
+
+// InboxItem is one entry in the inbox of a user, which collects everything the user might need to act on across
+// all projects.
+type InboxItem struct {
+	// The unique, numeric id of this inbox item.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"inboxitem"`
+	// The user this item belongs to.
+	UserID int64 `xorm:"bigint not null INDEX" json:"-"`
+	// Why this item is in the inbox. Either `assigned`, `mentioned` or `project_shared`.
+	Kind InboxItemKind `xorm:"varchar(50) not null" json:"kind"`
+	// The task this item is about. 0 for shared projects.
+	TaskID int64 `xorm:"bigint null INDEX" json:"task_id"`
+	// The comment the user was mentioned in. 0 if they were mentioned in the task description.
+	CommentID int64 `xorm:"bigint null" json:"comment_id"`
+	// The project this item is about. For tasks, this is the project of the task.
+	ProjectID int64 `xorm:"bigint null INDEX" json:"project_id"`
+	DoerID    int64 `xorm:"bigint null" json:"-"`
+
+	// The task this item is about.
+	Task *Task `xorm:"-" json:"task,omitempty"`
+	// The project this item is about.
+	Project *Project `xorm:"-" json:"project,omitempty"`
+	// The user who assigned, mentioned or shared.
+	Doer *user.User `xorm:"-" json:"doer"`
+
+	// Whether this item is read. Set this to mark the item as read or unread.
+	Read bool `xorm:"-" json:"read"`
+	// When this item was marked as read.
+	ReadAt time.Time `xorm:"datetime null" json:"read_at"`
+	// A timestamp when this item was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+
+	// If true, only unread items are returned.
+	UnreadOnly bool `xorm:"-" json:"-" query:"unread"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for inbox items
+func (*InboxItem) TableName() string {
+	return "inbox_items"
+}
+
+// addToInbox adds an item to the inbox of a user, unless the same thing is already in there.
+func addToInbox(s *xorm.Session, item *InboxItem) (err error) {
+	if item.UserID == item.DoerID {
+		// Nobody needs to be told about what they did themselves
+		return nil
+	}
+
+	exists, err := s.
+		Where("user_id = ? AND kind = ? AND task_id = ? AND comment_id = ? AND project_id = ?",
+			item.UserID, item.Kind, item.TaskID, item.CommentID, item.ProjectID).
+		Exist(&InboxItem{})
+	if err != nil || exists {
+		return err
+	}
+
+	_, err = s.Insert(item)
+	return
+}
+
+// ReadAll returns the inbox of the current user
+// @Summary Get the inbox of the current user
+// @Description Returns the tasks the current user was recently assigned to or mentioned in and the projects recently shared with them, across all projects, newest first.
+// @tags user
+// @Produce json
+// @Security JWTKeyAuth
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Param unread query bool false "If true, only unread items are returned."
+// @Success 200 {array} models.InboxItem "The inbox items"
+// @Failure 403 {object} web.HTTPError "Link shares don't have an inbox."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /user/inbox [get]
+func (item *InboxItem) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	u, err := user.GetFromAuth(a)
+	if err != nil {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	query := s.Where("user_id = ?", u.ID)
+	if item.UnreadOnly {
+		query = query.And("read_at IS NULL")
+	}
+
+	limit, start := getLimitFromPageIndex(page, perPage)
+	if limit > 0 {
+		query = query.Limit(limit, start)
+	}
+
+	items := []*InboxItem{}
+	numberOfTotalItems, err = query.OrderBy("id desc").FindAndCount(&items)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	items, err = addDetailsToInboxItems(s, u, items)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return items, len(items), numberOfTotalItems, nil
+}
+
+// addDetailsToInboxItems adds the tasks, projects and doers to inbox items. Items about things the user can't see
+// anymore, for example because the project was unshared again, are left out.
+func addDetailsToInboxItems(s *xorm.Session, u *user.User, items []*InboxItem) (visible []*InboxItem, err error) {
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	taskIDs := []int64{}
+	projectIDs := []int64{}
+	userIDs := []int64{}
+	for _, i := range items {
+		if i.TaskID != 0 {
+			taskIDs = append(taskIDs, i.TaskID)
+		}
+		projectIDs = append(projectIDs, i.ProjectID)
+		if i.DoerID != 0 {
+			userIDs = append(userIDs, i.DoerID)
+		}
+	}
+
+	tasks, err := GetTasksSimpleByIDs(s, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	taskMap := make(map[int64]*Task, len(tasks))
+	for _, t := range tasks {
+		taskMap[t.ID] = t
+		projectIDs = append(projectIDs, t.ProjectID)
+	}
+
+	projects, err := GetProjectsMapByIDs(s, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	doers, err := user.GetUsersByIDs(s, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	canRead := make(map[int64]bool, len(projects))
+	visible = make([]*InboxItem, 0, len(items))
+	for _, i := range items {
+		projectID := i.ProjectID
+		if i.TaskID != 0 {
+			t, has := taskMap[i.TaskID]
+			if !has {
+				continue
+			}
+			i.Task = t
+			projectID = t.ProjectID
+		}
+
+		can, checked := canRead[projectID]
+		if !checked {
+			can, _, err = (&Project{ID: projectID}).CanRead(s, u)
+			if err != nil && !IsErrProjectDoesNotExist(err) {
+				return nil, err
+			}
+			canRead[projectID] = can
+		}
+		if !can {
+			continue
+		}
+
+		i.Project = projects[projectID]
+		i.Doer = doers[i.DoerID]
+		i.Read = !i.ReadAt.IsZero()
+		visible = append(visible, i)
+	}
+
+	return visible, nil
+}
+
+// Update marks an inbox item as read or unread
+// @Summary Mark an inbox item as (un-)read
+// @Description Marks an item in the inbox of the current user as read or unread.
+// @tags user
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Inbox item ID"
+// @Param item body models.InboxItem true "The item with the read state."
+// @Success 200 {object} models.InboxItem "The updated inbox item."
+// @Failure 403 {object} web.HTTPError "The item does not belong to the current user."
+// @Failure 404 {object} web.HTTPError "The item does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /user/inbox/{id} [post]
+func (item *InboxItem) Update(s *xorm.Session, _ web.Auth) (err error) {
+	item.ReadAt = time.Time{}
+	if item.Read {
+		item.ReadAt = time.Now()
+	}
+
+	_, err = s.
+		Where("id = ?", item.ID).
+		Cols("read_at").
+		NoAutoCondition().
+		Update(item)
+	return
+}
+
+// InboxRead marks the whole inbox of a user as read
+type InboxRead struct {
+	web.CRUDable `json:"-"`
+	web.Rights   `json:"-"`
+}
+
+// Create marks all items in the inbox of the current user as read
+// @Summary Mark the whole inbox as read
+// @Description Marks all items in the inbox of the current user as read.
+// @tags user
+// @Produce json
+// @Security JWTKeyAuth
+// @Success 201 {object} models.Message "The inbox was marked as read."
+// @Failure 403 {object} web.HTTPError "Link shares don't have an inbox."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /user/inbox/read [post]
+func (ir *InboxRead) Create(s *xorm.Session, a web.Auth) (err error) {
+	_, err = s.
+		Where("user_id = ? AND read_at IS NULL", a.GetID()).
+		Cols("read_at").
+		Update(&InboxItem{ReadAt: time.Now()})
+	return
+}
+
+// addMentionsToInbox adds an item to the inbox of everyone mentioned in text who can see the task.
+func addMentionsToInbox(s *xorm.Session, task *Task, commentID int64, text string, doerID int64) error {
+	users, err := FindMentionedUsersInText(s, text)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range users {
+		can, _, err := task.CanRead(s, u)
+		if err != nil {
+			return err
+		}
+		if !can {
+			continue
+		}
+
+		err = addToInbox(s, &InboxItem{
+			UserID:    u.ID,
+			Kind:      InboxItemKindMentioned,
+			TaskID:    task.ID,
+			CommentID: commentID,
+			ProjectID: task.ProjectID,
+			DoerID:    doerID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addProjectShareToInbox adds an item about a shared project to the inbox of a single user or all members of a team.
+func addProjectShareToInbox(s *xorm.Session, projectID, userID, teamID, doerID int64) error {
+	userIDs := []int64{userID}
+	if teamID != 0 {
+		userIDs = []int64{}
+		err := s.
+			Table("team_members").
+			Where("team_id = ?", teamID).
+			Cols("user_id").
+			Find(&userIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range userIDs {
+		err := addToInbox(s, &InboxItem{
+			UserID:    id,
+			Kind:      InboxItemKindProjectShared,
+			ProjectID: projectID,
+			DoerID:    doerID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
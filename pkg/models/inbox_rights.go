@@ -0,0 +1,46 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanUpdate checks if a user can mark an inbox item as read
+func (item *InboxItem) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	existing := &InboxItem{}
+	exists, err := s.Where("id = ?", item.ID).Get(existing)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, ErrInboxItemDoesNotExist{ItemID: item.ID}
+	}
+
+	return existing.UserID == a.GetID(), nil
+}
+
+// CanCreate checks if a user can mark their inbox as read
+func (ir *InboxRead) CanCreate(_ *xorm.Session, a web.Auth) (bool, error) {
+	_, is := a.(*LinkSharing)
+	return !is, nil
+}
@@ -0,0 +1,143 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/builder"
+)
+
+func TestInbox(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("mentions", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task, err := GetTaskByIDSimple(s, 1)
+		require.NoError(t, err)
+
+		err = addMentionsToInbox(s, &task, 0, "Hey @user1, please have a look", 2)
+		require.NoError(t, err)
+		// Mentioning again does not add a second item
+		err = addMentionsToInbox(s, &task, 0, "Hey @user1, please have a look", 2)
+		require.NoError(t, err)
+		// The doer does not end up in their own inbox
+		err = addMentionsToInbox(s, &task, 0, "Note to self @user1", 1)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		db.AssertCount(t, "inbox_items", builder.Eq{
+			"user_id": 1,
+			"kind":    InboxItemKindMentioned,
+			"task_id": 1,
+		}, 1)
+	})
+	t.Run("project shared with a team", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := addProjectShareToInbox(s, 1, 0, 1, 1)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		db.AssertExists(t, "inbox_items", map[string]interface{}{
+			"user_id":    2,
+			"kind":       InboxItemKindProjectShared,
+			"project_id": 1,
+		}, false)
+		db.AssertMissing(t, "inbox_items", map[string]interface{}{
+			"user_id": 1,
+		})
+	})
+	t.Run("read all and mark as read", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := addToInbox(s, &InboxItem{UserID: 1, Kind: InboxItemKindAssigned, TaskID: 1, ProjectID: 1, DoerID: 2})
+		require.NoError(t, err)
+		// Project 2 belongs to user 3 and is not shared with user 1
+		err = addToInbox(s, &InboxItem{UserID: 1, Kind: InboxItemKindProjectShared, ProjectID: 2, DoerID: 3})
+		require.NoError(t, err)
+		err = addToInbox(s, &InboxItem{UserID: 1, Kind: InboxItemKindProjectShared, ProjectID: 3, DoerID: 3})
+		require.NoError(t, err)
+
+		item := &InboxItem{}
+		result, _, total, err := item.ReadAll(s, u, "", 0, 50)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		items := result.([]*InboxItem)
+		require.Len(t, items, 2)
+		assert.Equal(t, InboxItemKindProjectShared, items[0].Kind)
+		assert.Equal(t, int64(3), items[0].Project.ID)
+		assert.Equal(t, InboxItemKindAssigned, items[1].Kind)
+		assert.Equal(t, int64(1), items[1].Task.ID)
+		assert.Equal(t, int64(2), items[1].Doer.ID)
+		assert.False(t, items[1].Read)
+
+		update := &InboxItem{ID: items[1].ID, Read: true}
+		can, err := update.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = update.Update(s, u)
+		require.NoError(t, err)
+
+		item = &InboxItem{UnreadOnly: true}
+		result, _, _, err = item.ReadAll(s, u, "", 0, 50)
+		require.NoError(t, err)
+		assert.Len(t, result.([]*InboxItem), 1)
+
+		err = (&InboxRead{}).Create(s, u)
+		require.NoError(t, err)
+		result, _, _, err = item.ReadAll(s, u, "", 0, 50)
+		require.NoError(t, err)
+		assert.Empty(t, result.([]*InboxItem))
+	})
+	t.Run("someone else's item", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := addToInbox(s, &InboxItem{UserID: 2, Kind: InboxItemKindAssigned, TaskID: 1, ProjectID: 1, DoerID: 1})
+		require.NoError(t, err)
+		item := &InboxItem{}
+		_, err = s.Where("user_id = ?", 2).Get(item)
+		require.NoError(t, err)
+
+		can, err := (&InboxItem{ID: item.ID}).CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("nonexisting item", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := (&InboxItem{ID: 9999}).CanUpdate(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInboxItemDoesNotExist(err))
+	})
+}
@@ -42,10 +42,32 @@ type Bucket struct {
 
 	// How many tasks can be at the same time on this board max
 	Limit int64 `xorm:"default 0" json:"limit" minimum:"0" valid:"range(0|9223372036854775807)"`
+	// How many tasks one user can be assigned to in this bucket at the same time.
+	AssigneeLimit int64 `xorm:"default 0" json:"assignee_limit" minimum:"0" valid:"range(0|9223372036854775807)"`
+	// What happens when a task is moved into the bucket while it is at its limit. `hard` rejects the move, `soft`
+	// allows it but returns a warning.
+	LimitMode BucketLimitMode `xorm:"default 0" json:"limit_mode"`
 
 	// The number of tasks currently in this bucket
 	Count int64 `xorm:"-" json:"count"`
 
+	// If the view groups its tasks into swimlanes, this holds the tasks of this bucket per swimlane. Every bucket
+	// contains all swimlanes of the view in the same order, even those without tasks in this bucket.
+	Swimlanes []*KanbanSwimlane `xorm:"-" json:"swimlanes,omitempty"`
+
+	// The right a user needs on the project to move tasks into or out of this bucket. 0 = Everyone who can edit
+	// tasks, 1 = Read & Write, 2 = Admin. This applies to tasks moved because they were marked done as well.
+	MoveRight Right `xorm:"bigint not null default 0" json:"move_right"`
+
+	// Whether the bucket is archived. Archived buckets and their tasks are not shown on the board, but keep their
+	// position and the positions of their tasks so they can be restored as they were.
+	IsArchived bool `xorm:"not null default false" json:"is_archived"`
+	// If set to true, only archived buckets are returned.
+	ShowArchived bool `xorm:"-" json:"-" query:"archived"`
+
+	// Actions which run every time a task is moved into this bucket, in the order they are defined.
+	Actions []*BucketAction `xorm:"json null" json:"actions"`
+
 	// The position this bucket has when querying all buckets. See the tasks.position property on how to use this.
 	Position float64 `xorm:"double null" json:"position"`
 
@@ -89,7 +111,7 @@ func getDefaultBucketID(s *xorm.Session, view *ProjectView) (bucketID int64, err
 
 	bucket := &Bucket{}
 	_, err = s.
-		Where("project_view_id = ?", view.ID).
+		Where("project_view_id = ? AND is_archived = ?", view.ID, false).
 		OrderBy("position asc").
 		Get(bucket)
 	if err != nil {
@@ -108,6 +130,7 @@ func getDefaultBucketID(s *xorm.Session, view *ProjectView) (bucketID int64, err
 // @Security JWTKeyAuth
 // @Param id path int true "Project ID"
 // @Param view path int true "Project view ID"
+// @Param archived query bool false "If true, only archived buckets are returned instead of the ones shown on the board."
 // @Success 200 {array} models.Bucket "The buckets"
 // @Failure 500 {object} models.Message "Internal server error"
 // @Router /projects/{id}/views/{view}/buckets [get]
@@ -128,7 +151,7 @@ func (b *Bucket) ReadAll(s *xorm.Session, auth web.Auth, _ string, _ int, _ int)
 
 	buckets := []*Bucket{}
 	err = s.
-		Where("project_view_id = ?", b.ProjectViewID).
+		Where("project_view_id = ? AND is_archived = ?", b.ProjectViewID, b.ShowArchived).
 		OrderBy("position").
 		Find(&buckets)
 	if err != nil {
@@ -154,12 +177,16 @@ func (b *Bucket) ReadAll(s *xorm.Session, auth web.Auth, _ string, _ int, _ int)
 }
 
 func GetTasksInBucketsForView(s *xorm.Session, view *ProjectView, projects []*Project, opts *taskSearchOptions, auth web.Auth) (bucketsWithTasks []*Bucket, err error) {
+	if view.BucketConfigurationMode.groupedBy() != SwimlaneModeNone {
+		return getTasksInGeneratedBuckets(s, view, projects, opts, auth)
+	}
+
 	// Get all buckets for this project
 	buckets := []*Bucket{}
 
 	if view.BucketConfigurationMode == BucketConfigurationModeManual {
 		err = s.
-			Where("project_view_id = ?", view.ID).
+			Where("project_view_id = ? AND is_archived = ?", view.ID, false).
 			OrderBy("position").
 			Find(&buckets)
 		if err != nil {
@@ -233,6 +260,11 @@ func GetTasksInBucketsForView(s *xorm.Session, view *ProjectView, projects []*Pr
 				bucketFilter = "(" + view.BucketConfiguration[id].Filter + ")"
 			}
 
+			archiveAfter := view.getDoneBucketConfiguration().ArchiveAfterDays
+			if id == view.DoneBucketID && archiveAfter > 0 {
+				bucketFilter += " && (done = false || done_at > now-" + strconv.FormatInt(archiveAfter, 10) + "d)"
+			}
+
 			var filterString string
 			if originalFilter == "" {
 				filterString = bucketFilter
@@ -281,6 +313,13 @@ func GetTasksInBucketsForView(s *xorm.Session, view *ProjectView, projects []*Pr
 		bucketMap[task.BucketID].Tasks = append(bucketMap[task.BucketID].Tasks, task)
 	}
 
+	if view.SwimlaneMode != SwimlaneModeNone {
+		err = addSwimlanesToBuckets(s, view, buckets)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return buckets, nil
 }
 
@@ -306,6 +345,15 @@ func (b *Bucket) Create(s *xorm.Session, a web.Auth) (err error) {
 	}
 	b.CreatedByID = b.CreatedBy.ID
 
+	if err := b.MoveRight.isValid(); err != nil {
+		return err
+	}
+
+	err = validateBucketActions(s, b.Actions, a)
+	if err != nil {
+		return
+	}
+
 	_, err = s.Insert(b)
 	if err != nil {
 		return
@@ -318,7 +366,7 @@ func (b *Bucket) Create(s *xorm.Session, a web.Auth) (err error) {
 
 // Update Updates an existing bucket
 // @Summary Update an existing bucket
-// @Description Updates an existing kanban bucket.
+// @Description Updates an existing kanban bucket. Setting `is_archived` hides the bucket and its tasks from the board.
 // @tags project
 // @Accept json
 // @Produce json
@@ -332,14 +380,33 @@ func (b *Bucket) Create(s *xorm.Session, a web.Auth) (err error) {
 // @Failure 404 {object} web.HTTPError "The bucket does not exist."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /projects/{projectID}/views/{view}/buckets/{bucketID} [post]
-func (b *Bucket) Update(s *xorm.Session, _ web.Auth) (err error) {
+func (b *Bucket) Update(s *xorm.Session, a web.Auth) (err error) {
+	if err := b.MoveRight.isValid(); err != nil {
+		return err
+	}
+
+	err = validateBucketActions(s, b.Actions, a)
+	if err != nil {
+		return
+	}
+
+	err = b.checkCanArchive(s)
+	if err != nil {
+		return
+	}
+
 	_, err = s.
 		Where("id = ?", b.ID).
 		Cols(
 			"title",
 			"limit",
+			"assignee_limit",
+			"limit_mode",
 			"position",
 			"project_view_id",
+			"actions",
+			"is_archived",
+			"move_right",
 		).
 		Update(b)
 	return
@@ -0,0 +1,218 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// BucketActionKind defines what a bucket action does with a task
+type BucketActionKind string
+
+const (
+	// BucketActionSetDone marks the task as done.
+	BucketActionSetDone BucketActionKind = "set_done"
+	// BucketActionAssignUser assigns the user from UserID to the task.
+	BucketActionAssignUser BucketActionKind = "assign_user"
+	// BucketActionAddLabel adds the label from LabelID to the task.
+	BucketActionAddLabel BucketActionKind = "add_label"
+	// BucketActionSetDueDate sets the due date of the task to DueDateOffset seconds after the task was moved.
+	BucketActionSetDueDate BucketActionKind = "set_due_date"
+)
+
+// BucketAction is something which happens to a task when it is moved into a bucket.
+type BucketAction struct {
+	// What this action does. Can be one of set_done, assign_user, add_label or set_due_date.
+	Kind BucketActionKind `json:"kind"`
+	// The user to assign, only used with assign_user.
+	UserID int64 `json:"user_id,omitempty"`
+	// The label to add, only used with add_label.
+	LabelID int64 `json:"label_id,omitempty"`
+	// The number of seconds after moving the task its due date should be, only used with set_due_date.
+	DueDateOffset int64 `json:"due_date_offset,omitempty"`
+}
+
+func validateBucketActions(s *xorm.Session, actions []*BucketAction, a web.Auth) error {
+	for _, action := range actions {
+		switch action.Kind {
+		case BucketActionSetDone:
+		case BucketActionAssignUser:
+			_, err := user.GetUserByID(s, action.UserID)
+			if user.IsErrUserDoesNotExist(err) {
+				return &ErrInvalidBucketAction{Kind: action.Kind, Message: "The user to assign does not exist."}
+			}
+			if err != nil {
+				return err
+			}
+		case BucketActionAddLabel:
+			label := &Label{ID: action.LabelID}
+			has, _, err := label.hasAccessToLabel(s, a)
+			if err != nil {
+				return err
+			}
+			if !has {
+				return &ErrInvalidBucketAction{Kind: action.Kind, Message: "The label does not exist or you don't have access to it."}
+			}
+		case BucketActionSetDueDate:
+			if action.DueDateOffset < 0 {
+				return &ErrInvalidBucketAction{Kind: action.Kind, Message: "The due date offset must not be negative."}
+			}
+		default:
+			return &ErrInvalidBucketAction{Kind: action.Kind, Message: "Unknown action kind."}
+		}
+	}
+
+	return nil
+}
+
+// runActions runs all actions of the bucket on a task which was just moved into it. The task is updated in place,
+// changes to its fields are saved to the database.
+func (b *Bucket) runActions(s *xorm.Session, task *Task, a web.Auth) (err error) {
+	if len(b.Actions) == 0 {
+		return nil
+	}
+
+	var (
+		cols              []string
+		remindersChanged  bool
+		approvalRequested bool
+		project           *Project
+	)
+
+	for _, action := range b.Actions {
+		switch action.Kind {
+		case BucketActionSetDone:
+			if task.Done {
+				continue
+			}
+			oldTask := *task
+			task.Done = true
+
+			// Tasks in projects with approvers are only done once one of them approved it
+			var requested bool
+			requested, err = task.updateApprovalStatus(s, &oldTask, a)
+			if err != nil {
+				return err
+			}
+			cols = append(cols, "approval_status")
+			if !task.Done {
+				approvalRequested = requested
+				continue
+			}
+
+			task.setDoneBy(a)
+			updateDone(&oldTask, task)
+			cols = append(cols, "done", "done_at", "due_date", "start_date", "end_date", "done_by_id", "done_via")
+			remindersChanged = true
+		case BucketActionSetDueDate:
+			task.DueDate = time.Now().Add(time.Duration(action.DueDateOffset) * time.Second)
+			cols = append(cols, "due_date")
+			remindersChanged = true
+		case BucketActionAssignUser:
+			if project == nil {
+				project, err = GetProjectSimpleByID(s, task.ProjectID)
+				if err != nil {
+					return err
+				}
+			}
+			assigned, err := s.
+				Where("task_id = ? AND user_id = ?", task.ID, action.UserID).
+				Exist(&TaskAssginee{})
+			if err != nil {
+				return err
+			}
+			if assigned {
+				continue
+			}
+			err = task.addNewAssigneeByID(s, action.UserID, project, a)
+			// The user might have lost access to the project since the action was configured. That should
+			// not prevent anyone from moving tasks around.
+			if IsErrUserDoesNotHaveAccessToProject(err) || user.IsErrUserDoesNotExist(err) {
+				log.Debugf("Could not run bucket action %s on task %d: %s", action.Kind, task.ID, err)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		case BucketActionAddLabel:
+			lt := &LabelTask{TaskID: task.ID, LabelID: action.LabelID}
+			err = lt.Create(s, a)
+			if IsErrLabelIsAlreadyOnTask(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(cols) > 0 {
+		_, err = s.Where("id = ?", task.ID).
+			Cols(cols...).
+			Update(task)
+		if err != nil {
+			return err
+		}
+
+		err = resetReminderTriggers(s, task.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if remindersChanged {
+		task.Reminders, err = getRemindersForTasks(s, []int64{task.ID})
+		if err != nil {
+			return err
+		}
+		err = task.updateReminders(s, task)
+		if err != nil {
+			return err
+		}
+	}
+
+	if approvalRequested {
+		err = dispatchTaskApprovalRequested(s, task, a)
+		if err != nil {
+			return err
+		}
+	}
+
+	doer, _ := user.GetFromAuth(a)
+	if len(cols) > 0 {
+		err = events.Dispatch(&TaskUpdatedEvent{
+			Task: task,
+			Doer: doer,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return events.Dispatch(&TaskBucketActionsExecutedEvent{
+		Task:    task,
+		Bucket:  b,
+		Actions: b.Actions,
+		Doer:    doer,
+	})
+}
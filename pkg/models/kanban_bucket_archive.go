@@ -0,0 +1,90 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// checkCanArchive makes sure a bucket which is about to be archived is neither the default nor the done bucket of
+// its view, and not the last bucket still shown on the board.
+func (b *Bucket) checkCanArchive(s *xorm.Session) error {
+	if !b.IsArchived {
+		return nil
+	}
+
+	old, err := getBucketByID(s, b.ID)
+	if err != nil {
+		return err
+	}
+	if old.IsArchived {
+		return nil
+	}
+
+	view, err := GetProjectViewByID(s, old.ProjectViewID)
+	if err != nil {
+		return err
+	}
+	if view.DefaultBucketID == b.ID || view.DoneBucketID == b.ID {
+		return &ErrCannotArchiveDefaultBucket{BucketID: b.ID, ProjectViewID: view.ID}
+	}
+
+	remaining, err := s.
+		Where("project_view_id = ? AND is_archived = ? AND id != ?", view.ID, false, b.ID).
+		Count(&Bucket{})
+	if err != nil {
+		return err
+	}
+	if remaining == 0 {
+		return ErrCannotRemoveLastBucket{
+			BucketID:      b.ID,
+			ProjectViewID: view.ID,
+		}
+	}
+
+	return nil
+}
+
+// RestoreBucket brings an archived bucket back on the board. Since archiving does not change the position of the
+// bucket or of its tasks, it shows up exactly where it was before.
+func RestoreBucket(s *xorm.Session, bucket *Bucket, a web.Auth) (err error) {
+	can, err := bucket.CanUpdate(s, a)
+	if err != nil {
+		return err
+	}
+	if !can {
+		return ErrGenericForbidden{}
+	}
+
+	b, err := getBucketByID(s, bucket.ID)
+	if err != nil {
+		return err
+	}
+
+	b.IsArchived = false
+	_, err = s.
+		Where("id = ?", b.ID).
+		Cols("is_archived").
+		Update(b)
+	if err != nil {
+		return err
+	}
+
+	*bucket = *b
+	return nil
+}
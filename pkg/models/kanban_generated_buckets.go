@@ -0,0 +1,237 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+var priorityBucketTitles = map[int64]string{
+	0: "Unset",
+	1: "Low",
+	2: "Medium",
+	3: "High",
+	4: "Urgent",
+	5: "DO NOW",
+}
+
+// getTasksInGeneratedBuckets returns the tasks of a view whose buckets are generated from the labels, assignees,
+// priorities or projects of its tasks. The id of each bucket is the id of the label, user or project, or the priority.
+// Tasks with multiple labels or assignees show up in multiple buckets. Grouping by project is mostly useful for
+// saved filters, which show tasks from many projects.
+func getTasksInGeneratedBuckets(s *xorm.Session, view *ProjectView, projects []*Project, opts *taskSearchOptions, auth web.Auth) (buckets []*Bucket, err error) {
+	opts.page = -1
+	opts.sortby = []*sortParam{
+		{
+			projectViewID: view.ID,
+			orderBy:       orderAscending,
+			sortBy:        taskPropertyPosition,
+		},
+	}
+
+	tasks, _, _, err := getRawTasksForProjects(s, projects, auth, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	taskMap := make(map[int64]*Task, len(tasks))
+	for _, t := range tasks {
+		taskMap[t.ID] = t
+	}
+
+	err = addMoreInfoToTasks(s, taskMap, auth, view)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := view.BucketConfigurationMode.groupedBy()
+
+	// All priorities are always shown so that tasks can be moved to a priority no other task has yet.
+	var alwaysInclude []int64
+	if mode == SwimlaneModePriority {
+		for p := range priorityBucketTitles {
+			alwaysInclude = append(alwaysInclude, p)
+		}
+	}
+	ids, titles := groupTasks(mode, tasks, alwaysInclude...)
+
+	switch mode {
+	case SwimlaneModeLabel:
+		titles[0] = "No label"
+	case SwimlaneModeAssignee:
+		titles[0] = "Unassigned"
+	case SwimlaneModePriority:
+		for _, id := range ids {
+			title, exists := priorityBucketTitles[id]
+			if !exists {
+				title = strconv.FormatInt(id, 10)
+			}
+			titles[id] = title
+		}
+	case SwimlaneModeProject:
+		err = addProjectTitles(s, titles)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	buckets = make([]*Bucket, 0, len(ids))
+	bucketMap := make(map[int64]*Bucket, len(ids))
+	for i, id := range ids {
+		bucketMap[id] = &Bucket{
+			ID:            id,
+			Title:         titles[id],
+			ProjectViewID: view.ID,
+			Position:      float64(i),
+			Tasks:         []*Task{},
+			CreatedByID:   auth.GetID(),
+			Created:       time.Now(),
+			Updated:       time.Now(),
+		}
+		buckets = append(buckets, bucketMap[id])
+	}
+
+	for _, t := range tasks {
+		for _, id := range getTaskSwimlaneIDs(mode, t) {
+			bucketMap[id].Tasks = append(bucketMap[id].Tasks, t)
+			bucketMap[id].Count++
+		}
+	}
+
+	if view.SwimlaneMode != SwimlaneModeNone {
+		err = addSwimlanesToBuckets(s, view, buckets)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return buckets, nil
+}
+
+// moveTaskInGeneratedBuckets changes the label, assignee, priority or project of a task when it is moved between
+// buckets of a view whose buckets are generated from them.
+func (b *TaskBucket) moveTaskInGeneratedBuckets(s *xorm.Session, view *ProjectView, a web.Auth) (err error) {
+	task, err := GetTaskByIDSimple(s, b.TaskID)
+	if err != nil {
+		return err
+	}
+	b.TaskDone = task.Done
+
+	if b.BucketID == b.FromBucketID {
+		return nil
+	}
+
+	switch view.BucketConfigurationMode.groupedBy() {
+	case SwimlaneModePriority:
+		if _, exists := priorityBucketTitles[b.BucketID]; !exists {
+			return ErrBucketDoesNotExist{BucketID: b.BucketID}
+		}
+
+		task.Priority = b.BucketID
+		_, err = s.Where("id = ?", task.ID).
+			Cols("priority").
+			Update(&task)
+		if err != nil {
+			return err
+		}
+
+		doer, _ := user.GetFromAuth(a)
+		return events.Dispatch(&TaskUpdatedEvent{
+			Task: &task,
+			Doer: doer,
+		})
+	case SwimlaneModeLabel:
+		if b.BucketID != 0 {
+			label := &Label{ID: b.BucketID}
+			has, _, err := label.hasAccessToLabel(s, a)
+			if err != nil {
+				return err
+			}
+			if !has {
+				return ErrUserHasNoAccessToLabel{LabelID: b.BucketID, UserID: a.GetID()}
+			}
+		}
+
+		if b.FromBucketID != 0 {
+			lt := &LabelTask{TaskID: task.ID, LabelID: b.FromBucketID}
+			err = lt.Delete(s, a)
+			if err != nil {
+				return err
+			}
+		}
+
+		if b.BucketID != 0 {
+			lt := &LabelTask{TaskID: task.ID, LabelID: b.BucketID}
+			err = lt.Create(s, a)
+			if err != nil && !IsErrLabelIsAlreadyOnTask(err) {
+				return err
+			}
+		}
+	case SwimlaneModeAssignee:
+		if b.FromBucketID != 0 {
+			ta := &TaskAssginee{TaskID: task.ID, UserID: b.FromBucketID}
+			err = ta.Delete(s, a)
+			if err != nil {
+				return err
+			}
+		}
+
+		if b.BucketID != 0 {
+			assigned, err := s.
+				Where("task_id = ? AND user_id = ?", task.ID, b.BucketID).
+				Exist(&TaskAssginee{})
+			if err != nil || assigned {
+				return err
+			}
+
+			project, err := GetProjectSimpleByID(s, task.ProjectID)
+			if err != nil {
+				return err
+			}
+			return task.addNewAssigneeByID(s, b.BucketID, project, a)
+		}
+	case SwimlaneModeProject:
+		if b.BucketID == 0 {
+			return ErrBucketDoesNotExist{BucketID: b.BucketID}
+		}
+
+		can, err := (&Task{ID: task.ID, ProjectID: b.BucketID}).CanUpdate(s, a)
+		if err != nil {
+			return err
+		}
+		if !can {
+			return ErrGenericForbidden{}
+		}
+
+		// The full task is needed since updating it would otherwise remove its assignees and reminders
+		err = addMoreInfoToTasks(s, map[int64]*Task{task.ID: &task}, a, nil)
+		if err != nil {
+			return err
+		}
+
+		task.ProjectID = b.BucketID
+		return task.Update(s, a)
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedBuckets(t *testing.T) {
+	u := &user.User{ID: 1}
+	setBucketMode := func(t *testing.T, mode BucketConfigurationModeKind) {
+		s := db.NewSession()
+		defer s.Close()
+		_, err := s.Where("id = ?", 4).Cols("bucket_configuration_mode").Update(&ProjectView{BucketConfigurationMode: mode})
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+	}
+
+	t.Run("buckets by priority", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		setBucketMode(t, BucketConfigurationModePriority)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{ProjectID: 1, ProjectViewID: 4}
+		result, _, _, err := tc.ReadAll(s, u, "", 0, 0)
+		require.NoError(t, err)
+		buckets, is := result.([]*Bucket)
+		require.True(t, is)
+
+		bucketIDs := []int64{}
+		for _, b := range buckets {
+			bucketIDs = append(bucketIDs, b.ID)
+			for _, task := range b.Tasks {
+				assert.Equal(t, b.ID, task.Priority)
+			}
+		}
+		for p := range priorityBucketTitles {
+			assert.Contains(t, bucketIDs, p)
+		}
+		assert.Equal(t, int64(0), buckets[len(buckets)-1].ID)
+		assert.Equal(t, "Unset", buckets[len(buckets)-1].Title)
+	})
+	t.Run("move changes the priority", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		setBucketMode(t, BucketConfigurationModePriority)
+		s := db.NewSession()
+		defer s.Close()
+
+		tb := &TaskBucket{
+			TaskID:        1,
+			BucketID:      4,
+			ProjectViewID: 4,
+			ProjectID:     1, // In actual web requests set via the url
+		}
+		can, err := tb.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = tb.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":       1,
+			"priority": 4,
+		}, false)
+	})
+	t.Run("move removes the label", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		setBucketMode(t, BucketConfigurationModeLabel)
+		s := db.NewSession()
+		defer s.Close()
+
+		tb := &TaskBucket{
+			TaskID:        1,
+			BucketID:      0,
+			FromBucketID:  4,
+			ProjectViewID: 4,
+			ProjectID:     1, // In actual web requests set via the url
+		}
+		err := tb.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "label_tasks", map[string]interface{}{
+			"task_id":  1,
+			"label_id": 4,
+		})
+	})
+	t.Run("saved filter grouped by project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		view := &ProjectView{
+			Title:                   "Board",
+			ProjectID:               getProjectIDFromSavedFilterID(1),
+			ViewKind:                ProjectViewKindKanban,
+			BucketConfigurationMode: BucketConfigurationModeProject,
+		}
+		_, err := s.Insert(view)
+		require.NoError(t, err)
+
+		tc := &TaskCollection{ProjectID: view.ProjectID, ProjectViewID: view.ID}
+		result, _, _, err := tc.ReadAll(s, u, "", 0, 0)
+		require.NoError(t, err)
+		buckets, is := result.([]*Bucket)
+		require.True(t, is)
+		require.NotEmpty(t, buckets)
+
+		for _, b := range buckets {
+			assert.NotEmpty(t, b.Title)
+			for _, task := range b.Tasks {
+				assert.Equal(t, b.ID, task.ProjectID)
+			}
+		}
+	})
+	t.Run("move changes the project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		setBucketMode(t, BucketConfigurationModeProject)
+		s := db.NewSession()
+		defer s.Close()
+
+		tb := &TaskBucket{
+			TaskID:        1,
+			BucketID:      10, // User 1 has write access to project 10
+			FromBucketID:  1,
+			ProjectViewID: 4,
+			ProjectID:     1, // In actual web requests set via the url
+		}
+		err := tb.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":         1,
+			"project_id": 10,
+		}, false)
+	})
+	t.Run("move into a project without write access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		setBucketMode(t, BucketConfigurationModeProject)
+		s := db.NewSession()
+		defer s.Close()
+
+		tb := &TaskBucket{
+			TaskID:        1,
+			BucketID:      9, // User 1 can only read project 9
+			FromBucketID:  1,
+			ProjectViewID: 4,
+			ProjectID:     1, // In actual web requests set via the url
+		}
+		err := tb.Update(s, u)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,135 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// BucketLimitMode defines how the limits of a bucket are enforced
+type BucketLimitMode int
+
+const (
+	BucketLimitModeHard BucketLimitMode = iota
+	BucketLimitModeSoft
+)
+
+func (m *BucketLimitMode) MarshalJSON() ([]byte, error) {
+	switch *m {
+	case BucketLimitModeHard:
+		return []byte(`"hard"`), nil
+	case BucketLimitModeSoft:
+		return []byte(`"soft"`), nil
+	}
+
+	return []byte(`null`), nil
+}
+
+func (m *BucketLimitMode) UnmarshalJSON(bytes []byte) error {
+	var value string
+	err := json.Unmarshal(bytes, &value)
+	if err != nil {
+		return err
+	}
+
+	switch value {
+	case "hard":
+		*m = BucketLimitModeHard
+	case "soft":
+		*m = BucketLimitModeSoft
+	default:
+		return fmt.Errorf("unknown bucket limit mode: %s", value)
+	}
+
+	return nil
+}
+
+// BucketLimitWarning describes a limit of a bucket which was exceeded by moving a task into it.
+type BucketLimitWarning struct {
+	// The limit which was exceeded.
+	Limit int64 `json:"limit"`
+	// The number of tasks in the bucket before the task was moved into it. When the limit is per assignee, this
+	// only counts the tasks of that assignee.
+	Count int64 `json:"count"`
+	// The assignee whose limit was exceeded. 0 if the limit of the whole bucket was exceeded.
+	UserID int64 `json:"user_id,omitempty"`
+}
+
+// checkBucketLimit checks if adding a task to a bucket would exceed the limits of the bucket. Depending on the limit
+// mode of the bucket, this either returns an error or warnings about all exceeded limits.
+func checkBucketLimit(s *xorm.Session, t *Task, bucket *Bucket) (warnings []*BucketLimitWarning, err error) {
+	if bucket.Limit > 0 {
+		taskCount, err := s.
+			Where("bucket_id = ?", bucket.ID).
+			GroupBy("task_id").
+			Count(&TaskBucket{})
+		if err != nil {
+			return nil, err
+		}
+		if taskCount >= bucket.Limit {
+			if bucket.LimitMode == BucketLimitModeHard {
+				return nil, ErrBucketLimitExceeded{TaskID: t.ID, BucketID: bucket.ID, Limit: bucket.Limit}
+			}
+			warnings = append(warnings, &BucketLimitWarning{
+				Limit: bucket.Limit,
+				Count: taskCount,
+			})
+		}
+	}
+
+	if bucket.AssigneeLimit > 0 {
+		assignees := []*TaskAssginee{}
+		err = s.Where("task_id = ?", t.ID).Find(&assignees)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, assignee := range assignees {
+			taskCount, err := s.
+				Where(builder.And(
+					builder.Eq{"user_id": assignee.UserID},
+					builder.In("task_id", builder.Select("task_id").From("task_buckets").Where(builder.Eq{"bucket_id": bucket.ID})),
+				)).
+				Count(&TaskAssginee{})
+			if err != nil {
+				return nil, err
+			}
+			if taskCount < bucket.AssigneeLimit {
+				continue
+			}
+			if bucket.LimitMode == BucketLimitModeHard {
+				return nil, &ErrBucketAssigneeLimitExceeded{
+					BucketID: bucket.ID,
+					Limit:    bucket.AssigneeLimit,
+					TaskID:   t.ID,
+					UserID:   assignee.UserID,
+				}
+			}
+			warnings = append(warnings, &BucketLimitWarning{
+				Limit:  bucket.AssigneeLimit,
+				Count:  taskCount,
+				UserID: assignee.UserID,
+			})
+		}
+	}
+
+	return warnings, nil
+}
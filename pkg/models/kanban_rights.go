@@ -28,8 +28,13 @@ func (b *Bucket) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
 		return false, err
 	}
 
+	has, err := pv.hasRequiredRight(s, a)
+	if err != nil || !has {
+		return false, err
+	}
+
 	p := &Project{ID: pv.ProjectID}
-	return p.CanUpdate(s, a)
+	return p.canManageBuckets(s, a)
 }
 
 // CanUpdate checks if a user can update an existing bucket
@@ -55,6 +60,35 @@ func (b *Bucket) canDoBucket(s *xorm.Session, a web.Auth) (bool, error) {
 
 	// TODO saved filter check
 
+	has, err := pv.hasRequiredRight(s, a)
+	if err != nil || !has {
+		return false, err
+	}
+
 	p := &Project{ID: pv.ProjectID}
-	return p.CanUpdate(s, a)
+	return p.canManageBuckets(s, a)
+}
+
+// canManageBuckets checks if the user can edit the project or got the capability to manage buckets through a role
+func (p *Project) canManageBuckets(s *xorm.Session, a web.Auth) (bool, error) {
+	can, err := p.CanUpdate(s, a)
+	if err != nil || can || p.ID < 0 {
+		return can, err
+	}
+	return p.hasCapability(s, a, CapabilityManageBuckets)
+}
+
+// canMoveTasks checks if the user has the right on the project of a task which the bucket requires to move tasks
+// into or out of it.
+func (b *Bucket) canMoveTasks(s *xorm.Session, a web.Auth, task *Task) (bool, error) {
+	if b.MoveRight == RightRead {
+		return true, nil
+	}
+
+	p := &Project{ID: task.ProjectID}
+	_, maxRight, err := p.CanRead(s, a)
+	if err != nil {
+		return false, err
+	}
+	return int(b.MoveRight) <= maxRight, nil
 }
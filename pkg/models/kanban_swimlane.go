@@ -0,0 +1,250 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"sort"
+	"strings"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// KanbanSwimlane holds all tasks of a bucket which belong to one swimlane.
+type KanbanSwimlane struct {
+	// The id of the user, label, project or the priority this swimlane groups by. 0 holds all tasks without
+	// assignee, label or priority.
+	ID int64 `json:"id"`
+	// The name of the user or the title of the label or project. Empty for priority swimlanes and the swimlane with id 0.
+	Title string `json:"title"`
+	// The tasks in this swimlane, sorted by their position in it.
+	Tasks []*Task `json:"tasks"`
+}
+
+// TaskSwimlanePosition holds the position of a task within a swimlane of a kanban view.
+type TaskSwimlanePosition struct {
+	// The ID of the task this position is for
+	TaskID int64 `xorm:"bigint not null index" json:"task_id" param:"task"`
+	// The kanban view this position is for
+	ProjectViewID int64 `xorm:"bigint not null index" json:"project_view_id"`
+	// The id of the swimlane, see KanbanSwimlane.ID
+	SwimlaneID int64 `xorm:"bigint not null" json:"swimlane_id"`
+	// The position of the task in the swimlane. Works the same as the position of a task in a view.
+	// Tasks without a position in a swimlane are sorted by their position in the view.
+	Position float64 `xorm:"double not null" json:"position"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+func (tp *TaskSwimlanePosition) TableName() string {
+	return "task_swimlane_positions"
+}
+
+func (tp *TaskSwimlanePosition) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	t := &Task{ID: tp.TaskID}
+	return t.CanUpdate(s, a)
+}
+
+// Update is the handler to update the position of a task in a swimlane
+// @Summary Updates a task position in a swimlane
+// @Description Updates the position of a task in one swimlane of a kanban view. The view needs to group its tasks into swimlanes.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Task ID"
+// @Param view body models.TaskSwimlanePosition true "The task position with updated values you want to change."
+// @Success 200 {object} models.TaskSwimlanePosition "The updated task position."
+// @Failure 400 {object} web.HTTPError "Invalid task position object provided or the view has no swimlanes."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/swimlane-position [post]
+func (tp *TaskSwimlanePosition) Update(s *xorm.Session, _ web.Auth) (err error) {
+	view, err := GetProjectViewByID(s, tp.ProjectViewID)
+	if err != nil {
+		return err
+	}
+
+	if view.ViewKind != ProjectViewKindKanban || view.SwimlaneMode == SwimlaneModeNone {
+		return &ErrProjectViewHasNoSwimlanes{ProjectViewID: view.ID}
+	}
+
+	count, err := s.
+		Where("task_id = ? AND project_view_id = ? AND swimlane_id = ?", tp.TaskID, tp.ProjectViewID, tp.SwimlaneID).
+		Cols("position").
+		Update(tp)
+	if err != nil || count > 0 {
+		return err
+	}
+
+	_, err = s.Insert(tp)
+	return
+}
+
+// getTaskSwimlaneIDs returns the ids of all swimlanes a task shows up in
+func getTaskSwimlaneIDs(mode SwimlaneModeKind, t *Task) (ids []int64) {
+	switch mode {
+	case SwimlaneModeAssignee:
+		for _, a := range t.Assignees {
+			ids = append(ids, a.ID)
+		}
+	case SwimlaneModeLabel:
+		for _, l := range t.Labels {
+			ids = append(ids, l.ID)
+		}
+	case SwimlaneModePriority:
+		ids = append(ids, t.Priority)
+	case SwimlaneModeProject:
+		ids = append(ids, t.ProjectID)
+	}
+
+	if len(ids) == 0 {
+		ids = append(ids, 0)
+	}
+	return
+}
+
+// groupTasks returns the ids and titles of all groups the tasks fall into, sorted in the order they should be shown.
+// The group without assignee, label or priority always comes last. Priorities are sorted from the most to the least
+// urgent, projects by id since their titles are not known here, everything else by title. Groups in alwaysInclude are returned even if no task falls into them.
+func groupTasks(mode SwimlaneModeKind, tasks []*Task, alwaysInclude ...int64) (ids []int64, titles map[int64]string) {
+	titles = make(map[int64]string)
+	for _, id := range alwaysInclude {
+		titles[id] = ""
+	}
+	for _, t := range tasks {
+		for _, id := range getTaskSwimlaneIDs(mode, t) {
+			titles[id] = ""
+		}
+		switch mode {
+		case SwimlaneModeAssignee:
+			for _, a := range t.Assignees {
+				titles[a.ID] = a.GetName()
+			}
+		case SwimlaneModeLabel:
+			for _, l := range t.Labels {
+				titles[l.ID] = l.Title
+			}
+		}
+	}
+
+	ids = make([]int64, 0, len(titles))
+	for id := range titles {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := ids[i], ids[j]
+		if a == 0 || b == 0 {
+			return b == 0 && a != 0
+		}
+		if mode == SwimlaneModePriority {
+			return a > b
+		}
+		if mode == SwimlaneModeProject {
+			return a < b
+		}
+		ta, tb := strings.ToLower(titles[a]), strings.ToLower(titles[b])
+		if ta == tb {
+			return a < b
+		}
+		return ta < tb
+	})
+
+	return
+}
+
+// addSwimlanesToBuckets groups the tasks of all buckets into the swimlanes of the view. The tasks of the buckets
+// need to have their assignees and labels loaded already.
+func addSwimlanesToBuckets(s *xorm.Session, view *ProjectView, buckets []*Bucket) (err error) {
+	positions := []*TaskSwimlanePosition{}
+	err = s.Where("project_view_id = ?", view.ID).Find(&positions)
+	if err != nil {
+		return
+	}
+
+	type taskInSwimlane struct {
+		taskID     int64
+		swimlaneID int64
+	}
+	positionMap := make(map[taskInSwimlane]float64, len(positions))
+	for _, p := range positions {
+		positionMap[taskInSwimlane{p.TaskID, p.SwimlaneID}] = p.Position
+	}
+
+	tasks := []*Task{}
+	for _, b := range buckets {
+		tasks = append(tasks, b.Tasks...)
+	}
+	swimlaneIDs, titles := groupTasks(view.SwimlaneMode, tasks)
+	if view.SwimlaneMode == SwimlaneModeProject {
+		err = addProjectTitles(s, titles)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, b := range buckets {
+		swimlanes := make(map[int64]*KanbanSwimlane, len(swimlaneIDs))
+		b.Swimlanes = make([]*KanbanSwimlane, 0, len(swimlaneIDs))
+		for _, id := range swimlaneIDs {
+			swimlanes[id] = &KanbanSwimlane{
+				ID:    id,
+				Title: titles[id],
+				Tasks: []*Task{},
+			}
+			b.Swimlanes = append(b.Swimlanes, swimlanes[id])
+		}
+
+		for _, t := range b.Tasks {
+			for _, id := range getTaskSwimlaneIDs(view.SwimlaneMode, t) {
+				swimlanes[id].Tasks = append(swimlanes[id].Tasks, t)
+			}
+		}
+
+		for _, swimlane := range b.Swimlanes {
+			position := func(t *Task) float64 {
+				if p, has := positionMap[taskInSwimlane{t.ID, swimlane.ID}]; has {
+					return p
+				}
+				return t.Position
+			}
+			sort.SliceStable(swimlane.Tasks, func(i, j int) bool {
+				return position(swimlane.Tasks[i]) < position(swimlane.Tasks[j])
+			})
+		}
+	}
+
+	return nil
+}
+
+// addProjectTitles sets the title of every project in a grouping of tasks by project.
+func addProjectTitles(s *xorm.Session, titles map[int64]string) (err error) {
+	projectIDs := make([]int64, 0, len(titles))
+	for id := range titles {
+		projectIDs = append(projectIDs, id)
+	}
+
+	projects, err := GetProjectsMapByIDs(s, projectIDs)
+	if err != nil {
+		return err
+	}
+
+	for id, p := range projects {
+		titles[id] = p.Title
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKanbanSwimlanes(t *testing.T) {
+	readBuckets := func(t *testing.T) []*Bucket {
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{
+			ProjectViewID: 4,
+			ProjectID:     1,
+		}
+		bucketsInterface, _, _, err := tc.ReadAll(s, &user.User{ID: 1}, "", 0, 0)
+		require.NoError(t, err)
+		buckets, is := bucketsInterface.([]*Bucket)
+		require.True(t, is)
+		return buckets
+	}
+	setSwimlaneMode := func(t *testing.T, mode SwimlaneModeKind) {
+		s := db.NewSession()
+		defer s.Close()
+		_, err := s.Where("id = ?", 4).Cols("swimlane_mode").Update(&ProjectView{SwimlaneMode: mode})
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+	}
+
+	t.Run("no swimlanes", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		buckets := readBuckets(t)
+		for _, b := range buckets {
+			assert.Empty(t, b.Swimlanes)
+		}
+	})
+	t.Run("by label", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		setSwimlaneMode(t, SwimlaneModeLabel)
+
+		buckets := readBuckets(t)
+		for _, b := range buckets {
+			require.NotEmpty(t, b.Swimlanes)
+			// Every bucket has the same swimlanes so they line up
+			assert.Len(t, b.Swimlanes, len(buckets[0].Swimlanes))
+			assert.Equal(t, int64(0), b.Swimlanes[len(b.Swimlanes)-1].ID)
+
+			for _, swimlane := range b.Swimlanes {
+				for _, task := range swimlane.Tasks {
+					if swimlane.ID == 0 {
+						assert.Empty(t, task.Labels)
+						continue
+					}
+					labelIDs := []int64{}
+					for _, l := range task.Labels {
+						labelIDs = append(labelIDs, l.ID)
+					}
+					assert.Contains(t, labelIDs, swimlane.ID)
+				}
+			}
+		}
+	})
+	t.Run("position in swimlane", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		setSwimlaneMode(t, SwimlaneModeLabel)
+
+		var swimlane *KanbanSwimlane
+		for _, b := range readBuckets(t) {
+			for _, sl := range b.Swimlanes {
+				if len(sl.Tasks) > 1 {
+					swimlane = sl
+					break
+				}
+			}
+			if swimlane != nil {
+				break
+			}
+		}
+		require.NotNil(t, swimlane)
+		lastTaskID := swimlane.Tasks[len(swimlane.Tasks)-1].ID
+
+		s := db.NewSession()
+		defer s.Close()
+		tp := &TaskSwimlanePosition{
+			TaskID:        lastTaskID,
+			ProjectViewID: 4,
+			SwimlaneID:    swimlane.ID,
+			Position:      -1,
+		}
+		err := tp.Update(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		db.AssertExists(t, "task_swimlane_positions", map[string]interface{}{
+			"task_id":         lastTaskID,
+			"project_view_id": 4,
+			"swimlane_id":     swimlane.ID,
+		}, false)
+
+		for _, b := range readBuckets(t) {
+			for _, sl := range b.Swimlanes {
+				if sl.ID == swimlane.ID && len(sl.Tasks) > 1 && sl.Tasks[0].ID == lastTaskID {
+					return
+				}
+			}
+		}
+		t.Errorf("task %d was not moved to the top of swimlane %d", lastTaskID, swimlane.ID)
+	})
+	t.Run("view without swimlanes", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tp := &TaskSwimlanePosition{
+			TaskID:        1,
+			ProjectViewID: 1,
+		}
+		err := tp.Update(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrProjectViewHasNoSwimlanes(err))
+	})
+}
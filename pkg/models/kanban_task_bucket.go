@@ -17,6 +17,8 @@
 package models
 
 import (
+	"time"
+
 	"code.vikunja.io/api/pkg/events"
 	"code.vikunja.io/api/pkg/user"
 	"code.vikunja.io/web"
@@ -29,6 +31,14 @@ type TaskBucket struct {
 	ProjectViewID int64 `xorm:"bigint not null index" json:"project_view_id" param:"view"`
 	ProjectID     int64 `xorm:"-" json:"-" param:"project"`
 	TaskDone      bool  `xorm:"-" json:"task_done,omitempty"`
+	// The limits of the bucket which were exceeded by moving the task into it. Only set if the bucket enforces its
+	// limits softly, otherwise the move is rejected.
+	LimitWarnings []*BucketLimitWarning `xorm:"-" json:"limit_warnings,omitempty"`
+	// Only used for views with buckets generated from labels or assignees: The bucket the task was moved out of.
+	// That label or assignee is removed from the task.
+	FromBucketID int64 `xorm:"-" json:"from_bucket_id,omitempty"`
+	// The task after the actions of the bucket ran on it. Only set if the bucket has actions.
+	Task *Task `xorm:"-" json:"task,omitempty"`
 
 	web.Rights   `xorm:"-" json:"-"`
 	web.CRUDable `xorm:"-" json:"-"`
@@ -39,6 +49,27 @@ func (b *TaskBucket) TableName() string {
 }
 
 func (b *TaskBucket) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	view, err := GetProjectViewByIDAndProject(s, b.ProjectViewID, b.ProjectID)
+	if err != nil {
+		return false, err
+	}
+
+	// Generated buckets don't exist in the database, moving tasks between them only changes the task.
+	if view.BucketConfigurationMode.groupedBy() != SwimlaneModeNone {
+		has, err := view.hasRequiredRight(s, a)
+		if err != nil || !has {
+			return false, err
+		}
+		t := &Task{ID: b.TaskID}
+		return t.CanUpdate(s, a)
+	}
+
+	// The task is only known from the request body, it does not have to be part of the project of the route
+	can, _, err := (&Task{ID: b.TaskID}).CanRead(s, a)
+	if err != nil || !can {
+		return false, err
+	}
+
 	bucket := Bucket{
 		ID:            b.BucketID,
 		ProjectID:     b.ProjectID,
@@ -60,10 +91,20 @@ func (b *TaskBucket) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
 // @Param taskBucket body models.TaskBucket true "The id of the task you want to move into the bucket."
 // @Success 200 {object} models.TaskBucket "The updated task bucket."
 // @Failure 400 {object} web.HTTPError "Invalid task bucket object provided."
+// @Failure 412 {object} web.HTTPError "The bucket is archived or moving the task would exceed one of its hard limits."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /projects/{project}/views/{view}/buckets/{bucket}/tasks [post]
 func (b *TaskBucket) Update(s *xorm.Session, a web.Auth) (err error) {
 
+	view, err := GetProjectViewByIDAndProject(s, b.ProjectViewID, b.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	if view.BucketConfigurationMode.groupedBy() != SwimlaneModeNone {
+		return b.moveTaskInGeneratedBuckets(s, view, a)
+	}
+
 	oldTaskBucket := &TaskBucket{}
 	_, err = s.
 		Where("task_id = ? AND project_view_id = ?", b.TaskID, b.ProjectViewID).
@@ -77,11 +118,6 @@ func (b *TaskBucket) Update(s *xorm.Session, a web.Auth) (err error) {
 		return
 	}
 
-	view, err := GetProjectViewByIDAndProject(s, b.ProjectViewID, b.ProjectID)
-	if err != nil {
-		return err
-	}
-
 	bucket, err := getBucketByID(s, b.BucketID)
 	if err != nil {
 		return err
@@ -95,15 +131,24 @@ func (b *TaskBucket) Update(s *xorm.Session, a web.Auth) (err error) {
 		}
 	}
 
+	if bucket.IsArchived {
+		return &ErrBucketIsArchived{BucketID: bucket.ID}
+	}
+
 	task, err := GetTaskByIDSimple(s, b.TaskID)
 	if err != nil {
 		return err
 	}
 
+	err = checkCanMoveTaskBetweenBuckets(s, a, &task, oldTaskBucket.BucketID, bucket)
+	if err != nil {
+		return err
+	}
+
 	// Check the bucket limit
 	// Only check the bucket limit if the task is being moved between buckets, allow reordering the task within a bucket
 	if b.BucketID != 0 && b.BucketID != oldTaskBucket.BucketID {
-		err = checkBucketLimit(s, &task, bucket)
+		b.LimitWarnings, err = checkBucketLimit(s, &task, bucket)
 		if err != nil {
 			return err
 		}
@@ -113,9 +158,27 @@ func (b *TaskBucket) Update(s *xorm.Session, a web.Auth) (err error) {
 
 	// mark task done if moved into the done bucket
 	var doneChanged bool
+	doneConfig := view.getDoneBucketConfiguration()
 	if view.DoneBucketID == b.BucketID {
-		doneChanged = true
+		originalTask := task
 		task.Done = true
+
+		// Tasks in projects with approvers are only done once one of them approved it
+		var approvalRequested bool
+		approvalRequested, err = task.updateApprovalStatus(s, &originalTask, a)
+		if err != nil {
+			return err
+		}
+		if !task.Done {
+			return b.requestApproval(s, &task, approvalRequested, a)
+		}
+
+		doneChanged = true
+		task.setDoneBy(a)
+		// The done bucket archives tasks based on their done date, every done task needs one
+		if !originalTask.Done || task.DoneAt.IsZero() || doneConfig.SetDoneAtOnMove {
+			task.DoneAt = time.Now()
+		}
 		if task.RepeatAfter > 0 {
 			oldTask := task
 			task.Done = false
@@ -124,18 +187,31 @@ func (b *TaskBucket) Update(s *xorm.Session, a web.Auth) (err error) {
 		}
 	}
 
-	if oldTaskBucket.BucketID == view.DoneBucketID {
+	if oldTaskBucket.BucketID == view.DoneBucketID && !doneConfig.KeepDoneWhenMovedOut {
+		if task.Done {
+			err = (&Project{ID: task.ProjectID}).checkOpenTaskQuota(s)
+			if err != nil {
+				return err
+			}
+		}
 		doneChanged = true
 		task.Done = false
+		task.DoneAt = time.Time{}
+		task.setDoneBy(nil)
+		task.ApprovalStatus = TaskApprovalStatusNone
 	}
 
 	if doneChanged {
 		_, err = s.Where("id = ?", task.ID).
 			Cols(
 				"done",
+				"done_at",
 				"due_date",
 				"start_date",
 				"end_date",
+				"done_by_id",
+				"done_via",
+				"approval_status",
 			).
 			Update(task)
 		if err != nil {
@@ -171,9 +247,90 @@ func (b *TaskBucket) Update(s *xorm.Session, a web.Auth) (err error) {
 				return err
 			}
 		}
+
+		mover, err := GetUserOrLinkShareUser(s, a)
+		if err != nil {
+			return err
+		}
+		err = events.Dispatch(&TaskMovedToBucketEvent{
+			Task:   &task,
+			Bucket: bucket,
+			Doer:   mover,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(bucket.Actions) > 0 {
+			err = bucket.runActions(s, &task, a)
+			if err != nil {
+				return err
+			}
+			b.Task = &task
+		}
 	}
 
 	b.TaskDone = task.Done
 
 	return
 }
+
+// requestApproval keeps a task which was moved into the done bucket in its bucket until one of the approvers of
+// its project approved it.
+func (b *TaskBucket) requestApproval(s *xorm.Session, task *Task, requested bool, a web.Auth) error {
+	_, err := s.
+		ID(task.ID).
+		Cols("approval_status").
+		Update(task)
+	if err != nil {
+		return err
+	}
+
+	b.TaskDone = false
+	b.Task = task
+
+	if !requested {
+		return nil
+	}
+
+	return dispatchTaskApprovalRequested(s, task, a)
+}
+
+// dispatchTaskApprovalRequested lets the approvers of the task's project know a task is waiting for them.
+func dispatchTaskApprovalRequested(s *xorm.Session, task *Task, a web.Auth) error {
+	requester, err := GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return err
+	}
+	return events.Dispatch(&TaskApprovalRequestedEvent{
+		Task: task,
+		Doer: requester,
+	})
+}
+
+// checkCanMoveTaskBetweenBuckets makes sure the user has the rights both buckets require to move the task out of
+// the old and into the new one.
+func checkCanMoveTaskBetweenBuckets(s *xorm.Session, a web.Auth, task *Task, oldBucketID int64, newBucket *Bucket) error {
+	buckets := []*Bucket{newBucket}
+	if oldBucketID != 0 {
+		oldBucket, err := getBucketByID(s, oldBucketID)
+		if err != nil && !IsErrBucketDoesNotExist(err) {
+			return err
+		}
+		if err == nil {
+			buckets = append(buckets, oldBucket)
+		}
+	}
+
+	for _, bucket := range buckets {
+		can, err := bucket.canMoveTasks(s, a, task)
+		if err != nil {
+			return err
+		}
+		if !can {
+			return &ErrNotAllowedToMoveTaskInBucket{BucketID: bucket.ID, TaskID: task.ID}
+		}
+	}
+
+	return nil
+}
@@ -18,6 +18,7 @@ package models
 
 import (
 	"testing"
+	"time"
 
 	"code.vikunja.io/api/pkg/db"
 	"code.vikunja.io/api/pkg/user"
@@ -44,6 +45,73 @@ func TestTaskBucket_Update(t *testing.T) {
 		require.Error(t, err)
 		assert.True(t, IsErrBucketLimitExceeded(err))
 	})
+	t.Run("full bucket with soft limit", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Where("id = ?", 2).Cols("limit_mode").Update(&Bucket{LimitMode: BucketLimitModeSoft})
+		require.NoError(t, err)
+
+		tb := &TaskBucket{
+			TaskID:        1,
+			BucketID:      2, // Bucket 2 already has 3 tasks and a limit of 3
+			ProjectViewID: 4,
+			ProjectID:     1, // In actual web requests set via the url
+		}
+		err = tb.Update(s, u)
+		require.NoError(t, err)
+		require.Len(t, tb.LimitWarnings, 1)
+		assert.Equal(t, int64(3), tb.LimitWarnings[0].Limit)
+		assert.Equal(t, int64(0), tb.LimitWarnings[0].UserID)
+	})
+	t.Run("assignee limit", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Where("id = ?", 3).Cols("assignee_limit").Update(&Bucket{AssigneeLimit: 1})
+		require.NoError(t, err)
+		_, err = s.Insert(&TaskAssginee{TaskID: 2, UserID: 1}) // Task 2 is in bucket 3
+		require.NoError(t, err)
+
+		tb := &TaskBucket{
+			TaskID:        30, // Assigned to user 1 and 2
+			BucketID:      3,
+			ProjectViewID: 4,
+			ProjectID:     1, // In actual web requests set via the url
+		}
+		err = tb.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrBucketAssigneeLimitExceeded(err))
+	})
+	t.Run("bucket requiring a higher right", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Where("id = ?", 40).Cols("move_right").Update(&Bucket{MoveRight: RightAdmin})
+		require.NoError(t, err)
+
+		linkShare := &LinkSharing{
+			ID:          2,
+			ProjectID:   2,
+			Right:       RightWrite,
+			SharingType: SharingTypeWithoutPassword,
+		}
+		tb := &TaskBucket{
+			TaskID:        13,
+			BucketID:      40,
+			ProjectViewID: 8,
+			ProjectID:     2, // In actual web requests set via the url
+		}
+		err = tb.Update(s, linkShare)
+		require.Error(t, err)
+		assert.True(t, IsErrNotAllowedToMoveTaskInBucket(err))
+
+		err = tb.Update(s, u)
+		require.NoError(t, err)
+	})
 	t.Run("full bucket but not changing the bucket", func(t *testing.T) {
 		db.LoadAndAssertFixtures(t)
 		s := db.NewSession()
@@ -129,6 +197,85 @@ func TestTaskBucket_Update(t *testing.T) {
 			"bucket_id": 3,
 		})
 	})
+	t.Run("moving a task to the done bucket always sets the done date", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tb := &TaskBucket{
+			TaskID:        1,
+			BucketID:      3, // Bucket 3 is the done bucket
+			ProjectViewID: 4,
+			ProjectID:     1, // In actual web requests set via the url
+		}
+		err := tb.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		task, err := GetTaskByIDSimple(s, 1)
+		require.NoError(t, err)
+		assert.True(t, task.Done)
+		assert.WithinDuration(t, time.Now(), task.DoneAt, time.Minute)
+	})
+	t.Run("moving a task to the done bucket setting the done date", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Where("id = ?", 4).
+			Cols("done_bucket_configuration").
+			Update(&ProjectView{DoneBucketConfiguration: &ProjectViewDoneBucketConfiguration{SetDoneAtOnMove: true}})
+		require.NoError(t, err)
+
+		tb := &TaskBucket{
+			TaskID:        1,
+			BucketID:      3, // Bucket 3 is the done bucket
+			ProjectViewID: 4,
+			ProjectID:     1, // In actual web requests set via the url
+		}
+		err = tb.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+		assert.True(t, tb.TaskDone)
+
+		task, err := GetTaskByIDSimple(s, 1)
+		require.NoError(t, err)
+		assert.True(t, task.Done)
+		assert.False(t, task.DoneAt.IsZero())
+	})
+	t.Run("keep done when moving out of the done bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Where("id = ?", 4).
+			Cols("done_bucket_configuration").
+			Update(&ProjectView{DoneBucketConfiguration: &ProjectViewDoneBucketConfiguration{KeepDoneWhenMovedOut: true}})
+		require.NoError(t, err)
+
+		tb := &TaskBucket{
+			TaskID:        2,
+			BucketID:      1, // Bucket 1 is the default bucket
+			ProjectViewID: 4,
+			ProjectID:     1, // In actual web requests set via the url
+		}
+		err = tb.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+		assert.True(t, tb.TaskDone)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":   tb.TaskID,
+			"done": true,
+		}, false)
+		db.AssertExists(t, "task_buckets", map[string]interface{}{
+			"task_id":   tb.TaskID,
+			"bucket_id": 1,
+		}, false)
+	})
 	t.Run("moving a repeating task to the done bucket", func(t *testing.T) {
 		db.LoadAndAssertFixtures(t)
 		s := db.NewSession()
@@ -156,4 +303,82 @@ func TestTaskBucket_Update(t *testing.T) {
 			"bucket_id": 1,
 		}, false)
 	})
+	t.Run("bucket actions", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Where("id = ?", 1).Cols("actions").Update(&Bucket{Actions: []*BucketAction{
+			{Kind: BucketActionAssignUser, UserID: 1},
+			{Kind: BucketActionAddLabel, LabelID: 4},
+			{Kind: BucketActionSetDueDate, DueDateOffset: 3600},
+		}})
+		require.NoError(t, err)
+
+		tb := &TaskBucket{
+			TaskID:        3,
+			BucketID:      1,
+			ProjectViewID: 4,
+			ProjectID:     1, // In actual web requests set via the url
+		}
+		err = tb.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+		require.NotNil(t, tb.Task)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), tb.Task.DueDate, time.Minute)
+
+		db.AssertExists(t, "task_assignees", map[string]interface{}{
+			"task_id": 3,
+			"user_id": 1,
+		}, false)
+		db.AssertExists(t, "label_tasks", map[string]interface{}{
+			"task_id":  3,
+			"label_id": 4,
+		}, false)
+	})
+	t.Run("bucket action marking the task done", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Where("id = ?", 1).Cols("actions").Update(&Bucket{Actions: []*BucketAction{
+			{Kind: BucketActionSetDone},
+		}})
+		require.NoError(t, err)
+
+		tb := &TaskBucket{
+			TaskID:        3,
+			BucketID:      1,
+			ProjectViewID: 4,
+			ProjectID:     1, // In actual web requests set via the url
+		}
+		err = tb.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+		assert.True(t, tb.TaskDone)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":         3,
+			"done":       true,
+			"done_by_id": 1,
+		}, false)
+	})
+	t.Run("invalid bucket action", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{
+			ID:            1,
+			Title:         "testbucket1",
+			ProjectViewID: 4,
+			ProjectID:     1,
+			Actions:       []*BucketAction{{Kind: "explode"}},
+		}
+		err := b.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidBucketAction(err))
+	})
 }
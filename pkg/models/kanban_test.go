@@ -75,6 +75,34 @@ func TestBucket_ReadAll(t *testing.T) {
 		assert.Equal(t, int64(3), buckets[2].Tasks[1].BucketID)
 		assert.Equal(t, int64(3), buckets[2].Tasks[2].BucketID)
 	})
+	t.Run("done bucket hiding old done tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Where("id = ?", 4).
+			Cols("done_bucket_configuration").
+			Update(&ProjectView{DoneBucketConfiguration: &ProjectViewDoneBucketConfiguration{ArchiveAfterDays: 7}})
+		require.NoError(t, err)
+
+		testuser := &user.User{ID: 1}
+		b := &TaskCollection{
+			ProjectViewID: 4,
+			ProjectID:     1,
+		}
+		bucketsInterface, _, _, err := b.ReadAll(s, testuser, "", 0, 0)
+		require.NoError(t, err)
+
+		buckets, is := bucketsInterface.([]*Bucket)
+		assert.True(t, is)
+		require.Len(t, buckets, 3)
+		assert.Equal(t, int64(3), buckets[2].ID)
+		// Task 2 is done but has no done date, so it is hidden
+		assert.Len(t, buckets[2].Tasks, 3)
+		for _, task := range buckets[2].Tasks {
+			assert.NotEqual(t, int64(2), task.ID)
+		}
+	})
 	t.Run("filtered", func(t *testing.T) {
 		db.LoadAndAssertFixtures(t)
 		s := db.NewSession()
@@ -267,4 +295,73 @@ func TestBucket_Update(t *testing.T) {
 
 		testAndAssertBucketUpdate(t, b, s)
 	})
+	t.Run("archive and restore", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		u := &user.User{ID: 1}
+
+		b := &Bucket{
+			ID:            2,
+			Title:         "testbucket2",
+			Limit:         3,
+			Position:      2,
+			ProjectViewID: 4,
+			ProjectID:     1,
+			IsArchived:    true,
+		}
+		err := b.Update(s, u)
+		require.NoError(t, err)
+
+		tc := &TaskCollection{ProjectID: 1, ProjectViewID: 4}
+		result, _, _, err := tc.ReadAll(s, u, "", 0, 0)
+		require.NoError(t, err)
+		buckets := result.([]*Bucket)
+		assert.Len(t, buckets, 2)
+		for _, bucket := range buckets {
+			assert.NotEqual(t, int64(2), bucket.ID)
+		}
+
+		archived := &Bucket{ProjectID: 1, ProjectViewID: 4, ShowArchived: true}
+		result, _, _, err = archived.ReadAll(s, u, "", 0, 0)
+		require.NoError(t, err)
+		buckets = result.([]*Bucket)
+		require.Len(t, buckets, 1)
+		assert.Equal(t, int64(2), buckets[0].ID)
+
+		tb := &TaskBucket{TaskID: 1, BucketID: 2, ProjectViewID: 4, ProjectID: 1}
+		err = tb.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrBucketIsArchived(err))
+
+		restored := &Bucket{ID: 2, ProjectID: 1, ProjectViewID: 4}
+		err = RestoreBucket(s, restored, u)
+		require.NoError(t, err)
+		assert.False(t, restored.IsArchived)
+		assert.Equal(t, "testbucket2", restored.Title)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "buckets", map[string]interface{}{
+			"id":          2,
+			"is_archived": false,
+			"position":    2,
+		}, false)
+	})
+	t.Run("archive done bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		b := &Bucket{
+			ID:            3,
+			Title:         "testbucket3",
+			ProjectViewID: 4,
+			ProjectID:     1,
+			IsArchived:    true,
+		}
+		err := b.Update(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrCannotArchiveDefaultBucket(err))
+	})
 }
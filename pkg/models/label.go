@@ -23,6 +23,7 @@ import (
 	"code.vikunja.io/api/pkg/utils"
 
 	"code.vikunja.io/web"
+	"xorm.io/builder"
 	"xorm.io/xorm"
 )
 
@@ -41,6 +42,18 @@ type Label struct {
 	// The user who created this label
 	CreatedBy *user.User `xorm:"-" json:"created_by"`
 
+	// The team this label belongs to. All members of the team can use it, only team admins can change it.
+	// Labels without a team or project only belong to the user who created them.
+	TeamID int64 `xorm:"bigint null INDEX" json:"team_id"`
+	// The project this label belongs to. Everyone with access to the project can use it, everyone who can write
+	// to the project can change it. A label can't belong to a team and a project at the same time.
+	ProjectID int64 `xorm:"bigint null INDEX" json:"project_id"`
+
+	// The id of the label group this label is in, if any.
+	GroupID int64 `xorm:"bigint null INDEX" json:"group_id"`
+	// The label group this label is in. Read only, set the group_id to change it.
+	Group *LabelGroup `xorm:"-" json:"group"`
+
 	// A timestamp when this label was created. You cannot change this value.
 	Created time.Time `xorm:"created not null" json:"created"`
 	// A timestamp when this label was last updated. You cannot change this value.
@@ -65,6 +78,7 @@ func (Label) TableName() string {
 // @Param label body models.Label true "The label object"
 // @Success 201 {object} models.Label "The created label object."
 // @Failure 400 {object} web.HTTPError "Invalid label object provided."
+// @Failure 403 {object} web.HTTPError "The user is not allowed to create labels for this team or project."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /labels [put]
 func (l *Label) Create(s *xorm.Session, a web.Auth) (err error) {
@@ -73,6 +87,15 @@ func (l *Label) Create(s *xorm.Session, a web.Auth) (err error) {
 		return
 	}
 
+	if l.TeamID != 0 && l.ProjectID != 0 {
+		return ErrLabelHasMultipleOwners{}
+	}
+
+	err = checkLabelGroupAccess(s, a, l.GroupID)
+	if err != nil {
+		return
+	}
+
 	l.HexColor = utils.NormalizeHex(l.HexColor)
 	l.CreatedBy = u
 	l.CreatedByID = u.ID
@@ -83,7 +106,7 @@ func (l *Label) Create(s *xorm.Session, a web.Auth) (err error) {
 
 // Update updates a label
 // @Summary Update a label
-// @Description Update an existing label. The user needs to be the creator of the label to be able to do this.
+// @Description Update an existing label. The user needs to be the creator of the label, an admin of the team or able to write to the project the label belongs to to be able to do this.
 // @tags labels
 // @Accept json
 // @Produce json
@@ -100,12 +123,24 @@ func (l *Label) Update(s *xorm.Session, a web.Auth) (err error) {
 
 	l.HexColor = utils.NormalizeHex(l.HexColor)
 
+	old, err := getLabelByIDSimple(s, l.ID)
+	if err != nil {
+		return
+	}
+	if old.GroupID != l.GroupID {
+		err = checkLabelGroupAccess(s, a, l.GroupID)
+		if err != nil {
+			return
+		}
+	}
+
 	_, err = s.
 		ID(l.ID).
 		Cols(
 			"title",
 			"description",
 			"hex_color",
+			"group_id",
 		).
 		Update(l)
 	if err != nil {
@@ -118,7 +153,7 @@ func (l *Label) Update(s *xorm.Session, a web.Auth) (err error) {
 
 // Delete deletes a label
 // @Summary Delete a label
-// @Description Delete an existing label. The user needs to be the creator of the label to be able to do this.
+// @Description Delete an existing label. The user needs to be the creator of the label, an admin of the team or able to write to the project the label belongs to to be able to do this.
 // @tags labels
 // @Accept json
 // @Produce json
@@ -134,9 +169,20 @@ func (l *Label) Delete(s *xorm.Session, _ web.Auth) (err error) {
 	return err
 }
 
+// makeSharedLabelsPersonal turns labels which belong to a team or project into personal labels of the users who
+// created them. Used when the team or project is deleted, to not remove the labels from all tasks they are on.
+func makeSharedLabelsPersonal(s *xorm.Session, cond builder.Cond) (err error) {
+	_, err = s.
+		Where(cond).
+		Cols("team_id", "project_id").
+		NoAutoCondition().
+		Update(&Label{})
+	return
+}
+
 // ReadAll gets all labels a user can use
 // @Summary Get all labels a user has access to
-// @Description Returns all labels which are either created by the user or associated with a task the user has at least read-access to.
+// @Description Returns all labels which are either created by the user, belong to one of the user's teams or projects or are associated with a task the user has at least read-access to.
 // @tags labels
 // @Accept json
 // @Produce json
@@ -185,7 +231,25 @@ func (l *Label) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
 	}
 
 	l.CreatedBy = u
-	return
+
+	return addGroupsToLabels(s, []*Label{l})
+}
+
+// checkLabelGroupAccess makes sure a label can be put into the given group. Only the creator of a group can add
+// labels to it.
+func checkLabelGroupAccess(s *xorm.Session, a web.Auth, groupID int64) error {
+	if groupID == 0 {
+		return nil
+	}
+
+	group, err := getLabelGroupByID(s, groupID)
+	if err != nil {
+		return err
+	}
+	if group.CreatedByID != a.GetID() {
+		return ErrGenericForbidden{}
+	}
+	return nil
 }
 
 func getLabelByIDSimple(s *xorm.Session, labelID int64) (*Label, error) {
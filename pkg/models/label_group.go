@@ -0,0 +1,292 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// LabelGroup is a named group of labels, for example "Priority" or "Component".
+type LabelGroup struct {
+	// The unique, numeric id of this label group.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"labelgroup"`
+	// The title of the label group.
+	Title string `xorm:"varchar(250) not null" json:"title" valid:"runelength(1|250)" minLength:"1" maxLength:"250"`
+	// The label group description.
+	Description string `xorm:"longtext null" json:"description"`
+	// If true, a task can only have one label of this group at a time.
+	Exclusive bool `xorm:"bool not null default false" json:"exclusive"`
+
+	CreatedByID int64 `xorm:"bigint not null INDEX" json:"-"`
+	// The user who created this label group
+	CreatedBy *user.User `xorm:"-" json:"created_by"`
+
+	// A timestamp when this label group was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this label group was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName makes a pretty table name
+func (*LabelGroup) TableName() string {
+	return "label_groups"
+}
+
+// Create creates a new label group
+// @Summary Create a label group
+// @Description Creates a new label group.
+// @tags labels
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param group body models.LabelGroup true "The label group object"
+// @Success 201 {object} models.LabelGroup "The created label group object."
+// @Failure 400 {object} web.HTTPError "Invalid label group object provided."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /labels/groups [put]
+func (g *LabelGroup) Create(s *xorm.Session, a web.Auth) (err error) {
+	u, err := user.GetFromAuth(a)
+	if err != nil {
+		return
+	}
+
+	g.ID = 0
+	g.CreatedBy = u
+	g.CreatedByID = u.ID
+
+	_, err = s.Insert(g)
+	return
+}
+
+// ReadAll returns all label groups of the current user
+// @Summary Get all label groups
+// @Description Returns all label groups the current user created.
+// @tags labels
+// @Accept json
+// @Produce json
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Param s query string false "Search label groups by title."
+// @Security JWTKeyAuth
+// @Success 200 {array} models.LabelGroup "The label groups"
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /labels/groups [get]
+func (g *LabelGroup) ReadAll(s *xorm.Session, a web.Auth, search string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	u, err := user.GetFromAuth(a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	cond := builder.And(
+		builder.Eq{"created_by_id": u.ID},
+		db.ILIKE("title", search),
+	)
+
+	limit, start := getLimitFromPageIndex(page, perPage)
+	groups := []*LabelGroup{}
+	query := s.Where(cond).OrderBy("id ASC")
+	if limit > 0 {
+		query = query.Limit(limit, start)
+	}
+	err = query.Find(&groups)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	for _, group := range groups {
+		group.CreatedBy = u
+	}
+
+	numberOfTotalItems, err = s.Where(cond).Count(&LabelGroup{})
+	return groups, len(groups), numberOfTotalItems, err
+}
+
+// ReadOne returns one label group
+// @Summary Get one label group
+// @Description Returns one label group by its ID.
+// @tags labels
+// @Accept json
+// @Produce json
+// @Param id path int true "Label group ID"
+// @Security JWTKeyAuth
+// @Success 200 {object} models.LabelGroup "The label group"
+// @Failure 403 {object} web.HTTPError "The user does not have access to the label group"
+// @Failure 404 {object} web.HTTPError "Label group not found"
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /labels/groups/{id} [get]
+func (g *LabelGroup) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	group, err := getLabelGroupByID(s, g.ID)
+	if err != nil {
+		return
+	}
+	*g = *group
+
+	g.CreatedBy, err = user.GetUserByID(s, g.CreatedByID)
+	return
+}
+
+// Update updates a label group
+// @Summary Update a label group
+// @Description Updates a label group. Making a group exclusive does not remove labels from tasks which already have more than one label of the group.
+// @tags labels
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Label group ID"
+// @Param group body models.LabelGroup true "The label group object"
+// @Success 200 {object} models.LabelGroup "The updated label group object."
+// @Failure 400 {object} web.HTTPError "Invalid label group object provided."
+// @Failure 403 {object} web.HTTPError "Not allowed to update the label group."
+// @Failure 404 {object} web.HTTPError "Label group not found."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /labels/groups/{id} [post]
+func (g *LabelGroup) Update(s *xorm.Session, a web.Auth) (err error) {
+	_, err = s.
+		ID(g.ID).
+		Cols(
+			"title",
+			"description",
+			"exclusive",
+		).
+		Update(g)
+	if err != nil {
+		return
+	}
+
+	return g.ReadOne(s, a)
+}
+
+// Delete deletes a label group
+// @Summary Delete a label group
+// @Description Deletes a label group. The labels in it are kept, they just don't belong to a group anymore.
+// @tags labels
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Label group ID"
+// @Success 200 {object} models.Message "The label group was successfully deleted."
+// @Failure 403 {object} web.HTTPError "Not allowed to delete the label group."
+// @Failure 404 {object} web.HTTPError "Label group not found."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /labels/groups/{id} [delete]
+func (g *LabelGroup) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	err = removeLabelsFromGroups(s, builder.Eq{"group_id": g.ID})
+	if err != nil {
+		return
+	}
+
+	_, err = s.ID(g.ID).Delete(&LabelGroup{})
+	return
+}
+
+func getLabelGroupByID(s *xorm.Session, id int64) (group *LabelGroup, err error) {
+	group = &LabelGroup{}
+	exists, err := s.Where("id = ?", id).Get(group)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrLabelGroupDoesNotExist{GroupID: id}
+	}
+	return
+}
+
+func removeLabelsFromGroups(s *xorm.Session, cond builder.Cond) (err error) {
+	_, err = s.
+		Where(cond).
+		Cols("group_id").
+		NoAutoCondition().
+		Update(&Label{})
+	return
+}
+
+// addGroupsToLabels loads the groups of all given labels.
+func addGroupsToLabels(s *xorm.Session, labels []*Label) (err error) {
+	groupIDs := []int64{}
+	for _, l := range labels {
+		if l.GroupID != 0 {
+			groupIDs = append(groupIDs, l.GroupID)
+		}
+	}
+	if len(groupIDs) == 0 {
+		return nil
+	}
+
+	groups := make(map[int64]*LabelGroup, len(groupIDs))
+	err = s.In("id", groupIDs).Find(&groups)
+	if err != nil {
+		return
+	}
+
+	for _, l := range labels {
+		l.Group = groups[l.GroupID]
+	}
+	return
+}
+
+// checkExclusiveLabelGroupsOfTask makes sure adding labels to a task does not leave it with more than one label of
+// an exclusive group.
+func checkExclusiveLabelGroupsOfTask(s *xorm.Session, taskID int64, newLabelIDs ...int64) (err error) {
+	labelIDs := []int64{}
+	err = s.Table("label_tasks").Where("task_id = ?", taskID).Cols("label_id").Find(&labelIDs)
+	if err != nil {
+		return err
+	}
+	return checkExclusiveLabelGroups(s, append(labelIDs, newLabelIDs...))
+}
+
+// checkExclusiveLabelGroups makes sure a set of labels which should end up on the same task does not contain more
+// than one label of an exclusive group.
+func checkExclusiveLabelGroups(s *xorm.Session, labelIDs []int64) (err error) {
+	if len(labelIDs) < 2 {
+		return nil
+	}
+
+	labels := []*Label{}
+	err = s.
+		Select("labels.*").
+		Join("INNER", "label_groups", "label_groups.id = labels.group_id").
+		Where(builder.And(
+			builder.In("labels.id", labelIDs),
+			builder.Eq{"label_groups.exclusive": true},
+		)).
+		OrderBy("labels.id ASC").
+		Find(&labels)
+	if err != nil {
+		return
+	}
+
+	seen := make(map[int64]int64, len(labels))
+	for _, l := range labels {
+		if other, has := seen[l.GroupID]; has {
+			return ErrLabelGroupIsExclusive{GroupID: l.GroupID, LabelID: l.ID, OtherLabelID: other}
+		}
+		seen[l.GroupID] = l.ID
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanCreate checks if a user can create a label group
+func (g *LabelGroup) CanCreate(_ *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+	return true, nil
+}
+
+// CanRead checks if a user can read a label group
+func (g *LabelGroup) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	is, err := g.isOwner(s, a)
+	return is, int(RightAdmin), err
+}
+
+// CanUpdate checks if a user can update a label group
+func (g *LabelGroup) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return g.isOwner(s, a)
+}
+
+// CanDelete checks if a user can delete a label group
+func (g *LabelGroup) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return g.isOwner(s, a)
+}
+
+func (g *LabelGroup) isOwner(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	group, err := getLabelGroupByID(s, g.ID)
+	if err != nil {
+		return false, err
+	}
+	return group.CreatedByID == a.GetID(), nil
+}
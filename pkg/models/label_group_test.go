@@ -0,0 +1,160 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func TestLabelGroup(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	createGroup := func(t *testing.T, s *xorm.Session, exclusive bool) *LabelGroup {
+		group := &LabelGroup{Title: "Priority", Exclusive: exclusive}
+		err := group.Create(s, u)
+		require.NoError(t, err)
+
+		for _, id := range []int64{1, 2} {
+			label := &Label{ID: id}
+			err = label.ReadOne(s, u)
+			require.NoError(t, err)
+			label.GroupID = group.ID
+			err = label.Update(s, u)
+			require.NoError(t, err)
+			require.NotNil(t, label.Group)
+			assert.Equal(t, group.ID, label.Group.ID)
+		}
+		return group
+	}
+
+	t.Run("exclusive group, single label", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		createGroup(t, s, true)
+
+		err := (&LabelTask{TaskID: 1, LabelID: 1}).Create(s, u)
+		require.NoError(t, err)
+		err = (&LabelTask{TaskID: 1, LabelID: 2}).Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrLabelGroupIsExclusive(err))
+	})
+	t.Run("exclusive group, bulk", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		createGroup(t, s, true)
+
+		err := (&LabelTaskBulk{TaskID: 1, Labels: []*Label{{ID: 1}, {ID: 2}}}).Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrLabelGroupIsExclusive(err))
+
+		// Replacing one label of the group with another one is fine
+		err = (&LabelTaskBulk{TaskID: 1, Labels: []*Label{{ID: 1}}}).Create(s, u)
+		require.NoError(t, err)
+		err = (&LabelTaskBulk{TaskID: 1, Labels: []*Label{{ID: 2}}}).Create(s, u)
+		require.NoError(t, err)
+	})
+	t.Run("non-exclusive group", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		createGroup(t, s, false)
+
+		err := (&LabelTaskBulk{TaskID: 1, Labels: []*Label{{ID: 1}, {ID: 2}}}).Create(s, u)
+		require.NoError(t, err)
+	})
+	t.Run("exclusive group, task defaults", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		createGroup(t, s, true)
+		_, err := s.ID(1).Cols("task_defaults").Update(&Project{TaskDefaults: &ProjectTaskDefaults{
+			LabelIDs: []int64{1, 2},
+		}})
+		require.NoError(t, err)
+
+		task := &Task{Title: "Lorem", ProjectID: 1}
+		err = task.Create(s, u)
+		require.NoError(t, err)
+		require.Len(t, task.Labels, 1)
+		assert.Equal(t, int64(1), task.Labels[0].ID)
+	})
+	t.Run("exclusive group, bulk task create", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		createGroup(t, s, true)
+
+		btc := &BulkTaskCreate{
+			ProjectID: 1,
+			Tasks: []*Task{
+				{
+					Title:  "bulk one",
+					Labels: []*Label{{ID: 1}, {ID: 2}},
+				},
+			},
+		}
+		err := btc.Create(s, u)
+		require.NoError(t, err)
+		require.Len(t, btc.Results, 1)
+		require.NotNil(t, btc.Results[0].Error)
+		assert.Equal(t, ErrCodeLabelGroupIsExclusive, btc.Results[0].Error.Code)
+	})
+	t.Run("group of another user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		group := &LabelGroup{Title: "Component"}
+		err := group.Create(s, &user.User{ID: 2})
+		require.NoError(t, err)
+
+		err = (&Label{Title: "test", GroupID: group.ID}).Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+
+		can, _, err := (&LabelGroup{ID: group.ID}).CanRead(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("delete", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		group := createGroup(t, s, true)
+		err := group.Delete(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		db.AssertMissing(t, "label_groups", map[string]interface{}{"id": group.ID})
+		db.AssertExists(t, "labels", map[string]interface{}{"id": 1, "group_id": 0}, false)
+	})
+}
@@ -0,0 +1,117 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// LabelMerge holds everything needed to merge one label into another
+type LabelMerge struct {
+	// The id of the label which is merged into the target label and deleted afterwards.
+	LabelID int64 `json:"-" param:"label"`
+	// The id of the label which is kept.
+	TargetLabelID int64 `json:"-" param:"otherlabel"`
+
+	// The target label after the merge.
+	Label *Label `json:"label,omitempty"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// CanCreate checks if a user has the right to merge a label into another one
+func (lm *LabelMerge) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	if lm.LabelID == lm.TargetLabelID {
+		return false, ErrCannotMergeLabelIntoItself{LabelID: lm.LabelID}
+	}
+
+	// The merged label is deleted afterwards and the target label ends up on all of its tasks,
+	// so the user needs to be able to manage both of them.
+	can, err := (&Label{ID: lm.LabelID}).isLabelOwner(s, a)
+	if err != nil || !can {
+		return can, err
+	}
+
+	return (&Label{ID: lm.TargetLabelID}).isLabelOwner(s, a)
+}
+
+// Create merges a label into another one
+// @Summary Merge a label into another one
+// @Description Moves the label from all tasks it is on to the target label and deletes it afterwards. Tasks which already have both labels keep only the target label. The user needs to be able to update both labels.
+// @tags labels
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "The id of the label to merge into the other one. This label is deleted."
+// @Param otherID path int true "The id of the label to keep."
+// @Success 201 {object} models.LabelMerge "The target label after the merge."
+// @Failure 400 {object} web.HTTPError "The label cannot be merged into itself."
+// @Failure 403 {object} web.HTTPError "The user does not have access to one of the labels."
+// @Failure 404 {object} web.HTTPError "One of the labels does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /labels/{id}/merge/{otherID} [post]
+func (lm *LabelMerge) Create(s *xorm.Session, a web.Auth) (err error) {
+	source, err := getLabelByIDSimple(s, lm.LabelID)
+	if err != nil {
+		return err
+	}
+	target, err := getLabelByIDSimple(s, lm.TargetLabelID)
+	if err != nil {
+		return err
+	}
+
+	taskIDsWithTarget := []int64{}
+	err = s.
+		Table("label_tasks").
+		Where("label_id = ?", target.ID).
+		Cols("task_id").
+		Find(&taskIDsWithTarget)
+	if err != nil {
+		return err
+	}
+
+	if len(taskIDsWithTarget) > 0 {
+		_, err = s.
+			Where(builder.And(
+				builder.Eq{"label_id": source.ID},
+				builder.In("task_id", taskIDsWithTarget),
+			)).
+			Delete(&LabelTask{})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = s.
+		Where("label_id = ?", source.ID).
+		Cols("label_id").
+		Update(&LabelTask{LabelID: target.ID})
+	if err != nil {
+		return err
+	}
+
+	err = source.Delete(s, a)
+	if err != nil {
+		return err
+	}
+
+	lm.Label = &Label{ID: target.ID}
+	return lm.Label.ReadOne(s, a)
+}
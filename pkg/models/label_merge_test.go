@@ -0,0 +1,78 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/builder"
+)
+
+func TestLabelMerge_Create(t *testing.T) {
+	u := &user.User{ID: 2}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// Task 1 has both labels afterwards
+		_, err := s.Insert(&LabelTask{TaskID: 1, LabelID: 3})
+		require.NoError(t, err)
+
+		lm := &LabelMerge{LabelID: 4, TargetLabelID: 3}
+		can, err := lm.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = lm.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), lm.Label.ID)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "labels", map[string]interface{}{"id": 4})
+		db.AssertMissing(t, "label_tasks", map[string]interface{}{"label_id": 4})
+		db.AssertCount(t, "label_tasks", builder.Eq{"task_id": 1, "label_id": 3}, 1)
+		db.AssertExists(t, "label_tasks", map[string]interface{}{
+			"task_id":  40,
+			"label_id": 3,
+		}, false)
+	})
+	t.Run("into itself", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := (&LabelMerge{LabelID: 4, TargetLabelID: 4}).CanCreate(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrCannotMergeLabelIntoItself(err))
+	})
+	t.Run("no rights on target", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		can, err := (&LabelMerge{LabelID: 4, TargetLabelID: 1}).CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
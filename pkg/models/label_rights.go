@@ -32,19 +32,34 @@ func (l *Label) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
 	return l.isLabelOwner(s, a) // Only owners should be allowed to delete a label
 }
 
+// canManageLabel checks if a user may change labels belonging to the same team, project or user as the given label.
+func canManageLabel(s *xorm.Session, a web.Auth, label *Label) (bool, error) {
+	switch {
+	case label.TeamID != 0:
+		return (&Team{ID: label.TeamID}).IsAdmin(s, a)
+	case label.ProjectID != 0:
+		return (&Project{ID: label.ProjectID}).CanWrite(s, a)
+	default:
+		return label.CreatedByID == a.GetID(), nil
+	}
+}
+
 // CanRead checks if a user can read a label
 func (l *Label) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
 	return l.hasAccessToLabel(s, a)
 }
 
 // CanCreate checks if the user can create a label
-// Currently a dummy.
-func (l *Label) CanCreate(_ *xorm.Session, a web.Auth) (bool, error) {
+func (l *Label) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
 	if _, is := a.(*LinkSharing); is {
 		return false, nil
 	}
 
-	return true, nil
+	if l.TeamID == 0 && l.ProjectID == 0 {
+		return true, nil
+	}
+
+	return canManageLabel(s, a, &Label{TeamID: l.TeamID, ProjectID: l.ProjectID})
 }
 
 func (l *Label) isLabelOwner(s *xorm.Session, a web.Auth) (bool, error) {
@@ -57,7 +72,21 @@ func (l *Label) isLabelOwner(s *xorm.Session, a web.Auth) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	return lorig.CreatedByID == a.GetID(), nil
+	return canManageLabel(s, a, lorig)
+}
+
+// getSharedLabelsCond returns the condition for all labels which belong to one of the user's teams or one of the
+// given projects. The projects can be a list of ids or a subquery.
+func getSharedLabelsCond(userID int64, projects interface{}) builder.Cond {
+	return builder.Or(
+		builder.In("labels.team_id",
+			builder.
+				Select("team_id").
+				From("team_members").
+				Where(builder.Eq{"user_id": userID}),
+		),
+		builder.In("labels.project_id", projects),
+	)
 }
 
 // Helper method to check if a user can see a specific label
@@ -67,11 +96,14 @@ func (l *Label) hasAccessToLabel(s *xorm.Session, a web.Auth) (has bool, maxRigh
 
 	var where builder.Cond
 	var createdByID int64
+	var sharedCond builder.Cond
 	if isLinkShare {
 		where = builder.Eq{"project_id": linkShare.ProjectID}
+		sharedCond = builder.Eq{"labels.project_id": linkShare.ProjectID}
 	} else {
 		where = builder.In("project_id", getUserProjectsStatement(a.GetID(), "", false).Select("l.id"))
 		createdByID = a.GetID()
+		sharedCond = getSharedLabelsCond(a.GetID(), getUserProjectsStatement(a.GetID(), "", false).Select("l.id"))
 	}
 
 	cond := builder.In("label_tasks.task_id",
@@ -87,6 +119,7 @@ func (l *Label) hasAccessToLabel(s *xorm.Session, a web.Auth) (has bool, maxRigh
 		Join("LEFT", "label_tasks", "label_tasks.label_id = labels.id").
 		Where("label_tasks.label_id is not null OR labels.created_by_id = ?", createdByID).
 		Or(cond).
+		Or(sharedCond).
 		And("labels.id = ?", l.ID).
 		Exist(ll)
 	if err != nil {
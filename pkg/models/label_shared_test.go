@@ -0,0 +1,115 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/builder"
+)
+
+func TestLabel_Shared(t *testing.T) {
+	u1 := &user.User{ID: 1}
+	u2 := &user.User{ID: 2}
+	u3 := &user.User{ID: 3}
+
+	t.Run("team label", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 2 is a member of team 1, but not an admin
+		label := &Label{Title: "team label", TeamID: 1}
+		can, err := label.CanCreate(s, u2)
+		require.NoError(t, err)
+		assert.False(t, can)
+
+		can, err = label.CanCreate(s, u1)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = label.Create(s, u1)
+		require.NoError(t, err)
+
+		can, _, err = (&Label{ID: label.ID}).CanRead(s, u2)
+		require.NoError(t, err)
+		assert.True(t, can)
+		can, err = (&Label{ID: label.ID}).CanUpdate(s, u2)
+		require.NoError(t, err)
+		assert.False(t, can)
+		can, _, err = (&Label{ID: label.ID}).CanRead(s, u3)
+		require.NoError(t, err)
+		assert.False(t, can)
+
+		labels, _, _, err := (&Label{}).ReadAll(s, u2, "team label", 0, 50)
+		require.NoError(t, err)
+		require.Len(t, labels, 1)
+		assert.Equal(t, label.ID, labels.([]*LabelWithTaskID)[0].ID)
+	})
+	t.Run("project label", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		label := &Label{Title: "project label", ProjectID: 1}
+		can, err := label.CanCreate(s, u2)
+		require.NoError(t, err)
+		assert.False(t, can)
+
+		err = label.Create(s, u1)
+		require.NoError(t, err)
+
+		can, err = (&Label{ID: label.ID}).CanUpdate(s, u1)
+		require.NoError(t, err)
+		assert.True(t, can)
+		can, _, err = (&Label{ID: label.ID}).CanRead(s, u2)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("team and project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&Label{Title: "both", TeamID: 1, ProjectID: 1}).Create(s, u1)
+		require.Error(t, err)
+		assert.True(t, IsErrLabelHasMultipleOwners(err))
+	})
+	t.Run("made personal when the team is gone", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		label := &Label{Title: "team label", TeamID: 1}
+		err := label.Create(s, u1)
+		require.NoError(t, err)
+
+		err = makeSharedLabelsPersonal(s, builder.Eq{"team_id": 1})
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		db.AssertExists(t, "labels", map[string]interface{}{
+			"id":            label.ID,
+			"team_id":       0,
+			"created_by_id": 1,
+		}, false)
+	})
+}
@@ -0,0 +1,218 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"sort"
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// LabelStatistics holds numbers about how labels are used, either in one project or in all projects of a user.
+type LabelStatistics struct {
+	// If set, only tasks in this project are counted.
+	ProjectID int64 `json:"-" query:"project_id"`
+	// How many days the timeline should cover. Defaults to 30, can be at most 365.
+	Days int `json:"-" query:"days"`
+
+	// All labels which are on at least one task, the most used ones first.
+	Labels []*LabelUsageStatistics `json:"labels"`
+	// All labels the user can use which are not on any task.
+	Unused []*Label `json:"unused"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// LabelUsageStatistics holds the number of tasks with a label
+type LabelUsageStatistics struct {
+	Label *Label `json:"label"`
+	// The number of tasks with this label which are not done.
+	Open int64 `json:"open"`
+	// The number of tasks with this label which are done.
+	Done int64 `json:"done"`
+	// How often the label was added to a task on each of the last days, oldest first.
+	Timeline []*LabelTimelineStatistics `json:"timeline"`
+}
+
+// LabelTimelineStatistics holds the number of tasks a label was added to on one day
+type LabelTimelineStatistics struct {
+	// The day in the format YYYY-MM-DD, in the timezone of the user.
+	Date  string `json:"date"`
+	Added int64  `json:"added"`
+}
+
+// CanRead checks if a user can see label statistics
+func (ls *LabelStatistics) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	if ls.ProjectID != 0 {
+		return (&Project{ID: ls.ProjectID}).CanRead(s, a)
+	}
+	return true, int(RightRead), nil
+}
+
+// ReadOne returns the label statistics
+// @Summary Get label usage statistics
+// @Description Returns how many open and done tasks each label is on, how often it was added to tasks per day and which labels are not used at all. Counts either the tasks in one project or in all projects the user has access to. Labels removed from a task are not part of the timeline anymore.
+// @tags labels
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project_id query int false "Only count tasks in this project."
+// @Param days query int false "How many days the timeline should cover. Defaults to 30, can be at most 365."
+// @Success 200 {object} models.LabelStatistics "The label statistics."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /labels/stats [get]
+func (ls *LabelStatistics) ReadOne(s *xorm.Session, a web.Auth) (err error) {
+	projectIDs, err := ls.getProjectIDs(s, a)
+	if err != nil {
+		return err
+	}
+
+	labels, _, _, err := GetLabelsByTaskIDs(s, &LabelByTaskIDsOptions{
+		User:                a,
+		GetUnusedLabels:     true,
+		GroupByLabelIDsOnly: true,
+		GetForUser:          true,
+	})
+	if err != nil {
+		return err
+	}
+
+	counts := []*struct {
+		LabelID int64
+		Done    bool
+		Count   int64
+	}{}
+	if len(projectIDs) > 0 {
+		err = s.
+			Table("label_tasks").
+			Select("label_tasks.label_id, tasks.done, COUNT(*) AS count").
+			Join("INNER", "tasks", "tasks.id = label_tasks.task_id").
+			Where(builder.In("tasks.project_id", projectIDs)).
+			GroupBy("label_tasks.label_id, tasks.done").
+			Find(&counts)
+		if err != nil {
+			return err
+		}
+	}
+
+	byLabel := make(map[int64]*LabelUsageStatistics, len(labels))
+	ls.Labels = []*LabelUsageStatistics{}
+	ls.Unused = []*Label{}
+	for _, l := range labels {
+		byLabel[l.ID] = &LabelUsageStatistics{Label: &l.Label}
+	}
+	for _, c := range counts {
+		stats, has := byLabel[c.LabelID]
+		if !has {
+			continue
+		}
+		if c.Done {
+			stats.Done += c.Count
+			continue
+		}
+		stats.Open += c.Count
+	}
+
+	for _, l := range labels {
+		stats := byLabel[l.ID]
+		if stats.Open+stats.Done == 0 {
+			ls.Unused = append(ls.Unused, &l.Label)
+			continue
+		}
+		ls.Labels = append(ls.Labels, stats)
+	}
+	sort.SliceStable(ls.Labels, func(i, j int) bool {
+		return ls.Labels[i].Open+ls.Labels[i].Done > ls.Labels[j].Open+ls.Labels[j].Done
+	})
+
+	tz, err := getTimezoneForAuth(s, a)
+	if err != nil {
+		return err
+	}
+
+	return ls.addTimelines(s, projectIDs, time.Now().In(tz))
+}
+
+func (ls *LabelStatistics) getProjectIDs(s *xorm.Session, a web.Auth) ([]int64, error) {
+	if ls.ProjectID != 0 {
+		return []int64{ls.ProjectID}, nil
+	}
+
+	if linkShare, is := a.(*LinkSharing); is {
+		return []int64{linkShare.ProjectID}, nil
+	}
+
+	projects, _, _, err := getRawProjectsForUser(s, &projectOptions{
+		user: &user.User{ID: a.GetID()},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	projectIDs := make([]int64, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+	return projectIDs, nil
+}
+
+// addTimelines counts how often each used label was added to a task per day, in the timezone of the user.
+func (ls *LabelStatistics) addTimelines(s *xorm.Session, projectIDs []int64, now time.Time) (err error) {
+	days, since := getStatisticsTimeframe(ls.Days, now)
+
+	byDate := make(map[int64]map[string]*LabelTimelineStatistics, len(ls.Labels))
+	for _, stats := range ls.Labels {
+		stats.Timeline = make([]*LabelTimelineStatistics, 0, days)
+		byDate[stats.Label.ID] = make(map[string]*LabelTimelineStatistics, days)
+		for i := 0; i < days; i++ {
+			day := &LabelTimelineStatistics{Date: since.AddDate(0, 0, i).Format(time.DateOnly)}
+			stats.Timeline = append(stats.Timeline, day)
+			byDate[stats.Label.ID][day.Date] = day
+		}
+	}
+
+	if len(ls.Labels) == 0 {
+		return nil
+	}
+
+	added := []*LabelTask{}
+	err = s.
+		Select("label_tasks.label_id, label_tasks.created").
+		Join("INNER", "tasks", "tasks.id = label_tasks.task_id").
+		Where(builder.And(
+			builder.In("tasks.project_id", projectIDs),
+			builder.Gte{"label_tasks.created": since},
+		)).
+		Find(&added)
+	if err != nil {
+		return err
+	}
+
+	for _, lt := range added {
+		if day, has := byDate[lt.LabelID][lt.Created.In(now.Location()).Format(time.DateOnly)]; has {
+			day.Added++
+		}
+	}
+
+	return nil
+}
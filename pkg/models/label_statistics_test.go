@@ -0,0 +1,78 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelStatistics_ReadOne(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Insert(&LabelTask{TaskID: 3, LabelID: 1})
+		require.NoError(t, err)
+
+		ls := &LabelStatistics{ProjectID: 1, Days: 7}
+		can, _, err := ls.CanRead(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = ls.ReadOne(s, u)
+		require.NoError(t, err)
+
+		require.Len(t, ls.Labels, 2)
+		assert.Equal(t, int64(4), ls.Labels[0].Label.ID)
+		assert.Equal(t, int64(1), ls.Labels[0].Open)
+		assert.Equal(t, int64(1), ls.Labels[0].Done)
+		assert.Equal(t, int64(1), ls.Labels[1].Label.ID)
+		assert.Equal(t, int64(1), ls.Labels[1].Open)
+
+		require.Len(t, ls.Labels[1].Timeline, 7)
+		tz, err := getTimezoneForAuth(s, u)
+		require.NoError(t, err)
+		today := ls.Labels[1].Timeline[6]
+		assert.Equal(t, time.Now().In(tz).Format(time.DateOnly), today.Date)
+		assert.Equal(t, int64(1), today.Added)
+
+		unused := []int64{}
+		for _, l := range ls.Unused {
+			unused = append(unused, l.ID)
+		}
+		assert.Contains(t, unused, int64(2))
+		assert.NotContains(t, unused, int64(1))
+	})
+	t.Run("no access to project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		can, _, err := (&LabelStatistics{ProjectID: 2}).CanRead(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
@@ -94,6 +94,11 @@ func (lt *LabelTask) Create(s *xorm.Session, auth web.Auth) (err error) {
 		return ErrLabelIsAlreadyOnTask{lt.LabelID, lt.TaskID}
 	}
 
+	err = checkExclusiveLabelGroupsOfTask(s, lt.TaskID, lt.LabelID)
+	if err != nil {
+		return err
+	}
+
 	// Insert it
 	_, err = s.Insert(lt)
 	if err != nil {
@@ -192,9 +197,9 @@ func GetLabelsByTaskIDs(s *xorm.Session, opts *LabelByTaskIDsOptions) (ls []*Lab
 	if len(opts.TaskIDs) > 0 && !opts.GetForUser {
 		cond = builder.And(builder.In("label_tasks.task_id", opts.TaskIDs), cond)
 	}
+	var projectIDs []int64
 	if opts.GetForUser {
 
-		var projectIDs []int64
 		if isLinkShareAuth {
 			projectIDs = []int64{linkShare.ProjectID}
 		} else {
@@ -220,6 +225,13 @@ func GetLabelsByTaskIDs(s *xorm.Session, opts *LabelByTaskIDsOptions) (ls []*Lab
 	if opts.GetUnusedLabels && !isLinkShareAuth {
 		cond = builder.Or(cond, builder.Eq{"labels.created_by_id": opts.User.GetID()})
 	}
+	if opts.GetUnusedLabels && opts.GetForUser {
+		if isLinkShareAuth {
+			cond = builder.Or(cond, builder.Eq{"labels.project_id": linkShare.ProjectID})
+		} else {
+			cond = builder.Or(cond, getSharedLabelsCond(opts.User.GetID(), projectIDs))
+		}
+	}
 
 	ids := []int64{}
 
@@ -296,8 +308,15 @@ func GetLabelsByTaskIDs(s *xorm.Session, opts *LabelByTaskIDsOptions) (ls []*Lab
 	}
 
 	// Put it all together
+	plainLabels := make([]*Label, 0, len(labels))
 	for in, l := range labels {
 		labels[in].CreatedBy = users[l.CreatedByID]
+		plainLabels = append(plainLabels, &labels[in].Label)
+	}
+
+	err = addGroupsToLabels(s, plainLabels)
+	if err != nil {
+		return nil, 0, 0, err
 	}
 
 	// Get the total number of entries
@@ -330,8 +349,15 @@ func (t *Task) UpdateTaskLabels(s *xorm.Session, creator web.Auth, labels []*Lab
 
 	// Make a hashmap of the new labels for easier comparison
 	newLabels := make(map[int64]*Label, len(labels))
+	newLabelIDs := make([]int64, 0, len(labels))
 	for _, newLabel := range labels {
 		newLabels[newLabel.ID] = newLabel
+		newLabelIDs = append(newLabelIDs, newLabel.ID)
+	}
+
+	err = checkExclusiveLabelGroups(s, newLabelIDs)
+	if err != nil {
+		return err
 	}
 
 	// Get old labels to delete
@@ -132,6 +132,7 @@ func (share *LinkSharing) toUser() *user.User {
 // @Failure 400 {object} web.HTTPError "Invalid link share object provided."
 // @Failure 403 {object} web.HTTPError "Not allowed to add the project share."
 // @Failure 404 {object} web.HTTPError "The project does not exist."
+// @Failure 412 {object} web.HTTPError "The user reached their limit of link shares."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /projects/{project}/shares [put]
 func (share *LinkSharing) Create(s *xorm.Session, a web.Auth) (err error) {
@@ -141,6 +142,11 @@ func (share *LinkSharing) Create(s *xorm.Session, a web.Auth) (err error) {
 		return
 	}
 
+	err = checkUserLinkShareQuota(s, a.GetID())
+	if err != nil {
+		return
+	}
+
 	share.SharedByID = a.GetID()
 	share.Hash = utils.MakeRandomString(40)
 
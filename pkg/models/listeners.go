@@ -56,7 +56,19 @@ func RegisterListeners() {
 	events.RegisterListener((&TaskCommentUpdatedEvent{}).Name(), &HandleTaskCommentEditMentions{})
 	events.RegisterListener((&TaskCreatedEvent{}).Name(), &HandleTaskCreateMentions{})
 	events.RegisterListener((&TaskUpdatedEvent{}).Name(), &HandleTaskUpdatedMentions{})
+	events.RegisterListener((&TaskCreatedEvent{}).Name(), &CheckSavedFilterSubscriptions{})
+	events.RegisterListener((&TaskUpdatedEvent{}).Name(), &CheckSavedFilterSubscriptions{})
+	events.RegisterListener((&TaskAssigneeCreatedEvent{}).Name(), &CheckSavedFilterSubscriptions{})
+	events.RegisterListener((&TaskAssigneeDeletedEvent{}).Name(), &CheckSavedFilterSubscriptions{})
+	events.RegisterListener((&TaskAssigneeCreatedEvent{}).Name(), &AddTaskAssignmentToInbox{})
+	events.RegisterListener((&TaskCreatedEvent{}).Name(), &AddTaskMentionsToInbox{})
+	events.RegisterListener((&TaskUpdatedEvent{}).Name(), &AddTaskMentionsToInbox{})
+	events.RegisterListener((&TaskCommentCreatedEvent{}).Name(), &AddCommentMentionsToInbox{})
+	events.RegisterListener((&TaskCommentUpdatedEvent{}).Name(), &AddCommentMentionsToInbox{})
+	events.RegisterListener((&ProjectSharedWithUserEvent{}).Name(), &AddProjectShareToInbox{})
+	events.RegisterListener((&ProjectSharedWithTeamEvent{}).Name(), &AddProjectShareToInbox{})
 	events.RegisterListener((&UserDataExportRequestedEvent{}).Name(), &HandleUserDataExport{})
+	events.RegisterListener((&ProjectDuplicationRequestedEvent{}).Name(), &HandleProjectDuplication{})
 	events.RegisterListener((&TaskCommentCreatedEvent{}).Name(), &HandleTaskUpdateLastUpdated{})
 	events.RegisterListener((&TaskCommentUpdatedEvent{}).Name(), &HandleTaskUpdateLastUpdated{})
 	events.RegisterListener((&TaskCommentDeletedEvent{}).Name(), &HandleTaskUpdateLastUpdated{})
@@ -66,11 +78,27 @@ func RegisterListeners() {
 	events.RegisterListener((&TaskAttachmentDeletedEvent{}).Name(), &HandleTaskUpdateLastUpdated{})
 	events.RegisterListener((&TaskRelationCreatedEvent{}).Name(), &HandleTaskUpdateLastUpdated{})
 	events.RegisterListener((&TaskRelationDeletedEvent{}).Name(), &HandleTaskUpdateLastUpdated{})
-	if config.TypesenseEnabled.GetBool() {
+	events.RegisterListener((&TaskApprovalRequestedEvent{}).Name(), &SendTaskApprovalRequestedNotification{})
+	events.RegisterListener((&TaskApprovedEvent{}).Name(), &SendTaskApprovedNotification{})
+	events.RegisterListener((&TaskRejectedEvent{}).Name(), &SendTaskRejectedNotification{})
+	events.RegisterListener((&ProjectTransferRequestedEvent{}).Name(), &SendProjectTransferRequestedNotification{})
+	registerEventForProjectActivity(&TaskCreatedEvent{})
+	registerEventForProjectActivity(&TaskMarkedDoneEvent{})
+	registerEventForProjectActivity(&TaskMovedToBucketEvent{})
+	registerEventForProjectActivity(&TaskCommentCreatedEvent{})
+	registerEventForProjectActivity(&TaskAssigneeCreatedEvent{})
+	registerEventForProjectActivity(&ProjectSharedWithUserEvent{})
+	registerEventForProjectActivity(&ProjectSharedWithTeamEvent{})
+	if isTypesenseEnabled() {
 		events.RegisterListener((&TaskDeletedEvent{}).Name(), &RemoveTaskFromTypesense{})
 		events.RegisterListener((&TaskCreatedEvent{}).Name(), &AddTaskToTypesense{})
 		events.RegisterListener((&TaskUpdatedEvent{}).Name(), &UpdateTaskInTypesense{})
 	}
+	if config.SearchBackend.GetString() == SearchBackendMeilisearch {
+		events.RegisterListener((&TaskDeletedEvent{}).Name(), &RemoveTaskFromSearchIndex{})
+		events.RegisterListener((&TaskCreatedEvent{}).Name(), &UpdateTaskInSearchIndex{})
+		events.RegisterListener((&TaskUpdatedEvent{}).Name(), &UpdateTaskInSearchIndex{})
+	}
 	if config.WebhooksEnabled.GetBool() {
 		RegisterEventForWebhook(&TaskCreatedEvent{})
 		RegisterEventForWebhook(&TaskUpdatedEvent{})
@@ -84,10 +112,22 @@ func RegisterListeners() {
 		RegisterEventForWebhook(&TaskAttachmentDeletedEvent{})
 		RegisterEventForWebhook(&TaskRelationCreatedEvent{})
 		RegisterEventForWebhook(&TaskRelationDeletedEvent{})
+		RegisterEventForWebhook(&TaskApprovalRequestedEvent{})
+		RegisterEventForWebhook(&TaskApprovedEvent{})
+		RegisterEventForWebhook(&TaskRejectedEvent{})
+		RegisterEventForWebhook(&TaskPriorityAgedEvent{})
+		RegisterEventForWebhook(&TaskBucketActionsExecutedEvent{})
 		RegisterEventForWebhook(&ProjectUpdatedEvent{})
 		RegisterEventForWebhook(&ProjectDeletedEvent{})
 		RegisterEventForWebhook(&ProjectSharedWithUserEvent{})
 		RegisterEventForWebhook(&ProjectSharedWithTeamEvent{})
+		RegisterChatIntegrations()
+	}
+	if config.TelegramEnabled.GetBool() {
+		RegisterTelegramTransport()
+	}
+	if config.WebPushEnabled.GetBool() {
+		RegisterWebPushTransport()
 	}
 }
 
@@ -145,6 +185,11 @@ func notifyMentionedUsers(sess *xorm.Session, task *Task, text string, n notific
 			continue
 		}
 
+		err = subscribeUserToTask(sess, u, task.ID)
+		if err != nil {
+			return users, err
+		}
+
 		// Don't notify a user if they were already notified
 		dbn, err := notifications.GetNotificationsForNameAndUser(sess, u.ID, n.Name(), n.SubjectID())
 		if err != nil {
@@ -198,6 +243,37 @@ func (s *SendTaskCommentNotification) Handle(msg *message.Message) (err error) {
 		return err
 	}
 
+	// Replies only go to the people who take part in the thread, not to everyone subscribed to the task.
+	if event.Comment.ParentCommentID != 0 {
+		participants, err := getCommentThreadParticipants(sess, event.Comment.ParentCommentID)
+		if err != nil {
+			return err
+		}
+
+		log.Debugf("Sending task comment reply notifications to %d thread participants for task %d", len(participants), event.Task.ID)
+
+		for _, participant := range participants {
+			if participant.ID == event.Doer.ID {
+				continue
+			}
+			if _, has := mentionedUsers[participant.ID]; has {
+				continue
+			}
+
+			err = notifications.Notify(participant, &TaskCommentNotification{
+				Doer:    event.Doer,
+				Task:    event.Task,
+				Comment: event.Comment,
+				Reply:   true,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
 	subscribers, err := getSubscribersForEntity(sess, SubscriptionEntityTask, event.Task.ID)
 	if err != nil {
 		return err
@@ -319,6 +395,284 @@ func (s *SendTaskAssignedNotification) Handle(msg *message.Message) (err error)
 	return nil
 }
 
+// CheckSavedFilterSubscriptions represents a listener
+type CheckSavedFilterSubscriptions struct {
+}
+
+// Name defines the name for the CheckSavedFilterSubscriptions listener
+func (s *CheckSavedFilterSubscriptions) Name() string {
+	return "task.saved_filter.subscriptions.check"
+}
+
+// Handle is executed when the event CheckSavedFilterSubscriptions listens on is fired
+func (s *CheckSavedFilterSubscriptions) Handle(msg *message.Message) (err error) {
+	// All task events this listener is registered for contain the task and the doer
+	event := &struct {
+		Task *Task      `json:"task"`
+		Doer *user.User `json:"doer"`
+	}{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	if event.Task == nil {
+		return nil
+	}
+
+	sess := db.NewSession()
+	defer sess.Close()
+
+	err = checkSavedFilterSubscriptionsForTask(sess, event.Task.ID, event.Doer)
+	if err != nil {
+		_ = sess.Rollback()
+		return err
+	}
+
+	return sess.Commit()
+}
+
+// AddTaskAssignmentToInbox  represents a listener
+type AddTaskAssignmentToInbox struct {
+}
+
+// Name defines the name for the AddTaskAssignmentToInbox listener
+func (s *AddTaskAssignmentToInbox) Name() string {
+	return "task.assigned.inbox"
+}
+
+// Handle is executed when the event AddTaskAssignmentToInbox listens on is fired
+func (s *AddTaskAssignmentToInbox) Handle(msg *message.Message) (err error) {
+	event := &TaskAssigneeCreatedEvent{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	if event.Task == nil || event.Assignee == nil {
+		return nil
+	}
+
+	var doerID int64
+	if event.Doer != nil {
+		doerID = event.Doer.ID
+	}
+
+	sess := db.NewSession()
+	defer sess.Close()
+
+	err = addToInbox(sess, &InboxItem{
+		UserID:    event.Assignee.ID,
+		Kind:      InboxItemKindAssigned,
+		TaskID:    event.Task.ID,
+		ProjectID: event.Task.ProjectID,
+		DoerID:    doerID,
+	})
+	if err != nil {
+		_ = sess.Rollback()
+		return err
+	}
+
+	return sess.Commit()
+}
+
+// AddTaskMentionsToInbox  represents a listener
+type AddTaskMentionsToInbox struct {
+}
+
+// Name defines the name for the AddTaskMentionsToInbox listener
+func (s *AddTaskMentionsToInbox) Name() string {
+	return "task.mentions.inbox"
+}
+
+// Handle is executed when the event AddTaskMentionsToInbox listens on is fired
+func (s *AddTaskMentionsToInbox) Handle(msg *message.Message) (err error) {
+	// Created and updated events both contain the task and the doer
+	event := &struct {
+		Task *Task      `json:"task"`
+		Doer *user.User `json:"doer"`
+	}{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	if event.Task == nil {
+		return nil
+	}
+
+	var doerID int64
+	if event.Doer != nil {
+		doerID = event.Doer.ID
+	}
+
+	sess := db.NewSession()
+	defer sess.Close()
+
+	err = addMentionsToInbox(sess, event.Task, 0, event.Task.Description, doerID)
+	if err != nil {
+		_ = sess.Rollback()
+		return err
+	}
+
+	return sess.Commit()
+}
+
+// AddCommentMentionsToInbox  represents a listener
+type AddCommentMentionsToInbox struct {
+}
+
+// Name defines the name for the AddCommentMentionsToInbox listener
+func (s *AddCommentMentionsToInbox) Name() string {
+	return "task.comment.mentions.inbox"
+}
+
+// Handle is executed when the event AddCommentMentionsToInbox listens on is fired
+func (s *AddCommentMentionsToInbox) Handle(msg *message.Message) (err error) {
+	event := &TaskCommentCreatedEvent{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	if event.Task == nil || event.Comment == nil {
+		return nil
+	}
+
+	var doerID int64
+	if event.Doer != nil {
+		doerID = event.Doer.ID
+	}
+
+	sess := db.NewSession()
+	defer sess.Close()
+
+	err = addMentionsToInbox(sess, event.Task, event.Comment.ID, event.Comment.Comment, doerID)
+	if err != nil {
+		_ = sess.Rollback()
+		return err
+	}
+
+	return sess.Commit()
+}
+
+// SendTaskApprovalRequestedNotification  represents a listener
+type SendTaskApprovalRequestedNotification struct {
+}
+
+// Name defines the name for the SendTaskApprovalRequestedNotification listener
+func (s *SendTaskApprovalRequestedNotification) Name() string {
+	return "task.approval.requested.notification.send"
+}
+
+// Handle is executed when the event SendTaskApprovalRequestedNotification listens on is fired
+func (s *SendTaskApprovalRequestedNotification) Handle(msg *message.Message) (err error) {
+	event := &TaskApprovalRequestedEvent{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	sess := db.NewSession()
+	defer sess.Close()
+
+	approvers, err := getProjectApprovers(sess, event.Task.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Sending task approval requested notifications to %d approvers for task %d", len(approvers), event.Task.ID)
+
+	for _, approver := range approvers {
+		n := &TaskApprovalRequestedNotification{
+			Doer:     event.Doer,
+			Task:     event.Task,
+			Approver: approver,
+		}
+		err = notifications.Notify(approver, n)
+		if err != nil {
+			return
+		}
+	}
+
+	return nil
+}
+
+// SendTaskApprovedNotification  represents a listener
+type SendTaskApprovedNotification struct {
+}
+
+// Name defines the name for the SendTaskApprovedNotification listener
+func (s *SendTaskApprovedNotification) Name() string {
+	return "task.approval.approved.notification.send"
+}
+
+// Handle is executed when the event SendTaskApprovedNotification listens on is fired
+func (s *SendTaskApprovedNotification) Handle(msg *message.Message) (err error) {
+	event := &TaskApprovedEvent{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	return notifySubscribersAboutApprovalDecision(event.Task, event.Doer, true, event.Reason)
+}
+
+// SendTaskRejectedNotification  represents a listener
+type SendTaskRejectedNotification struct {
+}
+
+// Name defines the name for the SendTaskRejectedNotification listener
+func (s *SendTaskRejectedNotification) Name() string {
+	return "task.approval.rejected.notification.send"
+}
+
+// Handle is executed when the event SendTaskRejectedNotification listens on is fired
+func (s *SendTaskRejectedNotification) Handle(msg *message.Message) (err error) {
+	event := &TaskRejectedEvent{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	return notifySubscribersAboutApprovalDecision(event.Task, event.Doer, false, event.Reason)
+}
+
+func notifySubscribersAboutApprovalDecision(task *Task, doer *user.User, approved bool, reason string) (err error) {
+	sess := db.NewSession()
+	defer sess.Close()
+
+	subscribers, err := getSubscribersForEntity(sess, SubscriptionEntityTask, task.ID)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Sending task approval decision notifications to %d subscribers for task %d", len(subscribers), task.ID)
+
+	notifiedUsers := make(map[int64]bool)
+
+	for _, subscriber := range subscribers {
+		if subscriber.UserID == doer.ID || notifiedUsers[subscriber.UserID] {
+			continue
+		}
+
+		n := &TaskApprovalDecisionNotification{
+			Doer:     doer,
+			Task:     task,
+			Approved: approved,
+			Reason:   reason,
+		}
+		err = notifications.Notify(subscriber.User, n)
+		if err != nil {
+			return
+		}
+
+		notifiedUsers[subscriber.UserID] = true
+	}
+
+	return nil
+}
+
 // SendTaskDeletedNotification  represents a listener
 type SendTaskDeletedNotification struct {
 }
@@ -607,6 +961,56 @@ func (l *UpdateTaskInTypesense) Handle(msg *message.Message) (err error) {
 	return reindexTasksInTypesense(s, task)
 }
 
+// RemoveTaskFromSearchIndex  represents a listener
+type RemoveTaskFromSearchIndex struct {
+}
+
+// Name defines the name for the RemoveTaskFromSearchIndex listener
+func (l *RemoveTaskFromSearchIndex) Name() string {
+	return "search.task.remove"
+}
+
+// Handle is executed when the event RemoveTaskFromSearchIndex listens on is fired
+func (l *RemoveTaskFromSearchIndex) Handle(msg *message.Message) (err error) {
+	event := &TaskDeletedEvent{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	return GetSearchBackend().RemoveTask(event.Task.ID)
+}
+
+// UpdateTaskInSearchIndex  represents a listener
+type UpdateTaskInSearchIndex struct {
+}
+
+// Name defines the name for the UpdateTaskInSearchIndex listener
+func (l *UpdateTaskInSearchIndex) Name() string {
+	return "search.task.update"
+}
+
+// Handle is executed when the event UpdateTaskInSearchIndex listens on is fired
+func (l *UpdateTaskInSearchIndex) Handle(msg *message.Message) (err error) {
+	// Created and updated events both contain the task
+	event := &struct {
+		Task *Task `json:"task"`
+	}{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	if event.Task == nil {
+		return nil
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	return GetSearchBackend().IndexTasks(s, map[int64]*Task{event.Task.ID: event.Task})
+}
+
 // IncreaseAttachmentCounter  represents a listener
 type IncreaseAttachmentCounter struct {
 }
@@ -705,6 +1109,30 @@ func (s *SendProjectCreatedNotification) Handle(msg *message.Message) (err error
 	return nil
 }
 
+// SendProjectTransferRequestedNotification  represents a listener
+type SendProjectTransferRequestedNotification struct {
+}
+
+// Name defines the name for the SendProjectTransferRequestedNotification listener
+func (s *SendProjectTransferRequestedNotification) Name() string {
+	return "send.project.transfer.requested.notification"
+}
+
+// Handle is executed when the event SendProjectTransferRequestedNotification listens on is fired
+func (s *SendProjectTransferRequestedNotification) Handle(msg *message.Message) (err error) {
+	event := &ProjectTransferRequestedEvent{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	return notifications.Notify(event.To, &ProjectTransferRequestedNotification{
+		From:    event.From,
+		To:      event.To,
+		Project: event.Project,
+	})
+}
+
 // WebhookListener represents a listener
 type WebhookListener struct {
 	EventName string
@@ -775,6 +1203,11 @@ func (wl *WebhookListener) Handle(msg *message.Message) (err error) {
 	projectIDs = append(projectIDs, projectID)
 
 	for _, p := range parents {
+		// Webhooks are paused while the project or one of its parents is archived
+		if p.IsArchived {
+			log.Debugf("Project %d is archived, not sending webhooks for the %s event", p.ID, wl.EventName)
+			return nil
+		}
 		projectIDs = append(projectIDs, p.ID)
 	}
 
@@ -843,19 +1276,70 @@ func (wl *WebhookListener) Handle(msg *message.Message) (err error) {
 	}
 
 	for _, webhook := range matchingWebhooks {
-		err = webhook.sendWebhookPayload(&WebhookPayload{
+		// Failed deliveries are retried per webhook, returning an error here would send the event to all
+		// other webhooks again.
+		webhook.deliver(&WebhookPayload{
 			EventName: wl.EventName,
 			Time:      time.Now(),
 			Data:      event,
 		})
-		if err != nil {
-			return err
-		}
 	}
 
 	return
 }
 
+// AddProjectShareToInbox  represents a listener
+type AddProjectShareToInbox struct {
+}
+
+// Name defines the name for the AddProjectShareToInbox listener
+func (s *AddProjectShareToInbox) Name() string {
+	return "project.shared.inbox"
+}
+
+// Handle is executed when the event AddProjectShareToInbox listens on is fired
+func (s *AddProjectShareToInbox) Handle(msg *message.Message) (err error) {
+	// The doer is a web.Auth which can't be unmarshalled directly, that's why it is a map here
+	event := &struct {
+		Project *Project               `json:"project"`
+		User    *user.User             `json:"user"`
+		Team    *Team                  `json:"team"`
+		Doer    map[string]interface{} `json:"doer"`
+	}{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	if event.Project == nil || (event.User == nil && event.Team == nil) {
+		return nil
+	}
+
+	var doerID int64
+	if id, has := event.Doer["id"]; has {
+		doerID = getIDAsInt64(id)
+	}
+
+	var userID, teamID int64
+	if event.User != nil {
+		userID = event.User.ID
+	}
+	if event.Team != nil {
+		teamID = event.Team.ID
+	}
+
+	sess := db.NewSession()
+	defer sess.Close()
+
+	err = addProjectShareToInbox(sess, event.Project.ID, userID, teamID, doerID)
+	if err != nil {
+		_ = sess.Rollback()
+		return err
+	}
+
+	return sess.Commit()
+}
+
 ///////
 // Team Events
 
@@ -916,6 +1400,28 @@ func (s *SendTeamMemberAddedNotification) Handle(msg *message.Message) (err erro
 	})
 }
 
+// HandleProjectDuplication  represents a listener
+type HandleProjectDuplication struct {
+}
+
+// Name defines the name for the HandleProjectDuplication listener
+func (s *HandleProjectDuplication) Name() string {
+	return "handle.project.duplication"
+}
+
+// Handle is executed when the event HandleProjectDuplication listens on is fired
+func (s *HandleProjectDuplication) Handle(msg *message.Message) (err error) {
+	event := &ProjectDuplicationRequestedEvent{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Starting project duplication job %s for project %d...", event.Job.ID, event.Job.ProjectID)
+
+	return runProjectDuplicateJob(event.Job, event.Duplicate, event.Doer)
+}
+
 // HandleUserDataExport  represents a listener
 type HandleUserDataExport struct {
 }
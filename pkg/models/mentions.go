@@ -25,16 +25,21 @@ import (
 	"xorm.io/xorm"
 )
 
+// A mention has to start at the beginning of the text or after a character which can't be part of an email address,
+// to not treat "someone@example.com" as a mention of the user "example".
+var mentionRegex = regexp.MustCompile(`(?:^|[^\w.@+-])@([\w][\w.-]*)`)
+
+// FindMentionedUsersInText returns all existing users mentioned with @username in a text.
 func FindMentionedUsersInText(s *xorm.Session, text string) (users map[int64]*user.User, err error) {
-	reg := regexp.MustCompile(`@\w+`)
-	matches := reg.FindAllString(text, -1)
+	matches := mentionRegex.FindAllStringSubmatch(text, -1)
 	if matches == nil {
 		return
 	}
 
 	usernames := []string{}
 	for _, match := range matches {
-		usernames = append(usernames, strings.TrimPrefix(match, "@"))
+		// Dots and dashes are allowed in usernames, but at the end they most likely end a sentence.
+		usernames = append(usernames, strings.TrimRight(match[1], ".-"))
 	}
 
 	return user.GetUsersByUsername(s, usernames, true)
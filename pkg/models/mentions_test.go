@@ -25,6 +25,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"xorm.io/builder"
 )
 
 func TestFindMentionedUsersInText(t *testing.T) {
@@ -70,6 +71,20 @@ func TestFindMentionedUsersInText(t *testing.T) {
 			text:      "Lorem @user1 Ipsum @user2",
 			wantUsers: []*user.User{user1, user2},
 		},
+		{
+			name:      "user at the end of a sentence",
+			text:      "Please have a look, @user1.",
+			wantUsers: []*user.User{user1},
+		},
+		{
+			name:      "user in html",
+			text:      "<p>@user1</p>",
+			wantUsers: []*user.User{user1},
+		},
+		{
+			name: "email address",
+			text: "Send it to someone@user1",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -82,6 +97,9 @@ func TestFindMentionedUsersInText(t *testing.T) {
 				t.Errorf("FindMentionedUsersInText() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if len(gotUsers) != len(tt.wantUsers) {
+				t.Errorf("wanted %d users but got %d", len(tt.wantUsers), len(gotUsers))
+			}
 			for _, u := range tt.wantUsers {
 				_, has := gotUsers[u.ID]
 				if !has {
@@ -178,4 +196,42 @@ func TestSendingMentionNotification(t *testing.T) {
 		require.NoError(t, err)
 		assert.Len(t, dbNotifications, 1)
 	})
+	t.Run("should subscribe mentioned users to the task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task, err := GetTaskByIDSimple(s, 32)
+		require.NoError(t, err)
+		tc := &TaskComment{
+			Comment: "Lorem Ipsum @user2 @user4",
+			TaskID:  32,
+		}
+		err = tc.Create(s, u)
+		require.NoError(t, err)
+		n := &TaskCommentNotification{
+			Doer:    u,
+			Task:    &task,
+			Comment: tc,
+		}
+
+		_, err = notifyMentionedUsers(s, &task, tc.Comment, n)
+		require.NoError(t, err)
+		// Mentioning them again must not create a second subscription
+		_, err = notifyMentionedUsers(s, &task, tc.Comment, n)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		db.AssertCount(t, "subscriptions", builder.Eq{
+			"entity_type": SubscriptionEntityTask,
+			"entity_id":   32,
+			"user_id":     2,
+		}, 1)
+		// User 4 does not have access to the task
+		db.AssertMissing(t, "subscriptions", map[string]interface{}{
+			"entity_type": SubscriptionEntityTask,
+			"entity_id":   32,
+			"user_id":     4,
+		})
+	})
 }
@@ -48,23 +48,51 @@ func GetTables() []interface{} {
 		&TaskAssginee{},
 		&Label{},
 		&LabelTask{},
+		&LabelGroup{},
 		&TaskReminder{},
 		&LinkSharing{},
 		&TaskRelation{},
 		&TaskAttachment{},
+		&TaskAttachmentUpload{},
+		&TaskAttachmentVersion{},
 		&TaskComment{},
 		&Bucket{},
 		&UnsplashPhoto{},
 		&SavedFilter{},
+		&SavedFilterShare{},
+		&SavedFilterSubscriptionMatch{},
+		&ProjectViewPreferences{},
+		&InboxItem{},
 		&Subscription{},
 		&Favorite{},
 		&APIToken{},
 		&TypesenseSync{},
 		&Webhook{},
+		&ChatIntegration{},
+		&TelegramChat{},
+		&PushSubscription{},
 		&Reaction{},
 		&ProjectView{},
 		&TaskPosition{},
+		&TaskSwimlanePosition{},
+		&BucketTemplate{},
 		&TaskBucket{},
+		&TaskForm{},
+		&TaskDescriptionRevision{},
+		&TaskCommentRevision{},
+		&TaskSLARule{},
+		&TaskSLABreach{},
+		&TaskVote{},
+		&ProjectApprover{},
+		&MyDayTask{},
+		&TaskLastSeen{},
+		&ProjectActivity{},
+		&WebhookDelivery{},
+		&Role{},
+		&ProjectEmailIntake{},
+		&ProjectTransfer{},
+		&AuditLogEntry{},
+		&UsedTaskActionToken{},
 	}
 }
 
@@ -0,0 +1,253 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// MyDay is the list of tasks a user picked to work on today
+type MyDay struct {
+	// The ids of all tasks to work on today. Tasks picked before which are not in this list will be removed from the day, including carried over ones.
+	TaskIDs []int64 `json:"task_ids"`
+	// The tasks of the day, including tasks picked on earlier days which are not done yet.
+	Tasks []*MyDayTask `json:"tasks"`
+
+	web.CRUDable `json:"-"`
+	web.Rights   `json:"-"`
+}
+
+// MyDayTask is a task a user picked for a day
+type MyDayTask struct {
+	// The unique numeric id of this entry
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"-"`
+	// The user who picked the task
+	UserID int64 `xorm:"bigint not null unique(user_task)" json:"-"`
+	// The id of the picked task
+	TaskID int64 `xorm:"bigint not null unique(user_task)" json:"task_id"`
+	// The day the task was picked for, formatted as YYYY-MM-DD in the user's time zone
+	Day string `xorm:"varchar(10) not null INDEX" json:"day"`
+	// True if the task was picked for an earlier day and is not done yet.
+	CarriedOver bool `xorm:"-" json:"carried_over"`
+	// The task itself
+	Task *Task `xorm:"-" json:"task"`
+
+	// A timestamp when the task was picked. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for my day tasks
+func (*MyDayTask) TableName() string {
+	return "my_day_tasks"
+}
+
+// getUserToday returns today's date in the user's time zone, which is what their day is stored with.
+func getUserToday(u *user.User, now time.Time) (string, error) {
+	tz := config.GetTimeZone()
+	if u.Timezone != "" {
+		var err error
+		tz, err = time.LoadLocation(u.Timezone)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return now.In(tz).Format(time.DateOnly), nil
+}
+
+func getMyDayTasks(s *xorm.Session, a web.Auth, u *user.User, today string) (dayTasks []*MyDayTask, err error) {
+	dayTasks = []*MyDayTask{}
+	err = s.
+		Select("my_day_tasks.*").
+		Join("INNER", "tasks", "tasks.id = my_day_tasks.task_id").
+		Where(builder.And(
+			builder.Eq{"my_day_tasks.user_id": u.ID},
+			builder.Or(
+				builder.Eq{"my_day_tasks.day": today},
+				builder.And(
+					builder.Lt{"my_day_tasks.day": today},
+					builder.Eq{"tasks.done": false},
+				),
+			),
+		)).
+		OrderBy("my_day_tasks.day asc, my_day_tasks.id asc").
+		Find(&dayTasks)
+	if err != nil || len(dayTasks) == 0 {
+		return
+	}
+
+	taskIDs := make([]int64, 0, len(dayTasks))
+	for _, dt := range dayTasks {
+		taskIDs = append(taskIDs, dt.TaskID)
+	}
+	taskMap := make(map[int64]*Task, len(dayTasks))
+	err = s.In("id", taskIDs).Find(&taskMap)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addMoreInfoToTasks(s, taskMap, a, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The user might have lost access to a task since they picked it
+	visible := make([]*MyDayTask, 0, len(dayTasks))
+	for _, dt := range dayTasks {
+		task, has := taskMap[dt.TaskID]
+		if !has {
+			continue
+		}
+		canRead, _, err := task.CanRead(s, a)
+		if err != nil {
+			return nil, err
+		}
+		if !canRead {
+			continue
+		}
+		dt.Task = task
+		dt.CarriedOver = dt.Day < today
+		visible = append(visible, dt)
+	}
+
+	return visible, nil
+}
+
+// ReadAll returns the tasks of the current user's day
+// @Summary Get the tasks planned for today
+// @Description Returns all tasks the current user picked for today. Tasks picked on earlier days which are not done yet are carried over and returned as well.
+// @tags user
+// @Produce json
+// @Security JWTKeyAuth
+// @Success 200 {array} models.MyDayTask "The tasks of the day"
+// @Failure 403 {object} web.HTTPError "Link shares don't have a day."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /user/myday [get]
+func (md *MyDay) ReadAll(s *xorm.Session, a web.Auth, _ string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	can, _, err := md.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	u, err := user.GetUserByID(s, a.GetID())
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	today, err := getUserToday(u, time.Now())
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	dayTasks, err := getMyDayTasks(s, a, u, today)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return dayTasks, len(dayTasks), int64(len(dayTasks)), nil
+}
+
+// Create sets the tasks of the current user's day
+// @Summary Pick the tasks for today
+// @Description Sets the tasks the current user wants to work on today. The tasks can be in any project the user has access to. Tasks picked before which are not passed are removed from today, including tasks carried over from an earlier day. Passing a task which was carried over from an earlier day moves it to today.
+// @tags user
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param myday body models.MyDay true "The ids of the tasks for today."
+// @Success 201 {object} models.MyDay "The tasks of the day"
+// @Failure 403 {object} web.HTTPError "The user does not have access to one of the tasks."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /user/myday [put]
+func (md *MyDay) Create(s *xorm.Session, a web.Auth) (err error) {
+	u, err := user.GetUserByID(s, a.GetID())
+	if err != nil {
+		return err
+	}
+
+	today, err := getUserToday(u, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, taskID := range md.TaskIDs {
+		task := &Task{ID: taskID}
+		canRead, _, err := task.CanRead(s, a)
+		if err != nil {
+			return err
+		}
+		if !canRead {
+			return ErrGenericForbidden{}
+		}
+	}
+
+	// Carried over tasks are removed as well, otherwise they would stay on the user's day until they are done
+	cond := builder.Eq{"user_id": u.ID}
+	if len(md.TaskIDs) > 0 {
+		_, err = s.Where(cond).NotIn("task_id", md.TaskIDs).Delete(&MyDayTask{})
+	} else {
+		_, err = s.Where(cond).Delete(&MyDayTask{})
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, taskID := range md.TaskIDs {
+		existing := &MyDayTask{}
+		has, err := s.Where("user_id = ? AND task_id = ?", u.ID, taskID).Get(existing)
+		if err != nil {
+			return err
+		}
+
+		if has {
+			if existing.Day == today {
+				continue
+			}
+			existing.Day = today
+			_, err = s.ID(existing.ID).Cols("day").Update(existing)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		_, err = s.Insert(&MyDayTask{
+			UserID: u.ID,
+			TaskID: taskID,
+			Day:    today,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	md.Tasks, err = getMyDayTasks(s, a, u, today)
+	return err
+}
@@ -0,0 +1,36 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if the current user has a day to plan. Link shares don't.
+func (md *MyDay) CanRead(_ *xorm.Session, a web.Auth) (bool, int, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, 0, nil
+	}
+	return true, int(RightRead), nil
+}
+
+// CanCreate checks if the current user can pick tasks for their day. Access to the tasks is checked when picking them.
+func (md *MyDay) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	can, _, err := md.CanRead(s, a)
+	return can, err
+}
@@ -0,0 +1,123 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/builder"
+)
+
+func TestMyDay(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("pick tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		md := &MyDay{TaskIDs: []int64{1, 3}}
+		err := md.Create(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		require.Len(t, md.Tasks, 2)
+		assert.Equal(t, int64(1), md.Tasks[0].Task.ID)
+		assert.False(t, md.Tasks[0].CarriedOver)
+		assert.Equal(t, int64(3), md.Tasks[1].Task.ID)
+		db.AssertCount(t, "my_day_tasks", builder.Eq{"user_id": 1}, 2)
+
+		// Picking again replaces the selection
+		md = &MyDay{TaskIDs: []int64{3}}
+		err = md.Create(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		require.Len(t, md.Tasks, 1)
+		db.AssertMissing(t, "my_day_tasks", map[string]interface{}{
+			"user_id": 1,
+			"task_id": 1,
+		})
+	})
+	t.Run("carry over", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Insert(&[]*MyDayTask{
+			{UserID: 1, TaskID: 4, Day: "2020-01-01"},
+			// Task 2 is done and should not be carried over
+			{UserID: 1, TaskID: 2, Day: "2020-01-01"},
+		})
+		require.NoError(t, err)
+
+		md := &MyDay{}
+		result, _, _, err := md.ReadAll(s, u, "", 0, 0)
+		require.NoError(t, err)
+		dayTasks := result.([]*MyDayTask)
+		require.Len(t, dayTasks, 1)
+		assert.Equal(t, int64(4), dayTasks[0].TaskID)
+		assert.True(t, dayTasks[0].CarriedOver)
+
+		// Carried over tasks can be removed from the day
+		md = &MyDay{TaskIDs: []int64{1}}
+		err = md.Create(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		require.Len(t, md.Tasks, 1)
+		assert.Equal(t, int64(1), md.Tasks[0].TaskID)
+		db.AssertMissing(t, "my_day_tasks", map[string]interface{}{
+			"user_id": 1,
+			"task_id": 4,
+		})
+	})
+	t.Run("no access to task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&MyDay{TaskIDs: []int64{1}}).Create(s, &user.User{ID: 2})
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		can, _, err := (&MyDay{}).CanRead(s, &LinkSharing{ID: 1})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestGetUserToday(t *testing.T) {
+	now := time.Date(2026, 3, 2, 23, 30, 0, 0, time.UTC)
+
+	today, err := getUserToday(&user.User{Timezone: "Europe/Berlin"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-03", today)
+
+	today, err = getUserToday(&user.User{Timezone: "America/New_York"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-02", today)
+}
@@ -19,11 +19,13 @@ package models
 import (
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
-	"code.vikunja.io/api/pkg/utils"
-
 	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/i18n"
+	"code.vikunja.io/api/pkg/log"
 	"code.vikunja.io/api/pkg/notifications"
 	"code.vikunja.io/api/pkg/user"
 )
@@ -36,14 +38,14 @@ type ReminderDueNotification struct {
 }
 
 // ToMail returns the mail notification for ReminderDueNotification
-func (n *ReminderDueNotification) ToMail() *notifications.Mail {
+func (n *ReminderDueNotification) ToMail(lang string) *notifications.Mail {
 	return notifications.NewMail().
 		To(n.User.Email).
-		Subject(`Reminder for "`+n.Task.Title+`" (`+n.Project.Title+`)`).
-		Greeting("Hi "+n.User.GetName()+",").
-		Line(`This is a friendly reminder of the task "`+n.Task.Title+`" (`+n.Project.Title+`).`).
-		Action("Open Task", config.ServicePublicURL.GetString()+"tasks/"+strconv.FormatInt(n.Task.ID, 10)).
-		Line("Have a nice day!")
+		Subject(i18n.T(lang, "notifications.task.reminder.subject", n.Task.Title, n.Project.Title)).
+		Greeting(i18n.T(lang, "notifications.common.greeting", n.User.GetName())).
+		Line(i18n.T(lang, "notifications.task.reminder.message", n.Task.Title, n.Project.Title)).
+		Action(i18n.T(lang, "notifications.common.actions.open_task"), config.ServicePublicURL.GetString()+"tasks/"+strconv.FormatInt(n.Task.ID, 10)).
+		Line(i18n.T(lang, "notifications.common.have_nice_day"))
 }
 
 // ToDB returns the ReminderDueNotification notification in a format which can be saved in the db
@@ -59,12 +61,19 @@ func (n *ReminderDueNotification) Name() string {
 	return "task.reminder"
 }
 
+// ProjectID returns the project the notification is about
+func (n *ReminderDueNotification) ProjectID() int64 {
+	return n.Task.ProjectID
+}
+
 // TaskCommentNotification represents a TaskCommentNotification notification
 type TaskCommentNotification struct {
 	Doer      *user.User   `json:"doer"`
 	Task      *Task        `json:"task"`
 	Comment   *TaskComment `json:"comment"`
 	Mentioned bool         `json:"mentioned"`
+	// Set when the comment is a reply in a thread the notified user takes part in.
+	Reply bool `json:"reply"`
 }
 
 func (n *TaskCommentNotification) SubjectID() int64 {
@@ -72,22 +81,31 @@ func (n *TaskCommentNotification) SubjectID() int64 {
 }
 
 // ToMail returns the mail notification for TaskCommentNotification
-func (n *TaskCommentNotification) ToMail() *notifications.Mail {
+func (n *TaskCommentNotification) ToMail(lang string) *notifications.Mail {
 
 	mail := notifications.NewMail().
 		From(n.Doer.GetNameAndFromEmail()).
-		Subject("Re: " + n.Task.Title)
+		Subject(i18n.T(lang, "notifications.task.comment.subject", n.Task.Title))
 
 	if n.Mentioned {
 		mail.
-			Line("**" + n.Doer.GetName() + "** mentioned you in a comment:").
-			Subject(n.Doer.GetName() + ` mentioned you in a comment in "` + n.Task.Title + `"`)
+			Line(i18n.T(lang, "notifications.task.comment.mentioned_message", n.Doer.GetName())).
+			Subject(i18n.T(lang, "notifications.task.comment.mentioned_subject", n.Doer.GetName(), n.Task.Title))
+	}
+
+	if n.Reply && !n.Mentioned {
+		mail.Line(i18n.T(lang, "notifications.task.comment.reply_message", n.Doer.GetName()))
 	}
 
 	mail.HTML(n.Comment.Comment)
 
 	return mail.
-		Action("View Task", n.Task.GetFrontendURL())
+		Action(i18n.T(lang, "notifications.common.actions.view_task"), n.Task.GetFrontendURL())
+}
+
+// ReplyTo returns the address the notified user can answer to in order to reply to the comment
+func (n *TaskCommentNotification) ReplyTo(notifiableID int64) string {
+	return getTaskCommentReplyAddress(n.Task.ID, n.Comment.ID, notifiableID)
 }
 
 // ToDB returns the TaskCommentNotification notification in a format which can be saved in the db
@@ -100,6 +118,11 @@ func (n *TaskCommentNotification) Name() string {
 	return "task.comment"
 }
 
+// ProjectID returns the project the notification is about
+func (n *TaskCommentNotification) ProjectID() int64 {
+	return n.Task.ProjectID
+}
+
 // TaskAssignedNotification represents a TaskAssignedNotification notification
 type TaskAssignedNotification struct {
 	Doer     *user.User `json:"doer"`
@@ -109,18 +132,18 @@ type TaskAssignedNotification struct {
 }
 
 // ToMail returns the mail notification for TaskAssignedNotification
-func (n *TaskAssignedNotification) ToMail() *notifications.Mail {
+func (n *TaskAssignedNotification) ToMail(lang string) *notifications.Mail {
 	if n.Target.ID == n.Assignee.ID {
 		return notifications.NewMail().
-			Subject("You have been assigned to "+n.Task.Title+"("+n.Task.GetFullIdentifier()+")").
-			Line(n.Doer.GetName()+" has assigned you to "+n.Task.Title+".").
-			Action("View Task", n.Task.GetFrontendURL())
+			Subject(i18n.T(lang, "notifications.task.assigned.subject_to_you", n.Task.Title, n.Task.GetFullIdentifier())).
+			Line(i18n.T(lang, "notifications.task.assigned.message_to_you", n.Doer.GetName(), n.Task.Title)).
+			Action(i18n.T(lang, "notifications.common.actions.view_task"), n.Task.GetFrontendURL())
 	}
 
 	return notifications.NewMail().
-		Subject(n.Task.Title+"("+n.Task.GetFullIdentifier()+")"+" has been assigned to "+n.Assignee.GetName()).
-		Line(n.Doer.GetName()+" has assigned this task to "+n.Assignee.GetName()+".").
-		Action("View Task", n.Task.GetFrontendURL())
+		Subject(i18n.T(lang, "notifications.task.assigned.subject", n.Task.Title, n.Task.GetFullIdentifier(), n.Assignee.GetName())).
+		Line(i18n.T(lang, "notifications.task.assigned.message", n.Doer.GetName(), n.Assignee.GetName())).
+		Action(i18n.T(lang, "notifications.common.actions.view_task"), n.Task.GetFrontendURL())
 }
 
 // ToDB returns the TaskAssignedNotification notification in a format which can be saved in the db
@@ -133,6 +156,109 @@ func (n *TaskAssignedNotification) Name() string {
 	return "task.assigned"
 }
 
+// ProjectID returns the project the notification is about
+func (n *TaskAssignedNotification) ProjectID() int64 {
+	return n.Task.ProjectID
+}
+
+// BatchKey batches all assignments in the same project
+func (n *TaskAssignedNotification) BatchKey() string {
+	return strconv.FormatInt(n.Task.ProjectID, 10)
+}
+
+// DeduplicationKey identifies the assignment
+func (n *TaskAssignedNotification) DeduplicationKey() string {
+	return strconv.FormatInt(n.Task.ID, 10) + "-" + strconv.FormatInt(n.Assignee.ID, 10)
+}
+
+// Combine returns one notification for all assignments in the batch
+func (n *TaskAssignedNotification) Combine(batch []notifications.BatchableNotification) notifications.Notification {
+	doers := make([]*user.User, 0, len(batch))
+	tasks := make([]*Task, 0, len(batch))
+	assignedToTarget := true
+	for _, b := range batch {
+		assigned := b.(*TaskAssignedNotification)
+		doers = append(doers, assigned.Doer)
+		tasks = append(tasks, assigned.Task)
+		assignedToTarget = assignedToTarget && assigned.Assignee.ID == n.Target.ID
+	}
+
+	combined := newTasksBatchedNotification(n.Name(), doers, tasks)
+	combined.Project = getBatchProject(n.Task.ProjectID)
+	combined.AssignedToYou = assignedToTarget
+	return combined
+}
+
+// TaskApprovalRequestedNotification represents a TaskApprovalRequestedNotification notification
+type TaskApprovalRequestedNotification struct {
+	Doer     *user.User `json:"doer"`
+	Task     *Task      `json:"task"`
+	Approver *user.User `json:"-"`
+}
+
+// ToMail returns the mail notification for TaskApprovalRequestedNotification
+func (n *TaskApprovalRequestedNotification) ToMail(lang string) *notifications.Mail {
+	return notifications.NewMail().
+		Subject(i18n.T(lang, "notifications.task.approval.requested_subject", n.Task.Title, n.Task.GetFullIdentifier())).
+		Greeting(i18n.T(lang, "notifications.common.greeting", n.Approver.GetName())).
+		Line(i18n.T(lang, "notifications.task.approval.requested_message", n.Doer.GetName(), n.Task.Title)).
+		Action(i18n.T(lang, "notifications.task.approval.review"), n.Task.GetFrontendURL())
+}
+
+// ToDB returns the TaskApprovalRequestedNotification notification in a format which can be saved in the db
+func (n *TaskApprovalRequestedNotification) ToDB() interface{} {
+	return n
+}
+
+// Name returns the name of the notification
+func (n *TaskApprovalRequestedNotification) Name() string {
+	return "task.approval.requested"
+}
+
+// ProjectID returns the project the notification is about
+func (n *TaskApprovalRequestedNotification) ProjectID() int64 {
+	return n.Task.ProjectID
+}
+
+// TaskApprovalDecisionNotification represents a TaskApprovalDecisionNotification notification
+type TaskApprovalDecisionNotification struct {
+	Doer     *user.User `json:"doer"`
+	Task     *Task      `json:"task"`
+	Approved bool       `json:"approved"`
+	Reason   string     `json:"reason"`
+}
+
+// ToMail returns the mail notification for TaskApprovalDecisionNotification
+func (n *TaskApprovalDecisionNotification) ToMail(lang string) *notifications.Mail {
+	decision := "rejected"
+	if n.Approved {
+		decision = "approved"
+	}
+
+	mail := notifications.NewMail().
+		Subject(i18n.T(lang, "notifications.task.approval."+decision+"_subject", n.Task.Title, n.Task.GetFullIdentifier())).
+		Line(i18n.T(lang, "notifications.task.approval."+decision+"_message", n.Doer.GetName(), n.Task.Title))
+	if n.Reason != "" {
+		mail.Line(i18n.T(lang, "notifications.task.approval.reason", n.Reason))
+	}
+	return mail.Action(i18n.T(lang, "notifications.common.actions.view_task"), n.Task.GetFrontendURL())
+}
+
+// ToDB returns the TaskApprovalDecisionNotification notification in a format which can be saved in the db
+func (n *TaskApprovalDecisionNotification) ToDB() interface{} {
+	return n
+}
+
+// Name returns the name of the notification
+func (n *TaskApprovalDecisionNotification) Name() string {
+	return "task.approval.decided"
+}
+
+// ProjectID returns the project the notification is about
+func (n *TaskApprovalDecisionNotification) ProjectID() int64 {
+	return n.Task.ProjectID
+}
+
 // TaskDeletedNotification represents a TaskDeletedNotification notification
 type TaskDeletedNotification struct {
 	Doer *user.User `json:"doer"`
@@ -140,10 +266,10 @@ type TaskDeletedNotification struct {
 }
 
 // ToMail returns the mail notification for TaskDeletedNotification
-func (n *TaskDeletedNotification) ToMail() *notifications.Mail {
+func (n *TaskDeletedNotification) ToMail(lang string) *notifications.Mail {
 	return notifications.NewMail().
-		Subject(n.Task.Title + " (" + n.Task.GetFullIdentifier() + ")" + " has been deleted").
-		Line(n.Doer.GetName() + " has deleted the task " + n.Task.Title + " (" + n.Task.GetFullIdentifier() + ")")
+		Subject(i18n.T(lang, "notifications.task.deleted.subject", n.Task.Title, n.Task.GetFullIdentifier())).
+		Line(i18n.T(lang, "notifications.task.deleted.message", n.Doer.GetName(), n.Task.Title, n.Task.GetFullIdentifier()))
 }
 
 // ToDB returns the TaskDeletedNotification notification in a format which can be saved in the db
@@ -156,6 +282,92 @@ func (n *TaskDeletedNotification) Name() string {
 	return "task.deleted"
 }
 
+// ProjectID returns the project the notification is about
+func (n *TaskDeletedNotification) ProjectID() int64 {
+	return n.Task.ProjectID
+}
+
+// BatchKey batches all deleted tasks of the same project
+func (n *TaskDeletedNotification) BatchKey() string {
+	return strconv.FormatInt(n.Task.ProjectID, 10)
+}
+
+// DeduplicationKey identifies the deleted task
+func (n *TaskDeletedNotification) DeduplicationKey() string {
+	return strconv.FormatInt(n.Task.ID, 10)
+}
+
+// Combine returns one notification for all deleted tasks in the batch
+func (n *TaskDeletedNotification) Combine(batch []notifications.BatchableNotification) notifications.Notification {
+	doers := make([]*user.User, 0, len(batch))
+	tasks := make([]*Task, 0, len(batch))
+	for _, b := range batch {
+		deleted := b.(*TaskDeletedNotification)
+		doers = append(doers, deleted.Doer)
+		tasks = append(tasks, deleted.Task)
+	}
+
+	combined := newTasksBatchedNotification(n.Name(), doers, tasks)
+	combined.Project = getBatchProject(n.Task.ProjectID)
+	combined.TasksDeleted = true
+	return combined
+}
+
+// TaskMatchesSavedFilterNotification represents a TaskMatchesSavedFilterNotification notification
+type TaskMatchesSavedFilterNotification struct {
+	Doer   *user.User   `json:"doer"`
+	Task   *Task        `json:"task"`
+	Filter *SavedFilter `json:"filter"`
+}
+
+// ToMail returns the mail notification for TaskMatchesSavedFilterNotification
+func (n *TaskMatchesSavedFilterNotification) ToMail(lang string) *notifications.Mail {
+	return notifications.NewMail().
+		Subject(i18n.T(lang, "notifications.task.saved_filter_matched.subject", n.Task.Title, n.Task.GetFullIdentifier(), n.Filter.Title)).
+		Line(i18n.T(lang, "notifications.task.saved_filter_matched.message", n.Task.Title, n.Filter.Title)).
+		Action(i18n.T(lang, "notifications.common.actions.view_task"), n.Task.GetFrontendURL())
+}
+
+// ToDB returns the TaskMatchesSavedFilterNotification notification in a format which can be saved in the db
+func (n *TaskMatchesSavedFilterNotification) ToDB() interface{} {
+	return n
+}
+
+// Name returns the name of the notification
+func (n *TaskMatchesSavedFilterNotification) Name() string {
+	return "task.saved_filter.matched"
+}
+
+// ProjectID returns the project the notification is about
+func (n *TaskMatchesSavedFilterNotification) ProjectID() int64 {
+	return n.Task.ProjectID
+}
+
+// BatchKey batches all tasks matching the same filter
+func (n *TaskMatchesSavedFilterNotification) BatchKey() string {
+	return strconv.FormatInt(n.Filter.ID, 10)
+}
+
+// DeduplicationKey identifies the matching task
+func (n *TaskMatchesSavedFilterNotification) DeduplicationKey() string {
+	return strconv.FormatInt(n.Task.ID, 10)
+}
+
+// Combine returns one notification for all tasks in the batch which now match the filter
+func (n *TaskMatchesSavedFilterNotification) Combine(batch []notifications.BatchableNotification) notifications.Notification {
+	doers := make([]*user.User, 0, len(batch))
+	tasks := make([]*Task, 0, len(batch))
+	for _, b := range batch {
+		matched := b.(*TaskMatchesSavedFilterNotification)
+		doers = append(doers, matched.Doer)
+		tasks = append(tasks, matched.Task)
+	}
+
+	combined := newTasksBatchedNotification(n.Name(), doers, tasks)
+	combined.Filter = n.Filter
+	return combined
+}
+
 // ProjectCreatedNotification represents a ProjectCreatedNotification notification
 type ProjectCreatedNotification struct {
 	Doer    *user.User `json:"doer"`
@@ -163,11 +375,11 @@ type ProjectCreatedNotification struct {
 }
 
 // ToMail returns the mail notification for ProjectCreatedNotification
-func (n *ProjectCreatedNotification) ToMail() *notifications.Mail {
+func (n *ProjectCreatedNotification) ToMail(lang string) *notifications.Mail {
 	return notifications.NewMail().
-		Subject(n.Doer.GetName()+` created the project "`+n.Project.Title+`"`).
-		Line(n.Doer.GetName()+` created the project "`+n.Project.Title+`"`).
-		Action("View Project", config.ServicePublicURL.GetString()+"projects/")
+		Subject(i18n.T(lang, "notifications.project.created.subject", n.Doer.GetName(), n.Project.Title)).
+		Line(i18n.T(lang, "notifications.project.created.message", n.Doer.GetName(), n.Project.Title)).
+		Action(i18n.T(lang, "notifications.project.created.action"), config.ServicePublicURL.GetString()+"projects/")
 }
 
 // ToDB returns the ProjectCreatedNotification notification in a format which can be saved in the db
@@ -180,6 +392,11 @@ func (n *ProjectCreatedNotification) Name() string {
 	return "project.created"
 }
 
+// ProjectID returns the project the notification is about
+func (n *ProjectCreatedNotification) ProjectID() int64 {
+	return n.Project.ID
+}
+
 // TeamMemberAddedNotification represents a TeamMemberAddedNotification notification
 type TeamMemberAddedNotification struct {
 	Member *user.User `json:"member"`
@@ -188,13 +405,13 @@ type TeamMemberAddedNotification struct {
 }
 
 // ToMail returns the mail notification for TeamMemberAddedNotification
-func (n *TeamMemberAddedNotification) ToMail() *notifications.Mail {
+func (n *TeamMemberAddedNotification) ToMail(lang string) *notifications.Mail {
 	return notifications.NewMail().
-		Subject(n.Doer.GetName()+" added you to the "+n.Team.Name+" team in Vikunja").
+		Subject(i18n.T(lang, "notifications.team.member_added.subject", n.Doer.GetName(), n.Team.Name)).
 		From(n.Doer.GetNameAndFromEmail()).
-		Greeting("Hi "+n.Member.GetName()+",").
-		Line(n.Doer.GetName()+" has just added you to the "+n.Team.Name+" team in Vikunja.").
-		Action("View Team", config.ServicePublicURL.GetString()+"teams/"+strconv.FormatInt(n.Team.ID, 10)+"/edit")
+		Greeting(i18n.T(lang, "notifications.common.greeting", n.Member.GetName())).
+		Line(i18n.T(lang, "notifications.team.member_added.message", n.Doer.GetName(), n.Team.Name)).
+		Action(i18n.T(lang, "notifications.team.member_added.action"), config.ServicePublicURL.GetString()+"teams/"+strconv.FormatInt(n.Team.ID, 10)+"/edit")
 }
 
 // ToDB returns the TeamMemberAddedNotification notification in a format which can be saved in the db
@@ -215,14 +432,18 @@ type UndoneTaskOverdueNotification struct {
 }
 
 // ToMail returns the mail notification for UndoneTaskOverdueNotification
-func (n *UndoneTaskOverdueNotification) ToMail() *notifications.Mail {
+func (n *UndoneTaskOverdueNotification) ToMail(lang string) *notifications.Mail {
 	until := time.Until(n.Task.DueDate).Round(1*time.Hour) * -1
-	return notifications.NewMail().
-		Subject(`Task "`+n.Task.Title+`" (`+n.Project.Title+`) is overdue`).
-		Greeting("Hi "+n.User.GetName()+",").
-		Line(`This is a friendly reminder of the task "`+n.Task.Title+`" (`+n.Project.Title+`) which is overdue since `+utils.HumanizeDuration(until)+` and not yet done.`).
-		Action("Open Task", config.ServicePublicURL.GetString()+"tasks/"+strconv.FormatInt(n.Task.ID, 10)).
-		Line("Have a nice day!")
+	mail := notifications.NewMail().
+		Subject(i18n.T(lang, "notifications.task.overdue.subject", n.Task.Title, n.Project.Title)).
+		Greeting(i18n.T(lang, "notifications.common.greeting", n.User.GetName())).
+		Line(i18n.T(lang, "notifications.task.overdue.message", n.Task.Title, n.Project.Title, i18n.HumanizeDuration(lang, until)))
+	if links := taskActionLinks(lang, n.User, n.Task); links != "" {
+		mail.Line(links)
+	}
+	return mail.
+		Action(i18n.T(lang, "notifications.common.actions.open_task"), config.ServicePublicURL.GetString()+"tasks/"+strconv.FormatInt(n.Task.ID, 10)).
+		Line(i18n.T(lang, "notifications.common.have_nice_day"))
 }
 
 // ToDB returns the UndoneTaskOverdueNotification notification in a format which can be saved in the db
@@ -235,6 +456,11 @@ func (n *UndoneTaskOverdueNotification) Name() string {
 	return "task.undone.overdue"
 }
 
+// ProjectID returns the project the notification is about
+func (n *UndoneTaskOverdueNotification) ProjectID() int64 {
+	return n.Task.ProjectID
+}
+
 // UndoneTasksOverdueNotification represents a UndoneTasksOverdueNotification notification
 type UndoneTasksOverdueNotification struct {
 	User     *user.User
@@ -243,30 +469,57 @@ type UndoneTasksOverdueNotification struct {
 }
 
 // ToMail returns the mail notification for UndoneTasksOverdueNotification
-func (n *UndoneTasksOverdueNotification) ToMail() *notifications.Mail {
+func (n *UndoneTasksOverdueNotification) ToMail(lang string) *notifications.Mail {
 
-	sortedTasks := make([]*Task, 0, len(n.Tasks))
+	tasksByProject := make(map[int64][]*Task)
 	for _, task := range n.Tasks {
-		sortedTasks = append(sortedTasks, task)
+		tasksByProject[task.ProjectID] = append(tasksByProject[task.ProjectID], task)
 	}
 
-	sort.Slice(sortedTasks, func(i, j int) bool {
-		return sortedTasks[i].DueDate.Before(sortedTasks[j].DueDate)
+	projectIDs := make([]int64, 0, len(tasksByProject))
+	for projectID, tasks := range tasksByProject {
+		projectIDs = append(projectIDs, projectID)
+		sort.Slice(tasks, func(i, j int) bool {
+			return tasks[i].DueDate.Before(tasks[j].DueDate)
+		})
+	}
+
+	sort.Slice(projectIDs, func(i, j int) bool {
+		return n.Projects[projectIDs[i]].Title < n.Projects[projectIDs[j]].Title
 	})
 
-	overdueLine := ""
-	for _, task := range sortedTasks {
-		until := time.Until(task.DueDate).Round(1*time.Hour) * -1
-		overdueLine += `* [` + task.Title + `](` + config.ServicePublicURL.GetString() + "tasks/" + strconv.FormatInt(task.ID, 10) + `) (` + n.Projects[task.ProjectID].Title + `), overdue since ` + utils.HumanizeDuration(until) + "\n"
+	summary := i18n.T(lang, "notifications.task.overdue.summary", len(n.Tasks))
+	if len(projectIDs) > 1 {
+		summary = i18n.T(lang, "notifications.task.overdue.summary_projects", len(n.Tasks), len(projectIDs))
 	}
 
-	return notifications.NewMail().
-		Subject(`Your overdue tasks`).
-		Greeting("Hi "+n.User.GetName()+",").
-		Line("You have the following overdue tasks:").
-		Line(overdueLine).
-		Action("Open Vikunja", config.ServicePublicURL.GetString()).
-		Line("Have a nice day!")
+	mail := notifications.NewMail().
+		Subject(i18n.T(lang, "notifications.task.overdue.summary_subject")).
+		Greeting(i18n.T(lang, "notifications.common.greeting", n.User.GetName())).
+		Line(summary)
+
+	for _, projectID := range projectIDs {
+		tasks := tasksByProject[projectID]
+		project := n.Projects[projectID]
+
+		overdueLine := ""
+		for _, task := range tasks {
+			until := time.Until(task.DueDate).Round(1*time.Hour) * -1
+			overdueLine += `* [` + task.Title + `](` + config.ServicePublicURL.GetString() + "tasks/" + strconv.FormatInt(task.ID, 10) + `), ` + i18n.T(lang, "notifications.task.overdue.since", i18n.HumanizeDuration(lang, until))
+			if links := taskActionLinks(lang, n.User, task); links != "" {
+				overdueLine += ` – ` + links
+			}
+			overdueLine += "\n"
+		}
+
+		mail.
+			Line(`**[` + project.Title + `](` + config.ServicePublicURL.GetString() + "projects/" + strconv.FormatInt(project.ID, 10) + `)** (` + strconv.Itoa(len(tasks)) + `)`).
+			Line(overdueLine)
+	}
+
+	return mail.
+		Action(i18n.T(lang, "notifications.common.actions.open_vikunja"), config.ServicePublicURL.GetString()).
+		Line(i18n.T(lang, "notifications.common.have_nice_day"))
 }
 
 // ToDB returns the UndoneTasksOverdueNotification notification in a format which can be saved in the db
@@ -291,20 +544,25 @@ func (n *UserMentionedInTaskNotification) SubjectID() int64 {
 }
 
 // ToMail returns the mail notification for UserMentionedInTaskNotification
-func (n *UserMentionedInTaskNotification) ToMail() *notifications.Mail {
-	subject := n.Doer.GetName() + ` mentioned you in a new task "` + n.Task.Title + `"`
+func (n *UserMentionedInTaskNotification) ToMail(lang string) *notifications.Mail {
+	subject := i18n.T(lang, "notifications.task.mentioned.subject", n.Doer.GetName(), n.Task.Title)
 	if n.IsNew {
-		subject = n.Doer.GetName() + ` mentioned you in a task "` + n.Task.Title + `"`
+		subject = i18n.T(lang, "notifications.task.mentioned.subject_new", n.Doer.GetName(), n.Task.Title)
 	}
 
 	mail := notifications.NewMail().
 		From(n.Doer.GetNameAndFromEmail()).
 		Subject(subject).
-		Line("**" + n.Doer.GetName() + "** mentioned you in a task:").
+		Line(i18n.T(lang, "notifications.task.mentioned.message", n.Doer.GetName())).
 		HTML(n.Task.Description)
 
 	return mail.
-		Action("View Task", n.Task.GetFrontendURL())
+		Action(i18n.T(lang, "notifications.common.actions.view_task"), n.Task.GetFrontendURL())
+}
+
+// ReplyTo returns the address the notified user can answer to in order to comment on the task
+func (n *UserMentionedInTaskNotification) ReplyTo(notifiableID int64) string {
+	return getTaskCommentReplyAddress(n.Task.ID, 0, notifiableID)
 }
 
 // ToDB returns the UserMentionedInTaskNotification notification in a format which can be saved in the db
@@ -317,20 +575,25 @@ func (n *UserMentionedInTaskNotification) Name() string {
 	return "task.mentioned"
 }
 
+// ProjectID returns the project the notification is about
+func (n *UserMentionedInTaskNotification) ProjectID() int64 {
+	return n.Task.ProjectID
+}
+
 // DataExportReadyNotification represents a DataExportReadyNotification notification
 type DataExportReadyNotification struct {
 	User *user.User `json:"user"`
 }
 
 // ToMail returns the mail notification for DataExportReadyNotification
-func (n *DataExportReadyNotification) ToMail() *notifications.Mail {
+func (n *DataExportReadyNotification) ToMail(lang string) *notifications.Mail {
 	return notifications.NewMail().
-		Subject("Your Vikunja Data Export is ready").
-		Greeting("Hi "+n.User.GetName()+",").
-		Line("Your Vikunja Data Export is ready for you to download. Click the button below to download it:").
-		Action("Download", config.ServicePublicURL.GetString()+"user/export/download").
-		Line("The download will be available for the next 7 days.").
-		Line("Have a nice day!")
+		Subject(i18n.T(lang, "notifications.data_export.ready.subject")).
+		Greeting(i18n.T(lang, "notifications.common.greeting", n.User.GetName())).
+		Line(i18n.T(lang, "notifications.data_export.ready.message")).
+		Action(i18n.T(lang, "notifications.data_export.ready.action"), config.ServicePublicURL.GetString()+"user/export/download").
+		Line(i18n.T(lang, "notifications.data_export.ready.valid")).
+		Line(i18n.T(lang, "notifications.common.have_nice_day"))
 }
 
 // ToDB returns the DataExportReadyNotification notification in a format which can be saved in the db
@@ -342,3 +605,276 @@ func (n *DataExportReadyNotification) ToDB() interface{} {
 func (n *DataExportReadyNotification) Name() string {
 	return "data.export.ready"
 }
+
+// TaskSLABreachedNotification represents a TaskSLABreachedNotification notification
+type TaskSLABreachedNotification struct {
+	User    *user.User   `json:"-"`
+	Task    *Task        `json:"task"`
+	Project *Project     `json:"project"`
+	Rule    *TaskSLARule `json:"rule"`
+}
+
+// ToMail returns the mail notification for TaskSLABreachedNotification
+func (n *TaskSLABreachedNotification) ToMail(lang string) *notifications.Mail {
+	return notifications.NewMail().
+		Subject(i18n.T(lang, "notifications.task.sla_breached.subject", n.Task.Title, n.Rule.Title)).
+		Greeting(i18n.T(lang, "notifications.common.greeting", n.User.GetName())).
+		Line(i18n.T(lang, "notifications.task.sla_breached.message", n.Task.Title, n.Project.Title, n.Rule.Title)).
+		Action(i18n.T(lang, "notifications.common.actions.open_task"), config.ServicePublicURL.GetString()+"tasks/"+strconv.FormatInt(n.Task.ID, 10))
+}
+
+// ToDB returns the TaskSLABreachedNotification notification in a format which can be saved in the db
+func (n *TaskSLABreachedNotification) ToDB() interface{} {
+	return n
+}
+
+// Name returns the name of the notification
+func (n *TaskSLABreachedNotification) Name() string {
+	return "task.sla.breached"
+}
+
+// ProjectID returns the project the notification is about
+func (n *TaskSLABreachedNotification) ProjectID() int64 {
+	return n.Task.ProjectID
+}
+
+// ProjectTransferRequestedNotification represents a ProjectTransferRequestedNotification notification
+type ProjectTransferRequestedNotification struct {
+	From    *user.User `json:"from"`
+	To      *user.User `json:"-"`
+	Project *Project   `json:"project"`
+}
+
+// ToMail returns the mail notification for ProjectTransferRequestedNotification
+func (n *ProjectTransferRequestedNotification) ToMail(lang string) *notifications.Mail {
+	return notifications.NewMail().
+		Subject(i18n.T(lang, "notifications.project.transfer_requested.subject", n.From.GetName(), n.Project.Title)).
+		Greeting(i18n.T(lang, "notifications.common.greeting", n.To.GetName())).
+		Line(i18n.T(lang, "notifications.project.transfer_requested.message", n.From.GetName(), n.Project.Title)).
+		Line(i18n.T(lang, "notifications.project.transfer_requested.hint")).
+		Action(i18n.T(lang, "notifications.project.transfer_requested.action"), config.ServicePublicURL.GetString()+"projects/"+strconv.FormatInt(n.Project.ID, 10)+"/transfer")
+}
+
+// ToDB returns the ProjectTransferRequestedNotification notification in a format which can be saved in the db
+func (n *ProjectTransferRequestedNotification) ToDB() interface{} {
+	return n
+}
+
+// Name returns the name of the notification
+func (n *ProjectTransferRequestedNotification) Name() string {
+	return "project.transfer.requested"
+}
+
+// ProjectID returns the project the notification is about
+func (n *ProjectTransferRequestedNotification) ProjectID() int64 {
+	return n.Project.ID
+}
+
+// DigestNotification represents a DigestNotification notification
+type DigestNotification struct {
+	User         *user.User
+	Frequency    string
+	DueTasks     []*Task
+	OverdueTasks []*Task
+	Activities   []*ProjectActivity
+	Projects     map[int64]*Project
+	Location     *time.Location
+}
+
+func (n *DigestNotification) taskLine(lang string, task *Task) string {
+	line := `* [` + task.Title + `](` + task.GetFrontendURL() + `)`
+	if project, has := n.Projects[task.ProjectID]; has {
+		line += ` (` + project.Title + `)`
+	}
+	return line + `, ` + i18n.T(lang, "notifications.digest.due", task.DueDate.In(n.Location).Format("Mon, Jan 2 15:04"))
+}
+
+func (n *DigestNotification) activityLine(lang string, activity *ProjectActivity) string {
+	actor := i18n.T(lang, "notifications.digest.someone")
+	if activity.Actor != nil {
+		actor = activity.Actor.GetName()
+	}
+
+	description := activity.Kind
+	key := "notifications.digest.activities." + strings.ReplaceAll(activity.Kind, ".", "_")
+	if i18n.Has(key) {
+		description = i18n.T(lang, key)
+	}
+
+	line := `* ` + actor + ` ` + description
+	if activity.TaskID != 0 {
+		line += ` [` + activity.Title + `](` + (&Task{ID: activity.TaskID}).GetFrontendURL() + `)`
+	} else if activity.Title != "" {
+		line += ` ` + activity.Title
+	}
+	if project, has := n.Projects[activity.ProjectID]; has {
+		line += ` ` + i18n.T(lang, "notifications.digest.in_project", project.Title)
+	}
+	return line
+}
+
+// ToMail returns the mail notification for DigestNotification
+func (n *DigestNotification) ToMail(lang string) *notifications.Mail {
+	mail := notifications.NewMail().
+		Subject(i18n.T(lang, "notifications.digest.subject_"+n.Frequency)).
+		Greeting(i18n.T(lang, "notifications.common.greeting", n.User.GetName()))
+
+	if len(n.DueTasks) > 0 {
+		dueLine := i18n.T(lang, "notifications.digest.due_today")
+		if n.Frequency == user.DigestFrequencyWeekly {
+			dueLine = i18n.T(lang, "notifications.digest.due_this_week")
+		}
+		tasks := ""
+		for _, task := range n.DueTasks {
+			tasks += n.taskLine(lang, task) + "\n"
+		}
+		mail.Line(dueLine).Line(tasks)
+	}
+
+	if len(n.OverdueTasks) > 0 {
+		tasks := ""
+		for _, task := range n.OverdueTasks {
+			tasks += n.taskLine(lang, task) + "\n"
+		}
+		mail.Line(i18n.T(lang, "notifications.digest.overdue")).Line(tasks)
+	}
+
+	if len(n.Activities) > 0 {
+		activities := ""
+		for _, activity := range n.Activities {
+			activities += n.activityLine(lang, activity) + "\n"
+		}
+		mail.Line(i18n.T(lang, "notifications.digest.activity")).Line(activities)
+	}
+
+	return mail.
+		Action(i18n.T(lang, "notifications.common.actions.open_vikunja"), config.ServicePublicURL.GetString()).
+		Line(i18n.T(lang, "notifications.common.have_nice_day"))
+}
+
+// ToDB returns the DigestNotification notification in a format which can be saved in the db
+func (n *DigestNotification) ToDB() interface{} {
+	return nil
+}
+
+// Name returns the name of the notification
+func (n *DigestNotification) Name() string {
+	return "digest"
+}
+
+// TasksBatchedNotification combines many notifications of the same kind about tasks, for example when a lot of
+// tasks of a project were deleted at once.
+type TasksBatchedNotification struct {
+	// The user who caused all combined notifications. Empty if they were caused by different users.
+	Doer  *user.User `json:"doer"`
+	Tasks []*Task    `json:"tasks"`
+	// What happened, for example "17 tasks were deleted in Project X".
+	Summary string `json:"summary"`
+	// The name of the combined notifications, for example task.deleted
+	Notification string `json:"notification"`
+	// The project the tasks belong to, if the notifications were batched per project.
+	Project *Project `json:"project,omitempty"`
+	// The saved filter the tasks now match, if the notifications were batched per filter.
+	Filter *SavedFilter `json:"filter,omitempty"`
+
+	AssignedToYou bool `json:"-"`
+	TasksDeleted  bool `json:"-"`
+}
+
+func newTasksBatchedNotification(name string, doers []*user.User, tasks []*Task) *TasksBatchedNotification {
+	n := &TasksBatchedNotification{
+		Tasks:        tasks,
+		Notification: name,
+	}
+
+	n.Doer = doers[0]
+	for _, doer := range doers {
+		if doer == nil || n.Doer == nil || doer.ID != n.Doer.ID {
+			n.Doer = nil
+			break
+		}
+	}
+
+	return n
+}
+
+// getBatchProject returns the project for the summary of a batched notification or nil if it could not be found.
+func getBatchProject(projectID int64) *Project {
+	s := db.NewSession()
+	defer s.Close()
+
+	project, err := GetProjectSimpleByID(s, projectID)
+	if err != nil {
+		log.Errorf("Could not get project %d for batched notification: %s", projectID, err)
+		return nil
+	}
+	return project
+}
+
+func (n *TasksBatchedNotification) summary(lang string) string {
+	if n.Filter != nil {
+		return i18n.T(lang, "notifications.task.batched.saved_filter_matched", len(n.Tasks), n.Filter.Title)
+	}
+
+	project := i18n.T(lang, "notifications.task.batched.unknown_project")
+	if n.Project != nil {
+		project = n.Project.Title
+	}
+
+	switch {
+	case n.TasksDeleted:
+		return i18n.T(lang, "notifications.task.batched.deleted", len(n.Tasks), project)
+	case n.AssignedToYou:
+		return i18n.T(lang, "notifications.task.batched.assigned_to_you", len(n.Tasks), project)
+	default:
+		return i18n.T(lang, "notifications.task.batched.assigned", len(n.Tasks), project)
+	}
+}
+
+// ToMail returns the mail notification for TasksBatchedNotification
+func (n *TasksBatchedNotification) ToMail(lang string) *notifications.Mail {
+	summary := n.summary(lang)
+	mail := notifications.NewMail().
+		Subject(summary)
+
+	if n.Doer != nil {
+		mail.Line(i18n.T(lang, "notifications.task.batched.by", summary, n.Doer.GetName()))
+	} else {
+		mail.Line(summary + ":")
+	}
+
+	tasks := ""
+	for _, task := range n.Tasks {
+		if n.TasksDeleted {
+			tasks += `* ` + task.Title + ` (` + task.GetFullIdentifier() + `)` + "\n"
+			continue
+		}
+		tasks += `* [` + task.Title + `](` + task.GetFrontendURL() + `) (` + task.GetFullIdentifier() + `)` + "\n"
+	}
+
+	return mail.Line(tasks)
+}
+
+// ToDB returns the TasksBatchedNotification notification in a format which can be saved in the db
+func (n *TasksBatchedNotification) ToDB() interface{} {
+	n.Summary = n.summary(i18n.DefaultLanguage)
+	return n
+}
+
+// Name returns the name of the notification
+func (n *TasksBatchedNotification) Name() string {
+	return "task.batched"
+}
+
+// ProjectID returns the project of the combined notifications, if they were batched per project
+func (n *TasksBatchedNotification) ProjectID() int64 {
+	if n.Project == nil {
+		return 0
+	}
+	return n.Project.ID
+}
+
+// PreferenceName makes the combined notification follow the preferences of the notifications it combines
+func (n *TasksBatchedNotification) PreferenceName() string {
+	return n.Notification
+}
@@ -30,6 +30,14 @@ type DatabaseNotifications struct {
 	// True is read, false is unread.
 	Read bool `xorm:"-" json:"read"`
 
+	// Only return notifications with one of these names, for example task.comment.
+	FilterNames    []string `xorm:"-" json:"-" query:"name"`
+	FilterNamesArr []string `xorm:"-" json:"-" query:"name[]"`
+	// Only return notifications about this project.
+	FilterProjectID int64 `xorm:"-" json:"-" query:"project_id"`
+	// Only return notifications which were not read yet.
+	FilterUnread bool `xorm:"-" json:"-" query:"unread"`
+
 	web.CRUDable `xorm:"-" json:"-"`
 	web.Rights   `xorm:"-" json:"-"`
 }
@@ -42,6 +50,9 @@ type DatabaseNotifications struct {
 // @Produce json
 // @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
 // @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Param name query string false "Only return notifications with this name, for example task.comment. Can be passed multiple times."
+// @Param project_id query int false "Only return notifications about this project."
+// @Param unread query bool false "If true, only unread notifications are returned."
 // @Security JWTKeyAuth
 // @Success 200 {array} notifications.DatabaseNotification "The notifications"
 // @Failure 403 {object} web.HTTPError "Link shares cannot have notifications."
@@ -52,8 +63,14 @@ func (d *DatabaseNotifications) ReadAll(s *xorm.Session, a web.Auth, _ string, p
 		return nil, 0, 0, ErrGenericForbidden{}
 	}
 
+	filter := &notifications.Filter{
+		Names:      append(d.FilterNames, d.FilterNamesArr...),
+		ProjectID:  d.FilterProjectID,
+		UnreadOnly: d.FilterUnread,
+	}
+
 	limit, start := getLimitFromPageIndex(page, perPage)
-	return notifications.GetNotificationsForUser(s, a.GetID(), limit, start)
+	return notifications.GetNotificationsForUser(s, a.GetID(), filter, limit, start)
 }
 
 // CanUpdate checks if a user can mark a notification as read.
@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	"code.vikunja.io/api/pkg/config"
 	"code.vikunja.io/api/pkg/db"
 	"code.vikunja.io/api/pkg/events"
 	"code.vikunja.io/api/pkg/files"
@@ -47,6 +48,10 @@ type Project struct {
 	Identifier string `xorm:"varchar(10) null" json:"identifier" valid:"runelength(0|10)" minLength:"0" maxLength:"10"`
 	// The hex color of this project
 	HexColor string `xorm:"varchar(6) null" json:"hex_color" valid:"runelength(0|7)" maxLength:"7"`
+	// An emoji to show next to the project title. Clients should prefer the icon image if one was uploaded.
+	Icon string `xorm:"varchar(64) null" json:"icon" valid:"runelength(0|16)" maxLength:"16"`
+	// The id of the uploaded icon image. If not 0, the icon can be accessed at /projects/{projectID}/icon
+	IconFileID int64 `xorm:"bigint null" json:"icon_file_id"`
 
 	OwnerID         int64    `xorm:"bigint INDEX not null" json:"-"`
 	ParentProjectID int64    `xorm:"bigint INDEX null" json:"parent_project_id"`
@@ -57,6 +62,34 @@ type Project struct {
 
 	// Whether a project is archived.
 	IsArchived bool `xorm:"not null default false" json:"is_archived" query:"is_archived"`
+	// If this project was archived because one of its parent projects was archived, this holds the id of that
+	// parent project. The project is unarchived again together with it.
+	ArchivedWithProjectID int64 `xorm:"bigint INDEX null" json:"archived_with_project_id"`
+
+	// Whether this project is a template. Templates can be used to create new projects with the same structure.
+	IsTemplate bool `xorm:"not null default false" json:"is_template"`
+
+	// If set, the priority of undone tasks in this project is raised automatically as their due date comes closer.
+	PriorityAging []*PriorityAgingThreshold `xorm:"JSON null" json:"priority_aging"`
+
+	// Values new tasks in this project get if they are created without them. Only project admins can change these.
+	// If not provided when updating a project, the defaults stay as they are. Send an empty object to remove them.
+	TaskDefaults *ProjectTaskDefaults `xorm:"JSON null" json:"task_defaults"`
+
+	// If true, everyone can see this project and its tasks through the public endpoints, without logging in.
+	// Only project admins can change this.
+	IsPublic bool `xorm:"not null default false" json:"is_public"`
+
+	// Whether this project is frozen. Tasks in frozen projects and their child projects can still be read and
+	// commented on, but not changed. Only project admins can freeze or unfreeze a project.
+	IsFrozen bool `xorm:"not null default false" json:"is_frozen"`
+
+	// The maximum number of undone tasks this project and each of its child projects can have. 0 means no limit
+	// other than the one configured for the instance. Only project admins can change this.
+	MaxOpenTasks int64 `xorm:"bigint not null default 0" json:"max_open_tasks"`
+	// The maximum combined size in bytes of all task attachments in this project and each of its child projects.
+	// 0 means no limit other than the one configured for the instance. Only project admins can change this.
+	MaxAttachmentsSize int64 `xorm:"bigint not null default 0" json:"max_attachments_size"`
 
 	// The id of the file this project has set as background
 	BackgroundFileID int64 `xorm:"null" json:"-"`
@@ -72,6 +105,10 @@ type Project struct {
 	// Will only returned when retreiving one project.
 	Subscription *Subscription `xorm:"-" json:"subscription,omitempty"`
 
+	// How much storage the attachments of all tasks in this project use and how much they may use.
+	// Will only returned when retreiving one project.
+	AttachmentsUsage *AttachmentsUsage `xorm:"-" json:"attachments_usage,omitempty"`
+
 	// The position this project has when querying all projects. See the tasks.position property on how to use this.
 	Position float64 `xorm:"double null" json:"position"`
 
@@ -97,6 +134,8 @@ type ProjectWithTasksAndBuckets struct {
 	TaskBuckets      []*TaskBucket   `xorm:"-" json:"task_buckets"`
 	Positions        []*TaskPosition `xorm:"-" json:"positions"`
 	BackgroundFileID int64           `xorm:"null" json:"background_file_id"`
+	// Only used for migration, holds the content of the exported icon image.
+	IconFile []byte `xorm:"-" json:"-"`
 }
 
 // TableName returns a better name for the projects table
@@ -302,6 +341,13 @@ func (p *Project) ReadOne(s *xorm.Session, a web.Auth) (err error) {
 		return nil
 	}
 
+	if !isFilter {
+		p.AttachmentsUsage, err = p.getAttachmentsUsage(s)
+		if err != nil {
+			return err
+		}
+	}
+
 	p.Views, err = getViewsForProject(s, p.ID)
 	return
 }
@@ -570,16 +616,32 @@ func getSavedFilterProjects(s *xorm.Session, doer *user.User) (savedFiltersProje
 		return
 	}
 
-	if len(savedFilters) == 0 {
-		return nil, nil
-	}
-
 	for _, filter := range savedFilters {
 		filterProject := filter.toProject()
 		filterProject.Owner = doer
 		savedFiltersProjects = append(savedFiltersProjects, filterProject)
 	}
 
+	sharedFilters, err := getSavedFiltersSharedWithUser(s, doer)
+	if err != nil || len(sharedFilters) == 0 {
+		return
+	}
+
+	ownerIDs := make([]int64, 0, len(sharedFilters))
+	for _, filter := range sharedFilters {
+		ownerIDs = append(ownerIDs, filter.OwnerID)
+	}
+	owners, err := user.GetUsersByIDs(s, ownerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, filter := range sharedFilters {
+		filterProject := filter.toProject()
+		filterProject.Owner = owners[filter.OwnerID]
+		savedFiltersProjects = append(savedFiltersProjects, filterProject)
+	}
+
 	return
 }
 
@@ -726,7 +788,51 @@ func (p *Project) CheckIsArchived(s *xorm.Session) (err error) {
 	return nil
 }
 
+// checkIsFrozen returns an ErrProjectIsFrozen if the project or any of its parent projects is frozen.
+func (p *Project) checkIsFrozen(s *xorm.Session) error {
+	if p.IsFrozen {
+		return ErrProjectIsFrozen{ProjectID: p.ID}
+	}
+
+	if p.ParentProjectID == 0 {
+		return nil
+	}
+
+	parent, err := GetProjectSimpleByID(s, p.ParentProjectID)
+	if err != nil {
+		return err
+	}
+	return parent.checkIsFrozen(s)
+}
+
+// checkIsFrozenUpdate makes sure only project admins freeze or unfreeze a project
+func checkIsFrozenUpdate(s *xorm.Session, project *Project, a web.Auth) error {
+	old, err := GetProjectSimpleByID(s, project.ID)
+	if err != nil {
+		return err
+	}
+
+	if old.IsFrozen == project.IsFrozen {
+		return nil
+	}
+
+	isAdmin, err := project.IsAdmin(s, a)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return ErrGenericForbidden{}
+	}
+
+	return nil
+}
+
 func checkProjectBeforeUpdateOrDelete(s *xorm.Session, project *Project) (err error) {
+	err = validatePriorityAging(project)
+	if err != nil {
+		return err
+	}
+
 	if project.ParentProjectID < 0 {
 		return &ErrProjectCannotBelongToAPseudoParentProject{ProjectID: project.ID, ParentProjectID: project.ParentProjectID}
 	}
@@ -795,6 +901,11 @@ func CreateProject(s *xorm.Session, project *Project, auth web.Auth, createBackl
 		return err
 	}
 
+	err = checkUserProjectQuota(s, doer.ID)
+	if err != nil {
+		return err
+	}
+
 	project.OwnerID = doer.ID
 	project.Owner = doer
 
@@ -804,6 +915,12 @@ func CreateProject(s *xorm.Session, project *Project, auth web.Auth, createBackl
 	}
 
 	project.HexColor = utils.NormalizeHex(project.HexColor)
+	// Icon images can only be set through the upload, never by passing a file id
+	project.IconFileID = 0
+
+	if project.IsPublic && !config.ServiceEnablePublicProjects.GetBool() {
+		return &ErrPublicProjectsDisabled{}
+	}
 
 	_, err = s.Insert(project)
 	if err != nil {
@@ -860,6 +977,11 @@ func UpdateProject(s *xorm.Session, project *Project, auth web.Auth, updateProje
 		return
 	}
 
+	old, err := GetProjectSimpleByID(s, project.ID)
+	if err != nil {
+		return err
+	}
+
 	if project.IsArchived {
 		isDefaultProject, err := project.isDefaultProject(s)
 		if err != nil {
@@ -875,12 +997,21 @@ func UpdateProject(s *xorm.Session, project *Project, auth web.Auth, updateProje
 	colsToUpdate := []string{
 		"title",
 		"is_archived",
+		"archived_with_project_id",
 		"identifier",
 		"hex_color",
+		"icon",
 		"parent_project_id",
 		"position",
 		"done_bucket_id",
 		"default_bucket_id",
+		"priority_aging",
+		"is_template",
+		"task_defaults",
+		"is_public",
+		"is_frozen",
+		"max_open_tasks",
+		"max_attachments_size",
 	}
 	if project.Description != "" {
 		colsToUpdate = append(colsToUpdate, "description")
@@ -915,6 +1046,12 @@ func UpdateProject(s *xorm.Session, project *Project, auth web.Auth, updateProje
 
 	project.HexColor = utils.NormalizeHex(project.HexColor)
 
+	// Only archiving a parent project can archive a project on behalf of it
+	project.ArchivedWithProjectID = old.ArchivedWithProjectID
+	if !project.IsArchived {
+		project.ArchivedWithProjectID = 0
+	}
+
 	_, err = s.
 		ID(project.ID).
 		Cols(colsToUpdate...).
@@ -923,6 +1060,11 @@ func UpdateProject(s *xorm.Session, project *Project, auth web.Auth, updateProje
 		return err
 	}
 
+	err = updateChildProjectsArchivedState(s, project, old.IsArchived)
+	if err != nil {
+		return err
+	}
+
 	err = events.Dispatch(&ProjectUpdatedEvent{
 		Project: project,
 		Doer:    auth,
@@ -1003,6 +1145,26 @@ func (p *Project) Update(s *xorm.Session, a web.Auth) (err error) {
 		return nil
 	}
 
+	err = checkTaskDefaultsUpdate(s, p, a)
+	if err != nil {
+		return err
+	}
+
+	err = checkIsPublicUpdate(s, p, a)
+	if err != nil {
+		return err
+	}
+
+	err = checkIsFrozenUpdate(s, p, a)
+	if err != nil {
+		return err
+	}
+
+	err = checkQuotaUpdate(s, p, a)
+	if err != nil {
+		return err
+	}
+
 	return UpdateProject(s, p, a, false)
 }
 
@@ -1032,6 +1194,7 @@ func updateProjectByTaskID(s *xorm.Session, taskID int64) (err error) {
 // @Success 201 {object} models.Project "The created project."
 // @Failure 400 {object} web.HTTPError "Invalid project object provided."
 // @Failure 403 {object} web.HTTPError "The user does not have access to the project"
+// @Failure 412 {object} web.HTTPError "The user reached their limit of projects."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /projects [put]
 func (p *Project) Create(s *xorm.Session, a web.Auth) (err error) {
@@ -1101,6 +1264,11 @@ func (p *Project) Delete(s *xorm.Session, a web.Auth) (err error) {
 		return
 	}
 
+	err = fullProject.DeleteIconFileIfExists()
+	if err != nil {
+		return
+	}
+
 	// If we're deleting a default project, remove it as default
 	if isDefaultProject {
 		_, err = s.Where("default_project_id = ?", p.ID).
@@ -1151,6 +1319,68 @@ func (p *Project) Delete(s *xorm.Session, a web.Auth) (err error) {
 		return
 	}
 
+	_, err = s.Where("project_id = ?", p.ID).Delete(&TaskForm{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("project_id = ?", p.ID).Delete(&BucketTemplate{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("project_id = ?", p.ID).Delete(&SavedFilterShare{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("project_id = ?", p.ID).Delete(&ProjectViewPreferences{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("project_id = ?", p.ID).Delete(&InboxItem{})
+	if err != nil {
+		return
+	}
+
+	err = makeSharedLabelsPersonal(s, builder.Eq{"project_id": p.ID})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("project_id = ?", p.ID).Delete(&ProjectEmailIntake{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("project_id = ?", p.ID).Delete(&ProjectTransfer{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.
+		In("rule_id", builder.Select("id").From("task_sla_rules").Where(builder.Eq{"project_id": p.ID})).
+		Delete(&TaskSLABreach{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("project_id = ?", p.ID).Delete(&TaskSLARule{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("project_id = ?", p.ID).Delete(&ProjectApprover{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("project_id = ?", p.ID).Delete(&ProjectActivity{})
+	if err != nil {
+		return
+	}
+
 	// Delete the project
 	_, err = s.ID(p.ID).Delete(&Project{})
 	if err != nil {
@@ -0,0 +1,195 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// ProjectActivity is a single entry in the activity stream of a project
+type ProjectActivity struct {
+	// The unique, numeric id of this activity.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id"`
+	// The project this activity happened in.
+	ProjectID int64 `xorm:"bigint not null INDEX" json:"project_id" param:"project"`
+	// The task this activity is about, 0 if it is not about a task.
+	TaskID int64 `xorm:"bigint null INDEX" json:"task_id"`
+	// The name of the event which caused this activity, for example `task.created` or `project.shared.user`.
+	Kind string `xorm:"varchar(50) not null" json:"kind"`
+	// A short description of the subject, like the task title or the name of the user a project was shared with.
+	Title string `xorm:"text null" json:"title"`
+
+	ActorID int64 `xorm:"bigint null INDEX" json:"-"`
+	// The user or link share who did this.
+	Actor *user.User `xorm:"-" json:"actor"`
+
+	// When this activity happened.
+	Created time.Time `xorm:"created not null INDEX" json:"created"`
+
+	// Only return activities of this user. Link shares have negative ids.
+	FilterActorID int64 `xorm:"-" json:"-" query:"actor_id"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for project activities
+func (*ProjectActivity) TableName() string {
+	return "project_activities"
+}
+
+// CanRead checks if the user can see the activity stream of a project
+func (pa *ProjectActivity) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	return (&Project{ID: pa.ProjectID}).CanRead(s, a)
+}
+
+// ReadAll returns the activity stream of a project, newest first
+// @Summary Get the activity stream of a project
+// @Description Returns everything that happened in a project, like created or completed tasks, new comments, tasks moved between buckets and new shares. The newest activity comes first.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project ID"
+// @Param actor_id query int false "Only return activities of this user."
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Success 200 {array} models.ProjectActivity "The activities."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/activity [get]
+func (pa *ProjectActivity) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	can, _, err := pa.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	cond := builder.Eq{"project_id": pa.ProjectID}
+	if pa.FilterActorID != 0 {
+		cond["actor_id"] = pa.FilterActorID
+	}
+
+	activities := []*ProjectActivity{}
+	err = s.Where(cond).
+		OrderBy("created desc, id desc").
+		Limit(getLimitFromPageIndex(page, perPage)).
+		Find(&activities)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	numberOfTotalItems, err = s.Where(cond).Count(&ProjectActivity{})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	actorIDs := make([]int64, 0, len(activities))
+	for _, activity := range activities {
+		actorIDs = append(actorIDs, activity.ActorID)
+	}
+	actors, err := getUsersOrLinkSharesFromIDs(s, actorIDs)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	for _, activity := range activities {
+		activity.Actor = actors[activity.ActorID]
+	}
+
+	return activities, len(activities), numberOfTotalItems, nil
+}
+
+func registerEventForProjectActivity(event events.Event) {
+	events.RegisterListener(event.Name(), &RecordProjectActivity{
+		EventName: event.Name(),
+	})
+}
+
+// RecordProjectActivity represents a listener
+type RecordProjectActivity struct {
+	EventName string
+}
+
+// Name defines the name for the RecordProjectActivity listener
+func (r *RecordProjectActivity) Name() string {
+	return "project.activity.record"
+}
+
+// Handle is executed when the event RecordProjectActivity listens on is fired
+func (r *RecordProjectActivity) Handle(msg *message.Message) (err error) {
+	event := map[string]interface{}{}
+	err = json.Unmarshal(msg.Payload, &event)
+	if err != nil {
+		return err
+	}
+
+	projectID := getProjectIDFromAnyEvent(event)
+	if projectID == 0 {
+		return nil
+	}
+
+	activity := &ProjectActivity{
+		ProjectID: projectID,
+		Kind:      r.EventName,
+	}
+
+	if task, has := event["task"].(map[string]interface{}); has {
+		if id, has := task["id"]; has {
+			activity.TaskID = getIDAsInt64(id)
+		}
+		activity.Title, _ = task["title"].(string)
+	}
+	if u, has := event["user"].(map[string]interface{}); has {
+		activity.Title, _ = u["username"].(string)
+	}
+	if team, has := event["team"].(map[string]interface{}); has {
+		activity.Title, _ = team["name"].(string)
+	}
+
+	if doer, has := event["doer"].(map[string]interface{}); has {
+		if id, has := doer["id"]; has {
+			activity.ActorID = getIDAsInt64(id)
+		}
+		// Link shares are stored with negative ids, the same way they are in created_by_id of tasks
+		if _, isLinkShare := doer["hash"]; isLinkShare {
+			activity.ActorID *= -1
+		}
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	_, err = s.Insert(activity)
+	if err != nil {
+		_ = s.Rollback()
+		return err
+	}
+
+	return s.Commit()
+}
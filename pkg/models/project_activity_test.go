@@ -0,0 +1,91 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectActivity(t *testing.T) {
+	record := func(t *testing.T, event interface{ Name() string }) {
+		payload, err := json.Marshal(event)
+		require.NoError(t, err)
+		listener := &RecordProjectActivity{EventName: event.Name()}
+		err = listener.Handle(message.NewMessage("1", payload))
+		require.NoError(t, err)
+	}
+
+	t.Run("record and read", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		record(t, &TaskCreatedEvent{
+			Task: &Task{ID: 1, ProjectID: 1, Title: "task #1"},
+			Doer: &user.User{ID: 1},
+		})
+		record(t, &ProjectSharedWithUserEvent{
+			Project: &Project{ID: 1},
+			User:    &user.User{ID: 2, Username: "user2"},
+			Doer:    &LinkSharing{ID: 2, Hash: "test"},
+		})
+
+		db.AssertExists(t, "project_activities", map[string]interface{}{
+			"project_id": 1,
+			"task_id":    1,
+			"kind":       "task.created",
+			"title":      "task #1",
+			"actor_id":   1,
+		}, false)
+		db.AssertExists(t, "project_activities", map[string]interface{}{
+			"project_id": 1,
+			"kind":       "project.shared.user",
+			"title":      "user2",
+			"actor_id":   -2,
+		}, false)
+
+		s := db.NewSession()
+		defer s.Close()
+
+		pa := &ProjectActivity{ProjectID: 1, FilterActorID: 1}
+		result, _, _, err := pa.ReadAll(s, &user.User{ID: 1}, "", 0, 0)
+		require.NoError(t, err)
+		activities := result.([]*ProjectActivity)
+		require.NotEmpty(t, activities)
+		for _, activity := range activities {
+			assert.Equal(t, int64(1), activity.ActorID)
+			require.NotNil(t, activity.Actor)
+			assert.Equal(t, "user1", activity.Actor.Username)
+		}
+	})
+	t.Run("no access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pa := &ProjectActivity{ProjectID: 1}
+		_, _, _, err := pa.ReadAll(s, &user.User{ID: 2}, "", 0, 0)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+}
@@ -0,0 +1,185 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// ProjectApprover is a user who approves tasks being done in a project.
+// As soon as a project has at least one approver, tasks in it need approval before they are done.
+type ProjectApprover struct {
+	// The unique numeric id of this approver
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id"`
+	// The project the user approves tasks in
+	ProjectID int64 `xorm:"bigint not null unique(project_user)" json:"-" param:"project"`
+	// The id of the user who approves tasks
+	UserID int64      `xorm:"bigint not null unique(project_user)" json:"user_id" param:"user"`
+	User   *user.User `xorm:"-" json:"user"`
+
+	// A timestamp when this approver was added. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for project approvers
+func (*ProjectApprover) TableName() string {
+	return "project_approvers"
+}
+
+func getProjectApprovers(s *xorm.Session, projectID int64) (approvers []*user.User, err error) {
+	approvers = []*user.User{}
+	err = s.
+		Where(builder.In("id", builder.Select("user_id").From("project_approvers").Where(builder.Eq{"project_id": projectID}))).
+		Find(&approvers)
+	return
+}
+
+// isProjectApprover checks if the given auth may approve tasks in a project. Link shares never can.
+func isProjectApprover(s *xorm.Session, projectID int64, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	return s.
+		Where("project_id = ? AND user_id = ?", projectID, a.GetID()).
+		Exist(&ProjectApprover{})
+}
+
+// ReadAll returns all approvers of a project
+// @Summary Get all approvers of a project
+// @Description Returns all users who approve tasks being done in a project.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project ID"
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Success 200 {array} models.ProjectApprover "The approvers"
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project"
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/approvers [get]
+func (pa *ProjectApprover) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	can, _, err := pa.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	approvers := []*ProjectApprover{}
+	err = s.
+		Where("project_id = ?", pa.ProjectID).
+		Limit(getLimitFromPageIndex(page, perPage)).
+		OrderBy("id asc").
+		Find(&approvers)
+	if err != nil {
+		return
+	}
+
+	userIDs := make([]int64, 0, len(approvers))
+	for _, approver := range approvers {
+		userIDs = append(userIDs, approver.UserID)
+	}
+
+	users, err := user.GetUsersByIDs(s, userIDs)
+	if err != nil {
+		return
+	}
+
+	for _, approver := range approvers {
+		approver.User = users[approver.UserID]
+		if approver.User != nil {
+			approver.User.Email = ""
+		}
+	}
+
+	numberOfTotalItems, err = s.Where("project_id = ?", pa.ProjectID).Count(&ProjectApprover{})
+	return approvers, len(approvers), numberOfTotalItems, err
+}
+
+// Create adds an approver to a project
+// @Summary Add an approver to a project
+// @Description Adds a user who approves tasks being done in the project. The user needs access to the project. Will do nothing if the user already is an approver.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project ID"
+// @Param approver body models.ProjectApprover true "The approver, only the user_id is used."
+// @Success 201 {object} models.ProjectApprover "The approver"
+// @Failure 400 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the project"
+// @Failure 404 {object} web.HTTPError "The user does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/approvers [put]
+func (pa *ProjectApprover) Create(s *xorm.Session, _ web.Auth) (err error) {
+	pa.User, err = user.GetUserByID(s, pa.UserID)
+	if err != nil {
+		return err
+	}
+
+	project := &Project{ID: pa.ProjectID}
+	canRead, _, err := project.CanRead(s, pa.User)
+	if err != nil {
+		return err
+	}
+	if !canRead {
+		return ErrUserDoesNotHaveAccessToProject{ProjectID: pa.ProjectID, UserID: pa.UserID}
+	}
+
+	pa.ID = 0
+	pa.User.Email = ""
+
+	exists, err := s.
+		Where("project_id = ? AND user_id = ?", pa.ProjectID, pa.UserID).
+		Exist(&ProjectApprover{})
+	if err != nil || exists {
+		return err
+	}
+
+	_, err = s.Insert(pa)
+	return
+}
+
+// Delete removes an approver from a project
+// @Summary Remove an approver from a project
+// @Description Removes a user from the approvers of a project. Once the last approver was removed, tasks in the project don't need approval anymore.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project ID"
+// @Param userID path int true "The id of the user"
+// @Success 200 {object} models.Message "The approver was successfully removed."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the project"
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/approvers/{userID} [delete]
+func (pa *ProjectApprover) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.
+		Where("project_id = ? AND user_id = ?", pa.ProjectID, pa.UserID).
+		Delete(&ProjectApprover{})
+	return
+}
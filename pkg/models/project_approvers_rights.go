@@ -0,0 +1,40 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if a user can see the approvers of a project
+func (pa *ProjectApprover) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	p := &Project{ID: pa.ProjectID}
+	return p.CanRead(s, a)
+}
+
+// CanCreate checks if a user can add approvers to a project. Only project admins can do that.
+func (pa *ProjectApprover) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	p := &Project{ID: pa.ProjectID}
+	return p.IsAdmin(s, a)
+}
+
+// CanDelete checks if a user can remove approvers from a project
+func (pa *ProjectApprover) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	p := &Project{ID: pa.ProjectID}
+	return p.IsAdmin(s, a)
+}
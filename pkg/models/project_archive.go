@@ -0,0 +1,108 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// getAllChildProjectIDs returns the ids of all projects below the given one, no matter how deep they are nested.
+func getAllChildProjectIDs(s *xorm.Session, projectID int64) (ids []int64, err error) {
+	ids = []int64{}
+	err = s.SQL(`WITH RECURSIVE project_tree AS (
+			SELECT id FROM projects WHERE parent_project_id = ?
+			UNION ALL
+			SELECT p.id FROM projects p INNER JOIN project_tree pt ON p.parent_project_id = pt.id
+		)
+		SELECT id FROM project_tree`, projectID).Find(&ids)
+	return
+}
+
+// updateChildProjectsArchivedState archives all child projects of a project which was just archived or restores
+// them once it is unarchived again. Only child projects which were not archived on their own are touched, the
+// archived_with_project_id column keeps track of which ones those are.
+func updateChildProjectsArchivedState(s *xorm.Session, project *Project, wasArchived bool) (err error) {
+	if project.IsArchived == wasArchived {
+		return nil
+	}
+
+	if !project.IsArchived {
+		_, err = s.
+			Where("archived_with_project_id = ?", project.ID).
+			Cols("is_archived", "archived_with_project_id").
+			Update(&Project{})
+		return
+	}
+
+	childIDs, err := getAllChildProjectIDs(s, project.ID)
+	if err != nil || len(childIDs) == 0 {
+		return err
+	}
+
+	// A project can't be archived if any of the projects which would be archived with it is the default project
+	// of a user, the same way it can't be archived if it is one itself.
+	defaultProjectUser := &user.User{}
+	isDefault, err := s.
+		In("default_project_id", childIDs).
+		Get(defaultProjectUser)
+	if err != nil {
+		return err
+	}
+	if isDefault {
+		return &ErrCannotArchiveDefaultProject{ProjectID: defaultProjectUser.DefaultProjectID}
+	}
+
+	_, err = s.
+		In("id", childIDs).
+		And("is_archived = ?", false).
+		Cols("is_archived", "archived_with_project_id").
+		Update(&Project{
+			IsArchived:            true,
+			ArchivedWithProjectID: project.ID,
+		})
+	return
+}
+
+// UnarchiveProject unarchives a project together with all child projects which were archived with it.
+// Projects can only be unarchived if none of their parent projects is archived.
+func UnarchiveProject(s *xorm.Session, projectID int64, a web.Auth) (project *Project, err error) {
+	project, err = GetProjectSimpleByID(s, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	project.IsArchived = false
+	can, err := project.CanUpdate(s, a)
+	if err != nil {
+		return nil, err
+	}
+	if !can {
+		return nil, ErrGenericForbidden{}
+	}
+
+	// Unarchiving a project shouldn't change whether it is a favorite of the doer
+	project.IsFavorite, err = isFavorite(s, project.ID, a, FavoriteKindProject)
+	if err != nil {
+		return nil, err
+	}
+
+	err = UpdateProject(s, project, a, false)
+	return project, err
+}
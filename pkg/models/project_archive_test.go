@@ -0,0 +1,143 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func TestProjectArchiveCascade(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	// Creates a project with a child, a grandchild and a second child which is archived on its own
+	createTree := func(t *testing.T, s *xorm.Session) (parent, child, grandchild, archivedChild *Project) {
+		parent = &Project{Title: "parent"}
+		require.NoError(t, parent.Create(s, u))
+		child = &Project{Title: "child", ParentProjectID: parent.ID}
+		require.NoError(t, child.Create(s, u))
+		grandchild = &Project{Title: "grandchild", ParentProjectID: child.ID}
+		require.NoError(t, grandchild.Create(s, u))
+		archivedChild = &Project{Title: "archived child", ParentProjectID: parent.ID}
+		require.NoError(t, archivedChild.Create(s, u))
+		archivedChild.IsArchived = true
+		require.NoError(t, archivedChild.Update(s, u))
+		return
+	}
+	archive := func(t *testing.T, s *xorm.Session, projectID int64) error {
+		p, err := GetProjectSimpleByID(s, projectID)
+		require.NoError(t, err)
+		p.IsArchived = true
+		return p.Update(s, u)
+	}
+
+	t.Run("archives and restores child projects", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		parent, child, grandchild, archivedChild := createTree(t, s)
+		require.NoError(t, archive(t, s, parent.ID))
+		require.NoError(t, s.Commit())
+
+		for _, id := range []int64{child.ID, grandchild.ID} {
+			db.AssertExists(t, "projects", map[string]interface{}{
+				"id":                       id,
+				"is_archived":              true,
+				"archived_with_project_id": parent.ID,
+			}, false)
+		}
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":                       archivedChild.ID,
+			"is_archived":              true,
+			"archived_with_project_id": 0,
+		}, false)
+
+		s2 := db.NewSession()
+		defer s2.Close()
+		_, err := UnarchiveProject(s2, parent.ID, u)
+		require.NoError(t, err)
+		require.NoError(t, s2.Commit())
+
+		for _, id := range []int64{parent.ID, child.ID, grandchild.ID} {
+			db.AssertExists(t, "projects", map[string]interface{}{
+				"id":                       id,
+				"is_archived":              false,
+				"archived_with_project_id": 0,
+			}, false)
+		}
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":          archivedChild.ID,
+			"is_archived": true,
+		}, false)
+	})
+	t.Run("can't unarchive a child of an archived project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		parent, child, _, _ := createTree(t, s)
+		require.NoError(t, archive(t, s, parent.ID))
+
+		_, err := UnarchiveProject(s, child.ID, u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectIsArchived(err))
+	})
+	t.Run("child is a default project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		parent, _, grandchild, _ := createTree(t, s)
+		_, err := s.Where("id = ?", 1).Cols("default_project_id").Update(&user.User{DefaultProjectID: grandchild.ID})
+		require.NoError(t, err)
+
+		err = archive(t, s, parent.ID)
+		require.Error(t, err)
+		assert.True(t, IsErrCannotArchiveDefaultProject(err))
+	})
+	t.Run("tasks are left out of filters", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		parent, _, grandchild, _ := createTree(t, s)
+		task := &Task{Title: "in an archived project", ProjectID: grandchild.ID}
+		require.NoError(t, task.Create(s, u))
+		require.NoError(t, archive(t, s, parent.ID))
+
+		hasTask := func(tc *TaskCollection) bool {
+			result, _, _, err := tc.ReadAll(s, u, "", 0, -1)
+			require.NoError(t, err)
+			for _, tk := range result.([]*Task) {
+				if tk.ID == task.ID {
+					return true
+				}
+			}
+			return false
+		}
+
+		assert.False(t, hasTask(&TaskCollection{}))
+		assert.True(t, hasTask(&TaskCollection{IncludeArchived: true}))
+	})
+}
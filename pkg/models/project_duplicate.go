@@ -17,10 +17,17 @@
 package models
 
 import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"code.vikunja.io/api/pkg/events"
 	"code.vikunja.io/api/pkg/files"
 	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/user"
 	"code.vikunja.io/api/pkg/utils"
 	"code.vikunja.io/web"
+	"xorm.io/builder"
 	"xorm.io/xorm"
 )
 
@@ -31,8 +38,32 @@ type ProjectDuplicate struct {
 	// The target parent project
 	ParentProjectID int64 `json:"parent_project_id,omitempty"`
 
-	// The copied project
+	// If true, only the project with its views and buckets is copied, but no tasks.
+	SkipTasks bool `json:"skip_tasks"`
+	// If true, tasks which are done are not copied.
+	SkipDoneTasks bool `json:"skip_done_tasks"`
+	// If true, task attachments and their files are not copied.
+	SkipAttachments bool `json:"skip_attachments"`
+	// If true, task comments are not copied.
+	SkipComments bool `json:"skip_comments"`
+	// If true, user and team shares as well as link shares are not copied.
+	SkipShares bool `json:"skip_shares"`
+	// Moves the due, start and end dates and reminders of all copied tasks by this many days. Can be negative.
+	ShiftDatesByDays int64 `json:"shift_dates_by_days"`
+
+	// If true, the project is duplicated in a background job instead of while the request is running.
+	// Use this for large projects, the response then contains the job instead of the copied project.
+	Background bool `json:"background"`
+
+	// The copied project. Only set if the project was not duplicated in the background.
 	Project *Project `json:"duplicated_project,omitempty"`
+	// The background job which duplicates the project. Use its id to check the progress of the duplication.
+	Job *ProjectDuplicateJob `json:"job,omitempty"`
+
+	// When creating a project from a template, only the structure of the project is copied.
+	fromTemplate bool
+	// Set when the duplication runs as a background job to report progress to it
+	job *ProjectDuplicateJob
 
 	web.Rights   `json:"-"`
 	web.CRUDable `json:"-"`
@@ -56,29 +87,72 @@ func (pd *ProjectDuplicate) CanCreate(s *xorm.Session, a web.Auth) (canCreate bo
 	return parent.CanCreate(s, a)
 }
 
-// Create duplicates a project
+// Create duplicates a project or starts duplicating it in the background
 // @Summary Duplicate an existing project
-// @Description Copies the project, tasks, files, kanban data, assignees, comments, attachments, lables, relations, backgrounds, user/team rights and link shares from one project to a new one. The user needs read access in the project and write access in the parent of the new project.
+// @Description Copies the project, tasks, files, kanban data, assignees, comments, attachments, lables, relations, backgrounds, user/team rights, link shares, icons, subscriptions and the saved filters of the user which are scoped to the project from one project to a new one. Tasks, done tasks, attachments, comments and shares can be left out with the skip options. The user needs read access in the project and write access in the parent of the new project. If background is set, the project is copied in a background job instead and its progress can be checked with the id of the returned job.
 // @tags project
 // @Accept json
 // @Produce json
 // @Security JWTKeyAuth
 // @Param projectID path int true "The project ID to duplicate"
 // @Param project body models.ProjectDuplicate true "The target parent project which should hold the copied project."
-// @Success 201 {object} models.ProjectDuplicate "The created project."
+// @Success 201 {object} models.ProjectDuplicate "The created project or the started duplication job."
 // @Failure 400 {object} web.HTTPError "Invalid project duplicate object provided."
 // @Failure 403 {object} web.HTTPError "The user does not have access to the project or its parent."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /projects/{projectID}/duplicate [put]
+func (pd *ProjectDuplicate) Create(s *xorm.Session, doer web.Auth) (err error) {
+	if !pd.Background {
+		return pd.duplicate(s, doer)
+	}
+
+	if _, is := doer.(*LinkSharing); is {
+		return ErrGenericForbidden{}
+	}
+
+	u, err := user.GetFromAuth(doer)
+	if err != nil {
+		return err
+	}
+
+	pd.Job, err = createProjectDuplicateJob(pd.ProjectID, u.ID)
+	if err != nil {
+		return err
+	}
+
+	err = events.Dispatch(&ProjectDuplicationRequestedEvent{
+		Job:       pd.Job,
+		Duplicate: pd,
+		Doer:      u,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The project is only duplicated in the background
+	pd.Project = nil
+	return nil
+}
+
+// duplicate does the actual work of copying a project, the project to copy needs to be loaded already.
 //
 //nolint:gocyclo
-func (pd *ProjectDuplicate) Create(s *xorm.Session, doer web.Auth) (err error) {
+func (pd *ProjectDuplicate) duplicate(s *xorm.Session, doer web.Auth) (err error) {
 
 	log.Debugf("Duplicating project %d", pd.ProjectID)
 
+	err = pd.reportProgress(ProjectDuplicateStepProject)
+	if err != nil {
+		return
+	}
+
 	pd.Project.ID = 0
 	pd.Project.Identifier = "" // Reset the identifier to trigger regenerating a new one
+	pd.Project.IsPublic = false
+	pd.Project.IsFrozen = false
 	pd.Project.ParentProjectID = pd.ParentProjectID
+	// The icon file is copied later, the new project must not share it with the old one
+	iconFileID := pd.Project.IconFileID
 	// Set the owner to the current user
 	pd.Project.OwnerID = doer.GetID()
 	err = CreateProject(s, pd.Project, doer, false, false)
@@ -93,26 +167,86 @@ func (pd *ProjectDuplicate) Create(s *xorm.Session, doer web.Auth) (err error) {
 
 	log.Debugf("Duplicated project %d into new project %d", pd.ProjectID, pd.Project.ID)
 
-	newTaskIDs, err := duplicateTasks(s, doer, pd)
+	err = pd.reportProgress(ProjectDuplicateStepTasks)
 	if err != nil {
 		return
 	}
 
-	log.Debugf("Duplicated all tasks from project %d into %d", pd.ProjectID, pd.Project.ID)
+	newTaskIDs := make(map[int64]int64)
+	if !pd.SkipTasks {
+		newTaskIDs, err = duplicateTasks(s, doer, pd)
+		if err != nil {
+			return
+		}
 
-	err = duplicateViews(s, pd, doer, newTaskIDs)
+		log.Debugf("Duplicated all tasks from project %d into %d", pd.ProjectID, pd.Project.ID)
+	}
+
+	err = pd.reportProgress(ProjectDuplicateStepViews)
+	if err != nil {
+		return
+	}
+
+	err = duplicateViews(s, pd.ProjectID, pd.Project.ID, doer, newTaskIDs)
 	if err != nil {
 		return
 	}
 
 	log.Debugf("Duplicated all views, buckets and positions from project %d into %d", pd.ProjectID, pd.Project.ID)
 
+	err = pd.reportProgress(ProjectDuplicateStepSavedFilters)
+	if err != nil {
+		return
+	}
+
+	err = duplicateSavedFilters(s, pd, doer)
+	if err != nil {
+		return
+	}
+
+	err = pd.reportProgress(ProjectDuplicateStepBackground)
+	if err != nil {
+		return
+	}
+
 	err = duplicateProjectBackground(s, pd, doer)
 	if err != nil {
 		return
 	}
 
-	// Rights / Shares
+	err = duplicateProjectIcon(s, pd, iconFileID, doer)
+	if err != nil {
+		return
+	}
+
+	if !pd.SkipShares {
+		err = pd.reportProgress(ProjectDuplicateStepShares)
+		if err != nil {
+			return
+		}
+
+		err = duplicateShares(s, pd)
+		if err != nil {
+			return
+		}
+	}
+
+	// Subscriptions are copied after the shares because only users with access to the new project keep them
+	err = pd.reportProgress(ProjectDuplicateStepSubscriptions)
+	if err != nil {
+		return
+	}
+
+	err = duplicateSubscriptions(s, pd, newTaskIDs)
+	if err != nil {
+		return
+	}
+
+	err = pd.Project.ReadOne(s, doer)
+	return
+}
+
+func duplicateShares(s *xorm.Session, pd *ProjectDuplicate) (err error) {
 	// To keep it simple(r) we will only copy rights which are directly used with the project, not the parent
 	users := []*ProjectUser{}
 	err = s.Where("project_id = ?", pd.ProjectID).Find(&users)
@@ -159,14 +293,14 @@ func (pd *ProjectDuplicate) Create(s *xorm.Session, doer web.Auth) (err error) {
 
 	log.Debugf("Duplicated all link shares from project %d into %d", pd.ProjectID, pd.Project.ID)
 
-	err = pd.Project.ReadOne(s, doer)
 	return
 }
 
-func duplicateViews(s *xorm.Session, pd *ProjectDuplicate, doer web.Auth, taskMap map[int64]int64) (err error) {
-	// Duplicate Views
+// duplicateViews copies all views of a project including their buckets and task positions. The project id can
+// also be the pseudo project id of a saved filter.
+func duplicateViews(s *xorm.Session, oldProjectID, newProjectID int64, doer web.Auth, taskMap map[int64]int64) (err error) {
 	views := make(map[int64]*ProjectView)
-	err = s.Where("project_id = ?", pd.ProjectID).Find(&views)
+	err = s.Where("project_id = ?", oldProjectID).Find(&views)
 	if err != nil {
 		return
 	}
@@ -178,8 +312,9 @@ func duplicateViews(s *xorm.Session, pd *ProjectDuplicate, doer web.Auth, taskMa
 		oldViewIDs = append(oldViewIDs, oldID)
 
 		view.ID = 0
-		view.ProjectID = pd.Project.ID
-		err = view.Create(s, doer)
+		view.ProjectID = newProjectID
+		// The buckets of the view are copied below, no need to create a new backlog bucket
+		err = createProjectView(s, view, doer, false)
 		if err != nil {
 			return
 		}
@@ -203,7 +338,8 @@ func duplicateViews(s *xorm.Session, pd *ProjectDuplicate, doer web.Auth, taskMa
 		oldBucketIDs = append(oldBucketIDs, oldID)
 
 		b.ID = 0
-		b.ProjectID = pd.Project.ID
+		b.ProjectID = newProjectID
+		b.ProjectViewID = viewMap[b.ProjectViewID]
 
 		err = b.Create(s, doer)
 		if err != nil {
@@ -213,6 +349,23 @@ func duplicateViews(s *xorm.Session, pd *ProjectDuplicate, doer web.Auth, taskMa
 		bucketMap[oldID] = b.ID
 	}
 
+	// The default and done bucket of the copied views still point to the buckets of the old views
+	for _, view := range views {
+		if view.DefaultBucketID == 0 && view.DoneBucketID == 0 {
+			continue
+		}
+
+		view.DefaultBucketID = bucketMap[view.DefaultBucketID]
+		view.DoneBucketID = bucketMap[view.DoneBucketID]
+		_, err = s.
+			Where("id = ?", view.ID).
+			Cols("default_bucket_id", "done_bucket_id").
+			Update(view)
+		if err != nil {
+			return
+		}
+	}
+
 	oldTaskBuckets := []*TaskBucket{}
 	err = s.In("bucket_id", oldBucketIDs).Find(&oldTaskBuckets)
 	if err != nil {
@@ -221,9 +374,15 @@ func duplicateViews(s *xorm.Session, pd *ProjectDuplicate, doer web.Auth, taskMa
 
 	taskBuckets := []*TaskBucket{}
 	for _, tb := range oldTaskBuckets {
+		// Not all tasks are copied when duplicating with skip options
+		taskID, exists := taskMap[tb.TaskID]
+		if !exists {
+			continue
+		}
 		taskBuckets = append(taskBuckets, &TaskBucket{
-			BucketID: bucketMap[tb.BucketID],
-			TaskID:   taskMap[tb.TaskID],
+			BucketID:      bucketMap[tb.BucketID],
+			TaskID:        taskID,
+			ProjectViewID: viewMap[tb.ProjectViewID],
 		})
 	}
 
@@ -242,16 +401,149 @@ func duplicateViews(s *xorm.Session, pd *ProjectDuplicate, doer web.Auth, taskMa
 
 	taskPositions := []*TaskPosition{}
 	for _, tp := range oldTaskPositions {
+		taskID, exists := taskMap[tp.TaskID]
+		if !exists {
+			continue
+		}
 		taskPositions = append(taskPositions, &TaskPosition{
 			ProjectViewID: viewMap[tp.ProjectViewID],
-			TaskID:        taskMap[tp.TaskID],
+			TaskID:        taskID,
 			Position:      tp.Position,
 		})
 	}
 
 	if len(taskPositions) > 0 {
 		_, err = s.Insert(&taskPositions)
+		if err != nil {
+			return
+		}
+	}
+
+	oldSwimlanePositions := []*TaskSwimlanePosition{}
+	err = s.In("project_view_id", oldViewIDs).Find(&oldSwimlanePositions)
+	if err != nil {
+		return
+	}
+
+	swimlanePositions := []*TaskSwimlanePosition{}
+	for _, sp := range oldSwimlanePositions {
+		taskID, exists := taskMap[sp.TaskID]
+		if !exists {
+			continue
+		}
+		swimlanePositions = append(swimlanePositions, &TaskSwimlanePosition{
+			TaskID:        taskID,
+			ProjectViewID: viewMap[sp.ProjectViewID],
+			SwimlaneID:    sp.SwimlaneID,
+			Position:      sp.Position,
+		})
+	}
+
+	if len(swimlanePositions) > 0 {
+		_, err = s.Insert(&swimlanePositions)
+	}
+	return
+}
+
+// projectScopedFilter matches filter conditions like "project = 1" or "project_id in 1" which limit a saved
+// filter to the given project.
+func projectScopedFilter(projectID int64) *regexp.Regexp {
+	return regexp.MustCompile(`\b(project(?:_id)?\s*(?:=|in)\s*)` + strconv.FormatInt(projectID, 10) + `\b`)
+}
+
+// duplicateSavedFilters copies all saved filters of the doer which are scoped to the duplicated project.
+// The copies are scoped to the new project instead.
+func duplicateSavedFilters(s *xorm.Session, pd *ProjectDuplicate, doer web.Auth) (err error) {
+	filters := []*SavedFilter{}
+	err = s.Where("owner_id = ?", doer.GetID()).Find(&filters)
+	if err != nil {
+		return
+	}
+
+	scope := projectScopedFilter(pd.ProjectID)
+	for _, sf := range filters {
+		if sf.Filters == nil || !scope.MatchString(sf.Filters.Filter) {
+			continue
+		}
+
+		oldFilterProjectID := getProjectIDFromSavedFilterID(sf.ID)
+
+		sf.ID = 0
+		sf.IsFavorite = false
+		sf.Filters.Filter = scope.ReplaceAllString(sf.Filters.Filter, "${1}"+strconv.FormatInt(pd.Project.ID, 10))
+		// Not using sf.Create here because that would create the default views, the existing ones are copied instead
+		_, err = s.Insert(sf)
+		if err != nil {
+			return
+		}
+
+		err = duplicateViews(s, oldFilterProjectID, getProjectIDFromSavedFilterID(sf.ID), doer, nil)
+		if err != nil {
+			return
+		}
+	}
+
+	log.Debugf("Duplicated all saved filters of project %d into %d", pd.ProjectID, pd.Project.ID)
+
+	return
+}
+
+// duplicateSubscriptions copies the subscriptions to the project and its tasks for all users who can access
+// the new project. Users without access would otherwise get notified about a project they can't see.
+func duplicateSubscriptions(s *xorm.Session, pd *ProjectDuplicate, newTaskIDs map[int64]int64) (err error) {
+	oldTaskIDs := make([]int64, 0, len(newTaskIDs))
+	for oldID := range newTaskIDs {
+		oldTaskIDs = append(oldTaskIDs, oldID)
+	}
+
+	var cond builder.Cond = builder.Eq{
+		"entity_type": SubscriptionEntityProject,
+		"entity_id":   pd.ProjectID,
+	}
+	if len(oldTaskIDs) > 0 {
+		cond = builder.Or(
+			cond,
+			builder.And(
+				builder.Eq{"entity_type": SubscriptionEntityTask},
+				builder.In("entity_id", oldTaskIDs),
+			),
+		)
 	}
+
+	subscriptions := []*Subscription{}
+	err = s.Where(cond).Find(&subscriptions)
+	if err != nil {
+		return
+	}
+
+	canRead := make(map[int64]bool)
+	for _, sb := range subscriptions {
+		can, checked := canRead[sb.UserID]
+		if !checked {
+			p := &Project{ID: pd.Project.ID}
+			can, _, err = p.CanRead(s, &user.User{ID: sb.UserID})
+			if err != nil {
+				return
+			}
+			canRead[sb.UserID] = can
+		}
+		if !can {
+			continue
+		}
+
+		sb.ID = 0
+		if sb.EntityType == SubscriptionEntityProject {
+			sb.EntityID = pd.Project.ID
+		} else {
+			sb.EntityID = newTaskIDs[sb.EntityID]
+		}
+		if _, err = s.Insert(sb); err != nil {
+			return
+		}
+	}
+
+	log.Debugf("Duplicated all subscriptions from project %d into %d", pd.ProjectID, pd.Project.ID)
+
 	return
 }
 
@@ -271,12 +563,8 @@ func duplicateProjectBackground(s *xorm.Session, pd *ProjectDuplicate, doer web.
 	if err != nil {
 		return err
 	}
-	if err := f.LoadFileByID(); err != nil {
-		return err
-	}
-	defer f.File.Close()
 
-	file, err := files.Create(f.File, f.Name, f.Size, doer)
+	file, err := f.Copy(s, doer)
 	if err != nil {
 		return err
 	}
@@ -303,6 +591,36 @@ func duplicateProjectBackground(s *xorm.Session, pd *ProjectDuplicate, doer web.
 	return
 }
 
+func duplicateProjectIcon(s *xorm.Session, pd *ProjectDuplicate, iconFileID int64, doer web.Auth) (err error) {
+	if iconFileID == 0 {
+		return
+	}
+
+	f := &files.File{ID: iconFileID}
+	err = f.LoadFileMetaByID()
+	if err != nil && files.IsErrFileDoesNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	file, err := f.Copy(s, doer)
+	if err != nil {
+		return err
+	}
+
+	pd.Project.IconFileID = file.ID
+	err = setProjectIconFile(s, pd.Project.ID, file.ID)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Duplicated project icon from project %d into %d", pd.ProjectID, pd.Project.ID)
+
+	return
+}
+
 func duplicateTasks(s *xorm.Session, doer web.Auth, ld *ProjectDuplicate) (newTaskIDs map[int64]int64, err error) {
 	// Get all tasks + all task details
 	tasks, _, _, err := getTasksForProjects(s, []*Project{{ID: ld.ProjectID}}, doer, &taskSearchOptions{}, nil)
@@ -310,10 +628,35 @@ func duplicateTasks(s *xorm.Session, doer web.Auth, ld *ProjectDuplicate) (newTa
 		return nil, err
 	}
 
+	if ld.SkipDoneTasks {
+		undone := make([]*Task, 0, len(tasks))
+		for _, t := range tasks {
+			if !t.Done {
+				undone = append(undone, t)
+			}
+		}
+		tasks = undone
+	}
+
 	if len(tasks) == 0 {
 		return
 	}
 
+	shift := time.Duration(ld.ShiftDatesByDays) * 24 * time.Hour
+	for _, t := range tasks {
+		if ld.fromTemplate {
+			t.stripForTemplate()
+		}
+		if shift != 0 {
+			t.shiftDates(shift)
+			for _, r := range t.Reminders {
+				if !r.isRelative() {
+					r.Reminder = r.Reminder.Add(shift)
+				}
+			}
+		}
+	}
+
 	// This map contains the old task id as key and the new duplicated task id as value.
 	// It is used to map old task items to new ones.
 	newTaskIDs = make(map[int64]int64, len(tasks))
@@ -324,7 +667,7 @@ func duplicateTasks(s *xorm.Session, doer web.Auth, ld *ProjectDuplicate) (newTa
 		t.ID = 0
 		t.ProjectID = ld.Project.ID
 		t.UID = ""
-		err = createTask(s, t, doer, false, false)
+		err = createTask(s, t, doer, false, false, false)
 		if err != nil {
 			return nil, err
 		}
@@ -334,42 +677,110 @@ func duplicateTasks(s *xorm.Session, doer web.Auth, ld *ProjectDuplicate) (newTa
 
 	log.Debugf("Duplicated all tasks from project %d into %d", ld.ProjectID, ld.Project.ID)
 
+	if !ld.SkipAttachments {
+		err = duplicateAttachments(s, doer, ld, newTaskIDs, oldTaskIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Copy label tasks (not the labels)
+	labelTasks := []*LabelTask{}
+	err = s.In("task_id", oldTaskIDs).Find(&labelTasks)
+	if err != nil {
+		return
+	}
+
+	for _, lt := range labelTasks {
+		lt.ID = 0
+		lt.TaskID = newTaskIDs[lt.TaskID]
+		if _, err := s.Insert(lt); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Debugf("Duplicated all labels from project %d into %d", ld.ProjectID, ld.Project.ID)
+
+	if !ld.fromTemplate {
+		err = duplicateAssignees(s, doer, ld, newTaskIDs, oldTaskIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !ld.SkipComments {
+		err = duplicateComments(s, ld, newTaskIDs, oldTaskIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Relations in that project
+	// Low-Effort: Only copy those relations which are between tasks in the same project
+	// because we can do that without a lot of hassle
+	relations := []*TaskRelation{}
+	err = s.In("task_id", oldTaskIDs).Find(&relations)
+	if err != nil {
+		return
+	}
+	for _, r := range relations {
+		otherTaskID, exists := newTaskIDs[r.OtherTaskID]
+		if !exists {
+			continue
+		}
+		r.ID = 0
+		r.OtherTaskID = otherTaskID
+		r.TaskID = newTaskIDs[r.TaskID]
+		if _, err := s.Insert(r); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Debugf("Duplicated all task relations from project %d into %d", ld.ProjectID, ld.Project.ID)
+
+	return
+}
+
+func duplicateAttachments(s *xorm.Session, doer web.Auth, ld *ProjectDuplicate, newTaskIDs map[int64]int64, oldTaskIDs []int64) (err error) {
 	// Save all attachments
-	// We also duplicate all underlying files since they could be modified in one project which would result in
-	// file changes in the other project which is not something we want.
+	// The copied files share their content with the original ones, only the metadata is duplicated. Files are never
+	// modified in place, a new version of an attachment is a new file.
 	attachments, err := getTaskAttachmentsByTaskIDs(s, oldTaskIDs)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	for _, attachment := range attachments {
 		oldAttachmentID := attachment.ID
 		attachment.ID = 0
+		// Comments get new ids when they are copied, the copied files are attached to the task directly.
+		attachment.CommentID = 0
 		var exists bool
 		attachment.TaskID, exists = newTaskIDs[attachment.TaskID]
 		if !exists {
 			log.Debugf("Error duplicating attachment %d from old task %d to new task: Old task <-> new task does not seem to exist.", oldAttachmentID, attachment.TaskID)
 			continue
 		}
+		if attachment.IsLink() {
+			attachment.CreatedBy = nil
+			err = attachment.Create(s, doer)
+			if err != nil {
+				return err
+			}
+			continue
+		}
 		attachment.File = &files.File{ID: attachment.FileID}
 		if err := attachment.File.LoadFileMetaByID(); err != nil {
 			if files.IsErrFileDoesNotExist(err) {
 				log.Debugf("Not duplicating attachment %d (file %d) because it does not exist from project %d into %d", oldAttachmentID, attachment.FileID, ld.ProjectID, ld.Project.ID)
 				continue
 			}
-			return nil, err
-		}
-		if err := attachment.File.LoadFileByID(); err != nil {
-			return nil, err
+			return err
 		}
 
-		err := attachment.NewAttachment(s, attachment.File.File, attachment.File.Name, attachment.File.Size, doer)
+		err := attachment.newAttachmentFromFile(s, attachment.File, doer)
 		if err != nil {
-			return nil, err
-		}
-
-		if attachment.File.File != nil {
-			_ = attachment.File.File.Close()
+			return err
 		}
 
 		log.Debugf("Duplicated attachment %d into %d from project %d into %d", oldAttachmentID, attachment.ID, ld.ProjectID, ld.Project.ID)
@@ -377,23 +788,10 @@ func duplicateTasks(s *xorm.Session, doer web.Auth, ld *ProjectDuplicate) (newTa
 
 	log.Debugf("Duplicated all attachments from project %d into %d", ld.ProjectID, ld.Project.ID)
 
-	// Copy label tasks (not the labels)
-	labelTasks := []*LabelTask{}
-	err = s.In("task_id", oldTaskIDs).Find(&labelTasks)
-	if err != nil {
-		return
-	}
-
-	for _, lt := range labelTasks {
-		lt.ID = 0
-		lt.TaskID = newTaskIDs[lt.TaskID]
-		if _, err := s.Insert(lt); err != nil {
-			return nil, err
-		}
-	}
-
-	log.Debugf("Duplicated all labels from project %d into %d", ld.ProjectID, ld.Project.ID)
+	return
+}
 
+func duplicateAssignees(s *xorm.Session, doer web.Auth, ld *ProjectDuplicate, newTaskIDs map[int64]int64, oldTaskIDs []int64) (err error) {
 	// Assignees
 	// Only copy those assignees who have access to the task
 	assignees := []*TaskAssginee{}
@@ -410,50 +808,39 @@ func duplicateTasks(s *xorm.Session, doer web.Auth, ld *ProjectDuplicate) (newTa
 			if IsErrUserDoesNotHaveAccessToProject(err) {
 				continue
 			}
-			return nil, err
+			return err
 		}
 	}
 
 	log.Debugf("Duplicated all assignees from project %d into %d", ld.ProjectID, ld.Project.ID)
 
+	return
+}
+
+func duplicateComments(s *xorm.Session, ld *ProjectDuplicate, newTaskIDs map[int64]int64, oldTaskIDs []int64) (err error) {
 	// Comments
 	comments := []*TaskComment{}
-	err = s.In("task_id", oldTaskIDs).Find(&comments)
+	err = s.In("task_id", oldTaskIDs).OrderBy("id asc").Find(&comments)
 	if err != nil {
 		return
 	}
+	// Replies always have a higher id than the comment they reply to, which means the new id of the parent
+	// is already known when the reply is copied.
+	newCommentIDs := make(map[int64]int64, len(comments))
 	for _, c := range comments {
+		oldID := c.ID
 		c.ID = 0
 		c.TaskID = newTaskIDs[c.TaskID]
+		if c.ParentCommentID != 0 {
+			c.ParentCommentID = newCommentIDs[c.ParentCommentID]
+		}
 		if _, err := s.Insert(c); err != nil {
-			return nil, err
+			return err
 		}
+		newCommentIDs[oldID] = c.ID
 	}
 
 	log.Debugf("Duplicated all comments from project %d into %d", ld.ProjectID, ld.Project.ID)
 
-	// Relations in that project
-	// Low-Effort: Only copy those relations which are between tasks in the same project
-	// because we can do that without a lot of hassle
-	relations := []*TaskRelation{}
-	err = s.In("task_id", oldTaskIDs).Find(&relations)
-	if err != nil {
-		return
-	}
-	for _, r := range relations {
-		otherTaskID, exists := newTaskIDs[r.OtherTaskID]
-		if !exists {
-			continue
-		}
-		r.ID = 0
-		r.OtherTaskID = otherTaskID
-		r.TaskID = newTaskIDs[r.TaskID]
-		if _, err := s.Insert(r); err != nil {
-			return nil, err
-		}
-	}
-
-	log.Debugf("Duplicated all task relations from project %d into %d", ld.ProjectID, ld.Project.ID)
-
 	return
 }
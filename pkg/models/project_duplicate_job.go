@@ -0,0 +1,229 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/modules/keyvalue"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/api/pkg/utils"
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// ProjectDuplicateJobStatus holds the state of a project duplication job
+type ProjectDuplicateJobStatus string
+
+const (
+	ProjectDuplicateJobStatusPending ProjectDuplicateJobStatus = "pending"
+	ProjectDuplicateJobStatusRunning ProjectDuplicateJobStatus = "running"
+	ProjectDuplicateJobStatusDone    ProjectDuplicateJobStatus = "done"
+	ProjectDuplicateJobStatusFailed  ProjectDuplicateJobStatus = "failed"
+)
+
+// ProjectDuplicateStep is one of the steps a project duplication goes through
+type ProjectDuplicateStep string
+
+const (
+	ProjectDuplicateStepProject       ProjectDuplicateStep = "project"
+	ProjectDuplicateStepTasks         ProjectDuplicateStep = "tasks"
+	ProjectDuplicateStepViews         ProjectDuplicateStep = "views"
+	ProjectDuplicateStepSavedFilters  ProjectDuplicateStep = "saved_filters"
+	ProjectDuplicateStepBackground    ProjectDuplicateStep = "background"
+	ProjectDuplicateStepShares        ProjectDuplicateStep = "shares"
+	ProjectDuplicateStepSubscriptions ProjectDuplicateStep = "subscriptions"
+)
+
+var projectDuplicateSteps = []ProjectDuplicateStep{
+	ProjectDuplicateStepProject,
+	ProjectDuplicateStepTasks,
+	ProjectDuplicateStepViews,
+	ProjectDuplicateStepSavedFilters,
+	ProjectDuplicateStepBackground,
+	ProjectDuplicateStepShares,
+	ProjectDuplicateStepSubscriptions,
+}
+
+// ProjectDuplicateJob holds the progress of a project duplication running in the background
+type ProjectDuplicateJob struct {
+	// The unique id of this job.
+	ID string `json:"id" param:"job"`
+	// The id of the project which is duplicated.
+	ProjectID int64 `json:"project_id"`
+	// The id of the new project. Only set once the job is done.
+	DuplicatedProjectID int64 `json:"duplicated_project_id"`
+	// The state of the job. Can be "pending", "running", "done" or "failed".
+	Status ProjectDuplicateJobStatus `json:"status"`
+	// The step the job is currently working on.
+	Step ProjectDuplicateStep `json:"step"`
+	// How many of the steps are already finished.
+	StepsDone int `json:"steps_done"`
+	// How many steps the job has in total.
+	StepsTotal int `json:"steps_total"`
+	// If the job failed, this holds the reason.
+	Error string `json:"error,omitempty"`
+
+	// The id of the user who started the job. Only they can see its progress.
+	CreatedByID int64 `json:"created_by_id"`
+
+	// A timestamp when this job was created.
+	Created time.Time `json:"created"`
+	// A timestamp when this job was last updated.
+	Updated time.Time `json:"updated"`
+
+	web.CRUDable `json:"-"`
+	web.Rights   `json:"-"`
+}
+
+func projectDuplicateJobKey(id string) string {
+	return "project_duplicate_job_" + id
+}
+
+func createProjectDuplicateJob(projectID, createdByID int64) (job *ProjectDuplicateJob, err error) {
+	job = &ProjectDuplicateJob{
+		ID:          utils.MakeRandomString(32),
+		ProjectID:   projectID,
+		Status:      ProjectDuplicateJobStatusPending,
+		StepsTotal:  len(projectDuplicateSteps),
+		CreatedByID: createdByID,
+		Created:     time.Now(),
+	}
+	return job, job.save()
+}
+
+func getProjectDuplicateJob(id string) (job *ProjectDuplicateJob, err error) {
+	job = &ProjectDuplicateJob{}
+	exists, err := keyvalue.GetWithValue(projectDuplicateJobKey(id), job)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrProjectDuplicateJobDoesNotExist{JobID: id}
+	}
+	return job, nil
+}
+
+func (job *ProjectDuplicateJob) save() error {
+	job.Updated = time.Now()
+	return keyvalue.Put(projectDuplicateJobKey(job.ID), job)
+}
+
+func (pd *ProjectDuplicate) reportProgress(step ProjectDuplicateStep) error {
+	if pd.job == nil {
+		return nil
+	}
+
+	pd.job.Step = step
+	for i, s := range projectDuplicateSteps {
+		if s == step {
+			pd.job.StepsDone = i
+			break
+		}
+	}
+	return pd.job.save()
+}
+
+// runProjectDuplicateJob duplicates a project in the background and keeps track of the progress in the job.
+func runProjectDuplicateJob(job *ProjectDuplicateJob, pd *ProjectDuplicate, doer *user.User) (err error) {
+	pd.ProjectID = job.ProjectID
+	pd.job = job
+
+	job.Status = ProjectDuplicateJobStatusRunning
+	err = job.save()
+	if err != nil {
+		return err
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	err = func() error {
+		err := s.Begin()
+		if err != nil {
+			return err
+		}
+
+		// The rights might have changed since the job was started
+		can, err := pd.CanCreate(s, doer)
+		if err != nil {
+			return err
+		}
+		if !can {
+			return ErrGenericForbidden{}
+		}
+
+		err = pd.duplicate(s, doer)
+		if err != nil {
+			return err
+		}
+
+		return s.Commit()
+	}()
+	if err != nil {
+		_ = s.Rollback()
+		log.Errorf("Could not duplicate project %d: %s", job.ProjectID, err)
+
+		job.Status = ProjectDuplicateJobStatusFailed
+		job.Error = err.Error()
+		return job.save()
+	}
+
+	job.Status = ProjectDuplicateJobStatusDone
+	job.StepsDone = job.StepsTotal
+	job.DuplicatedProjectID = pd.Project.ID
+	return job.save()
+}
+
+// CanRead checks if a user can see the progress of a project duplication job
+func (job *ProjectDuplicateJob) CanRead(_ *xorm.Session, a web.Auth) (bool, int, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, 0, nil
+	}
+
+	j, err := getProjectDuplicateJob(job.ID)
+	if err != nil {
+		return false, 0, err
+	}
+
+	return j.CreatedByID == a.GetID(), int(RightRead), nil
+}
+
+// ReadOne returns the progress of a project duplication job
+// @Summary Get the progress of a project duplication
+// @Description Returns the state of a project duplication job started by the current user, including the step it is currently working on.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param job path string true "The id of the duplication job"
+// @Success 200 {object} models.ProjectDuplicateJob "The duplication job."
+// @Failure 403 {object} web.HTTPError "The user did not start this job."
+// @Failure 404 {object} web.HTTPError "The job does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/duplicate-jobs/{job} [get]
+func (job *ProjectDuplicateJob) ReadOne(_ *xorm.Session, _ web.Auth) error {
+	j, err := getProjectDuplicateJob(job.ID)
+	if err != nil {
+		return err
+	}
+
+	*job = *j
+	return nil
+}
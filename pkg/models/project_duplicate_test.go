@@ -17,14 +17,17 @@
 package models
 
 import (
+	"strconv"
 	"testing"
 
 	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
 	"code.vikunja.io/api/pkg/files"
 	"code.vikunja.io/api/pkg/user"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"xorm.io/builder"
 )
 
 func TestProjectDuplicate(t *testing.T) {
@@ -46,6 +49,8 @@ func TestProjectDuplicate(t *testing.T) {
 	assert.True(t, can)
 	err = l.Create(s, u)
 	require.NoError(t, err)
+	require.NotNil(t, l.Project)
+	assert.Nil(t, l.Job)
 
 	// assert the new project has the same number of buckets as the old one
 	numberOfOriginalViews, err := s.Where("project_id = ?", l.ProjectID).Count(&ProjectView{})
@@ -57,3 +62,241 @@ func TestProjectDuplicate(t *testing.T) {
 	// To make this test 100% useful, it would need to assert a lot more stuff, but it is good enough for now.
 	// Also, we're lacking utility functions to do all needed assertions.
 }
+
+func TestProjectDuplicate_Options(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	duplicate := func(t *testing.T, pd *ProjectDuplicate) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		can, err := pd.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = pd.duplicate(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+	}
+
+	t.Run("skip tasks", func(t *testing.T) {
+		pd := &ProjectDuplicate{ProjectID: 1, SkipTasks: true}
+		duplicate(t, pd)
+
+		db.AssertCount(t, "tasks", builder.Eq{"project_id": pd.Project.ID}, 0)
+		db.AssertMissing(t, "task_buckets", map[string]interface{}{
+			"task_id": 0,
+		})
+	})
+	t.Run("skip done tasks", func(t *testing.T) {
+		pd := &ProjectDuplicate{ProjectID: 1, SkipDoneTasks: true}
+		duplicate(t, pd)
+
+		db.AssertMissing(t, "tasks", map[string]interface{}{
+			"project_id": pd.Project.ID,
+			"done":       true,
+		})
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"project_id": pd.Project.ID,
+			"title":      "task #1",
+		}, false)
+	})
+	t.Run("skip attachments, comments and shares", func(t *testing.T) {
+		pd := &ProjectDuplicate{
+			ProjectID:       1,
+			SkipAttachments: true,
+			SkipComments:    true,
+			SkipShares:      true,
+		}
+		duplicate(t, pd)
+
+		newTasks := builder.Select("id").From("tasks").Where(builder.Eq{"project_id": pd.Project.ID})
+		db.AssertCount(t, "task_attachments", builder.In("task_id", newTasks), 0)
+		db.AssertCount(t, "task_comments", builder.In("task_id", newTasks), 0)
+		db.AssertMissing(t, "link_shares", map[string]interface{}{
+			"project_id": pd.Project.ID,
+		})
+	})
+	t.Run("view configuration", func(t *testing.T) {
+		pd := &ProjectDuplicate{ProjectID: 1}
+		duplicate(t, pd)
+
+		s := db.NewSession()
+		defer s.Close()
+
+		oldBuckets, err := s.Where("project_view_id = ?", 4).Count(&Bucket{})
+		require.NoError(t, err)
+		kanban := &ProjectView{}
+		has, err := s.Where("project_id = ? AND view_kind = ?", pd.Project.ID, ProjectViewKindKanban).Get(kanban)
+		require.NoError(t, err)
+		require.True(t, has)
+		newBuckets, err := s.Where("project_view_id = ?", kanban.ID).Count(&Bucket{})
+		require.NoError(t, err)
+		assert.Equal(t, oldBuckets, newBuckets)
+		if kanban.DefaultBucketID != 0 {
+			db.AssertExists(t, "buckets", map[string]interface{}{
+				"id":              kanban.DefaultBucketID,
+				"project_view_id": kanban.ID,
+			}, false)
+		}
+		newTasks := builder.Select("id").From("tasks").Where(builder.Eq{"project_id": pd.Project.ID})
+		db.AssertCount(t, "task_buckets", builder.And(builder.Eq{"project_view_id": 4}, builder.In("task_id", newTasks)), 0)
+	})
+	t.Run("saved filters", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		sf := &SavedFilter{
+			Title:   "project 1 undone",
+			Filters: &TaskCollection{Filter: "done = false && project = 1"},
+		}
+		err := sf.Create(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		s.Close()
+
+		pd := &ProjectDuplicate{ProjectID: 1}
+		duplicate(t, pd)
+
+		s = db.NewSession()
+		defer s.Close()
+		filters := []*SavedFilter{}
+		err = s.Where("title = ?", "project 1 undone").OrderBy("id asc").Find(&filters)
+		require.NoError(t, err)
+		require.Len(t, filters, 2)
+		assert.Equal(t, "done = false && project = "+strconv.FormatInt(pd.Project.ID, 10), filters[1].Filters.Filter)
+		db.AssertCount(t, "project_views", builder.Eq{"project_id": getProjectIDFromSavedFilterID(filters[1].ID)}, 4)
+
+		// Filters which are not scoped to the project are not copied
+		db.AssertCount(t, "saved_filters", builder.Eq{"title": "testfilter1"}, 1)
+	})
+	t.Run("subscriptions", func(t *testing.T) {
+		pd := &ProjectDuplicate{ProjectID: 1}
+		duplicate(t, pd)
+
+		s := db.NewSession()
+		defer s.Close()
+		task := &Task{}
+		has, err := s.Where("project_id = ? AND title = ?", pd.Project.ID, "task #2 done").Get(task)
+		require.NoError(t, err)
+		require.True(t, has)
+		db.AssertExists(t, "subscriptions", map[string]interface{}{
+			"entity_type": SubscriptionEntityTask,
+			"entity_id":   task.ID,
+			"user_id":     1,
+		}, false)
+	})
+	t.Run("shift dates", func(t *testing.T) {
+		pd := &ProjectDuplicate{ProjectID: 1, ShiftDatesByDays: 2}
+		duplicate(t, pd)
+
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{}
+		has, err := s.Where("project_id = ? AND title = ?", pd.Project.ID, "task #5 higher due date").Get(task)
+		require.NoError(t, err)
+		require.True(t, has)
+		assert.Equal(t, int64(1543636724+2*24*60*60), task.DueDate.Unix())
+	})
+}
+
+func TestProjectDuplicate_Job(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("starts a job", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pd := &ProjectDuplicate{ProjectID: 1, Background: true}
+		can, err := pd.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = pd.Create(s, u)
+		require.NoError(t, err)
+
+		require.NotNil(t, pd.Job)
+		assert.Nil(t, pd.Project)
+		assert.Equal(t, ProjectDuplicateJobStatusPending, pd.Job.Status)
+		events.AssertDispatched(t, &ProjectDuplicationRequestedEvent{})
+
+		job := &ProjectDuplicateJob{ID: pd.Job.ID}
+		can, _, err = job.CanRead(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		can, _, err = job.CanRead(s, &user.User{ID: 2})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("runs a job", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+
+		job, err := createProjectDuplicateJob(1, u.ID)
+		require.NoError(t, err)
+		err = runProjectDuplicateJob(job, &ProjectDuplicate{SkipTasks: true}, u)
+		require.NoError(t, err)
+
+		s := db.NewSession()
+		defer s.Close()
+
+		read := &ProjectDuplicateJob{ID: job.ID}
+		err = read.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, ProjectDuplicateJobStatusDone, read.Status)
+		assert.Equal(t, read.StepsTotal, read.StepsDone)
+		assert.NotZero(t, read.DuplicatedProjectID)
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":    read.DuplicatedProjectID,
+			"title": "Test1",
+		}, false)
+	})
+	t.Run("runs a job through the event", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+
+		job, err := createProjectDuplicateJob(1, u.ID)
+		require.NoError(t, err)
+
+		events.TestListener(t, &ProjectDuplicationRequestedEvent{
+			Job:       job,
+			Duplicate: &ProjectDuplicate{ProjectID: 1, SkipTasks: true, Background: true},
+			Doer:      &user.User{ID: u.ID, Username: "user1"},
+		}, &HandleProjectDuplication{})
+
+		s := db.NewSession()
+		defer s.Close()
+
+		read := &ProjectDuplicateJob{ID: job.ID}
+		can, _, err := read.CanRead(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = read.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, ProjectDuplicateJobStatusDone, read.Status)
+		assert.Equal(t, u.ID, read.CreatedByID)
+		assert.NotZero(t, read.DuplicatedProjectID)
+	})
+	t.Run("failed job", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		job, err := createProjectDuplicateJob(1, 2)
+		require.NoError(t, err)
+		err = runProjectDuplicateJob(job, &ProjectDuplicate{}, &user.User{ID: 2})
+		require.NoError(t, err)
+
+		assert.Equal(t, ProjectDuplicateJobStatusFailed, job.Status)
+		assert.NotEmpty(t, job.Error)
+	})
+	t.Run("nonexistent job", func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+
+		job := &ProjectDuplicateJob{ID: "doesnotexist"}
+		_, _, err := job.CanRead(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectDuplicateJobDoesNotExist(err))
+	})
+}
@@ -0,0 +1,156 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/api/pkg/utils"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// ProjectEmailIntake is the inbound email address of a project. Every mail sent to it is turned into a new task.
+type ProjectEmailIntake struct {
+	// The unique, numeric id of this intake address.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id"`
+	// The project all mails sent to this address end up in.
+	ProjectID int64 `xorm:"bigint not null unique" json:"project_id" param:"project"`
+	// The random local part of the address.
+	Hash string `xorm:"varchar(40) not null unique" json:"-"`
+	// The full email address. Send mails to this address to create tasks in the project.
+	Address string `xorm:"-" json:"address"`
+
+	// The user who created this address. All tasks created from mails will be created in the name of this user.
+	CreatedBy   *user.User `xorm:"-" json:"created_by" valid:"-"`
+	CreatedByID int64      `xorm:"bigint not null" json:"-"`
+
+	// A timestamp when this address was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this address was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName holds the table name
+func (*ProjectEmailIntake) TableName() string {
+	return "project_email_intakes"
+}
+
+func (pi *ProjectEmailIntake) setAddress() {
+	pi.Address = pi.Hash + "@" + config.EmailIntakeDomain.GetString()
+}
+
+func getProjectEmailIntakeByProjectID(s *xorm.Session, projectID int64) (intake *ProjectEmailIntake, err error) {
+	intake = &ProjectEmailIntake{}
+	exists, err := s.Where("project_id = ?", projectID).Get(intake)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrProjectEmailIntakeDoesNotExist{ProjectID: projectID}
+	}
+	return
+}
+
+func getProjectEmailIntakeByHash(s *xorm.Session, hash string) (intake *ProjectEmailIntake, exists bool, err error) {
+	intake = &ProjectEmailIntake{}
+	exists, err = s.Where("hash = ?", strings.ToLower(hash)).Get(intake)
+	return
+}
+
+// ReadOne returns the email intake address of a project
+// @Summary Get the email intake address of a project
+// @Description Returns the address mails need to be sent to in order to create tasks in this project.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Success 200 {object} models.ProjectEmailIntake "The email intake address."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The project does not have an intake address."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/email-intake [get]
+func (pi *ProjectEmailIntake) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	intake, err := getProjectEmailIntakeByProjectID(s, pi.ProjectID)
+	if err != nil {
+		return err
+	}
+	*pi = *intake
+	pi.setAddress()
+
+	pi.CreatedBy, err = user.GetUserByID(s, pi.CreatedByID)
+	return
+}
+
+// Create generates a new email intake address for a project
+// @Summary Generate an email intake address
+// @Description Generates a new random intake address for a project. If the project already has one, it is replaced and mails sent to the old address will not be accepted anymore.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Success 201 {object} models.ProjectEmailIntake "The new email intake address."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 412 {object} web.HTTPError "Email intake is not configured."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/email-intake [put]
+func (pi *ProjectEmailIntake) Create(s *xorm.Session, a web.Auth) (err error) {
+	if config.EmailIntakeDomain.GetString() == "" || config.EmailIntakeSecret.GetString() == "" {
+		return &ErrEmailIntakeNotConfigured{}
+	}
+
+	_, err = s.Where("project_id = ?", pi.ProjectID).Delete(&ProjectEmailIntake{})
+	if err != nil {
+		return err
+	}
+
+	pi.ID = 0
+	pi.Hash = strings.ToLower(utils.MakeRandomString(32))
+	pi.CreatedByID = a.GetID()
+
+	_, err = s.Insert(pi)
+	if err != nil {
+		return err
+	}
+	pi.setAddress()
+
+	pi.CreatedBy, err = user.GetUserByID(s, pi.CreatedByID)
+	return
+}
+
+// Delete removes the email intake address of a project
+// @Summary Remove the email intake address of a project
+// @Description Removes the intake address of a project. Mails sent to it afterwards will be rejected.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Success 200 {object} models.Message "The intake address was removed."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/email-intake [delete]
+func (pi *ProjectEmailIntake) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.Where("project_id = ?", pi.ProjectID).Delete(&ProjectEmailIntake{})
+	return
+}
@@ -0,0 +1,338 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"bytes"
+	"encoding/base64"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/microcosm-cc/bluemonday"
+	"xorm.io/xorm"
+)
+
+// Mails can nest multipart bodies (e.g. mixed > alternative > related), but there is no reason to go deeper than this.
+const maxIntakeMailDepth = 10
+
+type intakeMailAttachment struct {
+	Filename string
+	Content  []byte
+}
+
+type intakeMail struct {
+	From        string
+	Subject     string
+	Recipients  []*mail.Address
+	Text        string
+	HTML        string
+	Attachments []*intakeMailAttachment
+}
+
+var intakeHeaderDecoder = &mime.WordDecoder{}
+
+func decodeIntakeHeader(value string) string {
+	decoded, err := intakeHeaderDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+func parseIntakeMail(r io.Reader) (m *intakeMail, err error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, &ErrInvalidIntakeEmail{Reason: err.Error()}
+	}
+
+	m = &intakeMail{
+		Subject: strings.TrimSpace(decodeIntakeHeader(msg.Header.Get("Subject"))),
+	}
+
+	from, err := msg.Header.AddressList("From")
+	if err == nil && len(from) > 0 {
+		m.From = from[0].Address
+	}
+
+	// Mail servers usually record the envelope recipient in one of these headers which is the only way to
+	// find the intake address if the mail was sent as bcc.
+	for _, header := range []string{"To", "Cc", "Delivered-To", "X-Original-To"} {
+		addresses, err := msg.Header.AddressList(header)
+		if err != nil {
+			continue
+		}
+		m.Recipients = append(m.Recipients, addresses...)
+	}
+
+	err = m.readPart(
+		msg.Header.Get("Content-Type"),
+		msg.Header.Get("Content-Transfer-Encoding"),
+		msg.Header.Get("Content-Disposition"),
+		msg.Body,
+		0,
+	)
+	return m, err
+}
+
+func (m *intakeMail) readPart(contentType, encoding, disposition string, body io.Reader, depth int) error {
+	if depth > maxIntakeMailDepth {
+		return &ErrInvalidIntakeEmail{Reason: "too many nested parts"}
+	}
+
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "application/octet-stream"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return &ErrInvalidIntakeEmail{Reason: err.Error()}
+			}
+
+			err = m.readPart(
+				part.Header.Get("Content-Type"),
+				part.Header.Get("Content-Transfer-Encoding"),
+				part.Header.Get("Content-Disposition"),
+				part,
+				depth+1,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return &ErrInvalidIntakeEmail{Reason: err.Error()}
+	}
+
+	var filename string
+	dispositionType, dispositionParams, err := mime.ParseMediaType(disposition)
+	if err == nil {
+		filename = dispositionParams["filename"]
+	}
+	if filename == "" {
+		filename = params["name"]
+	}
+	filename = decodeIntakeHeader(filename)
+
+	if dispositionType == "attachment" || filename != "" {
+		if filename == "" {
+			filename = "attachment"
+		}
+		m.Attachments = append(m.Attachments, &intakeMailAttachment{
+			Filename: filename,
+			Content:  content,
+		})
+		return nil
+	}
+
+	switch mediaType {
+	case "text/plain":
+		if m.Text == "" {
+			m.Text = string(content)
+		}
+	case "text/html":
+		if m.HTML == "" {
+			m.HTML = string(content)
+		}
+	}
+
+	return nil
+}
+
+// intakeHashes returns the local parts of all recipients which are at the intake domain
+func (m *intakeMail) intakeHashes() (hashes []string) {
+	domain := strings.ToLower(config.EmailIntakeDomain.GetString())
+	seen := make(map[string]bool)
+	for _, recipient := range m.Recipients {
+		at := strings.LastIndex(recipient.Address, "@")
+		if at == -1 || strings.ToLower(recipient.Address[at+1:]) != domain {
+			continue
+		}
+
+		hash := strings.ToLower(recipient.Address[:at])
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		hashes = append(hashes, hash)
+	}
+	return
+}
+
+func (m *intakeMail) taskTitle() string {
+	title := m.Subject
+	if title == "" {
+		title = "Email from " + m.From
+	}
+
+	runes := []rune(title)
+	if len(runes) > 250 {
+		title = string(runes[:250])
+	}
+	return title
+}
+
+func intakeTextToHTML(text string) (converted string) {
+	paragraphs := strings.Split(strings.ReplaceAll(strings.TrimSpace(text), "\r\n", "\n"), "\n\n")
+	for _, paragraph := range paragraphs {
+		converted += "<p>" + strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>") + "</p>"
+	}
+	return
+}
+
+// taskDescription prefers the plain text body because we can't trust the html of arbitrary mails.
+// If there is only html, it is sanitized before it ends up in the task.
+func (m *intakeMail) taskDescription() string {
+	var description string
+	switch {
+	case strings.TrimSpace(m.Text) != "":
+		description = intakeTextToHTML(m.Text)
+	case m.HTML != "":
+		description = bluemonday.UGCPolicy().Sanitize(m.HTML)
+	}
+
+	if m.From != "" {
+		description = "<p>From: " + html.EscapeString(m.From) + "</p>" + description
+	}
+	return description
+}
+
+// saveAttachments adds all attachments of the mail to a task or, if commentID is set, to a comment of it.
+// Attachments larger than the configured file size limit are skipped.
+func (m *intakeMail) saveAttachments(s *xorm.Session, taskID, commentID int64, creator *user.User) (attachments []*TaskAttachment, err error) {
+	for _, attachment := range m.Attachments {
+		ta := &TaskAttachment{TaskID: taskID, CommentID: commentID}
+		err = ta.NewAttachment(
+			s,
+			io.NopCloser(bytes.NewReader(attachment.Content)),
+			attachment.Filename,
+			uint64(len(attachment.Content)),
+			creator,
+		)
+		if IsErrTaskAttachmentIsTooLarge(err) {
+			log.Warningf("Skipping attachment %s of intake mail for task %d: %s", attachment.Filename, taskID, err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, ta)
+	}
+	return
+}
+
+// ReceiveIntakeEmail turns a raw mail into a new task in every project whose intake address is one of the recipients.
+// Attachments of the mail are added to the tasks, as long as they are not larger than the configured file size limit.
+// Recipients which are reply addresses of notification mails turn the mail into a comment on the task instead.
+func ReceiveIntakeEmail(s *xorm.Session, r io.Reader) (tasks []*Task, err error) {
+	m, err := parseIntakeMail(r)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := m.intakeHashes()
+	if len(hashes) == 0 {
+		return nil, &ErrInvalidIntakeEmail{Reason: "none of the recipients is an intake address"}
+	}
+
+	var repliedToTask bool
+	for _, hash := range hashes {
+		if token, is := parseTaskCommentReplyToken(hash); is {
+			comment, err := receiveTaskCommentReply(s, m, token)
+			if err != nil {
+				return nil, err
+			}
+			repliedToTask = repliedToTask || comment != nil
+			continue
+		}
+
+		intake, exists, err := getProjectEmailIntakeByHash(s, hash)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			log.Debugf("Ignoring mail to unknown intake address %s", hash)
+			continue
+		}
+
+		creator, err := user.GetUserByID(s, intake.CreatedByID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Tasks are created in the name of whoever set up the intake address, they might have lost access since then
+		canWrite, err := (&Project{ID: intake.ProjectID}).CanWrite(s, creator)
+		if err != nil {
+			return nil, err
+		}
+		if !canWrite {
+			log.Debugf("Ignoring mail to intake address %s, its creator %d can no longer write to project %d", hash, creator.ID, intake.ProjectID)
+			continue
+		}
+
+		task := &Task{
+			Title:       m.taskTitle(),
+			Description: m.taskDescription(),
+			ProjectID:   intake.ProjectID,
+		}
+		err = createTask(s, task, creator, false, true, true)
+		if err != nil {
+			return nil, err
+		}
+
+		task.Attachments, err = m.saveAttachments(s, task.ID, 0, creator)
+		if err != nil {
+			return nil, err
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if len(tasks) == 0 && !repliedToTask {
+		return nil, &ErrInvalidIntakeEmail{Reason: "none of the recipients is an intake address"}
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,50 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if a user can see the email intake address of a project
+func (pi *ProjectEmailIntake) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	can, err := pi.canDoEmailIntake(s, a)
+	if err != nil || !can {
+		return false, 0, err
+	}
+	return true, int(RightWrite), nil
+}
+
+// CanCreate checks if a user can generate a new email intake address for a project
+func (pi *ProjectEmailIntake) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	return pi.canDoEmailIntake(s, a)
+}
+
+// CanDelete checks if a user can remove the email intake address of a project
+func (pi *ProjectEmailIntake) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return pi.canDoEmailIntake(s, a)
+}
+
+func (pi *ProjectEmailIntake) canDoEmailIntake(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	p := &Project{ID: pi.ProjectID}
+	return p.CanUpdate(s, a)
+}
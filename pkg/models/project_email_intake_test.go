@@ -0,0 +1,150 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIntakeMail = "From: Jane Doe <jane@example.com>\r\n" +
+	"To: %s\r\n" +
+	"Subject: =?UTF-8?Q?Printer_is_broken_=E2=9C=8B?=\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=\"outer\"\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=\"inner\"\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"The printer on the <second> floor=\r\n" +
+	" does not work.\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>The printer on the second floor does not work.</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"Content-Disposition: attachment; filename=\"error.log\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"UGFwZXIgamFt\r\n" +
+	"--outer--\r\n"
+
+func TestReceiveIntakeEmail(t *testing.T) {
+	config.EmailIntakeDomain.Set("intake.example.com")
+	config.EmailIntakeSecret.Set("secret")
+	defer config.EmailIntakeDomain.Set("")
+	defer config.EmailIntakeSecret.Set("")
+
+	t.Run("creates a task with attachments", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		intake := &ProjectEmailIntake{ProjectID: 1}
+		err := intake.Create(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, intake.Hash+"@intake.example.com", intake.Address)
+
+		mail := strings.Replace(testIntakeMail, "%s", strings.ToUpper(intake.Address), 1)
+		tasks, err := ReceiveIntakeEmail(s, strings.NewReader(mail))
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, int64(1), tasks[0].ProjectID)
+		assert.Equal(t, "Printer is broken ✋", tasks[0].Title)
+		assert.Equal(t, "<p>From: jane@example.com</p><p>The printer on the &lt;second&gt; floor does not work.</p>", tasks[0].Description)
+		require.Len(t, tasks[0].Attachments, 1)
+		assert.Equal(t, "error.log", tasks[0].Attachments[0].File.Name)
+		db.AssertExists(t, "task_attachments", map[string]interface{}{
+			"task_id": tasks[0].ID,
+		}, false)
+	})
+	t.Run("regenerating replaces the old address", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		intake := &ProjectEmailIntake{ProjectID: 1}
+		err := intake.Create(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		oldAddress := intake.Address
+
+		err = (&ProjectEmailIntake{ProjectID: 1}).Create(s, &user.User{ID: 1})
+		require.NoError(t, err)
+
+		mail := strings.Replace(testIntakeMail, "%s", oldAddress, 1)
+		_, err = ReceiveIntakeEmail(s, strings.NewReader(mail))
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidIntakeEmail(err))
+	})
+	t.Run("no intake recipient", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		mail := strings.Replace(testIntakeMail, "%s", "someone@example.com", 1)
+		_, err := ReceiveIntakeEmail(s, strings.NewReader(mail))
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidIntakeEmail(err))
+	})
+	t.Run("creator lost access to the project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 2 does not have access to project 1
+		_, err := s.Insert(&ProjectEmailIntake{ProjectID: 1, Hash: "lostaccess", CreatedByID: 2})
+		require.NoError(t, err)
+
+		mail := strings.Replace(testIntakeMail, "%s", "lostaccess@intake.example.com", 1)
+		_, err = ReceiveIntakeEmail(s, strings.NewReader(mail))
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidIntakeEmail(err))
+		db.AssertMissing(t, "tasks", map[string]interface{}{
+			"title": "Printer is broken ✋",
+		})
+	})
+}
+
+func TestIntakeMail_taskDescription(t *testing.T) {
+	t.Run("html is sanitized", func(t *testing.T) {
+		m := &intakeMail{HTML: `<p onclick="alert(1)">Hello</p><script>alert(1)</script><img src="x" onerror="alert(1)">`}
+		description := m.taskDescription()
+		assert.NotContains(t, description, "script")
+		assert.NotContains(t, description, "onclick")
+		assert.NotContains(t, description, "onerror")
+		assert.Contains(t, description, "<p>Hello</p>")
+	})
+	t.Run("plain text is preferred", func(t *testing.T) {
+		m := &intakeMail{Text: "Hello <b>", HTML: "<p>Hello</p>"}
+		assert.Equal(t, "<p>Hello &lt;b&gt;</p>", m.taskDescription())
+	})
+}
@@ -0,0 +1,70 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"archive/zip"
+	"io"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/utils"
+	"code.vikunja.io/api/pkg/version"
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// ProjectExportFilename returns the name of the zip archive a project is exported into
+func ProjectExportFilename(projectID int64) string {
+	return "project-" + strconv.FormatInt(projectID, 10) + ".zip"
+}
+
+// ExportProject packs a project with its views, buckets, tasks, comments, attachments, background and icon into a zip
+// archive which is written to w while it is created. The archive has the same layout as a user data export, so it can
+// be imported the same way.
+// The caller needs to make sure the auth has read access to the project.
+func ExportProject(s *xorm.Session, projectID int64, a web.Auth, w io.Writer) (err error) {
+	project, err := GetProjectSimpleByID(s, projectID)
+	if err != nil {
+		return err
+	}
+
+	wr := zip.NewWriter(w)
+
+	taskIDs, err := writeProjectsAndTasksToZip(s, a, []*Project{project}, wr)
+	if err != nil {
+		return err
+	}
+	err = exportTaskAttachments(s, wr, taskIDs)
+	if err != nil {
+		return err
+	}
+	err = writeProjectBackgroundsToZip([]*Project{project}, wr)
+	if err != nil {
+		return err
+	}
+	err = writeProjectIconsToZip([]*Project{project}, wr)
+	if err != nil {
+		return err
+	}
+	err = utils.WriteBytesToZip("VERSION", []byte(version.Version), wr)
+	if err != nil {
+		return err
+	}
+
+	return wr.Close()
+}
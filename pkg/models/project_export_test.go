@@ -0,0 +1,71 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportProject(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	files.InitTestFileFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	buf := &bytes.Buffer{}
+	err := ExportProject(s, 1, &user.User{ID: 1}, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "project-1.zip", ProjectExportFilename(1))
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	archiveFiles := map[string]*zip.File{}
+	for _, f := range archive.File {
+		archiveFiles[f.Name] = f
+	}
+	require.Contains(t, archiveFiles, "data.json")
+	assert.Contains(t, archiveFiles, "VERSION")
+
+	r, err := archiveFiles["data.json"].Open()
+	require.NoError(t, err)
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	projects := []*ProjectWithTasksAndBuckets{}
+	err = json.Unmarshal(data, &projects)
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, int64(1), projects[0].ID)
+	assert.NotEmpty(t, projects[0].Tasks)
+	assert.NotEmpty(t, projects[0].Buckets)
+	for _, task := range projects[0].Tasks {
+		assert.Equal(t, int64(1), task.ProjectID)
+	}
+}
@@ -0,0 +1,142 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func TestProjectFreeze(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	freeze := func(t *testing.T, s *xorm.Session, projectID int64) {
+		_, err := s.ID(projectID).Cols("is_frozen").Update(&Project{IsFrozen: true})
+		require.NoError(t, err)
+	}
+
+	t.Run("tasks cannot be changed", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		freeze(t, s, 1)
+
+		_, err := (&Task{ID: 1}).CanUpdate(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectIsFrozen(err))
+
+		_, err = (&Task{ProjectID: 1}).CanCreate(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectIsFrozen(err))
+	})
+	t.Run("tasks cannot be created from forms or mails", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		freeze(t, s, 1)
+
+		err := createTask(s, &Task{Title: "Lorem", ProjectID: 1}, u, false, true, true)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectIsFrozen(err))
+	})
+	t.Run("priorities don't age", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		freeze(t, s, 1)
+		now := time.Now()
+		_, err := s.ID(1).Cols("priority_aging").Update(&Project{
+			PriorityAging: []*PriorityAgingThreshold{
+				{BeforeDue: 86400, Priority: 3},
+			},
+		})
+		require.NoError(t, err)
+		_, err = s.ID(1).Cols("due_date").Update(&Task{DueDate: now.Add(time.Hour)})
+		require.NoError(t, err)
+
+		aged, err := agePriorities(s, now)
+		require.NoError(t, err)
+		assert.Equal(t, 0, aged)
+	})
+	t.Run("child projects are frozen as well", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// Project 27 is the parent of project 12
+		freeze(t, s, 27)
+
+		_, err := (&Project{ID: 12}).CanWrite(s, &user.User{ID: 6})
+		require.Error(t, err)
+		assert.True(t, IsErrProjectIsFrozen(err))
+	})
+	t.Run("comments are still possible", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		freeze(t, s, 1)
+
+		can, err := (&TaskComment{TaskID: 1}).CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("admins can unfreeze", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		freeze(t, s, 1)
+
+		project, err := GetProjectSimpleByID(s, 1)
+		require.NoError(t, err)
+		project.IsFrozen = false
+		can, err := project.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = project.Update(s, u)
+		require.NoError(t, err)
+
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":        1,
+			"is_frozen": false,
+		}, false)
+	})
+	t.Run("only admins can freeze", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 1 only has write access to project 10
+		project, err := GetProjectSimpleByID(s, 10)
+		require.NoError(t, err)
+		project.IsFrozen = true
+		err = project.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+}
@@ -0,0 +1,121 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"image/png"
+	"io"
+	"io/fs"
+
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/utils"
+	"code.vikunja.io/web"
+
+	"github.com/disintegration/imaging"
+	"xorm.io/xorm"
+)
+
+// Uploaded project icons are scaled down to fit into a square of this many pixels.
+const projectIconSize = 256
+
+// SetProjectIcon scales an uploaded image down, stores it as png and sets it as the icon of the project.
+// A previously uploaded icon is removed.
+func SetProjectIcon(s *xorm.Session, project *Project, src io.Reader, filename string, a web.Auth) (err error) {
+	img, err := imaging.Decode(src)
+	if err != nil {
+		return &ErrInvalidProjectIcon{ProjectID: project.ID}
+	}
+
+	buf := &bytes.Buffer{}
+	err = png.Encode(buf, imaging.Fit(img, projectIconSize, projectIconSize, imaging.Lanczos))
+	if err != nil {
+		return err
+	}
+
+	f, err := files.CreateWithMimeAndSession(s, buf, filename, uint64(buf.Len()), a, "image/png", true)
+	if err != nil {
+		return err
+	}
+
+	err = project.DeleteIconFileIfExists()
+	if err != nil {
+		return err
+	}
+
+	project.IconFileID = f.ID
+	return setProjectIconFile(s, project.ID, f.ID)
+}
+
+// RemoveProjectIcon removes the uploaded icon of a project. The emoji icon is kept.
+func RemoveProjectIcon(s *xorm.Session, project *Project) (err error) {
+	err = project.DeleteIconFileIfExists()
+	if err != nil {
+		return err
+	}
+
+	project.IconFileID = 0
+	return setProjectIconFile(s, project.ID, 0)
+}
+
+func setProjectIconFile(s *xorm.Session, projectID, fileID int64) (err error) {
+	_, err = s.
+		Where("id = ?", projectID).
+		Cols("icon_file_id").
+		Update(&Project{IconFileID: fileID})
+	return
+}
+
+// DeleteIconFileIfExists deletes the uploaded icon of a project from the db and the filesystem, if one exists
+func (p *Project) DeleteIconFileIfExists() (err error) {
+	if p.IconFileID == 0 {
+		return
+	}
+
+	file := files.File{ID: p.IconFileID}
+	err = file.Delete()
+	if err != nil && files.IsErrFileDoesNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+func writeProjectIconsToZip(projects []*Project, wr *zip.Writer) (err error) {
+	iconFiles := make(map[int64]io.ReadCloser)
+	for _, p := range projects {
+		if p.IconFileID == 0 {
+			continue
+		}
+
+		iconFile := &files.File{ID: p.IconFileID}
+		err = iconFile.LoadFileByID()
+		if err != nil {
+			var pathError *fs.PathError
+			if errors.As(err, &pathError) {
+				continue
+			}
+			return err
+		}
+
+		iconFiles[p.IconFileID] = iconFile.File
+	}
+
+	return utils.WriteFilesToZip(iconFiles, wr)
+}
@@ -0,0 +1,147 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testIconImage(t *testing.T, width, height int) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, width, height)))
+	require.NoError(t, err)
+	return buf
+}
+
+func TestSetProjectIcon(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("scales the image down", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project, err := GetProjectSimpleByID(s, 1)
+		require.NoError(t, err)
+		err = SetProjectIcon(s, project, testIconImage(t, 1024, 512), "icon.png", u)
+		require.NoError(t, err)
+		require.NotZero(t, project.IconFileID)
+		require.NoError(t, s.Commit())
+
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":           1,
+			"icon_file_id": project.IconFileID,
+		}, false)
+
+		f := &files.File{ID: project.IconFileID}
+		require.NoError(t, f.LoadFileByID())
+		defer f.File.Close()
+		cfg, _, err := image.DecodeConfig(f.File)
+		require.NoError(t, err)
+		assert.Equal(t, projectIconSize, cfg.Width)
+		assert.Equal(t, projectIconSize/2, cfg.Height)
+	})
+	t.Run("no image", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project, err := GetProjectSimpleByID(s, 1)
+		require.NoError(t, err)
+		err = SetProjectIcon(s, project, strings.NewReader("definitely not an image"), "icon.txt", u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidProjectIcon(err))
+	})
+	t.Run("remove", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project, err := GetProjectSimpleByID(s, 1)
+		require.NoError(t, err)
+		err = SetProjectIcon(s, project, testIconImage(t, 32, 32), "icon.png", u)
+		require.NoError(t, err)
+		fileID := project.IconFileID
+
+		err = RemoveProjectIcon(s, project)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":           1,
+			"icon_file_id": 0,
+		}, false)
+		db.AssertMissing(t, "files", map[string]interface{}{
+			"id": fileID,
+		})
+	})
+	t.Run("file id can't be set directly", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project := &Project{
+			Title:      "icon test",
+			Icon:       "🚀",
+			IconFileID: 1,
+		}
+		err := project.Create(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":           project.ID,
+			"icon":         "🚀",
+			"icon_file_id": 0,
+		}, false)
+	})
+	t.Run("duplicate copies the icon", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project, err := GetProjectSimpleByID(s, 1)
+		require.NoError(t, err)
+		err = SetProjectIcon(s, project, testIconImage(t, 32, 32), "icon.png", u)
+		require.NoError(t, err)
+
+		pd := &ProjectDuplicate{ProjectID: 1, SkipTasks: true}
+		can, err := pd.CanCreate(s, u)
+		require.NoError(t, err)
+		require.True(t, can)
+		err = pd.duplicate(s, u)
+		require.NoError(t, err)
+
+		assert.NotZero(t, pd.Project.IconFileID)
+		assert.NotEqual(t, project.IconFileID, pd.Project.IconFileID)
+	})
+}
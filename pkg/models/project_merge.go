@@ -0,0 +1,479 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// ProjectMerge holds everything needed to merge one project into another
+type ProjectMerge struct {
+	// The id of the project which is merged into the target project.
+	ProjectID int64 `json:"-" param:"project"`
+	// The project which receives all tasks and shares of the merged project.
+	TargetProjectID int64 `json:"target_project_id"`
+	// If true, the merged project is deleted afterwards. Otherwise it is archived.
+	DeleteSource bool `json:"delete_source"`
+
+	// The target project after the merge.
+	Project *Project `json:"project,omitempty"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// CanCreate checks if a user has the right to merge a project into another one
+func (pm *ProjectMerge) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	// The merged project is gone afterwards, which needs the same rights as deleting it
+	source := &Project{ID: pm.ProjectID}
+	can, err := source.IsAdmin(s, a)
+	if err != nil || !can {
+		return can, err
+	}
+
+	// The shares of the merged project are added to the target, which needs the same rights as sharing it
+	target := &Project{ID: pm.TargetProjectID}
+	return target.IsAdmin(s, a)
+}
+
+// Create merges a project into another one
+// @Summary Merge a project into another one
+// @Description Moves all tasks of a project into the target project. Buckets and labels with the same title in both projects are merged, all user and team shares are added to the target project, with at most the rights the user has on the target. Link shares are not carried over. Child projects are moved below the target project. The merged project is archived afterwards, or deleted if `delete_source` is true. The user needs admin rights on both projects.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param projectID path int true "The project ID to merge into the target"
+// @Param merge body models.ProjectMerge true "The target project and merge options."
+// @Success 201 {object} models.ProjectMerge "The target project after the merge."
+// @Failure 400 {object} web.HTTPError "The project cannot be merged into itself or one of its children."
+// @Failure 403 {object} web.HTTPError "The user does not have access to one of the projects."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{projectID}/merge [put]
+func (pm *ProjectMerge) Create(s *xorm.Session, doer web.Auth) (err error) {
+	source, err := GetProjectSimpleByID(s, pm.ProjectID)
+	if err != nil {
+		return err
+	}
+	target, err := GetProjectSimpleByID(s, pm.TargetProjectID)
+	if err != nil {
+		return err
+	}
+
+	err = target.CheckIsArchived(s)
+	if err != nil {
+		return err
+	}
+
+	parents, err := GetAllParentProjects(s, target.ID)
+	if err != nil {
+		return err
+	}
+	if _, isChild := parents[source.ID]; isChild {
+		return &ErrCannotMergeProjectIntoItself{ProjectID: source.ID, TargetProjectID: target.ID}
+	}
+
+	err = mergeProjectTasks(s, source, target, doer)
+	if err != nil {
+		return err
+	}
+
+	err = mergeProjectShares(s, source, target, doer)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.
+		Where("parent_project_id = ?", source.ID).
+		Cols("parent_project_id").
+		Update(&Project{ParentProjectID: target.ID})
+	if err != nil {
+		return err
+	}
+
+	if pm.DeleteSource {
+		err = source.Delete(s, doer)
+	} else {
+		source.IsArchived = true
+		_, err = s.ID(source.ID).Cols("is_archived").Update(source)
+	}
+	if err != nil {
+		return err
+	}
+
+	pm.Project = target
+	return events.Dispatch(&ProjectUpdatedEvent{
+		Project: target,
+		Doer:    doer,
+	})
+}
+
+// mergeProjectTasks moves all tasks from the source to the target project. Tasks keep the title of the bucket
+// they were in: if the target has a bucket with the same title they are put there, otherwise it is created.
+func mergeProjectTasks(s *xorm.Session, source, target *Project, doer web.Auth) (err error) {
+	tasks := []*Task{}
+	err = s.
+		Where("project_id = ?", source.ID).
+		OrderBy("`index` asc").
+		Find(&tasks)
+	if err != nil || len(tasks) == 0 {
+		return err
+	}
+
+	sourceViews, err := getViewsForProject(s, source.ID)
+	if err != nil {
+		return err
+	}
+	sourceViewIDs := make([]int64, 0, len(sourceViews))
+	for _, view := range sourceViews {
+		sourceViewIDs = append(sourceViewIDs, view.ID)
+	}
+
+	bucketTitles, err := getTaskBucketTitles(s, sourceViews)
+	if err != nil {
+		return err
+	}
+
+	nextIndex, err := getNextTaskIndex(s, target.ID)
+	if err != nil {
+		return err
+	}
+
+	taskIDs := make([]int64, 0, len(tasks))
+	for _, t := range tasks {
+		t.ProjectID = target.ID
+		t.Index = nextIndex
+		nextIndex++
+
+		_, err = s.ID(t.ID).Cols("project_id", "index").NoAutoTime().Update(t)
+		if err != nil {
+			return err
+		}
+		taskIDs = append(taskIDs, t.ID)
+	}
+
+	if len(sourceViewIDs) > 0 {
+		_, err = s.In("task_id", taskIDs).In("project_view_id", sourceViewIDs).Delete(&TaskBucket{})
+		if err != nil {
+			return err
+		}
+		_, err = s.In("task_id", taskIDs).In("project_view_id", sourceViewIDs).Delete(&TaskPosition{})
+		if err != nil {
+			return err
+		}
+	}
+
+	targetViews, err := getViewsForProject(s, target.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, view := range targetViews {
+		positions := make([]*TaskPosition, 0, len(tasks))
+		for _, t := range tasks {
+			positions = append(positions, &TaskPosition{
+				TaskID:        t.ID,
+				ProjectViewID: view.ID,
+				Position:      calculateDefaultPosition(t.Index, 0),
+			})
+		}
+		_, err = s.Insert(&positions)
+		if err != nil {
+			return err
+		}
+
+		if view.ViewKind != ProjectViewKindKanban || view.BucketConfigurationMode != BucketConfigurationModeManual {
+			continue
+		}
+
+		err = addMergedTasksToBuckets(s, view, tasks, bucketTitles, doer)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = mergeProjectLabels(s, target.ID, taskIDs)
+	if err != nil {
+		return err
+	}
+
+	return IndexTasksByID(s, taskIDs)
+}
+
+// getTaskBucketTitles returns the title of the bucket each task is in, keyed by task id.
+// Only the first manual kanban view of the project is taken into account.
+func getTaskBucketTitles(s *xorm.Session, views []*ProjectView) (titles map[int64]string, err error) {
+	titles = make(map[int64]string)
+
+	for _, view := range views {
+		if view.ViewKind != ProjectViewKindKanban || view.BucketConfigurationMode != BucketConfigurationModeManual {
+			continue
+		}
+
+		buckets := make(map[int64]*Bucket)
+		err = s.Where("project_view_id = ?", view.ID).Find(&buckets)
+		if err != nil {
+			return
+		}
+
+		taskBuckets := []*TaskBucket{}
+		err = s.Where("project_view_id = ?", view.ID).Find(&taskBuckets)
+		if err != nil {
+			return
+		}
+
+		for _, tb := range taskBuckets {
+			if b, has := buckets[tb.BucketID]; has {
+				titles[tb.TaskID] = b.Title
+			}
+		}
+
+		return
+	}
+
+	return
+}
+
+func addMergedTasksToBuckets(s *xorm.Session, view *ProjectView, tasks []*Task, bucketTitles map[int64]string, doer web.Auth) (err error) {
+	buckets := []*Bucket{}
+	err = s.Where("project_view_id = ?", view.ID).Find(&buckets)
+	if err != nil {
+		return err
+	}
+	bucketsByTitle := make(map[string]int64, len(buckets))
+	for _, b := range buckets {
+		if _, has := bucketsByTitle[b.Title]; !has {
+			bucketsByTitle[b.Title] = b.ID
+		}
+	}
+
+	defaultBucketID, err := getDefaultBucketID(s, view)
+	if err != nil {
+		return err
+	}
+
+	taskBuckets := make([]*TaskBucket, 0, len(tasks))
+	for _, t := range tasks {
+		bucketID := defaultBucketID
+
+		if title, has := bucketTitles[t.ID]; has {
+			bucketID, has = bucketsByTitle[title]
+			if !has {
+				b := &Bucket{
+					Title:         title,
+					ProjectViewID: view.ID,
+					ProjectID:     view.ProjectID,
+				}
+				err = b.Create(s, doer)
+				if err != nil {
+					return err
+				}
+				bucketsByTitle[title] = b.ID
+				bucketID = b.ID
+			}
+		}
+
+		if t.Done && view.DoneBucketID != 0 {
+			bucketID = view.DoneBucketID
+		}
+
+		taskBuckets = append(taskBuckets, &TaskBucket{
+			BucketID:      bucketID,
+			TaskID:        t.ID,
+			ProjectViewID: view.ID,
+		})
+	}
+
+	_, err = s.Insert(&taskBuckets)
+	return err
+}
+
+// mergeProjectLabels replaces labels on the moved tasks with a label of the same title which is already used
+// in the target project.
+func mergeProjectLabels(s *xorm.Session, targetProjectID int64, movedTaskIDs []int64) (err error) {
+	existing := []*Label{}
+	err = s.
+		Select("labels.*").
+		Join("INNER", "label_tasks", "label_tasks.label_id = labels.id").
+		Join("INNER", "tasks", "tasks.id = label_tasks.task_id").
+		Where("tasks.project_id = ?", targetProjectID).
+		And(builder.NotIn("label_tasks.task_id", movedTaskIDs)).
+		OrderBy("labels.id asc").
+		Find(&existing)
+	if err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+
+	labelsByTitle := make(map[string]int64, len(existing))
+	for _, l := range existing {
+		if _, has := labelsByTitle[l.Title]; !has {
+			labelsByTitle[l.Title] = l.ID
+		}
+	}
+
+	labelTasks := []*LabelTask{}
+	err = s.In("task_id", movedTaskIDs).Find(&labelTasks)
+	if err != nil || len(labelTasks) == 0 {
+		return err
+	}
+
+	labelIDs := make([]int64, 0, len(labelTasks))
+	for _, lt := range labelTasks {
+		labelIDs = append(labelIDs, lt.LabelID)
+	}
+	labels := make(map[int64]*Label)
+	err = s.In("id", labelIDs).Find(&labels)
+	if err != nil {
+		return err
+	}
+
+	for _, lt := range labelTasks {
+		l, has := labels[lt.LabelID]
+		if !has {
+			continue
+		}
+		targetLabelID, has := labelsByTitle[l.Title]
+		if !has || targetLabelID == lt.LabelID {
+			continue
+		}
+
+		exists, err := s.Exist(&LabelTask{TaskID: lt.TaskID, LabelID: targetLabelID})
+		if err != nil {
+			return err
+		}
+		if exists {
+			_, err = s.ID(lt.ID).Delete(&LabelTask{})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		// The task keeps its own label if the one of the target project is in an exclusive group with another
+		// label of the task
+		otherLabelIDs := []int64{}
+		err = s.Table("label_tasks").
+			Where("task_id = ? AND label_id != ?", lt.TaskID, lt.LabelID).
+			Cols("label_id").
+			Find(&otherLabelIDs)
+		if err != nil {
+			return err
+		}
+		err = checkExclusiveLabelGroups(s, append(otherLabelIDs, targetLabelID))
+		if IsErrLabelGroupIsExclusive(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = s.ID(lt.ID).Cols("label_id").Update(&LabelTask{LabelID: targetLabelID})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mergeProjectShares gives every user and team with access to the source project the same access to the target.
+// If they already have access to the target, they keep the higher of both rights. The owner of the source project
+// gets admin rights on the target so they don't lose access to their tasks.
+// No share gets more rights than the doer has on the target, nobody should be able to hand out rights they don't have.
+func mergeProjectShares(s *xorm.Session, source, target *Project, doer web.Auth) (err error) {
+	_, maxRight, err := target.CanRead(s, doer)
+	if err != nil {
+		return err
+	}
+	capRight := func(right Right) Right {
+		if right > Right(maxRight) {
+			return Right(maxRight)
+		}
+		return right
+	}
+
+	projectUsers := []*ProjectUser{}
+	err = s.Where("project_id = ?", source.ID).Find(&projectUsers)
+	if err != nil {
+		return err
+	}
+	if source.OwnerID != target.OwnerID {
+		projectUsers = append(projectUsers, &ProjectUser{UserID: source.OwnerID, Right: RightAdmin})
+	}
+
+	for _, pu := range projectUsers {
+		if pu.UserID == target.OwnerID {
+			continue
+		}
+		pu.Right = capRight(pu.Right)
+
+		existing := &ProjectUser{}
+		has, err := s.Where("project_id = ? AND user_id = ?", target.ID, pu.UserID).Get(existing)
+		if err != nil {
+			return err
+		}
+		if has {
+			if pu.Right > existing.Right {
+				existing.Right = pu.Right
+				_, err = s.ID(existing.ID).Cols("right").Update(existing)
+			}
+		} else {
+			_, err = s.Insert(&ProjectUser{UserID: pu.UserID, ProjectID: target.ID, Right: pu.Right})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	teamProjects := []*TeamProject{}
+	err = s.Where("project_id = ?", source.ID).Find(&teamProjects)
+	if err != nil {
+		return err
+	}
+
+	for _, tp := range teamProjects {
+		tp.Right = capRight(tp.Right)
+		existing := &TeamProject{}
+		has, err := s.Where("project_id = ? AND team_id = ?", target.ID, tp.TeamID).Get(existing)
+		if err != nil {
+			return err
+		}
+		if has {
+			if tp.Right > existing.Right {
+				existing.Right = tp.Right
+				_, err = s.ID(existing.ID).Cols("right").Update(existing)
+			}
+		} else {
+			_, err = s.Insert(&TeamProject{TeamID: tp.TeamID, ProjectID: target.ID, Right: tp.Right})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
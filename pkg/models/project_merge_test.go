@@ -0,0 +1,119 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectMerge_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("archive source", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pm := &ProjectMerge{ProjectID: 11, TargetProjectID: 1}
+		can, err := pm.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = pm.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":         20,
+			"project_id": 1,
+		}, false)
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":          11,
+			"is_archived": true,
+		}, false)
+		// The bucket does not exist in the target and is created
+		db.AssertExists(t, "buckets", map[string]interface{}{
+			"title":           "testbucket11",
+			"project_view_id": 4,
+		}, false)
+		db.AssertMissing(t, "task_buckets", map[string]interface{}{
+			"task_id":         20,
+			"project_view_id": 44,
+		})
+		// The owner of the merged project keeps access to its tasks
+		db.AssertExists(t, "users_projects", map[string]interface{}{
+			"user_id":    6,
+			"project_id": 1,
+			"right":      RightAdmin,
+		}, false)
+	})
+	t.Run("delete source", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pm := &ProjectMerge{ProjectID: 11, TargetProjectID: 1, DeleteSource: true}
+		err := pm.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "projects", map[string]interface{}{
+			"id": 11,
+		})
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":         20,
+			"project_id": 1,
+		}, false)
+	})
+	t.Run("into itself", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pm := &ProjectMerge{ProjectID: 1, TargetProjectID: 1}
+		err := pm.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrCannotMergeProjectIntoItself(err))
+	})
+	t.Run("no admin rights on source", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pm := &ProjectMerge{ProjectID: 10, TargetProjectID: 1}
+		can, err := pm.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("no admin rights on target", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pm := &ProjectMerge{ProjectID: 11, TargetProjectID: 10}
+		can, err := pm.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
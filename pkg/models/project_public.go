@@ -0,0 +1,225 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/user"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// PublicProject is the representation of a public project shown to everyone, without authentication.
+type PublicProject struct {
+	ID          int64                `json:"id"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	Identifier  string               `json:"identifier"`
+	HexColor    string               `json:"hex_color"`
+	Icon        string               `json:"icon"`
+	Views       []*PublicProjectView `json:"views"`
+}
+
+// PublicProjectView holds the parts of a project view needed to render it on a public page.
+type PublicProjectView struct {
+	ID       int64           `json:"id"`
+	Title    string          `json:"title"`
+	ViewKind ProjectViewKind `json:"view_kind"`
+	Position float64         `json:"position"`
+}
+
+// checkIsPublicUpdate makes sure only project admins can make a project public or private again
+func checkIsPublicUpdate(s *xorm.Session, project *Project, a web.Auth) error {
+	old, err := GetProjectSimpleByID(s, project.ID)
+	if err != nil {
+		return err
+	}
+
+	if old.IsPublic == project.IsPublic {
+		return nil
+	}
+
+	if project.IsPublic && !config.ServiceEnablePublicProjects.GetBool() {
+		return &ErrPublicProjectsDisabled{}
+	}
+
+	isAdmin, err := project.IsAdmin(s, a)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return ErrGenericForbidden{}
+	}
+
+	return nil
+}
+
+// getPublicProjectByID returns a project only if it was made public. Private projects are reported as not
+// existing to avoid leaking which project ids exist.
+func getPublicProjectByID(s *xorm.Session, projectID int64) (project *Project, err error) {
+	if !config.ServiceEnablePublicProjects.GetBool() {
+		return nil, ErrProjectDoesNotExist{ID: projectID}
+	}
+
+	project, err = GetProjectSimpleByID(s, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !project.IsPublic {
+		return nil, ErrProjectDoesNotExist{ID: projectID}
+	}
+
+	return project, nil
+}
+
+// publicAuth returns an anonymous, read only share of the project. Passing it to the task collection
+// scopes all queries to the project the same way a link share does.
+func (p *Project) publicAuth() *LinkSharing {
+	return &LinkSharing{
+		ProjectID:   p.ID,
+		Right:       RightRead,
+		SharingType: SharingTypeWithoutPassword,
+	}
+}
+
+// GetPublicProject returns a public project with all of its views
+func GetPublicProject(s *xorm.Session, projectID int64) (*PublicProject, error) {
+	project, err := getPublicProjectByID(s, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := getViewsForProject(s, project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	pp := &PublicProject{
+		ID:          project.ID,
+		Title:       project.Title,
+		Description: project.Description,
+		Identifier:  project.Identifier,
+		HexColor:    project.HexColor,
+		Icon:        project.Icon,
+		Views:       make([]*PublicProjectView, 0, len(views)),
+	}
+	for _, view := range views {
+		// Views which need more than read rights are not available to anonymous visitors
+		if view.Right > RightRead {
+			continue
+		}
+		pp.Views = append(pp.Views, &PublicProjectView{
+			ID:       view.ID,
+			Title:    view.Title,
+			ViewKind: view.ViewKind,
+			Position: view.Position,
+		})
+	}
+
+	return pp, nil
+}
+
+// GetPublicProjectViewTasks returns the tasks of a view of a public project. Like the authenticated endpoint,
+// this returns buckets with their tasks for kanban views and a flat list of tasks for everything else.
+func GetPublicProjectViewTasks(s *xorm.Session, projectID, viewID int64, page, perPage int) (result interface{}, resultCount int, totalItems int64, err error) {
+	project, err := getPublicProjectByID(s, projectID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	view, err := GetProjectViewByIDAndProject(s, viewID, project.ID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if view.Right > RightRead {
+		return nil, 0, 0, &ErrProjectViewDoesNotExist{ProjectViewID: viewID}
+	}
+
+	tc := &TaskCollection{
+		ProjectID:     project.ID,
+		ProjectViewID: viewID,
+	}
+	result, resultCount, totalItems, err = tc.ReadAll(s, project.publicAuth(), "", page, perPage)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	switch r := result.(type) {
+	case []*Task:
+		for _, t := range r {
+			t.sanitizeForPublic()
+		}
+	case []*Bucket:
+		for _, b := range r {
+			removeEmail(b.CreatedBy)
+			for _, t := range b.Tasks {
+				t.sanitizeForPublic()
+			}
+		}
+	}
+
+	return result, resultCount, totalItems, nil
+}
+
+func removeEmail(u *user.User) {
+	if u != nil {
+		u.Email = ""
+	}
+}
+
+// sanitizeForPublic removes everything from a task which should only be visible to people with access
+// to the project: subscriptions, the email addresses of all users and related tasks from other projects.
+func (t *Task) sanitizeForPublic() {
+	t.Subscription = nil
+	t.IsFavorite = false
+	t.IsUnread = false
+
+	removeEmail(t.CreatedBy)
+	removeEmail(t.DoneBy)
+	for _, u := range t.Assignees {
+		removeEmail(u)
+	}
+	for _, l := range t.Labels {
+		removeEmail(l.CreatedBy)
+	}
+	for _, a := range t.Attachments {
+		removeEmail(a.CreatedBy)
+	}
+	for _, users := range t.Reactions {
+		for _, u := range users {
+			removeEmail(u)
+		}
+	}
+	// Related tasks are loaded without checking access, only those in the same public project may be shown
+	for kind, related := range t.RelatedTasks {
+		public := make([]*Task, 0, len(related))
+		for _, rt := range related {
+			if rt.ProjectID != t.ProjectID {
+				continue
+			}
+			rt.sanitizeForPublic()
+			public = append(public, rt)
+		}
+		if len(public) == 0 {
+			delete(t.RelatedTasks, kind)
+			continue
+		}
+		t.RelatedTasks[kind] = public
+	}
+}
@@ -0,0 +1,164 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func TestPublicProject(t *testing.T) {
+	config.ServiceEnablePublicProjects.Set(true)
+	defer config.ServiceEnablePublicProjects.Set(false)
+
+	makePublic := func(t *testing.T, s *xorm.Session, projectID int64) {
+		_, err := s.ID(projectID).Cols("is_public").Update(&Project{IsPublic: true})
+		require.NoError(t, err)
+	}
+
+	t.Run("private project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := GetPublicProject(s, 1)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectDoesNotExist(err))
+	})
+	t.Run("public project with views", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		makePublic(t, s, 1)
+
+		project, err := GetPublicProject(s, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Test1", project.Title)
+		assert.Len(t, project.Views, 4)
+	})
+	t.Run("views which need more than read rights", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		makePublic(t, s, 1)
+		_, err := s.ID(2).Cols("right").Update(&ProjectView{Right: RightWrite})
+		require.NoError(t, err)
+
+		project, err := GetPublicProject(s, 1)
+		require.NoError(t, err)
+		assert.Len(t, project.Views, 3)
+		for _, view := range project.Views {
+			assert.NotEqual(t, int64(2), view.ID)
+		}
+
+		_, _, _, err = GetPublicProjectViewTasks(s, 1, 2, 1, 50)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectViewDoesNotExist(err))
+	})
+	t.Run("tasks without emails", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		makePublic(t, s, 1)
+
+		result, _, _, err := GetPublicProjectViewTasks(s, 1, 1, 1, 50)
+		require.NoError(t, err)
+		tasks := result.([]*Task)
+		require.NotEmpty(t, tasks)
+
+		var found bool
+		for _, task := range tasks {
+			assert.Equal(t, int64(1), task.ProjectID)
+			if task.CreatedBy != nil {
+				assert.Empty(t, task.CreatedBy.Email)
+			}
+			for _, assignee := range task.Assignees {
+				assert.Empty(t, assignee.Email)
+			}
+			if task.ID == 30 {
+				found = true
+				assert.Len(t, task.Assignees, 2)
+			}
+		}
+		assert.True(t, found)
+	})
+	t.Run("related tasks of other projects", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		makePublic(t, s, 1)
+		// Task 29 is a subtask of task 1, moving it to a private project must hide it
+		_, err := s.ID(29).Cols("project_id").Update(&Task{ProjectID: 2})
+		require.NoError(t, err)
+
+		result, _, _, err := GetPublicProjectViewTasks(s, 1, 1, 1, 50)
+		require.NoError(t, err)
+		for _, task := range result.([]*Task) {
+			for _, related := range task.RelatedTasks {
+				for _, rt := range related {
+					assert.Equal(t, int64(1), rt.ProjectID)
+				}
+			}
+		}
+	})
+	t.Run("kanban view", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		makePublic(t, s, 1)
+
+		result, _, _, err := GetPublicProjectViewTasks(s, 1, 4, 1, 50)
+		require.NoError(t, err)
+		buckets := result.([]*Bucket)
+		assert.Len(t, buckets, 3)
+	})
+	t.Run("view of another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		makePublic(t, s, 1)
+
+		_, _, _, err := GetPublicProjectViewTasks(s, 1, 5, 1, 50)
+		require.Error(t, err)
+	})
+	t.Run("only admins can make a project public", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 1 only has write access to project 10
+		project, err := GetProjectSimpleByID(s, 10)
+		require.NoError(t, err)
+		project.IsPublic = true
+		err = project.Update(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+}
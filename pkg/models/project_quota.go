@@ -0,0 +1,287 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/files"
+
+	"code.vikunja.io/web"
+	"github.com/c2h5oh/datasize"
+	"xorm.io/xorm"
+)
+
+// projectQuota holds the limits which apply to a project. A limit of 0 means there is no limit.
+type projectQuota struct {
+	MaxOpenTasks       int64
+	MaxAttachmentsSize int64
+}
+
+func stricterLimit(current, limit int64) int64 {
+	if limit > 0 && (current == 0 || limit < current) {
+		return limit
+	}
+	return current
+}
+
+// getQuota returns the limits of a project. These are the strictest of the configured instance limits and the
+// limits of the project and all of its parents.
+func (p *Project) getQuota(s *xorm.Session) (quota *projectQuota, err error) {
+	var maxSize datasize.ByteSize
+	err = maxSize.UnmarshalText([]byte(config.QuotasMaxAttachmentsSize.GetString()))
+	if err != nil {
+		return nil, err
+	}
+
+	quota = &projectQuota{
+		MaxOpenTasks:       config.QuotasMaxOpenTasks.GetInt64(),
+		MaxAttachmentsSize: int64(maxSize.Bytes()),
+	}
+
+	current := p
+	for {
+		quota.MaxOpenTasks = stricterLimit(quota.MaxOpenTasks, current.MaxOpenTasks)
+		quota.MaxAttachmentsSize = stricterLimit(quota.MaxAttachmentsSize, current.MaxAttachmentsSize)
+
+		if current.ParentProjectID == 0 {
+			return quota, nil
+		}
+
+		current, err = GetProjectSimpleByID(s, current.ParentProjectID)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// checkOpenTaskQuota returns an error if the project can't take another undone task
+func (p *Project) checkOpenTaskQuota(s *xorm.Session) error {
+	return p.checkOpenTaskQuotaFor(s, 1)
+}
+
+// checkOpenTaskQuotaFor returns an error if the project can't take that many more undone tasks
+func (p *Project) checkOpenTaskQuotaFor(s *xorm.Session, additional int64) error {
+	quota, err := p.getQuota(s)
+	if err != nil {
+		return err
+	}
+	if quota.MaxOpenTasks == 0 {
+		return nil
+	}
+
+	openTasks, err := s.
+		Where("project_id = ? AND done = ?", p.ID, false).
+		Count(&Task{})
+	if err != nil {
+		return err
+	}
+
+	if openTasks+additional > quota.MaxOpenTasks {
+		return &ErrProjectOpenTaskQuotaExceeded{ProjectID: p.ID, Limit: quota.MaxOpenTasks}
+	}
+	return nil
+}
+
+// AttachmentsUsage holds how much storage the attachments of a project or user take up and how much they may use
+type AttachmentsUsage struct {
+	// The combined size of all attachments in bytes.
+	Used int64 `json:"used"`
+	// The maximum combined size of all attachments in bytes. 0 means there is no limit.
+	Limit int64 `json:"limit"`
+}
+
+// getAttachmentsUsage returns the combined size of the attachments of all tasks in the project
+func (p *Project) getAttachmentsUsage(s *xorm.Session) (usage *AttachmentsUsage, err error) {
+	quota, err := p.getQuota(s)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.
+		Table("files").
+		Join("INNER", "task_attachments", "task_attachments.file_id = files.id").
+		Join("INNER", "tasks", "tasks.id = task_attachments.task_id").
+		Where("tasks.project_id = ?", p.ID).
+		SumInt(&files.File{}, "files.size")
+	if err != nil {
+		return nil, err
+	}
+
+	return &AttachmentsUsage{Used: used, Limit: quota.MaxAttachmentsSize}, nil
+}
+
+// checkAttachmentQuota returns an error if adding a file of the given size would put the attachments of all tasks
+// in the project above the limit
+func (p *Project) checkAttachmentQuota(s *xorm.Session, size uint64) error {
+	usage, err := p.getAttachmentsUsage(s)
+	if err != nil {
+		return err
+	}
+
+	if usage.Limit > 0 && usage.Used+int64(size) > usage.Limit {
+		return &ErrProjectAttachmentQuotaExceeded{
+			ProjectID: p.ID,
+			Limit:     usage.Limit,
+			Used:      usage.Used,
+		}
+	}
+	return nil
+}
+
+// GetUserAttachmentsUsage returns the combined size of all attachments a user uploaded, in all projects
+func GetUserAttachmentsUsage(s *xorm.Session, userID int64) (usage *AttachmentsUsage, err error) {
+	var limit datasize.ByteSize
+	err = limit.UnmarshalText([]byte(config.QuotasMaxAttachmentsSizePerUser.GetString()))
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.
+		Table("files").
+		Join("INNER", "task_attachments", "task_attachments.file_id = files.id").
+		Where("task_attachments.created_by_id = ?", userID).
+		SumInt(&files.File{}, "files.size")
+	if err != nil {
+		return nil, err
+	}
+
+	return &AttachmentsUsage{Used: used, Limit: int64(limit.Bytes())}, nil
+}
+
+// checkAttachmentQuotas returns an error if adding a file of the given size would exceed the attachment limit of the
+// project or of the user uploading it
+func checkAttachmentQuotas(s *xorm.Session, project *Project, a web.Auth, size uint64) error {
+	err := project.checkAttachmentQuota(s, size)
+	if err != nil {
+		return err
+	}
+
+	// Link shares don't have a limit of their own, only the one of the project applies
+	if _, is := a.(*LinkSharing); is {
+		return nil
+	}
+
+	usage, err := GetUserAttachmentsUsage(s, a.GetID())
+	if err != nil {
+		return err
+	}
+
+	if usage.Limit > 0 && usage.Used+int64(size) > usage.Limit {
+		return &ErrUserAttachmentQuotaExceeded{
+			UserID: a.GetID(),
+			Limit:  usage.Limit,
+			Used:   usage.Used,
+		}
+	}
+	return nil
+}
+
+// checkQuotaUpdate makes sure only project admins change the limits of a project
+func checkQuotaUpdate(s *xorm.Session, project *Project, a web.Auth) error {
+	if project.MaxOpenTasks < 0 {
+		return InvalidFieldError([]string{"max_open_tasks"})
+	}
+	if project.MaxAttachmentsSize < 0 {
+		return InvalidFieldError([]string{"max_attachments_size"})
+	}
+
+	old, err := GetProjectSimpleByID(s, project.ID)
+	if err != nil {
+		return err
+	}
+
+	if old.MaxOpenTasks == project.MaxOpenTasks && old.MaxAttachmentsSize == project.MaxAttachmentsSize {
+		return nil
+	}
+
+	isAdmin, err := project.IsAdmin(s, a)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return ErrGenericForbidden{}
+	}
+	return nil
+}
+
+// checkUserProjectQuota returns an error if the user can't own another project
+func checkUserProjectQuota(s *xorm.Session, userID int64) error {
+	return checkUserProjectQuotaFor(s, userID, 1)
+}
+
+// checkUserProjectQuotaFor returns an error if the user can't own that many more projects
+func checkUserProjectQuotaFor(s *xorm.Session, userID int64, additional int64) error {
+	limit := config.QuotasMaxProjectsPerUser.GetInt64()
+	if limit <= 0 {
+		return nil
+	}
+
+	owned, err := s.Where("owner_id = ?", userID).Count(&Project{})
+	if err != nil {
+		return err
+	}
+
+	if owned+additional > limit {
+		return &ErrUserProjectQuotaExceeded{UserID: userID, Limit: limit}
+	}
+	return nil
+}
+
+// checkUserTaskQuota returns an error if the user can't create another task. Link shares don't have a limit of
+// their own.
+func checkUserTaskQuota(s *xorm.Session, a web.Auth) error {
+	return checkUserTaskQuotaFor(s, a, 1)
+}
+
+// checkUserTaskQuotaFor returns an error if the user can't create that many more tasks
+func checkUserTaskQuotaFor(s *xorm.Session, a web.Auth, additional int64) error {
+	limit := config.QuotasMaxTasksPerUser.GetInt64()
+	if limit <= 0 {
+		return nil
+	}
+	if _, is := a.(*LinkSharing); is {
+		return nil
+	}
+
+	created, err := s.Where("created_by_id = ?", a.GetID()).Count(&Task{})
+	if err != nil {
+		return err
+	}
+
+	if created+additional > limit {
+		return &ErrUserTaskQuotaExceeded{UserID: a.GetID(), Limit: limit}
+	}
+	return nil
+}
+
+// checkUserLinkShareQuota returns an error if the user can't create another link share
+func checkUserLinkShareQuota(s *xorm.Session, userID int64) error {
+	limit := config.QuotasMaxLinkSharesPerUser.GetInt64()
+	if limit <= 0 {
+		return nil
+	}
+
+	created, err := s.Where("shared_by_id = ?", userID).Count(&LinkSharing{})
+	if err != nil {
+		return err
+	}
+
+	if created >= limit {
+		return &ErrUserLinkShareQuotaExceeded{UserID: userID, Limit: limit}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectQuota(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("open tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(1).Cols("max_open_tasks").Update(&Project{MaxOpenTasks: 1})
+		require.NoError(t, err)
+
+		err = (&Task{Title: "Lorem", ProjectID: 1}).Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectOpenTaskQuotaExceeded(err))
+
+		// Done tasks don't count towards the limit
+		err = (&Task{Title: "Lorem", ProjectID: 1, Done: true}).Create(s, u)
+		require.NoError(t, err)
+	})
+	t.Run("open tasks through updates", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(1).Cols("max_open_tasks").Update(&Project{MaxOpenTasks: 1})
+		require.NoError(t, err)
+		_, err = s.ID(2).Cols("max_open_tasks").Update(&Project{MaxOpenTasks: 1})
+		require.NoError(t, err)
+
+		// Task 2 is done
+		err = (&Task{ID: 2, Title: "task #2 done", Done: false}).Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectOpenTaskQuotaExceeded(err))
+
+		// Task 12 is in project 1
+		err = (&Task{ID: 12, ProjectID: 2}).Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectOpenTaskQuotaExceeded(err))
+	})
+	t.Run("strictest limit wins", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		config.QuotasMaxOpenTasks.Set(100)
+		defer config.QuotasMaxOpenTasks.Set(0)
+
+		// Project 27 is the parent of project 12
+		_, err := s.ID(27).Cols("max_open_tasks").Update(&Project{MaxOpenTasks: 10})
+		require.NoError(t, err)
+		_, err = s.ID(12).Cols("max_open_tasks").Update(&Project{MaxOpenTasks: 20})
+		require.NoError(t, err)
+
+		project, err := GetProjectSimpleByID(s, 12)
+		require.NoError(t, err)
+		quota, err := project.getQuota(s)
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), quota.MaxOpenTasks)
+	})
+	t.Run("attachment size", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// The attachments of project 1 already use 200 bytes
+		_, err := s.ID(1).Cols("max_attachments_size").Update(&Project{MaxAttachmentsSize: 250})
+		require.NoError(t, err)
+
+		ta := &TaskAttachment{TaskID: 1}
+		err = ta.NewAttachment(s, &testfile{content: []byte("testingstuff")}, "testfile", 100, u)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectAttachmentQuotaExceeded(err))
+
+		ta = &TaskAttachment{TaskID: 1}
+		err = ta.NewAttachment(s, &testfile{content: []byte("testingstuff")}, "testfile", 12, u)
+		require.NoError(t, err)
+
+		project := &Project{ID: 1}
+		err = project.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, &AttachmentsUsage{Used: 212, Limit: 250}, project.AttachmentsUsage)
+	})
+	t.Run("user attachment size", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		config.QuotasMaxAttachmentsSizePerUser.Set("150B")
+		defer config.QuotasMaxAttachmentsSizePerUser.Set("0")
+
+		// User 1 uploaded one attachment with 100 bytes, the other one's file does not exist
+		usage, err := GetUserAttachmentsUsage(s, u.ID)
+		require.NoError(t, err)
+		assert.Equal(t, &AttachmentsUsage{Used: 100, Limit: 150}, usage)
+
+		ta := &TaskAttachment{TaskID: 1}
+		err = ta.NewAttachment(s, &testfile{content: []byte("testingstuff")}, "testfile", 100, u)
+		require.Error(t, err)
+		assert.True(t, IsErrUserAttachmentQuotaExceeded(err))
+
+		ta = &TaskAttachment{TaskID: 1}
+		err = ta.NewAttachment(s, &testfile{content: []byte("testingstuff")}, "testfile", 12, u)
+		require.NoError(t, err)
+
+		// Link shares only have the project limit
+		share := &LinkSharing{ID: 2, ProjectID: 1, Right: RightWrite, SharedByID: 1}
+		ta = &TaskAttachment{TaskID: 1}
+		err = ta.NewAttachment(s, &testfile{content: []byte("testingstuff")}, "testfile", 100, share)
+		require.NoError(t, err)
+	})
+	t.Run("only admins can change limits", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 1 only has write access to project 10
+		project, err := GetProjectSimpleByID(s, 10)
+		require.NoError(t, err)
+		project.MaxOpenTasks = 5
+		err = project.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+}
+
+func TestUserQuota(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("projects", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 1 owns 3 projects
+		config.QuotasMaxProjectsPerUser.Set(3)
+		defer config.QuotasMaxProjectsPerUser.Set(0)
+
+		err := (&Project{Title: "Lorem"}).Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrUserProjectQuotaExceeded(err))
+
+		config.QuotasMaxProjectsPerUser.Set(4)
+		err = (&Project{Title: "Lorem"}).Create(s, u)
+		require.NoError(t, err)
+	})
+	t.Run("tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 1 created 24 tasks
+		config.QuotasMaxTasksPerUser.Set(24)
+		defer config.QuotasMaxTasksPerUser.Set(0)
+
+		err := (&Task{Title: "Lorem", ProjectID: 1}).Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrUserTaskQuotaExceeded(err))
+
+		// Link shares don't have a limit of their own
+		share := &LinkSharing{ID: 2, ProjectID: 1, Right: RightWrite, SharedByID: 1}
+		err = (&Task{Title: "Lorem", ProjectID: 1}).Create(s, share)
+		require.NoError(t, err)
+
+		config.QuotasMaxTasksPerUser.Set(25)
+		err = (&Task{Title: "Lorem", ProjectID: 1}).Create(s, u)
+		require.NoError(t, err)
+	})
+	t.Run("link shares", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 1 created 4 link shares
+		config.QuotasMaxLinkSharesPerUser.Set(4)
+		defer config.QuotasMaxLinkSharesPerUser.Set(0)
+
+		err := (&LinkSharing{ProjectID: 1, Right: RightRead}).Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrUserLinkShareQuotaExceeded(err))
+
+		config.QuotasMaxLinkSharesPerUser.Set(5)
+		err = (&LinkSharing{ProjectID: 1, Right: RightRead}).Create(s, u)
+		require.NoError(t, err)
+	})
+}
@@ -18,6 +18,7 @@ package models
 
 import (
 	"errors"
+	"slices"
 
 	"code.vikunja.io/api/pkg/user"
 	"code.vikunja.io/web"
@@ -27,9 +28,15 @@ import (
 
 // CanWrite return whether the user can write on that project or not
 func (p *Project) CanWrite(s *xorm.Session, a web.Auth) (bool, error) {
+	return p.canWrite(s, a, false)
+}
+
+// canWrite checks write access to a project. If ignoreFrozen is true, the project being frozen is not reported
+// as error. Only things which are still allowed in frozen projects, like commenting, should do that.
+func (p *Project) canWrite(s *xorm.Session, a web.Auth, ignoreFrozen bool) (bool, error) {
 
 	// The favorite project can't be edited
-	if p.ID == FavoritesPseudoProject.ID {
+	if p.ID == FavoritesPseudoProject.ID || isOutsideAPITokenProjects(a, p.ID) {
 		return false, nil
 	}
 
@@ -39,9 +46,12 @@ func (p *Project) CanWrite(s *xorm.Session, a web.Auth) (bool, error) {
 		return false, err
 	}
 
-	// We put the result of the is archived check in a separate variable to be able to return it later without
-	// needing to recheck it again
+	// We put the result of the is archived and frozen checks in a separate variable to be able to return it later
+	// without needing to recheck it again
 	errIsArchived := originalProject.CheckIsArchived(s)
+	if errIsArchived == nil && !ignoreFrozen {
+		errIsArchived = originalProject.checkIsFrozen(s)
+	}
 
 	var canWrite bool
 
@@ -71,6 +81,10 @@ func (p *Project) CanWrite(s *xorm.Session, a web.Auth) (bool, error) {
 // CanRead checks if a user has read access to a project
 func (p *Project) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
 
+	if isOutsideAPITokenProjects(a, p.ID) {
+		return false, 0, nil
+	}
+
 	// The favorite project needs a special treatment
 	if p.ID == FavoritesPseudoProject.ID {
 		owner, err := user.GetFromAuth(a)
@@ -154,6 +168,13 @@ func (p *Project) CanUpdate(s *xorm.Session, a web.Auth) (canUpdate bool, err er
 	if is && !p.IsArchived && archivedErr.ProjectID == p.ID {
 		err = nil
 	}
+	// The same goes for unfreezing a frozen project. Only admins are allowed to do that, which is checked
+	// when updating the project.
+	frozenErr := ErrProjectIsFrozen{}
+	is = errors.As(err, &frozenErr)
+	if is && !p.IsFrozen && frozenErr.ProjectID == p.ID {
+		err = nil
+	}
 	return canUpdate, err
 }
 
@@ -173,13 +194,17 @@ func (p *Project) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
 	if is {
 		return false, nil
 	}
+	// Tokens limited to projects can't create new top level projects
+	if u, is := a.(*user.User); is && len(u.APITokenProjectIDs) > 0 {
+		return false, nil
+	}
 	return true, nil
 }
 
 // IsAdmin returns whether the user has admin rights on the project or not
 func (p *Project) IsAdmin(s *xorm.Session, a web.Auth) (bool, error) {
 	// The favorite project can't be edited
-	if p.ID == FavoritesPseudoProject.ID {
+	if p.ID == FavoritesPseudoProject.ID || isOutsideAPITokenProjects(a, p.ID) {
 		return false, nil
 	}
 
@@ -204,6 +229,14 @@ func (p *Project) IsAdmin(s *xorm.Session, a web.Auth) (bool, error) {
 	return is, err
 }
 
+// isOutsideAPITokenProjects returns true if the request was authenticated with an api token which is limited to
+// other projects. The routes of a limited token are checked as well, but ids in the request body like the project
+// of a task are only checked here.
+func isOutsideAPITokenProjects(a web.Auth, projectID int64) bool {
+	u, is := a.(*user.User)
+	return is && len(u.APITokenProjectIDs) > 0 && !slices.Contains(u.APITokenProjectIDs, projectID)
+}
+
 // Little helper function to check if a user is project owner
 func (p *Project) isOwner(u *user.User) bool {
 	return p.OwnerID == u.ID
@@ -0,0 +1,338 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+const (
+	defaultProjectStatisticsDays = 30
+	maxProjectStatisticsDays     = 365
+)
+
+// ProjectStatistics holds aggregated numbers about the tasks in a project
+type ProjectStatistics struct {
+	// The project these statistics are about.
+	ProjectID int64 `json:"-" param:"project"`
+	// How many days the timeline should cover. Defaults to 30, can be at most 365.
+	Days int `json:"-" query:"days"`
+
+	// The number of tasks which are not done.
+	Open int64 `json:"open"`
+	// The number of tasks which are done.
+	Done int64 `json:"done"`
+	// The number of undone tasks with a due date in the past.
+	Overdue int64 `json:"overdue"`
+
+	// The number of tasks in each bucket of all kanban views of the project.
+	Buckets []*ProjectBucketStatistics `json:"buckets"`
+	// The number of open and done tasks per assignee.
+	Assignees []*ProjectAssigneeStatistics `json:"assignees"`
+	// How many tasks were created and completed on each of the last days, oldest first.
+	Timeline []*ProjectTimelineStatistics `json:"timeline"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// ProjectBucketStatistics holds the number of tasks in a bucket
+type ProjectBucketStatistics struct {
+	BucketID      int64  `json:"bucket_id"`
+	ProjectViewID int64  `json:"project_view_id"`
+	Title         string `json:"title"`
+	Count         int64  `json:"count"`
+}
+
+// ProjectAssigneeStatistics holds the number of tasks assigned to a user
+type ProjectAssigneeStatistics struct {
+	User *user.User `json:"user"`
+	Open int64      `json:"open"`
+	Done int64      `json:"done"`
+}
+
+// ProjectTimelineStatistics holds the number of tasks created and completed on one day
+type ProjectTimelineStatistics struct {
+	// The day in the format YYYY-MM-DD, in the timezone of the user.
+	Date      string `json:"date"`
+	Created   int64  `json:"created"`
+	Completed int64  `json:"completed"`
+}
+
+// CanRead checks if a user can see the statistics of a project
+func (ps *ProjectStatistics) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	p := &Project{ID: ps.ProjectID}
+	return p.CanRead(s, a)
+}
+
+// ReadOne returns the statistics of a project
+// @Summary Get project statistics
+// @Description Returns the number of open, done and overdue tasks of a project, how many tasks are in each bucket and assigned to each user and how many tasks were created and completed per day.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param days query int false "How many days the timeline should cover. Defaults to 30, can be at most 365."
+// @Success 200 {object} models.ProjectStatistics "The project statistics."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/stats [get]
+func (ps *ProjectStatistics) ReadOne(s *xorm.Session, a web.Auth) (err error) {
+	err = ps.addTaskCounts(s, time.Now())
+	if err != nil {
+		return err
+	}
+
+	err = ps.addBucketCounts(s)
+	if err != nil {
+		return err
+	}
+
+	err = ps.addAssigneeCounts(s)
+	if err != nil {
+		return err
+	}
+
+	tz, err := getTimezoneForAuth(s, a)
+	if err != nil {
+		return err
+	}
+
+	return ps.addTimeline(s, time.Now().In(tz))
+}
+
+// getStatisticsTimeframe limits the number of days a timeline covers and returns the start of the first day.
+func getStatisticsTimeframe(days int, now time.Time) (int, time.Time) {
+	if days <= 0 {
+		days = defaultProjectStatisticsDays
+	}
+	if days > maxProjectStatisticsDays {
+		days = maxProjectStatisticsDays
+	}
+
+	y, m, d := now.Date()
+	return days, time.Date(y, m, d, 0, 0, 0, 0, now.Location()).AddDate(0, 0, -(days - 1))
+}
+
+// getTimezoneForAuth returns the timezone of the user, or the configured default timezone for link shares and
+// users without one.
+func getTimezoneForAuth(s *xorm.Session, a web.Auth) (*time.Location, error) {
+	if _, is := a.(*LinkSharing); is {
+		return config.GetTimeZone(), nil
+	}
+
+	u, err := user.GetUserByID(s, a.GetID())
+	if err != nil {
+		return nil, err
+	}
+	if u.Timezone == "" {
+		return config.GetTimeZone(), nil
+	}
+	return time.LoadLocation(u.Timezone)
+}
+
+func (ps *ProjectStatistics) addTaskCounts(s *xorm.Session, now time.Time) (err error) {
+	counts := []*struct {
+		Done  bool
+		Count int64
+	}{}
+	err = s.
+		Table("tasks").
+		Select("done, COUNT(*) AS count").
+		Where("project_id = ?", ps.ProjectID).
+		GroupBy("done").
+		Find(&counts)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range counts {
+		if c.Done {
+			ps.Done += c.Count
+			continue
+		}
+		ps.Open += c.Count
+	}
+
+	ps.Overdue, err = s.
+		Where(builder.And(
+			builder.Eq{"project_id": ps.ProjectID},
+			builder.Eq{"done": false},
+			builder.NotNull{"due_date"},
+			builder.Lt{"due_date": now},
+		)).
+		Count(&Task{})
+	return err
+}
+
+func (ps *ProjectStatistics) addBucketCounts(s *xorm.Session) (err error) {
+	buckets := []*Bucket{}
+	err = s.
+		In("project_view_id", builder.Select("id").From("project_views").Where(builder.Eq{"project_id": ps.ProjectID})).
+		OrderBy("project_view_id asc, position asc").
+		Find(&buckets)
+	if err != nil {
+		return err
+	}
+
+	ps.Buckets = make([]*ProjectBucketStatistics, 0, len(buckets))
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	bucketIDs := make([]int64, 0, len(buckets))
+	for _, b := range buckets {
+		bucketIDs = append(bucketIDs, b.ID)
+	}
+
+	counts := []*struct {
+		BucketID int64
+		Count    int64
+	}{}
+	err = s.
+		Table("task_buckets").
+		Select("bucket_id, COUNT(*) AS count").
+		In("bucket_id", bucketIDs).
+		GroupBy("bucket_id").
+		Find(&counts)
+	if err != nil {
+		return err
+	}
+
+	countsByBucket := make(map[int64]int64, len(counts))
+	for _, c := range counts {
+		countsByBucket[c.BucketID] = c.Count
+	}
+
+	for _, b := range buckets {
+		ps.Buckets = append(ps.Buckets, &ProjectBucketStatistics{
+			BucketID:      b.ID,
+			ProjectViewID: b.ProjectViewID,
+			Title:         b.Title,
+			Count:         countsByBucket[b.ID],
+		})
+	}
+
+	return nil
+}
+
+func (ps *ProjectStatistics) addAssigneeCounts(s *xorm.Session) (err error) {
+	counts := []*struct {
+		UserID int64
+		Done   bool
+		Count  int64
+	}{}
+	err = s.
+		Table("task_assignees").
+		Select("task_assignees.user_id, tasks.done, COUNT(*) AS count").
+		Join("INNER", "tasks", "tasks.id = task_assignees.task_id").
+		Where("tasks.project_id = ?", ps.ProjectID).
+		GroupBy("task_assignees.user_id, tasks.done").
+		OrderBy("task_assignees.user_id asc").
+		Find(&counts)
+	if err != nil {
+		return err
+	}
+
+	ps.Assignees = []*ProjectAssigneeStatistics{}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, 0, len(counts))
+	for _, c := range counts {
+		userIDs = append(userIDs, c.UserID)
+	}
+
+	users, err := user.GetUsersByIDs(s, userIDs)
+	if err != nil {
+		return err
+	}
+
+	byUser := make(map[int64]*ProjectAssigneeStatistics, len(users))
+	for _, c := range counts {
+		stats, has := byUser[c.UserID]
+		if !has {
+			u, exists := users[c.UserID]
+			if !exists {
+				continue
+			}
+			stats = &ProjectAssigneeStatistics{User: u}
+			byUser[c.UserID] = stats
+			ps.Assignees = append(ps.Assignees, stats)
+		}
+
+		if c.Done {
+			stats.Done += c.Count
+			continue
+		}
+		stats.Open += c.Count
+	}
+
+	return nil
+}
+
+// addTimeline counts the tasks created and completed per day. The days depend on the timezone of the user, which
+// is why the timestamps are grouped here and not in the database.
+func (ps *ProjectStatistics) addTimeline(s *xorm.Session, now time.Time) (err error) {
+	days, since := getStatisticsTimeframe(ps.Days, now)
+
+	ps.Timeline = make([]*ProjectTimelineStatistics, 0, days)
+	byDate := make(map[string]*ProjectTimelineStatistics, days)
+	for i := 0; i < days; i++ {
+		day := &ProjectTimelineStatistics{Date: since.AddDate(0, 0, i).Format(time.DateOnly)}
+		ps.Timeline = append(ps.Timeline, day)
+		byDate[day.Date] = day
+	}
+
+	created := []*Task{}
+	err = s.
+		Cols("created").
+		Where("project_id = ? AND created >= ?", ps.ProjectID, since).
+		Find(&created)
+	if err != nil {
+		return err
+	}
+	for _, t := range created {
+		if day, has := byDate[t.Created.In(now.Location()).Format(time.DateOnly)]; has {
+			day.Created++
+		}
+	}
+
+	completed := []*Task{}
+	err = s.
+		Cols("done_at").
+		Where("project_id = ? AND done_at >= ?", ps.ProjectID, since).
+		Find(&completed)
+	if err != nil {
+		return err
+	}
+	for _, t := range completed {
+		if day, has := byDate[t.DoneAt.In(now.Location()).Format(time.DateOnly)]; has {
+			day.Completed++
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectStatistics_ReadOne(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ps := &ProjectStatistics{ProjectID: 1}
+		can, _, err := ps.CanRead(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = ps.ReadOne(s, u)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(17), ps.Open)
+		assert.Equal(t, int64(1), ps.Done)
+		assert.Equal(t, int64(2), ps.Overdue)
+
+		bucketCounts := map[int64]int64{}
+		for _, b := range ps.Buckets {
+			bucketCounts[b.BucketID] = b.Count
+		}
+		assert.Equal(t, map[int64]int64{1: 11, 2: 3, 3: 4}, bucketCounts)
+
+		require.Len(t, ps.Assignees, 2)
+		assert.Equal(t, int64(1), ps.Assignees[0].User.ID)
+		assert.Equal(t, int64(1), ps.Assignees[0].Open)
+		assert.Equal(t, int64(2), ps.Assignees[1].User.ID)
+		assert.Equal(t, int64(1), ps.Assignees[1].Open)
+
+		assert.Len(t, ps.Timeline, defaultProjectStatisticsDays)
+	})
+	t.Run("timeline", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{Title: "new", ProjectID: 1}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+		task.Done = true
+		err = task.Update(s, u)
+		require.NoError(t, err)
+
+		ps := &ProjectStatistics{ProjectID: 1, Days: 7}
+		err = ps.addTimeline(s, time.Now())
+		require.NoError(t, err)
+
+		require.Len(t, ps.Timeline, 7)
+		today := ps.Timeline[6]
+		assert.Equal(t, time.Now().Format(time.DateOnly), today.Date)
+		assert.Equal(t, int64(1), today.Created)
+		assert.Equal(t, int64(1), today.Completed)
+		assert.Equal(t, int64(0), ps.Timeline[0].Created)
+	})
+	t.Run("no access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ps := &ProjectStatistics{ProjectID: 1}
+		can, _, _ := ps.CanRead(s, &user.User{ID: 2})
+		assert.False(t, can)
+	})
+}
@@ -37,6 +37,8 @@ type TeamProject struct {
 	ProjectID int64 `xorm:"bigint not null INDEX" json:"-" param:"project"`
 	// The right this team has. 0 = Read only, 1 = Read & Write, 2 = Admin. See the docs for more details.
 	Right Right `xorm:"bigint INDEX not null default 0" json:"right" valid:"length(0|2)" maximum:"2" default:"0"`
+	// The id of a role which gives this team more capabilities than its right. 0 means no role.
+	RoleID int64 `xorm:"bigint null INDEX" json:"role_id"`
 
 	// A timestamp when this relation was created. You cannot change this value.
 	Created time.Time `xorm:"created not null" json:"created"`
@@ -54,8 +56,9 @@ func (TeamProject) TableName() string {
 
 // TeamWithRight represents a team, combined with rights.
 type TeamWithRight struct {
-	Team  `xorm:"extends"`
-	Right Right `json:"right"`
+	Team   `xorm:"extends"`
+	Right  Right `json:"right"`
+	RoleID int64 `json:"role_id"`
 }
 
 // Create creates a new team <-> project relation
@@ -80,6 +83,10 @@ func (tl *TeamProject) Create(s *xorm.Session, a web.Auth) (err error) {
 		return
 	}
 
+	if err = checkRoleExists(s, tl.RoleID); err != nil {
+		return
+	}
+
 	// Check if the team exists
 	team, err := GetTeamByID(s, tl.TeamID)
 	if err != nil {
@@ -254,9 +261,13 @@ func (tl *TeamProject) Update(s *xorm.Session, _ web.Auth) (err error) {
 		return err
 	}
 
+	if err := checkRoleExists(s, tl.RoleID); err != nil {
+		return err
+	}
+
 	_, err = s.
 		Where("project_id = ? AND team_id = ?", tl.ProjectID, tl.TeamID).
-		Cols("right").
+		Cols("right", "role_id").
 		Update(tl)
 	if err != nil {
 		return err
@@ -43,5 +43,25 @@ func (tl *TeamProject) canDoTeamProject(s *xorm.Session, a web.Auth) (bool, erro
 	}
 
 	l := Project{ID: tl.ProjectID}
-	return l.IsAdmin(s, a)
+	isAdmin, err := l.IsAdmin(s, a)
+	if err != nil || isAdmin {
+		return isAdmin, err
+	}
+
+	canManage, err := l.hasCapability(s, a, CapabilityManageShares)
+	if err != nil || !canManage {
+		return false, err
+	}
+
+	// Shares with admin rights can only be changed by admins
+	existing := &TeamProject{}
+	has, err := s.Where("project_id = ? AND team_id = ?", tl.ProjectID, tl.TeamID).Get(existing)
+	if err != nil {
+		return false, err
+	}
+	if has && existing.Right == RightAdmin {
+		return false, nil
+	}
+
+	return l.canGrant(s, a, tl.Right, tl.RoleID)
 }
@@ -0,0 +1,110 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// ProjectFromTemplate holds everything needed to create a new project from a template
+type ProjectFromTemplate struct {
+	// The id of the template project
+	TemplateID int64 `json:"-" param:"template"`
+	// The parent project of the new project. If not set, the new project will be a top-level project.
+	ParentProjectID int64 `json:"parent_project_id,omitempty"`
+	// The title of the new project. If empty, the title of the template is used.
+	Title string `json:"title"`
+
+	// The created project
+	Project *Project `json:"project,omitempty"`
+
+	duplicate *ProjectDuplicate
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// CanCreate checks if a user can create a project from a template
+func (pt *ProjectFromTemplate) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	pt.duplicate = &ProjectDuplicate{
+		ProjectID:       pt.TemplateID,
+		ParentProjectID: pt.ParentProjectID,
+		// Comments and shares belong to the template itself and should not end up in projects created from it
+		SkipComments: true,
+		SkipShares:   true,
+		fromTemplate: true,
+	}
+	can, err := pt.duplicate.CanCreate(s, a)
+	if err != nil || !can {
+		return can, err
+	}
+
+	if !pt.duplicate.Project.IsTemplate {
+		return false, &ErrProjectIsNotATemplate{ProjectID: pt.TemplateID}
+	}
+
+	return true, nil
+}
+
+// Create creates a new project from a template
+// @Summary Create a project from a template
+// @Description Creates a new project with the views, buckets, labels and tasks of a template project. Dates, reminders and assignees of the tasks are not copied, neither are comments or shares. The user needs read access to the template and write access in the parent of the new project.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param template path int true "The id of the template project"
+// @Param project body models.ProjectFromTemplate true "The parent project and title of the new project."
+// @Success 201 {object} models.ProjectFromTemplate "The created project."
+// @Failure 400 {object} web.HTTPError "Invalid project object provided or the project is not a template."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the template or the parent project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/from-template/{template} [put]
+func (pt *ProjectFromTemplate) Create(s *xorm.Session, a web.Auth) (err error) {
+	pd := pt.duplicate
+	pd.Project.IsTemplate = false
+	if pt.Title != "" {
+		pd.Project.Title = pt.Title
+	}
+
+	err = pd.duplicate(s, a)
+	if err != nil {
+		return err
+	}
+
+	pt.Project = pd.Project
+	return nil
+}
+
+// stripForTemplate removes everything from a task which is specific to one instance of it,
+// only leaving what can be reused when creating new tasks from a template.
+func (t *Task) stripForTemplate() {
+	t.Done = false
+	t.DoneAt = time.Time{}
+	t.setDoneBy(nil)
+	t.DueDate = time.Time{}
+	t.StartDate = time.Time{}
+	t.EndDate = time.Time{}
+	t.Reminders = nil
+	t.Assignees = nil
+	t.ApprovalStatus = TaskApprovalStatusNone
+	t.PercentDone = 0
+}
@@ -0,0 +1,100 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/builder"
+)
+
+func TestProjectFromTemplate_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Where("id = ?", 1).Cols("is_template").Update(&Project{IsTemplate: true})
+		require.NoError(t, err)
+
+		pt := &ProjectFromTemplate{
+			TemplateID: 1,
+			Title:      "From template",
+		}
+		can, err := pt.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = pt.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		require.NotNil(t, pt.Project)
+		assert.NotEqual(t, int64(1), pt.Project.ID)
+		assert.Equal(t, "From template", pt.Project.Title)
+		assert.False(t, pt.Project.IsTemplate)
+
+		originalTasks, err := s.Where("project_id = ?", 1).Count(&Task{})
+		require.NoError(t, err)
+		db.AssertCount(t, "tasks", builder.Eq{"project_id": pt.Project.ID}, originalTasks)
+		db.AssertCount(t, "tasks", builder.And(
+			builder.Eq{"project_id": pt.Project.ID},
+			builder.Or(
+				builder.Eq{"done": true},
+				builder.NotNull{"due_date"},
+			),
+		), 0)
+		db.AssertCount(t, "task_assignees", builder.In("task_id", builder.Select("id").From("tasks").Where(builder.Eq{"project_id": pt.Project.ID})), 0)
+		db.AssertCount(t, "task_comments", builder.In("task_id", builder.Select("id").From("tasks").Where(builder.Eq{"project_id": pt.Project.ID})), 0)
+		db.AssertMissing(t, "users_projects", map[string]interface{}{
+			"project_id": pt.Project.ID,
+		})
+
+		originalViews, err := s.Where("project_id = ?", 1).Count(&ProjectView{})
+		require.NoError(t, err)
+		db.AssertCount(t, "project_views", builder.Eq{"project_id": pt.Project.ID}, originalViews)
+	})
+	t.Run("not a template", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pt := &ProjectFromTemplate{TemplateID: 1}
+		can, err := pt.CanCreate(s, u)
+		require.Error(t, err)
+		assert.False(t, can)
+		assert.True(t, IsErrProjectIsNotATemplate(err))
+	})
+	t.Run("no access to the template", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pt := &ProjectFromTemplate{TemplateID: 1}
+		can, _ := pt.CanCreate(s, &user.User{ID: 2})
+		assert.False(t, can)
+	})
+}
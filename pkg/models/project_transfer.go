@@ -0,0 +1,356 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/user"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// ProjectTransfer is a pending request to hand a project over to another user. The project only changes
+// its owner once the receiving user accepted the transfer.
+type ProjectTransfer struct {
+	// The unique, numeric id of this transfer.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id"`
+	// The project which should be transferred. A project can only have one pending transfer at a time.
+	ProjectID int64 `xorm:"bigint not null unique" json:"project_id" param:"project"`
+
+	// The username of the user who should become the new owner of the project.
+	Username string     `xorm:"-" json:"username"`
+	ToUserID int64      `xorm:"bigint not null INDEX" json:"-"`
+	ToUser   *user.User `xorm:"-" json:"to_user" valid:"-"`
+
+	// The owner of the project at the time the transfer was requested.
+	FromUserID int64      `xorm:"bigint not null" json:"-"`
+	FromUser   *user.User `xorm:"-" json:"from_user" valid:"-"`
+
+	// If true, the current owner gets an admin share on the project once the transfer was accepted.
+	KeepAccess bool `xorm:"not null default false" json:"keep_access"`
+
+	// A timestamp when this transfer was requested. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName holds the table name
+func (*ProjectTransfer) TableName() string {
+	return "project_transfers"
+}
+
+func getProjectTransferByProjectID(s *xorm.Session, projectID int64) (transfer *ProjectTransfer, err error) {
+	transfer = &ProjectTransfer{}
+	exists, err := s.Where("project_id = ?", projectID).Get(transfer)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrProjectTransferDoesNotExist{ProjectID: projectID}
+	}
+	return
+}
+
+func (pt *ProjectTransfer) addUsers(s *xorm.Session) (err error) {
+	users, err := user.GetUsersByIDs(s, []int64{pt.FromUserID, pt.ToUserID})
+	if err != nil {
+		return err
+	}
+	pt.FromUser = users[pt.FromUserID]
+	pt.ToUser = users[pt.ToUserID]
+	if pt.ToUser != nil {
+		pt.Username = pt.ToUser.Username
+	}
+	return nil
+}
+
+// Create requests the transfer of a project to another user
+// @Summary Transfer a project to another user
+// @Description Requests to make another user the owner of a project. The user will be notified and needs to accept the transfer before the owner changes. Only the current owner can request a transfer, an existing pending transfer of the project is replaced.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param transfer body models.ProjectTransfer true "The user to transfer the project to."
+// @Success 201 {object} models.ProjectTransfer "The pending transfer."
+// @Failure 400 {object} web.HTTPError "The project is already owned by that user."
+// @Failure 403 {object} web.HTTPError "The user is not the owner of the project."
+// @Failure 404 {object} web.HTTPError "The user does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/transfer [post]
+func (pt *ProjectTransfer) Create(s *xorm.Session, a web.Auth) (err error) {
+	project, err := GetProjectSimpleByID(s, pt.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	target, err := user.GetUserByUsername(s, pt.Username)
+	if err != nil {
+		return err
+	}
+
+	if target.ID == project.OwnerID {
+		return &ErrCannotTransferProjectToOwner{ProjectID: project.ID, UserID: target.ID}
+	}
+
+	_, err = s.Where("project_id = ?", project.ID).Delete(&ProjectTransfer{})
+	if err != nil {
+		return err
+	}
+
+	pt.ID = 0
+	pt.FromUserID = project.OwnerID
+	pt.ToUserID = target.ID
+	_, err = s.Insert(pt)
+	if err != nil {
+		return err
+	}
+
+	err = pt.addUsers(s)
+	if err != nil {
+		return err
+	}
+
+	return events.Dispatch(&ProjectTransferRequestedEvent{
+		Project: project,
+		From:    pt.FromUser,
+		To:      pt.ToUser,
+	})
+}
+
+// ReadOne returns the pending transfer of a project
+// @Summary Get the pending transfer of a project
+// @Description Returns the pending ownership transfer of a project. Only available to the current owner and the user the project should be transferred to.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Success 200 {object} models.ProjectTransfer "The pending transfer."
+// @Failure 403 {object} web.HTTPError "The user is not part of the transfer."
+// @Failure 404 {object} web.HTTPError "There is no pending transfer for this project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/transfer [get]
+func (pt *ProjectTransfer) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	transfer, err := getProjectTransferByProjectID(s, pt.ProjectID)
+	if err != nil {
+		return err
+	}
+	*pt = *transfer
+	return pt.addUsers(s)
+}
+
+// Delete cancels or declines a pending transfer
+// @Summary Cancel or decline a project transfer
+// @Description Removes the pending ownership transfer of a project. The current owner can use this to cancel the transfer, the receiving user to decline it.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Success 200 {object} models.Message "The transfer was removed."
+// @Failure 403 {object} web.HTTPError "The user is not part of the transfer."
+// @Failure 404 {object} web.HTTPError "There is no pending transfer for this project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/transfer [delete]
+func (pt *ProjectTransfer) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.Where("project_id = ?", pt.ProjectID).Delete(&ProjectTransfer{})
+	return
+}
+
+// AcceptProjectTransfer makes the doer the new owner of a project with a pending transfer to them.
+// If the new owner can't see the parent project, the project is moved to the top level. Shares of the new
+// owner become redundant and are removed, the previous owner keeps an admin share if the transfer asked for it.
+func AcceptProjectTransfer(s *xorm.Session, projectID int64, a web.Auth) (project *Project, err error) {
+	if _, is := a.(*LinkSharing); is {
+		return nil, ErrGenericForbidden{}
+	}
+
+	transfer, err := getProjectTransferByProjectID(s, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.ToUserID != a.GetID() {
+		return nil, ErrGenericForbidden{}
+	}
+
+	project, err = GetProjectSimpleByID(s, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	newOwner, err := user.GetUserByID(s, transfer.ToUserID)
+	if err != nil {
+		return nil, err
+	}
+	previousOwnerID := project.OwnerID
+
+	err = checkUserProjectQuota(s, newOwner.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if project.ParentProjectID != 0 {
+		parent := &Project{ID: project.ParentProjectID}
+		canRead, _, err := parent.CanRead(s, newOwner)
+		if err != nil {
+			return nil, err
+		}
+		if !canRead {
+			project.ParentProjectID = 0
+		}
+	}
+
+	project.OwnerID = newOwner.ID
+	_, err = s.
+		ID(project.ID).
+		Cols("owner_id", "parent_project_id").
+		Update(project)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.
+		Where("project_id = ? AND user_id = ?", project.ID, newOwner.ID).
+		Delete(&ProjectUser{})
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.KeepAccess {
+		err = keepAdminAccessAfterTransfer(s, project.ID, previousOwnerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The previous owner can't use a project they might not have access to anymore as their default project
+	_, err = s.
+		Where("id = ? AND default_project_id = ?", previousOwnerID, project.ID).
+		Cols("default_project_id").
+		Update(&user.User{DefaultProjectID: 0})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.Where("project_id = ?", project.ID).Delete(&ProjectTransfer{})
+	if err != nil {
+		return nil, err
+	}
+
+	project.Owner = newOwner
+	err = events.Dispatch(&ProjectUpdatedEvent{
+		Project: project,
+		Doer:    newOwner,
+	})
+	return project, err
+}
+
+func keepAdminAccessAfterTransfer(s *xorm.Session, projectID, userID int64) error {
+	existing := &ProjectUser{}
+	exists, err := s.
+		Where("project_id = ? AND user_id = ?", projectID, userID).
+		Get(existing)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		existing.Right = RightAdmin
+		existing.RoleID = 0
+		_, err = s.ID(existing.ID).Cols("right", "role_id").Update(existing)
+		return err
+	}
+
+	_, err = s.Insert(&ProjectUser{
+		ProjectID: projectID,
+		UserID:    userID,
+		Right:     RightAdmin,
+	})
+	return err
+}
+
+// TransferOwnedProjects makes another user the owner of all projects the user owns, without asking the new owner
+// to accept. It is used by admins before deleting a user so their projects are kept.
+// Projects whose parent the new owner can't see are moved to the top level.
+func TransferOwnedProjects(s *xorm.Session, from, to *user.User) (err error) {
+	if from.ID == to.ID {
+		return &ErrCannotTransferProjectToOwner{UserID: to.ID}
+	}
+
+	projects := []*Project{}
+	err = s.Where("owner_id = ?", from.ID).Find(&projects)
+	if err != nil {
+		return err
+	}
+
+	err = checkUserProjectQuotaFor(s, to.ID, int64(len(projects)))
+	if err != nil {
+		return err
+	}
+
+	ownedIDs := make(map[int64]bool, len(projects))
+	for _, p := range projects {
+		ownedIDs[p.ID] = true
+	}
+
+	for _, project := range projects {
+		if project.ParentProjectID != 0 && !ownedIDs[project.ParentProjectID] {
+			parent := &Project{ID: project.ParentProjectID}
+			canRead, _, err := parent.CanRead(s, to)
+			if err != nil {
+				return err
+			}
+			if !canRead {
+				project.ParentProjectID = 0
+			}
+		}
+
+		project.OwnerID = to.ID
+		_, err = s.
+			ID(project.ID).
+			Cols("owner_id", "parent_project_id").
+			Update(project)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.
+			Where("project_id = ? AND user_id = ?", project.ID, to.ID).
+			Delete(&ProjectUser{})
+		if err != nil {
+			return err
+		}
+
+		_, err = s.Where("project_id = ?", project.ID).Delete(&ProjectTransfer{})
+		if err != nil {
+			return err
+		}
+	}
+
+	if ownedIDs[from.DefaultProjectID] {
+		_, err = s.
+			Where("id = ?", from.ID).
+			Cols("default_project_id").
+			Update(&user.User{DefaultProjectID: 0})
+	}
+	return err
+}
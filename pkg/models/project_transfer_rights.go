@@ -0,0 +1,67 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanCreate checks if a user can request the transfer of a project. Only the owner of a project can do that.
+func (pt *ProjectTransfer) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	project, err := GetProjectSimpleByID(s, pt.ProjectID)
+	if err != nil {
+		return false, err
+	}
+	return project.OwnerID == a.GetID(), nil
+}
+
+// CanRead checks if a user can see the pending transfer of a project
+func (pt *ProjectTransfer) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	can, err := pt.isPartOfTransfer(s, a)
+	if err != nil || !can {
+		return false, 0, err
+	}
+	return true, int(RightRead), nil
+}
+
+// CanDelete checks if a user can cancel or decline the pending transfer of a project
+func (pt *ProjectTransfer) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return pt.isPartOfTransfer(s, a)
+}
+
+func (pt *ProjectTransfer) isPartOfTransfer(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	transfer, err := getProjectTransferByProjectID(s, pt.ProjectID)
+	if err != nil {
+		return false, err
+	}
+
+	project, err := GetProjectSimpleByID(s, pt.ProjectID)
+	if err != nil {
+		return false, err
+	}
+
+	return project.OwnerID == a.GetID() || transfer.ToUserID == a.GetID(), nil
+}
@@ -0,0 +1,200 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectTransfer(t *testing.T) {
+	owner := &user.User{ID: 1}
+	receiver := &user.User{ID: 2}
+
+	t.Run("only the owner can request a transfer", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 1 has write access to project 10 but does not own it
+		can, err := (&ProjectTransfer{ProjectID: 10}).CanCreate(s, owner)
+		require.NoError(t, err)
+		assert.False(t, can)
+
+		can, err = (&ProjectTransfer{ProjectID: 1}).CanCreate(s, owner)
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("to the current owner", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&ProjectTransfer{ProjectID: 1, Username: "user1"}).Create(s, owner)
+		require.Error(t, err)
+		assert.True(t, IsErrCannotTransferProjectToOwner(err))
+	})
+	t.Run("accept", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		transfer := &ProjectTransfer{ProjectID: 1, Username: "user2", KeepAccess: true}
+		err := transfer.Create(s, owner)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), transfer.ToUser.ID)
+
+		// The owner does not change before the transfer was accepted
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":       1,
+			"owner_id": 1,
+		}, false)
+
+		// Only the receiving user can accept
+		_, err = AcceptProjectTransfer(s, 1, owner)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+
+		project, err := AcceptProjectTransfer(s, 1, receiver)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), project.OwnerID)
+
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":       1,
+			"owner_id": 2,
+		}, false)
+		db.AssertExists(t, "users_projects", map[string]interface{}{
+			"project_id": 1,
+			"user_id":    1,
+			"right":      RightAdmin,
+		}, false)
+		db.AssertMissing(t, "project_transfers", map[string]interface{}{
+			"project_id": 1,
+		})
+	})
+	t.Run("project quota of the receiving user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		config.QuotasMaxProjectsPerUser.Set(1)
+		defer config.QuotasMaxProjectsPerUser.Set(0)
+		_, err := s.ID(2).Cols("owner_id").Update(&Project{OwnerID: 2})
+		require.NoError(t, err)
+
+		err = (&ProjectTransfer{ProjectID: 1, Username: "user2"}).Create(s, owner)
+		require.NoError(t, err)
+
+		_, err = AcceptProjectTransfer(s, 1, receiver)
+		require.Error(t, err)
+		assert.True(t, IsErrUserProjectQuotaExceeded(err))
+	})
+	t.Run("moves the project out of an inaccessible parent", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// Project 21 is a child of project 22 which user 2 can't access
+		err := (&ProjectTransfer{ProjectID: 21, Username: "user2"}).Create(s, owner)
+		require.NoError(t, err)
+
+		_, err = AcceptProjectTransfer(s, 21, receiver)
+		require.NoError(t, err)
+
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":                21,
+			"owner_id":          2,
+			"parent_project_id": 0,
+		}, false)
+		db.AssertMissing(t, "users_projects", map[string]interface{}{
+			"project_id": 21,
+			"user_id":    1,
+		})
+	})
+	t.Run("decline", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&ProjectTransfer{ProjectID: 1, Username: "user2"}).Create(s, owner)
+		require.NoError(t, err)
+
+		can, err := (&ProjectTransfer{ProjectID: 1}).CanDelete(s, receiver)
+		require.NoError(t, err)
+		assert.True(t, can)
+
+		err = (&ProjectTransfer{ProjectID: 1}).Delete(s, receiver)
+		require.NoError(t, err)
+
+		_, err = AcceptProjectTransfer(s, 1, receiver)
+		require.Error(t, err)
+		assert.True(t, IsErrProjectTransferDoesNotExist(err))
+	})
+}
+
+func TestTransferOwnedProjects(t *testing.T) {
+	t.Run("transfers all projects", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := TransferOwnedProjects(s, &user.User{ID: 6}, &user.User{ID: 2})
+		require.NoError(t, err)
+
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":       24,
+			"owner_id": 2,
+		}, false)
+		// The parent was transferred as well, so the project stays where it is
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":                12,
+			"owner_id":          2,
+			"parent_project_id": 27,
+		}, false)
+		db.AssertMissing(t, "projects", map[string]interface{}{
+			"owner_id": 6,
+		})
+	})
+	t.Run("project quota of the new owner", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 6 owns more projects than this
+		config.QuotasMaxProjectsPerUser.Set(10)
+		defer config.QuotasMaxProjectsPerUser.Set(0)
+
+		err := TransferOwnedProjects(s, &user.User{ID: 6}, &user.User{ID: 2})
+		require.Error(t, err)
+		assert.True(t, IsErrUserProjectQuotaExceeded(err))
+	})
+	t.Run("to the same user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := TransferOwnedProjects(s, &user.User{ID: 6}, &user.User{ID: 6})
+		require.Error(t, err)
+		assert.True(t, IsErrCannotTransferProjectToOwner(err))
+	})
+}
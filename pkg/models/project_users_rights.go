@@ -17,6 +17,7 @@
 package models
 
 import (
+	"code.vikunja.io/api/pkg/user"
 	"code.vikunja.io/web"
 	"xorm.io/xorm"
 )
@@ -44,5 +45,29 @@ func (lu *ProjectUser) canDoProjectUser(s *xorm.Session, a web.Auth) (bool, erro
 
 	// Get the project and check if the user has write access on it
 	l := Project{ID: lu.ProjectID}
-	return l.IsAdmin(s, a)
+	isAdmin, err := l.IsAdmin(s, a)
+	if err != nil || isAdmin {
+		return isAdmin, err
+	}
+
+	canManage, err := l.hasCapability(s, a, CapabilityManageShares)
+	if err != nil || !canManage {
+		return false, err
+	}
+
+	// Shares with admin rights can only be changed by admins
+	u, err := user.GetUserByUsername(s, lu.Username)
+	if err != nil {
+		return false, err
+	}
+	existing := &ProjectUser{}
+	has, err := s.Where("project_id = ? AND user_id = ?", lu.ProjectID, u.ID).Get(existing)
+	if err != nil {
+		return false, err
+	}
+	if has && existing.Right == RightAdmin {
+		return false, nil
+	}
+
+	return l.canGrant(s, a, lu.Right, lu.RoleID)
 }
@@ -78,8 +78,28 @@ const (
 	BucketConfigurationModeNone BucketConfigurationModeKind = iota
 	BucketConfigurationModeManual
 	BucketConfigurationModeFilter
+	BucketConfigurationModeLabel
+	BucketConfigurationModeAssignee
+	BucketConfigurationModePriority
+	BucketConfigurationModeProject
 )
 
+// groupedBy returns by what the buckets of a view are generated, or SwimlaneModeNone if the view has manual or
+// filter buckets.
+func (p BucketConfigurationModeKind) groupedBy() SwimlaneModeKind {
+	switch p {
+	case BucketConfigurationModeLabel:
+		return SwimlaneModeLabel
+	case BucketConfigurationModeAssignee:
+		return SwimlaneModeAssignee
+	case BucketConfigurationModePriority:
+		return SwimlaneModePriority
+	case BucketConfigurationModeProject:
+		return SwimlaneModeProject
+	}
+	return SwimlaneModeNone
+}
+
 func (p *BucketConfigurationModeKind) MarshalJSON() ([]byte, error) {
 	switch *p {
 	case BucketConfigurationModeNone:
@@ -88,6 +108,14 @@ func (p *BucketConfigurationModeKind) MarshalJSON() ([]byte, error) {
 		return []byte(`"manual"`), nil
 	case BucketConfigurationModeFilter:
 		return []byte(`"filter"`), nil
+	case BucketConfigurationModeLabel:
+		return []byte(`"label"`), nil
+	case BucketConfigurationModeAssignee:
+		return []byte(`"assignee"`), nil
+	case BucketConfigurationModePriority:
+		return []byte(`"priority"`), nil
+	case BucketConfigurationModeProject:
+		return []byte(`"project"`), nil
 	}
 
 	return []byte(`null`), nil
@@ -107,6 +135,14 @@ func (p *BucketConfigurationModeKind) UnmarshalJSON(bytes []byte) error {
 		*p = BucketConfigurationModeManual
 	case "filter":
 		*p = BucketConfigurationModeFilter
+	case "label":
+		*p = BucketConfigurationModeLabel
+	case "assignee":
+		*p = BucketConfigurationModeAssignee
+	case "priority":
+		*p = BucketConfigurationModePriority
+	case "project":
+		*p = BucketConfigurationModeProject
 	default:
 		return fmt.Errorf("unknown bucket configuration mode kind: %s", value)
 	}
@@ -114,11 +150,74 @@ func (p *BucketConfigurationModeKind) UnmarshalJSON(bytes []byte) error {
 	return nil
 }
 
+type SwimlaneModeKind int
+
+const (
+	SwimlaneModeNone SwimlaneModeKind = iota
+	SwimlaneModeAssignee
+	SwimlaneModeLabel
+	SwimlaneModePriority
+	SwimlaneModeProject
+)
+
+func (p *SwimlaneModeKind) MarshalJSON() ([]byte, error) {
+	switch *p {
+	case SwimlaneModeNone:
+		return []byte(`"none"`), nil
+	case SwimlaneModeAssignee:
+		return []byte(`"assignee"`), nil
+	case SwimlaneModeLabel:
+		return []byte(`"label"`), nil
+	case SwimlaneModePriority:
+		return []byte(`"priority"`), nil
+	case SwimlaneModeProject:
+		return []byte(`"project"`), nil
+	}
+
+	return []byte(`null`), nil
+}
+
+func (p *SwimlaneModeKind) UnmarshalJSON(bytes []byte) error {
+	var value string
+	err := json.Unmarshal(bytes, &value)
+	if err != nil {
+		return err
+	}
+
+	switch value {
+	case "none":
+		*p = SwimlaneModeNone
+	case "assignee":
+		*p = SwimlaneModeAssignee
+	case "label":
+		*p = SwimlaneModeLabel
+	case "priority":
+		*p = SwimlaneModePriority
+	case "project":
+		*p = SwimlaneModeProject
+	default:
+		return fmt.Errorf("unknown swimlane mode kind: %s", value)
+	}
+
+	return nil
+}
+
 type ProjectViewBucketConfiguration struct {
 	Title  string
 	Filter string
 }
 
+// ProjectViewDoneBucketConfiguration configures what happens to tasks moved into or out of the done bucket of a view.
+type ProjectViewDoneBucketConfiguration struct {
+	// If true, the done date of a task which already was done is reset to the time it was moved into the done bucket.
+	// Tasks which were not done yet always get the time of the move as their done date.
+	SetDoneAtOnMove bool `json:"set_done_at_on_move"`
+	// Done tasks are hidden from the done bucket this many days after they were done. 0 keeps them forever.
+	ArchiveAfterDays int64 `json:"archive_after_days" minimum:"0" valid:"range(0|36500)"`
+	// If true, tasks moved out of the done bucket stay done instead of being reopened.
+	KeepDoneWhenMovedOut bool `json:"keep_done_when_moved_out"`
+}
+
 type ProjectView struct {
 	// The unique numeric id of this view
 	ID int64 `xorm:"autoincr not null unique pk" json:"id" param:"view"`
@@ -134,7 +233,7 @@ type ProjectView struct {
 	// The position of this view in the list. The list of all views will be sorted by this parameter.
 	Position float64 `xorm:"double null" json:"position"`
 
-	// The bucket configuration mode. Can be `none`, `manual` or `filter`. `manual` allows to move tasks between buckets as you normally would. `filter` creates buckets based on a filter for each bucket.
+	// The bucket configuration mode. Can be `none`, `manual`, `filter`, `label`, `assignee` or `priority`. `manual` allows to move tasks between buckets as you normally would. `filter` creates buckets based on a filter for each bucket. `label`, `assignee` and `priority` create one bucket per label, assignee or priority of the tasks in the view, moving a task between them changes that field of the task.
 	BucketConfigurationMode BucketConfigurationModeKind `xorm:"default 0" json:"bucket_configuration_mode"`
 	// When the bucket configuration mode is not `manual`, this field holds the options of that configuration.
 	BucketConfiguration []*ProjectViewBucketConfiguration `xorm:"json" json:"bucket_configuration"`
@@ -142,6 +241,21 @@ type ProjectView struct {
 	DefaultBucketID int64 `xorm:"bigint INDEX null" json:"default_bucket_id"`
 	// If tasks are moved to the done bucket, they are marked as done. If they are marked as done individually, they are moved into the done bucket.
 	DoneBucketID int64 `xorm:"bigint INDEX null" json:"done_bucket_id"`
+	// Configures how the done bucket treats tasks moved into or out of it.
+	DoneBucketConfiguration *ProjectViewDoneBucketConfiguration `xorm:"json null" json:"done_bucket_configuration"`
+
+	// Groups the tasks of a kanban view into swimlanes by assignee, label, priority or project in addition to the buckets.
+	// Tasks with multiple assignees or labels show up in multiple swimlanes.
+	SwimlaneMode SwimlaneModeKind `xorm:"default 0" json:"swimlane_mode"`
+
+	// The right a user needs on the project to see this view and to work with its tasks and buckets. 0 = Read only
+	// (everyone with access to the project), 1 = Read & Write, 2 = Admin. Allows to have views like a triage board
+	// which only some members of a project can use.
+	Right Right `xorm:"bigint not null default 0" json:"right"`
+
+	// Set when task positions in this view got too close to each other. The positions are then spread out again
+	// in the background.
+	NeedsRebalancing bool `xorm:"not null default false" json:"-"`
 
 	// A timestamp when this view was updated. You cannot change this value.
 	Updated time.Time `xorm:"updated not null" json:"updated"`
@@ -179,7 +293,7 @@ func getViewsForProject(s *xorm.Session, projectID int64) (views []*ProjectView,
 func (p *ProjectView) ReadAll(s *xorm.Session, a web.Auth, _ string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
 
 	pp := &Project{ID: p.ProjectID}
-	can, _, err := pp.CanRead(s, a)
+	can, maxRight, err := pp.CanRead(s, a)
 	if err != nil {
 		return nil, 0, 0, err
 	}
@@ -187,18 +301,22 @@ func (p *ProjectView) ReadAll(s *xorm.Session, a web.Auth, _ string, _ int, _ in
 		return nil, 0, 0, ErrGenericForbidden{}
 	}
 
-	projectViews, err := getViewsForProject(s, p.ProjectID)
+	allViews, err := getViewsForProject(s, p.ProjectID)
 	if err != nil {
 		return nil, 0, 0, err
 	}
 
-	totalCount, err := s.
-		Where("project_id = ?", p.ProjectID).
-		Count(&ProjectView{})
-	if err != nil {
-		return
+	// Saved filters don't have rights on their own, only the owner can see them.
+	isSavedFilter := getSavedFilterIDFromProjectID(p.ProjectID) > 0
+	projectViews := make([]*ProjectView, 0, len(allViews))
+	for _, view := range allViews {
+		if isSavedFilter || int(view.Right) <= maxRight {
+			projectViews = append(projectViews, view)
+		}
 	}
 
+	totalCount := int64(len(projectViews))
+
 	return projectViews, len(projectViews), totalCount, nil
 }
 
@@ -259,6 +377,10 @@ func (p *ProjectView) Delete(s *xorm.Session, _ web.Auth) (err error) {
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /projects/{project}/views [put]
 func (p *ProjectView) Create(s *xorm.Session, a web.Auth) (err error) {
+	if err := p.Right.isValid(); err != nil {
+		return err
+	}
+
 	return createProjectView(s, p, a, true)
 }
 
@@ -332,6 +454,10 @@ func (p *ProjectView) Update(s *xorm.Session, _ web.Auth) (err error) {
 		return
 	}
 
+	if err := p.Right.isValid(); err != nil {
+		return err
+	}
+
 	_, err = s.
 		ID(p.ID).
 		Cols(
@@ -343,6 +469,9 @@ func (p *ProjectView) Update(s *xorm.Session, _ web.Auth) (err error) {
 			"bucket_configuration",
 			"default_bucket_id",
 			"done_bucket_id",
+			"done_bucket_configuration",
+			"swimlane_mode",
+			"right",
 		).
 		Update(p)
 	return
@@ -433,6 +562,15 @@ func CreateDefaultViewsForProject(s *xorm.Session, project *Project, a web.Auth,
 		return
 	}
 
+	// Projects below a project with bucket templates start with those buckets instead of the backlog bucket
+	var templates []*BucketTemplate
+	if createBacklogBucket {
+		templates, err = getBucketTemplatesForNewProject(s, project)
+		if err != nil {
+			return
+		}
+	}
+
 	kanban := &ProjectView{
 		ProjectID:               project.ID,
 		Title:                   "Kanban",
@@ -440,11 +578,18 @@ func CreateDefaultViewsForProject(s *xorm.Session, project *Project, a web.Auth,
 		Position:                400,
 		BucketConfigurationMode: BucketConfigurationModeManual,
 	}
-	err = createProjectView(s, kanban, a, createBacklogBucket)
+	err = createProjectView(s, kanban, a, createBacklogBucket && len(templates) == 0)
 	if err != nil {
 		return
 	}
 
+	if len(templates) > 0 {
+		err = kanban.createBucketsFromTemplates(s, templates, a)
+		if err != nil {
+			return
+		}
+	}
+
 	project.Views = []*ProjectView{
 		list,
 		gantt,
@@ -454,3 +599,10 @@ func CreateDefaultViewsForProject(s *xorm.Session, project *Project, a web.Auth,
 
 	return
 }
+
+func (p *ProjectView) getDoneBucketConfiguration() *ProjectViewDoneBucketConfiguration {
+	if p.DoneBucketConfiguration == nil {
+		return &ProjectViewDoneBucketConfiguration{}
+	}
+	return p.DoneBucketConfiguration
+}
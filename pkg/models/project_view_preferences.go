@@ -0,0 +1,123 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// ProjectViewPreferences holds how a user has set up the views of a project, so that the settings are the same on
+// every device they use.
+type ProjectViewPreferences struct {
+	ID        int64 `xorm:"bigint autoincr not null unique pk" json:"-"`
+	UserID    int64 `xorm:"bigint not null unique(user_project)" json:"-"`
+	ProjectID int64 `xorm:"bigint not null unique(user_project) INDEX" json:"project_id" param:"project"`
+
+	// The view the user had open last in this project.
+	ActiveViewID int64 `xorm:"bigint null" json:"active_view_id"`
+	// The properties the user sorted the tasks of the project by, see the `sort_by` parameter of the task collection.
+	SortBy []string `xorm:"json null" json:"sort_by"`
+	// The order for each of the sort by properties, either `asc` or `desc`.
+	OrderBy []string `xorm:"json null" json:"order_by"`
+	// The ids of all kanban buckets the user collapsed.
+	CollapsedBuckets []int64 `xorm:"json null" json:"collapsed_buckets"`
+	// The width of the columns in the table view, by column name.
+	ColumnWidths map[string]int64 `xorm:"json null" json:"column_widths"`
+
+	// A timestamp when the preferences were last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for project view preferences
+func (*ProjectViewPreferences) TableName() string {
+	return "project_view_preferences"
+}
+
+// ReadOne returns the view preferences of the current user for a project
+// @Summary Get the view preferences for a project
+// @Description Returns how the current user has set up the views of a project. If they never changed anything, empty preferences are returned.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Success 200 {object} models.ProjectViewPreferences "The view preferences."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/preferences [get]
+func (pvp *ProjectViewPreferences) ReadOne(s *xorm.Session, a web.Auth) (err error) {
+	projectID := pvp.ProjectID
+	exists, err := s.
+		Where("user_id = ? AND project_id = ?", a.GetID(), projectID).
+		Get(pvp)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		*pvp = ProjectViewPreferences{ProjectID: projectID}
+	}
+
+	return nil
+}
+
+// Update saves the view preferences of the current user for a project
+// @Summary Save the view preferences for a project
+// @Description Saves how the current user has set up the views of a project. All preferences are replaced with the ones passed.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param preferences body models.ProjectViewPreferences true "The view preferences."
+// @Success 200 {object} models.ProjectViewPreferences "The saved view preferences."
+// @Failure 400 {object} web.HTTPError "The active view does not belong to the project."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/preferences [post]
+func (pvp *ProjectViewPreferences) Update(s *xorm.Session, a web.Auth) (err error) {
+	if pvp.ActiveViewID != 0 {
+		_, err = GetProjectViewByIDAndProject(s, pvp.ActiveViewID, pvp.ProjectID)
+		if err != nil {
+			return err
+		}
+	}
+
+	pvp.UserID = a.GetID()
+
+	count, err := s.
+		Where("user_id = ? AND project_id = ?", pvp.UserID, pvp.ProjectID).
+		Cols(
+			"active_view_id",
+			"sort_by",
+			"order_by",
+			"collapsed_buckets",
+			"column_widths",
+		).
+		Update(pvp)
+	if err != nil || count > 0 {
+		return err
+	}
+
+	pvp.ID = 0
+	_, err = s.Insert(pvp)
+	return
+}
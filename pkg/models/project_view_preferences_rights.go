@@ -0,0 +1,44 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if a user can see their view preferences of a project
+func (pvp *ProjectViewPreferences) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	can, err := pvp.canDoPreferences(s, a)
+	return can, int(RightRead), err
+}
+
+// CanUpdate checks if a user can save their view preferences of a project
+func (pvp *ProjectViewPreferences) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return pvp.canDoPreferences(s, a)
+}
+
+// Everyone who can see a project has their own preferences for it, except link shares which are not tied to a person.
+func (pvp *ProjectViewPreferences) canDoPreferences(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	p := &Project{ID: pvp.ProjectID}
+	can, _, err := p.CanRead(s, a)
+	return can, err
+}
@@ -0,0 +1,99 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectViewPreferences(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("empty preferences", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pvp := &ProjectViewPreferences{ProjectID: 1}
+		err := pvp.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), pvp.ProjectID)
+		assert.Equal(t, int64(0), pvp.ActiveViewID)
+	})
+	t.Run("save and read", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pvp := &ProjectViewPreferences{
+			ProjectID:        1,
+			ActiveViewID:     4,
+			SortBy:           []string{"due_date"},
+			OrderBy:          []string{"desc"},
+			CollapsedBuckets: []int64{2},
+			ColumnWidths:     map[string]int64{"title": 300},
+		}
+		can, err := pvp.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = pvp.Update(s, u)
+		require.NoError(t, err)
+
+		// Saving again replaces the existing preferences
+		pvp = &ProjectViewPreferences{ProjectID: 1, ActiveViewID: 1, CollapsedBuckets: []int64{2, 3}}
+		err = pvp.Update(s, u)
+		require.NoError(t, err)
+
+		read := &ProjectViewPreferences{ProjectID: 1}
+		err = read.ReadOne(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), read.ActiveViewID)
+		assert.Equal(t, []int64{2, 3}, read.CollapsedBuckets)
+		assert.Empty(t, read.SortBy)
+
+		// Other users have their own preferences
+		other := &ProjectViewPreferences{ProjectID: 1}
+		err = other.ReadOne(s, &user.User{ID: 2})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), other.ActiveViewID)
+	})
+	t.Run("view of another project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pvp := &ProjectViewPreferences{ProjectID: 1, ActiveViewID: 8}
+		err := pvp.Update(s, u)
+		require.Error(t, err)
+	})
+	t.Run("no access to the project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pvp := &ProjectViewPreferences{ProjectID: 2}
+		can, _, err := pvp.CanRead(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
@@ -29,7 +29,30 @@ func (p *ProjectView) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
 	}
 
 	pp := p.getProject()
-	return pp.CanRead(s, a)
+	can, maxRight, err := pp.CanRead(s, a)
+	if err != nil || !can {
+		return can, maxRight, err
+	}
+
+	view, err := GetProjectViewByIDAndProject(s, p.ID, p.ProjectID)
+	if err != nil {
+		return false, 0, err
+	}
+	return int(view.Right) <= maxRight, maxRight, nil
+}
+
+// hasRequiredRight checks if the user has the right on the project this view requires to work with it.
+func (p *ProjectView) hasRequiredRight(s *xorm.Session, a web.Auth) (bool, error) {
+	if p.Right == RightRead || getSavedFilterIDFromProjectID(p.ProjectID) > 0 {
+		return true, nil
+	}
+
+	pp := p.getProject()
+	_, maxRight, err := pp.CanRead(s, a)
+	if err != nil {
+		return false, err
+	}
+	return int(p.Right) <= maxRight, nil
 }
 
 func (p *ProjectView) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
@@ -0,0 +1,93 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectView_Right(t *testing.T) {
+	linkShare := &LinkSharing{
+		ID:          1,
+		ProjectID:   1,
+		Right:       RightRead,
+		SharingType: SharingTypeWithoutPassword,
+	}
+	restrictView := func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		_, err := s.Where("id = ?", 4).Cols("right").Update(&ProjectView{Right: RightWrite})
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+	}
+
+	t.Run("hidden from users without the right", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		restrictView(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pv := &ProjectView{ProjectID: 1}
+		views, _, _, err := pv.ReadAll(s, linkShare, "", 0, 0)
+		require.NoError(t, err)
+		for _, view := range views.([]*ProjectView) {
+			assert.NotEqual(t, int64(4), view.ID)
+		}
+
+		pv = &ProjectView{ID: 4, ProjectID: 1}
+		can, _, err := pv.CanRead(s, linkShare)
+		require.NoError(t, err)
+		assert.False(t, can)
+
+		tc := &TaskCollection{ProjectID: 1, ProjectViewID: 4}
+		_, _, _, err = tc.ReadAll(s, linkShare, "", 0, 0)
+		require.Error(t, err)
+		assert.IsType(t, ErrGenericForbidden{}, err)
+	})
+	t.Run("visible to users with the right", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		restrictView(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pv := &ProjectView{ID: 4, ProjectID: 1}
+		can, _, err := pv.CanRead(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("invalid right", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pv := &ProjectView{
+			Title:     "Triage",
+			ProjectID: 1,
+			ViewKind:  ProjectViewKindKanban,
+			Right:     Right(42),
+		}
+		err := pv.Create(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidRight(err))
+	})
+}
@@ -0,0 +1,228 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/notifications"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// PushSubscriptionKeys are the keys the browser uses to decrypt push messages
+type PushSubscriptionKeys struct {
+	P256dh string `json:"p256dh" valid:"required"`
+	Auth   string `json:"auth" valid:"required"`
+}
+
+// PushSubscription is a browser on one of the devices of a user which receives push notifications
+type PushSubscription struct {
+	// The unique, numeric id of this push subscription.
+	ID     int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"subscription"`
+	UserID int64 `xorm:"bigint not null INDEX" json:"-"`
+	// The url of the push service, as returned by the browser.
+	Endpoint string `xorm:"text not null" json:"endpoint" valid:"required,url"`
+	// The keys of the subscription, as returned by the browser. Only used when creating a subscription.
+	Keys   *PushSubscriptionKeys `xorm:"-" json:"keys,omitempty"`
+	P256dh string                `xorm:"varchar(255) not null" json:"-"`
+	Auth   string                `xorm:"varchar(255) not null" json:"-"`
+	// A name to tell the devices of a user apart.
+	DeviceName string `xorm:"varchar(250) null" json:"device_name" valid:"runelength(0|250)"`
+
+	// A timestamp when this subscription was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for push subscriptions
+func (*PushSubscription) TableName() string {
+	return "push_subscriptions"
+}
+
+// Create registers a device for push notifications
+// @Summary Register a device for push notifications
+// @Description Saves the push subscription of a browser. The body should be what the browser's `PushSubscription.toJSON()` returns. Registering the same endpoint again replaces the old subscription.
+// @tags user
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param subscription body models.PushSubscription true "The push subscription"
+// @Success 201 {object} models.PushSubscription "The created push subscription."
+// @Failure 412 {object} web.HTTPError "The subscription is missing its keys."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /user/settings/push-subscriptions [put]
+func (ps *PushSubscription) Create(s *xorm.Session, a web.Auth) (err error) {
+	if ps.Keys == nil || ps.Keys.P256dh == "" || ps.Keys.Auth == "" {
+		return InvalidFieldError([]string{"keys"})
+	}
+
+	// An endpoint belongs to exactly one browser, if it is registered again the browser was probably logged in
+	// with another account before.
+	_, err = s.Where("endpoint = ?", ps.Endpoint).Delete(&PushSubscription{})
+	if err != nil {
+		return err
+	}
+
+	ps.ID = 0
+	ps.UserID = a.GetID()
+	ps.P256dh = ps.Keys.P256dh
+	ps.Auth = ps.Keys.Auth
+	ps.Keys = nil
+
+	_, err = s.Insert(ps)
+	return
+}
+
+// ReadAll returns all push subscriptions of the current user
+// @Summary Get all push subscriptions
+// @Description Returns all devices of the current user which receive push notifications.
+// @tags user
+// @Produce json
+// @Security JWTKeyAuth
+// @Success 200 {array} models.PushSubscription "The push subscriptions"
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /user/settings/push-subscriptions [get]
+func (ps *PushSubscription) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	if _, isShareAuth := a.(*LinkSharing); isShareAuth {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	subscriptions := []*PushSubscription{}
+	err = s.
+		Where("user_id = ?", a.GetID()).
+		Limit(getLimitFromPageIndex(page, perPage)).
+		OrderBy("id asc").
+		Find(&subscriptions)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	total, err := s.Where("user_id = ?", a.GetID()).Count(&PushSubscription{})
+	return subscriptions, len(subscriptions), total, err
+}
+
+// Delete removes a push subscription
+// @Summary Unregister a device from push notifications
+// @tags user
+// @Produce json
+// @Security JWTKeyAuth
+// @Param subscriptionID path int true "Push subscription ID"
+// @Success 200 {object} models.Message "Successfully deleted."
+// @Failure 404 {object} web.HTTPError "The push subscription does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /user/settings/push-subscriptions/{subscriptionID} [delete]
+func (ps *PushSubscription) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.Where("id = ?", ps.ID).Delete(&PushSubscription{})
+	return
+}
+
+// webPushPayload is what the service worker of the frontend gets with every push message
+type webPushPayload struct {
+	Notification string `json:"notification"`
+	Title        string `json:"title"`
+	Body         string `json:"body"`
+	URL          string `json:"url"`
+}
+
+// webPushTransport sends reminders, mentions and assignments to all devices of a user
+type webPushTransport struct{}
+
+func (*webPushTransport) Channel() notifications.Channel {
+	return notifications.ChannelPush
+}
+
+func (*webPushTransport) Handles(notification notifications.Notification) bool {
+	switch n := notification.(type) {
+	case *ReminderDueNotification, *TaskAssignedNotification, *UserMentionedInTaskNotification:
+		return true
+	case *TaskCommentNotification:
+		return n.Mentioned
+	}
+	return false
+}
+
+func (*webPushTransport) Send(notifiable notifications.Notifiable, notification notifications.Notification) (err error) {
+	s := db.NewSession()
+	defer s.Close()
+
+	subscriptions := []*PushSubscription{}
+	err = s.Where("user_id = ?", notifiable.RouteForDB()).Find(&subscriptions)
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+
+	msg := notifications.NewChatMessage(notification, notifiable.Lang())
+	if msg == nil {
+		return nil
+	}
+	payload, err := json.Marshal(&webPushPayload{
+		Notification: notification.Name(),
+		Title:        msg.Title,
+		Body:         msg.PlainText(),
+		URL:          msg.ActionURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	subject := config.WebPushSubject.GetString()
+	if subject == "" {
+		subject = config.ServicePublicURL.GetString()
+	}
+
+	for _, subscription := range subscriptions {
+		status, err := notifications.SendWebPush(
+			getWebhookHTTPClient(),
+			&notifications.WebPushSubscription{
+				Endpoint: subscription.Endpoint,
+				P256dh:   subscription.P256dh,
+				Auth:     subscription.Auth,
+			},
+			payload,
+			subject,
+			config.WebPushVAPIDPublicKey.GetString(),
+			config.WebPushVAPIDPrivateKey.GetString(),
+		)
+		if status == http.StatusNotFound || status == http.StatusGone {
+			// The browser unsubscribed or the subscription expired
+			_, err = s.Where("id = ?", subscription.ID).Delete(&PushSubscription{})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			log.Errorf("Could not send push notification %s to subscription %d: %s", notification.Name(), subscription.ID, err)
+		}
+	}
+
+	return s.Commit()
+}
+
+// RegisterWebPushTransport sends notifications to the browsers users subscribed with
+func RegisterWebPushTransport() {
+	notifications.RegisterTransport(&webPushTransport{})
+}
@@ -0,0 +1,45 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanCreate checks if a user can register a device for push notifications
+func (ps *PushSubscription) CanCreate(_ *xorm.Session, a web.Auth) (bool, error) {
+	_, isShareAuth := a.(*LinkSharing)
+	return !isShareAuth, nil
+}
+
+// CanDelete checks if the push subscription belongs to the user
+func (ps *PushSubscription) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, isShareAuth := a.(*LinkSharing); isShareAuth {
+		return false, nil
+	}
+
+	existing := &PushSubscription{}
+	exists, err := s.Where("id = ?", ps.ID).Get(existing)
+	if err != nil {
+		return false, err
+	}
+	if !exists || existing.UserID != a.GetID() {
+		return false, ErrPushSubscriptionDoesNotExist{ID: ps.ID}
+	}
+	return true, nil
+}
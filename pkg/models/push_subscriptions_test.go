@@ -0,0 +1,117 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushSubscription_Create(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ps := &PushSubscription{
+			Endpoint:   "https://push.example.com/subscription/new",
+			Keys:       &PushSubscriptionKeys{P256dh: "key", Auth: "secret"},
+			DeviceName: "Laptop",
+		}
+		err := ps.Create(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		assert.Nil(t, ps.Keys)
+
+		db.AssertExists(t, "push_subscriptions", map[string]interface{}{
+			"id":      ps.ID,
+			"user_id": 1,
+			"p256dh":  "key",
+			"auth":    "secret",
+		}, false)
+	})
+	t.Run("same endpoint as another user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ps := &PushSubscription{
+			Endpoint: "https://push.example.com/subscription/2",
+			Keys:     &PushSubscriptionKeys{P256dh: "key", Auth: "secret"},
+		}
+		err := ps.Create(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		db.AssertMissing(t, "push_subscriptions", map[string]interface{}{
+			"id": 2,
+		})
+		db.AssertExists(t, "push_subscriptions", map[string]interface{}{
+			"endpoint": "https://push.example.com/subscription/2",
+			"user_id":  1,
+		}, false)
+	})
+	t.Run("without keys", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ps := &PushSubscription{Endpoint: "https://push.example.com/subscription/new"}
+		err := ps.Create(s, &user.User{ID: 1})
+		require.Error(t, err)
+	})
+}
+
+func TestPushSubscription_ReadAll(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	ps := &PushSubscription{}
+	result, _, _, err := ps.ReadAll(s, &user.User{ID: 1}, "", 1, 50)
+	require.NoError(t, err)
+	subscriptions := result.([]*PushSubscription)
+	require.Len(t, subscriptions, 1)
+	assert.Equal(t, int64(1), subscriptions[0].ID)
+	assert.Equal(t, "Phone", subscriptions[0].DeviceName)
+}
+
+func TestPushSubscription_CanDelete(t *testing.T) {
+	t.Run("own", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		can, err := (&PushSubscription{ID: 1}).CanDelete(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+	t.Run("of another user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := (&PushSubscription{ID: 2}).CanDelete(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrPushSubscriptionDoesNotExist(err))
+	})
+}
@@ -41,3 +41,42 @@ func (r Right) isValid() error {
 
 	return nil
 }
+
+// Capability is a single action a role allows on top of the right of a share
+type Capability string
+
+// All capabilities a role can grant
+const (
+	// Can create new tasks
+	CapabilityCreateTasks Capability = "tasks.create"
+	// Can edit existing tasks, but not move them to other projects
+	CapabilityEditTasks Capability = "tasks.edit"
+	// Can delete tasks
+	CapabilityDeleteTasks Capability = "tasks.delete"
+	// Can comment on tasks
+	CapabilityComment Capability = "comments.create"
+	// Can share the project with other users and teams, but not with admin rights
+	CapabilityManageShares Capability = "shares.manage"
+	// Can create, edit and delete kanban buckets
+	CapabilityManageBuckets Capability = "buckets.manage"
+)
+
+// AllCapabilities holds every capability a role can grant
+var AllCapabilities = []Capability{
+	CapabilityCreateTasks,
+	CapabilityEditTasks,
+	CapabilityDeleteTasks,
+	CapabilityComment,
+	CapabilityManageShares,
+	CapabilityManageBuckets,
+}
+
+func (c Capability) isValid() error {
+	for _, capability := range AllCapabilities {
+		if c == capability {
+			return nil
+		}
+	}
+
+	return &ErrInvalidCapability{Capability: c}
+}
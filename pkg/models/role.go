@@ -0,0 +1,250 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// Role is an instance wide set of capabilities which can be given to users and teams on top of the right of their share
+type Role struct {
+	// The unique, numeric id of this role.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id"`
+	// The title of the role, for example "Commenter".
+	Title string `xorm:"varchar(250) not null" json:"title" valid:"required,runelength(1|250)" minLength:"1" maxLength:"250"`
+	// A description of what this role is meant for.
+	Description string `xorm:"longtext null" json:"description"`
+	// Everything a user or team with this role can do in a project.
+	Capabilities []Capability `xorm:"JSON not null" json:"capabilities"`
+
+	// A timestamp when this role was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this role was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for roles
+func (*Role) TableName() string {
+	return "roles"
+}
+
+// hasCapability returns whether the role grants a capability
+func (r *Role) hasCapability(capability Capability) bool {
+	for _, c := range r.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadAll returns all roles which can be assigned to shares
+// @Summary Get all roles
+// @Description Returns all roles of this instance. Roles can be assigned to user and team shares to grant them more capabilities than their right. Roles are managed through the cli.
+// @tags sharing
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Success 200 {array} models.Role "The roles"
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /roles [get]
+func (r *Role) ReadAll(s *xorm.Session, a web.Auth, _ string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	if _, is := a.(*LinkSharing); is {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	roles, err := ListRoles(s)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return roles, len(roles), int64(len(roles)), nil
+}
+
+// ListRoles returns all roles of this instance
+func ListRoles(s *xorm.Session) (roles []*Role, err error) {
+	roles = []*Role{}
+	err = s.OrderBy("id asc").Find(&roles)
+	return
+}
+
+// CreateRole creates a new instance wide role
+func CreateRole(s *xorm.Session, role *Role) (err error) {
+	for _, c := range role.Capabilities {
+		if err := c.isValid(); err != nil {
+			return err
+		}
+	}
+
+	role.ID = 0
+	_, err = s.Insert(role)
+	return
+}
+
+// DeleteRole removes a role and takes it away from all shares it was assigned to
+func DeleteRole(s *xorm.Session, roleID int64) (err error) {
+	exists, err := s.Where("id = ?", roleID).Exist(&Role{})
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return &ErrRoleDoesNotExist{RoleID: roleID}
+	}
+
+	_, err = s.Where("role_id = ?", roleID).Cols("role_id").Update(&ProjectUser{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("role_id = ?", roleID).Cols("role_id").Update(&TeamProject{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("id = ?", roleID).Delete(&Role{})
+	return
+}
+
+func checkRoleExists(s *xorm.Session, roleID int64) error {
+	if roleID == 0 {
+		return nil
+	}
+
+	exists, err := s.Where("id = ?", roleID).Exist(&Role{})
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return &ErrRoleDoesNotExist{RoleID: roleID}
+	}
+	return nil
+}
+
+// hasCapability checks if the user got a capability on the project through a role on one of their shares.
+// Like rights, roles are inherited from parent projects if the project itself is not shared with the user.
+func (p *Project) hasCapability(s *xorm.Session, a web.Auth, capability Capability) (bool, error) {
+	// Link shares can't have roles
+	if _, is := a.(*LinkSharing); is || isOutsideAPITokenProjects(a, p.ID) {
+		return false, nil
+	}
+
+	project, err := GetProjectSimpleByID(s, p.ID)
+	if err != nil {
+		return false, err
+	}
+
+	roleIDs := []int64{}
+	err = s.
+		Table("users_projects").
+		Cols("role_id").
+		Where(builder.And(
+			builder.Eq{"project_id": project.ID},
+			builder.Eq{"user_id": a.GetID()},
+			builder.Neq{"role_id": 0},
+		)).
+		Find(&roleIDs)
+	if err != nil {
+		return false, err
+	}
+
+	teamRoleIDs := []int64{}
+	err = s.
+		Table("team_projects").
+		Cols("team_projects.role_id").
+		Join("INNER", "team_members", "team_members.team_id = team_projects.team_id").
+		Where(builder.And(
+			builder.Eq{"team_projects.project_id": project.ID},
+			builder.Eq{"team_members.user_id": a.GetID()},
+			builder.Neq{"team_projects.role_id": 0},
+		)).
+		Find(&teamRoleIDs)
+	if err != nil {
+		return false, err
+	}
+	roleIDs = append(roleIDs, teamRoleIDs...)
+
+	if len(roleIDs) == 0 {
+		if project.ParentProjectID > 0 {
+			return (&Project{ID: project.ParentProjectID}).hasCapability(s, a, capability)
+		}
+		return false, nil
+	}
+
+	roles := []*Role{}
+	err = s.In("id", roleIDs).Find(&roles)
+	if err != nil {
+		return false, err
+	}
+
+	for _, r := range roles {
+		if r.hasCapability(capability) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// canGrant checks if a user who manages the shares of a project through a role can give out a share with the right
+// and role. Managing shares through a role never allows giving out admin rights or more than the user has themselves.
+func (p *Project) canGrant(s *xorm.Session, a web.Auth, right Right, roleID int64) (bool, error) {
+	if right == RightAdmin {
+		return false, nil
+	}
+
+	_, maxRight, err := p.CanRead(s, a)
+	if err != nil {
+		return false, err
+	}
+	if right > Right(maxRight) {
+		return false, nil
+	}
+
+	if roleID == 0 {
+		return true, nil
+	}
+
+	role := &Role{}
+	has, err := s.Where("id = ?", roleID).Get(role)
+	if err != nil {
+		return false, err
+	}
+	if !has {
+		return false, &ErrRoleDoesNotExist{RoleID: roleID}
+	}
+
+	for _, c := range role.Capabilities {
+		// Everyone with write access can already work with tasks, comments and buckets
+		if Right(maxRight) >= RightWrite && c != CapabilityManageShares {
+			continue
+		}
+
+		holds, err := p.hasCapability(s, a, c)
+		if err != nil || !holds {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
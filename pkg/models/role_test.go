@@ -0,0 +1,149 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRole(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("invalid capability", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := CreateRole(s, &Role{Title: "Broken", Capabilities: []Capability{"tasks.fly"}})
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidCapability(err))
+	})
+	t.Run("capabilities on a read only share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 1 has read access to project 3
+		can, err := (&Task{ProjectID: 3}).CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+
+		role := &Role{
+			Title:        "Contributor",
+			Capabilities: []Capability{CapabilityCreateTasks, CapabilityComment},
+		}
+		err = CreateRole(s, role)
+		require.NoError(t, err)
+		_, err = s.ID(1).Cols("role_id").Update(&ProjectUser{RoleID: role.ID})
+		require.NoError(t, err)
+
+		can, err = (&Task{ProjectID: 3}).CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		can, err = (&TaskComment{TaskID: 32}).CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		can, err = (&Task{ID: 32}).CanDelete(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("managing shares through a role", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 1 has read access to project 3, user 2 too
+		role := &Role{
+			Title:        "Sharer",
+			Capabilities: []Capability{CapabilityManageShares, CapabilityComment},
+		}
+		err := CreateRole(s, role)
+		require.NoError(t, err)
+		_, err = s.ID(1).Cols("role_id").Update(&ProjectUser{RoleID: role.ID})
+		require.NoError(t, err)
+
+		can, err := (&ProjectUser{Username: "user2", ProjectID: 3, Right: RightRead}).CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+
+		// Not more than the user has themselves
+		can, err = (&ProjectUser{Username: "user2", ProjectID: 3, Right: RightWrite}).CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+
+		can, err = (&ProjectUser{Username: "user2", ProjectID: 3, RoleID: role.ID}).CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+
+		deleter := &Role{Title: "Deleter", Capabilities: []Capability{CapabilityDeleteTasks}}
+		err = CreateRole(s, deleter)
+		require.NoError(t, err)
+		can, err = (&ProjectUser{Username: "user2", ProjectID: 3, RoleID: deleter.ID}).CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+
+		// Admins can't be changed by someone who is not an admin
+		_, err = s.ID(2).Cols("right").Update(&ProjectUser{Right: RightAdmin})
+		require.NoError(t, err)
+		can, err = (&ProjectUser{Username: "user2", ProjectID: 3, Right: RightRead}).CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+		can, err = (&ProjectUser{Username: "user2", ProjectID: 3}).CanDelete(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("share with nonexistent role", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pu := &ProjectUser{Username: "user2", ProjectID: 1, RoleID: 9999}
+		err := pu.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrRoleDoesNotExist(err))
+	})
+	t.Run("delete role", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		role := &Role{Title: "Commenter", Capabilities: []Capability{CapabilityComment}}
+		err := CreateRole(s, role)
+		require.NoError(t, err)
+		_, err = s.ID(1).Cols("role_id").Update(&ProjectUser{RoleID: role.ID})
+		require.NoError(t, err)
+
+		err = DeleteRole(s, role.ID)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "roles", map[string]interface{}{
+			"id": role.ID,
+		})
+		db.AssertExists(t, "users_projects", map[string]interface{}{
+			"id":      1,
+			"role_id": 0,
+		}, false)
+	})
+}
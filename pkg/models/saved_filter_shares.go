@@ -0,0 +1,289 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// SavedFilterShare gives a team or everyone with access to a project access to a saved filter.
+type SavedFilterShare struct {
+	// The unique, numeric id of this share.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"share"`
+	// The saved filter this share belongs to.
+	FilterID int64 `xorm:"bigint not null INDEX" json:"-" param:"filter"`
+	// The team the filter is shared with. Either this or the project id must be set.
+	TeamID int64 `xorm:"bigint null INDEX" json:"team_id"`
+	// The project the filter is shared with. Everyone who can read the project gets access to the filter.
+	ProjectID int64 `xorm:"bigint null INDEX" json:"project_id"`
+	// The right the team or project members have on the filter. 0 = Read only, 1 = Read & Write, 2 = Admin.
+	Right Right `xorm:"bigint INDEX not null default 0" json:"right" valid:"length(0|2)" maximum:"2" default:"0"`
+
+	// A timestamp when this share was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this share was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for saved filter shares
+func (*SavedFilterShare) TableName() string {
+	return "saved_filter_shares"
+}
+
+func getSavedFilterShareByID(s *xorm.Session, id int64) (share *SavedFilterShare, err error) {
+	share = &SavedFilterShare{}
+	exists, err := s.Where("id = ?", id).Get(share)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrSavedFilterShareDoesNotExist{ShareID: id}
+	}
+	return
+}
+
+// Create shares a saved filter with a team or project
+// @Summary Share a saved filter
+// @Description Shares a saved filter with a team or with everyone who has access to a project. Exactly one of `team_id` and `project_id` must be set.
+// @tags filter
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param filter path int true "Filter ID"
+// @Param share body models.SavedFilterShare true "The share"
+// @Success 201 {object} models.SavedFilterShare "The created share."
+// @Failure 400 {object} web.HTTPError "Invalid share provided."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the saved filter."
+// @Failure 409 {object} web.HTTPError "The filter is already shared with this team or project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /filters/{filter}/shares [put]
+func (share *SavedFilterShare) Create(s *xorm.Session, _ web.Auth) (err error) {
+	if err = share.Right.isValid(); err != nil {
+		return
+	}
+
+	if (share.TeamID == 0) == (share.ProjectID == 0) {
+		return ErrInvalidSavedFilterShare{TeamID: share.TeamID, ProjectID: share.ProjectID}
+	}
+
+	if share.TeamID != 0 {
+		_, err = GetTeamByID(s, share.TeamID)
+	} else {
+		_, err = GetProjectSimpleByID(s, share.ProjectID)
+	}
+	if err != nil {
+		return err
+	}
+
+	exists, err := s.
+		Where("filter_id = ? AND team_id = ? AND project_id = ?", share.FilterID, share.TeamID, share.ProjectID).
+		Exist(&SavedFilterShare{})
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrSavedFilterAlreadyShared{SavedFilterID: share.FilterID, TeamID: share.TeamID, ProjectID: share.ProjectID}
+	}
+
+	share.ID = 0
+	_, err = s.Insert(share)
+	return
+}
+
+// ReadAll returns all shares of a saved filter
+// @Summary Get all shares of a saved filter
+// @Description Returns all teams and projects a saved filter is shared with.
+// @tags filter
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param filter path int true "Filter ID"
+// @Success 200 {array} models.SavedFilterShare "The shares."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the saved filter."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /filters/{filter}/shares [get]
+func (share *SavedFilterShare) ReadAll(s *xorm.Session, a web.Auth, _ string, _ int, _ int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	sf := &SavedFilter{ID: share.FilterID}
+	can, _, err := sf.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	shares := []*SavedFilterShare{}
+	err = s.Where("filter_id = ?", share.FilterID).OrderBy("id asc").Find(&shares)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return shares, len(shares), int64(len(shares)), nil
+}
+
+// Update changes the right of a saved filter share
+// @Summary Update a saved filter share
+// @Description Updates the right a team or project has on a saved filter.
+// @tags filter
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param filter path int true "Filter ID"
+// @Param share path int true "Share ID"
+// @Param share body models.SavedFilterShare true "The share"
+// @Success 200 {object} models.SavedFilterShare "The updated share."
+// @Failure 400 {object} web.HTTPError "Invalid share provided."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the saved filter."
+// @Failure 404 {object} web.HTTPError "The share does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /filters/{filter}/shares/{share} [post]
+func (share *SavedFilterShare) Update(s *xorm.Session, _ web.Auth) (err error) {
+	if err = share.Right.isValid(); err != nil {
+		return
+	}
+
+	_, err = s.
+		Where("id = ? AND filter_id = ?", share.ID, share.FilterID).
+		Cols("right").
+		Update(share)
+	if err != nil {
+		return err
+	}
+
+	updated, err := getSavedFilterShareByID(s, share.ID)
+	if err != nil {
+		return err
+	}
+	*share = *updated
+	return
+}
+
+// Delete removes a saved filter share
+// @Summary Remove a saved filter share
+// @Description Removes the access of a team or project to a saved filter.
+// @tags filter
+// @Produce json
+// @Security JWTKeyAuth
+// @Param filter path int true "Filter ID"
+// @Param share path int true "Share ID"
+// @Success 200 {object} models.Message "The share was successfully removed."
+// @Failure 403 {object} web.HTTPError "The user does not have admin access to the saved filter."
+// @Failure 404 {object} web.HTTPError "The share does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /filters/{filter}/shares/{share} [delete]
+func (share *SavedFilterShare) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.
+		Where("id = ? AND filter_id = ?", share.ID, share.FilterID).
+		Delete(&SavedFilterShare{})
+	return
+}
+
+// getRightForUser returns the highest right a user has on a saved filter, either as its owner or through one of
+// its shares. If the user has no access at all, it returns RightUnknown.
+func (sf *SavedFilter) getRightForUser(s *xorm.Session, a web.Auth) (right Right, err error) {
+	if sf.OwnerID == a.GetID() {
+		return RightAdmin, nil
+	}
+
+	shares := []*SavedFilterShare{}
+	err = s.
+		Where(builder.And(
+			builder.Eq{"filter_id": sf.ID},
+			builder.Or(
+				builder.In("team_id", builder.Select("team_id").From("team_members").Where(builder.Eq{"user_id": a.GetID()})),
+				builder.Neq{"project_id": 0},
+			),
+		)).
+		Find(&shares)
+	if err != nil {
+		return RightUnknown, err
+	}
+
+	right = RightUnknown
+	for _, share := range shares {
+		if share.Right <= right {
+			continue
+		}
+
+		if share.ProjectID != 0 {
+			can, _, err := (&Project{ID: share.ProjectID}).CanRead(s, a)
+			if err != nil {
+				return RightUnknown, err
+			}
+			if !can {
+				continue
+			}
+		}
+
+		right = share.Right
+	}
+
+	return
+}
+
+// getSavedFiltersSharedWithUser returns all saved filters of other users which were shared with a team the user is
+// a member of or with a project the user can read.
+func getSavedFiltersSharedWithUser(s *xorm.Session, u *user.User) (filters []*SavedFilter, err error) {
+	shares := []*SavedFilterShare{}
+	err = s.
+		Where(builder.Or(
+			builder.In("team_id", builder.Select("team_id").From("team_members").Where(builder.Eq{"user_id": u.ID})),
+			builder.Neq{"project_id": 0},
+		)).
+		Find(&shares)
+	if err != nil || len(shares) == 0 {
+		return nil, err
+	}
+
+	canReadProject := make(map[int64]bool)
+	filterIDs := []int64{}
+	for _, share := range shares {
+		if share.ProjectID != 0 {
+			can, has := canReadProject[share.ProjectID]
+			if !has {
+				can, _, err = (&Project{ID: share.ProjectID}).CanRead(s, u)
+				if err != nil {
+					return nil, err
+				}
+				canReadProject[share.ProjectID] = can
+			}
+			if !can {
+				continue
+			}
+		}
+
+		filterIDs = append(filterIDs, share.FilterID)
+	}
+
+	if len(filterIDs) == 0 {
+		return nil, nil
+	}
+
+	err = s.
+		In("id", filterIDs).
+		And("owner_id != ?", u.ID).
+		Find(&filters)
+	return
+}
@@ -0,0 +1,65 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanCreate checks if a user can share a saved filter with a team or project
+func (share *SavedFilterShare) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	can, err := share.canDoSavedFilterShare(s, a)
+	if err != nil || !can {
+		return can, err
+	}
+
+	// Sharing with a project only makes sense if the user can see that project themselves
+	if share.ProjectID != 0 {
+		can, _, err = (&Project{ID: share.ProjectID}).CanRead(s, a)
+	}
+	return can, err
+}
+
+// CanUpdate checks if a user can update a saved filter share
+func (share *SavedFilterShare) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return share.canDoExistingSavedFilterShare(s, a)
+}
+
+// CanDelete checks if a user can delete a saved filter share
+func (share *SavedFilterShare) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return share.canDoExistingSavedFilterShare(s, a)
+}
+
+func (share *SavedFilterShare) canDoExistingSavedFilterShare(s *xorm.Session, a web.Auth) (bool, error) {
+	existing, err := getSavedFilterShareByID(s, share.ID)
+	if err != nil {
+		return false, err
+	}
+	if existing.FilterID != share.FilterID {
+		return false, ErrSavedFilterShareDoesNotExist{ShareID: share.ID}
+	}
+
+	return share.canDoSavedFilterShare(s, a)
+}
+
+// Only users with admin rights on a filter can manage who it is shared with
+func (share *SavedFilterShare) canDoSavedFilterShare(s *xorm.Session, a web.Auth) (bool, error) {
+	sf := &SavedFilter{ID: share.FilterID}
+	can, _, err := sf.canDoFilter(s, a, RightAdmin)
+	return can, err
+}
@@ -0,0 +1,134 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavedFilterShare(t *testing.T) {
+	owner := &user.User{ID: 1}
+	teamMember := &user.User{ID: 2}
+
+	t.Run("share with team", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		share := &SavedFilterShare{FilterID: 1, TeamID: 1, Right: RightRead}
+		can, err := share.CanCreate(s, owner)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = share.Create(s, owner)
+		require.NoError(t, err)
+
+		sf := &SavedFilter{ID: 1}
+		can, maxRight, err := sf.CanRead(s, teamMember)
+		require.NoError(t, err)
+		assert.True(t, can)
+		assert.Equal(t, int(RightRead), maxRight)
+
+		can, err = (&SavedFilter{ID: 1}).CanUpdate(s, teamMember)
+		require.NoError(t, err)
+		assert.False(t, can)
+
+		projects, err := getSavedFilterProjects(s, teamMember)
+		require.NoError(t, err)
+		require.Len(t, projects, 1)
+		assert.Equal(t, int64(-2), projects[0].ID)
+		assert.Equal(t, owner.ID, projects[0].Owner.ID)
+	})
+	t.Run("write right allows updating", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		share := &SavedFilterShare{FilterID: 1, TeamID: 1, Right: RightWrite}
+		err := share.Create(s, owner)
+		require.NoError(t, err)
+
+		can, err := (&SavedFilter{ID: 1}).CanUpdate(s, teamMember)
+		require.NoError(t, err)
+		assert.True(t, can)
+
+		can, err = (&SavedFilter{ID: 1}).CanDelete(s, teamMember)
+		require.NoError(t, err)
+		assert.False(t, can)
+
+		can, err = (&SavedFilterShare{FilterID: 1, TeamID: 9}).CanCreate(s, teamMember)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("not shared", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		can, _, err := (&SavedFilter{ID: 1}).CanRead(s, teamMember)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("team and project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		share := &SavedFilterShare{FilterID: 1, TeamID: 1, ProjectID: 1}
+		err := share.Create(s, owner)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidSavedFilterShare(err))
+	})
+	t.Run("already shared", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&SavedFilterShare{FilterID: 1, ProjectID: 1}).Create(s, owner)
+		require.NoError(t, err)
+		err = (&SavedFilterShare{FilterID: 1, ProjectID: 1}).Create(s, owner)
+		require.Error(t, err)
+		assert.True(t, IsErrSavedFilterAlreadyShared(err))
+	})
+	t.Run("delete", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		share := &SavedFilterShare{FilterID: 1, TeamID: 1}
+		err := share.Create(s, owner)
+		require.NoError(t, err)
+
+		toDelete := &SavedFilterShare{ID: share.ID, FilterID: 1}
+		can, err := toDelete.CanDelete(s, owner)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = toDelete.Delete(s, owner)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "saved_filter_shares", map[string]interface{}{
+			"id": share.ID,
+		})
+	})
+}
@@ -0,0 +1,203 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/user"
+
+	"xorm.io/xorm"
+)
+
+// SavedFilterSubscriptionMatch records that a task matched the saved filter of a subscription the last time it was
+// checked. This allows notifying subscribers only when a task starts matching, not every time it changes.
+type SavedFilterSubscriptionMatch struct {
+	ID             int64     `xorm:"bigint autoincr not null unique pk" json:"-"`
+	SubscriptionID int64     `xorm:"bigint not null INDEX" json:"-"`
+	TaskID         int64     `xorm:"bigint not null INDEX" json:"-"`
+	Created        time.Time `xorm:"created not null" json:"-"`
+}
+
+// TableName returns the table name for saved filter subscription matches
+func (*SavedFilterSubscriptionMatch) TableName() string {
+	return "saved_filter_subscription_matches"
+}
+
+// getMatchingTaskIDs returns the ids of all tasks the user can see which match the saved filter. If taskIDs is not
+// empty, only these tasks are checked.
+func (sf *SavedFilter) getMatchingTaskIDs(s *xorm.Session, u *user.User, taskIDs []int64) (ids []int64, err error) {
+	tc := *sf.Filters
+	tc.ProjectID = getProjectIDFromSavedFilterID(sf.ID)
+	tc.ProjectViewID = 0
+	tc.isSavedFilter = true
+
+	if tc.FilterTimezone == "" {
+		tc.FilterTimezone = u.Timezone
+	}
+
+	if len(taskIDs) > 0 {
+		idFilter := "id in "
+		for i, id := range taskIDs {
+			if i > 0 {
+				idFilter += ", "
+			}
+			idFilter += strconv.FormatInt(id, 10)
+		}
+		if tc.Filter != "" {
+			tc.Filter = "(" + tc.Filter + ") && " + idFilter
+		} else {
+			tc.Filter = idFilter
+		}
+	}
+
+	result, _, _, err := tc.ReadAll(s, u, "", 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, is := result.([]*Task)
+	if !is {
+		return nil, nil
+	}
+
+	ids = make([]int64, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+	return
+}
+
+// recordCurrentSavedFilterMatches stores all tasks which match the filter at the time of subscribing so that the
+// subscriber only gets notified about tasks matching the filter after that.
+func (sb *Subscription) recordCurrentSavedFilterMatches(s *xorm.Session) (err error) {
+	sf, err := getSavedFilterSimpleByID(s, sb.EntityID)
+	if err != nil {
+		return err
+	}
+
+	taskIDs, err := sf.getMatchingTaskIDs(s, sb.User, nil)
+	if err != nil || len(taskIDs) == 0 {
+		return err
+	}
+
+	matches := make([]*SavedFilterSubscriptionMatch, 0, len(taskIDs))
+	for _, id := range taskIDs {
+		matches = append(matches, &SavedFilterSubscriptionMatch{
+			SubscriptionID: sb.ID,
+			TaskID:         id,
+		})
+	}
+
+	_, err = s.Insert(matches)
+	return
+}
+
+// checkSavedFilterSubscriptionsForTask checks the task against all subscribed saved filters and notifies every
+// subscriber whose filter the task did not match before but does now.
+func checkSavedFilterSubscriptionsForTask(s *xorm.Session, taskID int64, doer *user.User) (err error) {
+	subscriptions := []*Subscription{}
+	err = s.Where("entity_type = ?", SubscriptionEntitySavedFilter).Find(&subscriptions)
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+
+	task, err := GetTaskByIDSimple(s, taskID)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subscriptions {
+		u, err := user.GetUserByID(s, sub.UserID)
+		if err != nil {
+			if user.IsErrUserDoesNotExist(err) {
+				continue
+			}
+			return err
+		}
+
+		sf := &SavedFilter{ID: sub.EntityID}
+		can, _, err := sf.CanRead(s, u)
+		if err != nil {
+			if IsErrSavedFilterDoesNotExist(err) {
+				continue
+			}
+			return err
+		}
+		if !can {
+			continue
+		}
+
+		matchingIDs, err := sf.getMatchingTaskIDs(s, u, []int64{taskID})
+		if err != nil {
+			return err
+		}
+		matches := len(matchingIDs) > 0
+
+		matchedBefore, err := s.
+			Where("subscription_id = ? AND task_id = ?", sub.ID, taskID).
+			Exist(&SavedFilterSubscriptionMatch{})
+		if err != nil {
+			return err
+		}
+
+		if !matches {
+			if matchedBefore {
+				_, err = s.
+					Where("subscription_id = ? AND task_id = ?", sub.ID, taskID).
+					Delete(&SavedFilterSubscriptionMatch{})
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if matchedBefore {
+			continue
+		}
+
+		_, err = s.Insert(&SavedFilterSubscriptionMatch{
+			SubscriptionID: sub.ID,
+			TaskID:         taskID,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Users don't need to be told about their own changes
+		if doer != nil && doer.ID == u.ID {
+			continue
+		}
+
+		log.Debugf("Task %d newly matches saved filter %d, notifying user %d", taskID, sf.ID, u.ID)
+
+		err = notifications.Notify(u, &TaskMatchesSavedFilterNotification{
+			Doer:   doer,
+			Task:   &task,
+			Filter: sf,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
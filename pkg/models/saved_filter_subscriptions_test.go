@@ -0,0 +1,111 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/builder"
+)
+
+func TestSavedFilterSubscription(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("records current matches when subscribing", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		sb := &Subscription{Entity: "filter", EntityID: 1}
+		can, err := sb.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = sb.Create(s, u)
+		require.NoError(t, err)
+
+		fullUser, err := user.GetUserByID(s, u.ID)
+		require.NoError(t, err)
+		sf, err := getSavedFilterSimpleByID(s, 1)
+		require.NoError(t, err)
+		matching, err := sf.getMatchingTaskIDs(s, fullUser, nil)
+		require.NoError(t, err)
+		require.NotEmpty(t, matching)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertCount(t, "saved_filter_subscription_matches", builder.Eq{"subscription_id": sb.ID}, int64(len(matching)))
+	})
+	t.Run("notifies about newly matching tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		notifications.Fake()
+		s := db.NewSession()
+		defer s.Close()
+
+		sb := &Subscription{Entity: "filter", EntityID: 1}
+		_, err := sb.CanCreate(s, u)
+		require.NoError(t, err)
+		err = sb.Create(s, u)
+		require.NoError(t, err)
+
+		sf, err := getSavedFilterSimpleByID(s, 1)
+		require.NoError(t, err)
+		matching, err := sf.getMatchingTaskIDs(s, sb.User, nil)
+		require.NoError(t, err)
+		require.NotEmpty(t, matching)
+		taskID := matching[0]
+
+		// Pretend the task did not match before
+		_, err = s.Where("subscription_id = ? AND task_id = ?", sb.ID, taskID).Delete(&SavedFilterSubscriptionMatch{})
+		require.NoError(t, err)
+
+		err = checkSavedFilterSubscriptionsForTask(s, taskID, &user.User{ID: 2})
+		require.NoError(t, err)
+		notifications.AssertSent(t, &TaskMatchesSavedFilterNotification{})
+
+		exists, err := s.Where("subscription_id = ? AND task_id = ?", sb.ID, taskID).Exist(&SavedFilterSubscriptionMatch{})
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+	t.Run("unsubscribing removes matches", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		sb := &Subscription{Entity: "filter", EntityID: 1}
+		_, err := sb.CanCreate(s, u)
+		require.NoError(t, err)
+		err = sb.Create(s, u)
+		require.NoError(t, err)
+
+		toDelete := &Subscription{Entity: "filter", EntityID: 1}
+		can, err := toDelete.CanDelete(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = toDelete.Delete(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertCount(t, "saved_filter_subscription_matches", builder.Eq{"subscription_id": sb.ID}, 0)
+	})
+}
@@ -212,5 +212,26 @@ func (sf *SavedFilter) Delete(s *xorm.Session, _ web.Auth) error {
 	_, err := s.
 		Where("id = ?", sf.ID).
 		Delete(sf)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.
+		Where("filter_id = ?", sf.ID).
+		Delete(&SavedFilterShare{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.
+		Where("subscription_id IN (SELECT id FROM subscriptions WHERE entity_id = ? AND entity_type = ?)", sf.ID, SubscriptionEntitySavedFilter).
+		Delete(&SavedFilterSubscriptionMatch{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.
+		Where("entity_id = ? AND entity_type = ?", sf.ID, SubscriptionEntitySavedFilter).
+		Delete(&Subscription{})
 	return err
 }
@@ -23,20 +23,22 @@ import (
 
 // CanRead checks if a user has the right to read a saved filter
 func (sf *SavedFilter) CanRead(s *xorm.Session, auth web.Auth) (bool, int, error) {
-	can, err := sf.canDoFilter(s, auth)
-	return can, int(RightAdmin), err
+	can, maxRight, err := sf.canDoFilter(s, auth, RightRead)
+	return can, int(maxRight), err
 }
 
 // CanDelete checks if a user has the right to delete a saved filter
 func (sf *SavedFilter) CanDelete(s *xorm.Session, auth web.Auth) (bool, error) {
-	return sf.canDoFilter(s, auth)
+	can, _, err := sf.canDoFilter(s, auth, RightAdmin)
+	return can, err
 }
 
 // CanUpdate checks if a user has the right to update a saved filter
 func (sf *SavedFilter) CanUpdate(s *xorm.Session, auth web.Auth) (bool, error) {
 	// A normal check would replace the passed struct which in our case would override the values we want to update.
 	sff := &SavedFilter{ID: sf.ID}
-	return sff.canDoFilter(s, auth)
+	can, _, err := sff.canDoFilter(s, auth, RightWrite)
+	return can, err
 }
 
 // CanCreate checks if a user has the right to update a saved filter
@@ -49,23 +51,27 @@ func (sf *SavedFilter) CanCreate(_ *xorm.Session, auth web.Auth) (bool, error) {
 }
 
 // Helper function to check saved filter rights sind they all have the same logic
-func (sf *SavedFilter) canDoFilter(s *xorm.Session, auth web.Auth) (can bool, err error) {
+func (sf *SavedFilter) canDoFilter(s *xorm.Session, auth web.Auth, right Right) (can bool, maxRight Right, err error) {
 	// Link shares can't view or modify saved filters, therefore we can error out right away
 	if _, is := auth.(*LinkSharing); is {
-		return false, ErrSavedFilterNotAvailableForLinkShare{LinkShareID: auth.GetID(), SavedFilterID: sf.ID}
+		return false, RightUnknown, ErrSavedFilterNotAvailableForLinkShare{LinkShareID: auth.GetID(), SavedFilterID: sf.ID}
 	}
 
 	sff, err := getSavedFilterSimpleByID(s, sf.ID)
 	if err != nil {
-		return false, err
+		return false, RightUnknown, err
 	}
 
-	// Only owners are allowed to do something with a saved filter
-	if sff.OwnerID != auth.GetID() {
-		return false, nil
+	// Owners can do everything with a saved filter, everyone else needs a share with a high enough right
+	maxRight, err = sff.getRightForUser(s, auth)
+	if err != nil {
+		return false, RightUnknown, err
+	}
+	if maxRight < right {
+		return false, maxRight, nil
 	}
 
 	*sf = *sff
 
-	return true, nil
+	return true, maxRight, nil
 }
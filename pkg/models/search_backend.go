@@ -0,0 +1,213 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+
+	"github.com/typesense/typesense-go/typesense/api"
+	"github.com/typesense/typesense-go/typesense/api/pointer"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
+)
+
+const (
+	SearchBackendDatabase    = "database"
+	SearchBackendPostgres    = "postgres"
+	SearchBackendTypesense   = "typesense"
+	SearchBackendMeilisearch = "meilisearch"
+)
+
+// The number of matching tasks fetched from an external search engine at once. Searches page through all results.
+const searchBackendPageSize = 1000
+
+// Typesense does not return more than this many hits per page.
+const typesenseMaxPerPage = 250
+
+// SearchBackend runs the full text search of tasks.
+type SearchBackend interface {
+	// TaskCond returns a condition which matches all tasks in the given projects whose text matches the search.
+	TaskCond(s *xorm.Session, search string, projectIDs []int64) (builder.Cond, error)
+	// IndexTasks adds or updates tasks in the search index.
+	IndexTasks(s *xorm.Session, tasks map[int64]*Task) error
+	// RemoveTask removes a task from the search index.
+	RemoveTask(taskID int64) error
+	// Reindex throws away the existing search index and indexes all tasks again.
+	Reindex() error
+}
+
+// GetSearchBackend returns the configured search backend.
+func GetSearchBackend() SearchBackend {
+	switch config.SearchBackend.GetString() {
+	case SearchBackendPostgres:
+		if db.Type() != schemas.POSTGRES {
+			log.Warningf("The %s search backend only works with PostgreSQL, falling back to searching with the database.", SearchBackendPostgres)
+			return &databaseSearchBackend{}
+		}
+		return &postgresSearchBackend{}
+	case SearchBackendTypesense:
+		return &typesenseSearchBackend{}
+	case SearchBackendMeilisearch:
+		return &meilisearchSearchBackend{}
+	}
+	return &databaseSearchBackend{}
+}
+
+// IndexTasksByID updates tasks in the search index right away. Changes to many tasks at once, like merging or
+// importing projects, don't dispatch an event for every task and use this instead.
+func IndexTasksByID(s *xorm.Session, taskIDs []int64) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+
+	tasks := make(map[int64]*Task, len(taskIDs))
+	err := s.In("id", taskIDs).Find(&tasks)
+	if err != nil {
+		return err
+	}
+
+	return GetSearchBackend().IndexTasks(s, tasks)
+}
+
+func getTaskIDsCond(taskIDs []int64) builder.Cond {
+	if len(taskIDs) == 0 {
+		return builder.Expr("0=1")
+	}
+	return builder.In("tasks.id", taskIDs)
+}
+
+// databaseSearchBackend searches with a LIKE query. It does not need an index.
+type databaseSearchBackend struct{}
+
+func (b *databaseSearchBackend) TaskCond(_ *xorm.Session, search string, _ []int64) (builder.Cond, error) {
+	return builder.Or(
+		db.ILIKE("title", search),
+		db.ILIKE("description", search),
+	), nil
+}
+
+func (b *databaseSearchBackend) IndexTasks(_ *xorm.Session, _ map[int64]*Task) error {
+	return nil
+}
+
+func (b *databaseSearchBackend) RemoveTask(_ int64) error {
+	return nil
+}
+
+func (b *databaseSearchBackend) Reindex() error {
+	return nil
+}
+
+// postgresSearchBackend uses the full text search of PostgreSQL. The index is an expression index on the tasks
+// table which PostgreSQL keeps up to date on its own.
+type postgresSearchBackend struct{}
+
+const postgresTaskSearchVector = `to_tsvector('simple', coalesce(title, '') || ' ' || coalesce(description, ''))`
+
+func (b *postgresSearchBackend) TaskCond(_ *xorm.Session, search string, _ []int64) (builder.Cond, error) {
+	return builder.Expr(postgresTaskSearchVector+" @@ websearch_to_tsquery('simple', ?)", search), nil
+}
+
+func (b *postgresSearchBackend) IndexTasks(_ *xorm.Session, _ map[int64]*Task) error {
+	return nil
+}
+
+func (b *postgresSearchBackend) RemoveTask(_ int64) error {
+	return nil
+}
+
+func (b *postgresSearchBackend) Reindex() error {
+	s := db.NewSession()
+	defer s.Close()
+
+	_, err := s.Exec("DROP INDEX IF EXISTS tasks_search_idx")
+	if err != nil {
+		return err
+	}
+	_, err = s.Exec("CREATE INDEX tasks_search_idx ON tasks USING GIN (" + postgresTaskSearchVector + ")")
+	return err
+}
+
+// typesenseSearchBackend searches through Typesense. Most searches go through the typesenseTaskSearcher, which also
+// handles filters - this is only used when the filters need to be resolved by the database.
+type typesenseSearchBackend struct{}
+
+func (b *typesenseSearchBackend) TaskCond(_ *xorm.Session, search string, projectIDs []int64) (builder.Cond, error) {
+	projectIDStrings := make([]string, 0, len(projectIDs))
+	for _, id := range projectIDs {
+		projectIDStrings = append(projectIDStrings, strconv.FormatInt(id, 10))
+	}
+
+	taskIDs := []int64{}
+	for page := 1; ; page++ {
+		result, err := typesenseClient.Collection("tasks").
+			Documents().
+			Search(context.Background(), &api.SearchCollectionParams{
+				Q:             search,
+				QueryBy:       "title, identifier, description, comments.comment",
+				FilterBy:      pointer.String("project_id: [" + strings.Join(projectIDStrings, ", ") + "]"),
+				IncludeFields: pointer.String("id"),
+				Page:          pointer.Int(page),
+				PerPage:       pointer.Int(typesenseMaxPerPage),
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, h := range *result.Hits {
+			hit := *h.Document
+			taskID, err := strconv.ParseInt(hit["id"].(string), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			taskIDs = append(taskIDs, taskID)
+		}
+
+		if len(*result.Hits) < typesenseMaxPerPage || result.Found == nil || len(taskIDs) >= *result.Found {
+			break
+		}
+	}
+
+	return getTaskIDsCond(taskIDs), nil
+}
+
+func (b *typesenseSearchBackend) IndexTasks(s *xorm.Session, tasks map[int64]*Task) error {
+	return reindexTasksInTypesense(s, tasks)
+}
+
+func (b *typesenseSearchBackend) RemoveTask(taskID int64) error {
+	_, err := typesenseClient.
+		Collection("tasks").
+		Document(strconv.FormatInt(taskID, 10)).
+		Delete(context.Background())
+	return err
+}
+
+func (b *typesenseSearchBackend) Reindex() error {
+	err := CreateTypesenseCollections()
+	if err != nil {
+		return err
+	}
+	return ReindexAllTasks()
+}
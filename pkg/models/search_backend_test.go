@@ -0,0 +1,52 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+
+	"github.com/stretchr/testify/assert"
+	"xorm.io/xorm/schemas"
+)
+
+func TestGetSearchBackend(t *testing.T) {
+	defer config.SearchBackend.Set(SearchBackendDatabase)
+
+	t.Run("default", func(t *testing.T) {
+		config.SearchBackend.Set(SearchBackendDatabase)
+		assert.IsType(t, &databaseSearchBackend{}, GetSearchBackend())
+	})
+	t.Run("typesense", func(t *testing.T) {
+		config.SearchBackend.Set(SearchBackendTypesense)
+		assert.IsType(t, &typesenseSearchBackend{}, GetSearchBackend())
+		assert.True(t, isTypesenseEnabled())
+	})
+	t.Run("meilisearch", func(t *testing.T) {
+		config.SearchBackend.Set(SearchBackendMeilisearch)
+		assert.IsType(t, &meilisearchSearchBackend{}, GetSearchBackend())
+	})
+	t.Run("postgres without postgres", func(t *testing.T) {
+		if db.Type() == schemas.POSTGRES {
+			t.Skip("Only relevant for other databases")
+		}
+		config.SearchBackend.Set(SearchBackendPostgres)
+		assert.IsType(t, &databaseSearchBackend{}, GetSearchBackend())
+	})
+}
@@ -0,0 +1,200 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+
+	"github.com/meilisearch/meilisearch-go"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// meilisearchSearchBackend searches through Meilisearch. Only the text of tasks is indexed, filtering and sorting
+// still happens in the database.
+type meilisearchSearchBackend struct{}
+
+type meilisearchTask struct {
+	ID          int64  `json:"id"`
+	ProjectID   int64  `json:"project_id"`
+	Identifier  string `json:"identifier"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+const meilisearchIndex = "tasks"
+
+// Meilisearch does not return more hits than this for a single search, even when paginating. The default of
+// Meilisearch is 1000, the index is configured with this limit instead.
+const meilisearchMaxTotalHits = 100000
+
+var meilisearchClient meilisearch.ServiceManager
+
+func InitMeilisearch() {
+	if config.SearchBackend.GetString() != SearchBackendMeilisearch {
+		return
+	}
+
+	meilisearchClient = meilisearch.New(
+		config.MeilisearchURL.GetString(),
+		meilisearch.WithAPIKey(config.MeilisearchAPIKey.GetString()),
+		meilisearch.WithCustomClient(&http.Client{Timeout: 30 * time.Second}),
+	)
+}
+
+func (b *meilisearchSearchBackend) TaskCond(_ *xorm.Session, search string, projectIDs []int64) (builder.Cond, error) {
+	projectIDStrings := make([]string, 0, len(projectIDs))
+	for _, id := range projectIDs {
+		projectIDStrings = append(projectIDStrings, strconv.FormatInt(id, 10))
+	}
+
+	taskIDs := []int64{}
+	for {
+		result, err := meilisearchClient.Index(meilisearchIndex).Search(search, &meilisearch.SearchRequest{
+			Filter:               "project_id IN [" + strings.Join(projectIDStrings, ", ") + "]",
+			Offset:               int64(len(taskIDs)),
+			Limit:                searchBackendPageSize,
+			AttributesToRetrieve: []string{"id"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, h := range result.Hits {
+			hit, is := h.(map[string]interface{})
+			if !is {
+				continue
+			}
+			id, is := hit["id"].(float64)
+			if !is {
+				continue
+			}
+			taskIDs = append(taskIDs, int64(id))
+		}
+
+		if len(result.Hits) < searchBackendPageSize {
+			break
+		}
+	}
+
+	if len(taskIDs) >= meilisearchMaxTotalHits {
+		return nil, &ErrSearchHasTooManyResults{Limit: meilisearchMaxTotalHits}
+	}
+
+	return getTaskIDsCond(taskIDs), nil
+}
+
+func (b *meilisearchSearchBackend) IndexTasks(s *xorm.Session, tasks map[int64]*Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	projectIDs := make([]int64, 0, len(tasks))
+	for _, t := range tasks {
+		projectIDs = append(projectIDs, t.ProjectID)
+	}
+	projects, err := GetProjectsMapByIDs(s, projectIDs)
+	if err != nil {
+		return err
+	}
+
+	documents := make([]*meilisearchTask, 0, len(tasks))
+	for _, t := range tasks {
+		doc := &meilisearchTask{
+			ID:          t.ID,
+			ProjectID:   t.ProjectID,
+			Title:       t.Title,
+			Description: t.Description,
+		}
+		if p, has := projects[t.ProjectID]; has {
+			doc.Identifier = p.Identifier + "-" + strconv.FormatInt(t.Index, 10)
+		}
+		documents = append(documents, doc)
+	}
+
+	_, err = meilisearchClient.Index(meilisearchIndex).AddDocuments(documents, "id")
+	return err
+}
+
+func (b *meilisearchSearchBackend) RemoveTask(taskID int64) error {
+	_, err := meilisearchClient.Index(meilisearchIndex).DeleteDocument(strconv.FormatInt(taskID, 10))
+	return err
+}
+
+func (b *meilisearchSearchBackend) Reindex() (err error) {
+	// The index might not exist yet
+	_, _ = meilisearchClient.DeleteIndex(meilisearchIndex)
+
+	_, err = meilisearchClient.CreateIndex(&meilisearch.IndexConfig{
+		Uid:        meilisearchIndex,
+		PrimaryKey: "id",
+	})
+	if err != nil {
+		return err
+	}
+
+	info, err := meilisearchClient.Index(meilisearchIndex).UpdateSettings(&meilisearch.Settings{
+		FilterableAttributes: []string{"project_id"},
+		SearchableAttributes: []string{"title", "identifier", "description"},
+		Pagination:           &meilisearch.Pagination{MaxTotalHits: meilisearchMaxTotalHits},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Meilisearch applies settings asynchronously, a broken index should not go unnoticed until the first search
+	task, err := meilisearchClient.WaitForTask(info.TaskUID, 100*time.Millisecond)
+	if err != nil {
+		return err
+	}
+	if task.Status == meilisearch.TaskStatusFailed {
+		return fmt.Errorf("could not configure the meilisearch index: %s", task.Error.Message)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	const batchSize = 1000
+	var indexed int
+	for page := 0; ; page++ {
+		tasks := make(map[int64]*Task)
+		err = s.OrderBy("id asc").Limit(batchSize, page*batchSize).Find(tasks)
+		if err != nil {
+			return err
+		}
+		if len(tasks) == 0 {
+			break
+		}
+
+		err = b.IndexTasks(s, tasks)
+		if err != nil {
+			return err
+		}
+		indexed += len(tasks)
+	}
+
+	log.Debugf("Indexed %d tasks into Meilisearch", indexed)
+	return nil
+}
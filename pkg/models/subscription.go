@@ -34,11 +34,13 @@ const (
 	SubscriptionEntityNamespace // Kept even though not used anymore since we don't want to manually change all ids
 	SubscriptionEntityProject
 	SubscriptionEntityTask
+	SubscriptionEntitySavedFilter
 )
 
 const (
-	entityProject = `project`
-	entityTask    = `task`
+	entityProject     = `project`
+	entityTask        = `task`
+	entitySavedFilter = `filter`
 )
 
 // Subscription represents a subscription for an entity
@@ -73,6 +75,8 @@ func getEntityTypeFromString(entityType string) SubscriptionEntityType {
 		return SubscriptionEntityProject
 	case entityTask:
 		return SubscriptionEntityTask
+	case entitySavedFilter:
+		return SubscriptionEntitySavedFilter
 	}
 
 	return SubscriptionEntityUnknown
@@ -85,6 +89,8 @@ func (et SubscriptionEntityType) String() string {
 		return entityProject
 	case SubscriptionEntityTask:
 		return entityTask
+	case SubscriptionEntitySavedFilter:
+		return entitySavedFilter
 	}
 
 	return ""
@@ -92,7 +98,8 @@ func (et SubscriptionEntityType) String() string {
 
 func (et SubscriptionEntityType) validate() error {
 	if et == SubscriptionEntityProject ||
-		et == SubscriptionEntityTask {
+		et == SubscriptionEntityTask ||
+		et == SubscriptionEntitySavedFilter {
 		return nil
 	}
 
@@ -101,12 +108,12 @@ func (et SubscriptionEntityType) validate() error {
 
 // Create subscribes the current user to an entity
 // @Summary Subscribes the current user to an entity.
-// @Description Subscribes the current user to an entity.
+// @Description Subscribes the current user to an entity. Subscribing to a saved filter notifies the user whenever a task starts matching the filter.
 // @tags subscriptions
 // @Accept json
 // @Produce json
 // @Security JWTKeyAuth
-// @Param entity path string true "The entity the user subscribes to. Can be either `project` or `task`."
+// @Param entity path string true "The entity the user subscribes to. Can be either `project`, `task` or `filter`."
 // @Param entityID path string true "The numeric id of the entity to subscribe to."
 // @Success 201 {object} models.Subscription "The subscription"
 // @Failure 403 {object} web.HTTPError "The user does not have access to subscribe to this entity."
@@ -137,6 +144,13 @@ func (sb *Subscription) Create(s *xorm.Session, auth web.Auth) (err error) {
 	}
 
 	sb.User, err = user.GetFromAuth(auth)
+	if err != nil {
+		return
+	}
+
+	if sb.EntityType == SubscriptionEntitySavedFilter {
+		err = sb.recordCurrentSavedFilterMatches(s)
+	}
 	return
 }
 
@@ -147,7 +161,7 @@ func (sb *Subscription) Create(s *xorm.Session, auth web.Auth) (err error) {
 // @Accept json
 // @Produce json
 // @Security JWTKeyAuth
-// @Param entity path string true "The entity the user subscribed to. Can be either `project` or `task`."
+// @Param entity path string true "The entity the user subscribed to. Can be either `project`, `task` or `filter`."
 // @Param entityID path string true "The numeric id of the subscribed entity to."
 // @Success 200 {object} models.Subscription "The subscription"
 // @Failure 403 {object} web.HTTPError "The user does not have access to subscribe to this entity."
@@ -157,12 +171,36 @@ func (sb *Subscription) Create(s *xorm.Session, auth web.Auth) (err error) {
 func (sb *Subscription) Delete(s *xorm.Session, auth web.Auth) (err error) {
 	sb.UserID = auth.GetID()
 
+	if sb.EntityType == SubscriptionEntitySavedFilter {
+		_, err = s.
+			Where("subscription_id IN (SELECT id FROM subscriptions WHERE entity_id = ? AND entity_type = ? AND user_id = ?)", sb.EntityID, sb.EntityType, sb.UserID).
+			Delete(&SavedFilterSubscriptionMatch{})
+		if err != nil {
+			return
+		}
+	}
+
 	_, err = s.
 		Where("entity_id = ? AND entity_type = ? AND user_id = ?", sb.EntityID, sb.EntityType, sb.UserID).
 		Delete(&Subscription{})
 	return
 }
 
+// subscribeUserToTask subscribes a user to a task, unless they are already subscribed to it or its project.
+func subscribeUserToTask(s *xorm.Session, u *user.User, taskID int64) error {
+	sub, err := GetSubscription(s, SubscriptionEntityTask, taskID, u)
+	if err != nil || sub != nil {
+		return err
+	}
+
+	_, err = s.Insert(&Subscription{
+		EntityType: SubscriptionEntityTask,
+		EntityID:   taskID,
+		UserID:     u.ID,
+	})
+	return err
+}
+
 func getSubscriberCondForEntities(entityType SubscriptionEntityType, entityIDs []int64) (cond builder.Cond) {
 	if entityType == SubscriptionEntityProject {
 		return builder.And(
@@ -171,6 +209,13 @@ func getSubscriberCondForEntities(entityType SubscriptionEntityType, entityIDs [
 		)
 	}
 
+	if entityType == SubscriptionEntitySavedFilter {
+		return builder.And(
+			builder.In("entity_id", entityIDs),
+			builder.Eq{"entity_type": SubscriptionEntitySavedFilter},
+		)
+	}
+
 	if entityType == SubscriptionEntityTask {
 		return builder.Or(
 			builder.And(
@@ -267,6 +312,23 @@ func GetSubscriptions(s *xorm.Session, entityType SubscriptionEntityType, entity
 			}
 		}
 
+		return subs, nil
+	case SubscriptionEntitySavedFilter:
+		var subscriptions []*Subscription
+		query := s.Where(getSubscriberCondForEntities(SubscriptionEntitySavedFilter, entityIDs))
+		if u != nil {
+			query = query.And("user_id = ?", u.ID)
+		}
+		err = query.Find(&subscriptions)
+		if err != nil {
+			return nil, err
+		}
+
+		subs := make(map[int64][]*Subscription)
+		for _, sub := range subscriptions {
+			sub.Entity = sub.EntityType.String()
+			subs[sub.EntityID] = append(subs[sub.EntityID], sub)
+		}
 		return subs, nil
 	}
 
@@ -36,6 +36,9 @@ func (sb *Subscription) CanCreate(s *xorm.Session, a web.Auth) (can bool, err er
 	case SubscriptionEntityTask:
 		t := &Task{ID: sb.EntityID}
 		can, _, err = t.CanRead(s, a)
+	case SubscriptionEntitySavedFilter:
+		sf := &SavedFilter{ID: sb.EntityID}
+		can, _, err = sf.CanRead(s, a)
 	default:
 		return false, &ErrUnknownSubscriptionEntityType{EntityType: sb.EntityType}
 	}
@@ -0,0 +1,232 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/i18n"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"xorm.io/xorm"
+)
+
+// TaskAction is something a user can do with a task directly from a notification, without logging in.
+type TaskAction string
+
+const (
+	TaskActionDone   TaskAction = "done"
+	TaskActionSnooze TaskAction = "snooze"
+)
+
+// Task action links stay valid for a week, which is enough for the next overdue summary to replace them.
+const taskActionTokenTTL = 7 * 24 * time.Hour
+
+// UsedTaskActionToken remembers a task action link which was already used, so that every link works only once.
+// Entries are kept until the token expires anyway.
+type UsedTaskActionToken struct {
+	TokenID   string    `xorm:"varchar(36) not null unique pk"`
+	ExpiresAt time.Time `xorm:"datetime not null index"`
+}
+
+// TableName returns the table name for used task action tokens
+func (*UsedTaskActionToken) TableName() string {
+	return "used_task_action_tokens"
+}
+
+// TaskActionRequest is a verified task action link which was not used yet.
+type TaskActionRequest struct {
+	Task   *Task
+	User   *user.User
+	Action TaskAction
+
+	tokenID   string
+	expiresAt time.Time
+}
+
+// The tokens are signed with a key derived from the jwt secret so that they can never be used as auth tokens.
+func taskActionTokenKey() []byte {
+	return []byte(config.ServiceJWTSecret.GetString() + ".task-actions")
+}
+
+// NewTaskActionToken creates a signed token which allows the user to run the action on the task once.
+func NewTaskActionToken(u *user.User, task *Task, action TaskAction) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"jti":     uuid.NewString(),
+		"user_id": u.ID,
+		"task_id": task.ID,
+		"action":  string(action),
+		"exp":     time.Now().Add(taskActionTokenTTL).Unix(),
+	}).SignedString(taskActionTokenKey())
+}
+
+// GetTaskActionURL returns the url which asks the user to confirm the action on the task when opened.
+func GetTaskActionURL(u *user.User, task *Task, action TaskAction) (string, error) {
+	token, err := NewTaskActionToken(u, task, action)
+	if err != nil {
+		return "", err
+	}
+	return config.ServicePublicURL.GetString() + "api/v1/task-actions/" + token, nil
+}
+
+// snoozeDueDate moves a due date to the day after now, keeping its time of day.
+func snoozeDueDate(dueDate, now time.Time) time.Time {
+	tomorrow := now.In(dueDate.Location()).AddDate(0, 0, 1)
+	return time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), dueDate.Hour(), dueDate.Minute(), dueDate.Second(), 0, dueDate.Location())
+}
+
+// GetTaskActionRequest verifies a task action token without running its action. The token must not have been used
+// before, the user it was issued for must still be active and allowed to edit the task.
+func GetTaskActionRequest(s *xorm.Session, token string) (request *TaskActionRequest, err error) {
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(_ *jwt.Token) (interface{}, error) {
+		return taskActionTokenKey(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, &ErrInvalidTaskActionToken{}
+	}
+
+	tokenID, isToken := claims["jti"].(string)
+	userID, isUser := claims["user_id"].(float64)
+	taskID, isTask := claims["task_id"].(float64)
+	action, isAction := claims["action"].(string)
+	if !isToken || !isUser || !isTask || !isAction {
+		return nil, &ErrInvalidTaskActionToken{}
+	}
+	if TaskAction(action) != TaskActionDone && TaskAction(action) != TaskActionSnooze {
+		return nil, &ErrInvalidTaskActionToken{}
+	}
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil {
+		return nil, &ErrInvalidTaskActionToken{}
+	}
+
+	used, err := s.Where("token_id = ?", tokenID).Exist(&UsedTaskActionToken{})
+	if err != nil {
+		return nil, err
+	}
+	if used {
+		return nil, &ErrInvalidTaskActionToken{}
+	}
+
+	u, err := user.GetUserByID(s, int64(userID))
+	if err != nil {
+		return nil, err
+	}
+	if u.Status == user.StatusDisabled {
+		return nil, &user.ErrAccountDisabled{UserID: u.ID}
+	}
+
+	task := &Task{ID: int64(taskID)}
+	can, err := task.CanUpdate(s, u)
+	if err != nil {
+		return nil, err
+	}
+	if !can {
+		return nil, ErrGenericForbidden{}
+	}
+
+	err = task.ReadOne(s, u)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TaskActionRequest{
+		Task:      task,
+		User:      u,
+		Action:    TaskAction(action),
+		tokenID:   tokenID,
+		expiresAt: expiresAt.Time,
+	}, nil
+}
+
+// Run runs the action of a task action link as the user it was issued for. Afterwards the link can't be used again.
+func (r *TaskActionRequest) Run(s *xorm.Session) (err error) {
+	_, err = s.Insert(&UsedTaskActionToken{
+		TokenID:   r.tokenID,
+		ExpiresAt: r.expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch r.Action {
+	case TaskActionDone:
+		r.Task.Done = true
+	case TaskActionSnooze:
+		r.Task.DueDate = snoozeDueDate(r.Task.DueDate, time.Now())
+	}
+
+	return r.Task.Update(s, r.User)
+}
+
+// RunTaskAction verifies a task action token and runs its action as the user it was issued for.
+// It returns the task the action was run on.
+func RunTaskAction(s *xorm.Session, token string) (task *Task, err error) {
+	request, err := GetTaskActionRequest(s, token)
+	if err != nil {
+		return nil, err
+	}
+
+	err = request.Run(s)
+	return request.Task, err
+}
+
+// RegisterUsedTaskActionTokenCleanupCron registers a cron function to forget used task action tokens once they
+// expired, since they are rejected anyway then.
+func RegisterUsedTaskActionTokenCleanupCron() {
+	const logPrefix = "[Used Task Action Token Cleanup Cron] "
+
+	err := cron.Schedule("0 * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		deleted, err := s.
+			Where("expires_at < ?", time.Now()).
+			Delete(&UsedTaskActionToken{})
+		if err != nil {
+			log.Errorf(logPrefix+"Error removing expired task action tokens: %s", err)
+			return
+		}
+		if deleted > 0 {
+			log.Debugf(logPrefix+"Deleted %d expired task action tokens", deleted)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register used task action token cleanup cron: %s", err)
+	}
+}
+
+// taskActionLinks returns markdown links to mark the task as done or snooze it. Because they are only a
+// convenience, errors only lead to missing links.
+func taskActionLinks(lang string, u *user.User, task *Task) string {
+	done, err := GetTaskActionURL(u, task, TaskActionDone)
+	if err != nil {
+		return ""
+	}
+	snooze, err := GetTaskActionURL(u, task, TaskActionSnooze)
+	if err != nil {
+		return ""
+	}
+	return `[` + i18n.T(lang, "notifications.task.overdue.mark_done") + `](` + done + `) · [` + i18n.T(lang, "notifications.task.overdue.snooze") + `](` + snooze + `)`
+}
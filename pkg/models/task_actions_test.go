@@ -0,0 +1,123 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunTaskAction(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("done", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		token, err := NewTaskActionToken(u, &Task{ID: 5}, TaskActionDone)
+		require.NoError(t, err)
+		task, err := RunTaskAction(s, token)
+		require.NoError(t, err)
+		assert.True(t, task.Done)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":   5,
+			"done": true,
+		}, false)
+	})
+	t.Run("snooze", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		token, err := NewTaskActionToken(u, &Task{ID: 5}, TaskActionSnooze)
+		require.NoError(t, err)
+		task, err := RunTaskAction(s, token)
+		require.NoError(t, err)
+		assert.True(t, task.DueDate.After(time.Now()))
+		assert.False(t, task.Done)
+	})
+	t.Run("invalid token", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := RunTaskAction(s, "invalid")
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskActionToken(err))
+	})
+	t.Run("only once", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		token, err := NewTaskActionToken(u, &Task{ID: 5}, TaskActionSnooze)
+		require.NoError(t, err)
+
+		// Opening the link does not use it up
+		request, err := GetTaskActionRequest(s, token)
+		require.NoError(t, err)
+		assert.Equal(t, TaskActionSnooze, request.Action)
+		assert.Equal(t, int64(5), request.Task.ID)
+
+		_, err = RunTaskAction(s, token)
+		require.NoError(t, err)
+		_, err = RunTaskAction(s, token)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskActionToken(err))
+	})
+	t.Run("disabled user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		token, err := NewTaskActionToken(u, &Task{ID: 5}, TaskActionDone)
+		require.NoError(t, err)
+		err = user.SetUserStatus(s, u, user.StatusDisabled)
+		require.NoError(t, err)
+
+		_, err = RunTaskAction(s, token)
+		require.Error(t, err)
+		assert.True(t, user.IsErrAccountDisabled(err))
+	})
+	t.Run("no right to edit the task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		token, err := NewTaskActionToken(&user.User{ID: 2}, &Task{ID: 5}, TaskActionDone)
+		require.NoError(t, err)
+		_, err = RunTaskAction(s, token)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+}
+
+func TestSnoozeDueDate(t *testing.T) {
+	dueDate := time.Date(2018, 11, 28, 10, 30, 0, 0, time.UTC)
+	now := time.Date(2018, 12, 1, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2018, 12, 2, 10, 30, 0, 0, time.UTC), snoozeDueDate(dueDate, now))
+}
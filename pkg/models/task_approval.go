@@ -0,0 +1,164 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/user"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// TaskApprovalStatus represents where a task is in the approval workflow of its project
+type TaskApprovalStatus int
+
+// All approval states a task can be in
+const (
+	TaskApprovalStatusNone TaskApprovalStatus = iota
+	TaskApprovalStatusPending
+	TaskApprovalStatusApproved
+	TaskApprovalStatusRejected
+)
+
+// TaskApproval is the decision of an approver about a task waiting for approval
+type TaskApproval struct {
+	// The task to approve or reject
+	TaskID int64 `json:"-" param:"task"`
+	// Set by the route, approving and rejecting use different endpoints.
+	Approve bool `json:"-"`
+	// An optional explanation for the decision, it is passed on to everyone notified about it.
+	Reason string `json:"reason"`
+
+	// The task after the decision
+	Task *Task `json:"task,omitempty"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// updateApprovalStatus decides whether a task which is being marked as done needs approval first.
+// If it does, the task stays undone and waits for an approver. Approvers don't need to approve their own changes.
+func (t *Task) updateApprovalStatus(s *xorm.Session, ot *Task, a web.Auth) (requested bool, err error) {
+	t.ApprovalStatus = ot.ApprovalStatus
+
+	if ot.Done && !t.Done {
+		t.ApprovalStatus = TaskApprovalStatusNone
+		return false, nil
+	}
+
+	if ot.Done || !t.Done {
+		return false, nil
+	}
+
+	approvers, err := s.Where("project_id = ?", t.ProjectID).Count(&ProjectApprover{})
+	if err != nil || approvers == 0 {
+		return false, err
+	}
+
+	isApprover, err := isProjectApprover(s, t.ProjectID, a)
+	if err != nil {
+		return false, err
+	}
+	if isApprover {
+		t.ApprovalStatus = TaskApprovalStatusApproved
+		return false, nil
+	}
+
+	t.Done = false
+	requested = ot.ApprovalStatus != TaskApprovalStatusPending
+	t.ApprovalStatus = TaskApprovalStatusPending
+	return requested, nil
+}
+
+// CanCreate checks if a user can approve or reject a task. Only approvers of the task's project can do that.
+func (ta *TaskApproval) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	t, err := GetTaskByIDSimple(s, ta.TaskID)
+	if err != nil {
+		return false, err
+	}
+
+	isApprover, err := isProjectApprover(s, t.ProjectID, a)
+	if err != nil || !isApprover {
+		return false, err
+	}
+
+	// Approvers who lost access to the project can't decide about its tasks anymore
+	return t.CanUpdate(s, a)
+}
+
+// Create approves or rejects a task
+// @Summary Approve or reject a task
+// @Description Approving a task waiting for approval marks it as done. Rejecting it leaves the task undone, it can be marked as done again to ask for another approval. Only approvers of the task's project can decide.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param approval body models.TaskApproval false "An optional reason for the decision."
+// @Success 201 {object} models.TaskApproval "The decision with the updated task."
+// @Failure 403 {object} web.HTTPError "The user is not an approver of the task's project."
+// @Failure 412 {object} web.HTTPError "The task is not waiting for approval."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/approve [post]
+// @Router /tasks/{taskID}/reject [post]
+func (ta *TaskApproval) Create(s *xorm.Session, a web.Auth) (err error) {
+	ta.Task = &Task{ID: ta.TaskID}
+	err = ta.Task.ReadOne(s, a)
+	if err != nil {
+		return err
+	}
+
+	if ta.Task.ApprovalStatus != TaskApprovalStatusPending {
+		return ErrTaskIsNotPendingApproval{TaskID: ta.TaskID}
+	}
+
+	doer, err := user.GetFromAuth(a)
+	if err != nil {
+		return err
+	}
+
+	if ta.Approve {
+		// The approver marking the task as done takes care of everything else, like repeating the task
+		ta.Task.Done = true
+		err = ta.Task.Update(s, a)
+		if err != nil {
+			return err
+		}
+
+		return events.Dispatch(&TaskApprovedEvent{
+			Task:   ta.Task,
+			Doer:   doer,
+			Reason: ta.Reason,
+		})
+	}
+
+	ta.Task.ApprovalStatus = TaskApprovalStatusRejected
+	_, err = s.
+		ID(ta.TaskID).
+		Cols("approval_status").
+		Update(ta.Task)
+	if err != nil {
+		return err
+	}
+
+	return events.Dispatch(&TaskRejectedEvent{
+		Task:   ta.Task,
+		Doer:   doer,
+		Reason: ta.Reason,
+	})
+}
@@ -0,0 +1,287 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskApproval(t *testing.T) {
+	u := &user.User{ID: 1}
+	approver := &user.User{ID: 2}
+
+	// Set up the approver directly, user 2 does not have access to project 1 so we share it with them.
+	addApprover := func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+		_, err := s.Insert(&ProjectApprover{ProjectID: 1, UserID: approver.ID})
+		require.NoError(t, err)
+		_, err = s.Insert(&ProjectUser{ProjectID: 1, UserID: approver.ID, Right: RightWrite})
+		require.NoError(t, err)
+	}
+
+	markDone := func(t *testing.T) *Task {
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 1}
+		err := task.ReadOne(s, u)
+		require.NoError(t, err)
+		task.Done = true
+		err = task.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+		return task
+	}
+
+	t.Run("no approvers", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		task := markDone(t)
+		assert.True(t, task.Done)
+		assert.Equal(t, TaskApprovalStatusNone, task.ApprovalStatus)
+	})
+	t.Run("marking done requests approval", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		events.Fake()
+		addApprover(t)
+
+		task := markDone(t)
+		assert.False(t, task.Done)
+		assert.Equal(t, TaskApprovalStatusPending, task.ApprovalStatus)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":              1,
+			"done":            false,
+			"approval_status": TaskApprovalStatusPending,
+		}, false)
+		events.AssertDispatched(t, &TaskApprovalRequestedEvent{})
+	})
+	t.Run("approve", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		addApprover(t)
+		markDone(t)
+
+		s := db.NewSession()
+		defer s.Close()
+
+		ta := &TaskApproval{TaskID: 1, Approve: true}
+		can, err := ta.CanCreate(s, approver)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = ta.Create(s, approver)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.True(t, ta.Task.Done)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":              1,
+			"done":            true,
+			"approval_status": TaskApprovalStatusApproved,
+		}, false)
+	})
+	t.Run("reject", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		addApprover(t)
+		markDone(t)
+
+		s := db.NewSession()
+		defer s.Close()
+
+		ta := &TaskApproval{TaskID: 1, Reason: "Not quite there yet"}
+		err := ta.Create(s, approver)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":              1,
+			"done":            false,
+			"approval_status": TaskApprovalStatusRejected,
+		}, false)
+	})
+	t.Run("not pending", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		addApprover(t)
+
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&TaskApproval{TaskID: 1, Approve: true}).Create(s, approver)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskIsNotPendingApproval(err))
+	})
+	t.Run("not an approver", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		addApprover(t)
+
+		s := db.NewSession()
+		defer s.Close()
+
+		can, err := (&TaskApproval{TaskID: 1}).CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("approver without access to the project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Insert(&ProjectApprover{ProjectID: 1, UserID: approver.ID})
+		require.NoError(t, err)
+
+		can, err := (&TaskApproval{TaskID: 1}).CanCreate(s, approver)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("creating a done task requests approval", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		events.Fake()
+		addApprover(t)
+
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{Title: "done right away", ProjectID: 1, Done: true}
+		err := task.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.False(t, task.Done)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":              task.ID,
+			"done":            false,
+			"approval_status": TaskApprovalStatusPending,
+		}, false)
+		events.AssertDispatched(t, &TaskApprovalRequestedEvent{})
+	})
+	t.Run("moving into the done bucket requests approval", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		events.Fake()
+		addApprover(t)
+
+		s := db.NewSession()
+		defer s.Close()
+
+		tb := &TaskBucket{
+			TaskID:        1,
+			BucketID:      3, // Bucket 3 is the done bucket
+			ProjectViewID: 4,
+			ProjectID:     1,
+		}
+		err := tb.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.False(t, tb.TaskDone)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":              1,
+			"done":            false,
+			"approval_status": TaskApprovalStatusPending,
+		}, false)
+		db.AssertMissing(t, "task_buckets", map[string]interface{}{
+			"task_id":   1,
+			"bucket_id": 3,
+		})
+		events.AssertDispatched(t, &TaskApprovalRequestedEvent{})
+	})
+	t.Run("bucket action marking the task done requests approval", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		events.Fake()
+		addApprover(t)
+
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Where("id = ?", 1).Cols("actions").Update(&Bucket{Actions: []*BucketAction{
+			{Kind: BucketActionSetDone},
+		}})
+		require.NoError(t, err)
+
+		tb := &TaskBucket{
+			TaskID:        3,
+			BucketID:      1,
+			ProjectViewID: 4,
+			ProjectID:     1,
+		}
+		err = tb.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.False(t, tb.TaskDone)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":              3,
+			"done":            false,
+			"approval_status": TaskApprovalStatusPending,
+		}, false)
+		events.AssertDispatched(t, &TaskApprovalRequestedEvent{})
+	})
+	t.Run("approvers mark done directly", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		s := db.NewSession()
+		_, err := s.Insert(&ProjectApprover{ProjectID: 1, UserID: u.ID})
+		require.NoError(t, err)
+		s.Close()
+
+		task := markDone(t)
+		assert.True(t, task.Done)
+		assert.Equal(t, TaskApprovalStatusApproved, task.ApprovalStatus)
+	})
+}
+
+func TestProjectApprover_Create(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		pa := &ProjectApprover{ProjectID: 1, UserID: 1}
+		can, err := pa.CanCreate(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = pa.Create(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "project_approvers", map[string]interface{}{
+			"project_id": 1,
+			"user_id":    1,
+		}, false)
+	})
+	t.Run("user without access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&ProjectApprover{ProjectID: 1, UserID: 2}).Create(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrUserDoesNotHaveAccessToProject(err))
+	})
+}
@@ -25,6 +25,7 @@ import (
 	"code.vikunja.io/api/pkg/files"
 	"code.vikunja.io/api/pkg/user"
 	"code.vikunja.io/web"
+	"xorm.io/builder"
 	"xorm.io/xorm"
 )
 
@@ -33,6 +34,18 @@ type TaskAttachment struct {
 	ID     int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"attachment"`
 	TaskID int64 `xorm:"bigint not null" json:"task_id" param:"task"`
 	FileID int64 `xorm:"bigint not null" json:"-"`
+	// The id of the comment this file was attached to. 0 if it was attached to the task directly.
+	CommentID int64 `xorm:"bigint null default 0 INDEX" json:"comment_id" param:"commentid"`
+	// The version of the file, starting at 1. Uploading a new version of the attachment increases it, the previous
+	// files are available as versions of the attachment.
+	Version int64 `xorm:"bigint not null default 1" json:"version"`
+
+	// If the attachment is a link to an external resource instead of a file, this holds its url. Links don't have a file.
+	URL string `xorm:"text null" json:"url"`
+	// The title of a link attachment. If none is provided when creating the link, it is taken from the linked page.
+	Title string `xorm:"varchar(250) null" json:"title" valid:"runelength(0|250)" maxLength:"250"`
+	// The url of the favicon of the linked page.
+	FaviconURL string `xorm:"text null" json:"favicon_url"`
 
 	CreatedByID int64      `xorm:"bigint not null" json:"-"`
 	CreatedBy   *user.User `xorm:"-" json:"created_by"`
@@ -54,14 +67,63 @@ func (*TaskAttachment) TableName() string {
 // Note: I'm not sure if only accepting an io.ReadCloser and not an afero.File or os.File instead is a good way of doing things.
 func (ta *TaskAttachment) NewAttachment(s *xorm.Session, f io.ReadCloser, realname string, realsize uint64, a web.Auth) error {
 
+	task, err := ta.checkNewAttachment(s, realsize, a)
+	if err != nil {
+		return err
+	}
+
 	// Store the file
 	file, err := files.Create(f, realname, realsize, a)
 	if err != nil {
 		if files.IsErrFileIsTooLarge(err) {
 			return ErrTaskAttachmentIsTooLarge{Size: realsize}
 		}
+		if infected, is := err.(files.ErrFileIsInfected); is {
+			return ErrTaskAttachmentIsInfected{Name: infected.Name, Signature: infected.Signature}
+		}
 		return err
 	}
+
+	return ta.insertWithFile(s, task, file, a)
+}
+
+// newAttachmentFromFile attaches a copy of an existing file. The copy shares the stored content with the original.
+func (ta *TaskAttachment) newAttachmentFromFile(s *xorm.Session, original *files.File, a web.Auth) error {
+	task, err := ta.checkNewAttachment(s, original.Size, a)
+	if err != nil {
+		return err
+	}
+
+	file, err := original.Copy(s, a)
+	if err != nil {
+		return err
+	}
+
+	return ta.insertWithFile(s, task, file, a)
+}
+
+// checkNewAttachment makes sure an attachment of that size can be added to the task by the doer and returns the task
+func (ta *TaskAttachment) checkNewAttachment(s *xorm.Session, size uint64, a web.Auth) (task *Task, err error) {
+	t, err := GetTaskByIDSimple(s, ta.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	err = ta.checkComment(s)
+	if err != nil {
+		return nil, err
+	}
+	project, err := GetProjectSimpleByID(s, t.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	err = checkAttachmentQuotas(s, project, a, size)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (ta *TaskAttachment) insertWithFile(s *xorm.Session, task *Task, file *files.File, a web.Auth) (err error) {
 	ta.File = file
 
 	// Add an entry to the db
@@ -76,6 +138,7 @@ func (ta *TaskAttachment) NewAttachment(s *xorm.Session, f io.ReadCloser, realna
 		return err
 	}
 	ta.CreatedByID = ta.CreatedBy.ID
+	ta.Version = 1
 
 	_, err = s.Insert(ta)
 	if err != nil {
@@ -86,18 +149,64 @@ func (ta *TaskAttachment) NewAttachment(s *xorm.Session, f io.ReadCloser, realna
 		return err
 	}
 
-	task, err := GetTaskByIDSimple(s, ta.TaskID)
-	if err != nil {
-		return err
-	}
-
 	return events.Dispatch(&TaskAttachmentCreatedEvent{
-		Task:       &task,
+		Task:       task,
 		Attachment: ta,
 		Doer:       ta.CreatedBy,
 	})
 }
 
+// checkComment makes sure the comment an attachment is added to belongs to the same task
+func (ta *TaskAttachment) checkComment(s *xorm.Session) error {
+	if ta.CommentID == 0 {
+		return nil
+	}
+
+	comment := &TaskComment{ID: ta.CommentID}
+	err := getTaskCommentSimple(s, comment)
+	if err != nil {
+		return err
+	}
+	if comment.TaskID != ta.TaskID {
+		return ErrTaskCommentDoesNotExist{ID: ta.CommentID, TaskID: ta.TaskID}
+	}
+	return nil
+}
+
+// Thumbnail returns a scaled down version of an image attachment. The attachment needs to be loaded already.
+func (ta *TaskAttachment) Thumbnail(size string) (thumbnail io.ReadSeekCloser, mime string, err error) {
+	if size == "" {
+		size = "medium"
+	}
+
+	if ta.IsLink() {
+		return nil, "", ErrTaskAttachmentIsNotAnImage{AttachmentID: ta.ID}
+	}
+
+	thumbnail, mime, err = ta.File.Thumbnail(size)
+	if files.IsErrInvalidThumbnailSize(err) {
+		return nil, "", ErrInvalidTaskAttachmentThumbnailSize{Size: size}
+	}
+	if files.IsErrFileIsNotAnImage(err) {
+		return nil, "", ErrTaskAttachmentIsNotAnImage{AttachmentID: ta.ID}
+	}
+	return
+}
+
+// Preview returns the first page of a pdf attachment as image or the beginning of a text attachment.
+// The attachment needs to be loaded already.
+func (ta *TaskAttachment) Preview() (preview io.ReadSeekCloser, mime string, err error) {
+	if ta.IsLink() {
+		return nil, "", ErrTaskAttachmentHasNoPreview{AttachmentID: ta.ID}
+	}
+
+	preview, mime, err = ta.File.Preview()
+	if files.IsErrFileHasNoPreview(err) {
+		return nil, "", ErrTaskAttachmentHasNoPreview{AttachmentID: ta.ID}
+	}
+	return
+}
+
 // ReadOne returns a task attachment
 func (ta *TaskAttachment) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
 	exists, err := s.Where("id = ?", ta.ID).Get(ta)
@@ -111,6 +220,10 @@ func (ta *TaskAttachment) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
 		}
 	}
 
+	if ta.IsLink() {
+		return nil
+	}
+
 	// Get the file
 	ta.File = &files.File{ID: ta.FileID}
 	err = ta.File.LoadFileMetaByID()
@@ -119,7 +232,7 @@ func (ta *TaskAttachment) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
 
 // ReadAll returns a project with all attachments
 // @Summary Get  all attachments for one task.
-// @Description Get all task attachments for one task.
+// @Description Get all task attachments for one task. This includes the files attached to comments, those have the id of the comment set. Link attachments are listed as well, they have a url instead of a file.
 // @tags task
 // @Accept json
 // @Produce json
@@ -214,16 +327,23 @@ func (ta *TaskAttachment) Delete(s *xorm.Session, a web.Auth) error {
 		return err
 	}
 
-	// Delete the underlying file
-	err = ta.File.Delete()
-	// If the file does not exist, we don't want to error out
-	if err != nil && files.IsErrFileDoesNotExist(err) {
-		return nil
-	}
+	err = deleteTaskAttachmentVersions(s, ta.ID)
 	if err != nil {
 		return err
 	}
 
+	// Delete the underlying file
+	if !ta.IsLink() {
+		err = ta.File.Delete()
+		// If the file does not exist, we don't want to error out
+		if err != nil && files.IsErrFileDoesNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
 	doer, _ := user.GetFromAuth(a)
 	task, err := GetTaskByIDSimple(s, ta.TaskID)
 	if err != nil {
@@ -238,9 +358,58 @@ func (ta *TaskAttachment) Delete(s *xorm.Session, a web.Auth) error {
 }
 
 func getTaskAttachmentsByTaskIDs(s *xorm.Session, taskIDs []int64) (attachments []*TaskAttachment, err error) {
+	return getTaskAttachments(s, builder.In("task_id", taskIDs))
+}
+
+// addAttachmentsToComments puts all attachments which were uploaded to one of the comments onto it.
+func addAttachmentsToComments(s *xorm.Session, comments []*TaskComment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	commentIDs := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		commentIDs = append(commentIDs, comment.ID)
+	}
+
+	attachments, err := getTaskAttachments(s, builder.In("comment_id", commentIDs))
+	if err != nil {
+		return err
+	}
+
+	byComment := make(map[int64][]*TaskAttachment, len(comments))
+	for _, a := range attachments {
+		byComment[a.CommentID] = append(byComment[a.CommentID], a)
+	}
+	for _, comment := range comments {
+		comment.Attachments = byComment[comment.ID]
+	}
+
+	return nil
+}
+
+// deleteCommentAttachments removes all attachments matching the condition, including their files.
+func deleteCommentAttachments(s *xorm.Session, a web.Auth, cond builder.Cond) error {
+	attachments := []*TaskAttachment{}
+	err := s.Where(cond).Find(&attachments)
+	if err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		err = attachment.Delete(s, a)
+		if err != nil && !IsErrTaskAttachmentDoesNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getTaskAttachments(s *xorm.Session, cond builder.Cond) (attachments []*TaskAttachment, err error) {
 	attachments = []*TaskAttachment{}
 	err = s.
-		In("task_id", taskIDs).
+		Where(cond).
 		Find(&attachments)
 	if err != nil {
 		return
@@ -0,0 +1,248 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/version"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+const (
+	// Only the head of a page is needed to find its title and favicon
+	linkMetadataMaxBytes = 512 * 1024
+	linkMetadataTimeout  = 5 * time.Second
+)
+
+var (
+	// The links are provided by users, the server must not be used to reach into the network it runs in.
+	// Checking the address right before connecting also covers redirects and dns names resolving to internal hosts.
+	linkMetadataClient = &http.Client{
+		Timeout: linkMetadataTimeout,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: linkMetadataTimeout,
+				Control: rejectInternalAddresses,
+			}).DialContext,
+		},
+	}
+
+	linkTitleRegex     = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	linkTagRegex       = regexp.MustCompile(`(?is)<link\s[^>]*>`)
+	linkAttributeRegex = regexp.MustCompile(`(?is)(rel|href)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+)
+
+// IsLink returns true if the attachment points to an external url instead of a file
+func (ta *TaskAttachment) IsLink() bool {
+	return ta.URL != ""
+}
+
+// Create adds a link as attachment to a task
+// @Summary Add a link attachment
+// @Description Adds an external url like a shared document or a commit as attachment to a task. Links are stored without a file and listed with the other attachments. If no title or favicon is provided, Vikunja takes them from the linked page.
+// @tags task
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param commentID path int false "Comment ID, only when adding the link to a comment"
+// @Param link body models.TaskAttachment true "The link, only url, title and favicon_url are used."
+// @Security JWTKeyAuth
+// @Success 201 {object} models.TaskAttachment "The created link attachment."
+// @Failure 400 {object} web.HTTPError "The url is invalid."
+// @Failure 403 {object} models.Message "No access to the task."
+// @Failure 404 {object} models.Message "The task does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/attachments/links [put]
+// @Router /tasks/{id}/comments/{commentID}/attachments/links [put]
+func (ta *TaskAttachment) Create(s *xorm.Session, a web.Auth) (err error) {
+	link, err := parseHTTPURL(ta.URL)
+	if err != nil {
+		return ErrInvalidTaskAttachmentLinkURL{URL: ta.URL}
+	}
+	if ta.FaviconURL != "" {
+		favicon, err := parseHTTPURL(ta.FaviconURL)
+		if err != nil {
+			return ErrInvalidTaskAttachmentLinkURL{URL: ta.FaviconURL}
+		}
+		ta.FaviconURL = favicon.String()
+	}
+
+	task, err := GetTaskByIDSimple(s, ta.TaskID)
+	if err != nil {
+		return err
+	}
+	err = ta.checkComment(s)
+	if err != nil {
+		return err
+	}
+
+	ta.ID = 0
+	ta.FileID = 0
+	ta.File = nil
+	ta.Version = 1
+	ta.URL = link.String()
+	ta.Title = strings.TrimSpace(ta.Title)
+
+	if (ta.Title == "" || ta.FaviconURL == "") && config.ServiceFetchLinkMetadata.GetBool() {
+		title, favicon, err := fetchLinkMetadata(link)
+		if err != nil {
+			log.Debugf("Could not get the metadata of link %s: %s", ta.URL, err)
+		}
+		if ta.Title == "" {
+			ta.Title = title
+		}
+		if ta.FaviconURL == "" {
+			ta.FaviconURL = favicon
+		}
+	}
+	if ta.FaviconURL == "" {
+		ta.FaviconURL = link.ResolveReference(&url.URL{Path: "/favicon.ico"}).String()
+	}
+	ta.Title = truncateRunes(ta.Title, 250)
+
+	ta.CreatedBy, err = GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return err
+	}
+	ta.CreatedByID = ta.CreatedBy.ID
+
+	_, err = s.Insert(ta)
+	if err != nil {
+		return err
+	}
+
+	return events.Dispatch(&TaskAttachmentCreatedEvent{
+		Task:       &task,
+		Attachment: ta,
+		Doer:       ta.CreatedBy,
+	})
+}
+
+var errNoHTTPURL = errors.New("not an http or https url")
+
+func parseHTTPURL(raw string) (*url.URL, error) {
+	link, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, err
+	}
+	if (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+		return nil, errNoHTTPURL
+	}
+	return link, nil
+}
+
+func rejectInternalAddresses(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil ||
+		ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() {
+		return fmt.Errorf("refusing to connect to internal address %s", host)
+	}
+	return nil
+}
+
+func truncateRunes(text string, length int) string {
+	if utf8.RuneCountInString(text) <= length {
+		return text
+	}
+	return string([]rune(text)[:length])
+}
+
+// fetchLinkMetadata requests a page and returns its title and the absolute url of its favicon
+func fetchLinkMetadata(link *url.URL) (title, favicon string, err error) {
+	req, err := http.NewRequest(http.MethodGet, link.String(), nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("User-Agent", "Vikunja/"+version.Version)
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := linkMetadataClient.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 || !strings.Contains(resp.Header.Get("Content-Type"), "text/html") {
+		return "", "", nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, linkMetadataMaxBytes))
+	if err != nil {
+		return "", "", err
+	}
+
+	// Redirects change the url relative favicon paths are resolved against
+	title, favicon = parseLinkMetadata(string(body), resp.Request.URL)
+	return title, favicon, nil
+}
+
+func parseLinkMetadata(page string, base *url.URL) (title, favicon string) {
+	if match := linkTitleRegex.FindStringSubmatch(page); match != nil {
+		title = strings.Join(strings.Fields(html.UnescapeString(match[1])), " ")
+	}
+
+	for _, tag := range linkTagRegex.FindAllString(page, -1) {
+		var rel, href string
+		for _, attr := range linkAttributeRegex.FindAllStringSubmatch(tag, -1) {
+			value := attr[2] + attr[3] + attr[4]
+			if strings.EqualFold(attr[1], "rel") {
+				rel = strings.ToLower(value)
+			} else {
+				href = html.UnescapeString(value)
+			}
+		}
+
+		if href == "" || !slices.Contains(strings.Fields(rel), "icon") {
+			continue
+		}
+
+		iconURL, err := base.Parse(href)
+		if err != nil || (iconURL.Scheme != "http" && iconURL.Scheme != "https") {
+			continue
+		}
+		return title, iconURL.String()
+	}
+
+	return title, ""
+}
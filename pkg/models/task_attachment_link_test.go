@@ -0,0 +1,174 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskAttachment_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = fmt.Fprint(w, `<html><head>
+<title>Quarterly report &amp; numbers</title>
+<link rel="stylesheet" href="/style.css">
+<link href="/static/icon.png" rel="shortcut icon">
+</head><body></body></html>`)
+	}))
+	defer server.Close()
+
+	// The test server listens on localhost which the metadata client refuses to connect to
+	guardedClient := linkMetadataClient
+	linkMetadataClient = server.Client()
+	defer func() {
+		linkMetadataClient = guardedClient
+	}()
+
+	t.Run("with metadata from the page", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ta := &TaskAttachment{TaskID: 1, URL: server.URL + "/docs/report"}
+		err := ta.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, "Quarterly report & numbers", ta.Title)
+		assert.Equal(t, server.URL+"/static/icon.png", ta.FaviconURL)
+		assert.Equal(t, int64(0), ta.FileID)
+		assert.True(t, ta.IsLink())
+
+		require.NoError(t, s.Commit())
+		db.AssertExists(t, "task_attachments", map[string]interface{}{
+			"id":      ta.ID,
+			"task_id": 1,
+			"url":     server.URL + "/docs/report",
+			"file_id": 0,
+		}, false)
+	})
+	t.Run("keeps the provided title", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ta := &TaskAttachment{TaskID: 1, URL: server.URL, Title: "Report"}
+		err := ta.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, "Report", ta.Title)
+		assert.Equal(t, server.URL+"/static/icon.png", ta.FaviconURL)
+	})
+	t.Run("invalid url", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ta := &TaskAttachment{TaskID: 1, URL: "javascript:alert(1)"}
+		err := ta.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskAttachmentLinkURL(err))
+	})
+	t.Run("invalid favicon url", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ta := &TaskAttachment{TaskID: 1, URL: server.URL, FaviconURL: "javascript:alert(1)"}
+		err := ta.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskAttachmentLinkURL(err))
+	})
+	t.Run("no metadata from internal addresses", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		linkMetadataClient = guardedClient
+		defer func() {
+			linkMetadataClient = server.Client()
+		}()
+
+		ta := &TaskAttachment{TaskID: 1, URL: server.URL + "/docs/report"}
+		err := ta.Create(s, u)
+		require.NoError(t, err)
+		assert.Empty(t, ta.Title)
+		assert.Equal(t, server.URL+"/favicon.ico", ta.FaviconURL)
+	})
+	t.Run("listed with the other attachments", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ta := &TaskAttachment{TaskID: 1, URL: server.URL, Title: "Report"}
+		err := ta.Create(s, u)
+		require.NoError(t, err)
+
+		list := &TaskAttachment{TaskID: 1}
+		result, _, _, err := list.ReadAll(s, u, "", 0, 50)
+		require.NoError(t, err)
+		attachments := result.([]*TaskAttachment)
+		assert.Len(t, attachments, 4)
+		for _, a := range attachments {
+			if a.ID == ta.ID {
+				assert.Equal(t, server.URL, a.URL)
+				assert.Nil(t, a.File)
+			}
+		}
+	})
+	t.Run("delete", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ta := &TaskAttachment{TaskID: 1, URL: server.URL, Title: "Report"}
+		err := ta.Create(s, u)
+		require.NoError(t, err)
+
+		err = (&TaskAttachment{TaskID: 1, ID: ta.ID}).Delete(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		db.AssertMissing(t, "task_attachments", map[string]interface{}{
+			"id": ta.ID,
+		})
+	})
+}
+
+func TestParseLinkMetadata(t *testing.T) {
+	base, _ := url.Parse("https://example.com/some/page")
+
+	t.Run("no favicon", func(t *testing.T) {
+		title, favicon := parseLinkMetadata(`<title>
+			Some   page
+		</title>`, base)
+		assert.Equal(t, "Some page", title)
+		assert.Empty(t, favicon)
+	})
+	t.Run("relative favicon", func(t *testing.T) {
+		_, favicon := parseLinkMetadata(`<link rel='icon' href='favicon.svg'>`, base)
+		assert.Equal(t, "https://example.com/some/favicon.svg", favicon)
+	})
+}
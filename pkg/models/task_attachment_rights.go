@@ -35,9 +35,21 @@ func (ta *TaskAttachment) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
 
 // CanCreate checks if the user can create an attachment
 func (ta *TaskAttachment) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	// Only the author of a comment can add files to it
+	if ta.CommentID != 0 {
+		tc := &TaskComment{ID: ta.CommentID, TaskID: ta.TaskID}
+		return tc.canUserModifyTaskComment(s, a)
+	}
+
 	t, err := GetTaskByIDSimple(s, ta.TaskID)
 	if err != nil {
 		return false, err
 	}
 	return t.CanCreate(s, a)
 }
+
+// CanUpdate checks if the user can upload a new version of an attachment
+func (ta *TaskAttachment) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	t := &Task{ID: ta.TaskID}
+	return t.CanWrite(s, a)
+}
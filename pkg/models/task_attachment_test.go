@@ -277,3 +277,63 @@ func TestTaskAttachment_Rights(t *testing.T) {
 		})
 	})
 }
+
+func TestTaskAttachment_Comment(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		files.InitTestFileFixtures(t)
+
+		ta := &TaskAttachment{TaskID: 1, CommentID: 1}
+		can, err := ta.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = ta.NewAttachment(s, &testfile{content: []byte("testingstuff")}, "testfile", 100, u)
+		require.NoError(t, err)
+
+		comment := &TaskComment{ID: 1, TaskID: 1}
+		err = comment.ReadOne(s, u)
+		require.NoError(t, err)
+		require.Len(t, comment.Attachments, 1)
+		assert.Equal(t, ta.ID, comment.Attachments[0].ID)
+
+		// The file is part of the task attachments as well
+		as, _, _, err := (&TaskAttachment{TaskID: 1}).ReadAll(s, u, "", 0, 50)
+		require.NoError(t, err)
+		attachments := as.([]*TaskAttachment)
+		require.Len(t, attachments, 4)
+		assert.Equal(t, int64(1), attachments[3].CommentID)
+
+		err = comment.Delete(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		db.AssertMissing(t, "task_attachments", map[string]interface{}{"id": ta.ID})
+	})
+	t.Run("comment of another task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+		files.InitTestFileFixtures(t)
+
+		ta := &TaskAttachment{TaskID: 2, CommentID: 1}
+		err := ta.NewAttachment(s, &testfile{content: []byte("testingstuff")}, "testfile", 100, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskCommentDoesNotExist(err))
+	})
+	t.Run("not the author of the comment", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		other := &TaskComment{Comment: "other", TaskID: 1, AuthorID: 2}
+		_, err := s.Insert(other)
+		require.NoError(t, err)
+
+		can, err := (&TaskAttachment{TaskID: 1, CommentID: other.ID}).CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
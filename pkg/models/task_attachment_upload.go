@@ -0,0 +1,302 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"io"
+	"time"
+
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/log"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// Uploads which did not receive a chunk for this long are removed.
+const taskAttachmentUploadTTL = 24 * time.Hour
+
+// TaskAttachmentUpload is a resumable upload of a task attachment. The file is sent in chunks which are kept until
+// all of them arrived, after that they are assembled into a regular attachment and the upload is removed.
+type TaskAttachmentUpload struct {
+	// The unique, numeric id of this upload.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"upload"`
+	// The task the attachment will be added to.
+	TaskID int64 `xorm:"bigint not null INDEX" json:"task_id" param:"task"`
+	// The comment the attachment will be added to. 0 to attach it to the task directly.
+	CommentID int64 `xorm:"bigint null default 0" json:"comment_id"`
+	// The name of the file.
+	FileName string `xorm:"text not null" json:"file_name" valid:"required,runelength(1|250)"`
+	// The total size of the file in bytes.
+	Size uint64 `xorm:"bigint not null" json:"size" valid:"required"`
+	// How many bytes were received so far. This is the offset the next chunk needs to start at.
+	Received uint64 `xorm:"bigint not null default 0" json:"received"`
+	Chunks   int    `xorm:"int not null default 0" json:"-"`
+
+	CreatedByID int64 `xorm:"bigint not null" json:"-"`
+
+	// The attachment created from all chunks, only set in the response to the last chunk.
+	Attachment *TaskAttachment `xorm:"-" json:"attachment,omitempty"`
+
+	// When the upload will be removed if it does not receive any more chunks until then.
+	Expires time.Time `xorm:"not null" json:"expires"`
+	Created time.Time `xorm:"created not null" json:"created"`
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for task attachment uploads
+func (*TaskAttachmentUpload) TableName() string {
+	return "task_attachment_uploads"
+}
+
+func getTaskAttachmentUpload(s *xorm.Session, id, taskID int64) (upload *TaskAttachmentUpload, err error) {
+	upload = &TaskAttachmentUpload{}
+	exists, err := s.
+		Where("id = ? AND task_id = ? AND expires > ?", id, taskID, time.Now()).
+		Get(upload)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrTaskAttachmentUploadDoesNotExist{UploadID: id, TaskID: taskID}
+	}
+	return upload, nil
+}
+
+// Create starts a new resumable upload
+// @Summary Start a resumable attachment upload
+// @Description Starts an upload of a task attachment which is sent in chunks. Send the chunks with `PATCH /tasks/{id}/attachments/uploads/{uploadID}`. Uploads expire 24 hours after the last chunk was received.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Task ID"
+// @Param upload body models.TaskAttachmentUpload true "The file name and total size of the file."
+// @Success 201 {object} models.TaskAttachmentUpload "The upload."
+// @Failure 400 {object} web.HTTPError "Invalid upload object provided or the file is too large."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/attachments/uploads [put]
+func (tu *TaskAttachmentUpload) Create(s *xorm.Session, a web.Auth) (err error) {
+	err = files.CheckFileSize(tu.Size)
+	if files.IsErrFileIsTooLarge(err) {
+		return ErrTaskAttachmentIsTooLarge{Size: tu.Size}
+	}
+	if err != nil {
+		return err
+	}
+
+	task, err := GetTaskByIDSimple(s, tu.TaskID)
+	if err != nil {
+		return err
+	}
+	project, err := GetProjectSimpleByID(s, task.ProjectID)
+	if err != nil {
+		return err
+	}
+	err = checkAttachmentQuotas(s, project, a, tu.Size)
+	if err != nil {
+		return err
+	}
+
+	doer, err := GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return err
+	}
+
+	tu.ID = 0
+	tu.Received = 0
+	tu.Chunks = 0
+	tu.CreatedByID = doer.ID
+	tu.Expires = time.Now().Add(taskAttachmentUploadTTL)
+
+	_, err = s.Insert(tu)
+	return
+}
+
+// ReadOne returns an upload, clients use it to find out where to continue after an interrupted chunk
+// @Summary Get a resumable attachment upload
+// @Description Returns the state of an upload. The `received` field is the offset the next chunk needs to start at.
+// @tags task
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Task ID"
+// @Param uploadID path int true "Upload ID"
+// @Success 200 {object} models.TaskAttachmentUpload "The upload."
+// @Failure 403 {object} web.HTTPError "The upload was not started by the user."
+// @Failure 404 {object} web.HTTPError "The upload does not exist or has expired."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/attachments/uploads/{uploadID} [get]
+func (tu *TaskAttachmentUpload) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	upload, err := getTaskAttachmentUpload(s, tu.ID, tu.TaskID)
+	if err != nil {
+		return err
+	}
+	*tu = *upload
+	return nil
+}
+
+// Delete cancels an upload
+// @Summary Cancel a resumable attachment upload
+// @Description Cancels an upload and removes all chunks received so far.
+// @tags task
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Task ID"
+// @Param uploadID path int true "Upload ID"
+// @Success 200 {object} models.Message "The upload was canceled."
+// @Failure 403 {object} web.HTTPError "The upload was not started by the user."
+// @Failure 404 {object} web.HTTPError "The upload does not exist or has expired."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/attachments/uploads/{uploadID} [delete]
+func (tu *TaskAttachmentUpload) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	upload, err := getTaskAttachmentUpload(s, tu.ID, tu.TaskID)
+	if err != nil {
+		return err
+	}
+	return upload.remove(s)
+}
+
+func (tu *TaskAttachmentUpload) remove(s *xorm.Session) (err error) {
+	_, err = s.Where("id = ?", tu.ID).Delete(&TaskAttachmentUpload{})
+	if err != nil {
+		return err
+	}
+	return files.DeleteUploadChunks(tu.ID, tu.Chunks)
+}
+
+// AddChunk appends a chunk to the upload. The chunk has to start exactly where the previous one ended.
+// Once all bytes were received, the chunks are assembled into the attachment.
+func (tu *TaskAttachmentUpload) AddChunk(s *xorm.Session, offset uint64, chunk io.Reader, a web.Auth) (err error) {
+	upload, err := getTaskAttachmentUpload(s, tu.ID, tu.TaskID)
+	if err != nil {
+		return err
+	}
+	*tu = *upload
+
+	if offset != tu.Received {
+		return ErrTaskAttachmentUploadOffsetMismatch{UploadID: tu.ID, Expected: tu.Received, Given: offset}
+	}
+
+	// Reading one byte more than what is left makes it possible to detect chunks which are too large
+	remaining := tu.Size - tu.Received
+	counter := &countingReader{r: io.LimitReader(chunk, int64(remaining)+1)}
+	err = files.SaveUploadChunk(tu.ID, tu.Chunks, counter)
+	if err != nil {
+		return err
+	}
+	if counter.n > remaining {
+		_ = files.DeleteUploadChunk(tu.ID, tu.Chunks)
+		return ErrTaskAttachmentUploadChunkTooLarge{UploadID: tu.ID, Size: tu.Size}
+	}
+	if counter.n == 0 {
+		return files.DeleteUploadChunk(tu.ID, tu.Chunks)
+	}
+
+	// The offset in the condition makes sure concurrent requests for the same chunk don't both count.
+	tu.Chunks++
+	tu.Received += counter.n
+	tu.Expires = time.Now().Add(taskAttachmentUploadTTL)
+	updated, err := s.
+		Where("id = ? AND received = ?", tu.ID, offset).
+		Cols("chunks", "received", "expires").
+		Update(tu)
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrTaskAttachmentUploadOffsetMismatch{UploadID: tu.ID, Expected: tu.Received, Given: offset}
+	}
+
+	if tu.Received < tu.Size {
+		return nil
+	}
+
+	return tu.assemble(s, a)
+}
+
+func (tu *TaskAttachmentUpload) assemble(s *xorm.Session, a web.Auth) (err error) {
+	content := files.OpenUploadChunks(tu.ID, tu.Chunks)
+	defer content.Close()
+
+	attachment := &TaskAttachment{
+		TaskID:    tu.TaskID,
+		CommentID: tu.CommentID,
+	}
+	err = attachment.NewAttachment(s, content, tu.FileName, tu.Size, a)
+	if err != nil {
+		return err
+	}
+	tu.Attachment = attachment
+
+	return tu.remove(s)
+}
+
+type countingReader struct {
+	r io.Reader
+	n uint64
+}
+
+func (c *countingReader) Read(p []byte) (n int, err error) {
+	n, err = c.r.Read(p)
+	c.n += uint64(n)
+	return
+}
+
+// RegisterTaskAttachmentUploadCleanupCron removes all expired uploads and their chunks
+func RegisterTaskAttachmentUploadCleanupCron() {
+	const logPrefix = "[Task Attachment Upload Cleanup Cron] "
+
+	err := cron.Schedule("0 * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		uploads := []*TaskAttachmentUpload{}
+		err := s.Where("expires <= ?", time.Now()).Find(&uploads)
+		if err != nil {
+			log.Errorf(logPrefix+"Could not get expired uploads: %s", err)
+			return
+		}
+
+		if len(uploads) == 0 {
+			return
+		}
+
+		log.Debugf(logPrefix+"Removing %d expired uploads...", len(uploads))
+
+		for _, upload := range uploads {
+			err = upload.remove(s)
+			if err != nil {
+				log.Errorf(logPrefix+"Could not remove upload %d: %s", upload.ID, err)
+				_ = s.Rollback()
+				return
+			}
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf(logPrefix+"Could not commit: %s", err)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register task attachment upload cleanup cron: %s", err)
+	}
+}
@@ -0,0 +1,58 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanCreate checks if a user can start an upload, which is the case if they can upload attachments to the task
+func (tu *TaskAttachmentUpload) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	ta := &TaskAttachment{TaskID: tu.TaskID, CommentID: tu.CommentID}
+	return ta.CanCreate(s, a)
+}
+
+// CanRead checks if a user can get the state of an upload
+func (tu *TaskAttachmentUpload) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	is, err := tu.isCreator(s, a)
+	return is, int(RightWrite), err
+}
+
+// CanUpdate checks if a user can add chunks to an upload
+func (tu *TaskAttachmentUpload) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return tu.isCreator(s, a)
+}
+
+// CanDelete checks if a user can cancel an upload
+func (tu *TaskAttachmentUpload) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return tu.isCreator(s, a)
+}
+
+// Only whoever started an upload can continue it. Access to the task is checked again once the attachment is created.
+func (tu *TaskAttachmentUpload) isCreator(s *xorm.Session, a web.Auth) (bool, error) {
+	upload, err := getTaskAttachmentUpload(s, tu.ID, tu.TaskID)
+	if err != nil {
+		return false, err
+	}
+
+	doer, err := GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return false, err
+	}
+	return upload.CreatedByID == doer.ID, nil
+}
@@ -0,0 +1,112 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskAttachmentUpload(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("upload in chunks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		upload := &TaskAttachmentUpload{TaskID: 1, FileName: "large.txt", Size: 11}
+		err := upload.Create(s, u)
+		require.NoError(t, err)
+
+		err = upload.AddChunk(s, 0, strings.NewReader("hello "), u)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(6), upload.Received)
+		assert.Nil(t, upload.Attachment)
+
+		err = upload.AddChunk(s, 6, strings.NewReader("world"), u)
+		require.NoError(t, err)
+		require.NotNil(t, upload.Attachment)
+		assert.Equal(t, "large.txt", upload.Attachment.File.Name)
+
+		err = upload.Attachment.File.LoadFileByID()
+		require.NoError(t, err)
+		content, err := io.ReadAll(upload.Attachment.File.File)
+		require.NoError(t, err)
+		assert.Equal(t, "hello world", string(content))
+
+		require.NoError(t, s.Commit())
+		db.AssertExists(t, "task_attachments", map[string]interface{}{
+			"id":      upload.Attachment.ID,
+			"task_id": 1,
+		}, false)
+		db.AssertMissing(t, "task_attachment_uploads", map[string]interface{}{
+			"id": upload.ID,
+		})
+	})
+	t.Run("wrong offset", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		upload := &TaskAttachmentUpload{TaskID: 1, FileName: "large.txt", Size: 11}
+		err := upload.Create(s, u)
+		require.NoError(t, err)
+
+		err = upload.AddChunk(s, 3, strings.NewReader("hello "), u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskAttachmentUploadOffsetMismatch(err))
+	})
+	t.Run("chunk larger than the file", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		upload := &TaskAttachmentUpload{TaskID: 1, FileName: "large.txt", Size: 5}
+		err := upload.Create(s, u)
+		require.NoError(t, err)
+
+		err = upload.AddChunk(s, 0, strings.NewReader("hello world"), u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskAttachmentUploadChunkTooLarge(err))
+	})
+	t.Run("only the creator can continue", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		upload := &TaskAttachmentUpload{TaskID: 32, FileName: "large.txt", Size: 5}
+		err := upload.Create(s, &user.User{ID: 2})
+		require.NoError(t, err)
+
+		can, err := (&TaskAttachmentUpload{ID: upload.ID, TaskID: 32}).CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
@@ -0,0 +1,307 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"io"
+	"time"
+
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/user"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// TaskAttachmentVersion is a previous file of an attachment. Uploading a new version of an attachment keeps the
+// file it had before as version, so that it can still be downloaded or restored.
+type TaskAttachmentVersion struct {
+	ID           int64 `xorm:"bigint autoincr not null unique pk" json:"id"`
+	TaskID       int64 `xorm:"-" json:"-" param:"task"`
+	AttachmentID int64 `xorm:"bigint not null INDEX" json:"attachment_id" param:"attachment"`
+	// The number of this version, the file an attachment was created with is version 1.
+	Version int64 `xorm:"bigint not null" json:"version" param:"version"`
+	FileID  int64 `xorm:"bigint not null" json:"-"`
+
+	File *files.File `xorm:"-" json:"file"`
+	// The user who uploaded this version.
+	CreatedBy *user.User `xorm:"-" json:"created_by"`
+
+	// When this version was replaced by a newer one.
+	Replaced time.Time `xorm:"created not null" json:"replaced"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for task attachment versions
+func (*TaskAttachmentVersion) TableName() string {
+	return "task_attachment_versions"
+}
+
+// NewVersion replaces the file of an attachment with a new one. The previous file is kept as a version of the attachment.
+func (ta *TaskAttachment) NewVersion(s *xorm.Session, f io.ReadCloser, realname string, realsize uint64, a web.Auth) (err error) {
+	current, err := getTaskAttachmentByTaskAndID(s, ta.TaskID, ta.ID)
+	if err != nil {
+		return err
+	}
+	*ta = *current
+
+	if ta.IsLink() {
+		return ErrTaskAttachmentIsALink{AttachmentID: ta.ID}
+	}
+
+	task, err := GetTaskByIDSimple(s, ta.TaskID)
+	if err != nil {
+		return err
+	}
+	project, err := GetProjectSimpleByID(s, task.ProjectID)
+	if err != nil {
+		return err
+	}
+	err = checkAttachmentQuotas(s, project, a, realsize)
+	if err != nil {
+		return err
+	}
+
+	file, err := files.Create(f, realname, realsize, a)
+	if err != nil {
+		if files.IsErrFileIsTooLarge(err) {
+			return ErrTaskAttachmentIsTooLarge{Size: realsize}
+		}
+		if infected, is := err.(files.ErrFileIsInfected); is {
+			return ErrTaskAttachmentIsInfected{Name: infected.Name, Signature: infected.Signature}
+		}
+		return err
+	}
+
+	previous := &TaskAttachmentVersion{
+		AttachmentID: ta.ID,
+		Version:      ta.Version,
+		FileID:       ta.FileID,
+	}
+	_, err = s.Insert(previous)
+	if err != nil {
+		_ = file.Delete()
+		return err
+	}
+
+	ta.FileID = file.ID
+	ta.File = file
+	ta.Version++
+	_, err = s.
+		Where("id = ?", ta.ID).
+		Cols("file_id", "version").
+		Update(ta)
+	if err != nil {
+		_ = file.Delete()
+	}
+	return err
+}
+
+func getTaskAttachmentVersion(s *xorm.Session, taskID, attachmentID, version int64) (v *TaskAttachmentVersion, err error) {
+	// Makes sure the attachment actually belongs to the task the rights were checked for
+	_, err = getTaskAttachmentByTaskAndID(s, taskID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	v = &TaskAttachmentVersion{}
+	exists, err := s.
+		Where("attachment_id = ? AND version = ?", attachmentID, version).
+		Get(v)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrTaskAttachmentVersionDoesNotExist{AttachmentID: attachmentID, Version: version}
+	}
+	v.TaskID = taskID
+	return v, nil
+}
+
+func getTaskAttachmentByTaskAndID(s *xorm.Session, taskID, attachmentID int64) (ta *TaskAttachment, err error) {
+	ta = &TaskAttachment{}
+	exists, err := s.
+		Where("id = ? AND task_id = ?", attachmentID, taskID).
+		Get(ta)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrTaskAttachmentDoesNotExist{TaskID: taskID, AttachmentID: attachmentID}
+	}
+	return ta, nil
+}
+
+// ReadOne returns a version of an attachment including its file
+func (v *TaskAttachmentVersion) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	version, err := getTaskAttachmentVersion(s, v.TaskID, v.AttachmentID, v.Version)
+	if err != nil {
+		return err
+	}
+	*v = *version
+
+	v.File = &files.File{ID: v.FileID}
+	err = v.File.LoadFileMetaByID()
+	if err != nil {
+		return err
+	}
+
+	users, err := getUsersOrLinkSharesFromIDs(s, []int64{v.File.CreatedByID})
+	if err != nil {
+		return err
+	}
+	v.CreatedBy = users[v.File.CreatedByID]
+	return nil
+}
+
+// ReadAll returns all previous versions of an attachment
+// @Summary Get all versions of an attachment
+// @Description Returns all previous versions of an attachment, newest first. The current file is not part of the list.
+// @tags task
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param attachmentID path int true "Attachment ID"
+// @Security JWTKeyAuth
+// @Success 200 {array} models.TaskAttachmentVersion "The versions."
+// @Failure 403 {object} models.Message "No access to this task."
+// @Failure 404 {object} models.Message "The attachment does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/attachments/{attachmentID}/versions [get]
+func (v *TaskAttachmentVersion) ReadAll(s *xorm.Session, _ web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	_, err = getTaskAttachmentByTaskAndID(s, v.TaskID, v.AttachmentID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	versions := []*TaskAttachmentVersion{}
+	limit, start := getLimitFromPageIndex(page, perPage)
+	query := s.
+		Where("attachment_id = ?", v.AttachmentID).
+		OrderBy("version desc")
+	if limit > 0 {
+		query = query.Limit(limit, start)
+	}
+	err = query.Find(&versions)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	if len(versions) == 0 {
+		return versions, 0, 0, nil
+	}
+
+	fileIDs := make([]int64, 0, len(versions))
+	for _, version := range versions {
+		fileIDs = append(fileIDs, version.FileID)
+	}
+	fs := make(map[int64]*files.File)
+	err = s.In("id", fileIDs).Find(&fs)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	userIDs := make([]int64, 0, len(fs))
+	for _, f := range fs {
+		userIDs = append(userIDs, f.CreatedByID)
+	}
+	users, err := getUsersOrLinkSharesFromIDs(s, userIDs)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	for _, version := range versions {
+		version.TaskID = v.TaskID
+		version.File = fs[version.FileID]
+		if version.File != nil {
+			version.CreatedBy = users[version.File.CreatedByID]
+		}
+	}
+
+	numberOfTotalItems, err = s.
+		Where("attachment_id = ?", v.AttachmentID).
+		Count(&TaskAttachmentVersion{})
+	return versions, len(versions), numberOfTotalItems, err
+}
+
+// deleteTaskAttachmentVersions removes all previous versions of an attachment including their files
+func deleteTaskAttachmentVersions(s *xorm.Session, attachmentID int64) error {
+	versions := []*TaskAttachmentVersion{}
+	err := s.Where("attachment_id = ?", attachmentID).Find(&versions)
+	if err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		err = (&files.File{ID: version.FileID}).Delete()
+		if err != nil && !files.IsErrFileDoesNotExist(err) {
+			return err
+		}
+	}
+
+	_, err = s.Where("attachment_id = ?", attachmentID).Delete(&TaskAttachmentVersion{})
+	return err
+}
+
+// TaskAttachmentVersionRestore makes an older version of an attachment the current one again
+type TaskAttachmentVersionRestore struct {
+	TaskID       int64 `json:"-" param:"task"`
+	AttachmentID int64 `json:"-" param:"attachment"`
+	Version      int64 `json:"-" param:"version"`
+
+	// The attachment after restoring the version.
+	Attachment *TaskAttachment `json:"attachment"`
+
+	web.CRUDable `json:"-"`
+	web.Rights   `json:"-"`
+}
+
+// Create restores a version of an attachment
+// @Summary Restore a version of an attachment
+// @Description Uploads the file of an older version as new version of the attachment. The current file stays available as version as well, no version is lost.
+// @tags task
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param attachmentID path int true "Attachment ID"
+// @Param version path int true "The version to restore"
+// @Security JWTKeyAuth
+// @Success 201 {object} models.TaskAttachmentVersionRestore "The attachment with the restored file."
+// @Failure 403 {object} models.Message "No access to this task."
+// @Failure 404 {object} models.Message "The attachment or version does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/attachments/{attachmentID}/versions/{version}/restore [post]
+func (r *TaskAttachmentVersionRestore) Create(s *xorm.Session, a web.Auth) (err error) {
+	version := &TaskAttachmentVersion{
+		TaskID:       r.TaskID,
+		AttachmentID: r.AttachmentID,
+		Version:      r.Version,
+	}
+	err = version.ReadOne(s, a)
+	if err != nil {
+		return err
+	}
+
+	err = version.File.LoadFileByID()
+	if err != nil {
+		return err
+	}
+	defer version.File.File.Close()
+
+	r.Attachment = &TaskAttachment{ID: r.AttachmentID, TaskID: r.TaskID}
+	return r.Attachment.NewVersion(s, version.File.File, version.File.Name, version.File.Size, a)
+}
@@ -0,0 +1,32 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if the user can see the versions of an attachment
+func (v *TaskAttachmentVersion) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	return (&Task{ID: v.TaskID}).CanRead(s, a)
+}
+
+// CanCreate checks if a user can restore a version, which is the case if they can edit the task
+func (r *TaskAttachmentVersionRestore) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	return (&TaskAttachment{ID: r.AttachmentID, TaskID: r.TaskID}).CanUpdate(s, a)
+}
@@ -0,0 +1,117 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskAttachment_NewVersion(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ta := &TaskAttachment{ID: 1, TaskID: 1}
+		err := ta.NewVersion(s, io.NopCloser(strings.NewReader("testfile2")), "test.txt", 9, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), ta.Version)
+		assert.NotEqual(t, int64(1), ta.FileID)
+
+		require.NoError(t, s.Commit())
+		db.AssertExists(t, "task_attachments", map[string]interface{}{
+			"id":      1,
+			"file_id": ta.FileID,
+			"version": 2,
+		}, false)
+		db.AssertExists(t, "task_attachment_versions", map[string]interface{}{
+			"attachment_id": 1,
+			"file_id":       1,
+			"version":       1,
+		}, false)
+	})
+	t.Run("attachment of another task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ta := &TaskAttachment{ID: 1, TaskID: 2}
+		err := ta.NewVersion(s, io.NopCloser(strings.NewReader("testfile2")), "test.txt", 9, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskAttachmentDoesNotExist(err))
+	})
+}
+
+func TestTaskAttachmentVersion(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("read all and restore", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		ta := &TaskAttachment{ID: 1, TaskID: 1}
+		err := ta.NewVersion(s, io.NopCloser(strings.NewReader("testfile2")), "test.txt", 9, u)
+		require.NoError(t, err)
+
+		v := &TaskAttachmentVersion{TaskID: 1, AttachmentID: 1}
+		result, resultCount, total, err := v.ReadAll(s, u, "", 0, 50)
+		require.NoError(t, err)
+		assert.Equal(t, 1, resultCount)
+		assert.Equal(t, int64(1), total)
+		versions := result.([]*TaskAttachmentVersion)
+		assert.Equal(t, int64(1), versions[0].Version)
+		assert.Equal(t, "test", versions[0].File.Name)
+		assert.Equal(t, int64(1), versions[0].CreatedBy.ID)
+
+		restore := &TaskAttachmentVersionRestore{TaskID: 1, AttachmentID: 1, Version: 1}
+		err = restore.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), restore.Attachment.Version)
+
+		err = restore.Attachment.File.LoadFileByID()
+		require.NoError(t, err)
+		content, err := io.ReadAll(restore.Attachment.File.File)
+		require.NoError(t, err)
+		assert.Equal(t, "testfile1", string(content))
+	})
+	t.Run("nonexisting version", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		files.InitTestFileFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		v := &TaskAttachmentVersion{TaskID: 1, AttachmentID: 1, Version: 5}
+		err := v.ReadOne(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskAttachmentVersionDoesNotExist(err))
+	})
+}
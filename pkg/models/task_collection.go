@@ -45,6 +45,18 @@ type TaskCollection struct {
 	// If set to true, the result will also include null values
 	FilterIncludeNulls bool `query:"filter_include_nulls" json:"filter_include_nulls"`
 
+	// A search query like `assignee:jan label:"bug" due:<2024-06-01 has:attachment`. It is combined with the filter.
+	Query string `query:"q" json:"q"`
+
+	// Where else the search text should be looked for, besides the title and description of tasks. Can contain
+	// `comments` and `attachments`.
+	SearchIn    []string `query:"search_in" json:"search_in"`
+	SearchInArr []string `query:"search_in[]" json:"-"`
+
+	// If set to true, tasks of archived projects are included when the tasks of all projects or of a saved
+	// filter are requested. They are left out by default.
+	IncludeArchived bool `query:"include_archived" json:"include_archived"`
+
 	// If set to `subtasks`, Vikunja will fetch only tasks which do not have subtasks and then in a
 	// second step, will fetch all of these subtasks. This may result in more tasks than the
 	// pagination limit being returned, but all subtasks will be present in the response.
@@ -77,6 +89,7 @@ func validateTaskField(fieldName string) error {
 		taskPropertyEndDate,
 		taskPropertyHexColor,
 		taskPropertyPercentDone,
+		taskPropertyVotes,
 		taskPropertyUID,
 		taskPropertyCreated,
 		taskPropertyUpdated,
@@ -151,8 +164,9 @@ func getRelevantProjectsFromCollection(s *xorm.Session, a web.Auth, tf *TaskColl
 		projects, _, _, err = getRawProjectsForUser(
 			s,
 			&projectOptions{
-				user: &user.User{ID: a.GetID()},
-				page: -1,
+				user:        &user.User{ID: a.GetID()},
+				page:        -1,
+				getArchived: tf.IncludeArchived,
 			},
 		)
 		return projects, err
@@ -185,11 +199,14 @@ func getRelevantProjectsFromCollection(s *xorm.Session, a web.Auth, tf *TaskColl
 // @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
 // @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
 // @Param s query string false "Search tasks by task text."
-// @Param sort_by query string false "The sorting parameter. You can pass this multiple times to get the tasks ordered by multiple different parametes, along with `order_by`. Possible values to sort by are `id`, `title`, `description`, `done`, `done_at`, `due_date`, `created_by_id`, `project_id`, `repeat_after`, `priority`, `start_date`, `end_date`, `hex_color`, `percent_done`, `uid`, `created`, `updated`. Default is `id`."
+// @Param sort_by query string false "The sorting parameter. You can pass this multiple times to get the tasks ordered by multiple different parametes, along with `order_by`. Possible values to sort by are `id`, `title`, `description`, `done`, `done_at`, `due_date`, `created_by_id`, `project_id`, `repeat_after`, `priority`, `start_date`, `end_date`, `hex_color`, `percent_done`, `votes`, `uid`, `created`, `updated`. Default is `id`."
 // @Param order_by query string false "The ordering parameter. Possible values to order by are `asc` or `desc`. Default is `asc`."
 // @Param filter query string false "The filter query to match tasks by. Check out https://vikunja.io/docs/filters for a full explanation of the feature."
 // @Param filter_timezone query string false "The time zone which should be used for date match (statements like "now" resolve to different actual times)"
 // @Param filter_include_nulls query string false "If set to true the result will include filtered fields whose value is set to `null`. Available values are `true` or `false`. Defaults to `false`."
+// @Param q query string false "A search query like `assignee:jan label:bug due:<2024-06-01 has:attachment`. Terms are combined with `AND` unless separated by `OR` and can be grouped with parentheses. Terms without a field search the title and description."
+// @Param search_in query string false "Where else to search besides the title and description. Can be `comments` or `attachments` and be passed multiple times. Every task then contains the places the search text was found in `search_matches`."
+// @Param include_archived query bool false "If set to true, tasks of archived projects are included when requesting the tasks of a saved filter. Defaults to `false`."
 // @Param expand query string false "If set to `subtasks`, Vikunja will fetch only tasks which do not have subtasks and then in a second step, will fetch all of these subtasks. This may result in more tasks than the pagination limit being returned, but all subtasks will be present in the response. You can only set this to `subtasks`."
 // @Security JWTKeyAuth
 // @Success 200 {array} models.Task "The tasks"
@@ -229,9 +246,18 @@ func (tf *TaskCollection) ReadAll(s *xorm.Session, a web.Auth, search string, pa
 			sf.Filters.FilterTimezone = u.Timezone
 		}
 
+		if tf.Query != "" {
+			if sf.Filters.Query != "" {
+				sf.Filters.Query = "(" + sf.Filters.Query + ") (" + tf.Query + ")"
+			} else {
+				sf.Filters.Query = tf.Query
+			}
+		}
+
 		tc := sf.getTaskCollection()
 		tc.ProjectViewID = tf.ProjectViewID
 		tc.ProjectID = tf.ProjectID
+		tc.IncludeArchived = tc.IncludeArchived || tf.IncludeArchived
 		tc.isSavedFilter = true
 
 		return tc.ReadAll(s, a, search, page, perPage)
@@ -244,6 +270,14 @@ func (tf *TaskCollection) ReadAll(s *xorm.Session, a web.Auth, search string, pa
 			return nil, 0, 0, err
 		}
 
+		has, err := view.hasRequiredRight(s, a)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if !has {
+			return nil, 0, 0, ErrGenericForbidden{}
+		}
+
 		if view.Filter != "" {
 			if tf.Filter != "" {
 				tf.Filter = "(" + tf.Filter + ") && (" + view.Filter + ")"
@@ -258,6 +292,17 @@ func (tf *TaskCollection) ReadAll(s *xorm.Session, a web.Auth, search string, pa
 		return nil, 0, 0, err
 	}
 
+	opts.queryFilters, err = parseSearchQuery(s, a, tf.Query, tf.FilterTimezone)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	opts.searchIn = append(tf.SearchIn, tf.SearchInArr...)
+	err = validateTaskSearchIn(opts.searchIn)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
 	opts.search = search
 	opts.page = page
 	opts.perPage = perPage
@@ -51,6 +51,38 @@ const (
 const safariDateAndTime = "2006-01-02 15:04"
 const safariDate = "2006-01-02"
 
+// relativeDateTokens holds the named dates which can be used in filters, together with the date math expression
+// they stand for. They can be followed by an offset, like `start_of_week+7d`.
+// The order matters: tokens which are a prefix of another one must come after it.
+var relativeDateTokens = []struct {
+	token      string
+	expression string
+}{
+	{"start_of_day", "now/d"},
+	{"end_of_day", "now/d+1d-1s"},
+	{"start_of_week", "now/w"},
+	{"end_of_week", "now/w+1w-1s"},
+	{"start_of_month", "now/M"},
+	{"end_of_month", "now/M+1M-1s"},
+	{"start_of_year", "now/y"},
+	{"end_of_year", "now/y+1y-1s"},
+}
+
+// Matches unquoted relative date values like `now+7d` or `start_of_week` after a comparator so that they can be
+// quoted before parsing - the filter parser would otherwise choke on the + and - signs.
+var relativeDateValueRegex = regexp.MustCompile(`(^|[^?<>!=])([<>!]?=|[<>])\s*((?:now|start_of_[a-z]+|end_of_[a-z]+)[^\s&|')]*)`)
+
+// resolveRelativeDate replaces a named relative date at the beginning of the value with the date math expression
+// it stands for. Values which don't start with a named date are returned unchanged.
+func resolveRelativeDate(value string) string {
+	for _, t := range relativeDateTokens {
+		if strings.HasPrefix(value, t.token) {
+			return t.expression + strings.TrimPrefix(value, t.token)
+		}
+	}
+	return value
+}
+
 type taskFilter struct {
 	field      string
 	value      interface{} // Needs to be an interface to be able to hold the field's native value
@@ -152,6 +184,7 @@ func getTaskFiltersFromFilterString(filter string, filterTimezone string) (filte
 	}
 
 	filter = strings.ReplaceAll(filter, " in ", " ?= ")
+	filter = relativeDateValueRegex.ReplaceAllString(filter, "$1$2 '$3'")
 
 	// Replaces all occurrences with in with a string so that it passes the filter
 	pattern := `(\?=\s+([^&|']+))|(([<>]?=|[<>])[^&|')]+\/[^&|')]+([&|')]+))`
@@ -267,7 +300,7 @@ func getValueForField(field reflect.StructField, rawValue string, loc *time.Loca
 	case reflect.Struct:
 		if field.Type == schemas.TimeType {
 			var t datemath.Expression
-			t, err = datemath.Parse(rawValue)
+			t, err = datemath.Parse(resolveRelativeDate(rawValue))
 			if err == nil {
 				value = t.Time(datemath.WithLocation(config.GetTimeZone())).In(loc)
 			} else {
@@ -301,6 +334,12 @@ func getNativeValueForTaskField(fieldName string, comparator taskFilterComparato
 
 	realFieldName := strings.ReplaceAll(strcase.ToCamel(fieldName), "Id", "ID")
 
+	// Filters on the project hierarchy don't have a task field but compare project ids as well
+	switch fieldName {
+	case "parent_project", "parent_project_id", "project_tree":
+		realFieldName = "ProjectID"
+	}
+
 	if realFieldName == "Assignees" {
 		vals := strings.Split(value, ",")
 		valueSlice := append([]string{}, vals...)
@@ -0,0 +1,58 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTaskFiltersFromFilterString_RelativeDates(t *testing.T) {
+	t.Run("now with offset", func(t *testing.T) {
+		filters, err := getTaskFiltersFromFilterString("due_date < now+7d", "")
+		require.NoError(t, err)
+		require.Len(t, filters, 1)
+		assert.Equal(t, "due_date", filters[0].field)
+		assert.Equal(t, taskFilterComparatorLess, filters[0].comparator)
+		value, is := filters[0].value.(time.Time)
+		require.True(t, is)
+		assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), value, time.Minute)
+	})
+	t.Run("named date", func(t *testing.T) {
+		filters, err := getTaskFiltersFromFilterString("due_date >= start_of_day && done = false", "")
+		require.NoError(t, err)
+		require.Len(t, filters, 2)
+		value, is := filters[0].value.(time.Time)
+		require.True(t, is)
+		assert.Equal(t, 0, value.Hour())
+		assert.Equal(t, 0, value.Minute())
+		assert.Equal(t, "done", filters[1].field)
+	})
+	t.Run("named date with offset", func(t *testing.T) {
+		assert.Equal(t, "now/w+7d", resolveRelativeDate("start_of_week+7d"))
+		assert.Equal(t, "now/M+1M-1s", resolveRelativeDate("end_of_month"))
+		assert.Equal(t, "now-30d", resolveRelativeDate("now-30d"))
+	})
+	t.Run("inside parentheses", func(t *testing.T) {
+		filters, err := getTaskFiltersFromFilterString("done = false || (done_at > now-30d)", "")
+		require.NoError(t, err)
+		require.Len(t, filters, 2)
+	})
+}
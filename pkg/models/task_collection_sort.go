@@ -41,6 +41,7 @@ const (
 	taskPropertyEndDate       string = "end_date"
 	taskPropertyHexColor      string = "hex_color"
 	taskPropertyPercentDone   string = "percent_done"
+	taskPropertyVotes         string = "votes"
 	taskPropertyUID           string = "uid"
 	taskPropertyCreated       string = "created"
 	taskPropertyUpdated       string = "updated"
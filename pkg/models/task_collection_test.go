@@ -115,6 +115,9 @@ func TestTaskCollection_ReadAll(t *testing.T) {
 				},
 			},
 		},
+		SubtaskRollup: &TaskSubtaskRollup{
+			Total: 1,
+		},
 		Attachments: []*TaskAttachment{
 			{
 				ID:          1,
@@ -472,7 +475,7 @@ func TestTaskCollection_ReadAll(t *testing.T) {
 			{
 				ID:             2,
 				TaskID:         27,
-				Reminder:       time.Unix(1543626824, 0).In(loc),
+				Reminder:       time.Unix(1543613124, 0).In(loc),
 				Created:        time.Unix(1543626724, 0).In(loc),
 				RelativePeriod: -3600,
 				RelativeTo:     "start_date",
@@ -1196,7 +1199,41 @@ func TestTaskCollection_ReadAll(t *testing.T) {
 			want:    []*Task{},
 			wantErr: false,
 		},
-		// TODO filter parent project?
+		{
+			name: "filter parent project",
+			fields: fields{
+				Filter: "parent_project = 22",
+			},
+			args: defaultArgs,
+			want: []*Task{
+				task35,
+			},
+			wantErr: false,
+		},
+		{
+			name: "filter project tree",
+			fields: fields{
+				// Tasks in project 22 itself are not returned because it is archived
+				Filter: "project_tree = 22",
+			},
+			args: defaultArgs,
+			want: []*Task{
+				task35,
+			},
+			wantErr: false,
+		},
+		{
+			name: "filter project tree in",
+			fields: fields{
+				Filter: "project_tree in 22,6",
+			},
+			args: defaultArgs,
+			want: []*Task{
+				task15,
+				task35,
+			},
+			wantErr: false,
+		},
 		{
 			name: "filter by index",
 			fields: fields{
@@ -0,0 +1,129 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strconv"
+	"strings"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// TaskCommentConversion holds everything needed to convert a task comment into a new task
+type TaskCommentConversion struct {
+	// The task the comment belongs to
+	TaskID int64 `json:"-" param:"task"`
+	// The comment to convert
+	CommentID int64 `json:"-" param:"commentid"`
+
+	// The task created from the comment
+	Task *Task `json:"task,omitempty"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// The maximum length of a task title created from a comment. Everything after it is only kept in the description.
+const convertedCommentTitleLength = 250
+
+// CanCreate checks if a user can convert a comment into a task
+func (tcc *TaskCommentConversion) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	t := &Task{ID: tcc.TaskID}
+	return t.CanWrite(s, a)
+}
+
+// Create converts a comment into a task
+// @Summary Convert a comment into a task
+// @Description Creates a new task in the same project from the text of a comment, links it to the task of the comment with a `related` relation and marks the comment as converted. A comment can only be converted once.
+// @tags task
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param commentID path int true "Comment ID"
+// @Success 201 {object} models.TaskCommentConversion "The task created from the comment."
+// @Failure 403 {object} web.HTTPError "The user does not have write access to the task."
+// @Failure 404 {object} web.HTTPError "The comment does not exist."
+// @Failure 409 {object} web.HTTPError "The comment was already converted."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/comments/{commentID}/convert [post]
+func (tcc *TaskCommentConversion) Create(s *xorm.Session, a web.Auth) (err error) {
+	comment := &TaskComment{ID: tcc.CommentID, TaskID: tcc.TaskID}
+	err = getTaskCommentSimple(s, comment)
+	if err != nil {
+		return err
+	}
+	if comment.TaskID != tcc.TaskID {
+		return ErrTaskCommentDoesNotExist{ID: tcc.CommentID, TaskID: tcc.TaskID}
+	}
+	if comment.ConvertedTaskID != 0 {
+		return ErrTaskCommentAlreadyConverted{CommentID: comment.ID, ConvertedTaskID: comment.ConvertedTaskID}
+	}
+
+	originalTask, err := GetTaskByIDSimple(s, tcc.TaskID)
+	if err != nil {
+		return err
+	}
+
+	tcc.Task = &Task{
+		Title:       getTitleFromComment(comment),
+		Description: comment.Comment,
+		ProjectID:   originalTask.ProjectID,
+	}
+	err = createTask(s, tcc.Task, a, false, true, true)
+	if err != nil {
+		return err
+	}
+
+	relation := &TaskRelation{
+		TaskID:       originalTask.ID,
+		OtherTaskID:  tcc.Task.ID,
+		RelationKind: RelationKindRelated,
+	}
+	err = relation.Create(s, a)
+	if err != nil {
+		return err
+	}
+
+	comment.ConvertedTaskID = tcc.Task.ID
+	_, err = s.
+		ID(comment.ID).
+		Cols("converted_task_id").
+		NoAutoTime().
+		Update(comment)
+	return err
+}
+
+// getTitleFromComment uses the first line of a comment's text as title for the task created from it.
+func getTitleFromComment(comment *TaskComment) string {
+	text := htmlToMarkdown(comment.Comment)
+	title, _, _ := strings.Cut(text, "\n")
+	title = strings.ReplaceAll(title, "**", "")
+	title = strings.TrimSpace(strings.Trim(title, "#_ "))
+
+	runes := []rune(title)
+	if len(runes) > convertedCommentTitleLength {
+		title = strings.TrimSpace(string(runes[:convertedCommentTitleLength-1])) + "…"
+	}
+
+	// Comments without any text, for example only an image, still need a title
+	if title == "" {
+		return "Comment #" + strconv.FormatInt(comment.ID, 10)
+	}
+
+	return title
+}
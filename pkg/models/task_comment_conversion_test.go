@@ -0,0 +1,95 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskCommentConversion_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tcc := &TaskCommentConversion{TaskID: 1, CommentID: 1}
+		can, err := tcc.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = tcc.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, "Lorem Ipsum Dolor Sit Amet", tcc.Task.Title)
+		assert.Equal(t, int64(1), tcc.Task.ProjectID)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":          tcc.Task.ID,
+			"title":       "Lorem Ipsum Dolor Sit Amet",
+			"description": "Lorem Ipsum Dolor Sit Amet",
+			"project_id":  1,
+		}, false)
+		db.AssertExists(t, "task_relations", map[string]interface{}{
+			"task_id":       1,
+			"other_task_id": tcc.Task.ID,
+			"relation_kind": RelationKindRelated,
+		}, false)
+		db.AssertExists(t, "task_comments", map[string]interface{}{
+			"id":                1,
+			"converted_task_id": tcc.Task.ID,
+		}, false)
+	})
+	t.Run("already converted", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&TaskCommentConversion{TaskID: 1, CommentID: 1}).Create(s, u)
+		require.NoError(t, err)
+		err = (&TaskCommentConversion{TaskID: 1, CommentID: 1}).Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskCommentAlreadyConverted(err))
+	})
+	t.Run("comment of another task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&TaskCommentConversion{TaskID: 1, CommentID: 2}).Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskCommentDoesNotExist(err))
+	})
+}
+
+func TestGetTitleFromComment(t *testing.T) {
+	t.Run("first line", func(t *testing.T) {
+		title := getTitleFromComment(&TaskComment{ID: 1, Comment: "<p><strong>Fix</strong> the login</p><p>It breaks on mobile.</p>"})
+		assert.Equal(t, "Fix the login", title)
+	})
+	t.Run("without text", func(t *testing.T) {
+		title := getTitleFromComment(&TaskComment{ID: 3, Comment: `<p><img src="foo.png"></p>`})
+		assert.Equal(t, "Comment #3", title)
+	})
+}
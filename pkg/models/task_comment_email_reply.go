@@ -0,0 +1,204 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/microcosm-cc/bluemonday"
+	"xorm.io/xorm"
+)
+
+// Reply addresses look like reply+<task id>-<parent comment id>-<user id>-<signature>@<intake domain>.
+// They don't need to be stored anywhere because the signature makes sure nobody can comment in someone else's name.
+// The signature is keyed on the intake secret, so changing it invalidates all reply addresses sent out before.
+const taskCommentReplyPrefix = "reply+"
+
+var (
+	// Matches the line most mail clients put above the quoted message, like "On Mon, 1 Jan 2024, Jane <jane@example.com> wrote:"
+	replyQuoteHeaderRegex = regexp.MustCompile(`(?i)^(on\s.+\swrote:|-+\s*original message\s*-+)$`)
+	// Html-only replies can't be stripped line by line, everything after the start of the quote is dropped instead.
+	replyHTMLQuoteRegex = regexp.MustCompile(`(?is)(<div[^>]*class="[^"]*gmail_quote[^"]*"|<blockquote).*$`)
+)
+
+type taskCommentReplyToken struct {
+	TaskID          int64
+	ParentCommentID int64
+	UserID          int64
+}
+
+func taskCommentReplySignature(taskID, parentCommentID, userID int64) string {
+	mac := hmac.New(sha256.New, []byte(config.EmailIntakeSecret.GetString()))
+	_, _ = mac.Write([]byte(strconv.FormatInt(taskID, 10) + "-" + strconv.FormatInt(parentCommentID, 10) + "-" + strconv.FormatInt(userID, 10)))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// getTaskCommentReplyAddress returns the address a user can answer a notification mail to in order to comment on the task.
+// It returns an empty string if replying via mail is not possible in this instance.
+func getTaskCommentReplyAddress(taskID, parentCommentID, userID int64) string {
+	if !config.EmailIntakeEnabled.GetBool() ||
+		!config.ServiceEnableTaskComments.GetBool() ||
+		config.EmailIntakeDomain.GetString() == "" ||
+		config.EmailIntakeSecret.GetString() == "" {
+		return ""
+	}
+
+	return taskCommentReplyPrefix +
+		strconv.FormatInt(taskID, 10) + "-" +
+		strconv.FormatInt(parentCommentID, 10) + "-" +
+		strconv.FormatInt(userID, 10) + "-" +
+		taskCommentReplySignature(taskID, parentCommentID, userID) +
+		"@" + config.EmailIntakeDomain.GetString()
+}
+
+func parseTaskCommentReplyToken(localPart string) (token *taskCommentReplyToken, ok bool) {
+	if !strings.HasPrefix(localPart, taskCommentReplyPrefix) {
+		return nil, false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(localPart, taskCommentReplyPrefix), "-")
+	if len(parts) != 4 {
+		return nil, false
+	}
+
+	ids := make([]int64, 3)
+	for i := range ids {
+		id, err := strconv.ParseInt(parts[i], 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		ids[i] = id
+	}
+
+	signature := taskCommentReplySignature(ids[0], ids[1], ids[2])
+	if !hmac.Equal([]byte(parts[3]), []byte(signature)) {
+		log.Debugf("Ignoring mail to reply address %s with invalid signature", localPart)
+		return nil, false
+	}
+
+	return &taskCommentReplyToken{
+		TaskID:          ids[0],
+		ParentCommentID: ids[1],
+		UserID:          ids[2],
+	}, true
+}
+
+// stripQuotedReply removes the quoted original message and the signature from the plain text body of a reply.
+func stripQuotedReply(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	kept := make([]string, 0, len(lines))
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if line == "-- " || line == "--" || replyQuoteHeaderRegex.MatchString(trimmed) {
+			break
+		}
+		// Some clients wrap the quote header to the next line
+		if i+1 < len(lines) &&
+			strings.HasPrefix(strings.ToLower(trimmed), "on ") &&
+			replyQuoteHeaderRegex.MatchString(trimmed+" "+strings.TrimSpace(lines[i+1])) {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+func (m *intakeMail) replyComment() string {
+	if strings.TrimSpace(m.Text) != "" {
+		text := stripQuotedReply(m.Text)
+		if text == "" {
+			return ""
+		}
+		return intakeTextToHTML(text)
+	}
+
+	return strings.TrimSpace(bluemonday.UGCPolicy().Sanitize(replyHTMLQuoteRegex.ReplaceAllString(m.HTML, "")))
+}
+
+// receiveTaskCommentReply creates a comment from a reply to a notification mail. The comment is attributed to the
+// user the notification was sent to, but only if the reply was sent from that user's email address.
+// A nil comment without an error means the reply was ignored.
+func receiveTaskCommentReply(s *xorm.Session, m *intakeMail, token *taskCommentReplyToken) (comment *TaskComment, err error) {
+	u, err := user.GetUserByID(s, token.UserID)
+	if err != nil {
+		if user.IsErrUserDoesNotExist(err) {
+			log.Debugf("Ignoring reply to task %d from deleted user %d", token.TaskID, token.UserID)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if !strings.EqualFold(m.From, u.Email) {
+		log.Debugf("Ignoring reply to task %d because the sender %s is not the email address of user %d", token.TaskID, m.From, u.ID)
+		return nil, nil
+	}
+
+	comment = &TaskComment{
+		TaskID:  token.TaskID,
+		Comment: m.replyComment(),
+	}
+	if comment.Comment == "" {
+		log.Debugf("Ignoring empty reply to task %d from user %d", token.TaskID, u.ID)
+		return nil, nil
+	}
+
+	if token.ParentCommentID != 0 {
+		parent := &TaskComment{ID: token.ParentCommentID}
+		err = getTaskCommentSimple(s, parent)
+		if err != nil && !IsErrTaskCommentDoesNotExist(err) {
+			return nil, err
+		}
+		// When the comment was deleted in the meantime, the reply becomes a new top level comment.
+		if err == nil && parent.TaskID == token.TaskID {
+			comment.ParentCommentID = parent.ID
+		}
+	}
+
+	can, err := comment.CanCreate(s, u)
+	if err != nil {
+		if IsErrTaskDoesNotExist(err) {
+			log.Debugf("Ignoring reply to deleted task %d", token.TaskID)
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !can {
+		log.Debugf("Ignoring reply to task %d because user %d is not allowed to comment on it", token.TaskID, u.ID)
+		return nil, nil
+	}
+
+	err = comment.Create(s, u)
+	if err != nil {
+		return nil, err
+	}
+
+	comment.Attachments, err = m.saveAttachments(s, comment.TaskID, comment.ID, u)
+	return comment, err
+}
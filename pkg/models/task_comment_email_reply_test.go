@@ -0,0 +1,164 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testReplyMail = "From: %s\r\n" +
+	"To: %s\r\n" +
+	"Subject: Re: task #1\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"Sounds good, I'll take care of it.\r\n" +
+	"\r\n" +
+	"On Mon, 1 Jan 2024 at 10:00, Vikunja <vikunja@example.com>\r\n" +
+	"wrote:\r\n" +
+	"> Can someone look into this?\r\n"
+
+func TestTaskCommentReplyAddress(t *testing.T) {
+	config.EmailIntakeEnabled.Set(true)
+	config.EmailIntakeDomain.Set("intake.example.com")
+	config.EmailIntakeSecret.Set("secret")
+	defer config.EmailIntakeEnabled.Set(false)
+	defer config.EmailIntakeDomain.Set("")
+	defer config.EmailIntakeSecret.Set("")
+
+	t.Run("round trip", func(t *testing.T) {
+		address := getTaskCommentReplyAddress(1, 2, 3)
+		require.True(t, strings.HasSuffix(address, "@intake.example.com"))
+
+		token, ok := parseTaskCommentReplyToken(strings.TrimSuffix(address, "@intake.example.com"))
+		require.True(t, ok)
+		assert.Equal(t, &taskCommentReplyToken{TaskID: 1, ParentCommentID: 2, UserID: 3}, token)
+	})
+	t.Run("tampered", func(t *testing.T) {
+		address := getTaskCommentReplyAddress(1, 2, 3)
+		localPart := strings.Replace(strings.TrimSuffix(address, "@intake.example.com"), "-3-", "-4-", 1)
+
+		_, ok := parseTaskCommentReplyToken(localPart)
+		assert.False(t, ok)
+	})
+	t.Run("intake secret changed", func(t *testing.T) {
+		address := getTaskCommentReplyAddress(1, 2, 3)
+		config.EmailIntakeSecret.Set("other secret")
+		defer config.EmailIntakeSecret.Set("secret")
+
+		_, ok := parseTaskCommentReplyToken(strings.TrimSuffix(address, "@intake.example.com"))
+		assert.False(t, ok)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		config.EmailIntakeEnabled.Set(false)
+		defer config.EmailIntakeEnabled.Set(true)
+
+		assert.Empty(t, getTaskCommentReplyAddress(1, 2, 3))
+	})
+}
+
+func TestStripQuotedReply(t *testing.T) {
+	tests := map[string]string{
+		"Thanks!\n\nOn Mon, 1 Jan 2024, Jane <jane@example.com> wrote:\n> Hello": "Thanks!",
+		"Thanks!\n> Hello\nSee above":                                            "Thanks!\nSee above",
+		"Thanks!\n-----Original Message-----\nFrom: Jane":                        "Thanks!",
+		"Thanks!\n-- \nJane Doe\nACME Inc.":                                      "Thanks!",
+		"On Monday I will do it.\nBye":                                           "On Monday I will do it.\nBye",
+	}
+	for text, expected := range tests {
+		assert.Equal(t, expected, stripQuotedReply(text))
+	}
+}
+
+func TestReceiveIntakeEmail_Reply(t *testing.T) {
+	config.EmailIntakeEnabled.Set(true)
+	config.EmailIntakeDomain.Set("intake.example.com")
+	config.EmailIntakeSecret.Set("secret")
+	defer config.EmailIntakeEnabled.Set(false)
+	defer config.EmailIntakeDomain.Set("")
+	defer config.EmailIntakeSecret.Set("")
+
+	t.Run("creates a reply to the comment", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		mail := strings.Replace(testReplyMail, "%s", "User 1 <user1@example.com>", 1)
+		mail = strings.Replace(mail, "%s", getTaskCommentReplyAddress(1, 1, 1), 1)
+		_, err := ReceiveIntakeEmail(s, strings.NewReader(mail))
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_comments", map[string]interface{}{
+			"task_id":           1,
+			"parent_comment_id": 1,
+			"author_id":         1,
+			"comment":           "<p>Sounds good, I&#39;ll take care of it.</p>",
+		}, false)
+	})
+	t.Run("html reply is sanitized", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		mail := "From: user1@example.com\r\n" +
+			"To: " + getTaskCommentReplyAddress(1, 0, 1) + "\r\n" +
+			"Subject: Re: task #1\r\n" +
+			"Content-Type: text/html; charset=utf-8\r\n" +
+			"\r\n" +
+			`<p>Done<script>alert(1)</script><img src="x" onerror="alert(1)"></p><blockquote>Old message</blockquote>`
+		_, err := ReceiveIntakeEmail(s, strings.NewReader(mail))
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_comments", map[string]interface{}{
+			"task_id":   1,
+			"author_id": 1,
+			"comment":   `<p>Done<img src="x"></p>`,
+		}, false)
+	})
+	t.Run("sender does not match the user", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		mail := strings.Replace(testReplyMail, "%s", "user2@example.com", 1)
+		mail = strings.Replace(mail, "%s", getTaskCommentReplyAddress(1, 0, 1), 1)
+		_, err := ReceiveIntakeEmail(s, strings.NewReader(mail))
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidIntakeEmail(err))
+	})
+	t.Run("no access to the task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		mail := strings.Replace(testReplyMail, "%s", "user1@example.com", 1)
+		mail = strings.Replace(mail, "%s", getTaskCommentReplyAddress(14, 0, 1), 1)
+		_, err := ReceiveIntakeEmail(s, strings.NewReader(mail))
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidIntakeEmail(err))
+	})
+}
@@ -0,0 +1,126 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// TaskCommentPin pins a comment to the top of the comments of a task
+type TaskCommentPin struct {
+	// The task the comment belongs to
+	TaskID int64 `json:"-" param:"task"`
+	// The comment to pin
+	CommentID int64 `json:"-" param:"commentid"`
+
+	// The comment after it was pinned or unpinned
+	Comment *TaskComment `json:"comment,omitempty"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// CanCreate checks if a user can pin a comment
+func (tcp *TaskCommentPin) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+	t := &Task{ID: tcp.TaskID}
+	return t.CanWrite(s, a)
+}
+
+// CanDelete checks if a user can unpin a comment
+func (tcp *TaskCommentPin) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return tcp.CanCreate(s, a)
+}
+
+// Create pins a comment
+// @Summary Pin a comment
+// @Description Pins a comment to the top of the comments of a task, for example to keep a summary of a decision visible. More than one comment can be pinned at a time, they are ordered by the time they were pinned. The user needs write access to the task.
+// @tags task
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param commentID path int true "Comment ID"
+// @Success 201 {object} models.TaskCommentPin "The pinned comment."
+// @Failure 403 {object} web.HTTPError "The user does not have write access to the task."
+// @Failure 404 {object} web.HTTPError "The comment does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/comments/{commentID}/pin [put]
+func (tcp *TaskCommentPin) Create(s *xorm.Session, a web.Auth) (err error) {
+	return tcp.setPinned(s, a, true)
+}
+
+// Delete unpins a comment
+// @Summary Unpin a comment
+// @Description Removes a comment from the pinned comments of a task. The user needs write access to the task.
+// @tags task
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param commentID path int true "Comment ID"
+// @Success 200 {object} models.TaskCommentPin "The unpinned comment."
+// @Failure 403 {object} web.HTTPError "The user does not have write access to the task."
+// @Failure 404 {object} web.HTTPError "The comment does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/comments/{commentID}/pin [delete]
+func (tcp *TaskCommentPin) Delete(s *xorm.Session, a web.Auth) (err error) {
+	return tcp.setPinned(s, a, false)
+}
+
+func (tcp *TaskCommentPin) setPinned(s *xorm.Session, a web.Auth, pinned bool) (err error) {
+	comment := &TaskComment{ID: tcp.CommentID}
+	err = getTaskCommentSimple(s, comment)
+	if err != nil {
+		return err
+	}
+	if comment.TaskID != tcp.TaskID {
+		return ErrTaskCommentDoesNotExist{ID: tcp.CommentID, TaskID: tcp.TaskID}
+	}
+
+	comment.PinnedAt = time.Time{}
+	comment.PinnedByID = 0
+	if pinned {
+		comment.PinnedAt = time.Now()
+		comment.PinnedByID = a.GetID()
+	}
+	comment.Pinned = pinned
+
+	_, err = s.
+		ID(comment.ID).
+		Cols("pinned", "pinned_at", "pinned_by_id").
+		NoAutoTime().
+		Update(comment)
+	if err != nil {
+		return err
+	}
+
+	if pinned {
+		comment.PinnedBy, err = user.GetUserByID(s, comment.PinnedByID)
+		if err != nil {
+			return err
+		}
+	}
+
+	tcp.Comment = comment
+	return nil
+}
@@ -0,0 +1,86 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskCommentPin(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("pinned first", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		newer := &TaskComment{Comment: "decision", TaskID: 1}
+		err := newer.Create(s, u)
+		require.NoError(t, err)
+
+		pin := &TaskCommentPin{TaskID: 1, CommentID: newer.ID}
+		can, err := pin.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = pin.Create(s, u)
+		require.NoError(t, err)
+		assert.True(t, pin.Comment.Pinned)
+		assert.Equal(t, int64(1), pin.Comment.PinnedBy.ID)
+
+		result, _, _, err := (&TaskComment{TaskID: 1}).ReadAll(s, u, "", 0, -1)
+		require.NoError(t, err)
+		comments := result.([]*TaskComment)
+		require.Len(t, comments, 2)
+		assert.Equal(t, newer.ID, comments[0].ID)
+		assert.True(t, comments[0].Pinned)
+		assert.Equal(t, int64(1), comments[1].ID)
+
+		err = (&TaskCommentPin{TaskID: 1, CommentID: newer.ID}).Delete(s, u)
+		require.NoError(t, err)
+
+		result, _, _, err = (&TaskComment{TaskID: 1}).ReadAll(s, u, "", 0, -1)
+		require.NoError(t, err)
+		comments = result.([]*TaskComment)
+		assert.Equal(t, int64(1), comments[0].ID)
+		assert.False(t, comments[1].Pinned)
+	})
+	t.Run("comment of another task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&TaskCommentPin{TaskID: 2, CommentID: 1}).Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskCommentDoesNotExist(err))
+	})
+	t.Run("read only access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// Task 15 is in a project shared read only with user 1
+		can, err := (&TaskCommentPin{TaskID: 15, CommentID: 1}).CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
@@ -0,0 +1,170 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// TaskCommentRevision holds a previous version of an edited comment
+type TaskCommentRevision struct {
+	ID        int64 `xorm:"bigint autoincr not null unique pk" json:"id"`
+	TaskID    int64 `xorm:"bigint not null INDEX" json:"-" param:"task"`
+	CommentID int64 `xorm:"bigint not null INDEX" json:"comment_id" param:"commentid"`
+	// The text of the comment before it was edited.
+	Comment string `xorm:"text not null" json:"comment"`
+
+	EditorID int64 `xorm:"bigint not null" json:"-"`
+	// The user who edited the comment away from this revision.
+	Editor *user.User `xorm:"-" json:"editor"`
+
+	// A timestamp when the comment was edited. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for task comment revisions
+func (*TaskCommentRevision) TableName() string {
+	return "task_comment_revisions"
+}
+
+// saveTaskCommentRevision stores the current text of a comment as a revision before it gets replaced.
+func saveTaskCommentRevision(s *xorm.Session, comment *TaskComment, a web.Auth) error {
+	editor, err := GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Insert(&TaskCommentRevision{
+		TaskID:    comment.TaskID,
+		CommentID: comment.ID,
+		Comment:   comment.Comment,
+		EditorID:  editor.ID,
+	})
+	return err
+}
+
+// addEditedToComments marks all comments which have at least one revision as edited.
+func addEditedToComments(s *xorm.Session, comments []*TaskComment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	commentIDs := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		commentIDs = append(commentIDs, comment.ID)
+	}
+
+	edited := []int64{}
+	err := s.
+		Table("task_comment_revisions").
+		Distinct("comment_id").
+		In("comment_id", commentIDs).
+		Find(&edited)
+	if err != nil {
+		return err
+	}
+
+	editedComments := make(map[int64]bool, len(edited))
+	for _, id := range edited {
+		editedComments[id] = true
+	}
+	for _, comment := range comments {
+		comment.Edited = editedComments[comment.ID]
+	}
+
+	return nil
+}
+
+func deleteTaskCommentRevisions(s *xorm.Session, cond builder.Cond) (err error) {
+	_, err = s.Where(cond).Delete(&TaskCommentRevision{})
+	return
+}
+
+// ReadAll returns all previous versions of a comment
+// @Summary Get all revisions of a comment
+// @Description Returns all previous versions of an edited comment, newest first, with the user who edited it away from each version.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param commentID path int true "Comment ID"
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Success 200 {array} models.TaskCommentRevision "The revisions of the comment"
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 404 {object} web.HTTPError "The comment does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/comments/{commentID}/revisions [get]
+func (r *TaskCommentRevision) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	canRead, _, err := r.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !canRead {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	comment := &TaskComment{ID: r.CommentID}
+	err = getTaskCommentSimple(s, comment)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if comment.TaskID != r.TaskID {
+		return nil, 0, 0, ErrTaskCommentDoesNotExist{ID: r.CommentID, TaskID: r.TaskID}
+	}
+
+	limit, start := getLimitFromPageIndex(page, perPage)
+	query := s.
+		Where("comment_id = ?", r.CommentID).
+		OrderBy("id desc")
+	if limit > 0 {
+		query = query.Limit(limit, start)
+	}
+
+	revisions := []*TaskCommentRevision{}
+	err = query.Find(&revisions)
+	if err != nil {
+		return
+	}
+
+	editorIDs := make([]int64, 0, len(revisions))
+	for _, revision := range revisions {
+		editorIDs = append(editorIDs, revision.EditorID)
+	}
+	editors, err := getUsersOrLinkSharesFromIDs(s, editorIDs)
+	if err != nil {
+		return
+	}
+	for _, revision := range revisions {
+		revision.Editor = editors[revision.EditorID]
+	}
+
+	numberOfTotalItems, err = s.
+		Where("comment_id = ?", r.CommentID).
+		Count(&TaskCommentRevision{})
+	return revisions, len(revisions), numberOfTotalItems, err
+}
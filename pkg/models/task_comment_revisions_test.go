@@ -0,0 +1,85 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskCommentRevision_ReadAll(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		for _, text := range []string{"first edit", "second edit"} {
+			err := (&TaskComment{ID: 1, TaskID: 1, Comment: text}).Update(s, u)
+			require.NoError(t, err)
+		}
+
+		r := &TaskCommentRevision{TaskID: 1, CommentID: 1}
+		result, count, total, err := r.ReadAll(s, u, "", 0, -1)
+		require.NoError(t, err)
+		revisions := result.([]*TaskCommentRevision)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, "first edit", revisions[0].Comment)
+		assert.Equal(t, "Lorem Ipsum Dolor Sit Amet", revisions[1].Comment)
+		assert.Equal(t, int64(1), revisions[0].Editor.ID)
+
+		comments, _, _, err := (&TaskComment{TaskID: 1}).ReadAll(s, u, "", 0, -1)
+		require.NoError(t, err)
+		assert.True(t, comments.([]*TaskComment)[0].Edited)
+	})
+	t.Run("unchanged text", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&TaskComment{ID: 1, TaskID: 1, Comment: "Lorem Ipsum Dolor Sit Amet"}).Update(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		db.AssertMissing(t, "task_comment_revisions", map[string]interface{}{"comment_id": 1})
+	})
+	t.Run("comment of another task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, _, _, err := (&TaskCommentRevision{TaskID: 2, CommentID: 1}).ReadAll(s, u, "", 0, -1)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskCommentDoesNotExist(err))
+	})
+	t.Run("no access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, _, _, err := (&TaskCommentRevision{TaskID: 14, CommentID: 1}).ReadAll(s, u, "", 0, -1)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+}
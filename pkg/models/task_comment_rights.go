@@ -27,9 +27,30 @@ func (tc *TaskComment) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
 	return t.CanRead(s, a)
 }
 
-func (tc *TaskComment) canUserModifyTaskComment(s *xorm.Session, a web.Auth) (bool, error) {
+// CanRead checks if a user can see the revisions of a comment
+func (r *TaskCommentRevision) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	t := Task{ID: r.TaskID}
+	return t.CanRead(s, a)
+}
+
+// canWriteTask checks if the user has write access to the task of a comment. Since frozen projects still
+// allow discussing their tasks, being frozen is not an error here.
+func (tc *TaskComment) canWriteTask(s *xorm.Session, a web.Auth) (bool, error) {
 	t := Task{ID: tc.TaskID}
-	canWriteTask, err := t.CanWrite(s, a)
+	can, err := t.CanWrite(s, a)
+	if !IsErrProjectIsFrozen(err) {
+		return can, err
+	}
+
+	task, err := GetTaskByIDSimple(s, tc.TaskID)
+	if err != nil {
+		return false, err
+	}
+	return (&Project{ID: task.ProjectID}).canWrite(s, a, true)
+}
+
+func (tc *TaskComment) canUserModifyTaskComment(s *xorm.Session, a web.Auth) (bool, error) {
+	canWriteTask, err := tc.canWriteTask(s, a)
 	if err != nil {
 		return false, err
 	}
@@ -61,6 +82,14 @@ func (tc *TaskComment) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
 
 // CanCreate checks if a user can create a new comment
 func (tc *TaskComment) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
-	t := Task{ID: tc.TaskID}
-	return t.CanWrite(s, a)
+	can, err := tc.canWriteTask(s, a)
+	if err != nil || can {
+		return can, err
+	}
+
+	task, err := GetTaskByIDSimple(s, tc.TaskID)
+	if err != nil {
+		return false, err
+	}
+	return (&Project{ID: task.ProjectID}).hasCapability(s, a, CapabilityComment)
 }
@@ -0,0 +1,120 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// TaskCommentThread is a comment together with all replies to it
+type TaskCommentThread struct {
+	// The task the comment belongs to
+	TaskID int64 `json:"-" param:"task"`
+	// The comment which started the thread
+	CommentID int64 `json:"-" param:"commentid"`
+
+	// The comment which started the thread. If the comment id in the url is a reply, this is the comment it replies to.
+	Comment *TaskComment `json:"comment"`
+	// All replies to the comment, oldest first.
+	Replies []*TaskComment `json:"replies"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+// CanRead checks if a user can read a comment thread
+func (tct *TaskCommentThread) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	t := &Task{ID: tct.TaskID}
+	return t.CanRead(s, a)
+}
+
+// ReadOne returns a comment thread
+// @Summary Get a comment thread
+// @Description Returns a comment with all replies to it. The user needs to have at least read access to the task.
+// @tags task
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param commentID path int true "Comment ID"
+// @Success 200 {object} models.TaskCommentThread "The comment thread."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 404 {object} web.HTTPError "The comment does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/comments/{commentID}/thread [get]
+func (tct *TaskCommentThread) ReadOne(s *xorm.Session, a web.Auth) (err error) {
+	comment := &TaskComment{ID: tct.CommentID}
+	err = getTaskCommentSimple(s, comment)
+	if err != nil {
+		return err
+	}
+	if comment.TaskID != tct.TaskID {
+		return ErrTaskCommentDoesNotExist{ID: tct.CommentID, TaskID: tct.TaskID}
+	}
+
+	if comment.ParentCommentID != 0 {
+		comment = &TaskComment{ID: comment.ParentCommentID}
+		err = getTaskCommentSimple(s, comment)
+		if err != nil {
+			return err
+		}
+	}
+
+	tct.Replies = []*TaskComment{}
+	err = s.
+		Where("parent_comment_id = ?", comment.ID).
+		OrderBy("created asc, id asc").
+		Find(&tct.Replies)
+	if err != nil {
+		return err
+	}
+
+	all := append([]*TaskComment{comment}, tct.Replies...)
+	authorIDs := make([]int64, 0, len(all))
+	commentIDs := make([]int64, 0, len(all))
+	for _, c := range all {
+		authorIDs = append(authorIDs, c.AuthorID)
+		commentIDs = append(commentIDs, c.ID)
+	}
+
+	authors, err := getUsersOrLinkSharesFromIDs(s, authorIDs)
+	if err != nil {
+		return err
+	}
+
+	reactions, err := getReactionsForEntityIDs(s, ReactionKindComment, commentIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range all {
+		c.Author = authors[c.AuthorID]
+		if r, has := reactions[c.ID]; has {
+			c.Reactions = r
+		}
+	}
+
+	comment.ReplyCount = int64(len(tct.Replies))
+	tct.Comment = comment
+
+	err = addAttachmentsToComments(s, all)
+	if err != nil {
+		return err
+	}
+
+	return addEditedToComments(s, all)
+}
@@ -0,0 +1,64 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskCommentThread_ReadOne(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		reply := &TaskComment{Comment: "reply", TaskID: 1, ParentCommentID: 1}
+		err := reply.Create(s, u)
+		require.NoError(t, err)
+
+		// Asking for the thread of a reply returns the whole thread
+		tct := &TaskCommentThread{TaskID: 1, CommentID: reply.ID}
+		can, _, err := tct.CanRead(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = tct.ReadOne(s, u)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1), tct.Comment.ID)
+		assert.Equal(t, int64(1), tct.Comment.ReplyCount)
+		require.Len(t, tct.Replies, 1)
+		assert.Equal(t, reply.ID, tct.Replies[0].ID)
+		assert.Equal(t, int64(1), tct.Replies[0].Author.ID)
+	})
+	t.Run("comment of another task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := (&TaskCommentThread{TaskID: 2, CommentID: 1}).ReadOne(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskCommentDoesNotExist(err))
+	})
+}
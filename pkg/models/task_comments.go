@@ -36,8 +36,28 @@ type TaskComment struct {
 	AuthorID int64      `xorm:"not null" json:"-"`
 	Author   *user.User `xorm:"-" json:"author"`
 	TaskID   int64      `xorm:"not null" json:"-" param:"task"`
+	// The id of the task this comment was converted into, if any.
+	ConvertedTaskID int64 `xorm:"bigint null default 0" json:"converted_task_id"`
+	// The id of the comment this comment is a reply to. Replies to replies end up in the thread of the first comment.
+	ParentCommentID int64 `xorm:"bigint null default 0 INDEX" json:"parent_comment_id"`
+	// The number of replies to this comment. Only set in the comment list.
+	ReplyCount int64 `xorm:"-" json:"reply_count"`
+	// Whether the comment was changed after it was created. All previous versions are available as revisions.
+	Edited bool `xorm:"-" json:"edited"`
+
+	// Pinned comments are returned before all other comments.
+	Pinned bool `xorm:"bool not null default false" json:"pinned"`
+	// When the comment was pinned.
+	PinnedAt   time.Time  `xorm:"datetime null" json:"pinned_at"`
+	PinnedByID int64      `xorm:"bigint null default 0" json:"-"`
+	PinnedBy   *user.User `xorm:"-" json:"pinned_by,omitempty"`
+
+	// If true, replies are returned together with all other comments instead of only in their thread.
+	IncludeReplies bool `xorm:"-" json:"-" query:"include_replies"`
 
 	Reactions ReactionMap `xorm:"-" json:"reactions"`
+	// All files attached to this comment. They are part of the task attachments as well.
+	Attachments []*TaskAttachment `xorm:"-" json:"attachments"`
 
 	Created time.Time `xorm:"created" json:"created"`
 	Updated time.Time `xorm:"updated" json:"updated"`
@@ -68,6 +88,10 @@ func (tc *TaskComment) Create(s *xorm.Session, a web.Auth) (err error) {
 
 	tc.Created = time.Time{}
 	tc.Updated = time.Time{}
+	tc.ConvertedTaskID = 0
+	tc.Pinned = false
+	tc.PinnedAt = time.Time{}
+	tc.PinnedByID = 0
 
 	return tc.CreateWithTimestamps(s, a)
 }
@@ -79,6 +103,20 @@ func (tc *TaskComment) CreateWithTimestamps(s *xorm.Session, a web.Auth) (err er
 		return err
 	}
 
+	if tc.ParentCommentID != 0 {
+		parent := &TaskComment{ID: tc.ParentCommentID}
+		err = getTaskCommentSimple(s, parent)
+		if err != nil {
+			return err
+		}
+		if parent.TaskID != tc.TaskID {
+			return ErrTaskCommentDoesNotExist{ID: tc.ParentCommentID, TaskID: tc.TaskID}
+		}
+		if parent.ParentCommentID != 0 {
+			tc.ParentCommentID = parent.ParentCommentID
+		}
+	}
+
 	tc.Author, err = GetUserOrLinkShareUser(s, a)
 	if err != nil {
 		return err
@@ -118,7 +156,7 @@ func (tc *TaskComment) CreateWithTimestamps(s *xorm.Session, a web.Auth) (err er
 // @Failure 404 {object} web.HTTPError "The task comment was not found."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /tasks/{taskID}/comments/{commentID} [delete]
-func (tc *TaskComment) Delete(s *xorm.Session, _ web.Auth) error {
+func (tc *TaskComment) Delete(s *xorm.Session, a web.Auth) error {
 	deleted, err := s.
 		ID(tc.ID).
 		NoAutoCondition().
@@ -131,6 +169,27 @@ func (tc *TaskComment) Delete(s *xorm.Session, _ web.Auth) error {
 		return err
 	}
 
+	err = deleteCommentAttachments(s, a, builder.Or(
+		builder.Eq{"comment_id": tc.ID},
+		builder.In("comment_id", builder.Select("id").From("task_comments").Where(builder.Eq{"parent_comment_id": tc.ID})),
+	))
+	if err != nil {
+		return err
+	}
+
+	err = deleteTaskCommentRevisions(s, builder.Or(
+		builder.Eq{"comment_id": tc.ID},
+		builder.In("comment_id", builder.Select("id").From("task_comments").Where(builder.Eq{"parent_comment_id": tc.ID})),
+	))
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("parent_comment_id = ?", tc.ID).Delete(&TaskComment{})
+	if err != nil {
+		return err
+	}
+
 	task, err := GetTaskByIDSimple(s, tc.TaskID)
 	if err != nil {
 		return err
@@ -145,7 +204,7 @@ func (tc *TaskComment) Delete(s *xorm.Session, _ web.Auth) error {
 
 // Update updates a task text by its ID
 // @Summary Update an existing task comment
-// @Description Update an existing task comment. The user doing this need to have at least write access to the task this comment belongs to.
+// @Description Update an existing task comment. The user doing this need to have at least write access to the task this comment belongs to. The previous text of the comment is kept as a revision.
 // @tags task
 // @Accept json
 // @Produce json
@@ -157,7 +216,21 @@ func (tc *TaskComment) Delete(s *xorm.Session, _ web.Auth) error {
 // @Failure 404 {object} web.HTTPError "The task comment was not found."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /tasks/{taskID}/comments/{commentID} [post]
-func (tc *TaskComment) Update(s *xorm.Session, _ web.Auth) error {
+func (tc *TaskComment) Update(s *xorm.Session, a web.Auth) error {
+	saved := &TaskComment{ID: tc.ID}
+	err := getTaskCommentSimple(s, saved)
+	if err != nil {
+		return err
+	}
+
+	if saved.Comment != tc.Comment {
+		err = saveTaskCommentRevision(s, saved, a)
+		if err != nil {
+			return err
+		}
+		tc.Edited = true
+	}
+
 	updated, err := s.
 		ID(tc.ID).
 		Cols("comment").
@@ -226,17 +299,24 @@ func (tc *TaskComment) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
 		Where("id = ?", tc.AuthorID).
 		Get(author)
 	tc.Author = author
-	return
+
+	err = addAttachmentsToComments(s, []*TaskComment{tc})
+	if err != nil {
+		return err
+	}
+
+	return addEditedToComments(s, []*TaskComment{tc})
 }
 
 // ReadAll returns all comments for a task
 // @Summary Get all task comments
-// @Description Get all task comments. The user doing this need to have at least read access to the task.
+// @Description Get all task comments. The user doing this need to have at least read access to the task. Pinned comments come first, in the order they were pinned, followed by all other comments from oldest to newest. Replies are only included when searching or if `include_replies` is true, otherwise only their number is returned with each comment.
 // @tags task
 // @Accept json
 // @Produce json
 // @Security JWTKeyAuth
 // @Param taskID path int true "Task ID"
+// @Param include_replies query bool false "If true, replies are returned in the list as well."
 // @Success 200 {array} models.TaskComment "The array with all task comments"
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /tasks/{taskID}/comments [get]
@@ -267,10 +347,13 @@ func (tc *TaskComment) ReadAll(s *xorm.Session, auth web.Auth, search string, pa
 	if search != "" {
 		where = append(where, db.ILIKE("comment", search))
 	}
+	if search == "" && !tc.IncludeReplies {
+		where = append(where, builder.Eq{"parent_comment_id": 0})
+	}
 	query := s.
 		Where(builder.And(where...)).
 		Join("LEFT", "users", "users.id = task_comments.author_id").
-		OrderBy("task_comments.created asc")
+		OrderBy("task_comments.pinned desc, task_comments.pinned_at asc, task_comments.created asc")
 	if limit > 0 {
 		query = query.Limit(limit, start)
 	}
@@ -284,6 +367,9 @@ func (tc *TaskComment) ReadAll(s *xorm.Session, auth web.Auth, search string, pa
 	for _, comment := range comments {
 		authorIDs = append(authorIDs, comment.AuthorID)
 		commentIDs = append(commentIDs, comment.ID)
+		if comment.PinnedByID != 0 {
+			authorIDs = append(authorIDs, comment.PinnedByID)
+		}
 	}
 
 	authors, err := getUsersOrLinkSharesFromIDs(s, authorIDs)
@@ -298,14 +384,89 @@ func (tc *TaskComment) ReadAll(s *xorm.Session, auth web.Auth, search string, pa
 
 	for _, comment := range comments {
 		comment.Author = authors[comment.AuthorID]
+		if comment.PinnedByID != 0 {
+			comment.PinnedBy = authors[comment.PinnedByID]
+		}
 		r, has := reactions[comment.ID]
 		if has {
 			comment.Reactions = r
 		}
 	}
 
+	err = addReplyCountsToComments(s, comments)
+	if err != nil {
+		return
+	}
+
+	err = addEditedToComments(s, comments)
+	if err != nil {
+		return
+	}
+
+	err = addAttachmentsToComments(s, comments)
+	if err != nil {
+		return
+	}
+
 	numberOfTotalItems, err = s.
-		Where("task_id = ? AND comment like ?", tc.TaskID, "%"+search+"%").
+		Where(builder.And(where...)).
 		Count(&TaskCommentWithAuthor{})
 	return comments, len(comments), numberOfTotalItems, err
 }
+
+func addReplyCountsToComments(s *xorm.Session, comments []*TaskComment) (err error) {
+	commentIDs := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		if comment.ParentCommentID == 0 {
+			commentIDs = append(commentIDs, comment.ID)
+		}
+	}
+	if len(commentIDs) == 0 {
+		return nil
+	}
+
+	counts := []*struct {
+		ParentCommentID int64
+		Count           int64
+	}{}
+	err = s.
+		Table("task_comments").
+		Select("parent_comment_id, COUNT(*) AS count").
+		In("parent_comment_id", commentIDs).
+		GroupBy("parent_comment_id").
+		Find(&counts)
+	if err != nil {
+		return err
+	}
+
+	countsByComment := make(map[int64]int64, len(counts))
+	for _, c := range counts {
+		countsByComment[c.ParentCommentID] = c.Count
+	}
+	for _, comment := range comments {
+		comment.ReplyCount = countsByComment[comment.ID]
+	}
+
+	return nil
+}
+
+// getCommentThreadParticipants returns all users who wrote the first comment of a thread or replied to it.
+func getCommentThreadParticipants(s *xorm.Session, threadCommentID int64) (participants map[int64]*user.User, err error) {
+	authorIDs := []int64{}
+	err = s.
+		Table("task_comments").
+		Where(builder.And(
+			builder.Or(
+				builder.Eq{"id": threadCommentID},
+				builder.Eq{"parent_comment_id": threadCommentID},
+			),
+			builder.Gt{"author_id": 0},
+		)).
+		Distinct("author_id").
+		Find(&authorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return user.GetUsersByIDs(s, authorIDs)
+}
@@ -92,6 +92,57 @@ func TestTaskComment_Create(t *testing.T) {
 			"name":          (&TaskCommentNotification{}).Name(),
 		}, false)
 	})
+	t.Run("reply", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		reply := &TaskComment{Comment: "reply", TaskID: 1, ParentCommentID: 1}
+		err := reply.Create(s, u)
+		require.NoError(t, err)
+
+		// Replies to replies end up in the same thread
+		nested := &TaskComment{Comment: "nested", TaskID: 1, ParentCommentID: reply.ID}
+		err = nested.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), nested.ParentCommentID)
+	})
+	t.Run("reply to a comment of another task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskComment{Comment: "reply", TaskID: 2, ParentCommentID: 1}
+		err := tc.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskCommentDoesNotExist(err))
+	})
+	t.Run("should notify thread participants about replies", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Insert(&TaskComment{Comment: "first reply", TaskID: 1, AuthorID: 2, ParentCommentID: 1})
+		require.NoError(t, err)
+
+		task, err := GetTaskByIDSimple(s, 1)
+		require.NoError(t, err)
+		tc := &TaskComment{Comment: "second reply", TaskID: 1, ParentCommentID: 1}
+		err = tc.Create(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		events.TestListener(t, &TaskCommentCreatedEvent{
+			Task:    &task,
+			Doer:    u,
+			Comment: tc,
+		}, &SendTaskCommentNotification{})
+		db.AssertExists(t, "notifications", map[string]interface{}{
+			"subject_id":    tc.ID,
+			"notifiable_id": 2,
+			"name":          (&TaskCommentNotification{}).Name(),
+		}, false)
+	})
 }
 
 func TestTaskComment_Delete(t *testing.T) {
@@ -226,6 +277,26 @@ func TestTaskComment_ReadAll(t *testing.T) {
 		assert.Equal(t, "Lorem Ipsum Dolor Sit Amet", resultComment[0].Comment)
 		assert.NotEmpty(t, resultComment[0].Author.ID)
 	})
+	t.Run("replies are only counted", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 1}
+		err := (&TaskComment{Comment: "reply", TaskID: 1, ParentCommentID: 1}).Create(s, u)
+		require.NoError(t, err)
+
+		result, _, total, err := (&TaskComment{TaskID: 1}).ReadAll(s, u, "", 0, -1)
+		require.NoError(t, err)
+		comments := result.([]*TaskComment)
+		require.Len(t, comments, 1)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, int64(1), comments[0].ReplyCount)
+
+		result, _, _, err = (&TaskComment{TaskID: 1, IncludeReplies: true}).ReadAll(s, u, "", 0, -1)
+		require.NoError(t, err)
+		assert.Len(t, result.([]*TaskComment), 2)
+	})
 	t.Run("no access to task", func(t *testing.T) {
 		db.LoadAndAssertFixtures(t)
 		s := db.NewSession()
@@ -0,0 +1,129 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/user"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// shiftDependentTaskDates moves the dates of all tasks blocked by a task by the given duration, following the
+// blocking relations further down until no more tasks are left. Tasks which are already done or which the user
+// cannot edit are left alone, and so are the tasks they are blocking.
+func shiftDependentTaskDates(s *xorm.Session, taskID int64, shift time.Duration, a web.Auth) (shifted []*Task, err error) {
+	shifted = []*Task{}
+	visited := map[int64]bool{taskID: true}
+	blocking := []int64{taskID}
+
+	doer, _ := user.GetFromAuth(a)
+
+	for len(blocking) > 0 {
+		relations := []*TaskRelation{}
+		err = s.
+			In("task_id", blocking).
+			And("relation_kind = ?", RelationKindBlocking).
+			Find(&relations)
+		if err != nil {
+			return nil, err
+		}
+
+		blockedIDs := []int64{}
+		for _, rel := range relations {
+			if visited[rel.OtherTaskID] {
+				continue
+			}
+			visited[rel.OtherTaskID] = true
+			blockedIDs = append(blockedIDs, rel.OtherTaskID)
+		}
+
+		blocking = []int64{}
+		if len(blockedIDs) == 0 {
+			break
+		}
+
+		blocked := []*Task{}
+		err = s.In("id", blockedIDs).OrderBy("id asc").Find(&blocked)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, task := range blocked {
+			if task.Done {
+				continue
+			}
+
+			canWrite, err := task.CanWrite(s, a)
+			if err != nil {
+				return nil, err
+			}
+			if !canWrite {
+				continue
+			}
+
+			// Tasks without any dates have nothing to move, but the tasks they are blocking might
+			blocking = append(blocking, task.ID)
+			if task.StartDate.IsZero() && task.EndDate.IsZero() && task.DueDate.IsZero() {
+				continue
+			}
+
+			task.shiftDates(shift)
+
+			_, err = s.
+				ID(task.ID).
+				Cols("start_date", "end_date", "due_date").
+				Update(task)
+			if err != nil {
+				return nil, err
+			}
+
+			err = resetReminderTriggers(s, task.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			err = events.Dispatch(&TaskUpdatedEvent{
+				Task: task,
+				Doer: doer,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			shifted = append(shifted, task)
+		}
+	}
+
+	return shifted, nil
+}
+
+// shiftDates moves all dates of a task which are set by the given duration.
+func (t *Task) shiftDates(shift time.Duration) {
+	if !t.StartDate.IsZero() {
+		t.StartDate = t.StartDate.Add(shift)
+	}
+	if !t.EndDate.IsZero() {
+		t.EndDate = t.EndDate.Add(shift)
+	}
+	if !t.DueDate.IsZero() {
+		t.DueDate = t.DueDate.Add(shift)
+	}
+}
@@ -0,0 +1,102 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTask_Update_ShiftDependentTasks(t *testing.T) {
+	u := &user.User{ID: 1}
+	due := time.Date(2026, 3, 2, 12, 0, 0, 0, time.Local)
+	start := time.Date(2026, 3, 3, 9, 0, 0, 0, time.Local)
+
+	setup := func(t *testing.T) {
+		s := db.NewSession()
+		defer s.Close()
+
+		// 1 blocks 3 blocks 4, and 4 blocks 1 again to make sure this does not loop forever
+		_, err := s.Insert(&[]*TaskRelation{
+			{TaskID: 1, OtherTaskID: 3, RelationKind: RelationKindBlocking, CreatedByID: 1},
+			{TaskID: 3, OtherTaskID: 4, RelationKind: RelationKindBlocking, CreatedByID: 1},
+			{TaskID: 4, OtherTaskID: 1, RelationKind: RelationKindBlocking, CreatedByID: 1},
+		})
+		require.NoError(t, err)
+		_, err = s.ID(1).Cols("due_date").Update(&Task{DueDate: due})
+		require.NoError(t, err)
+		_, err = s.ID(3).Cols("start_date", "due_date").Update(&Task{StartDate: start, DueDate: start.Add(24 * time.Hour)})
+		require.NoError(t, err)
+		_, err = s.ID(4).Cols("due_date").Update(&Task{DueDate: start.Add(48 * time.Hour)})
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+	}
+
+	t.Run("shift", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		setup(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 1}
+		err := task.ReadOne(s, u)
+		require.NoError(t, err)
+		task.DueDate = due.Add(72 * time.Hour)
+		task.ShiftDependentTasks = true
+		err = task.Update(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		require.Len(t, task.ShiftedTasks, 2)
+		assert.Equal(t, int64(3), task.ShiftedTasks[0].ID)
+		assert.Equal(t, int64(4), task.ShiftedTasks[1].ID)
+
+		task3, err := GetTaskByIDSimple(s, 3)
+		require.NoError(t, err)
+		assert.Equal(t, start.Add(72*time.Hour).Unix(), task3.StartDate.Unix())
+		assert.Equal(t, start.Add(96*time.Hour).Unix(), task3.DueDate.Unix())
+		assert.True(t, task3.EndDate.IsZero())
+		task4, err := GetTaskByIDSimple(s, 4)
+		require.NoError(t, err)
+		assert.Equal(t, start.Add(120*time.Hour).Unix(), task4.DueDate.Unix())
+	})
+	t.Run("without flag", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		setup(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 1}
+		err := task.ReadOne(s, u)
+		require.NoError(t, err)
+		task.DueDate = due.Add(72 * time.Hour)
+		err = task.Update(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		assert.Empty(t, task.ShiftedTasks)
+		task3, err := GetTaskByIDSimple(s, 3)
+		require.NoError(t, err)
+		assert.Equal(t, start.Unix(), task3.StartDate.Unix())
+	})
+}
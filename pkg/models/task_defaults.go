@@ -0,0 +1,188 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"reflect"
+
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// ProjectTaskDefaults holds the values new tasks in a project get if they were not set when creating them.
+// The bucket new tasks are put into is configured per kanban view through its default bucket.
+type ProjectTaskDefaults struct {
+	// The ids of labels every new task gets.
+	LabelIDs []int64 `json:"label_ids"`
+	// The id of a user every new task without assignees is assigned to. The user needs access to the project.
+	AssigneeID int64 `json:"assignee_id"`
+	// If a new task has a due date but no reminders, a reminder is added this many seconds before the due date.
+	ReminderOffset int64 `json:"reminder_offset"`
+	// The priority new tasks get if they are created without one.
+	Priority int64 `json:"priority"`
+}
+
+func (d *ProjectTaskDefaults) isEmpty() bool {
+	return d == nil || reflect.DeepEqual(d, &ProjectTaskDefaults{})
+}
+
+// validateTaskDefaults checks that all labels and the assignee in the defaults can actually be used
+func validateTaskDefaults(s *xorm.Session, project *Project, a web.Auth) error {
+	defaults := project.TaskDefaults
+	if defaults.isEmpty() {
+		return nil
+	}
+
+	if defaults.Priority < 0 || defaults.Priority > taskPriorityDoNow {
+		return InvalidFieldError([]string{"task_defaults.priority"})
+	}
+	if defaults.ReminderOffset < 0 {
+		return InvalidFieldError([]string{"task_defaults.reminder_offset"})
+	}
+
+	for _, labelID := range defaults.LabelIDs {
+		label := &Label{ID: labelID}
+		has, _, err := label.hasAccessToLabel(s, a)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ErrUserHasNoAccessToLabel{LabelID: labelID, UserID: a.GetID()}
+		}
+	}
+
+	if defaults.AssigneeID != 0 {
+		assignee, err := user.GetUserByID(s, defaults.AssigneeID)
+		if err != nil {
+			return err
+		}
+		canRead, _, err := project.CanRead(s, assignee)
+		if err != nil {
+			return err
+		}
+		if !canRead {
+			return ErrUserDoesNotHaveAccessToProject{ProjectID: project.ID, UserID: assignee.ID}
+		}
+	}
+
+	return nil
+}
+
+// checkTaskDefaultsUpdate makes sure only project admins change the task defaults of a project
+func checkTaskDefaultsUpdate(s *xorm.Session, project *Project, a web.Auth) error {
+	old, err := GetProjectSimpleByID(s, project.ID)
+	if err != nil {
+		return err
+	}
+
+	// Clients which don't know about the defaults should not remove them when updating the project
+	if project.TaskDefaults == nil {
+		project.TaskDefaults = old.TaskDefaults
+		return nil
+	}
+
+	if old.TaskDefaults.isEmpty() && project.TaskDefaults.isEmpty() ||
+		reflect.DeepEqual(old.TaskDefaults, project.TaskDefaults) {
+		return nil
+	}
+
+	isAdmin, err := project.IsAdmin(s, a)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return ErrGenericForbidden{}
+	}
+
+	return validateTaskDefaults(s, project, a)
+}
+
+// applyBeforeCreate sets all defaults which are stored with the task itself
+func (d *ProjectTaskDefaults) applyBeforeCreate(t *Task) {
+	if d.isEmpty() {
+		return
+	}
+
+	if t.Priority == 0 {
+		t.Priority = d.Priority
+	}
+
+	if d.ReminderOffset > 0 && !t.DueDate.IsZero() && len(t.Reminders) == 0 {
+		t.Reminders = []*TaskReminder{
+			{
+				RelativeTo:     ReminderRelationDueDate,
+				RelativePeriod: -d.ReminderOffset,
+			},
+		}
+	}
+}
+
+// applyAfterCreate adds the default labels and assignee to a newly created task
+func (d *ProjectTaskDefaults) applyAfterCreate(s *xorm.Session, t *Task, project *Project, a web.Auth) (err error) {
+	if d.isEmpty() {
+		return nil
+	}
+
+	for _, labelID := range d.LabelIDs {
+		label, err := getLabelByIDSimple(s, labelID)
+		if IsErrLabelDoesNotExist(err) {
+			// The label was deleted after it was set as default
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// Explicitly set labels win over default ones of the same exclusive group
+		err = checkExclusiveLabelGroupsOfTask(s, t.ID, label.ID)
+		if IsErrLabelGroupIsExclusive(err) {
+			log.Debugf("Not adding default label %d to task %d: %s", label.ID, t.ID, err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = s.Insert(&LabelTask{TaskID: t.ID, LabelID: label.ID})
+		if err != nil {
+			return err
+		}
+		t.Labels = append(t.Labels, label)
+	}
+
+	if d.AssigneeID == 0 || len(t.Assignees) > 0 {
+		return nil
+	}
+
+	err = t.addNewAssigneeByID(s, d.AssigneeID, project, a)
+	if IsErrUserDoesNotHaveAccessToProject(err) || user.IsErrUserDoesNotExist(err) {
+		log.Debugf("Not assigning default assignee %d to task %d: %s", d.AssigneeID, t.ID, err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	assignee, err := user.GetUserByID(s, d.AssigneeID)
+	if err != nil {
+		return err
+	}
+	t.Assignees = append(t.Assignees, assignee)
+	return nil
+}
@@ -0,0 +1,113 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectTaskDefaults(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("applied to new tasks", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(1).Cols("task_defaults").Update(&Project{TaskDefaults: &ProjectTaskDefaults{
+			Priority: 3,
+			LabelIDs: []int64{1},
+		}})
+		require.NoError(t, err)
+
+		task := &Task{Title: "Lorem", ProjectID: 1}
+		err = task.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), task.Priority)
+		assert.Len(t, task.Labels, 1)
+		db.AssertExists(t, "label_tasks", map[string]interface{}{
+			"task_id":  task.ID,
+			"label_id": 1,
+		}, false)
+	})
+	t.Run("explicit values win", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(1).Cols("task_defaults").Update(&Project{TaskDefaults: &ProjectTaskDefaults{Priority: 3}})
+		require.NoError(t, err)
+
+		task := &Task{Title: "Lorem", ProjectID: 1, Priority: 1}
+		err = task.Create(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), task.Priority)
+	})
+	t.Run("only admins can change them", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// User 1 only has write access to project 10
+		project, err := GetProjectSimpleByID(s, 10)
+		require.NoError(t, err)
+		project.TaskDefaults = &ProjectTaskDefaults{Priority: 2}
+		err = project.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+	t.Run("kept when not provided", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(10).Cols("task_defaults").Update(&Project{TaskDefaults: &ProjectTaskDefaults{Priority: 3}})
+		require.NoError(t, err)
+
+		// User 1 only has write access to project 10, which is enough as long as the defaults don't change
+		project, err := GetProjectSimpleByID(s, 10)
+		require.NoError(t, err)
+		project.TaskDefaults = nil
+		project.Title = "Changed"
+		err = project.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		project, err = GetProjectSimpleByID(s, 10)
+		require.NoError(t, err)
+		require.NotNil(t, project.TaskDefaults)
+		assert.Equal(t, int64(3), project.TaskDefaults.Priority)
+	})
+	t.Run("invalid priority", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		project, err := GetProjectSimpleByID(s, 1)
+		require.NoError(t, err)
+		project.TaskDefaults = &ProjectTaskDefaults{Priority: 42}
+		err = project.Update(s, u)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,282 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/api/pkg/utils"
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// TaskDescriptionRevision holds a previous version of a task's description
+type TaskDescriptionRevision struct {
+	ID     int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"revision"`
+	TaskID int64 `xorm:"bigint not null INDEX" json:"task_id" param:"task"`
+	// The description of the task before it was changed.
+	Description string `xorm:"longtext null" json:"description"`
+	// The changes from this revision to the version of the description which replaced it.
+	Diff string `xorm:"-" json:"diff"`
+
+	CreatedByID int64 `xorm:"bigint not null" json:"-"`
+	// The user who changed the description away from this revision.
+	CreatedBy *user.User `xorm:"-" json:"created_by"`
+
+	// A timestamp when this revision was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for task description revisions
+func (*TaskDescriptionRevision) TableName() string {
+	return "task_description_revisions"
+}
+
+// saveTaskDescriptionRevision stores the current description of a task as a revision before it gets replaced.
+// An empty description is only worth keeping if the task had one before, so clearing it can be undone as well.
+func saveTaskDescriptionRevision(s *xorm.Session, task *Task, a web.Auth) error {
+	if task.Description == "" {
+		hasRevisions, err := s.
+			Where("task_id = ?", task.ID).
+			Exist(&TaskDescriptionRevision{})
+		if err != nil || !hasRevisions {
+			return err
+		}
+	}
+
+	doer, err := GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Insert(&TaskDescriptionRevision{
+		TaskID:      task.ID,
+		Description: task.Description,
+		CreatedByID: doer.ID,
+	})
+	return err
+}
+
+func getTaskDescriptionRevision(s *xorm.Session, id, taskID int64) (revision *TaskDescriptionRevision, err error) {
+	revision = &TaskDescriptionRevision{}
+	exists, err := s.
+		Where("id = ? AND task_id = ?", id, taskID).
+		Get(revision)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrTaskDescriptionRevisionDoesNotExist{ID: id, TaskID: taskID}
+	}
+	return
+}
+
+// ReadAll returns all previous versions of a task's description
+// @Summary Get all description revisions of a task
+// @Description Returns all previous versions of the description of a task, newest first. Each revision contains the changes to the version which replaced it.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param task path int true "Task ID"
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Success 200 {array} models.TaskDescriptionRevision "The description revisions of the task"
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{task}/description/revisions [get]
+func (r *TaskDescriptionRevision) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	canRead, _, err := r.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !canRead {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	task, err := GetTaskByIDSimple(s, r.TaskID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	limit, start := getLimitFromPageIndex(page, perPage)
+	query := s.
+		Where("task_id = ?", r.TaskID).
+		OrderBy("id desc")
+	if limit > 0 {
+		query = query.Limit(limit, start)
+	}
+
+	revisions := []*TaskDescriptionRevision{}
+	err = query.Find(&revisions)
+	if err != nil {
+		return
+	}
+
+	if len(revisions) == 0 {
+		return revisions, 0, 0, nil
+	}
+
+	// The newest revision on this page was replaced either by the current description or by the revision
+	// right after it, which lives on the previous page.
+	replacedBy := &TaskDescriptionRevision{Description: task.Description}
+	_, err = s.
+		Where("task_id = ? AND id > ?", r.TaskID, revisions[0].ID).
+		OrderBy("id asc").
+		Get(replacedBy)
+	if err != nil {
+		return
+	}
+
+	userIDs := make([]int64, 0, len(revisions))
+	for _, revision := range revisions {
+		userIDs = append(userIDs, revision.CreatedByID)
+	}
+	users, err := getUsersOrLinkSharesFromIDs(s, userIDs)
+	if err != nil {
+		return
+	}
+
+	newerDescription := replacedBy.Description
+	for _, revision := range revisions {
+		revision.Diff = utils.LineDiff(revision.Description, newerDescription)
+		revision.CreatedBy = users[revision.CreatedByID]
+		newerDescription = revision.Description
+	}
+
+	numberOfTotalItems, err = s.
+		Where("task_id = ?", r.TaskID).
+		Count(&TaskDescriptionRevision{})
+	return revisions, len(revisions), numberOfTotalItems, err
+}
+
+// ReadOne returns a single description revision
+// @Summary Get one description revision of a task
+// @Description Returns a single previous version of the description of a task. The diff contains the changes from this revision to the current description.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param task path int true "Task ID"
+// @Param revision path int true "Revision ID"
+// @Success 200 {object} models.TaskDescriptionRevision "The revision"
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 404 {object} web.HTTPError "The revision does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{task}/description/revisions/{revision} [get]
+func (r *TaskDescriptionRevision) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	revision, err := getTaskDescriptionRevision(s, r.ID, r.TaskID)
+	if err != nil {
+		return err
+	}
+
+	task, err := GetTaskByIDSimple(s, r.TaskID)
+	if err != nil {
+		return err
+	}
+
+	users, err := getUsersOrLinkSharesFromIDs(s, []int64{revision.CreatedByID})
+	if err != nil {
+		return err
+	}
+
+	*r = *revision
+	r.CreatedBy = users[r.CreatedByID]
+	r.Diff = utils.LineDiff(r.Description, task.Description)
+	return nil
+}
+
+// TaskDescriptionRevisionRestore restores a previous version of a task's description
+type TaskDescriptionRevisionRestore struct {
+	TaskID     int64 `json:"-" param:"task"`
+	RevisionID int64 `json:"-" param:"revision"`
+
+	// The task with the restored description
+	Task *Task `json:"task,omitempty"`
+
+	web.CRUDable `json:"-"`
+	web.Rights   `json:"-"`
+}
+
+// Update restores a description revision
+// @Summary Restore a description revision
+// @Description Replaces the description of a task with a previous version. The description which is replaced is kept as a new revision so that restoring can be undone.
+// @tags task
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param task path int true "Task ID"
+// @Param revision path int true "Revision ID"
+// @Success 200 {object} models.TaskDescriptionRevisionRestore "The task with the restored description."
+// @Failure 403 {object} web.HTTPError "The user does not have write access to the task."
+// @Failure 404 {object} web.HTTPError "The revision does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{task}/description/revisions/{revision}/restore [post]
+func (rr *TaskDescriptionRevisionRestore) Update(s *xorm.Session, a web.Auth) (err error) {
+	revision, err := getTaskDescriptionRevision(s, rr.RevisionID, rr.TaskID)
+	if err != nil {
+		return err
+	}
+
+	task, err := GetTaskByIDSimple(s, rr.TaskID)
+	if err != nil {
+		return err
+	}
+
+	if task.Description == revision.Description {
+		rr.Task = &task
+		return nil
+	}
+
+	err = saveTaskDescriptionRevision(s, &task, a)
+	if err != nil {
+		return err
+	}
+
+	task.Description = revision.Description
+	_, err = s.
+		ID(task.ID).
+		Cols("description").
+		Update(&task)
+	if err != nil {
+		return err
+	}
+
+	rr.Task = &task
+
+	err = IndexTasksByID(s, []int64{task.ID})
+	if err != nil {
+		return err
+	}
+
+	doer, _ := user.GetFromAuth(a)
+	err = events.Dispatch(&TaskUpdatedEvent{
+		Task: &task,
+		Doer: doer,
+	})
+	if err != nil {
+		return err
+	}
+
+	return updateProjectLastUpdated(s, &Project{ID: task.ProjectID})
+}
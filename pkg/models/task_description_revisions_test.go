@@ -0,0 +1,144 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskDescriptionRevision_ReadAll(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &TaskDescriptionRevision{TaskID: 1}
+		result, resultCount, total, err := r.ReadAll(s, u, "", 0, 50)
+		require.NoError(t, err)
+		assert.Equal(t, 1, resultCount)
+		assert.Equal(t, int64(1), total)
+		revisions := result.([]*TaskDescriptionRevision)
+		assert.Equal(t, "Lorem", revisions[0].Description)
+		assert.Equal(t, "- Lorem\n+ Lorem Ipsum", revisions[0].Diff)
+		assert.Equal(t, int64(1), revisions[0].CreatedBy.ID)
+	})
+	t.Run("saved when the description changes", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{
+			ID:          1,
+			ProjectID:   1,
+			Title:       "task #1",
+			Description: "Lorem Ipsum\nDolor",
+		}
+		err := task.Update(s, u)
+		require.NoError(t, err)
+
+		r := &TaskDescriptionRevision{TaskID: 1}
+		result, resultCount, _, err := r.ReadAll(s, u, "", 0, 50)
+		require.NoError(t, err)
+		assert.Equal(t, 2, resultCount)
+		revisions := result.([]*TaskDescriptionRevision)
+		assert.Equal(t, "Lorem Ipsum", revisions[0].Description)
+		assert.Equal(t, "  Lorem Ipsum\n+ Dolor", revisions[0].Diff)
+		assert.Equal(t, "Lorem", revisions[1].Description)
+		assert.Equal(t, "- Lorem\n+ Lorem Ipsum", revisions[1].Diff)
+	})
+	t.Run("cleared description is kept", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		for _, description := range []string{"", "Dolor"} {
+			task := &Task{
+				ID:          1,
+				ProjectID:   1,
+				Title:       "task #1",
+				Description: description,
+			}
+			err := task.Update(s, u)
+			require.NoError(t, err)
+		}
+
+		r := &TaskDescriptionRevision{TaskID: 1}
+		result, resultCount, _, err := r.ReadAll(s, u, "", 0, 50)
+		require.NoError(t, err)
+		assert.Equal(t, 3, resultCount)
+		revisions := result.([]*TaskDescriptionRevision)
+		assert.Equal(t, "", revisions[0].Description)
+		assert.Equal(t, "Lorem Ipsum", revisions[1].Description)
+	})
+	t.Run("no access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		r := &TaskDescriptionRevision{TaskID: 1}
+		_, _, _, err := r.ReadAll(s, &user.User{ID: 2}, "", 0, 50)
+		require.Error(t, err)
+		assert.True(t, IsErrGenericForbidden(err))
+	})
+}
+
+func TestTaskDescriptionRevisionRestore_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		rr := &TaskDescriptionRevisionRestore{TaskID: 1, RevisionID: 1}
+		can, err := rr.CanUpdate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = rr.Update(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, "Lorem", rr.Task.Description)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":          1,
+			"description": "Lorem",
+		}, false)
+		db.AssertExists(t, "task_description_revisions", map[string]interface{}{
+			"task_id":     1,
+			"description": "Lorem Ipsum",
+		}, false)
+	})
+	t.Run("revision of another task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		rr := &TaskDescriptionRevisionRestore{TaskID: 2, RevisionID: 1}
+		err := rr.Update(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskDescriptionRevisionDoesNotExist(err))
+	})
+}
@@ -0,0 +1,58 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+)
+
+// TaskDoneVia holds the client a task was marked as done from.
+type TaskDoneVia string
+
+const (
+	TaskDoneViaWeb       TaskDoneVia = "web"
+	TaskDoneViaCaldav    TaskDoneVia = "caldav"
+	TaskDoneViaAPIToken  TaskDoneVia = "api_token"
+	TaskDoneViaLinkShare TaskDoneVia = "link_share"
+)
+
+// setDoneBy records who marked the task as done and through which client.
+// Passing a nil auth resets it, for example when the task is marked as undone again.
+func (t *Task) setDoneBy(a web.Auth) {
+	switch auth := a.(type) {
+	case *LinkSharing:
+		t.DoneByID = auth.getUserID()
+		t.DoneBy = auth.toUser()
+		t.DoneVia = TaskDoneViaLinkShare
+	case *user.User:
+		t.DoneByID = auth.ID
+		t.DoneBy = auth
+		switch auth.AuthSource {
+		case user.AuthSourceCaldav:
+			t.DoneVia = TaskDoneViaCaldav
+		case user.AuthSourceAPIToken:
+			t.DoneVia = TaskDoneViaAPIToken
+		default:
+			t.DoneVia = TaskDoneViaWeb
+		}
+	default:
+		t.DoneByID = 0
+		t.DoneBy = nil
+		t.DoneVia = ""
+	}
+}
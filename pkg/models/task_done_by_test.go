@@ -0,0 +1,164 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTask_DoneBy(t *testing.T) {
+	t.Run("marked done from the web", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 1, Done: true}
+		err := task.Update(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1), task.DoneByID)
+		assert.Equal(t, TaskDoneViaWeb, task.DoneVia)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":         1,
+			"done":       true,
+			"done_by_id": 1,
+			"done_via":   "web",
+		}, false)
+	})
+	t.Run("marked done with an api token", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 1, Done: true}
+		err := task.Update(s, &user.User{ID: 1, AuthSource: user.AuthSourceAPIToken})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":         1,
+			"done_by_id": 1,
+			"done_via":   "api_token",
+		}, false)
+	})
+	t.Run("marked done by a link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 13, Done: true}
+		err := task.Update(s, &LinkSharing{ID: 2, ProjectID: 2, Right: RightWrite})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":         13,
+			"done_by_id": -2,
+			"done_via":   "link_share",
+		}, false)
+	})
+	t.Run("done via is not taken from the request", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 1, Done: true, DoneVia: TaskDoneViaCaldav}
+		err := task.Update(s, &user.User{ID: 1})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":       1,
+			"done_via": "web",
+		}, false)
+	})
+	t.Run("marking a task undone resets it", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 1}
+		task := &Task{ID: 1, Done: true}
+		err := task.Update(s, u)
+		require.NoError(t, err)
+
+		task = &Task{ID: 1, Title: "task #1", Done: false}
+		err = task.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(0), task.DoneByID)
+		assert.Empty(t, task.DoneVia)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":         1,
+			"done":       false,
+			"done_by_id": 0,
+		}, false)
+	})
+	t.Run("moved into the done bucket", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tb := &TaskBucket{
+			TaskID:        1,
+			BucketID:      3,
+			ProjectViewID: 4,
+			ProjectID:     1,
+		}
+		err := tb.Update(s, &user.User{ID: 1, AuthSource: user.AuthSourceCaldav})
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":         1,
+			"done":       true,
+			"done_by_id": 1,
+			"done_via":   "caldav",
+		}, false)
+	})
+	t.Run("loaded with the task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 1}
+		task := &Task{ID: 1, Done: true}
+		err := task.Update(s, u)
+		require.NoError(t, err)
+
+		task = &Task{ID: 1}
+		err = task.ReadOne(s, u)
+		require.NoError(t, err)
+		require.NotNil(t, task.DoneBy)
+		assert.Equal(t, int64(1), task.DoneBy.ID)
+		assert.Equal(t, TaskDoneViaWeb, task.DoneVia)
+	})
+}
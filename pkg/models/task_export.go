@@ -0,0 +1,255 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/utils"
+	"code.vikunja.io/web"
+
+	"github.com/c2h5oh/datasize"
+	"xorm.io/xorm"
+)
+
+// TaskExportFormat is the format of a single exported task
+type TaskExportFormat string
+
+// All formats a task can be exported in
+const (
+	TaskExportFormatMarkdown TaskExportFormat = `markdown`
+	TaskExportFormatPDF      TaskExportFormat = `pdf`
+)
+
+const taskExportDateFormat = `2006-01-02 15:04`
+
+// TaskExport is a single task rendered into a downloadable document
+type TaskExport struct {
+	Filename string
+	MimeType string
+	Content  []byte
+}
+
+// ExportTask renders a task with its checklist, comments and attachments into a document of the given format.
+// The caller needs to make sure the auth has read access to the task.
+func ExportTask(s *xorm.Session, taskID int64, a web.Auth, format TaskExportFormat) (export *TaskExport, err error) {
+	if format != TaskExportFormatMarkdown && format != TaskExportFormatPDF {
+		return nil, ErrInvalidTaskExportFormat{Format: string(format)}
+	}
+
+	task := &Task{ID: taskID}
+	err = task.ReadOne(s, a)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := GetProjectSimpleByID(s, task.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := []*TaskComment{}
+	if config.ServiceEnableTaskComments.GetBool() {
+		tc := &TaskComment{TaskID: task.ID, IncludeReplies: true}
+		result, _, _, err := tc.ReadAll(s, a, "", 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		comments = result.([]*TaskComment)
+	}
+
+	markdown := renderTaskMarkdown(task, project, comments)
+	filename := "task-" + strconv.FormatInt(task.ID, 10)
+
+	if format == TaskExportFormatPDF {
+		var font []byte
+		if path := config.ServicePDFExportFont.GetString(); path != "" {
+			font, err = os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		content, err := utils.RenderTextPDF(markdownToPDFLines(markdown), font)
+		if err != nil {
+			return nil, err
+		}
+
+		return &TaskExport{
+			Filename: filename + ".pdf",
+			MimeType: "application/pdf",
+			Content:  content,
+		}, nil
+	}
+
+	return &TaskExport{
+		Filename: filename + ".md",
+		MimeType: "text/markdown; charset=utf-8",
+		Content:  []byte(markdown),
+	}, nil
+}
+
+func renderTaskMarkdown(task *Task, project *Project, comments []*TaskComment) string {
+	md := &strings.Builder{}
+
+	title := task.Title
+	if task.Identifier != "" {
+		title = task.Identifier + " " + title
+	}
+	fmt.Fprintf(md, "# %s\n\n", title)
+
+	fmt.Fprintf(md, "- **Project:** %s\n", project.Title)
+	status := "Open"
+	if task.Done {
+		status = "Done"
+		if !task.DoneAt.IsZero() {
+			status += " (" + task.DoneAt.Format(taskExportDateFormat) + ")"
+		}
+	}
+	fmt.Fprintf(md, "- **Status:** %s\n", status)
+	if task.Priority > 0 {
+		fmt.Fprintf(md, "- **Priority:** %d\n", task.Priority)
+	}
+	if !task.StartDate.IsZero() {
+		fmt.Fprintf(md, "- **Start date:** %s\n", task.StartDate.Format(taskExportDateFormat))
+	}
+	if !task.DueDate.IsZero() {
+		fmt.Fprintf(md, "- **Due date:** %s\n", task.DueDate.Format(taskExportDateFormat))
+	}
+	if !task.EndDate.IsZero() {
+		fmt.Fprintf(md, "- **End date:** %s\n", task.EndDate.Format(taskExportDateFormat))
+	}
+	if len(task.Assignees) > 0 {
+		names := make([]string, 0, len(task.Assignees))
+		for _, u := range task.Assignees {
+			names = append(names, u.GetName())
+		}
+		fmt.Fprintf(md, "- **Assignees:** %s\n", strings.Join(names, ", "))
+	}
+	if len(task.Labels) > 0 {
+		labels := make([]string, 0, len(task.Labels))
+		for _, l := range task.Labels {
+			labels = append(labels, l.Title)
+		}
+		fmt.Fprintf(md, "- **Labels:** %s\n", strings.Join(labels, ", "))
+	}
+	done, total := countChecklistItems(task.Description)
+	if total > 0 {
+		fmt.Fprintf(md, "- **Checklist:** %d of %d done\n", done, total)
+	}
+
+	if description := htmlToMarkdown(task.Description); description != "" {
+		fmt.Fprintf(md, "\n## Description\n\n%s\n", description)
+	}
+
+	if len(comments) > 0 {
+		md.WriteString("\n## Comments\n")
+		for _, c := range comments {
+			author := "Unknown"
+			if c.Author != nil {
+				author = c.Author.GetName()
+			}
+			fmt.Fprintf(md, "\n### %s, %s\n\n%s\n", author, c.Created.Format(taskExportDateFormat), htmlToMarkdown(c.Comment))
+		}
+	}
+
+	if len(task.Attachments) > 0 {
+		md.WriteString("\n## Attachments\n\n")
+		for _, a := range task.Attachments {
+			if a.File == nil {
+				continue
+			}
+			fmt.Fprintf(md, "- %s (%s)\n", a.File.Name, datasize.ByteSize(a.File.Size).HumanReadable())
+		}
+	}
+
+	return md.String()
+}
+
+var (
+	checklistItemRegex     = regexp.MustCompile(`<li[^>]*data-checked="(true|false)"[^>]*>`)
+	htmlListItemRegex      = regexp.MustCompile(`<li[^>]*>`)
+	htmlHeadingRegex       = regexp.MustCompile(`<h([1-6])[^>]*>`)
+	htmlLineBreakRegex     = regexp.MustCompile(`<br\s*/?>|</(p|h[1-6]|li|ul|ol|div|blockquote|pre)>`)
+	htmlBoldRegex          = regexp.MustCompile(`</?(strong|b)>`)
+	htmlItalicRegex        = regexp.MustCompile(`</?(em|i)>`)
+	htmlCodeRegex          = regexp.MustCompile(`</?code>`)
+	htmlTagRegex           = regexp.MustCompile(`<[^>]+>`)
+	multipleNewlinesRegex  = regexp.MustCompile(`\n\s*\n+`)
+	separatedListItemRegex = regexp.MustCompile(`\n\n(- )`)
+)
+
+// countChecklistItems returns how many checklist items a task description has and how many of them are checked.
+func countChecklistItems(description string) (done, total int) {
+	for _, match := range checklistItemRegex.FindAllStringSubmatch(description, -1) {
+		total++
+		if match[1] == "true" {
+			done++
+		}
+	}
+	return
+}
+
+// htmlToMarkdown converts the html produced by the editor of the frontend into markdown.
+// It only knows about the elements the editor uses and drops everything else.
+func htmlToMarkdown(text string) string {
+	if !strings.Contains(text, "<") {
+		return strings.TrimSpace(text)
+	}
+
+	text = checklistItemRegex.ReplaceAllStringFunc(text, func(item string) string {
+		if strings.Contains(item, `data-checked="true"`) {
+			return "\n- [x] "
+		}
+		return "\n- [ ] "
+	})
+	text = htmlListItemRegex.ReplaceAllString(text, "\n- ")
+	text = htmlHeadingRegex.ReplaceAllStringFunc(text, func(heading string) string {
+		level, _ := strconv.Atoi(htmlHeadingRegex.FindStringSubmatch(heading)[1])
+		return "\n" + strings.Repeat("#", level) + " "
+	})
+	text = htmlLineBreakRegex.ReplaceAllString(text, "\n")
+	text = htmlBoldRegex.ReplaceAllString(text, "**")
+	text = htmlItalicRegex.ReplaceAllString(text, "_")
+	text = htmlCodeRegex.ReplaceAllString(text, "`")
+	text = htmlTagRegex.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = multipleNewlinesRegex.ReplaceAllString(text, "\n\n")
+	text = separatedListItemRegex.ReplaceAllString(text, "\n$1")
+
+	return strings.TrimSpace(text)
+}
+
+// markdownToPDFLines turns the markdown of an exported task into lines for the pdf, rendering headings in bold.
+func markdownToPDFLines(markdown string) []utils.PDFLine {
+	lines := []utils.PDFLine{}
+	for _, line := range strings.Split(markdown, "\n") {
+		heading := strings.HasPrefix(line, "#")
+		if heading {
+			line = strings.TrimLeft(line, "# ")
+		}
+		line = strings.ReplaceAll(line, "**", "")
+		lines = append(lines, utils.PDFLine{Text: line, Bold: heading})
+	}
+	return lines
+}
@@ -0,0 +1,83 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"bytes"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportTask(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("markdown", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		export, err := ExportTask(s, 1, u, TaskExportFormatMarkdown)
+		require.NoError(t, err)
+		assert.Equal(t, "task-1.md", export.Filename)
+		md := string(export.Content)
+		assert.Contains(t, md, "# test1-1 task #1\n")
+		assert.Contains(t, md, "- **Project:** Test1\n")
+		assert.Contains(t, md, "## Description\n\nLorem Ipsum\n")
+		assert.Contains(t, md, "## Comments\n")
+		assert.Contains(t, md, "Lorem Ipsum Dolor Sit Amet")
+		assert.Contains(t, md, "## Attachments\n")
+	})
+	t.Run("pdf", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		export, err := ExportTask(s, 1, u, TaskExportFormatPDF)
+		require.NoError(t, err)
+		assert.Equal(t, "task-1.pdf", export.Filename)
+		assert.Equal(t, "application/pdf", export.MimeType)
+		assert.True(t, bytes.HasPrefix(export.Content, []byte("%PDF")))
+	})
+	t.Run("invalid format", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := ExportTask(s, 1, u, "docx")
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskExportFormat(err))
+	})
+}
+
+func TestHtmlToMarkdown(t *testing.T) {
+	description := `<p>Some <strong>bold</strong> text &amp; more</p>` +
+		`<ul data-type="taskList">` +
+		`<li data-checked="true" data-type="taskItem"><label><input type="checkbox" checked="checked"><span></span></label><div><p>First</p></div></li>` +
+		`<li data-checked="false" data-type="taskItem"><label><input type="checkbox"><span></span></label><div><p>Second</p></div></li>` +
+		`</ul>`
+
+	assert.Equal(t, "Some **bold** text & more\n- [x] First\n- [ ] Second", htmlToMarkdown(description))
+
+	done, total := countChecklistItems(description)
+	assert.Equal(t, 1, done)
+	assert.Equal(t, 2, total)
+}
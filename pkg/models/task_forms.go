@@ -0,0 +1,458 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/api/pkg/utils"
+
+	"code.vikunja.io/web"
+	"github.com/microcosm-cc/bluemonday"
+	"xorm.io/xorm"
+)
+
+// All task fields which can be enabled on a task form in addition to the title.
+const (
+	TaskFormFieldDescription = "description"
+	TaskFormFieldDueDate     = "due_date"
+	TaskFormFieldStartDate   = "start_date"
+	TaskFormFieldEndDate     = "end_date"
+	TaskFormFieldPriority    = "priority"
+)
+
+var availableTaskFormFields = map[string]bool{
+	TaskFormFieldDescription: true,
+	TaskFormFieldDueDate:     true,
+	TaskFormFieldStartDate:   true,
+	TaskFormFieldEndDate:     true,
+	TaskFormFieldPriority:    true,
+}
+
+// TaskForm is a public form which allows unauthenticated users to create tasks in a project.
+type TaskForm struct {
+	// The unique, numeric id of this form.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"form"`
+	// The public token of this form. Submissions are made against this token.
+	Hash string `xorm:"varchar(40) not null unique" json:"hash" param:"hash"`
+	// The project all submitted tasks will be created in.
+	ProjectID int64 `xorm:"bigint not null INDEX" json:"project_id" param:"project"`
+	// The title of the form, shown to everyone filling it out.
+	Title string `xorm:"varchar(250) not null" json:"title" valid:"required,runelength(1|250)" minLength:"1" maxLength:"250"`
+	// A description shown above the form.
+	Description string `xorm:"longtext null" json:"description"`
+	// The task fields submitters are allowed to fill out besides the title. Can contain `description`, `due_date`, `start_date`, `end_date` and `priority`.
+	AllowedFields []string `xorm:"JSON null" json:"allowed_fields"`
+	// If true, every submission needs to pass a captcha check. Only available if a captcha secret is configured.
+	RequireCaptcha bool `xorm:"not null default false" json:"require_captcha"`
+	// Disabled forms do not accept any submissions.
+	Enabled bool `xorm:"not null default true" json:"enabled"`
+
+	// The user who created this form. All tasks submitted through the form will be created in the name of this user.
+	CreatedBy   *user.User `xorm:"-" json:"created_by" valid:"-"`
+	CreatedByID int64      `xorm:"bigint not null" json:"-"`
+
+	// A timestamp when this form was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this form was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for task forms
+func (*TaskForm) TableName() string {
+	return "task_forms"
+}
+
+func (tf *TaskForm) validate() error {
+	for _, field := range tf.AllowedFields {
+		if !availableTaskFormFields[field] {
+			return InvalidFieldError([]string{"allowed_fields"})
+		}
+	}
+
+	if tf.RequireCaptcha && config.TaskFormsCaptchaSecret.GetString() == "" {
+		return &ErrTaskFormCaptchaNotConfigured{}
+	}
+
+	return nil
+}
+
+func (tf *TaskForm) allowsField(field string) bool {
+	for _, f := range tf.AllowedFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Create creates a new task form
+// @Summary Create a task form
+// @Description Creates a new public task form for a project. Everyone with the form's hash will be able to create tasks in the project through it.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param form body models.TaskForm true "The task form"
+// @Success 201 {object} models.TaskForm "The created task form."
+// @Failure 400 {object} web.HTTPError "Invalid task form object provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/forms [put]
+func (tf *TaskForm) Create(s *xorm.Session, a web.Auth) (err error) {
+	err = tf.validate()
+	if err != nil {
+		return
+	}
+
+	tf.ID = 0
+	tf.Enabled = true
+	tf.Hash = utils.MakeRandomString(40)
+	tf.CreatedByID = a.GetID()
+
+	_, err = s.Insert(tf)
+	if err != nil {
+		return
+	}
+
+	tf.CreatedBy, err = user.GetUserByID(s, tf.CreatedByID)
+	return
+}
+
+// ReadOne returns one task form
+// @Summary Get one task form
+// @Description Returns one task form of a project.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param form path int true "Form ID"
+// @Success 200 {object} models.TaskForm "The task form."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The task form does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/forms/{form} [get]
+func (tf *TaskForm) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	form, err := getTaskFormByID(s, tf.ID)
+	if err != nil {
+		return err
+	}
+
+	*tf = *form
+	tf.CreatedBy, err = user.GetUserByID(s, tf.CreatedByID)
+	return
+}
+
+// ReadAll returns all task forms of a project
+// @Summary Get all task forms of a project
+// @Description Returns all task forms of a project.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Success 200 {array} models.TaskForm "The task forms."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/forms [get]
+func (tf *TaskForm) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, totalItems int64, err error) {
+	p := &Project{ID: tf.ProjectID}
+	can, err := p.CanUpdate(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	forms := []*TaskForm{}
+	err = s.Where("project_id = ?", tf.ProjectID).
+		Limit(getLimitFromPageIndex(page, perPage)).
+		OrderBy("id asc").
+		Find(&forms)
+	if err != nil {
+		return
+	}
+
+	totalItems, err = s.Where("project_id = ?", tf.ProjectID).
+		Count(&TaskForm{})
+	if err != nil {
+		return
+	}
+
+	userIDs := []int64{}
+	for _, form := range forms {
+		userIDs = append(userIDs, form.CreatedByID)
+	}
+
+	users, err := user.GetUsersByIDs(s, userIDs)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	for _, form := range forms {
+		form.CreatedBy = users[form.CreatedByID]
+	}
+
+	return forms, len(forms), totalItems, nil
+}
+
+// Update updates a task form
+// @Summary Update a task form
+// @Description Updates a task form. The hash and project of a form cannot be changed.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param form path int true "Form ID"
+// @Param form body models.TaskForm true "The task form"
+// @Success 200 {object} models.TaskForm "The updated task form."
+// @Failure 400 {object} web.HTTPError "Invalid task form object provided."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The task form does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/forms/{form} [post]
+func (tf *TaskForm) Update(s *xorm.Session, a web.Auth) (err error) {
+	err = tf.validate()
+	if err != nil {
+		return
+	}
+
+	_, err = s.
+		Where("id = ?", tf.ID).
+		Cols(
+			"title",
+			"description",
+			"allowed_fields",
+			"require_captcha",
+			"enabled",
+		).
+		Update(tf)
+	if err != nil {
+		return
+	}
+
+	return tf.ReadOne(s, a)
+}
+
+// Delete removes a task form
+// @Summary Delete a task form
+// @Description Deletes a task form. Tasks which were already submitted through the form are not affected.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param form path int true "Form ID"
+// @Success 200 {object} models.Message "The task form was successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The task form does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/forms/{form} [delete]
+func (tf *TaskForm) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.Where("id = ?", tf.ID).Delete(&TaskForm{})
+	return
+}
+
+func getTaskFormByID(s *xorm.Session, id int64) (form *TaskForm, err error) {
+	form = &TaskForm{}
+	exists, err := s.Where("id = ?", id).Get(form)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrTaskFormDoesNotExist{ID: id}
+	}
+	return
+}
+
+// GetTaskFormByHash returns a task form by its public hash
+func GetTaskFormByHash(s *xorm.Session, hash string) (form *TaskForm, err error) {
+	form = &TaskForm{}
+	exists, err := s.Where("hash = ?", hash).Get(form)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrTaskFormDoesNotExist{Hash: hash}
+	}
+	return
+}
+
+// PublicTaskForm is the representation of a task form shown to unauthenticated users.
+type PublicTaskForm struct {
+	Title          string   `json:"title"`
+	Description    string   `json:"description"`
+	AllowedFields  []string `json:"allowed_fields"`
+	RequireCaptcha bool     `json:"require_captcha"`
+	// The site key to use for rendering the captcha widget. Only set if the form requires a captcha.
+	CaptchaSiteKey string `json:"captcha_site_key,omitempty"`
+}
+
+// ToPublic returns the parts of a form which are safe to show to everyone who knows its hash.
+func (tf *TaskForm) ToPublic() *PublicTaskForm {
+	pf := &PublicTaskForm{
+		Title:          tf.Title,
+		Description:    tf.Description,
+		AllowedFields:  tf.AllowedFields,
+		RequireCaptcha: tf.RequireCaptcha,
+	}
+	if tf.RequireCaptcha {
+		pf.CaptchaSiteKey = config.TaskFormsCaptchaSiteKey.GetString()
+	}
+	return pf
+}
+
+// TaskFormSubmission holds everything an unauthenticated user can submit through a task form.
+type TaskFormSubmission struct {
+	Hash string `param:"hash" json:"-"`
+
+	// The title of the new task.
+	Title string `json:"title" valid:"required,runelength(1|250)" minLength:"1" maxLength:"250"`
+	// The task description. Only used if the form allows it.
+	Description string `json:"description"`
+	// The due date. Only used if the form allows it.
+	DueDate time.Time `json:"due_date"`
+	// The start date. Only used if the form allows it.
+	StartDate time.Time `json:"start_date"`
+	// The end date. Only used if the form allows it.
+	EndDate time.Time `json:"end_date"`
+	// The task priority. Only used if the form allows it.
+	Priority int64 `json:"priority"`
+
+	// The response token of the captcha widget. Required if the form requires a captcha.
+	CaptchaToken string `json:"captcha_token"`
+}
+
+type captchaVerifyResponse struct {
+	Success bool `json:"success"`
+}
+
+var verifyTaskFormCaptcha = func(token, remoteIP string) (bool, error) {
+	values := url.Values{}
+	values.Set("secret", config.TaskFormsCaptchaSecret.GetString())
+	values.Set("response", token)
+	if remoteIP != "" {
+		values.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(
+		context.Background(),
+		http.MethodPost,
+		config.TaskFormsCaptchaVerifyURL.GetString(),
+		strings.NewReader(values.Encode()),
+	)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	result := &captchaVerifyResponse{}
+	err = json.NewDecoder(res.Body).Decode(result)
+	if err != nil {
+		return false, err
+	}
+
+	return result.Success, nil
+}
+
+// SubmitTaskForm creates a new task from a form submission. Only fields allowed by the form are taken over
+// into the new task, everything else is silently ignored.
+func SubmitTaskForm(s *xorm.Session, submission *TaskFormSubmission, remoteIP string) (task *Task, err error) {
+	form, err := GetTaskFormByHash(s, submission.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if !form.Enabled {
+		return nil, &ErrTaskFormDoesNotExist{Hash: submission.Hash}
+	}
+
+	if form.RequireCaptcha {
+		if submission.CaptchaToken == "" {
+			return nil, &ErrTaskFormCaptchaInvalid{FormID: form.ID}
+		}
+
+		valid, err := verifyTaskFormCaptcha(submission.CaptchaToken, remoteIP)
+		if err != nil {
+			log.Errorf("Could not verify captcha for task form %d: %s", form.ID, err)
+			return nil, &ErrTaskFormCaptchaInvalid{FormID: form.ID}
+		}
+		if !valid {
+			return nil, &ErrTaskFormCaptchaInvalid{FormID: form.ID}
+		}
+	}
+
+	creator, err := user.GetUserByID(s, form.CreatedByID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tasks are created in the name of whoever set up the form, they might have lost access since then
+	canWrite, err := (&Project{ID: form.ProjectID}).CanWrite(s, creator)
+	if err != nil {
+		return nil, err
+	}
+	if !canWrite {
+		log.Debugf("Ignoring submission of task form %d, its creator %d can no longer write to project %d", form.ID, creator.ID, form.ProjectID)
+		return nil, &ErrTaskFormDoesNotExist{Hash: submission.Hash}
+	}
+
+	task = &Task{
+		Title:     submission.Title,
+		ProjectID: form.ProjectID,
+	}
+	if form.allowsField(TaskFormFieldDescription) {
+		// Submissions come from anonymous users, their html can't be trusted
+		task.Description = bluemonday.UGCPolicy().Sanitize(submission.Description)
+	}
+	if form.allowsField(TaskFormFieldDueDate) {
+		task.DueDate = submission.DueDate
+	}
+	if form.allowsField(TaskFormFieldStartDate) {
+		task.StartDate = submission.StartDate
+	}
+	if form.allowsField(TaskFormFieldEndDate) {
+		task.EndDate = submission.EndDate
+	}
+	if form.allowsField(TaskFormFieldPriority) {
+		task.Priority = submission.Priority
+	}
+
+	err = createTask(s, task, creator, false, true, true)
+	return
+}
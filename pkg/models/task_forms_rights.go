@@ -0,0 +1,66 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if a user can see a task form with all of its details
+func (tf *TaskForm) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	can, err := tf.canDoTaskForm(s, a)
+	if err != nil || !can {
+		return false, 0, err
+	}
+	return true, int(RightAdmin), nil
+}
+
+// CanCreate checks if a user can create a new task form for a project
+func (tf *TaskForm) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	return tf.canDoTaskForm(s, a)
+}
+
+// CanUpdate checks if a user can update a task form
+func (tf *TaskForm) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return tf.canDoTaskForm(s, a)
+}
+
+// CanDelete checks if a user can delete a task form
+func (tf *TaskForm) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return tf.canDoTaskForm(s, a)
+}
+
+func (tf *TaskForm) canDoTaskForm(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	// Make sure the form actually belongs to the project from the url
+	if tf.ID != 0 {
+		form, err := getTaskFormByID(s, tf.ID)
+		if err != nil {
+			return false, err
+		}
+		if form.ProjectID != tf.ProjectID {
+			return false, &ErrTaskFormDoesNotExist{ID: tf.ID}
+		}
+	}
+
+	p := &Project{ID: tf.ProjectID}
+	return p.CanUpdate(s, a)
+}
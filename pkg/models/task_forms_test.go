@@ -0,0 +1,279 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskForm_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		form := &TaskForm{
+			ProjectID:     1,
+			Title:         "Bug reports",
+			AllowedFields: []string{TaskFormFieldDescription},
+		}
+		err := form.Create(s, u)
+		require.NoError(t, err)
+		assert.Len(t, form.Hash, 40)
+		assert.True(t, form.Enabled)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_forms", map[string]interface{}{
+			"id":            form.ID,
+			"project_id":    1,
+			"title":         "Bug reports",
+			"created_by_id": 1,
+		}, false)
+	})
+	t.Run("invalid field", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		form := &TaskForm{
+			ProjectID:     1,
+			Title:         "Bug reports",
+			AllowedFields: []string{"assignees"},
+		}
+		err := form.Create(s, u)
+		require.Error(t, err)
+	})
+	t.Run("captcha not configured", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		form := &TaskForm{
+			ProjectID:      1,
+			Title:          "Bug reports",
+			RequireCaptcha: true,
+		}
+		err := form.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskFormCaptchaNotConfigured(err))
+	})
+	t.Run("no project access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		form := &TaskForm{
+			ProjectID: 2,
+			Title:     "Bug reports",
+		}
+		can, err := form.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestSubmitTaskForm(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		form := &TaskForm{
+			ProjectID:     1,
+			Title:         "Bug reports",
+			AllowedFields: []string{TaskFormFieldDescription},
+		}
+		err := form.Create(s, u)
+		require.NoError(t, err)
+
+		task, err := SubmitTaskForm(s, &TaskFormSubmission{
+			Hash:        form.Hash,
+			Title:       "Something is broken",
+			Description: "Lorem Ipsum",
+			DueDate:     time.Now(),
+			Priority:    5,
+		}, "")
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1), task.ProjectID)
+		assert.Equal(t, int64(1), task.CreatedByID)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":          task.ID,
+			"title":       "Something is broken",
+			"description": "Lorem Ipsum",
+			"priority":    0,
+		}, false)
+	})
+	t.Run("description is sanitized", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		form := &TaskForm{
+			ProjectID:     1,
+			Title:         "Bug reports",
+			AllowedFields: []string{TaskFormFieldDescription},
+		}
+		err := form.Create(s, u)
+		require.NoError(t, err)
+
+		task, err := SubmitTaskForm(s, &TaskFormSubmission{
+			Hash:        form.Hash,
+			Title:       "Something is broken",
+			Description: `<p onclick="alert(1)">Lorem Ipsum</p><script>alert(1)</script>`,
+		}, "")
+		require.NoError(t, err)
+		assert.Equal(t, "<p>Lorem Ipsum</p>", task.Description)
+	})
+	t.Run("creator lost access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		form := &TaskForm{
+			ProjectID: 1,
+			Title:     "Bug reports",
+		}
+		err := form.Create(s, u)
+		require.NoError(t, err)
+		// User 2 does not have access to project 1
+		_, err = s.Where("id = ?", form.ID).Cols("created_by_id").Update(&TaskForm{CreatedByID: 2})
+		require.NoError(t, err)
+
+		_, err = SubmitTaskForm(s, &TaskFormSubmission{
+			Hash:  form.Hash,
+			Title: "Something is broken",
+		}, "")
+		require.Error(t, err)
+		assert.True(t, IsErrTaskFormDoesNotExist(err))
+	})
+	t.Run("archived project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		form := &TaskForm{
+			ProjectID: 1,
+			Title:     "Bug reports",
+		}
+		err := form.Create(s, u)
+		require.NoError(t, err)
+		_, err = s.Where("id = ?", 1).Cols("is_archived").Update(&Project{IsArchived: true})
+		require.NoError(t, err)
+
+		_, err = SubmitTaskForm(s, &TaskFormSubmission{
+			Hash:  form.Hash,
+			Title: "Something is broken",
+		}, "")
+		require.Error(t, err)
+		assert.True(t, IsErrProjectIsArchived(err))
+	})
+	t.Run("disabled form", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		form := &TaskForm{
+			ProjectID: 1,
+			Title:     "Bug reports",
+		}
+		err := form.Create(s, u)
+		require.NoError(t, err)
+		form.Enabled = false
+		err = form.Update(s, u)
+		require.NoError(t, err)
+
+		_, err = SubmitTaskForm(s, &TaskFormSubmission{
+			Hash:  form.Hash,
+			Title: "Something is broken",
+		}, "")
+		require.Error(t, err)
+		assert.True(t, IsErrTaskFormDoesNotExist(err))
+	})
+	t.Run("nonexisting form", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := SubmitTaskForm(s, &TaskFormSubmission{
+			Hash:  "nonexisting",
+			Title: "Something is broken",
+		}, "")
+		require.Error(t, err)
+		assert.True(t, IsErrTaskFormDoesNotExist(err))
+	})
+	t.Run("captcha", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		config.TaskFormsCaptchaSecret.Set("secret")
+		defer config.TaskFormsCaptchaSecret.Set("")
+		originalVerify := verifyTaskFormCaptcha
+		defer func() {
+			verifyTaskFormCaptcha = originalVerify
+		}()
+		verifyTaskFormCaptcha = func(token, _ string) (bool, error) {
+			return token == "valid", nil
+		}
+
+		form := &TaskForm{
+			ProjectID:      1,
+			Title:          "Bug reports",
+			RequireCaptcha: true,
+		}
+		err := form.Create(s, u)
+		require.NoError(t, err)
+
+		_, err = SubmitTaskForm(s, &TaskFormSubmission{
+			Hash:  form.Hash,
+			Title: "Something is broken",
+		}, "")
+		require.Error(t, err)
+		assert.True(t, IsErrTaskFormCaptchaInvalid(err))
+
+		_, err = SubmitTaskForm(s, &TaskFormSubmission{
+			Hash:         form.Hash,
+			Title:        "Something is broken",
+			CaptchaToken: "invalid",
+		}, "")
+		require.Error(t, err)
+		assert.True(t, IsErrTaskFormCaptchaInvalid(err))
+
+		_, err = SubmitTaskForm(s, &TaskFormSubmission{
+			Hash:         form.Hash,
+			Title:        "Something is broken",
+			CaptchaToken: "valid",
+		}, "")
+		require.NoError(t, err)
+	})
+}
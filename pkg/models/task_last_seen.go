@@ -0,0 +1,162 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// TaskLastSeen holds when a user last opened a task
+type TaskLastSeen struct {
+	ID       int64     `xorm:"bigint autoincr not null unique pk"`
+	UserID   int64     `xorm:"bigint not null unique(user_task)"`
+	TaskID   int64     `xorm:"bigint not null unique(user_task) INDEX"`
+	LastSeen time.Time `xorm:"datetime not null"`
+}
+
+// TableName returns the table name for task last seen timestamps
+func (*TaskLastSeen) TableName() string {
+	return "task_last_seen"
+}
+
+// ProjectTasksRead marks all tasks in a project as read
+type ProjectTasksRead struct {
+	ProjectID int64 `json:"-" param:"project"`
+
+	web.CRUDable `json:"-"`
+	web.Rights   `json:"-"`
+}
+
+// The number of tasks marked as seen with one query, to stay below the parameter limits of the databases.
+const taskLastSeenBatchSize = 200
+
+// MarkTaskAsSeen remembers that the user opened the task.
+func MarkTaskAsSeen(s *xorm.Session, a web.Auth, taskID int64) error {
+	return markTasksAsSeen(s, a, []int64{taskID})
+}
+
+// markTasksAsSeen remembers that the user has seen the current state of the tasks.
+func markTasksAsSeen(s *xorm.Session, a web.Auth, taskIDs []int64) (err error) {
+	u, err := user.GetFromAuth(a)
+	if err != nil || len(taskIDs) == 0 {
+		// Link shares don't have a read state
+		return nil
+	}
+
+	now := time.Now()
+	for start := 0; start < len(taskIDs); start += taskLastSeenBatchSize {
+		end := start + taskLastSeenBatchSize
+		if end > len(taskIDs) {
+			end = len(taskIDs)
+		}
+		batch := taskIDs[start:end]
+
+		_, err = s.
+			Where("user_id = ?", u.ID).
+			In("task_id", batch).
+			Delete(&TaskLastSeen{})
+		if err != nil {
+			return err
+		}
+
+		seen := make([]*TaskLastSeen, 0, len(batch))
+		for _, id := range batch {
+			seen = append(seen, &TaskLastSeen{
+				UserID:   u.ID,
+				TaskID:   id,
+				LastSeen: now,
+			})
+		}
+
+		_, err = s.Insert(&seen)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getUnreadTasks returns which tasks changed since the user opened them last.
+// Tasks the user never opened are not considered unread since there is nothing they could have missed.
+func getUnreadTasks(s *xorm.Session, taskMap map[int64]*Task, taskIDs []int64, a web.Auth) (unread map[int64]bool, err error) {
+	unread = make(map[int64]bool)
+	u, err := user.GetFromAuth(a)
+	if err != nil {
+		return unread, nil
+	}
+
+	seen := []*TaskLastSeen{}
+	err = s.Where(builder.And(
+		builder.Eq{"user_id": u.ID},
+		builder.In("task_id", taskIDs),
+	)).
+		Find(&seen)
+	if err != nil {
+		return
+	}
+
+	for _, ls := range seen {
+		task, has := taskMap[ls.TaskID]
+		if has && task.Updated.After(ls.LastSeen) {
+			unread[ls.TaskID] = true
+		}
+	}
+	return
+}
+
+// CanCreate checks if a user can mark the tasks of a project as read
+func (ptr *ProjectTasksRead) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	p := &Project{ID: ptr.ProjectID}
+	can, _, err := p.CanRead(s, a)
+	return can, err
+}
+
+// Create marks all tasks in a project as read
+// @Summary Mark all tasks of a project as read
+// @Description Marks all tasks in the project as read for the current user, as if they had opened every one of them.
+// @tags task
+// @Produce json
+// @Security JWTKeyAuth
+// @Param id path int true "Project ID"
+// @Success 201 {object} models.Message "The tasks were marked as read."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project"
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/tasks/read [post]
+func (ptr *ProjectTasksRead) Create(s *xorm.Session, a web.Auth) (err error) {
+	taskIDs := []int64{}
+	err = s.
+		Table("tasks").
+		Where("project_id = ?", ptr.ProjectID).
+		Cols("id").
+		Find(&taskIDs)
+	if err != nil {
+		return err
+	}
+
+	return markTasksAsSeen(s, a, taskIDs)
+}
@@ -0,0 +1,121 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/builder"
+)
+
+func TestTaskReadState(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("never opened is not unread", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task, err := GetTaskByIDSimple(s, 1)
+		require.NoError(t, err)
+		unread, err := getUnreadTasks(s, map[int64]*Task{1: &task}, []int64{1}, u)
+		require.NoError(t, err)
+		assert.False(t, unread[1])
+	})
+	t.Run("opening marks as read", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := MarkTaskAsSeen(s, u, 1)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		db.AssertExists(t, "task_last_seen", map[string]interface{}{
+			"user_id": 1,
+			"task_id": 1,
+		}, false)
+	})
+	t.Run("reading a task does not mark it as read", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		task := &Task{ID: 1}
+		err := task.ReadOne(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		db.AssertMissing(t, "task_last_seen", map[string]interface{}{
+			"user_id": 1,
+			"task_id": 1,
+		})
+	})
+	t.Run("changed after opening", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Insert(&TaskLastSeen{UserID: 1, TaskID: 1, LastSeen: time.Date(2018, 11, 1, 0, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+
+		task, err := GetTaskByIDSimple(s, 1)
+		require.NoError(t, err)
+		unread, err := getUnreadTasks(s, map[int64]*Task{1: &task}, []int64{1}, u)
+		require.NoError(t, err)
+		assert.True(t, unread[1])
+	})
+	t.Run("mark project as read", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Insert(&TaskLastSeen{UserID: 1, TaskID: 1, LastSeen: time.Date(2018, 11, 1, 0, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+
+		ptr := &ProjectTasksRead{ProjectID: 1}
+		can, err := ptr.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = ptr.Create(s, u)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		total, err := s.Where("project_id = ?", 1).Count(&Task{})
+		require.NoError(t, err)
+		db.AssertCount(t, "task_last_seen", builder.Eq{"user_id": 1}, total)
+
+		task, err := GetTaskByIDSimple(s, 1)
+		require.NoError(t, err)
+		unread, err := getUnreadTasks(s, map[int64]*Task{1: &task}, []int64{1}, u)
+		require.NoError(t, err)
+		assert.False(t, unread[1])
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		can, err := (&ProjectTasksRead{ProjectID: 1}).CanCreate(s, &LinkSharing{ID: 1, ProjectID: 1})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
@@ -34,6 +34,7 @@ import (
 func getUndoneOverdueTasks(s *xorm.Session, now time.Time) (usersWithTasks map[int64]*userWithTasks, err error) {
 	now = utils.GetTimeWithoutSeconds(now)
 	nextMinute := now.Add(1 * time.Minute)
+	threshold := time.Duration(config.ServiceOverdueTasksThreshold.GetInt64()) * time.Hour
 
 	var tasks []*Task
 	err = s.
@@ -87,7 +88,7 @@ func getUndoneOverdueTasks(s *xorm.Session, now time.Time) (usersWithTasks map[i
 		overdueMailTime := time.Date(now.Year(), now.Month(), now.Day(), tm.Hour(), tm.Minute(), 0, 0, tz)
 		isTimeForReminder := overdueMailTime.After(now) || overdueMailTime.Equal(now.In(tz))
 		wasTimeForReminder := overdueMailTime.Before(nextMinute)
-		taskIsOverdueInUserTimezone := overdueMailTime.After(t.Task.DueDate.In(tz))
+		taskIsOverdueInUserTimezone := overdueMailTime.Add(-threshold).After(t.Task.DueDate.In(tz))
 		if isTimeForReminder && wasTimeForReminder && taskIsOverdueInUserTimezone {
 			_, exists := uts[t.User.ID]
 			if !exists {
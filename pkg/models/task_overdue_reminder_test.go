@@ -20,6 +20,7 @@ import (
 	"testing"
 	"time"
 
+	"code.vikunja.io/api/pkg/config"
 	"code.vikunja.io/api/pkg/db"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -62,6 +63,23 @@ func TestGetUndoneOverDueTasks(t *testing.T) {
 		assert.Truef(t, task5Present, "expected task 5 to be present but was not")
 		assert.Truef(t, task6Present, "expected task 6 to be present but was not")
 	})
+	t.Run("undone overdue with threshold", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		config.ServiceOverdueTasksThreshold.Set(10)
+		defer config.ServiceOverdueTasksThreshold.Set(0)
+
+		now, err := time.Parse(time.RFC3339Nano, "2018-12-01T09:00:00Z")
+		require.NoError(t, err)
+		uts, err := getUndoneOverdueTasks(s, now)
+		require.NoError(t, err)
+		assert.Len(t, uts, 1)
+		// Task 5 is only overdue for 5 hours
+		assert.Len(t, uts[1].tasks, 1)
+		assert.NotNil(t, uts[1].tasks[6])
+	})
 	t.Run("done overdue", func(t *testing.T) {
 		db.LoadAndAssertFixtures(t)
 		s := db.NewSession()
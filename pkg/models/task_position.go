@@ -19,6 +19,10 @@ package models
 import (
 	"math"
 
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/user"
 	"code.vikunja.io/web"
 	"xorm.io/xorm"
 )
@@ -51,9 +55,13 @@ func (tp *TaskPosition) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
 	return t.CanUpdate(s, a)
 }
 
+// minTaskPositionSpacing is the smallest distance two task positions may have before the positions of the view are
+// rebalanced.
+const minTaskPositionSpacing = 0.01
+
 // Update is the handler to update a task position
 // @Summary Updates a task position
-// @Description Updates a task position.
+// @Description Updates a task position. Only the position of this task is changed. When positions in the view get too close to each other, all of them are spread out again in the background shortly after.
 // @tags task
 // @Accept json
 // @Produce json
@@ -64,19 +72,7 @@ func (tp *TaskPosition) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
 // @Failure 400 {object} web.HTTPError "Invalid task position object provided."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /tasks/{id}/position [post]
-func (tp *TaskPosition) Update(s *xorm.Session, a web.Auth) (err error) {
-
-	// Update all positions if the newly saved position is < 0.1
-	var shouldRecalculate bool
-	var view *ProjectView
-	if tp.Position < 0.1 {
-		shouldRecalculate = true
-		view, err = GetProjectViewByID(s, tp.ProjectViewID)
-		if err != nil {
-			return err
-		}
-	}
-
+func (tp *TaskPosition) Update(s *xorm.Session, _ web.Auth) (err error) {
 	exists, err := s.
 		Where("task_id = ? AND project_view_id = ?", tp.TaskID, tp.ProjectViewID).
 		Exist(&TaskPosition{})
@@ -84,30 +80,42 @@ func (tp *TaskPosition) Update(s *xorm.Session, a web.Auth) (err error) {
 		return err
 	}
 
-	if !exists {
+	if exists {
+		_, err = s.
+			Where("task_id = ? AND project_view_id = ?", tp.TaskID, tp.ProjectViewID).
+			Cols("position").
+			Update(tp)
+	} else {
 		_, err = s.Insert(tp)
-		if err != nil {
-			return
-		}
-		if shouldRecalculate {
-			return RecalculateTaskPositions(s, view, a)
-		}
-		return nil
 	}
-
-	_, err = s.
-		Where("task_id = ?", tp.TaskID).
-		Cols("project_view_id", "position").
-		Update(tp)
 	if err != nil {
 		return
 	}
 
-	if shouldRecalculate {
-		return RecalculateTaskPositions(s, view, a)
+	return tp.markViewForRebalancingIfNeeded(s)
+}
+
+// markViewForRebalancingIfNeeded flags the view of the position for rebalancing if the position is too close to
+// zero or to the position of another task, so that there is room to put tasks in between again.
+func (tp *TaskPosition) markViewForRebalancingIfNeeded(s *xorm.Session) error {
+	needsRebalancing := tp.Position < 0.1
+	if !needsRebalancing {
+		var err error
+		needsRebalancing, err = s.
+			Where("project_view_id = ? AND task_id != ? AND position > ? AND position < ?",
+				tp.ProjectViewID, tp.TaskID, tp.Position-minTaskPositionSpacing, tp.Position+minTaskPositionSpacing).
+			Exist(&TaskPosition{})
+		if err != nil || !needsRebalancing {
+			return err
+		}
 	}
 
-	return
+	_, err := s.
+		Where("id = ? AND needs_rebalancing = ?", tp.ProjectViewID, false).
+		Cols("needs_rebalancing").
+		NoAutoTime().
+		Update(&ProjectView{NeedsRebalancing: true})
+	return err
 }
 
 func RecalculateTaskPositions(s *xorm.Session, view *ProjectView, a web.Auth) (err error) {
@@ -165,9 +173,83 @@ func RecalculateTaskPositions(s *xorm.Session, view *ProjectView, a web.Auth) (e
 	}
 
 	_, err = s.Insert(newPositions)
+	if err != nil {
+		return
+	}
+
+	_, err = s.
+		Where("id = ?", view.ID).
+		Cols("needs_rebalancing").
+		NoAutoTime().
+		Update(&ProjectView{NeedsRebalancing: false})
+	return
+}
+
+// rebalanceTaskPositions spreads out the task positions of all views flagged for it. Since this does not happen
+// in the context of a request, the positions are recalculated with the rights of the owner of the project or
+// saved filter.
+func rebalanceTaskPositions(s *xorm.Session) (rebalanced int, err error) {
+	views := []*ProjectView{}
+	err = s.Where("needs_rebalancing = ?", true).Find(&views)
+	if err != nil {
+		return
+	}
+
+	for _, view := range views {
+		var ownerID int64
+		filterID := getSavedFilterIDFromProjectID(view.ProjectID)
+		if filterID > 0 {
+			sf, err := getSavedFilterSimpleByID(s, filterID)
+			if err != nil {
+				return rebalanced, err
+			}
+			ownerID = sf.OwnerID
+		} else {
+			project, err := GetProjectSimpleByID(s, view.ProjectID)
+			if err != nil {
+				return rebalanced, err
+			}
+			ownerID = project.OwnerID
+		}
+
+		err = RecalculateTaskPositions(s, view, &user.User{ID: ownerID})
+		if err != nil {
+			return rebalanced, err
+		}
+		rebalanced++
+	}
+
 	return
 }
 
+// RegisterTaskPositionRebalancingCron registers a cron function which rebalances the task positions of views where
+// tasks were moved so close to each other that no task fits in between anymore.
+func RegisterTaskPositionRebalancingCron() {
+	err := cron.Schedule("* * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		rebalanced, err := rebalanceTaskPositions(s)
+		if err != nil {
+			log.Errorf("[Task Position Rebalancing Cron] Could not rebalance task positions: %s", err)
+			_ = s.Rollback()
+			return
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf("[Task Position Rebalancing Cron] Could not commit rebalanced task positions: %s", err)
+			return
+		}
+
+		if rebalanced > 0 {
+			log.Debugf("[Task Position Rebalancing Cron] Rebalanced the task positions of %d views", rebalanced)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register task position rebalancing cron: %s", err)
+	}
+}
+
 func getPositionsForView(s *xorm.Session, view *ProjectView) (positions []*TaskPosition, err error) {
 	positions = []*TaskPosition{}
 	err = s.
@@ -0,0 +1,91 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/builder"
+)
+
+func TestTaskPosition_Update(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("only updates the one position", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Insert(&TaskPosition{TaskID: 1, ProjectViewID: 2, Position: 10})
+		require.NoError(t, err)
+
+		tp := &TaskPosition{TaskID: 1, ProjectViewID: 1, Position: 3}
+		err = tp.Update(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_positions", map[string]interface{}{
+			"task_id":         1,
+			"project_view_id": 1,
+			"position":        3,
+		}, false)
+		db.AssertExists(t, "task_positions", map[string]interface{}{
+			"task_id":         1,
+			"project_view_id": 2,
+			"position":        10,
+		}, false)
+		db.AssertExists(t, "project_views", map[string]interface{}{
+			"id":                1,
+			"needs_rebalancing": false,
+		}, false)
+	})
+	t.Run("rebalances when positions get too close", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tp := &TaskPosition{TaskID: 1, ProjectViewID: 1, Position: 4.001} // Task 2 is at 4
+		err := tp.Update(s, u)
+		require.NoError(t, err)
+
+		db.AssertExists(t, "project_views", map[string]interface{}{
+			"id":                1,
+			"needs_rebalancing": true,
+		}, false)
+
+		rebalanced, err := rebalanceTaskPositions(s)
+		require.NoError(t, err)
+		assert.Equal(t, 1, rebalanced)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "project_views", map[string]interface{}{
+			"id":                1,
+			"needs_rebalancing": false,
+		}, false)
+		db.AssertCount(t, "task_positions", builder.And(
+			builder.Eq{"project_view_id": 1},
+			builder.Lt{"position": 1},
+		), 0)
+	})
+}
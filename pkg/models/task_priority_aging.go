@@ -0,0 +1,162 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/log"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// PriorityAgingThreshold raises the priority of a task once its due date is less than the configured time away
+type PriorityAgingThreshold struct {
+	// How many seconds before the due date the priority should be raised.
+	BeforeDue int64 `json:"before_due"`
+	// The priority the task should have at least once the threshold is reached.
+	Priority int64 `json:"priority"`
+}
+
+func validatePriorityAging(project *Project) error {
+	for _, threshold := range project.PriorityAging {
+		if threshold.BeforeDue < 0 || threshold.Priority < 1 || threshold.Priority > taskPriorityDoNow {
+			return &ErrInvalidPriorityAgingThreshold{
+				ProjectID: project.ID,
+				BeforeDue: threshold.BeforeDue,
+				Priority:  threshold.Priority,
+			}
+		}
+	}
+	return nil
+}
+
+// agedPriority returns the priority a task due at the given time should have at least. Overdue tasks reached all thresholds.
+func agedPriority(thresholds []*PriorityAgingThreshold, dueDate time.Time, now time.Time) (priority int64) {
+	untilDue := dueDate.Sub(now)
+	for _, threshold := range thresholds {
+		if untilDue <= time.Duration(threshold.BeforeDue)*time.Second && threshold.Priority > priority {
+			priority = threshold.Priority
+		}
+	}
+	return
+}
+
+// agePriorities raises the priority of all undone tasks in projects with priority aging which reached a threshold.
+// Priorities are never lowered, a user might have raised them on purpose.
+func agePriorities(s *xorm.Session, now time.Time) (aged int, err error) {
+	projects := []*Project{}
+	err = s.
+		Where("priority_aging IS NOT NULL").
+		And("is_archived = ?", false).
+		Find(&projects)
+	if err != nil {
+		return
+	}
+
+	for _, project := range projects {
+		if len(project.PriorityAging) == 0 {
+			continue
+		}
+
+		// Tasks in frozen projects can't change
+		err = project.checkIsFrozen(s)
+		if IsErrProjectIsFrozen(err) {
+			err = nil
+			continue
+		}
+		if err != nil {
+			return
+		}
+
+		var maxBeforeDue int64
+		for _, threshold := range project.PriorityAging {
+			maxBeforeDue = max(maxBeforeDue, threshold.BeforeDue)
+		}
+
+		tasks := []*Task{}
+		err = s.
+			Where(builder.And(
+				builder.Eq{"project_id": project.ID},
+				builder.Eq{"done": false},
+				builder.NotNull{"due_date"},
+				builder.Lte{"due_date": now.Add(time.Duration(maxBeforeDue) * time.Second)},
+			)).
+			Find(&tasks)
+		if err != nil {
+			return
+		}
+
+		for _, task := range tasks {
+			priority := agedPriority(project.PriorityAging, task.DueDate, now)
+			if priority <= task.Priority {
+				continue
+			}
+
+			oldPriority := task.Priority
+			task.Priority = priority
+			_, err = s.ID(task.ID).Cols("priority").Update(task)
+			if err != nil {
+				return
+			}
+
+			err = events.Dispatch(&TaskPriorityAgedEvent{
+				Task:        task,
+				OldPriority: oldPriority,
+				NewPriority: priority,
+			})
+			if err != nil {
+				return
+			}
+			aged++
+		}
+	}
+
+	return
+}
+
+// RegisterPriorityAgingCron registers a cron function which raises task priorities according to their project's aging thresholds.
+// It runs next to the overdue reminders but does not depend on the mailer being enabled.
+func RegisterPriorityAgingCron() {
+	err := cron.Schedule("* * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		aged, err := agePriorities(s, time.Now())
+		if err != nil {
+			log.Errorf("[Priority Aging Cron] Could not age task priorities: %s", err)
+			_ = s.Rollback()
+			return
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf("[Priority Aging Cron] Could not commit aged priorities: %s", err)
+			return
+		}
+
+		if aged > 0 {
+			log.Debugf("[Priority Aging Cron] Raised the priority of %d tasks", aged)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register priority aging cron: %s", err)
+	}
+}
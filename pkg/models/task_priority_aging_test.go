@@ -0,0 +1,90 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgedPriority(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	thresholds := []*PriorityAgingThreshold{
+		{BeforeDue: 3 * 86400, Priority: 3},
+		{BeforeDue: 86400, Priority: 4},
+		{BeforeDue: 0, Priority: 5},
+	}
+
+	assert.Equal(t, int64(0), agedPriority(thresholds, now.Add(4*24*time.Hour), now))
+	assert.Equal(t, int64(3), agedPriority(thresholds, now.Add(2*24*time.Hour), now))
+	assert.Equal(t, int64(4), agedPriority(thresholds, now.Add(time.Hour), now))
+	assert.Equal(t, int64(5), agedPriority(thresholds, now.Add(-time.Hour), now))
+}
+
+func TestAgePriorities(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	events.Fake()
+	s := db.NewSession()
+	defer s.Close()
+
+	now := time.Now()
+	_, err := s.ID(1).Cols("priority_aging").Update(&Project{
+		PriorityAging: []*PriorityAgingThreshold{
+			{BeforeDue: 86400, Priority: 3},
+		},
+	})
+	require.NoError(t, err)
+	// Task 1 is due soon, task 4 has no due date within the threshold
+	_, err = s.ID(1).Cols("due_date").Update(&Task{DueDate: now.Add(time.Hour)})
+	require.NoError(t, err)
+	_, err = s.ID(4).Cols("due_date").Update(&Task{DueDate: now.Add(48 * time.Hour)})
+	require.NoError(t, err)
+
+	aged, err := agePriorities(s, now)
+	require.NoError(t, err)
+	require.NoError(t, s.Commit())
+
+	assert.Equal(t, 1, aged)
+	db.AssertExists(t, "tasks", map[string]interface{}{
+		"id":       1,
+		"priority": 3,
+	}, false)
+	db.AssertExists(t, "tasks", map[string]interface{}{
+		"id":       4,
+		"priority": 1,
+	}, false)
+	events.AssertDispatched(t, &TaskPriorityAgedEvent{})
+}
+
+func TestValidatePriorityAging(t *testing.T) {
+	err := validatePriorityAging(&Project{PriorityAging: []*PriorityAgingThreshold{{BeforeDue: 3600, Priority: 2}}})
+	require.NoError(t, err)
+
+	err = validatePriorityAging(&Project{PriorityAging: []*PriorityAgingThreshold{{BeforeDue: 3600, Priority: 6}}})
+	require.Error(t, err)
+	assert.True(t, IsErrInvalidPriorityAgingThreshold(err))
+
+	err = validatePriorityAging(&Project{PriorityAging: []*PriorityAgingThreshold{{BeforeDue: -1, Priority: 2}}})
+	require.Error(t, err)
+	assert.True(t, IsErrInvalidPriorityAgingThreshold(err))
+}
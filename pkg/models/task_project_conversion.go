@@ -0,0 +1,133 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// TaskProjectConversion holds everything needed to promote a task into its own project
+type TaskProjectConversion struct {
+	// The task to promote
+	TaskID int64 `json:"-" param:"task"`
+
+	// The project created from the task
+	Project *Project `json:"project,omitempty"`
+	// The tasks which were moved or created in the new project
+	Tasks []*Task `json:"tasks,omitempty"`
+
+	web.Rights   `json:"-"`
+	web.CRUDable `json:"-"`
+}
+
+var descriptionChecklistItemRegex = regexp.MustCompile(`(?s)<li[^>]*data-checked="(true|false)"[^>]*>(.*?)</li>`)
+
+// CanCreate checks if a user can promote a task into a project
+func (tpc *TaskProjectConversion) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	// Link shares can't create projects
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	t := &Task{ID: tpc.TaskID}
+	return t.CanWrite(s, a)
+}
+
+// Create promotes a task into a project
+// @Summary Convert a task into a project
+// @Description Creates a new child project of the task's project with the title and description of the task. All subtasks of the task from the same project are moved into the new project, every checklist item of the task description becomes a task in it. The task itself stays where it is and the new project's description links back to it.
+// @tags task
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Success 201 {object} models.TaskProjectConversion "The project created from the task."
+// @Failure 403 {object} web.HTTPError "The user does not have write access to the task."
+// @Failure 404 {object} web.HTTPError "The task does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/promote [post]
+func (tpc *TaskProjectConversion) Create(s *xorm.Session, a web.Auth) (err error) {
+	task := &Task{ID: tpc.TaskID}
+	err = task.ReadOne(s, a)
+	if err != nil {
+		return err
+	}
+
+	tpc.Project = &Project{
+		Title:           task.Title,
+		Description:     task.Description + `<p>Converted from task <a href="` + task.GetFrontendURL() + `">` + html.EscapeString(task.GetFullIdentifier()) + `</a>.</p>`,
+		ParentProjectID: task.ProjectID,
+		HexColor:        task.HexColor,
+	}
+	err = CreateProject(s, tpc.Project, a, true, true)
+	if err != nil {
+		return err
+	}
+
+	tpc.Tasks = []*Task{}
+
+	// Only subtasks living next to the task are moved, subtasks from other projects stay where they are.
+	for _, subtask := range task.RelatedTasks[RelationKindSubtask] {
+		if subtask.ProjectID != task.ProjectID {
+			continue
+		}
+
+		moved := &Task{ID: subtask.ID}
+		err = moved.ReadOne(s, a)
+		if err != nil {
+			return err
+		}
+		moved.ProjectID = tpc.Project.ID
+		err = moved.Update(s, a)
+		if err != nil {
+			return err
+		}
+		tpc.Tasks = append(tpc.Tasks, moved)
+	}
+
+	for _, item := range getChecklistItemsFromDescription(task.Description) {
+		item.ProjectID = tpc.Project.ID
+		err = createTask(s, item, a, false, true, true)
+		if err != nil {
+			return err
+		}
+		tpc.Tasks = append(tpc.Tasks, item)
+	}
+
+	return nil
+}
+
+// getChecklistItemsFromDescription returns a task for every checklist item in an html task description.
+// The tasks don't have a project set.
+func getChecklistItemsFromDescription(description string) (tasks []*Task) {
+	tasks = []*Task{}
+	for _, match := range descriptionChecklistItemRegex.FindAllStringSubmatch(description, -1) {
+		title := strings.TrimSpace(html.UnescapeString(htmlTagRegex.ReplaceAllString(match[2], "")))
+		if title == "" {
+			continue
+		}
+		tasks = append(tasks, &Task{
+			Title: title,
+			Done:  match[1] == "true",
+		})
+	}
+	return
+}
@@ -0,0 +1,103 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskProjectConversion_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(1).Cols("description").Update(&Task{
+			Description: `<p>Lorem Ipsum</p><ul data-type="taskList"><li data-type="taskItem" data-checked="true"><p>First</p></li><li data-type="taskItem" data-checked="false"><p>Second &amp; third</p></li></ul>`,
+		})
+		require.NoError(t, err)
+
+		tpc := &TaskProjectConversion{TaskID: 1}
+		can, err := tpc.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = tpc.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.Equal(t, "task #1", tpc.Project.Title)
+		assert.Equal(t, int64(1), tpc.Project.ParentProjectID)
+		assert.Contains(t, tpc.Project.Description, "/tasks/1")
+		require.Len(t, tpc.Tasks, 3)
+
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"id":                tpc.Project.ID,
+			"title":             "task #1",
+			"parent_project_id": 1,
+		}, false)
+		// The subtask is moved
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":         29,
+			"project_id": tpc.Project.ID,
+		}, false)
+		// Checklist items become tasks
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"title":      "First",
+			"done":       true,
+			"project_id": tpc.Project.ID,
+		}, false)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"title":      "Second & third",
+			"done":       false,
+			"project_id": tpc.Project.ID,
+		}, false)
+		// The task itself stays where it was
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":         1,
+			"project_id": 1,
+		}, false)
+	})
+	t.Run("no write access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tpc := &TaskProjectConversion{TaskID: 1}
+		can, err := tpc.CanCreate(s, &user.User{ID: 2})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tpc := &TaskProjectConversion{TaskID: 1}
+		can, err := tpc.CanCreate(s, &LinkSharing{ID: 2, ProjectID: 2, Right: RightWrite})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
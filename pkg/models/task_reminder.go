@@ -56,6 +56,50 @@ type TaskReminder struct {
 	RelativePeriod int64 `xorm:"bigint null" json:"relative_period"`
 	// The name of the date field to which the relative period refers to.
 	RelativeTo ReminderRelation `xorm:"varchar(50) null" json:"relative_to"`
+	// The interval in seconds after which the reminder is sent again until the task is done. 0 means the reminder is only sent once.
+	RepeatInterval int64 `xorm:"bigint null default 0" json:"repeat_interval"`
+	// The factor by which the repeat interval grows after every repetition, between 1 and 10. 0 or 1 keep the interval constant.
+	RepeatBackoff float64 `xorm:"double null" json:"repeat_backoff"`
+	// The maximum repeat interval in seconds the backoff can grow to. 0 means the interval grows up to one year.
+	RepeatMaxInterval int64 `xorm:"bigint null default 0" json:"repeat_max_interval"`
+	// The next time a relative or repeating reminder triggers, so the reminder cron does not have to resolve all of
+	// them every minute. Null if it has to be computed again, for example because the dates of the task changed.
+	NextTrigger time.Time `xorm:"DATETIME null INDEX 'next_trigger'" json:"-"`
+}
+
+const (
+	// minReminderRepeatInterval is the shortest repeat interval possible since the reminder cron only runs once per minute.
+	minReminderRepeatInterval = 60
+	// maxReminderRepeatInterval is the longest a repeat interval can be or grow to, in seconds.
+	maxReminderRepeatInterval = 366 * 24 * 60 * 60
+	// maxReminderRepeatBackoff is the largest factor a repeat interval can grow by after every repetition.
+	maxReminderRepeatBackoff = 10
+	// maxReminderBackoffSteps is the number of repetitions after which the interval of a repeating reminder stops
+	// growing, even if it did not reach its maximum yet. This bounds the work needed to compute the next trigger.
+	maxReminderBackoffSteps = 1000
+)
+
+// validateRepeat checks the repeat settings of a reminder are in a range the reminder cron can handle.
+func (r *TaskReminder) validateRepeat(taskID int64) error {
+	if r.RepeatInterval != 0 && (r.RepeatInterval < minReminderRepeatInterval || r.RepeatInterval > maxReminderRepeatInterval) {
+		return ErrReminderRepeatIntervalInvalid{
+			TaskID:         taskID,
+			RepeatInterval: r.RepeatInterval,
+		}
+	}
+
+	backoffInvalid := r.RepeatBackoff != 0 && (r.RepeatBackoff < 1 || r.RepeatBackoff > maxReminderRepeatBackoff)
+	maxIntervalInvalid := r.RepeatMaxInterval != 0 &&
+		(r.RepeatMaxInterval < r.RepeatInterval || r.RepeatMaxInterval > maxReminderRepeatInterval)
+	if backoffInvalid || maxIntervalInvalid {
+		return ErrReminderRepeatBackoffInvalid{
+			TaskID:            taskID,
+			RepeatBackoff:     r.RepeatBackoff,
+			RepeatMaxInterval: r.RepeatMaxInterval,
+		}
+	}
+
+	return nil
 }
 
 // TableName returns a pretty table name
@@ -63,6 +107,101 @@ func (TaskReminder) TableName() string {
 	return "task_reminders"
 }
 
+// isRelative returns true if the reminder is defined as an offset to one of the task's dates.
+func (r *TaskReminder) isRelative() bool {
+	return r.RelativeTo != ""
+}
+
+// resolveRelativeReminder returns the absolute time a relative reminder triggers for the given task.
+// If the task does not have the date the reminder relates to, a zero time is returned.
+func (r *TaskReminder) resolveRelativeReminder(task *Task) time.Time {
+	var relativeTo time.Time
+	switch r.RelativeTo {
+	case ReminderRelationDueDate:
+		relativeTo = task.DueDate
+	case ReminderRelationStartDate:
+		relativeTo = task.StartDate
+	case ReminderRelationEndDate:
+		relativeTo = task.EndDate
+	}
+
+	if relativeTo.IsZero() {
+		return time.Time{}
+	}
+
+	return relativeTo.Add(time.Duration(r.RelativePeriod) * time.Second)
+}
+
+// needsNextTrigger returns true if the reminder cron finds the reminder through its precomputed next trigger
+// instead of its reminder date.
+func (r *TaskReminder) needsNextTrigger() bool {
+	return r.isRelative() || r.isRepeating()
+}
+
+// resetReminderTriggers makes the reminder cron compute the next trigger of all reminders of a task again. This
+// needs to be called whenever the dates of a task change without updating its reminders.
+func resetReminderTriggers(s *xorm.Session, taskIDs ...int64) (err error) {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+
+	_, err = s.
+		Table("task_reminders").
+		In("task_id", taskIDs).
+		Update(map[string]interface{}{"next_trigger": nil})
+	return
+}
+
+// isRepeating returns true if the reminder is sent again after it first triggered.
+func (r *TaskReminder) isRepeating() bool {
+	return r.RepeatInterval > 0
+}
+
+// nextTrigger returns the first time at or after from when a reminder which first triggers at base will be sent.
+// For reminders which do not repeat this is always base.
+func (r *TaskReminder) nextTrigger(base, from time.Time) time.Time {
+	if !r.isRepeating() || !base.Before(from) {
+		return base
+	}
+
+	trigger := base
+	interval := time.Duration(r.RepeatInterval) * time.Second
+	maxInterval := time.Duration(r.RepeatMaxInterval) * time.Second
+	if maxInterval <= 0 || maxInterval > maxReminderRepeatInterval*time.Second {
+		maxInterval = maxReminderRepeatInterval * time.Second
+	}
+	if interval > maxInterval {
+		interval = maxInterval
+	}
+
+	// While the interval grows every repetition has to be stepped through, but only until it reaches its maximum
+	// or the step limit. The backoff is validated to be at least 1, so this can never run backwards.
+	for step := 0; r.RepeatBackoff > 1 && interval < maxInterval && step < maxReminderBackoffSteps; step++ {
+		if !trigger.Before(from) {
+			return trigger
+		}
+
+		trigger = trigger.Add(interval)
+		next := float64(interval) * r.RepeatBackoff
+		if next >= float64(maxInterval) {
+			interval = maxInterval
+		} else {
+			interval = time.Duration(next)
+		}
+	}
+
+	if !trigger.Before(from) {
+		return trigger
+	}
+
+	// Once the interval stopped growing the remaining repetitions can be skipped in one go
+	missed := from.Sub(trigger) / interval
+	if from.Sub(trigger)%interval != 0 {
+		missed++
+	}
+	return trigger.Add(missed * interval)
+}
+
 type taskUser struct {
 	Task *Task      `xorm:"extends"`
 	User *user.User `xorm:"extends"`
@@ -135,17 +274,28 @@ func getTasksWithRemindersDueAndTheirUsers(s *xorm.Session, now time.Time) (remi
 
 	log.Debugf("[Task Reminder Cron] Looking for reminders between %s and %s to send...", now, nextMinute)
 
+	// All reminders from -12h to +14h to include all time zones
+	windowStart := now.Add(time.Hour * -12)
+	windowEnd := nextMinute.Add(time.Hour * 14)
+
 	reminders := []*TaskReminder{}
 	err = s.
 		Join("INNER", "tasks", "tasks.id = task_reminders.task_id").
-		// All reminders from -12h to +14h to include all time zones
-		Where("reminder >= ? and reminder < ?", now.Add(time.Hour*-12).Format(dbTimeFormat), nextMinute.Add(time.Hour*14).Format(dbTimeFormat)).
+		Where("reminder >= ? and reminder < ?", windowStart.Format(dbTimeFormat), windowEnd.Format(dbTimeFormat)).
 		And("tasks.done = false").
+		And(builder.Or(builder.IsNull{"relative_to"}, builder.Eq{"relative_to": ""})).
+		And(builder.Or(builder.IsNull{"repeat_interval"}, builder.Eq{"repeat_interval": 0})).
 		Find(&reminders)
 	if err != nil {
 		return
 	}
 
+	resolvedReminders, err := getResolvedRemindersDue(s, now, nextMinute)
+	if err != nil {
+		return
+	}
+	reminders = append(reminders, resolvedReminders...)
+
 	log.Debugf("[Task Reminder Cron] Found %d reminders", len(reminders))
 
 	if len(reminders) == 0 {
@@ -185,6 +335,11 @@ func getTasksWithRemindersDueAndTheirUsers(s *xorm.Session, now time.Time) (remi
 
 		for _, u := range usersPerTask[r.TaskID] {
 
+			// Reminders of tasks in archived projects are paused until the project is unarchived
+			if p, has := projects[u.Task.ProjectID]; has && p.IsArchived {
+				continue
+			}
+
 			// This ensures we send each reminder only once to each user
 			if seen[r.TaskID] == nil {
 				seen[r.TaskID] = make(map[int64]bool)
@@ -224,6 +379,91 @@ func getTasksWithRemindersDueAndTheirUsers(s *xorm.Session, now time.Time) (remi
 	return
 }
 
+// getResolvedRemindersDue returns all relative or repeating reminders which trigger between start and end.
+// Relative reminders are resolved against the current dates of their tasks so they follow every change of the
+// date they relate to. Repeating reminders are only considered for tasks which are not done, which is what stops
+// them once their task gets completed.
+// To avoid resolving every reminder every minute, only reminders whose precomputed next trigger is due or which
+// don't have one yet are looked at. Afterwards their next trigger is updated.
+func getResolvedRemindersDue(s *xorm.Session, start, end time.Time) (reminders []*TaskReminder, err error) {
+	startDB := start.In(config.GetTimeZone()).Format(dbTimeFormat)
+	endDB := end.In(config.GetTimeZone()).Format(dbTimeFormat)
+
+	candidates := []*TaskReminder{}
+	err = s.
+		Select("task_reminders.*").
+		Join("INNER", "tasks", "tasks.id = task_reminders.task_id").
+		Where("tasks.done = false").
+		And(builder.Or(
+			builder.And(builder.NotNull{"relative_to"}, builder.Neq{"relative_to": ""}),
+			builder.Gt{"repeat_interval": 0},
+		)).
+		And(builder.Or(
+			builder.IsNull{"next_trigger"},
+			// Repeating reminders whose trigger was missed, for example because Vikunja was not running, are picked
+			// up again so they continue with their next repetition.
+			builder.And(
+				builder.Lt{"next_trigger": endDB},
+				builder.Or(builder.Gt{"repeat_interval": 0}, builder.Gte{"next_trigger": startDB}),
+			),
+		)).
+		Find(&candidates)
+	if err != nil || len(candidates) == 0 {
+		return
+	}
+
+	taskIDs := make([]int64, 0, len(candidates))
+	for _, r := range candidates {
+		taskIDs = append(taskIDs, r.TaskID)
+	}
+
+	tasks := make(map[int64]*Task, len(taskIDs))
+	err = s.In("id", taskIDs).Find(&tasks)
+	if err != nil {
+		return
+	}
+
+	for _, r := range candidates {
+		task, has := tasks[r.TaskID]
+		if !has {
+			continue
+		}
+
+		base := r.Reminder
+		if r.isRelative() {
+			base = r.resolveRelativeReminder(task)
+		}
+		if base.IsZero() {
+			continue
+		}
+
+		trigger := r.nextTrigger(base, start)
+
+		next := trigger
+		if r.isRepeating() && trigger.Before(end) {
+			next = r.nextTrigger(base, end)
+		}
+		if !next.Equal(r.NextTrigger) {
+			_, err = s.
+				ID(r.ID).
+				Cols("next_trigger").
+				Update(&TaskReminder{NextTrigger: next})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if trigger.Before(start) || !trigger.Before(end) {
+			continue
+		}
+
+		r.Reminder = trigger
+		reminders = append(reminders, r)
+	}
+
+	return
+}
+
 // RegisterReminderCron registers a cron function which runs every minute to check if any reminders are due the
 // next minute to send emails.
 func RegisterReminderCron() {
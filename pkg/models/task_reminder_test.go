@@ -39,6 +39,110 @@ func TestReminderGetTasksInTheNextMinute(t *testing.T) {
 		assert.Len(t, notifications, 1)
 		assert.Equal(t, int64(27), notifications[0].Task.ID)
 	})
+	t.Run("Found Tasks with relative reminder", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// Task 27 has a reminder one hour before its start date
+		now, err := time.Parse(time.RFC3339Nano, "2018-11-30T21:25:00Z")
+		require.NoError(t, err)
+		notifications, err := getTasksWithRemindersDueAndTheirUsers(s, now)
+		require.NoError(t, err)
+		assert.Len(t, notifications, 1)
+		assert.Equal(t, int64(27), notifications[0].Task.ID)
+	})
+	t.Run("Relative reminder follows date changes", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		// Move the start date without touching the reminders
+		_, err := s.ID(27).Cols("start_date").Update(&Task{StartDate: time.Date(2018, 12, 5, 10, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+
+		now, err := time.Parse(time.RFC3339Nano, "2018-11-30T21:25:00Z")
+		require.NoError(t, err)
+		notifications, err := getTasksWithRemindersDueAndTheirUsers(s, now)
+		require.NoError(t, err)
+		assert.Empty(t, notifications)
+
+		now, err = time.Parse(time.RFC3339Nano, "2018-12-05T09:00:00Z")
+		require.NoError(t, err)
+		notifications, err = getTasksWithRemindersDueAndTheirUsers(s, now)
+		require.NoError(t, err)
+		assert.Len(t, notifications, 1)
+		assert.Equal(t, int64(27), notifications[0].Task.ID)
+	})
+	t.Run("Relative reminder uses the precomputed next trigger", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		now, err := time.Parse(time.RFC3339Nano, "2018-11-30T21:25:00Z")
+		require.NoError(t, err)
+
+		// A next trigger in the future means the reminder is not even looked at
+		_, err = s.Where("task_id = ?", 27).
+			Cols("next_trigger").
+			Update(&TaskReminder{NextTrigger: now.Add(time.Hour)})
+		require.NoError(t, err)
+		notifications, err := getTasksWithRemindersDueAndTheirUsers(s, now)
+		require.NoError(t, err)
+		assert.Empty(t, notifications)
+
+		err = resetReminderTriggers(s, 27)
+		require.NoError(t, err)
+		notifications, err = getTasksWithRemindersDueAndTheirUsers(s, now)
+		require.NoError(t, err)
+		assert.Len(t, notifications, 1)
+		assert.Equal(t, int64(27), notifications[0].Task.ID)
+	})
+	t.Run("Found Tasks with repeating reminder", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Insert(&TaskReminder{
+			TaskID:         27,
+			Reminder:       time.Date(2018, 12, 1, 0, 0, 0, 0, time.UTC),
+			RepeatInterval: 3600,
+		})
+		require.NoError(t, err)
+
+		now, err := time.Parse(time.RFC3339Nano, "2018-12-01T03:00:00Z")
+		require.NoError(t, err)
+		notifications, err := getTasksWithRemindersDueAndTheirUsers(s, now)
+		require.NoError(t, err)
+		assert.Len(t, notifications, 1)
+		assert.Equal(t, int64(27), notifications[0].Task.ID)
+
+		now, err = time.Parse(time.RFC3339Nano, "2018-12-01T03:30:00Z")
+		require.NoError(t, err)
+		notifications, err = getTasksWithRemindersDueAndTheirUsers(s, now)
+		require.NoError(t, err)
+		assert.Empty(t, notifications)
+	})
+	t.Run("Repeating reminder stops when the task is done", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.Insert(&TaskReminder{
+			TaskID:         27,
+			Reminder:       time.Date(2018, 12, 1, 0, 0, 0, 0, time.UTC),
+			RepeatInterval: 3600,
+		})
+		require.NoError(t, err)
+		_, err = s.ID(27).Cols("done").Update(&Task{Done: true})
+		require.NoError(t, err)
+
+		now, err := time.Parse(time.RFC3339Nano, "2018-12-01T03:00:00Z")
+		require.NoError(t, err)
+		notifications, err := getTasksWithRemindersDueAndTheirUsers(s, now)
+		require.NoError(t, err)
+		assert.Empty(t, notifications)
+	})
 	t.Run("Found No Tasks", func(t *testing.T) {
 		db.LoadAndAssertFixtures(t)
 		s := db.NewSession()
@@ -51,3 +155,67 @@ func TestReminderGetTasksInTheNextMinute(t *testing.T) {
 		assert.Empty(t, taskIDs)
 	})
 }
+
+func TestTaskReminder_nextTrigger(t *testing.T) {
+	base := time.Date(2018, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("not repeating", func(t *testing.T) {
+		r := &TaskReminder{}
+		assert.Equal(t, base, r.nextTrigger(base, base.Add(5*time.Hour)))
+	})
+	t.Run("not yet triggered", func(t *testing.T) {
+		r := &TaskReminder{RepeatInterval: 3600}
+		assert.Equal(t, base, r.nextTrigger(base, base.Add(-time.Hour)))
+	})
+	t.Run("constant interval", func(t *testing.T) {
+		r := &TaskReminder{RepeatInterval: 3600}
+		assert.Equal(t, base.Add(3*time.Hour), r.nextTrigger(base, base.Add(3*time.Hour)))
+		assert.Equal(t, base.Add(4*time.Hour), r.nextTrigger(base, base.Add(3*time.Hour+time.Second)))
+	})
+	t.Run("backoff", func(t *testing.T) {
+		r := &TaskReminder{RepeatInterval: 3600, RepeatBackoff: 2}
+		// Triggers at 0h, 1h, 3h, 7h, 15h
+		assert.Equal(t, base.Add(3*time.Hour), r.nextTrigger(base, base.Add(2*time.Hour)))
+		assert.Equal(t, base.Add(7*time.Hour), r.nextTrigger(base, base.Add(4*time.Hour)))
+		assert.Equal(t, base.Add(15*time.Hour), r.nextTrigger(base, base.Add(8*time.Hour)))
+	})
+	t.Run("backoff with max interval", func(t *testing.T) {
+		r := &TaskReminder{RepeatInterval: 3600, RepeatBackoff: 2, RepeatMaxInterval: 4 * 3600}
+		// Triggers at 0h, 1h, 3h, 7h, 11h, 15h
+		assert.Equal(t, base.Add(11*time.Hour), r.nextTrigger(base, base.Add(8*time.Hour)))
+		assert.Equal(t, base.Add(15*time.Hour), r.nextTrigger(base, base.Add(12*time.Hour)))
+	})
+	t.Run("tiny backoff", func(t *testing.T) {
+		r := &TaskReminder{RepeatInterval: 60, RepeatBackoff: 1.0000001}
+		from := base.Add(10 * 365 * 24 * time.Hour)
+		trigger := r.nextTrigger(base, from)
+		assert.False(t, trigger.Before(from))
+		assert.True(t, trigger.Before(from.Add(2*time.Minute)))
+	})
+	t.Run("huge backoff", func(t *testing.T) {
+		r := &TaskReminder{RepeatInterval: 60, RepeatBackoff: 1e300}
+		// The interval is capped at one year instead of overflowing
+		assert.Equal(t, base.Add(time.Minute+maxReminderRepeatInterval*time.Second), r.nextTrigger(base, base.Add(time.Hour)))
+	})
+}
+
+func TestTaskReminder_validateRepeat(t *testing.T) {
+	valid := []*TaskReminder{
+		{},
+		{RepeatInterval: 3600},
+		{RepeatInterval: 3600, RepeatBackoff: 1},
+		{RepeatInterval: 3600, RepeatBackoff: 2, RepeatMaxInterval: 86400},
+	}
+	for _, r := range valid {
+		require.NoError(t, r.validateRepeat(1))
+	}
+
+	err := (&TaskReminder{RepeatInterval: 30}).validateRepeat(1)
+	assert.True(t, IsErrReminderRepeatIntervalInvalid(err))
+	err = (&TaskReminder{RepeatInterval: 3600, RepeatBackoff: 11}).validateRepeat(1)
+	assert.True(t, IsErrReminderRepeatBackoffInvalid(err))
+	err = (&TaskReminder{RepeatInterval: 3600, RepeatBackoff: 0.5}).validateRepeat(1)
+	assert.True(t, IsErrReminderRepeatBackoffInvalid(err))
+	err = (&TaskReminder{RepeatInterval: 3600, RepeatMaxInterval: 60}).validateRepeat(1)
+	assert.True(t, IsErrReminderRepeatBackoffInvalid(err))
+}
@@ -155,6 +155,31 @@ func convertFiltersToDBFilterCond(rawFilters []*taskFilter, includeNulls bool) (
 			continue
 		}
 
+		if f.field == "attachments" {
+			filter, err := getFilterCond(&taskFilter{
+				// recreating the struct here to avoid modifying it when reusing the opts struct
+				field:      "id",
+				value:      f.value,
+				comparator: f.comparator,
+				isNumeric:  f.isNumeric,
+			}, false)
+			if err != nil {
+				return nil, err
+			}
+
+			dbFilters = append(dbFilters, getFilterCondForSeparateTable("task_attachments", filter))
+			continue
+		}
+
+		if f.field == "project_tree" {
+			cond, err := getProjectTreeFilterCond(f)
+			if err != nil {
+				return nil, err
+			}
+			dbFilters = append(dbFilters, cond)
+			continue
+		}
+
 		if f.field == "parent_project" || f.field == "parent_project_id" {
 			filter, err := getFilterCond(&taskFilter{
 				// recreating the struct here to avoid modifying it when reusing the opts struct
@@ -205,6 +230,36 @@ func convertFiltersToDBFilterCond(rawFilters []*taskFilter, includeNulls bool) (
 	return filterCond, nil
 }
 
+// getProjectTreeFilterCond matches all tasks in the given projects and all of their child projects, however deep
+// they are nested.
+func getProjectTreeFilterCond(f *taskFilter) (cond builder.Cond, err error) {
+	projectIDs, is := f.value.([]interface{})
+	if !is {
+		projectIDs = []interface{}{f.value}
+	}
+	if len(projectIDs) == 0 {
+		return builder.Expr("0=1"), nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(projectIDs)), ",")
+	// UNION instead of UNION ALL stops the recursion if the parent projects ever form a cycle
+	tree := builder.Expr(`WITH RECURSIVE project_tree AS (
+			SELECT id FROM projects WHERE id IN (`+placeholders+`)
+			UNION
+			SELECT p.id FROM projects p INNER JOIN project_tree pt ON p.parent_project_id = pt.id
+		)
+		SELECT id FROM project_tree`, projectIDs...)
+
+	switch f.comparator {
+	case taskFilterComparatorEquals, taskFilterComparatorIn:
+		return builder.In("project_id", tree), nil
+	case taskFilterComparatorNotEquals:
+		return builder.NotIn("project_id", tree), nil
+	default:
+		return nil, ErrInvalidTaskFilterComparator{Comparator: f.comparator}
+	}
+}
+
 //nolint:gocyclo
 func (d *dbTaskSearcher) Search(opts *taskSearchOptions) (tasks []*Task, totalCount int64, err error) {
 
@@ -221,7 +276,7 @@ func (d *dbTaskSearcher) Search(opts *taskSearchOptions) (tasks []*Task, totalCo
 		}
 	}
 
-	filterCond, err := convertFiltersToDBFilterCond(opts.parsedFilters, opts.filterIncludeNulls)
+	filterCond, err := convertFiltersToDBFilterCond(opts.getFilters(), opts.filterIncludeNulls)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -230,11 +285,14 @@ func (d *dbTaskSearcher) Search(opts *taskSearchOptions) (tasks []*Task, totalCo
 	var where builder.Cond
 
 	if opts.search != "" {
-		where =
-			builder.Or(
-				db.ILIKE("title", opts.search),
-				db.ILIKE("description", opts.search),
-			)
+		where, err = GetSearchBackend().TaskCond(d.s, opts.search, opts.projectIDs)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if searchInCond := getTaskSearchInCond(opts.search, opts.searchIn); searchInCond != nil {
+			where = builder.Or(where, searchInCond)
+		}
 
 		searchIndex := getTaskIndexFromSearchString(opts.search)
 		if searchIndex > 0 {
@@ -509,7 +567,7 @@ func (t *typesenseTaskSearcher) Search(opts *taskSearchOptions) (tasks []*Task,
 		projectIDStrings = append(projectIDStrings, strconv.FormatInt(id, 10))
 	}
 
-	filter, err := convertParsedFilterToTypesense(opts.parsedFilters)
+	filter, err := convertParsedFilterToTypesense(opts.getFilters())
 	if err != nil {
 		return nil, 0, err
 	}
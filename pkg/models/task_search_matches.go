@@ -0,0 +1,195 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"code.vikunja.io/api/pkg/db"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+const (
+	// TaskSearchInComments makes the task search also look in the comments of tasks.
+	TaskSearchInComments = "comments"
+	// TaskSearchInAttachments makes the task search also look in the file names of attachments.
+	TaskSearchInAttachments = "attachments"
+)
+
+// How many characters of context a search match snippet shows around the match.
+const searchSnippetContext = 40
+
+// TaskSearchMatch is a place where the search text was found in a task.
+type TaskSearchMatch struct {
+	// Where the match is. Either `title`, `description`, `comment` or `attachment`.
+	Field string `json:"field"`
+	// The id of the comment or attachment the match is in.
+	ID int64 `json:"id,omitempty"`
+	// The text around the match.
+	Snippet string `json:"snippet"`
+}
+
+func validateTaskSearchIn(searchIn []string) error {
+	for _, in := range searchIn {
+		if in != TaskSearchInComments && in != TaskSearchInAttachments {
+			return ErrInvalidTaskSearchIn{SearchIn: in}
+		}
+	}
+	return nil
+}
+
+func searchesIn(searchIn []string, in string) bool {
+	for _, i := range searchIn {
+		if i == in {
+			return true
+		}
+	}
+	return false
+}
+
+// getTaskSearchInCond returns the condition matching tasks which have comments or attachments matching the search,
+// depending on where the search should look.
+func getTaskSearchInCond(search string, searchIn []string) (cond builder.Cond) {
+	conds := []builder.Cond{}
+	if searchesIn(searchIn, TaskSearchInComments) {
+		conds = append(conds, builder.In("tasks.id",
+			builder.
+				Select("task_id").
+				From("task_comments").
+				Where(db.ILIKE("comment", search)),
+		))
+	}
+	if searchesIn(searchIn, TaskSearchInAttachments) {
+		conds = append(conds, builder.In("tasks.id",
+			builder.
+				Select("task_attachments.task_id").
+				From("task_attachments").
+				InnerJoin("files", "files.id = task_attachments.file_id").
+				Where(db.ILIKE("files.name", search)),
+		))
+	}
+	if len(conds) == 0 {
+		return nil
+	}
+	return builder.Or(conds...)
+}
+
+// getSearchSnippet returns the text around the first case-insensitive occurrence of search in text.
+func getSearchSnippet(text, search string) (snippet string, found bool) {
+	lower := func(s string) string {
+		return strings.Map(unicode.ToLower, s)
+	}
+
+	// Lowering every rune on its own keeps the number of runes the same, so that positions in the lowered text
+	// match the ones in the original text.
+	byteIndex := strings.Index(lower(text), lower(search))
+	if search == "" || byteIndex < 0 {
+		return "", false
+	}
+
+	runes := []rune(text)
+	start := utf8.RuneCountInString(lower(text)[:byteIndex])
+	end := start + utf8.RuneCountInString(search)
+
+	from := max(start-searchSnippetContext, 0)
+	to := min(end+searchSnippetContext, len(runes))
+
+	snippet = strings.TrimSpace(string(runes[from:to]))
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(runes) {
+		snippet += "…"
+	}
+	return snippet, true
+}
+
+// addSearchMatchesToTasks adds where the search was found to every task.
+func addSearchMatchesToTasks(s *xorm.Session, taskMap map[int64]*Task, search string, searchIn []string) (err error) {
+	if search == "" || len(taskMap) == 0 {
+		return nil
+	}
+
+	taskIDs := make([]int64, 0, len(taskMap))
+	for id, t := range taskMap {
+		taskIDs = append(taskIDs, id)
+
+		if snippet, found := getSearchSnippet(t.Title, search); found {
+			t.SearchMatches = append(t.SearchMatches, &TaskSearchMatch{Field: "title", Snippet: snippet})
+		}
+		description := htmlTagRegex.ReplaceAllString(t.Description, " ")
+		if snippet, found := getSearchSnippet(description, search); found {
+			t.SearchMatches = append(t.SearchMatches, &TaskSearchMatch{Field: "description", Snippet: snippet})
+		}
+	}
+
+	if searchesIn(searchIn, TaskSearchInComments) {
+		comments := []*TaskComment{}
+		err = s.
+			In("task_id", taskIDs).
+			And(db.ILIKE("comment", search)).
+			OrderBy("id asc").
+			Find(&comments)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range comments {
+			text := htmlTagRegex.ReplaceAllString(c.Comment, " ")
+			if snippet, found := getSearchSnippet(text, search); found {
+				taskMap[c.TaskID].SearchMatches = append(taskMap[c.TaskID].SearchMatches, &TaskSearchMatch{
+					Field:   "comment",
+					ID:      c.ID,
+					Snippet: snippet,
+				})
+			}
+		}
+	}
+
+	if searchesIn(searchIn, TaskSearchInAttachments) {
+		attachments := []*struct {
+			ID     int64
+			TaskID int64
+			Name   string
+		}{}
+		err = s.
+			Table("task_attachments").
+			Select("task_attachments.id, task_attachments.task_id, files.name").
+			Join("INNER", "files", "files.id = task_attachments.file_id").
+			In("task_attachments.task_id", taskIDs).
+			And(db.ILIKE("files.name", search)).
+			OrderBy("task_attachments.id asc").
+			Find(&attachments)
+		if err != nil {
+			return err
+		}
+
+		for _, a := range attachments {
+			taskMap[a.TaskID].SearchMatches = append(taskMap[a.TaskID].SearchMatches, &TaskSearchMatch{
+				Field:   "attachment",
+				ID:      a.ID,
+				Snippet: a.Name,
+			})
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSearchSnippet(t *testing.T) {
+	t.Run("short text", func(t *testing.T) {
+		snippet, found := getSearchSnippet("Lorem Ipsum Dolor", "ipsum")
+		assert.True(t, found)
+		assert.Equal(t, "Lorem Ipsum Dolor", snippet)
+	})
+	t.Run("long text", func(t *testing.T) {
+		text := strings.Repeat("a", 100) + " needle " + strings.Repeat("b", 100)
+		snippet, found := getSearchSnippet(text, "NEEDLE")
+		assert.True(t, found)
+		assert.Equal(t, "…"+strings.Repeat("a", 39)+" needle "+strings.Repeat("b", 39)+"…", snippet)
+	})
+	t.Run("multibyte", func(t *testing.T) {
+		snippet, found := getSearchSnippet("Größe der Übersicht", "übersicht")
+		assert.True(t, found)
+		assert.Equal(t, "Größe der Übersicht", snippet)
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, found := getSearchSnippet("Lorem Ipsum", "dolor")
+		assert.False(t, found)
+	})
+}
+
+func TestTaskCollection_ReadAll_SearchIn(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	search := func(t *testing.T, text string, searchIn ...string) []*Task {
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{ProjectID: 1, SearchIn: searchIn}
+		result, _, _, err := tc.ReadAll(s, u, text, 0, 50)
+		require.NoError(t, err)
+		return result.([]*Task)
+	}
+
+	t.Run("comments", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		assert.Empty(t, search(t, "dolor"))
+
+		tasks := search(t, "dolor", TaskSearchInComments)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, int64(1), tasks[0].ID)
+		require.Len(t, tasks[0].SearchMatches, 1)
+		assert.Equal(t, "comment", tasks[0].SearchMatches[0].Field)
+		assert.Equal(t, int64(1), tasks[0].SearchMatches[0].ID)
+		assert.Equal(t, "Lorem Ipsum Dolor Sit Amet", tasks[0].SearchMatches[0].Snippet)
+	})
+	t.Run("attachments", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		assert.Empty(t, search(t, "test"))
+
+		tasks := search(t, "test", TaskSearchInAttachments)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, int64(1), tasks[0].ID)
+		require.Len(t, tasks[0].SearchMatches, 1)
+		assert.Equal(t, "attachment", tasks[0].SearchMatches[0].Field)
+		assert.Equal(t, "test", tasks[0].SearchMatches[0].Snippet)
+	})
+	t.Run("title match", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+
+		tasks := search(t, "high prio")
+		require.Len(t, tasks, 1)
+		require.Len(t, tasks[0].SearchMatches, 1)
+		assert.Equal(t, "title", tasks[0].SearchMatches[0].Field)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{ProjectID: 1, SearchIn: []string{"labels"}}
+		_, _, _, err := tc.ReadAll(s, u, "foo", 0, 50)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTaskSearchIn(err))
+	})
+}
@@ -0,0 +1,529 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// The search query language is a more human friendly way to write filters. A query like
+// `assignee:jan label:"bug" due:<2024-06-01 has:attachment "error log"` is parsed into the same filters the filter
+// query parameter would produce. Terms are combined with AND unless separated by OR, and can be grouped with
+// parentheses. Terms without a field search in the title and description of tasks.
+
+// searchQueryDateFields maps the date fields which can be used in a search query to the task property they filter.
+var searchQueryDateFields = map[string]string{
+	"due":        taskPropertyDueDate,
+	"due_date":   taskPropertyDueDate,
+	"start":      taskPropertyStartDate,
+	"start_date": taskPropertyStartDate,
+	"end":        taskPropertyEndDate,
+	"end_date":   taskPropertyEndDate,
+	"created":    taskPropertyCreated,
+	"updated":    taskPropertyUpdated,
+	"done_at":    taskPropertyDoneAt,
+}
+
+type searchQueryTokenKind int
+
+const (
+	searchQueryTokenTerm searchQueryTokenKind = iota
+	searchQueryTokenAnd
+	searchQueryTokenOr
+	searchQueryTokenOpen
+	searchQueryTokenClose
+)
+
+type searchQueryToken struct {
+	kind    searchQueryTokenKind
+	negated bool
+	field   string
+	value   string
+}
+
+// readSearchQueryValue reads a value starting at position i, either a quoted phrase or everything up to the next
+// whitespace or closing parenthesis.
+func readSearchQueryValue(query []rune, i int) (value string, next int, err error) {
+	if i < len(query) && query[i] == '"' {
+		end := i + 1
+		for end < len(query) && query[end] != '"' {
+			end++
+		}
+		if end >= len(query) {
+			return "", 0, ErrInvalidSearchQuery{Query: string(query), Reason: "a quoted phrase is not closed"}
+		}
+		return string(query[i+1 : end]), end + 1, nil
+	}
+
+	end := i
+	for end < len(query) && !unicode.IsSpace(query[end]) && query[end] != '(' && query[end] != ')' {
+		end++
+		// Stop after the field name when the value is quoted, as in label:"needs review"
+		if query[end-1] == ':' && end < len(query) && query[end] == '"' {
+			break
+		}
+	}
+	return string(query[i:end]), end, nil
+}
+
+func tokenizeSearchQuery(q string) (tokens []*searchQueryToken, err error) {
+	query := []rune(q)
+	for i := 0; i < len(query); {
+		r := query[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			tokens = append(tokens, &searchQueryToken{kind: searchQueryTokenOpen})
+			i++
+			continue
+		case r == ')':
+			tokens = append(tokens, &searchQueryToken{kind: searchQueryTokenClose})
+			i++
+			continue
+		case strings.HasPrefix(string(query[i:]), "&&"):
+			tokens = append(tokens, &searchQueryToken{kind: searchQueryTokenAnd})
+			i += 2
+			continue
+		case strings.HasPrefix(string(query[i:]), "||"):
+			tokens = append(tokens, &searchQueryToken{kind: searchQueryTokenOr})
+			i += 2
+			continue
+		}
+
+		token := &searchQueryToken{kind: searchQueryTokenTerm}
+		if r == '-' && i+1 < len(query) && !unicode.IsSpace(query[i+1]) {
+			token.negated = true
+			i++
+		}
+
+		if query[i] == '(' {
+			// Negated groups are handled by the parser
+			tokens = append(tokens, token)
+			continue
+		}
+
+		var word string
+		quoted := query[i] == '"'
+		word, i, err = readSearchQueryValue(query, i)
+		if err != nil {
+			return nil, err
+		}
+
+		if !quoted && !token.negated && (word == "AND" || word == "OR") {
+			token.kind = searchQueryTokenAnd
+			if word == "OR" {
+				token.kind = searchQueryTokenOr
+			}
+			tokens = append(tokens, token)
+			continue
+		}
+
+		if field, value, has := strings.Cut(word, ":"); has && !quoted {
+			token.field = strings.ToLower(field)
+			token.value = value
+			if value == "" && i < len(query) && query[i] == '"' {
+				token.value, i, err = readSearchQueryValue(query, i)
+				if err != nil {
+					return nil, err
+				}
+			}
+		} else {
+			token.value = word
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+type searchQueryParser struct {
+	s      *xorm.Session
+	a      web.Auth
+	loc    *time.Location
+	query  string
+	tokens []*searchQueryToken
+	pos    int
+}
+
+// parseSearchQuery parses a search query into task filters.
+func parseSearchQuery(s *xorm.Session, a web.Auth, query string, filterTimezone string) (filters []*taskFilter, err error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+
+	p := &searchQueryParser{
+		s:     s,
+		a:     a,
+		query: query,
+	}
+
+	if filterTimezone != "" {
+		p.loc, err = time.LoadLocation(filterTimezone)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.tokens, err = tokenizeSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	filters, err = p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, p.invalid("unexpected closing parenthesis")
+	}
+
+	return filters, nil
+}
+
+func (p *searchQueryParser) invalid(reason string) error {
+	return ErrInvalidSearchQuery{Query: p.query, Reason: reason}
+}
+
+func (p *searchQueryParser) peek() *searchQueryToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return p.tokens[p.pos]
+}
+
+// groupFilters returns the filters as one filter, nesting them if there is more than one.
+func groupFilters(filters []*taskFilter, join taskFilterConcatinator) *taskFilter {
+	if len(filters) == 1 {
+		filters[0].join = join
+		return filters[0]
+	}
+	return &taskFilter{value: filters, join: join}
+}
+
+func (p *searchQueryParser) parseOr() (filters []*taskFilter, err error) {
+	for {
+		and, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		join := filterConcatOr
+		if len(filters) == 0 {
+			join = filterConcatAnd
+		}
+		filters = append(filters, groupFilters(and, join))
+
+		next := p.peek()
+		if next == nil || next.kind != searchQueryTokenOr {
+			return filters, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *searchQueryParser) parseAnd() (filters []*taskFilter, err error) {
+	for {
+		next := p.peek()
+		if next == nil || next.kind == searchQueryTokenOr || next.kind == searchQueryTokenClose {
+			break
+		}
+		if next.kind == searchQueryTokenAnd {
+			p.pos++
+			continue
+		}
+
+		filter, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		filter.join = filterConcatAnd
+		filters = append(filters, filter)
+	}
+
+	if len(filters) == 0 {
+		return nil, p.invalid("expected a search term")
+	}
+
+	return filters, nil
+}
+
+func (p *searchQueryParser) parseTerm() (filter *taskFilter, err error) {
+	token := p.tokens[p.pos]
+	p.pos++
+
+	if token.kind == searchQueryTokenOpen || (token.negated && token.value == "" && token.field == "") {
+		if token.negated {
+			return nil, p.invalid("groups can't be negated")
+		}
+		group, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		next := p.peek()
+		if next == nil || next.kind != searchQueryTokenClose {
+			return nil, p.invalid("a parenthesis is not closed")
+		}
+		p.pos++
+		return groupFilters(group, filterConcatAnd), nil
+	}
+
+	return p.getFilterForToken(token)
+}
+
+func (p *searchQueryParser) newFilter(field string, comparator taskFilterComparator, value string) (*taskFilter, error) {
+	reflectValue, nativeValue, err := getNativeValueForTaskField(field, comparator, value, p.loc)
+	if err != nil {
+		return nil, ErrInvalidTaskFilterValue{Field: field, Value: value}
+	}
+
+	filter := &taskFilter{
+		field:      field,
+		value:      nativeValue,
+		comparator: comparator,
+		join:       filterConcatAnd,
+	}
+	if reflectValue != nil {
+		filter.isNumeric = reflectValue.Type.Kind() == reflect.Int64
+	}
+	return filter, nil
+}
+
+// splitSearchQueryComparator splits a comparator like `<` or `>=` from the beginning of a value.
+func splitSearchQueryComparator(value string) (comparator taskFilterComparator, rest string) {
+	for _, c := range []taskFilterComparator{
+		taskFilterComparatorGreateEquals,
+		taskFilterComparatorLessEquals,
+		taskFilterComparatorNotEquals,
+		taskFilterComparatorGreater,
+		taskFilterComparatorLess,
+		taskFilterComparatorEquals,
+	} {
+		if strings.HasPrefix(value, string(c)) {
+			return c, strings.TrimPrefix(value, string(c))
+		}
+	}
+	return taskFilterComparatorEquals, value
+}
+
+func invertComparator(comparator taskFilterComparator) taskFilterComparator {
+	switch comparator {
+	case taskFilterComparatorEquals:
+		return taskFilterComparatorNotEquals
+	case taskFilterComparatorNotEquals:
+		return taskFilterComparatorEquals
+	case taskFilterComparatorGreater:
+		return taskFilterComparatorLessEquals
+	case taskFilterComparatorGreateEquals:
+		return taskFilterComparatorLess
+	case taskFilterComparatorLess:
+		return taskFilterComparatorGreateEquals
+	case taskFilterComparatorLessEquals:
+		return taskFilterComparatorGreater
+	}
+	return comparator
+}
+
+func (p *searchQueryParser) getFilterForToken(token *searchQueryToken) (filter *taskFilter, err error) {
+	if token.value == "" {
+		return nil, p.invalid("the field " + token.field + " needs a value")
+	}
+
+	if dateField, is := searchQueryDateFields[token.field]; is {
+		return p.getDateFilter(dateField, token)
+	}
+
+	switch token.field {
+	case "":
+		if token.negated {
+			return nil, p.invalid("search text can't be negated")
+		}
+		title, err := p.newFilter(taskPropertyTitle, taskFilterComparatorLike, token.value)
+		if err != nil {
+			return nil, err
+		}
+		description, err := p.newFilter(taskPropertyDescription, taskFilterComparatorLike, token.value)
+		if err != nil {
+			return nil, err
+		}
+		description.join = filterConcatOr
+		return groupFilters([]*taskFilter{title, description}, filterConcatAnd), nil
+	case "done", "is":
+		var done bool
+		switch strings.ToLower(token.value) {
+		case "true", "yes", "done":
+			done = true
+		case "false", "no", "open", "undone":
+			done = false
+		default:
+			return nil, p.invalid(token.value + " is not a valid value for " + token.field)
+		}
+		if token.negated {
+			done = !done
+		}
+		return p.newFilter(taskPropertyDone, taskFilterComparatorEquals, strconv.FormatBool(done))
+	case "priority":
+		comparator, value := splitSearchQueryComparator(token.value)
+		if token.negated {
+			comparator = invertComparator(comparator)
+		}
+		return p.newFilter(taskPropertyPriority, comparator, value)
+	case "has":
+		if token.negated {
+			return nil, p.invalid("has: can't be negated")
+		}
+		return p.getHasFilter(token.value)
+	}
+
+	if token.negated {
+		return nil, p.invalid(token.field + ": can't be negated")
+	}
+
+	switch token.field {
+	case "assignee", "assignees":
+		username := token.value
+		if username == "me" {
+			if _, is := p.a.(*LinkSharing); is {
+				return nil, p.invalid("link shares can't search for their own tasks")
+			}
+			u, err := user.GetUserByID(p.s, p.a.GetID())
+			if err != nil {
+				return nil, err
+			}
+			username = u.Username
+		}
+		return p.newFilter("assignees", taskFilterComparatorIn, username)
+	case "label", "labels":
+		ids := []int64{}
+		err = p.s.
+			Table("labels").
+			Where(builder.Expr("LOWER(title) = ?", strings.ToLower(token.value))).
+			Cols("id").
+			Find(&ids)
+		if err != nil {
+			return nil, err
+		}
+		if id, err := strconv.ParseInt(token.value, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+		return p.newFilter("labels", taskFilterComparatorIn, joinIDs(ids))
+	case "project":
+		ids := []int64{}
+		if id, err := strconv.ParseInt(token.value, 10, 64); err == nil {
+			ids = append(ids, id)
+		} else {
+			err = p.s.
+				Table("projects").
+				Where(builder.Expr("LOWER(title) = ?", strings.ToLower(token.value))).
+				Cols("id").
+				Find(&ids)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return p.newFilter(taskPropertyProjectID, taskFilterComparatorIn, joinIDs(ids))
+	}
+
+	return nil, p.invalid(token.field + " is not a known field")
+}
+
+// joinIDs returns the ids as a comma separated list. Because an empty list would match everything, it returns an
+// id which never exists when there are none.
+func joinIDs(ids []int64) string {
+	if len(ids) == 0 {
+		return "0"
+	}
+	strs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		strs = append(strs, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(strs, ",")
+}
+
+func (p *searchQueryParser) getDateFilter(field string, token *searchQueryToken) (filter *taskFilter, err error) {
+	comparator, value := splitSearchQueryComparator(token.value)
+
+	// A date without a time means the whole day when searching for equality
+	day, dayErr := time.Parse(safariDate, value)
+	if dayErr != nil || (comparator != taskFilterComparatorEquals && comparator != taskFilterComparatorNotEquals) {
+		if token.negated {
+			comparator = invertComparator(comparator)
+		}
+		return p.newFilter(field, comparator, value)
+	}
+
+	outside := comparator == taskFilterComparatorNotEquals
+	if token.negated {
+		outside = !outside
+	}
+
+	nextDay := day.AddDate(0, 0, 1).Format(safariDate)
+	if outside {
+		before, err := p.newFilter(field, taskFilterComparatorLess, value)
+		if err != nil {
+			return nil, err
+		}
+		after, err := p.newFilter(field, taskFilterComparatorGreateEquals, nextDay)
+		if err != nil {
+			return nil, err
+		}
+		after.join = filterConcatOr
+		return groupFilters([]*taskFilter{before, after}, filterConcatAnd), nil
+	}
+
+	from, err := p.newFilter(field, taskFilterComparatorGreateEquals, value)
+	if err != nil {
+		return nil, err
+	}
+	until, err := p.newFilter(field, taskFilterComparatorLess, nextDay)
+	if err != nil {
+		return nil, err
+	}
+	return groupFilters([]*taskFilter{from, until}, filterConcatAnd), nil
+}
+
+func (p *searchQueryParser) getHasFilter(value string) (filter *taskFilter, err error) {
+	switch strings.ToLower(value) {
+	case "attachment", "attachments":
+		return p.newFilter("attachments", taskFilterComparatorGreater, "0")
+	case "label", "labels":
+		return p.newFilter("labels", taskFilterComparatorGreater, "0")
+	case "reminder", "reminders":
+		return p.newFilter("reminders", taskFilterComparatorGreater, "1970-01-01")
+	case "due", "due_date":
+		return p.newFilter(taskPropertyDueDate, taskFilterComparatorGreater, "1970-01-01")
+	case "start", "start_date":
+		return p.newFilter(taskPropertyStartDate, taskFilterComparatorGreater, "1970-01-01")
+	case "end", "end_date":
+		return p.newFilter(taskPropertyEndDate, taskFilterComparatorGreater, "1970-01-01")
+	}
+	return nil, p.invalid(value + " is not something a task can have")
+}
@@ -0,0 +1,108 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenizeSearchQuery(t *testing.T) {
+	tokens, err := tokenizeSearchQuery(`assignee:jan label:"needs review" -due:<2024-06-01 (foo OR "bar baz")`)
+	require.NoError(t, err)
+	require.Len(t, tokens, 8)
+
+	assert.Equal(t, "assignee", tokens[0].field)
+	assert.Equal(t, "jan", tokens[0].value)
+	assert.Equal(t, "label", tokens[1].field)
+	assert.Equal(t, "needs review", tokens[1].value)
+	assert.Equal(t, "due", tokens[2].field)
+	assert.Equal(t, "<2024-06-01", tokens[2].value)
+	assert.True(t, tokens[2].negated)
+	assert.Equal(t, searchQueryTokenOpen, tokens[3].kind)
+	assert.Equal(t, "foo", tokens[4].value)
+	assert.Equal(t, searchQueryTokenOr, tokens[5].kind)
+	assert.Equal(t, "bar baz", tokens[6].value)
+	assert.Empty(t, tokens[6].field)
+	assert.Equal(t, searchQueryTokenClose, tokens[7].kind)
+}
+
+func TestTaskCollection_ReadAll_SearchQuery(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	search := func(t *testing.T, q string) []int64 {
+		s := db.NewSession()
+		defer s.Close()
+
+		tc := &TaskCollection{ProjectID: 1, Query: q}
+		result, _, _, err := tc.ReadAll(s, u, "", 0, 50)
+		require.NoError(t, err)
+
+		ids := []int64{}
+		for _, task := range result.([]*Task) {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	t.Run("text with or", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		assert.Equal(t, []int64{3, 4}, search(t, `"high prio" OR "low prio"`))
+	})
+	t.Run("comparator", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		assert.Equal(t, []int64{3}, search(t, `priority:>=50`))
+	})
+	t.Run("has attachment", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		assert.Equal(t, []int64{1}, search(t, `has:attachment`))
+	})
+	t.Run("label by title and done", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		assert.Equal(t, []int64{1, 2}, search(t, `label:"label #4 - visible via other task"`))
+		assert.Equal(t, []int64{1}, search(t, `label:"label #4 - visible via other task" is:open`))
+		assert.Equal(t, []int64{2}, search(t, `label:"label #4 - visible via other task" AND -done:false`))
+	})
+	t.Run("grouping", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		assert.Equal(t, []int64{1, 3}, search(t, `("high prio" OR has:attachment) is:open`))
+	})
+	t.Run("invalid queries", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		for _, q := range []string{
+			`(foo`,
+			`foo)`,
+			`"foo`,
+			`unknown:bar`,
+			`-assignee:user1`,
+			`-(foo bar)`,
+			`has:nothing`,
+		} {
+			_, err := parseSearchQuery(s, u, q, "")
+			require.Error(t, err, q)
+			assert.True(t, IsErrInvalidSearchQuery(err), q)
+		}
+	})
+}
@@ -0,0 +1,441 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/user"
+
+	"code.vikunja.io/web"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// The highest priority a task can have, shown as "DO NOW" in the frontend.
+const taskPriorityDoNow = 5
+
+// TaskSLARule defines how long tasks in a project may stay undone and what happens once they took longer.
+type TaskSLARule struct {
+	// The unique, numeric id of this rule.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"slarule"`
+	// The project this rule applies to.
+	ProjectID int64 `xorm:"bigint not null INDEX" json:"project_id" param:"project"`
+	// A title to recognize the rule.
+	Title string `xorm:"varchar(250) not null" json:"title" valid:"required,runelength(1|250)" minLength:"1" maxLength:"250"`
+	// Only tasks with this priority are checked. 0 means the rule applies to tasks of all priorities.
+	Priority int64 `xorm:"bigint not null default 0" json:"priority" valid:"range(0|5)"`
+	// The time in seconds after their creation tasks need to be done in.
+	MaxDuration int64 `xorm:"bigint not null" json:"max_duration" valid:"range(60|9223372036854775807)"`
+
+	// If true, the priority of breaching tasks is raised by one.
+	BumpPriority bool `xorm:"not null default false" json:"bump_priority"`
+	// If true, all admins of the project are notified about breaching tasks.
+	NotifyAdmins bool `xorm:"not null default false" json:"notify_admins"`
+	// If set, this label is added to breaching tasks.
+	LabelID int64 `xorm:"bigint not null default 0" json:"label_id"`
+
+	CreatedByID int64 `xorm:"bigint not null" json:"-"`
+
+	// A timestamp when this rule was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this rule was last updated. You cannot change this value.
+	Updated time.Time `xorm:"updated not null" json:"updated"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for task sla rules
+func (*TaskSLARule) TableName() string {
+	return "task_sla_rules"
+}
+
+// TaskSLABreach records that a task breached an sla rule so it is only escalated once per rule.
+type TaskSLABreach struct {
+	ID      int64     `xorm:"bigint autoincr not null unique pk" json:"id"`
+	TaskID  int64     `xorm:"bigint not null unique(task_rule)" json:"task_id"`
+	RuleID  int64     `xorm:"bigint not null unique(task_rule)" json:"rule_id"`
+	Created time.Time `xorm:"created not null" json:"created"`
+}
+
+// TableName returns the table name for task sla breaches
+func (*TaskSLABreach) TableName() string {
+	return "task_sla_breaches"
+}
+
+func (r *TaskSLARule) validate() error {
+	if !r.BumpPriority && !r.NotifyAdmins && r.LabelID == 0 {
+		return &ErrTaskSLARuleHasNoAction{}
+	}
+	return nil
+}
+
+// Create creates a new sla rule
+// @Summary Create an sla rule
+// @Description Creates a new sla rule for a project. Tasks which are not done within the configured duration after their creation are escalated with the configured actions.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param rule body models.TaskSLARule true "The sla rule"
+// @Success 201 {object} models.TaskSLARule "The created sla rule."
+// @Failure 400 {object} web.HTTPError "Invalid sla rule object provided."
+// @Failure 403 {object} web.HTTPError "The user is not an admin of the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/slarules [put]
+func (r *TaskSLARule) Create(s *xorm.Session, a web.Auth) (err error) {
+	err = r.validate()
+	if err != nil {
+		return
+	}
+
+	r.ID = 0
+	r.CreatedByID = a.GetID()
+
+	_, err = s.Insert(r)
+	return
+}
+
+// ReadOne returns one sla rule
+// @Summary Get one sla rule
+// @Description Returns one sla rule of a project.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param slarule path int true "Rule ID"
+// @Success 200 {object} models.TaskSLARule "The sla rule."
+// @Failure 403 {object} web.HTTPError "The user is not an admin of the project."
+// @Failure 404 {object} web.HTTPError "The sla rule does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/slarules/{slarule} [get]
+func (r *TaskSLARule) ReadOne(s *xorm.Session, _ web.Auth) (err error) {
+	rule, err := getTaskSLARuleByID(s, r.ID)
+	if err != nil {
+		return err
+	}
+
+	*r = *rule
+	return
+}
+
+// ReadAll returns all sla rules of a project
+// @Summary Get all sla rules of a project
+// @Description Returns all sla rules of a project.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Success 200 {array} models.TaskSLARule "The sla rules."
+// @Failure 403 {object} web.HTTPError "The user is not an admin of the project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/slarules [get]
+func (r *TaskSLARule) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, totalItems int64, err error) {
+	can, err := r.canDoTaskSLARule(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	rules := []*TaskSLARule{}
+	err = s.Where("project_id = ?", r.ProjectID).
+		Limit(getLimitFromPageIndex(page, perPage)).
+		OrderBy("id asc").
+		Find(&rules)
+	if err != nil {
+		return
+	}
+
+	totalItems, err = s.Where("project_id = ?", r.ProjectID).
+		Count(&TaskSLARule{})
+	return rules, len(rules), totalItems, err
+}
+
+// Update updates an sla rule
+// @Summary Update an sla rule
+// @Description Updates an sla rule. Tasks which already breached the rule are not escalated again.
+// @tags project
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param slarule path int true "Rule ID"
+// @Param rule body models.TaskSLARule true "The sla rule"
+// @Success 200 {object} models.TaskSLARule "The updated sla rule."
+// @Failure 400 {object} web.HTTPError "Invalid sla rule object provided."
+// @Failure 403 {object} web.HTTPError "The user is not an admin of the project."
+// @Failure 404 {object} web.HTTPError "The sla rule does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/slarules/{slarule} [post]
+func (r *TaskSLARule) Update(s *xorm.Session, a web.Auth) (err error) {
+	err = r.validate()
+	if err != nil {
+		return
+	}
+
+	_, err = s.
+		Where("id = ?", r.ID).
+		Cols(
+			"title",
+			"priority",
+			"max_duration",
+			"bump_priority",
+			"notify_admins",
+			"label_id",
+		).
+		Update(r)
+	if err != nil {
+		return
+	}
+
+	return r.ReadOne(s, a)
+}
+
+// Delete removes an sla rule
+// @Summary Delete an sla rule
+// @Description Deletes an sla rule.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param slarule path int true "Rule ID"
+// @Success 200 {object} models.Message "The sla rule was successfully deleted."
+// @Failure 403 {object} web.HTTPError "The user is not an admin of the project."
+// @Failure 404 {object} web.HTTPError "The sla rule does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/slarules/{slarule} [delete]
+func (r *TaskSLARule) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.Where("rule_id = ?", r.ID).Delete(&TaskSLABreach{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("id = ?", r.ID).Delete(&TaskSLARule{})
+	return
+}
+
+func getTaskSLARuleByID(s *xorm.Session, id int64) (rule *TaskSLARule, err error) {
+	rule = &TaskSLARule{}
+	exists, err := s.Where("id = ?", id).Get(rule)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrTaskSLARuleDoesNotExist{ID: id}
+	}
+	return
+}
+
+// getProjectAdmins returns the owner of a project and all users who have admin rights on it directly or through a team.
+func getProjectAdmins(s *xorm.Session, project *Project) (admins map[int64]*user.User, err error) {
+	userIDs := []int64{project.OwnerID}
+
+	projectUsers := []*ProjectUser{}
+	err = s.
+		Where(builder.Eq{"project_id": project.ID, "right": RightAdmin}).
+		Find(&projectUsers)
+	if err != nil {
+		return
+	}
+	for _, pu := range projectUsers {
+		userIDs = append(userIDs, pu.UserID)
+	}
+
+	teamProjects := []*TeamProject{}
+	err = s.
+		Where(builder.Eq{"project_id": project.ID, "right": RightAdmin}).
+		Find(&teamProjects)
+	if err != nil {
+		return
+	}
+
+	if len(teamProjects) > 0 {
+		teamIDs := make([]int64, 0, len(teamProjects))
+		for _, tp := range teamProjects {
+			teamIDs = append(teamIDs, tp.TeamID)
+		}
+
+		members := []*TeamMember{}
+		err = s.In("team_id", teamIDs).Find(&members)
+		if err != nil {
+			return
+		}
+		for _, m := range members {
+			userIDs = append(userIDs, m.UserID)
+		}
+	}
+
+	return user.GetUsersByIDs(s, userIDs)
+}
+
+// escalateSLABreaches finds all undone tasks which breached one of the sla rules of their project and escalates
+// them with the actions configured in the rule. Every task is only escalated once per rule.
+func escalateSLABreaches(s *xorm.Session, now time.Time) (escalated int, err error) {
+	rules := []*TaskSLARule{}
+	err = s.Find(&rules)
+	if err != nil || len(rules) == 0 {
+		return
+	}
+
+	for _, rule := range rules {
+		cond := builder.And(
+			builder.Eq{"project_id": rule.ProjectID},
+			builder.Eq{"done": false},
+			builder.Lt{"created": now.Add(-time.Duration(rule.MaxDuration) * time.Second).Format(dbTimeFormat)},
+			builder.NotIn("id", builder.Select("task_id").From("task_sla_breaches").Where(builder.Eq{"rule_id": rule.ID})),
+		)
+		if rule.Priority != 0 {
+			cond = builder.And(cond, builder.Eq{"priority": rule.Priority})
+		}
+
+		tasks := []*Task{}
+		err = s.Where(cond).Find(&tasks)
+		if err != nil {
+			return
+		}
+
+		if len(tasks) == 0 {
+			continue
+		}
+
+		var project *Project
+		project, err = GetProjectSimpleByID(s, rule.ProjectID)
+		if err != nil {
+			return
+		}
+
+		// Tasks in frozen projects can't change, they are escalated once the project is unfrozen
+		err = project.checkIsFrozen(s)
+		if IsErrProjectIsFrozen(err) {
+			err = nil
+			continue
+		}
+		if err != nil {
+			return
+		}
+
+		for _, task := range tasks {
+			err = escalateSLABreach(s, rule, task, project)
+			if err != nil {
+				return
+			}
+			escalated++
+		}
+	}
+
+	return
+}
+
+func escalateSLABreach(s *xorm.Session, rule *TaskSLARule, task *Task, project *Project) (err error) {
+	_, err = s.Insert(&TaskSLABreach{
+		TaskID: task.ID,
+		RuleID: rule.ID,
+	})
+	if err != nil {
+		return
+	}
+
+	if rule.BumpPriority && task.Priority < taskPriorityDoNow {
+		task.Priority++
+		_, err = s.ID(task.ID).Cols("priority").Update(task)
+		if err != nil {
+			return
+		}
+	}
+
+	if rule.LabelID != 0 {
+		var exists bool
+		exists, err = s.Exist(&LabelTask{TaskID: task.ID, LabelID: rule.LabelID})
+		if err != nil {
+			return
+		}
+		if !exists {
+			err = checkExclusiveLabelGroupsOfTask(s, task.ID, rule.LabelID)
+			if err == nil {
+				_, err = s.Insert(&LabelTask{TaskID: task.ID, LabelID: rule.LabelID})
+			}
+			// The escalation should still happen if the task already has another label of the same exclusive group
+			if IsErrLabelGroupIsExclusive(err) {
+				log.Debugf("Not adding label %d of sla rule %d to task %d: %s", rule.LabelID, rule.ID, task.ID, err)
+				err = nil
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	if !rule.NotifyAdmins {
+		return
+	}
+
+	admins, err := getProjectAdmins(s, project)
+	if err != nil {
+		return
+	}
+
+	for _, admin := range admins {
+		err = notifications.Notify(admin, &TaskSLABreachedNotification{
+			User:    admin,
+			Task:    task,
+			Project: project,
+			Rule:    rule,
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// RegisterSLAEscalationCron registers a cron function which escalates all tasks breaching an sla rule every five minutes.
+func RegisterSLAEscalationCron() {
+	err := cron.Schedule("*/5 * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		escalated, err := escalateSLABreaches(s, time.Now())
+		if err != nil {
+			log.Errorf("[Task SLA Cron] Could not escalate sla breaches: %s", err)
+			_ = s.Rollback()
+			return
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf("[Task SLA Cron] Could not commit sla escalations: %s", err)
+			return
+		}
+
+		if escalated > 0 {
+			log.Debugf("[Task SLA Cron] Escalated %d sla breaches", escalated)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register sla escalation cron: %s", err)
+	}
+}
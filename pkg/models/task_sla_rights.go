@@ -0,0 +1,80 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if a user can see an sla rule
+func (r *TaskSLARule) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	can, err := r.canDoTaskSLARule(s, a)
+	if err != nil || !can {
+		return false, 0, err
+	}
+	return true, int(RightAdmin), nil
+}
+
+// CanCreate checks if a user can create a new sla rule for a project
+func (r *TaskSLARule) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	return r.canDoTaskSLARule(s, a)
+}
+
+// CanUpdate checks if a user can update an sla rule
+func (r *TaskSLARule) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
+	return r.canDoTaskSLARule(s, a)
+}
+
+// CanDelete checks if a user can delete an sla rule
+func (r *TaskSLARule) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return r.canDoTaskSLARule(s, a)
+}
+
+func (r *TaskSLARule) canDoTaskSLARule(s *xorm.Session, a web.Auth) (bool, error) {
+	if _, is := a.(*LinkSharing); is {
+		return false, nil
+	}
+
+	// Make sure the rule actually belongs to the project from the url
+	if r.ID != 0 {
+		rule, err := getTaskSLARuleByID(s, r.ID)
+		if err != nil {
+			return false, err
+		}
+		if rule.ProjectID != r.ProjectID {
+			return false, &ErrTaskSLARuleDoesNotExist{ID: r.ID}
+		}
+	}
+
+	p := &Project{ID: r.ProjectID}
+	isAdmin, err := p.IsAdmin(s, a)
+	if err != nil || !isAdmin {
+		return false, err
+	}
+
+	// Breaching tasks get the label added without further checks, so the user needs access to it
+	if r.LabelID != 0 {
+		l := &Label{ID: r.LabelID}
+		canRead, _, err := l.CanRead(s, a)
+		if err != nil || !canRead {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
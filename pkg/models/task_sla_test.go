@@ -0,0 +1,132 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskSLARule_Create(t *testing.T) {
+	u := &user.User{ID: 1}
+
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		rule := &TaskSLARule{
+			ProjectID:    1,
+			Title:        "Urgent tasks",
+			Priority:     4,
+			MaxDuration:  48 * 3600,
+			BumpPriority: true,
+		}
+		can, err := rule.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = rule.Create(s, u)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_sla_rules", map[string]interface{}{
+			"id":         rule.ID,
+			"project_id": 1,
+			"priority":   4,
+		}, false)
+	})
+	t.Run("without action", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		rule := &TaskSLARule{
+			ProjectID:   1,
+			Title:       "Nothing",
+			MaxDuration: 3600,
+		}
+		err := rule.Create(s, u)
+		require.Error(t, err)
+		assert.True(t, IsErrTaskSLARuleHasNoAction(err))
+	})
+	t.Run("no admin", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		rule := &TaskSLARule{ProjectID: 2}
+		can, err := rule.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+}
+
+func TestEscalateSLABreaches(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	rule := &TaskSLARule{
+		ProjectID:    1,
+		Title:        "Low priority",
+		Priority:     1,
+		MaxDuration:  3600,
+		BumpPriority: true,
+		NotifyAdmins: true,
+		LabelID:      1,
+		CreatedByID:  1,
+	}
+	_, err := s.Insert(rule)
+	require.NoError(t, err)
+
+	now := time.Date(2018, 12, 1, 3, 0, 0, 0, time.UTC)
+	escalated, err := escalateSLABreaches(s, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, escalated)
+
+	// Already escalated tasks are not escalated again
+	escalated, err = escalateSLABreaches(s, now)
+	require.NoError(t, err)
+	assert.Equal(t, 0, escalated)
+
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertExists(t, "tasks", map[string]interface{}{
+		"id":       4,
+		"priority": 2,
+	}, false)
+	db.AssertExists(t, "label_tasks", map[string]interface{}{
+		"task_id":  4,
+		"label_id": 1,
+	}, false)
+	db.AssertExists(t, "task_sla_breaches", map[string]interface{}{
+		"task_id": 4,
+		"rule_id": rule.ID,
+	}, false)
+	db.AssertExists(t, "notifications", map[string]interface{}{
+		"notifiable_id": 1,
+		"name":          "task.sla.breached",
+	}, false)
+}
@@ -0,0 +1,57 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import "time"
+
+// TaskSubtaskRollup sums up the subtasks of a task
+type TaskSubtaskRollup struct {
+	// The number of subtasks
+	Total int64 `json:"total"`
+	// How many of the subtasks are done
+	Done int64 `json:"done"`
+	// The sum of the estimates of all subtasks, in seconds.
+	Estimate int64 `json:"estimate"`
+	// The earliest start date of all subtasks
+	EarliestStartDate time.Time `json:"earliest_start_date"`
+	// The latest due date of all subtasks
+	LatestDueDate time.Time `json:"latest_due_date"`
+}
+
+func rollupSubtasks(subtasks []*Task) *TaskSubtaskRollup {
+	if len(subtasks) == 0 {
+		return nil
+	}
+
+	rollup := &TaskSubtaskRollup{}
+	for _, subtask := range subtasks {
+		rollup.Total++
+		if subtask.Done {
+			rollup.Done++
+		}
+		rollup.Estimate += subtask.Estimate
+
+		if !subtask.StartDate.IsZero() && (rollup.EarliestStartDate.IsZero() || subtask.StartDate.Before(rollup.EarliestStartDate)) {
+			rollup.EarliestStartDate = subtask.StartDate
+		}
+		if subtask.DueDate.After(rollup.LatestDueDate) {
+			rollup.LatestDueDate = subtask.DueDate
+		}
+	}
+
+	return rollup
+}
@@ -0,0 +1,47 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollupSubtasks(t *testing.T) {
+	t.Run("no subtasks", func(t *testing.T) {
+		assert.Nil(t, rollupSubtasks(nil))
+	})
+	t.Run("subtasks", func(t *testing.T) {
+		early := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+		late := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+
+		rollup := rollupSubtasks([]*Task{
+			{ID: 1, Done: true, Estimate: 3600, StartDate: late},
+			{ID: 2, Estimate: 1800, StartDate: early, DueDate: early},
+			{ID: 3, DueDate: late},
+		})
+		assert.Equal(t, &TaskSubtaskRollup{
+			Total:             3,
+			Done:              1,
+			Estimate:          5400,
+			EarliestStartDate: early,
+			LatestDueDate:     late,
+		}, rollup)
+	})
+}
@@ -0,0 +1,193 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web"
+
+	"xorm.io/xorm"
+)
+
+// TaskVote is an upvote of a user or link share on a task
+type TaskVote struct {
+	// The unique numeric id of this vote
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"-"`
+	// The task this vote belongs to
+	TaskID int64 `xorm:"bigint not null unique(task_user)" json:"-" param:"task"`
+
+	// The user who voted. Link shares are stored with their negative id.
+	UserID int64      `xorm:"bigint not null unique(task_user)" json:"-"`
+	User   *user.User `xorm:"-" json:"user"`
+
+	// A timestamp when this vote was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName returns the table name for task votes
+func (*TaskVote) TableName() string {
+	return "task_votes"
+}
+
+// updateTaskVoteCount stores the current number of votes with the task so it can be sorted and filtered by.
+func updateTaskVoteCount(s *xorm.Session, taskID int64, doer *user.User) (err error) {
+	votes, err := s.Where("task_id = ?", taskID).Count(&TaskVote{})
+	if err != nil {
+		return
+	}
+
+	// The task itself did not change, so its updated timestamp should not change either
+	_, err = s.
+		ID(taskID).
+		Cols("votes").
+		NoAutoTime().
+		Update(&Task{Votes: votes})
+	if err != nil {
+		return
+	}
+
+	// Search indexes and webhooks need to know about the new vote count
+	t, err := GetTaskByIDSimple(s, taskID)
+	if err != nil {
+		return
+	}
+	return events.Dispatch(&TaskUpdatedEvent{
+		Task: &t,
+		Doer: doer,
+	})
+}
+
+// ReadAll returns all votes of a task
+// @Summary Get all votes of a task
+// @Description Returns everyone who voted for a task.
+// @tags task
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Success 200 {array} models.TaskVote "The votes"
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task"
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/votes [get]
+func (v *TaskVote) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	can, _, err := v.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	votes := []*TaskVote{}
+	err = s.
+		Where("task_id = ?", v.TaskID).
+		Limit(getLimitFromPageIndex(page, perPage)).
+		OrderBy("created asc, id asc").
+		Find(&votes)
+	if err != nil {
+		return
+	}
+
+	userIDs := make([]int64, 0, len(votes))
+	for _, vote := range votes {
+		userIDs = append(userIDs, vote.UserID)
+	}
+
+	users, err := getUsersOrLinkSharesFromIDs(s, userIDs)
+	if err != nil {
+		return
+	}
+
+	for _, vote := range votes {
+		vote.User = users[vote.UserID]
+		if vote.User != nil {
+			vote.User.Email = ""
+		}
+	}
+
+	numberOfTotalItems, err = s.Where("task_id = ?", v.TaskID).Count(&TaskVote{})
+	return votes, len(votes), numberOfTotalItems, err
+}
+
+// Create adds the vote of the current user to a task
+// @Summary Vote for a task
+// @Description Adds the vote of the current user or link share to a task. Will do nothing if they already voted for the task.
+// @tags task
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Success 201 {object} models.TaskVote "The vote"
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task"
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/votes [put]
+func (v *TaskVote) Create(s *xorm.Session, a web.Auth) (err error) {
+	v.User, err = GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return err
+	}
+	v.ID = 0
+	v.UserID = v.User.ID
+	v.User.Email = ""
+
+	exists, err := s.
+		Where("task_id = ? AND user_id = ?", v.TaskID, v.UserID).
+		Exist(&TaskVote{})
+	if err != nil || exists {
+		return err
+	}
+
+	_, err = s.Insert(v)
+	if err != nil {
+		return
+	}
+
+	return updateTaskVoteCount(s, v.TaskID, v.User)
+}
+
+// Delete removes the vote of the current user from a task
+// @Summary Remove a vote
+// @Description Removes the vote of the current user or link share from a task.
+// @tags task
+// @Produce json
+// @Security JWTKeyAuth
+// @Param taskID path int true "Task ID"
+// @Success 200 {object} models.Message "The vote was successfully removed."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task"
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{taskID}/votes [delete]
+func (v *TaskVote) Delete(s *xorm.Session, a web.Auth) (err error) {
+	voter, err := GetUserOrLinkShareUser(s, a)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.
+		Where("task_id = ? AND user_id = ?", v.TaskID, voter.ID).
+		Delete(&TaskVote{})
+	if err != nil || deleted == 0 {
+		return
+	}
+
+	return updateTaskVoteCount(s, v.TaskID, voter)
+}
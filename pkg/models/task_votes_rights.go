@@ -0,0 +1,62 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if a user can see who voted for a task
+func (v *TaskVote) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	t := &Task{ID: v.TaskID}
+	return t.CanRead(s, a)
+}
+
+// CanCreate checks if a user can vote for a task. Everyone who can see a task can vote for it.
+func (v *TaskVote) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
+	return v.canVote(s, a)
+}
+
+// CanDelete checks if a user can remove their vote from a task
+func (v *TaskVote) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
+	return v.canVote(s, a)
+}
+
+// canVote checks read access to the task. A vote changes the vote count of the task, so like all other changes it
+// is not possible in archived or frozen projects.
+func (v *TaskVote) canVote(s *xorm.Session, a web.Auth) (bool, error) {
+	t, err := GetTaskByIDSimple(s, v.TaskID)
+	if err != nil {
+		return false, err
+	}
+
+	can, _, err := t.CanRead(s, a)
+	if err != nil || !can {
+		return false, err
+	}
+
+	p, err := GetProjectSimpleByID(s, t.ProjectID)
+	if err != nil {
+		return false, err
+	}
+	err = p.CheckIsArchived(s)
+	if err != nil {
+		return false, err
+	}
+	return true, p.checkIsFrozen(s)
+}
@@ -0,0 +1,134 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/builder"
+)
+
+func TestTaskVote_Create(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		events.Fake()
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 1}
+		vote := &TaskVote{TaskID: 1}
+		can, err := vote.CanCreate(s, u)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = vote.Create(s, u)
+		require.NoError(t, err)
+
+		// Voting twice does not count twice
+		err = (&TaskVote{TaskID: 1}).Create(s, u)
+		require.NoError(t, err)
+
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertCount(t, "task_votes", builder.Eq{"task_id": 1}, 1)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":    1,
+			"votes": 1,
+		}, false)
+		events.AssertDispatched(t, &TaskUpdatedEvent{})
+	})
+	t.Run("link share", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		share := &LinkSharing{ID: 1, ProjectID: 1, Right: RightRead}
+		vote := &TaskVote{TaskID: 1}
+		can, err := vote.CanCreate(s, share)
+		require.NoError(t, err)
+		assert.True(t, can)
+		err = vote.Create(s, share)
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "task_votes", map[string]interface{}{
+			"task_id": 1,
+			"user_id": -1,
+		}, false)
+	})
+	t.Run("no access", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		vote := &TaskVote{TaskID: 1}
+		can, err := vote.CanCreate(s, &user.User{ID: 2})
+		require.NoError(t, err)
+		assert.False(t, can)
+	})
+	t.Run("archived project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(1).Cols("is_archived").Update(&Project{IsArchived: true})
+		require.NoError(t, err)
+
+		_, err = (&TaskVote{TaskID: 1}).CanCreate(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrProjectIsArchived(err))
+	})
+	t.Run("frozen project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := s.ID(1).Cols("is_frozen").Update(&Project{IsFrozen: true})
+		require.NoError(t, err)
+
+		_, err = (&TaskVote{TaskID: 1}).CanDelete(s, &user.User{ID: 1})
+		require.Error(t, err)
+		assert.True(t, IsErrProjectIsFrozen(err))
+	})
+}
+
+func TestTaskVote_Delete(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	u := &user.User{ID: 1}
+	err := (&TaskVote{TaskID: 1}).Create(s, u)
+	require.NoError(t, err)
+	err = (&TaskVote{TaskID: 1}).Delete(s, u)
+	require.NoError(t, err)
+	err = s.Commit()
+	require.NoError(t, err)
+
+	db.AssertMissing(t, "task_votes", map[string]interface{}{"task_id": 1})
+	db.AssertExists(t, "tasks", map[string]interface{}{
+		"id":    1,
+		"votes": 0,
+	}, false)
+}
@@ -57,7 +57,12 @@ type Task struct {
 	// Whether a task is done or not.
 	Done bool `xorm:"INDEX null" json:"done"`
 	// The time when a task was marked as done.
-	DoneAt time.Time `xorm:"INDEX null 'done_at'" json:"done_at"`
+	DoneAt   time.Time `xorm:"INDEX null 'done_at'" json:"done_at"`
+	DoneByID int64     `xorm:"bigint null" json:"-"`
+	// The user who marked this task as done.
+	DoneBy *user.User `xorm:"-" json:"done_by"`
+	// The client the task was marked as done from. Can be one of "web", "caldav", "api_token" or "link_share".
+	DoneVia TaskDoneVia `xorm:"varchar(20) null" json:"done_via"`
 	// The time when the task is due.
 	DueDate time.Time `xorm:"DATETIME INDEX null 'due_date'" json:"due_date"`
 	// An array of reminders that are associated with this task.
@@ -82,6 +87,12 @@ type Task struct {
 	HexColor string `xorm:"varchar(6) null" json:"hex_color" valid:"runelength(0|7)" maxLength:"7"`
 	// Determines how far a task is left from being done
 	PercentDone float64 `xorm:"DOUBLE null" json:"percent_done"`
+	// How long the task is estimated to take, in seconds.
+	Estimate int64 `xorm:"bigint not null default 0" json:"estimate"`
+	// The number of votes this task has. Use the votes endpoints to vote for a task.
+	Votes int64 `xorm:"bigint not null default 0" json:"votes"`
+	// Whether the task is waiting for an approver of its project to approve it being done. 0 = No approval needed, 1 = Pending, 2 = Approved, 3 = Rejected. Use the approval endpoints to change it.
+	ApprovalStatus TaskApprovalStatus `xorm:"int not null default 0" json:"approval_status"`
 
 	// The task identifier, based on the project identifier and the task's index
 	Identifier string `xorm:"-" json:"identifier"`
@@ -103,6 +114,12 @@ type Task struct {
 	// True if a task is a favorite task. Favorite tasks show up in a separate "Important" project. This value depends on the user making the call to the api.
 	IsFavorite bool `xorm:"-" json:"is_favorite"`
 
+	// True if the task changed since the user making the call to the api opened it the last time.
+	IsUnread bool `xorm:"-" json:"is_unread"`
+
+	// Where the search text was found in this task, only returned when searching.
+	SearchMatches []*TaskSearchMatch `xorm:"-" json:"search_matches,omitempty"`
+
 	// The subscription status for the user reading this task. You can only read this property, use the subscription endpoints to modify it.
 	// Will only returned when retrieving one task.
 	Subscription *Subscription `xorm:"-" json:"subscription,omitempty"`
@@ -125,6 +142,14 @@ type Task struct {
 	// Reactions on that task.
 	Reactions ReactionMap `xorm:"-" json:"reactions"`
 
+	// If true and the due date of the task changes, the start, end and due dates of all tasks this task is blocking are moved by the same amount. This continues with the tasks those are blocking. Only used when updating a task.
+	ShiftDependentTasks bool `xorm:"-" json:"shift_dependent_tasks,omitempty"`
+	// The tasks whose dates were moved because shift_dependent_tasks was set. Only returned when updating a task.
+	ShiftedTasks []*Task `xorm:"-" json:"shifted_tasks,omitempty"`
+
+	// Aggregated information about the direct subtasks of this task. Only present if the task has subtasks.
+	SubtaskRollup *TaskSubtaskRollup `xorm:"-" json:"subtask_rollup,omitempty"`
+
 	// The user who initially created the task.
 	CreatedBy   *user.User `xorm:"-" json:"created_by" valid:"-"`
 	CreatedByID int64      `xorm:"bigint not null" json:"-"` // ID of the user who put that task on the project
@@ -172,12 +197,14 @@ type taskSearchOptions struct {
 	perPage            int
 	sortby             []*sortParam
 	parsedFilters      []*taskFilter
+	queryFilters       []*taskFilter
 	filterIncludeNulls bool
 	filter             string
 	filterTimezone     string
 	isSavedFilter      bool
 	projectIDs         []int64
 	expand             TaskCollectionExpandable
+	searchIn           []string
 }
 
 // ReadAll is a dummy function to still have that endpoint documented
@@ -189,11 +216,14 @@ type taskSearchOptions struct {
 // @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
 // @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
 // @Param s query string false "Search tasks by task text."
-// @Param sort_by query string false "The sorting parameter. You can pass this multiple times to get the tasks ordered by multiple different parametes, along with `order_by`. Possible values to sort by are `id`, `title`, `description`, `done`, `done_at`, `due_date`, `created_by_id`, `project_id`, `repeat_after`, `priority`, `start_date`, `end_date`, `hex_color`, `percent_done`, `uid`, `created`, `updated`. Default is `id`."
+// @Param sort_by query string false "The sorting parameter. You can pass this multiple times to get the tasks ordered by multiple different parametes, along with `order_by`. Possible values to sort by are `id`, `title`, `description`, `done`, `done_at`, `due_date`, `created_by_id`, `project_id`, `repeat_after`, `priority`, `start_date`, `end_date`, `hex_color`, `percent_done`, `votes`, `uid`, `created`, `updated`. Default is `id`."
 // @Param order_by query string false "The ordering parameter. Possible values to order by are `asc` or `desc`. Default is `asc`."
 // @Param filter query string false "The filter query to match tasks by. Check out https://vikunja.io/docs/filters for a full explanation of the feature."
 // @Param filter_timezone query string false "The time zone which should be used for date match (statements like "now" resolve to different actual times)"
 // @Param filter_include_nulls query string false "If set to true the result will include filtered fields whose value is set to `null`. Available values are `true` or `false`. Defaults to `false`."
+// @Param q query string false "A search query like `assignee:jan label:bug due:<2024-06-01 has:attachment`. Terms are combined with `AND` unless separated by `OR` and can be grouped with parentheses. Terms without a field search the title and description."
+// @Param search_in query string false "Where else to search besides the title and description. Can be `comments` or `attachments` and be passed multiple times. Every task then contains the places the search text was found in `search_matches`."
+// @Param include_archived query bool false "If set to true, tasks of archived projects and their child projects are included. Defaults to `false`."
 // @Param expand query string false "If set to `subtasks`, Vikunja will fetch only tasks which do not have subtasks and then in a second step, will fetch all of these subtasks. This may result in more tasks than the pagination limit being returned, but all subtasks will be present in the response. You can only set this to `subtasks`."
 // @Security JWTKeyAuth
 // @Success 200 {array} models.Task "The tasks"
@@ -294,7 +324,8 @@ func getRawTasksForProjects(s *xorm.Session, projects []*Project, a web.Auth, op
 		a:                   a,
 		hasFavoritesProject: hasFavoritesProject,
 	}
-	if config.TypesenseEnabled.GetBool() {
+	// Typesense does not know about the project hierarchy or attachments, filters on them need to be resolved by the database
+	if isTypesenseEnabled() && !needsDatabaseFilter(opts.getFilters()) && !searchesIn(opts.searchIn, TaskSearchInAttachments) {
 		searcher = &typesenseTaskSearcher{
 			s: s,
 		}
@@ -306,6 +337,37 @@ func getRawTasksForProjects(s *xorm.Session, projects []*Project, a web.Auth, op
 	return tasks, len(tasks), totalItems, err
 }
 
+// getFilters returns the filters from the filter query combined with the ones from the search query.
+func (opts *taskSearchOptions) getFilters() []*taskFilter {
+	if len(opts.queryFilters) == 0 {
+		return opts.parsedFilters
+	}
+	if len(opts.parsedFilters) == 0 {
+		return opts.queryFilters
+	}
+
+	return []*taskFilter{
+		{value: opts.parsedFilters, join: filterConcatAnd},
+		{value: opts.queryFilters, join: filterConcatAnd},
+	}
+}
+
+func needsDatabaseFilter(filters []*taskFilter) bool {
+	for _, f := range filters {
+		if nested, is := f.value.([]*taskFilter); is {
+			if needsDatabaseFilter(nested) {
+				return true
+			}
+			continue
+		}
+		switch f.field {
+		case "project_tree", "parent_project", "parent_project_id", "attachments":
+			return true
+		}
+	}
+	return false
+}
+
 func getTasksForProjects(s *xorm.Session, projects []*Project, a web.Auth, opts *taskSearchOptions, view *ProjectView) (tasks []*Task, resultCount int, totalItems int64, err error) {
 
 	tasks, resultCount, totalItems, err = getRawTasksForProjects(s, projects, a, opts)
@@ -323,6 +385,11 @@ func getTasksForProjects(s *xorm.Session, projects []*Project, a web.Auth, opts
 		return nil, 0, 0, err
 	}
 
+	err = addSearchMatchesToTasks(s, taskMap, opts.search, opts.searchIn)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
 	return tasks, resultCount, totalItems, err
 }
 
@@ -531,6 +598,10 @@ func addRelatedTasksToTasks(s *xorm.Session, taskIDs []int64, taskMap map[int64]
 		taskMap[rt.TaskID].RelatedTasks[rt.RelationKind] = append(taskMap[rt.TaskID].RelatedTasks[rt.RelationKind], otherTask)
 	}
 
+	for _, task := range taskMap {
+		task.SubtaskRollup = rollupSubtasks(task.RelatedTasks[RelationKindSubtask])
+	}
+
 	return
 }
 
@@ -550,6 +621,9 @@ func addMoreInfoToTasks(s *xorm.Session, taskMap map[int64]*Task, a web.Auth, vi
 	for _, i := range taskMap {
 		taskIDs = append(taskIDs, i.ID)
 		userIDs = append(userIDs, i.CreatedByID)
+		if i.DoneByID != 0 {
+			userIDs = append(userIDs, i.DoneByID)
+		}
 		projectIDs = append(projectIDs, i.ProjectID)
 	}
 
@@ -583,6 +657,11 @@ func addMoreInfoToTasks(s *xorm.Session, taskMap map[int64]*Task, a web.Auth, vi
 		return err
 	}
 
+	unreadTasks, err := getUnreadTasks(s, taskMap, taskIDs, a)
+	if err != nil {
+		return err
+	}
+
 	// Get all identifiers
 	projects, err := GetProjectsMapByIDs(s, projectIDs)
 	if err != nil {
@@ -610,9 +689,17 @@ func addMoreInfoToTasks(s *xorm.Session, taskMap map[int64]*Task, a web.Auth, vi
 
 		// Make created by user objects
 		task.CreatedBy = users[task.CreatedByID]
+		if task.DoneByID != 0 {
+			task.DoneBy = users[task.DoneByID]
+		}
 
-		// Add the reminders
+		// Add the reminders. Relative reminders always reflect the current dates of the task.
 		task.Reminders = taskReminders[task.ID]
+		for _, r := range task.Reminders {
+			if r.isRelative() {
+				r.Reminder = r.resolveRelativeReminder(task)
+			}
+		}
 
 		// Prepare the subtasks
 		task.RelatedTasks = make(RelatedTaskMap)
@@ -621,6 +708,7 @@ func addMoreInfoToTasks(s *xorm.Session, taskMap map[int64]*Task, a web.Auth, vi
 		task.setIdentifier(projects[task.ProjectID])
 
 		task.IsFavorite = taskFavorites[task.ID]
+		task.IsUnread = unreadTasks[task.ID]
 
 		r, has := reactions[task.ID]
 		if has {
@@ -638,23 +726,6 @@ func addMoreInfoToTasks(s *xorm.Session, taskMap map[int64]*Task, a web.Auth, vi
 	return
 }
 
-// Checks if adding a new task would exceed the bucket limit
-func checkBucketLimit(s *xorm.Session, t *Task, bucket *Bucket) (err error) {
-	if bucket.Limit > 0 {
-		taskCount, err := s.
-			Where("bucket_id = ?", bucket.ID).
-			GroupBy("task_id").
-			Count(&TaskBucket{})
-		if err != nil {
-			return err
-		}
-		if taskCount >= bucket.Limit {
-			return ErrBucketLimitExceeded{TaskID: t.ID, BucketID: bucket.ID, Limit: bucket.Limit}
-		}
-	}
-	return nil
-}
-
 func calculateDefaultPosition(entityID int64, position float64) float64 {
 	if position == 0 {
 		return float64(entityID) * math.Pow(2, 16)
@@ -688,15 +759,18 @@ func getNextTaskIndex(s *xorm.Session, projectID int64) (nextIndex int64, err er
 // @Success 201 {object} models.Task "The created task object."
 // @Failure 400 {object} web.HTTPError "Invalid task object provided."
 // @Failure 403 {object} web.HTTPError "The user does not have access to the project"
+// @Failure 412 {object} web.HTTPError "The project or the user reached their limit of tasks."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /projects/{id}/tasks [put]
 func (t *Task) Create(s *xorm.Session, a web.Auth) (err error) {
-	return createTask(s, t, a, true, true)
+	return createTask(s, t, a, true, true, true)
 }
 
-func createTask(s *xorm.Session, t *Task, a web.Auth, updateAssignees bool, setBucket bool) (err error) {
+func createTask(s *xorm.Session, t *Task, a web.Auth, updateAssignees bool, setBucket bool, applyDefaults bool) (err error) {
 
 	t.ID = 0
+	t.Votes = 0
+	t.ApprovalStatus = TaskApprovalStatusNone
 
 	// Check if we have at least a title
 	if t.Title == "" {
@@ -709,6 +783,30 @@ func createTask(s *xorm.Session, t *Task, a web.Auth, updateAssignees bool, setB
 		return err
 	}
 
+	// Tasks are not only created through the api where the rights check catches this, but also from forms and mails
+	err = p.checkIsFrozen(s)
+	if err != nil {
+		return err
+	}
+
+	// Tasks created as done in projects with approvers are only done once one of them approved it
+	approvalRequested, err := t.updateApprovalStatus(s, &Task{ProjectID: t.ProjectID}, a)
+	if err != nil {
+		return err
+	}
+
+	if !t.Done {
+		err = p.checkOpenTaskQuota(s)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = checkUserTaskQuota(s, a)
+	if err != nil {
+		return err
+	}
+
 	createdBy, err := GetUserOrLinkShareUser(s, a)
 	if err != nil {
 		return err
@@ -728,6 +826,10 @@ func createTask(s *xorm.Session, t *Task, a web.Auth, updateAssignees bool, setB
 
 	t.HexColor = utils.NormalizeHex(t.HexColor)
 
+	if applyDefaults {
+		p.TaskDefaults.applyBeforeCreate(t)
+	}
+
 	_, err = s.Insert(t)
 	if err != nil {
 		return err
@@ -796,6 +898,12 @@ func createTask(s *xorm.Session, t *Task, a web.Auth, updateAssignees bool, setB
 		return err
 	}
 
+	if applyDefaults {
+		if err := p.TaskDefaults.applyAfterCreate(s, t, p, a); err != nil {
+			return err
+		}
+	}
+
 	t.setIdentifier(p)
 
 	if t.IsFavorite {
@@ -812,6 +920,16 @@ func createTask(s *xorm.Session, t *Task, a web.Auth, updateAssignees bool, setB
 		return err
 	}
 
+	if approvalRequested {
+		err = events.Dispatch(&TaskApprovalRequestedEvent{
+			Task: t,
+			Doer: createdBy,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	err = updateProjectLastUpdated(s, &Project{ID: t.ProjectID})
 	return
 }
@@ -844,6 +962,10 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 		t.ProjectID = ot.ProjectID
 	}
 
+	oldDueDate := ot.DueDate
+	shiftDependentTasks := t.ShiftDependentTasks
+	t.ShiftedTasks = nil
+
 	// Get the stored reminders
 	reminders, err := getRemindersForTasks(s, []int64{t.ID})
 	if err != nil {
@@ -870,11 +992,15 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 		"end_date",
 		"hex_color",
 		"done_at",
+		"done_by_id",
+		"done_via",
 		"percent_done",
+		"estimate",
 		"project_id",
 		"bucket_id",
 		"repeat_mode",
 		"cover_image_attachment_id",
+		"approval_status",
 	}
 
 	// If the task is being moved between projects, make sure to move the bucket + index as well
@@ -887,6 +1013,20 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 		colsToUpdate = append(colsToUpdate, "index")
 	}
 
+	// Tasks in projects with approvers are only done once one of them approved it
+	approvalRequested, err := t.updateApprovalStatus(s, &ot, a)
+	if err != nil {
+		return err
+	}
+
+	// An undone task counts towards the open task quota of its project, no matter how it got there
+	if !t.Done && (ot.Done || t.ProjectID != ot.ProjectID) {
+		err = (&Project{ID: t.ProjectID}).checkOpenTaskQuota(s)
+		if err != nil {
+			return err
+		}
+	}
+
 	// When a task was marked done or moved between projects, make sure it is in the correct bucket
 	if t.Done != ot.Done || t.ProjectID != ot.ProjectID {
 		views, err := getViewsForProject(s, t.ProjectID)
@@ -899,6 +1039,11 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 				continue
 			}
 
+			// Generated buckets follow the labels, assignees or priority of a task, not its done state
+			if view.BucketConfigurationMode.groupedBy() != SwimlaneModeNone {
+				continue
+			}
+
 			var bucketID = view.DoneBucketID
 			if bucketID == 0 || t.ProjectID != ot.ProjectID {
 				bucketID, err = getDefaultBucketID(s, view)
@@ -923,6 +1068,16 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 				return err
 			}
 
+			// The actions of the new bucket may have changed the task as well, those changes must not be
+			// overwritten with the values from the request.
+			if tb.Task != nil {
+				t.Done = tb.Task.Done
+				t.DoneAt = tb.Task.DoneAt
+				t.DueDate = tb.Task.DueDate
+				t.StartDate = tb.Task.StartDate
+				t.EndDate = tb.Task.EndDate
+			}
+
 			tp := TaskPosition{
 				TaskID:        t.ID,
 				ProjectViewID: view.ID,
@@ -935,6 +1090,13 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 		}
 	}
 
+	// Who completed a task is only ever set here, never taken from the request
+	markedDone := !ot.Done && t.Done
+	markedUndone := ot.Done && !t.Done
+	t.DoneByID = ot.DoneByID
+	t.DoneBy = nil
+	t.DoneVia = ot.DoneVia
+
 	// When a repeating task is marked as done, we update all deadlines and reminders and set it as undone
 	updateDone(&ot, t)
 
@@ -960,6 +1122,13 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 		}
 	}
 
+	// Keep the previous description around so it can be restored after an accidental overwrite
+	if t.Description != ot.Description {
+		if err := saveTaskDescriptionRevision(s, &ot, a); err != nil {
+			return err
+		}
+	}
+
 	wasFavorite, err := isFavorite(s, t.ID, a, FavoriteKindTask)
 	if err != nil {
 		return
@@ -1042,6 +1211,10 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 	if t.PercentDone == 0 {
 		ot.PercentDone = 0
 	}
+	// Estimate
+	if t.Estimate == 0 {
+		ot.Estimate = 0
+	}
 	// Repeat from current date
 	if t.RepeatMode == TaskRepeatModeDefault {
 		ot.RepeatMode = TaskRepeatModeDefault
@@ -1054,6 +1227,17 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 	if t.CoverImageAttachmentID == 0 {
 		ot.CoverImageAttachmentID = 0
 	}
+	// Approval status
+	if t.ApprovalStatus == TaskApprovalStatusNone {
+		ot.ApprovalStatus = TaskApprovalStatusNone
+	}
+	// Completion metadata
+	if markedDone {
+		ot.setDoneBy(a)
+	}
+	if markedUndone {
+		ot.setDoneBy(nil)
+	}
 
 	_, err = s.ID(t.ID).
 		Cols(colsToUpdate...).
@@ -1072,6 +1256,19 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 	}
 	t.Updated = nt.Updated
 
+	// Users know about their own changes
+	err = markTasksAsSeen(s, a, []int64{t.ID})
+	if err != nil {
+		return err
+	}
+
+	if shiftDependentTasks && !oldDueDate.IsZero() && !t.DueDate.IsZero() && !t.DueDate.Equal(oldDueDate) {
+		t.ShiftedTasks, err = shiftDependentTaskDates(s, t.ID, t.DueDate.Sub(oldDueDate), a)
+		if err != nil {
+			return err
+		}
+	}
+
 	doer, _ := user.GetFromAuth(a)
 	err = events.Dispatch(&TaskUpdatedEvent{
 		Task: t,
@@ -1081,6 +1278,35 @@ func (t *Task) Update(s *xorm.Session, a web.Auth) (err error) {
 		return err
 	}
 
+	if approvalRequested {
+		// Link shares can mark tasks as done too, the approvers should still know who it was
+		requester, err := GetUserOrLinkShareUser(s, a)
+		if err != nil {
+			return err
+		}
+		err = events.Dispatch(&TaskApprovalRequestedEvent{
+			Task: t,
+			Doer: requester,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if markedDone {
+		doneBy, err := GetUserOrLinkShareUser(s, a)
+		if err != nil {
+			return err
+		}
+		err = events.Dispatch(&TaskMarkedDoneEvent{
+			Task: t,
+			Doer: doneBy,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	return updateProjectLastUpdated(s, &Project{ID: t.ProjectID})
 }
 
@@ -1264,23 +1490,9 @@ func updateDone(oldTask *Task, newTask *Task) {
 // Set the absolute trigger dates for Reminders with relative period
 func updateRelativeReminderDates(task *Task) (err error) {
 	for _, reminder := range task.Reminders {
-		relativeDuration := time.Duration(reminder.RelativePeriod) * time.Second
-		if reminder.RelativeTo != "" {
-			reminder.Reminder = time.Time{}
-		}
 		switch reminder.RelativeTo {
-		case ReminderRelationDueDate:
-			if !task.DueDate.IsZero() {
-				reminder.Reminder = task.DueDate.Add(relativeDuration)
-			}
-		case ReminderRelationStartDate:
-			if !task.StartDate.IsZero() {
-				reminder.Reminder = task.StartDate.Add(relativeDuration)
-			}
-		case ReminderRelationEndDate:
-			if !task.EndDate.IsZero() {
-				reminder.Reminder = task.EndDate.Add(relativeDuration)
-			}
+		case ReminderRelationDueDate, ReminderRelationStartDate, ReminderRelationEndDate:
+			reminder.Reminder = reminder.resolveRelativeReminder(task)
 		default:
 			if reminder.RelativePeriod != 0 {
 				err = ErrReminderRelativeToMissing{
@@ -1306,6 +1518,12 @@ func (t *Task) updateReminders(s *xorm.Session, task *Task) (err error) {
 		return
 	}
 
+	for _, reminder := range task.Reminders {
+		if err := reminder.validateRepeat(t.ID); err != nil {
+			return err
+		}
+	}
+
 	err = updateRelativeReminderDates(task)
 	if err != nil {
 		return
@@ -1322,10 +1540,17 @@ func (t *Task) updateReminders(s *xorm.Session, task *Task) (err error) {
 	// Loop through all reminders and add them
 	for _, r := range reminderMap {
 		taskReminder := &TaskReminder{
-			TaskID:         t.ID,
-			Reminder:       r.Reminder,
-			RelativePeriod: r.RelativePeriod,
-			RelativeTo:     r.RelativeTo}
+			TaskID:            t.ID,
+			Reminder:          r.Reminder,
+			RelativePeriod:    r.RelativePeriod,
+			RelativeTo:        r.RelativeTo,
+			RepeatInterval:    r.RepeatInterval,
+			RepeatBackoff:     r.RepeatBackoff,
+			RepeatMaxInterval: r.RepeatMaxInterval,
+		}
+		if taskReminder.needsNextTrigger() && !taskReminder.Reminder.IsZero() {
+			taskReminder.NextTrigger = taskReminder.nextTrigger(taskReminder.Reminder, time.Now())
+		}
 		_, err = s.Insert(taskReminder)
 		if err != nil {
 			return err
@@ -1408,6 +1633,11 @@ func (t *Task) Delete(s *xorm.Session, a web.Auth) (err error) {
 		return
 	}
 
+	err = deleteTaskCommentRevisions(s, builder.Eq{"task_id": t.ID})
+	if err != nil {
+		return
+	}
+
 	// Delete all relations
 	_, err = s.Where("task_id = ? OR other_task_id = ?", t.ID, t.ID).Delete(&TaskRelation{})
 	if err != nil {
@@ -1420,12 +1650,56 @@ func (t *Task) Delete(s *xorm.Session, a web.Auth) (err error) {
 		return
 	}
 
+	// Delete all votes
+	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskVote{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("task_id = ?", t.ID).Delete(&InboxItem{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("task_id = ?", t.ID).Delete(&SavedFilterSubscriptionMatch{})
+	if err != nil {
+		return
+	}
+
+	// Remove it from everyone's day
+	_, err = s.Where("task_id = ?", t.ID).Delete(&MyDayTask{})
+	if err != nil {
+		return
+	}
+
+	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskLastSeen{})
+	if err != nil {
+		return
+	}
+
+	// Delete all sla breaches
+	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskSLABreach{})
+	if err != nil {
+		return
+	}
+
+	// Delete all description revisions
+	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskDescriptionRevision{})
+	if err != nil {
+		return
+	}
+
 	// Delete all positions
 	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskPosition{})
 	if err != nil {
 		return
 	}
 
+	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskSwimlanePosition{})
+	if err != nil {
+		return
+	}
+
 	// Delete all bucket relations
 	_, err = s.Where("task_id = ?", t.ID).Delete(&TaskBucket{})
 	if err != nil {
@@ -1487,6 +1761,11 @@ func (t *Task) ReadOne(s *xorm.Session, a web.Auth) (err error) {
 	if err != nil && IsErrProjectDoesNotExist(err) {
 		return nil
 	}
+	if err != nil {
+		return err
+	}
 
-	return
+	// The task is only marked as seen by the http handler, ReadOne is used internally too.
+	t.IsUnread = false
+	return nil
 }
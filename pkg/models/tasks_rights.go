@@ -23,19 +23,23 @@ import (
 
 // CanDelete checks if the user can delete an task
 func (t *Task) CanDelete(s *xorm.Session, a web.Auth) (bool, error) {
-	return t.canDoTask(s, a)
+	return t.canDoTaskWithCapability(s, a, CapabilityDeleteTasks)
 }
 
 // CanUpdate determines if a user has the right to update a project task
 func (t *Task) CanUpdate(s *xorm.Session, a web.Auth) (bool, error) {
-	return t.canDoTask(s, a)
+	return t.canDoTaskWithCapability(s, a, CapabilityEditTasks)
 }
 
 // CanCreate determines if a user has the right to create a project task
 func (t *Task) CanCreate(s *xorm.Session, a web.Auth) (bool, error) {
 	// A user can do a task if he has write acces to its project
 	l := &Project{ID: t.ProjectID}
-	return l.CanWrite(s, a)
+	can, err := l.CanWrite(s, a)
+	if err != nil || can {
+		return can, err
+	}
+	return l.hasCapability(s, a, CapabilityCreateTasks)
 }
 
 // CanRead determines if a user can read a task
@@ -80,3 +84,23 @@ func (t *Task) canDoTask(s *xorm.Session, a web.Auth) (bool, error) {
 	l := &Project{ID: ot.ProjectID}
 	return l.CanWrite(s, a)
 }
+
+// canDoTaskWithCapability checks if the user has write access to the task or got the capability through a role
+func (t *Task) canDoTaskWithCapability(s *xorm.Session, a web.Auth, capability Capability) (bool, error) {
+	can, err := t.canDoTask(s, a)
+	if err != nil || can {
+		return can, err
+	}
+
+	ot, err := GetTaskByIDSimple(s, t.ID)
+	if err != nil {
+		return false, err
+	}
+
+	// Roles only apply within a project, moving tasks elsewhere always needs write access
+	if t.ProjectID != 0 && t.ProjectID != ot.ProjectID {
+		return false, nil
+	}
+
+	return (&Project{ID: ot.ProjectID}).hasCapability(s, a, capability)
+}
@@ -39,9 +39,10 @@ type Team struct {
 	// The team's description.
 	Description string `xorm:"longtext null" json:"description"`
 	CreatedByID int64  `xorm:"bigint not null INDEX" json:"-"`
-	// The team's oidc id delivered by the oidc provider
+	// The team's oidc id delivered by the oidc provider. For teams synced from ldap, this is the dn of the group.
 	OidcID string `xorm:"varchar(250) null" maxLength:"250" json:"oidc_id"`
-	// Contains the issuer extracted from the vikunja_groups claim if this team was created through oidc
+	// Contains the issuer extracted from the vikunja_groups claim if this team was created through oidc or "ldap"
+	// if it was created from an ldap group
 	Issuer string `xorm:"text null" json:"-"`
 
 	// The user who created this team.
@@ -167,6 +168,19 @@ func FindAllOidcTeamIDsForUser(s *xorm.Session, userID int64) (ts []int64, err e
 	return ts, nil
 }
 
+// FindAllTeamIDsForUserAndIssuer returns the ids of all teams of a user which were created by an external auth provider
+// with that issuer.
+func FindAllTeamIDsForUserAndIssuer(s *xorm.Session, userID int64, issuer string) (ts []int64, err error) {
+	ts = []int64{}
+	err = s.
+		Table("team_members").
+		Join("INNER", "teams", "teams.id = team_members.team_id").
+		Where("team_members.user_id = ? AND teams.issuer = ?", userID, issuer).
+		Cols("teams.id").
+		Find(&ts)
+	return
+}
+
 func addMoreInfoToTeams(s *xorm.Session, teams []*Team) (err error) {
 
 	if len(teams) == 0 {
@@ -388,6 +402,16 @@ func (t *Team) Delete(s *xorm.Session, a web.Auth) (err error) {
 		return
 	}
 
+	_, err = s.Where("team_id = ?", t.ID).Delete(&SavedFilterShare{})
+	if err != nil {
+		return
+	}
+
+	err = makeSharedLabelsPersonal(s, builder.Eq{"team_id": t.ID})
+	if err != nil {
+		return
+	}
+
 	return events.Dispatch(&TeamDeletedEvent{
 		Team: t,
 		Doer: a,
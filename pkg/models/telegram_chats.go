@@ -0,0 +1,344 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/api/pkg/utils"
+
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// TelegramChat links a user to the chat they have with the telegram bot
+type TelegramChat struct {
+	ID     int64 `xorm:"bigint autoincr not null unique pk" json:"-"`
+	UserID int64 `xorm:"bigint not null unique" json:"-"`
+	// The id of the telegram chat. 0 until the user has sent the link token to the bot.
+	ChatID int64 `xorm:"bigint not null default 0 INDEX" json:"-"`
+	// Send `/start <link_token>` to the bot to link your telegram account. Only set until the account is linked.
+	LinkToken string `xorm:"varchar(40) null INDEX" json:"link_token"`
+	// Whether the telegram account is linked and receives notifications.
+	Linked bool `xorm:"-" json:"linked"`
+
+	// A timestamp when the link was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+// TableName holds the table name
+func (*TelegramChat) TableName() string {
+	return "telegram_chats"
+}
+
+// ReadOne returns the telegram link of the current user
+// @Summary Get the telegram link
+// @Description Returns whether the current user has linked a telegram account and, if they did not send it to the bot yet, the link token.
+// @tags user
+// @Produce json
+// @Security JWTKeyAuth
+// @Success 200 {object} models.TelegramChat "The telegram link."
+// @Failure 404 {object} web.HTTPError "The user has not linked a telegram account."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /user/settings/telegram [get]
+func (tc *TelegramChat) ReadOne(s *xorm.Session, a web.Auth) (err error) {
+	exists, err := s.Where("user_id = ?", a.GetID()).Get(tc)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrTelegramChatDoesNotExist{UserID: a.GetID()}
+	}
+	tc.Linked = tc.ChatID != 0
+	return nil
+}
+
+// Create starts linking a telegram account
+// @Summary Link a telegram account
+// @Description Generates a new link token. Send `/start <link_token>` to the bot to link the telegram account the message was sent from. A telegram account which was linked before is unlinked.
+// @tags user
+// @Produce json
+// @Security JWTKeyAuth
+// @Success 201 {object} models.TelegramChat "The new telegram link."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /user/settings/telegram [put]
+func (tc *TelegramChat) Create(s *xorm.Session, a web.Auth) (err error) {
+	_, err = s.Where("user_id = ?", a.GetID()).Delete(&TelegramChat{})
+	if err != nil {
+		return err
+	}
+
+	tc.ID = 0
+	tc.UserID = a.GetID()
+	tc.ChatID = 0
+	tc.Linked = false
+	tc.LinkToken, err = utils.CryptoRandomString(32)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Insert(tc)
+	return
+}
+
+// Delete unlinks the telegram account of the current user
+// @Summary Unlink the telegram account
+// @tags user
+// @Produce json
+// @Security JWTKeyAuth
+// @Success 200 {object} models.Message "The telegram account was unlinked."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /user/settings/telegram [delete]
+func (tc *TelegramChat) Delete(s *xorm.Session, a web.Auth) (err error) {
+	_, err = s.Where("user_id = ?", a.GetID()).Delete(&TelegramChat{})
+	return
+}
+
+// telegramTransport sends reminders and assignments to the telegram chat of a user
+type telegramTransport struct{}
+
+func (*telegramTransport) Channel() notifications.Channel {
+	return notifications.ChannelTelegram
+}
+
+func (*telegramTransport) Handles(notification notifications.Notification) bool {
+	switch notification.(type) {
+	case *ReminderDueNotification, *TaskAssignedNotification:
+		return true
+	}
+	return false
+}
+
+func (*telegramTransport) Send(notifiable notifications.Notifiable, notification notifications.Notification) (err error) {
+	s := db.NewSession()
+	defer s.Close()
+
+	chat := &TelegramChat{}
+	exists, err := s.
+		Where("user_id = ? AND chat_id != 0", notifiable.RouteForDB()).
+		Get(chat)
+	if err != nil || !exists {
+		return err
+	}
+
+	msg := notifications.NewChatMessage(notification, notifiable.Lang())
+	if msg == nil {
+		return nil
+	}
+
+	return notifications.SendTelegramMessage(getWebhookHTTPClient(), config.TelegramBotToken.GetString(), &notifications.TelegramSendMessage{
+		ChatID:    chat.ChatID,
+		Text:      msg.Telegram(),
+		ParseMode: "HTML",
+	})
+}
+
+// RegisterTelegramTransport sends reminders and assignments to linked telegram accounts
+func RegisterTelegramTransport() {
+	notifications.RegisterTransport(&telegramTransport{})
+}
+
+const telegramHelpText = `Reply "done" to a reminder or assignment to mark the task as done, or "snooze 1h" to get reminded again later. ` +
+	`Send me anything else to add it as a new task to your default project.`
+
+// HandleTelegramUpdate handles a message someone sent to the telegram bot and returns the answer to send back.
+// Returns nil if there is nothing to answer.
+func HandleTelegramUpdate(s *xorm.Session, update *notifications.TelegramUpdate) (answer *notifications.TelegramSendMessage, err error) {
+	if update.Message == nil || strings.TrimSpace(update.Message.Text) == "" {
+		return nil, nil
+	}
+
+	message := update.Message
+	text, err := handleTelegramMessage(s, message)
+	if err != nil || text == "" {
+		return nil, err
+	}
+
+	return &notifications.TelegramSendMessage{
+		Method:    "sendMessage",
+		ChatID:    message.Chat.ID,
+		Text:      text,
+		ParseMode: "HTML",
+	}, nil
+}
+
+func handleTelegramMessage(s *xorm.Session, message *notifications.TelegramMessage) (answer string, err error) {
+	text := strings.TrimSpace(message.Text)
+
+	if token, is := strings.CutPrefix(text, "/start"); is {
+		return linkTelegramChat(s, message.Chat.ID, strings.TrimSpace(token))
+	}
+
+	chat := &TelegramChat{}
+	exists, err := s.Where("chat_id = ?", message.Chat.ID).Get(chat)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "This chat is not linked to a Vikunja account. Link it in your Vikunja settings first.", nil
+	}
+
+	u, err := user.GetUserByID(s, chat.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	if message.ReplyToMessage != nil {
+		return handleTelegramTaskCommand(s, u, message.ReplyToMessage, text)
+	}
+
+	if text == "/help" {
+		return telegramHelpText, nil
+	}
+
+	return createTaskFromTelegram(s, u, text)
+}
+
+func linkTelegramChat(s *xorm.Session, chatID int64, token string) (answer string, err error) {
+	chat := &TelegramChat{}
+	exists, err := s.Where("link_token = ? AND chat_id = 0", token).Get(chat)
+	if err != nil {
+		return "", err
+	}
+	if token == "" || !exists {
+		return "This link is invalid. Please link your telegram account again in your Vikunja settings.", nil
+	}
+
+	// A telegram account can only be linked to one Vikunja account
+	_, err = s.Where("chat_id = ?", chatID).Delete(&TelegramChat{})
+	if err != nil {
+		return "", err
+	}
+
+	chat.ChatID = chatID
+	chat.LinkToken = ""
+	_, err = s.ID(chat.ID).Cols("chat_id", "link_token").Update(chat)
+	if err != nil {
+		return "", err
+	}
+
+	return "Your Vikunja account is now linked. You will get your reminders and assignments here.\n\n" + telegramHelpText, nil
+}
+
+// telegramMessageTaskID finds the task a message the bot sent is about through the link to the task
+func telegramMessageTaskID(message *notifications.TelegramMessage) int64 {
+	prefix := config.ServicePublicURL.GetString() + "tasks/"
+	for _, link := range message.LinkURLs() {
+		id, is := strings.CutPrefix(link, prefix)
+		if !is {
+			continue
+		}
+		taskID, err := strconv.ParseInt(id, 10, 64)
+		if err == nil {
+			return taskID
+		}
+	}
+	return 0
+}
+
+// parseSnoozeDuration parses durations like "30m", "2h" or "1d". Without a duration, tasks are snoozed for one hour.
+func parseSnoozeDuration(str string) (time.Duration, error) {
+	if str == "" {
+		return time.Hour, nil
+	}
+	if days, is := strings.CutSuffix(str, "d"); is {
+		d, err := strconv.Atoi(days)
+		return time.Duration(d) * 24 * time.Hour, err
+	}
+	return time.ParseDuration(str)
+}
+
+func handleTelegramTaskCommand(s *xorm.Session, u *user.User, repliedTo *notifications.TelegramMessage, text string) (answer string, err error) {
+	taskID := telegramMessageTaskID(repliedTo)
+	if taskID == 0 {
+		return "I don't know which task you mean. Reply to a reminder or assignment.", nil
+	}
+
+	task := &Task{ID: taskID}
+	can, err := task.CanUpdate(s, u)
+	if err != nil && !IsErrTaskDoesNotExist(err) {
+		return "", err
+	}
+	if !can {
+		return "You are not allowed to change this task.", nil
+	}
+
+	err = task.ReadOne(s, u)
+	if err != nil {
+		return "", err
+	}
+
+	command, argument, _ := strings.Cut(strings.ToLower(text), " ")
+	switch command {
+	case "done":
+		task.Done = true
+		err = task.Update(s, u)
+		if err != nil {
+			return "", err
+		}
+		return `Marked "` + html.EscapeString(task.Title) + `" as done.`, nil
+	case "snooze":
+		duration, err := parseSnoozeDuration(strings.TrimSpace(argument))
+		if err != nil || duration <= 0 {
+			return `I don't understand this duration. Try something like "snooze 30m", "snooze 2h" or "snooze 1d".`, nil
+		}
+		task.Reminders = append(task.Reminders, &TaskReminder{Reminder: time.Now().Add(duration)})
+		err = task.Update(s, u)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(`I will remind you of "%s" again in %s.`, html.EscapeString(task.Title), utils.HumanizeDuration(duration)), nil
+	}
+
+	return telegramHelpText, nil
+}
+
+func createTaskFromTelegram(s *xorm.Session, u *user.User, text string) (answer string, err error) {
+	if u.DefaultProjectID == 0 {
+		return "Set a default project in your Vikunja settings to add tasks from here.", nil
+	}
+
+	task := &Task{
+		Title:     text,
+		ProjectID: u.DefaultProjectID,
+	}
+	can, err := task.CanCreate(s, u)
+	if err != nil {
+		return "", err
+	}
+	if !can {
+		return "You are not allowed to add tasks to your default project.", nil
+	}
+
+	err = task.Create(s, u)
+	if err != nil {
+		return "", err
+	}
+
+	return `Added <a href="` + html.EscapeString(task.GetFrontendURL()) + `">` + html.EscapeString(task.Title) + `</a>.`, nil
+}
@@ -0,0 +1,43 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// CanRead checks if a user can see their telegram link
+func (tc *TelegramChat) CanRead(_ *xorm.Session, a web.Auth) (bool, int, error) {
+	return tc.canDoTelegramChat(a), int(RightAdmin), nil
+}
+
+// CanCreate checks if a user can link a telegram account
+func (tc *TelegramChat) CanCreate(_ *xorm.Session, a web.Auth) (bool, error) {
+	return tc.canDoTelegramChat(a), nil
+}
+
+// CanDelete checks if a user can unlink their telegram account
+func (tc *TelegramChat) CanDelete(_ *xorm.Session, a web.Auth) (bool, error) {
+	return tc.canDoTelegramChat(a), nil
+}
+
+// Only users have a telegram account, link shares don't
+func (tc *TelegramChat) canDoTelegramChat(a web.Auth) bool {
+	_, isShareAuth := a.(*LinkSharing)
+	return !isShareAuth
+}
@@ -0,0 +1,165 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelegramChat_Create(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	tc := &TelegramChat{}
+	err := tc.Create(s, &user.User{ID: 1})
+	require.NoError(t, err)
+	require.NoError(t, s.Commit())
+	assert.Len(t, tc.LinkToken, 32)
+	assert.False(t, tc.Linked)
+
+	// Creating a new link unlinks the old chat
+	db.AssertMissing(t, "telegram_chats", map[string]interface{}{
+		"chat_id": 100,
+	})
+	db.AssertExists(t, "telegram_chats", map[string]interface{}{
+		"user_id":    1,
+		"chat_id":    0,
+		"link_token": tc.LinkToken,
+	}, false)
+}
+
+func telegramTestUpdate(chatID int64, text string, replyToTaskID int64) *notifications.TelegramUpdate {
+	message := &notifications.TelegramMessage{Text: text}
+	message.Chat.ID = chatID
+	if replyToTaskID != 0 {
+		message.ReplyToMessage = &notifications.TelegramMessage{}
+		message.ReplyToMessage.Entities = append(message.ReplyToMessage.Entities, struct {
+			Type string `json:"type"`
+			URL  string `json:"url"`
+		}{Type: "text_link", URL: (&Task{ID: replyToTaskID}).GetFrontendURL()})
+	}
+	return &notifications.TelegramUpdate{Message: message}
+}
+
+func TestHandleTelegramUpdate(t *testing.T) {
+	t.Run("link chat", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		answer, err := HandleTelegramUpdate(s, telegramTestUpdate(200, "/start telegramlinktoken", 0))
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		assert.Equal(t, int64(200), answer.ChatID)
+		assert.Contains(t, answer.Text, "now linked")
+
+		db.AssertExists(t, "telegram_chats", map[string]interface{}{
+			"id":      3,
+			"user_id": 2,
+			"chat_id": 200,
+		}, false)
+	})
+	t.Run("invalid link token", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		answer, err := HandleTelegramUpdate(s, telegramTestUpdate(200, "/start wrong", 0))
+		require.NoError(t, err)
+		assert.Contains(t, answer.Text, "invalid")
+	})
+	t.Run("unknown chat", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		answer, err := HandleTelegramUpdate(s, telegramTestUpdate(999, "Buy milk", 0))
+		require.NoError(t, err)
+		assert.Contains(t, answer.Text, "not linked")
+	})
+	t.Run("done", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		answer, err := HandleTelegramUpdate(s, telegramTestUpdate(100, "Done", 1))
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		assert.Contains(t, answer.Text, "as done")
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"id":   1,
+			"done": true,
+		}, false)
+	})
+	t.Run("snooze", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		answer, err := HandleTelegramUpdate(s, telegramTestUpdate(100, "snooze 2h", 1))
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		assert.Contains(t, answer.Text, "again in 2 hours")
+
+		db.AssertExists(t, "task_reminders", map[string]interface{}{
+			"task_id": 1,
+		}, false)
+	})
+	t.Run("task of someone else", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		answer, err := HandleTelegramUpdate(s, telegramTestUpdate(300, "done", 1))
+		require.NoError(t, err)
+		assert.Contains(t, answer.Text, "not allowed")
+	})
+	t.Run("create task", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		answer, err := HandleTelegramUpdate(s, telegramTestUpdate(300, "Buy milk", 0))
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		assert.Contains(t, answer.Text, "Buy milk")
+
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"title":         "Buy milk",
+			"project_id":    4,
+			"created_by_id": 3,
+		}, false)
+	})
+	t.Run("no default project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		answer, err := HandleTelegramUpdate(s, telegramTestUpdate(100, "Buy milk", 0))
+		require.NoError(t, err)
+		assert.Contains(t, answer.Text, "default project")
+	})
+}
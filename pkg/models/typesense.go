@@ -41,8 +41,12 @@ type TypesenseSync struct {
 
 var typesenseClient *typesense.Client
 
+func isTypesenseEnabled() bool {
+	return config.SearchBackend.GetString() == SearchBackendTypesense
+}
+
 func InitTypesense() {
-	if !config.TypesenseEnabled.GetBool() {
+	if !isTypesenseEnabled() {
 		return
 	}
 
@@ -120,6 +124,11 @@ func CreateTypesenseCollections() error {
 				Name: "percent_done",
 				Type: "float",
 			},
+			{
+				Name:     "votes",
+				Type:     "int64",
+				Optional: pointer.True(),
+			},
 			{
 				Name: "identifier",
 				Type: "string",
@@ -467,6 +476,7 @@ type typesenseTask struct {
 	EndDate                *int64      `json:"end_date"`
 	HexColor               string      `json:"hex_color"`
 	PercentDone            float64     `json:"percent_done"`
+	Votes                  int64       `json:"votes"`
 	Identifier             string      `json:"identifier"`
 	Index                  int64       `json:"index"`
 	UID                    string      `json:"uid"`
@@ -501,6 +511,7 @@ func convertTaskToTypesenseTask(task *Task, positions []*TaskPositionWithView, b
 		EndDate:                pointer.Int64(task.EndDate.UTC().Unix()),
 		HexColor:               task.HexColor,
 		PercentDone:            task.PercentDone,
+		Votes:                  task.Votes,
 		Identifier:             task.Identifier,
 		Index:                  task.Index,
 		UID:                    task.UID,
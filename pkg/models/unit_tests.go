@@ -44,6 +44,7 @@ func SetupTests() {
 
 	err = db.InitTestFixtures(
 		"files",
+		"file_contents",
 		"label_tasks",
 		"labels",
 		"link_shares",
@@ -69,6 +70,13 @@ func SetupTests() {
 		"project_views",
 		"task_positions",
 		"task_buckets",
+		"task_description_revisions",
+		"task_sla_rules",
+		"task_sla_breaches",
+		"task_votes",
+		"project_approvers",
+		"my_day_tasks",
+		"task_last_seen",
 	)
 	if err != nil {
 		log.Fatal(err)
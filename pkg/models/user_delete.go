@@ -149,6 +149,50 @@ func DeleteUser(s *xorm.Session, u *user.User) (err error) {
 		}
 	}
 
+	_, err = s.Where("user_id = ?", u.ID).Delete(&MyDayTask{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("user_id = ?", u.ID).Delete(&TaskLastSeen{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("user_id = ?", u.ID).Delete(&ProjectViewPreferences{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("user_id = ?", u.ID).Delete(&InboxItem{})
+	if err != nil {
+		return err
+	}
+
+	err = removeLabelsFromGroups(s, builder.In("group_id", builder.Select("id").From("label_groups").Where(builder.Eq{"created_by_id": u.ID})))
+	if err != nil {
+		return err
+	}
+	_, err = s.Where("created_by_id = ?", u.ID).Delete(&LabelGroup{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("user_id = ?", u.ID).Delete(&user.WebAuthnCredential{})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Where("user_id = ?", u.ID).Delete(&user.TOTPRecoveryCode{})
+	if err != nil {
+		return err
+	}
+
+	err = user.RevokeAllSessionsExcept(s, u, "")
+	if err != nil {
+		return err
+	}
+
 	_, err = s.Where("id = ?", u.ID).Delete(&user.User{})
 	if err != nil {
 		return err
@@ -0,0 +1,284 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/web"
+	"xorm.io/xorm"
+)
+
+// webhookRetryBaseDelay is the time to wait before the first retry of a failed webhook request.
+// It doubles with every further attempt.
+const webhookRetryBaseDelay = time.Minute
+
+// WebhookDelivery is a log entry of one attempt to send an event to a webhook target
+type WebhookDelivery struct {
+	// The unique, numeric id of this delivery attempt.
+	ID int64 `xorm:"bigint autoincr not null unique pk" json:"id"`
+	// The webhook this delivery belongs to.
+	WebhookID int64 `xorm:"bigint not null INDEX" json:"webhook_id" param:"webhook"`
+	ProjectID int64 `xorm:"-" json:"-" param:"project"`
+	// The event which was sent.
+	EventName string `xorm:"varchar(100) not null" json:"event_name"`
+	// The number of this attempt, starting at 1. Every retry of the same event is a new attempt.
+	Attempt int `xorm:"not null default 1" json:"attempt"`
+	// The http status the webhook target responded with, 0 if it could not be reached.
+	StatusCode int `xorm:"null" json:"status_code"`
+	// Why the delivery failed, empty if it was successful.
+	Error string `xorm:"text null" json:"error"`
+	// Whether the webhook target accepted the payload.
+	Success bool `xorm:"not null default false" json:"success"`
+	// The payload which was sent. Only kept until the delivery was retried.
+	Payload string `xorm:"longtext null" json:"-"`
+	// When the failed delivery will be retried. Not set if it won't be retried.
+	NextRetryAt time.Time `xorm:"DATETIME null INDEX 'next_retry_at'" json:"next_retry_at"`
+
+	// A timestamp when this delivery was attempted.
+	Created time.Time `xorm:"created not null" json:"created"`
+
+	web.CRUDable `xorm:"-" json:"-"`
+	web.Rights   `xorm:"-" json:"-"`
+}
+
+func (*WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// CanRead checks if the user can see the deliveries of a webhook. This needs the same rights as managing the webhook.
+func (wd *WebhookDelivery) CanRead(s *xorm.Session, a web.Auth) (bool, int, error) {
+	exists, err := s.
+		Where("id = ? AND project_id = ?", wd.WebhookID, wd.ProjectID).
+		Exist(&Webhook{})
+	if err != nil || !exists {
+		return false, 0, err
+	}
+
+	can, err := (&Webhook{ID: wd.WebhookID, ProjectID: wd.ProjectID}).canDoWebhook(s, a)
+	return can, int(RightAdmin), err
+}
+
+// ReadAll returns the delivery log of a webhook
+// @Summary Get all deliveries of a webhook
+// @Description Returns every attempt to send an event to a webhook target, newest first. Failed attempts are retried in the background with exponential backoff, each retry is listed as its own delivery. Old deliveries are removed after the configured retention period.
+// @tags webhooks
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. This parameter is limited by the configured maximum of items per page."
+// @Param id path int true "Project ID"
+// @Param webhookID path int true "Webhook ID"
+// @Success 200 {array} models.WebhookDelivery "The deliveries of the webhook"
+// @Failure 403 {object} web.HTTPError "The user does not have access to the webhook."
+// @Failure 500 {object} models.Message "Internal server error"
+// @Router /projects/{id}/webhooks/{webhookID}/deliveries [get]
+func (wd *WebhookDelivery) ReadAll(s *xorm.Session, a web.Auth, _ string, page int, perPage int) (result interface{}, resultCount int, numberOfTotalItems int64, err error) {
+	can, _, err := wd.CanRead(s, a)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !can {
+		return nil, 0, 0, ErrGenericForbidden{}
+	}
+
+	deliveries := []*WebhookDelivery{}
+	err = s.Where("webhook_id = ?", wd.WebhookID).
+		OrderBy("created desc, id desc").
+		Limit(getLimitFromPageIndex(page, perPage)).
+		Find(&deliveries)
+	if err != nil {
+		return
+	}
+
+	total, err := s.Where("webhook_id = ?", wd.WebhookID).
+		Count(&WebhookDelivery{})
+	if err != nil {
+		return
+	}
+
+	return deliveries, len(deliveries), total, nil
+}
+
+// deliver sends a payload to the webhook target and saves the attempt in the delivery log. If the target can't be
+// reached or responds with a server error, the delivery is retried later with exponential backoff.
+func (w *Webhook) deliver(p *WebhookPayload) {
+	payload, err := json.Marshal(p)
+	if err != nil {
+		log.Errorf("Could not encode event %s for webhook %d: %s", p.EventName, w.ID, err)
+		return
+	}
+
+	w.attemptDelivery(p.EventName, payload, 1)
+}
+
+func (w *Webhook) attemptDelivery(eventName string, payload []byte, attempt int) {
+	statusCode, sendErr := w.sendWebhookPayload(eventName, payload)
+
+	delivery := &WebhookDelivery{
+		WebhookID:  w.ID,
+		EventName:  eventName,
+		Attempt:    attempt,
+		StatusCode: statusCode,
+		Success:    sendErr == nil,
+	}
+	if sendErr != nil {
+		delivery.Error = sendErr.Error()
+
+		// The target rejected the payload, sending the same one again won't change that
+		rejected := statusCode >= 400 && statusCode < 500 && statusCode != http.StatusTooManyRequests
+		if !rejected && attempt <= config.WebhooksMaxRetries.GetInt() {
+			delivery.Payload = string(payload)
+			delivery.NextRetryAt = time.Now().Add(webhookRetryBaseDelay << (attempt - 1))
+		} else {
+			log.Errorf("Could not deliver event %s to webhook %d", eventName, w.ID)
+		}
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	_, err := s.Insert(delivery)
+	if err != nil {
+		_ = s.Rollback()
+		log.Errorf("Could not save delivery of event %s to webhook %d: %s", eventName, w.ID, err)
+		return
+	}
+
+	if err := s.Commit(); err != nil {
+		log.Errorf("Could not save delivery of event %s to webhook %d: %s", eventName, w.ID, err)
+	}
+}
+
+// RetryWebhookDeliveries sends all failed webhook deliveries which are due for a retry again.
+// Each retry is saved as a new delivery.
+func RetryWebhookDeliveries(now time.Time) {
+	s := db.NewSession()
+	defer s.Close()
+
+	due := []*WebhookDelivery{}
+	err := s.
+		Where("next_retry_at IS NOT NULL AND next_retry_at <= ?", now.In(config.GetTimeZone()).Format(dbTimeFormat)).
+		Find(&due)
+	if err != nil {
+		log.Errorf("Could not get webhook deliveries to retry: %s", err)
+		return
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	deliveryIDs := make([]int64, 0, len(due))
+	webhookIDs := make([]int64, 0, len(due))
+	for _, d := range due {
+		deliveryIDs = append(deliveryIDs, d.ID)
+		webhookIDs = append(webhookIDs, d.WebhookID)
+	}
+
+	webhooks := make(map[int64]*Webhook)
+	err = s.In("id", webhookIDs).Find(&webhooks)
+	if err != nil {
+		log.Errorf("Could not get webhooks to retry deliveries: %s", err)
+		return
+	}
+
+	// Every delivery is only retried once, the retry decides whether there will be another one
+	_, err = s.
+		Table("webhook_deliveries").
+		In("id", deliveryIDs).
+		Update(map[string]interface{}{
+			"next_retry_at": nil,
+			"payload":       nil,
+		})
+	if err != nil {
+		_ = s.Rollback()
+		log.Errorf("Could not reset webhook deliveries to retry: %s", err)
+		return
+	}
+	if err := s.Commit(); err != nil {
+		log.Errorf("Could not reset webhook deliveries to retry: %s", err)
+		return
+	}
+
+	for _, d := range due {
+		w, has := webhooks[d.WebhookID]
+		if !has {
+			// The webhook was deleted in the meantime
+			continue
+		}
+		w.attemptDelivery(d.EventName, []byte(d.Payload), d.Attempt+1)
+	}
+}
+
+// deleteWebhookDeliveriesBefore removes all entries from the delivery log which were created before the given time
+// and won't be retried anymore.
+func deleteWebhookDeliveriesBefore(s *xorm.Session, before time.Time) (deleted int64, err error) {
+	return s.
+		Where("created < ? AND next_retry_at IS NULL", before.In(config.GetTimeZone()).Format(dbTimeFormat)).
+		Delete(&WebhookDelivery{})
+}
+
+// RegisterWebhookDeliveryCron retries failed webhook deliveries every minute and removes old entries from the
+// delivery log once an hour.
+func RegisterWebhookDeliveryCron() {
+	if !config.WebhooksEnabled.GetBool() {
+		return
+	}
+
+	const logPrefix = "[Webhook Delivery Cron] "
+
+	err := cron.Schedule("* * * * *", func() {
+		RetryWebhookDeliveries(time.Now())
+	})
+	if err != nil {
+		log.Fatalf("Could not register webhook delivery retry cron: %s", err)
+	}
+
+	if config.WebhooksDeliveryRetentionDays.GetInt() <= 0 {
+		return
+	}
+
+	err = cron.Schedule("0 * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		retention := time.Duration(config.WebhooksDeliveryRetentionDays.GetInt()) * 24 * time.Hour
+		deleted, err := deleteWebhookDeliveriesBefore(s, time.Now().Add(-retention))
+		if err != nil {
+			_ = s.Rollback()
+			log.Errorf(logPrefix+"Could not delete old webhook deliveries: %s", err)
+			return
+		}
+		if err := s.Commit(); err != nil {
+			log.Errorf(logPrefix+"Could not delete old webhook deliveries: %s", err)
+			return
+		}
+		if deleted > 0 {
+			log.Debugf(logPrefix+"Deleted %d old webhook deliveries", deleted)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register webhook delivery retention cron: %s", err)
+	}
+}
@@ -0,0 +1,143 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/builder"
+)
+
+func TestWebhook_deliver(t *testing.T) {
+	config.WebhooksMaxRetries.Set(3)
+
+	respondWith := func(t *testing.T, statusCodes ...int) *httptest.Server {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			status := statusCodes[len(statusCodes)-1]
+			if requests < len(statusCodes) {
+				status = statusCodes[requests]
+			}
+			requests++
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	// Retries load the webhook from the database
+	createWebhook := func(t *testing.T, id int64, targetURL string) *Webhook {
+		s := db.NewSession()
+		defer s.Close()
+		w := &Webhook{ID: id, TargetURL: targetURL, Events: []string{"task.created"}, ProjectID: 1, CreatedByID: 1}
+		_, err := s.Insert(w)
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+		return w
+	}
+
+	// Pretends enough time passed for all pending retries to be due
+	retryAll := func(times int) {
+		for i := 0; i < times; i++ {
+			RetryWebhookDeliveries(time.Now().Add(24 * time.Hour))
+		}
+	}
+
+	t.Run("retries server errors", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		server := respondWith(t, http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK)
+
+		w := createWebhook(t, 9001, server.URL)
+		w.deliver(&WebhookPayload{EventName: "task.created", Time: time.Now()})
+
+		db.AssertCount(t, "webhook_deliveries", builder.Eq{"webhook_id": 9001}, 1)
+		db.AssertCount(t, "webhook_deliveries", builder.And(
+			builder.Eq{"webhook_id": 9001},
+			builder.NotNull{"next_retry_at"},
+		), 1)
+
+		retryAll(2)
+
+		db.AssertCount(t, "webhook_deliveries", builder.Eq{"webhook_id": 9001}, 3)
+		db.AssertExists(t, "webhook_deliveries", map[string]interface{}{
+			"webhook_id":  9001,
+			"attempt":     3,
+			"status_code": http.StatusOK,
+			"success":     true,
+		}, false)
+		db.AssertCount(t, "webhook_deliveries", builder.And(
+			builder.Eq{"webhook_id": 9001},
+			builder.NotNull{"next_retry_at"},
+		), 0)
+	})
+	t.Run("gives up after max retries", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		server := respondWith(t, http.StatusServiceUnavailable)
+
+		w := createWebhook(t, 9002, server.URL)
+		w.deliver(&WebhookPayload{EventName: "task.created", Time: time.Now()})
+		retryAll(5)
+
+		db.AssertCount(t, "webhook_deliveries", builder.Eq{"webhook_id": 9002}, 4)
+		db.AssertCount(t, "webhook_deliveries", builder.Eq{"webhook_id": 9002, "success": true}, 0)
+	})
+	t.Run("does not retry client errors", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		server := respondWith(t, http.StatusBadRequest)
+
+		w := createWebhook(t, 9003, server.URL)
+		w.deliver(&WebhookPayload{EventName: "task.created", Time: time.Now()})
+		retryAll(1)
+
+		db.AssertCount(t, "webhook_deliveries", builder.Eq{"webhook_id": 9003}, 1)
+	})
+	t.Run("retries are not due yet", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		server := respondWith(t, http.StatusInternalServerError)
+
+		w := createWebhook(t, 9004, server.URL)
+		w.deliver(&WebhookPayload{EventName: "task.created", Time: time.Now()})
+		RetryWebhookDeliveries(time.Now())
+
+		db.AssertCount(t, "webhook_deliveries", builder.Eq{"webhook_id": 9004}, 1)
+	})
+}
+
+func TestDeleteWebhookDeliveriesBefore(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	_, err := s.Insert(&WebhookDelivery{WebhookID: 9005, EventName: "task.created", Attempt: 1, Success: true})
+	require.NoError(t, err)
+
+	deleted, err := deleteWebhookDeliveriesBefore(s, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+
+	deleted, err = deleteWebhookDeliveriesBefore(s, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
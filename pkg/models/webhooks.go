@@ -23,7 +23,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
-	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -231,6 +231,11 @@ func (w *Webhook) Update(s *xorm.Session, _ web.Auth) (err error) {
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /projects/{id}/webhooks/{webhookID} [delete]
 func (w *Webhook) Delete(s *xorm.Session, _ web.Auth) (err error) {
+	_, err = s.Where("webhook_id = ?", w.ID).Delete(&WebhookDelivery{})
+	if err != nil {
+		return
+	}
+
 	_, err = s.Where("id = ?", w.ID).Delete(&Webhook{})
 	return
 }
@@ -264,15 +269,10 @@ func getWebhookHTTPClient() (client *http.Client) {
 	return
 }
 
-func (w *Webhook) sendWebhookPayload(p *WebhookPayload) (err error) {
-	payload, err := json.Marshal(p)
-	if err != nil {
-		return err
-	}
-
+func (w *Webhook) sendWebhookPayload(eventName string, payload []byte) (statusCode int, err error) {
 	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, w.TargetURL, bytes.NewReader(payload))
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	if len(w.Secret) > 0 {
@@ -291,7 +291,7 @@ func (w *Webhook) sendWebhookPayload(p *WebhookPayload) (err error) {
 	client := getWebhookHTTPClient()
 	res, err := client.Do(req)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	defer res.Body.Close()
@@ -299,12 +299,13 @@ func (w *Webhook) sendWebhookPayload(p *WebhookPayload) (err error) {
 	if res.StatusCode > 399 {
 		responseBody, err := io.ReadAll(res.Body)
 		if err != nil {
-			return err
+			return res.StatusCode, err
 		}
 
 		log.Errorf("Got response with status %d from webhook %d: %s", res.StatusCode, w.ID, responseBody)
+		return res.StatusCode, fmt.Errorf("webhook target responded with status %d", res.StatusCode)
 	}
 
-	log.Debugf("Sent webhook payload for webhook %d for event %s", w.ID, p.EventName)
-	return
+	log.Debugf("Sent webhook payload for webhook %d for event %s", w.ID, eventName)
+	return res.StatusCode, nil
 }
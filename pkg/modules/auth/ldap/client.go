@@ -0,0 +1,79 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ldap
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+const connectionTimeout = 10 * time.Second
+
+// dial connects to the configured ldap server.
+func dial() (*ldap.Conn, error) {
+	scheme := "ldap"
+	if config.AuthLdapUseTLS.GetBool() {
+		scheme = "ldaps"
+	}
+	address := scheme + "://" + net.JoinHostPort(config.AuthLdapHost.GetString(), strconv.Itoa(config.AuthLdapPort.GetInt()))
+
+	c, err := ldap.DialURL(
+		address,
+		ldap.DialWithDialer(&net.Dialer{Timeout: connectionTimeout}),
+		ldap.DialWithTLSConfig(&tls.Config{
+			//#nosec G402
+			InsecureSkipVerify: !config.AuthLdapVerifyTLS.GetBool(),
+			ServerName:         config.AuthLdapHost.GetString(),
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to ldap server at %s: %w", address, err)
+	}
+	c.SetTimeout(connectionTimeout)
+
+	return c, nil
+}
+
+// search returns all entries below the base dn matching the filter.
+func search(c *ldap.Conn, baseDN string, filter string, attributes []string) ([]*ldap.Entry, error) {
+	result, err := c.Search(ldap.NewSearchRequest(
+		baseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.DerefAlways,
+		0, // No size limit
+		int(connectionTimeout.Seconds()),
+		false,
+		filter,
+		attributes,
+		nil,
+	))
+	if err != nil {
+		return nil, err
+	}
+	return result.Entries, nil
+}
+
+func isInvalidCredentials(err error) bool {
+	return ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials)
+}
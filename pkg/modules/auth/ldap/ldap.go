@@ -0,0 +1,269 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ldap
+
+import (
+	"fmt"
+	"strings"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/api/pkg/utils"
+
+	"github.com/go-ldap/ldap/v3"
+	"xorm.io/xorm"
+)
+
+type group struct {
+	DN          string
+	Name        string
+	Description string
+}
+
+// AuthenticateUserInLDAP checks the credentials of a user against the configured ldap server.
+// If they are valid, the user is created or updated in Vikunja and returned. If the user does not exist in ldap or the
+// password is wrong, an ErrWrongUsernameOrPassword is returned.
+func AuthenticateUserInLDAP(s *xorm.Session, username, password string) (u *user.User, err error) {
+	// An empty password would result in an unauthenticated bind which most servers accept
+	if username == "" || password == "" {
+		return nil, user.ErrNoUsernamePassword{}
+	}
+
+	c, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	err = bindServiceAccount(c)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := search(
+		c,
+		config.AuthLdapBaseDN.GetString(),
+		fmt.Sprintf(config.AuthLdapUserFilter.GetString(), ldap.EscapeFilter(username)),
+		[]string{
+			config.AuthLdapAttributeUsername.GetString(),
+			config.AuthLdapAttributeEmail.GetString(),
+			config.AuthLdapAttributeDisplayname.GetString(),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not search for user %s in ldap: %w", username, err)
+	}
+	if len(entries) != 1 {
+		log.Debugf("Found %d ldap entries for user %s, expected exactly one", len(entries), username)
+		return nil, user.ErrWrongUsernameOrPassword{}
+	}
+	userEntry := entries[0]
+
+	err = c.Bind(userEntry.DN, password)
+	if isInvalidCredentials(err) {
+		return nil, user.ErrWrongUsernameOrPassword{}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not bind as %s: %w", userEntry.DN, err)
+	}
+
+	u, err = getOrCreateLDAPUser(s, userEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	if !config.AuthLdapGroupSyncEnabled.GetBool() {
+		return u, nil
+	}
+
+	// The user might not be allowed to search for groups
+	err = bindServiceAccount(c)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := getGroupsForUser(c, userEntry.DN)
+	if err != nil {
+		return nil, err
+	}
+
+	err = syncUserTeams(s, u, groups)
+	return u, err
+}
+
+func bindServiceAccount(c *ldap.Conn) error {
+	if config.AuthLdapBindDN.GetString() == "" {
+		return nil
+	}
+
+	err := c.Bind(config.AuthLdapBindDN.GetString(), config.AuthLdapBindPassword.GetString())
+	if err != nil {
+		return fmt.Errorf("could not bind to ldap server with the configured bind dn: %w", err)
+	}
+	return nil
+}
+
+func getOrCreateLDAPUser(s *xorm.Session, e *ldap.Entry) (u *user.User, err error) {
+	username := e.GetEqualFoldAttributeValue(config.AuthLdapAttributeUsername.GetString())
+	email := e.GetEqualFoldAttributeValue(config.AuthLdapAttributeEmail.GetString())
+	name := e.GetEqualFoldAttributeValue(config.AuthLdapAttributeDisplayname.GetString())
+
+	if username == "" {
+		return nil, fmt.Errorf("ldap entry %s does not contain the username attribute %s", e.DN, config.AuthLdapAttributeUsername.GetString())
+	}
+	if email == "" {
+		return nil, &user.ErrNoLDAPEmailProvided{Username: username}
+	}
+
+	u, err = user.GetUserWithEmail(s, &user.User{
+		Issuer:  user.IssuerLDAP,
+		Subject: username,
+	})
+	if err != nil && !user.IsErrUserDoesNotExist(err) {
+		return nil, err
+	}
+
+	if user.IsErrUserDoesNotExist(err) {
+		u, err = user.CreateUser(s, &user.User{
+			Username: strings.ReplaceAll(username, " ", "-"),
+			Email:    email,
+			Name:     name,
+			Status:   user.StatusActive,
+			Issuer:   user.IssuerLDAP,
+			Subject:  username,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = models.CreateNewProjectForUser(s, u)
+		return u, err
+	}
+
+	if email != u.Email || name != u.Name {
+		u.Email = email
+		u.Name = name
+
+		u, err = user.UpdateUser(s, u, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return u, nil
+}
+
+func getGroupsForUser(c *ldap.Conn, userDN string) (groups []*group, err error) {
+	entries, err := search(
+		c,
+		config.AuthLdapBaseDN.GetString(),
+		fmt.Sprintf(config.AuthLdapGroupSyncFilter.GetString(), ldap.EscapeFilter(userDN)),
+		[]string{"cn", "description"},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not search for groups of %s in ldap: %w", userDN, err)
+	}
+
+	groups = make([]*group, 0, len(entries))
+	for _, e := range entries {
+		name := e.GetEqualFoldAttributeValue("cn")
+		if name == "" {
+			name = e.DN
+		}
+		groups = append(groups, &group{
+			DN:          e.DN,
+			Name:        name,
+			Description: e.GetEqualFoldAttributeValue("description"),
+		})
+	}
+
+	return groups, nil
+}
+
+// syncUserTeams makes sure the user is a member of exactly the teams belonging to their ldap groups.
+// Teams which don't exist yet are created.
+func syncUserTeams(s *xorm.Session, u *user.User, groups []*group) error {
+	oldTeamIDs, err := models.FindAllTeamIDsForUserAndIssuer(s, u.ID, user.IssuerLDAP)
+	if err != nil {
+		return err
+	}
+
+	teamIDs := make([]int64, 0, len(groups))
+	for _, g := range groups {
+		team, err := models.GetTeamByOidcIDAndIssuer(s, g.DN, user.IssuerLDAP)
+		if err != nil && !models.IsErrOIDCTeamDoesNotExist(err) {
+			return err
+		}
+
+		if models.IsErrOIDCTeamDoesNotExist(err) {
+			log.Debugf("Creating team for ldap group %s", g.DN)
+			team = &models.Team{
+				Name:        g.Name,
+				Description: g.Description,
+				OidcID:      g.DN,
+				Issuer:      user.IssuerLDAP,
+			}
+			// Creating the team makes the user a member of it
+			err = team.CreateNewTeam(s, u, false)
+			if err != nil {
+				return err
+			}
+			teamIDs = append(teamIDs, team.ID)
+			continue
+		}
+
+		if team.Name != g.Name || team.Description != g.Description {
+			team.Name = g.Name
+			team.Description = g.Description
+			_, err = s.
+				Where("id = ?", team.ID).
+				Cols("name", "description").
+				Update(team)
+			if err != nil {
+				return err
+			}
+		}
+
+		tm := &models.TeamMember{TeamID: team.ID, UserID: u.ID, Username: u.Username}
+		exists, err := tm.MembershipExists(s)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			err = tm.Create(s, u)
+			if err != nil {
+				return err
+			}
+		}
+
+		teamIDs = append(teamIDs, team.ID)
+	}
+
+	teamIDsToLeave := utils.NotIn(oldTeamIDs, teamIDs)
+	if len(teamIDsToLeave) == 0 {
+		return nil
+	}
+
+	log.Debugf("Removing user %d from ldap teams %v", u.ID, teamIDsToLeave)
+	_, err = s.
+		In("team_id", teamIDsToLeave).
+		And("user_id = ?", u.ID).
+		Delete(&models.TeamMember{})
+	return err
+}
@@ -0,0 +1,320 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ldap
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/api/pkg/user"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLDAPUser struct {
+	dn       string
+	password string
+	mail     string
+	name     string
+}
+
+type testLDAPGroup struct {
+	dn      string
+	cn      string
+	members []string
+}
+
+// testLDAPServer is a tiny ldap server which only knows the requests Vikunja sends.
+type testLDAPServer struct {
+	sync.Mutex
+	listener net.Listener
+	users    map[string]*testLDAPUser
+	groups   []*testLDAPGroup
+}
+
+func startTestLDAPServer(t *testing.T) *testLDAPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &testLDAPServer{
+		listener: listener,
+		users: map[string]*testLDAPUser{
+			"ldapuser": {
+				dn:       "uid=ldapuser,ou=people,dc=example,dc=org",
+				password: "secret",
+				mail:     "ldapuser@example.org",
+				name:     "LDAP User",
+			},
+		},
+		groups: []*testLDAPGroup{
+			{
+				dn:      "cn=developers,ou=groups,dc=example,dc=org",
+				cn:      "developers",
+				members: []string{"uid=ldapuser,ou=people,dc=example,dc=org"},
+			},
+		},
+	}
+	go server.serve()
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	config.AuthLdapHost.Set("127.0.0.1")
+	config.AuthLdapPort.Set(portNumber)
+	config.AuthLdapBaseDN.Set("dc=example,dc=org")
+	config.AuthLdapBindDN.Set("cn=admin,dc=example,dc=org")
+	config.AuthLdapBindPassword.Set("admin")
+	t.Cleanup(func() {
+		config.AuthLdapHost.Set("")
+		config.AuthLdapPort.Set(389)
+		config.AuthLdapBaseDN.Set("")
+		config.AuthLdapBindDN.Set("")
+		config.AuthLdapBindPassword.Set("")
+		config.AuthLdapGroupSyncEnabled.Set(false)
+	})
+
+	return server
+}
+
+func (server *testLDAPServer) serve() {
+	for {
+		c, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+		go server.handle(c)
+	}
+}
+
+func (server *testLDAPServer) handle(c net.Conn) {
+	defer c.Close()
+	reader := bufio.NewReader(c)
+	for {
+		message, err := ber.ReadPacket(reader)
+		if err != nil || len(message.Children) < 2 {
+			return
+		}
+		id := message.Children[0].Value
+		op := message.Children[1]
+
+		server.Lock()
+		respond := func(response *ber.Packet) {
+			envelope := ber.NewSequence("")
+			envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, ""))
+			envelope.AppendChild(response)
+			_, _ = c.Write(envelope.Bytes())
+		}
+		result := func(tag ber.Tag, code int64) {
+			response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "")
+			response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, ""))
+			response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+			response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+			respond(response)
+		}
+		searchEntry := func(dn string, attributes map[string]string) {
+			response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "")
+			response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, ""))
+			list := ber.NewSequence("")
+			for name, value := range attributes {
+				attribute := ber.NewSequence("")
+				attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, ""))
+				values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "")
+				values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, ""))
+				attribute.AppendChild(values)
+				list.AppendChild(attribute)
+			}
+			response.AppendChild(list)
+			respond(response)
+		}
+
+		switch op.Tag {
+		case ldap.ApplicationUnbindRequest:
+			server.Unlock()
+			return
+		case ldap.ApplicationBindRequest:
+			dn := op.Children[1].Data.String()
+			password := op.Children[2].Data.String()
+			code := int64(ldap.LDAPResultInvalidCredentials)
+			if dn == "cn=admin,dc=example,dc=org" && password == "admin" {
+				code = ldap.LDAPResultSuccess
+			}
+			for _, u := range server.users {
+				if u.dn == dn && u.password == password {
+					code = ldap.LDAPResultSuccess
+				}
+			}
+			result(ldap.ApplicationBindResponse, code)
+		case ldap.ApplicationSearchRequest:
+			conditions := map[string]string{}
+			collectEqualityMatches(op.Children[6], conditions)
+
+			if u, exists := server.users[conditions["uid"]]; exists {
+				searchEntry(u.dn, map[string]string{
+					"uid":         conditions["uid"],
+					"mail":        u.mail,
+					"displayName": u.name,
+				})
+			}
+			for _, g := range server.groups {
+				for _, member := range g.members {
+					if member == conditions["member"] {
+						searchEntry(g.dn, map[string]string{"cn": g.cn})
+					}
+				}
+			}
+			result(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess)
+		}
+		server.Unlock()
+	}
+}
+
+func collectEqualityMatches(filter *ber.Packet, conditions map[string]string) {
+	if filter.Tag == ldap.FilterEqualityMatch && filter.ClassType == ber.ClassContext {
+		conditions[strings.ToLower(filter.Children[0].Data.String())] = filter.Children[1].Data.String()
+		return
+	}
+	for _, child := range filter.Children {
+		collectEqualityMatches(child, conditions)
+	}
+}
+
+func TestAuthenticateUserInLDAP(t *testing.T) {
+	t.Run("new user", func(t *testing.T) {
+		startTestLDAPServer(t)
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u, err := AuthenticateUserInLDAP(s, "ldapuser", "secret")
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.Equal(t, "ldapuser", u.Username)
+		db.AssertExists(t, "users", map[string]interface{}{
+			"id":       u.ID,
+			"username": "ldapuser",
+			"email":    "ldapuser@example.org",
+			"name":     "LDAP User",
+			"issuer":   user.IssuerLDAP,
+			"subject":  "ldapuser",
+		}, false)
+	})
+	t.Run("existing user", func(t *testing.T) {
+		server := startTestLDAPServer(t)
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		first, err := AuthenticateUserInLDAP(s, "ldapuser", "secret")
+		require.NoError(t, err)
+
+		server.Lock()
+		server.users["ldapuser"].mail = "changed@example.org"
+		server.Unlock()
+		second, err := AuthenticateUserInLDAP(s, "ldapuser", "secret")
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		assert.Equal(t, first.ID, second.ID)
+		db.AssertExists(t, "users", map[string]interface{}{
+			"id":    first.ID,
+			"email": "changed@example.org",
+		}, false)
+	})
+	t.Run("wrong password", func(t *testing.T) {
+		startTestLDAPServer(t)
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := AuthenticateUserInLDAP(s, "ldapuser", "wrong")
+		require.Error(t, err)
+		assert.True(t, user.IsErrWrongUsernameOrPassword(err))
+	})
+	t.Run("empty password", func(t *testing.T) {
+		startTestLDAPServer(t)
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := AuthenticateUserInLDAP(s, "ldapuser", "")
+		require.Error(t, err)
+		assert.True(t, user.IsErrNoUsernamePassword(err))
+	})
+	t.Run("unknown user", func(t *testing.T) {
+		startTestLDAPServer(t)
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, err := AuthenticateUserInLDAP(s, "somebody", "secret")
+		require.Error(t, err)
+		assert.True(t, user.IsErrWrongUsernameOrPassword(err))
+	})
+	t.Run("group sync", func(t *testing.T) {
+		server := startTestLDAPServer(t)
+		config.AuthLdapGroupSyncEnabled.Set(true)
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u, err := AuthenticateUserInLDAP(s, "ldapuser", "secret")
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		teamIDs, err := models.FindAllTeamIDsForUserAndIssuer(s, u.ID, user.IssuerLDAP)
+		require.NoError(t, err)
+		require.Len(t, teamIDs, 1)
+		db.AssertExists(t, "teams", map[string]interface{}{
+			"id":      teamIDs[0],
+			"name":    "developers",
+			"oidc_id": "cn=developers,ou=groups,dc=example,dc=org",
+			"issuer":  user.IssuerLDAP,
+		}, false)
+
+		server.Lock()
+		server.groups[0].members = []string{}
+		server.Unlock()
+		_, err = AuthenticateUserInLDAP(s, "ldapuser", "secret")
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "team_members", map[string]interface{}{
+			"team_id": teamIDs[0],
+			"user_id": u.ID,
+		})
+	})
+}
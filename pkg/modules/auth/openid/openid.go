@@ -56,8 +56,10 @@ type Provider struct {
 	ClientID        string `json:"client_id"`
 	Scope           string `json:"scope"`
 	ClientSecret    string `json:"-"`
-	openIDProvider  *oidc.Provider
-	Oauth2Config    *oauth2.Config `json:"-"`
+	// The claim containing the groups of a user. If set, the user's teams from this provider are synced with it.
+	GroupsClaim    string `json:"-"`
+	openIDProvider *oidc.Provider
+	Oauth2Config   *oauth2.Config `json:"-"`
 }
 type claims struct {
 	Email             string                   `json:"email"`
@@ -205,25 +207,29 @@ func HandleCallback(c echo.Context) error {
 	// does the oidc token contain well formed "vikunja_groups" through vikunja_scope
 	log.Debugf("Checking for vikunja_groups in token %v", cl.VikunjaGroups)
 	teamData, errs := getTeamDataFromToken(cl.VikunjaGroups, provider)
-	if len(teamData) > 0 {
-		for _, err := range errs {
-			log.Errorf("Error creating teams for user and vikunja groups %s: %v", cl.VikunjaGroups, err)
-		}
+	for _, err := range errs {
+		log.Errorf("Error creating teams for user and vikunja groups %s: %v", cl.VikunjaGroups, err)
+	}
+	syncTeams := len(teamData) > 0
 
-		// find old teams for user through oidc
-		oldOidcTeams, err := models.FindAllOidcTeamIDsForUser(s, u.ID)
+	if provider.GroupsClaim != "" {
+		groups, err := getGroupsFromToken(idToken, oauth2Token, provider)
 		if err != nil {
-			log.Debugf("No oidc teams found for user %v", err)
+			_ = s.Rollback()
+			log.Errorf("Error getting the groups claim %s for provider %s: %v", provider.GroupsClaim, provider.Name, err)
+			return handler.HandleHTTPError(err, c)
 		}
-		oidcTeams, err := AssignOrCreateUserToTeams(s, u, teamData, idToken.Issuer)
+		log.Debugf("Got groups %v from claim %s", groups, provider.GroupsClaim)
+		teamData = append(teamData, getTeamDataFromGroups(groups)...)
+		// Users who are not part of any group anymore need to leave all teams
+		syncTeams = true
+	}
+
+	if syncTeams {
+		err = SyncUserTeams(s, u, teamData, idToken.Issuer)
 		if err != nil {
 			log.Errorf("Could not proceed with group routine %v", err)
 		}
-		teamIDsToLeave := utils.NotIn(oldOidcTeams, oidcTeams)
-		err = RemoveUserFromTeamsByIDs(s, u, teamIDsToLeave)
-		if err != nil {
-			log.Errorf("Error while leaving teams %v", err)
-		}
 	}
 	err = s.Commit()
 	if err != nil {
@@ -235,6 +241,23 @@ func HandleCallback(c echo.Context) error {
 	return auth.NewUserAuthTokenResponse(u, c, false)
 }
 
+// SyncUserTeams makes sure the user is a member of exactly the teams from teamData out of all teams created by the
+// issuer. Teams which don't exist yet are created.
+func SyncUserTeams(s *xorm.Session, u *user.User, teamData []*models.OIDCTeam, issuer string) (err error) {
+	oldOidcTeams, err := models.FindAllTeamIDsForUserAndIssuer(s, u.ID, issuer)
+	if err != nil {
+		return err
+	}
+
+	oidcTeams, err := AssignOrCreateUserToTeams(s, u, teamData, issuer)
+	if err != nil {
+		return err
+	}
+
+	teamIDsToLeave := utils.NotIn(oldOidcTeams, oidcTeams)
+	return RemoveUserFromTeamsByIDs(s, u, teamIDsToLeave)
+}
+
 func AssignOrCreateUserToTeams(s *xorm.Session, u *user.User, teamData []*models.OIDCTeam, issuer string) (oidcTeams []int64, err error) {
 	if len(teamData) == 0 {
 		return
@@ -322,6 +345,72 @@ func getTeamDataFromToken(groups []map[string]interface{}, provider *Provider) (
 	return teamData, errs
 }
 
+// getGroupsFromToken reads the groups claim of the provider from the id token or, if it is not part of it, from the
+// userinfo endpoint. Most providers leave out the claim if the user is not part of any group.
+func getGroupsFromToken(idToken *oidc.IDToken, oauth2Token *oauth2.Token, provider *Provider) (groups []string, err error) {
+	rawClaims := map[string]interface{}{}
+	err = idToken.Claims(&rawClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, exists := getGroupsFromClaims(rawClaims, provider.GroupsClaim)
+	if exists {
+		return groups, nil
+	}
+
+	info, err := provider.openIDProvider.UserInfo(context.Background(), provider.Oauth2Config.TokenSource(context.Background(), oauth2Token))
+	if err != nil {
+		return nil, err
+	}
+
+	rawClaims = map[string]interface{}{}
+	err = info.Claims(&rawClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, _ = getGroupsFromClaims(rawClaims, provider.GroupsClaim)
+	return groups, nil
+}
+
+// getGroupsFromClaims returns the groups from a claim which is either a list of strings or a single string.
+func getGroupsFromClaims(rawClaims map[string]interface{}, claim string) (groups []string, exists bool) {
+	raw, exists := rawClaims[claim]
+	if !exists {
+		return nil, false
+	}
+
+	groups = []string{}
+	switch v := raw.(type) {
+	case string:
+		if v != "" {
+			groups = append(groups, v)
+		}
+	case []interface{}:
+		for _, group := range v {
+			name, is := group.(string)
+			if !is || name == "" {
+				log.Errorf("Group %v in claim %s is not a string", group, claim)
+				continue
+			}
+			groups = append(groups, name)
+		}
+	default:
+		log.Errorf("Claim %s is neither a string nor a list of strings: %v", claim, raw)
+	}
+
+	return groups, true
+}
+
+func getTeamDataFromGroups(groups []string) (teamData []*models.OIDCTeam) {
+	teamData = make([]*models.OIDCTeam, 0, len(groups))
+	for _, group := range groups {
+		teamData = append(teamData, &models.OIDCTeam{Name: group, OidcID: group})
+	}
+	return
+}
+
 func getOIDCTeamName(name string) string {
 	return name + " (OIDC)"
 }
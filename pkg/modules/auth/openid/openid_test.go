@@ -232,3 +232,89 @@ func TestGetOrCreateUser(t *testing.T) {
 		})
 	})
 }
+
+func TestGetGroupsFromClaims(t *testing.T) {
+	t.Run("list of groups", func(t *testing.T) {
+		groups, exists := getGroupsFromClaims(map[string]interface{}{
+			"groups": []interface{}{"developers", "admins", 42},
+		}, "groups")
+		assert.True(t, exists)
+		assert.Equal(t, []string{"developers", "admins"}, groups)
+	})
+	t.Run("single group", func(t *testing.T) {
+		groups, exists := getGroupsFromClaims(map[string]interface{}{
+			"groups": "developers",
+		}, "groups")
+		assert.True(t, exists)
+		assert.Equal(t, []string{"developers"}, groups)
+	})
+	t.Run("missing claim", func(t *testing.T) {
+		groups, exists := getGroupsFromClaims(map[string]interface{}{}, "groups")
+		assert.False(t, exists)
+		assert.Empty(t, groups)
+	})
+}
+
+func TestSyncUserTeams(t *testing.T) {
+	t.Run("join new and existing teams", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 10, Username: "user10"}
+		err := SyncUserTeams(s, u, getTeamDataFromGroups([]string{"14", "new group"}), "https://some.issuer")
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "team_members", map[string]interface{}{
+			"team_id": 14,
+			"user_id": u.ID,
+		}, false)
+		db.AssertMissing(t, "team_members", map[string]interface{}{
+			"team_id": 15,
+			"user_id": u.ID,
+		})
+		db.AssertExists(t, "teams", map[string]interface{}{
+			"name":    "new group (OIDC)",
+			"oidc_id": "new group",
+			"issuer":  "https://some.issuer",
+		}, false)
+	})
+	t.Run("leave all teams", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 10, Username: "user10"}
+		err := SyncUserTeams(s, u, getTeamDataFromGroups([]string{}), "https://some.issuer")
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertMissing(t, "team_members", map[string]interface{}{
+			"team_id": 14,
+			"user_id": u.ID,
+		})
+		db.AssertMissing(t, "team_members", map[string]interface{}{
+			"team_id": 15,
+			"user_id": u.ID,
+		})
+	})
+	t.Run("teams of other issuers are kept", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &user.User{ID: 10, Username: "user10"}
+		err := SyncUserTeams(s, u, getTeamDataFromGroups([]string{}), "https://other.issuer")
+		require.NoError(t, err)
+		err = s.Commit()
+		require.NoError(t, err)
+
+		db.AssertExists(t, "team_members", map[string]interface{}{
+			"team_id": 14,
+			"user_id": u.ID,
+		}, false)
+	})
+}
@@ -139,6 +139,11 @@ func getProviderFromMap(pi map[string]interface{}) (provider *Provider, err erro
 		Scope:           scope,
 	}
 
+	groupsClaim, is := pi["groupsclaim"].(string)
+	if is {
+		provider.GroupsClaim = groupsClaim
+	}
+
 	cl, is := pi["clientid"].(int)
 	if is {
 		provider.ClientID = strconv.Itoa(cl)
@@ -209,6 +209,9 @@ func (bp *BackgroundProvider) UploadBackground(c echo.Context) error {
 		if files.IsErrFileIsTooLarge(err) {
 			return echo.ErrBadRequest
 		}
+		if files.IsErrFileIsInfected(err) {
+			return c.JSON(http.StatusBadRequest, models.Message{Message: "The uploaded file contains a virus."})
+		}
 
 		return handler.HandleHTTPError(err, c)
 	}
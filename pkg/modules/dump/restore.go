@@ -166,7 +166,11 @@ func Restore(filename string) error {
 			return fmt.Errorf("could not parse file id %s: %w", i, err)
 		}
 
+		// Where the content is stored depends on the hash of the file
 		f := &files.File{ID: id}
+		if err := f.LoadFileMetaByID(); err != nil && !files.IsErrFileDoesNotExist(err) {
+			return fmt.Errorf("could not load file %s: %w", i, err)
+		}
 
 		fc, err := file.Open()
 		if err != nil {
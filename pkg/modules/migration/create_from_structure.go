@@ -32,10 +32,16 @@ import (
 // InsertFromStructure takes a fully nested Vikunja data structure and a user and then creates everything for this user
 // (Projects, tasks, etc. Even attachments and relations.)
 func InsertFromStructure(str []*models.ProjectWithTasksAndBuckets, user *user.User) (err error) {
+	return InsertFromStructureIntoParent(str, user, 0)
+}
+
+// InsertFromStructureIntoParent works like InsertFromStructure but creates all top level projects of the structure
+// as children of the given parent project. A parent id of 0 creates them at the top level.
+func InsertFromStructureIntoParent(str []*models.ProjectWithTasksAndBuckets, user *user.User, parentProjectID int64) (err error) {
 	s := db.NewSession()
 	defer s.Close()
 
-	err = insertFromStructure(s, str, user)
+	err = insertFromStructure(s, str, user, parentProjectID)
 	if err != nil {
 		log.Errorf("[creating structure] Error while creating structure: %s", err.Error())
 		_ = s.Rollback()
@@ -45,7 +51,7 @@ func InsertFromStructure(str []*models.ProjectWithTasksAndBuckets, user *user.Us
 	return s.Commit()
 }
 
-func insertFromStructure(s *xorm.Session, str []*models.ProjectWithTasksAndBuckets, user *user.User) (err error) {
+func insertFromStructure(s *xorm.Session, str []*models.ProjectWithTasksAndBuckets, user *user.User, parentProjectID int64) (err error) {
 
 	log.Debugf("[creating structure] Creating %d projects", len(str))
 
@@ -60,9 +66,11 @@ func insertFromStructure(s *xorm.Session, str []*models.ProjectWithTasksAndBucke
 
 		if p.ParentProjectID != 0 {
 			childRelations[p.ParentProjectID] = append(childRelations[p.ParentProjectID], oldID)
-			p.ParentProjectID = 0
 		}
 
+		// Projects with a parent in the structure are moved below it once it exists.
+		p.ParentProjectID = parentProjectID
+
 		p.ID = 0
 
 		for _, view := range p.Views {
@@ -171,6 +179,13 @@ func createProjectWithEverything(s *xorm.Session, project *models.ProjectWithTas
 		log.Debugf("[creating structure] Created a background file for project %d", project.ID)
 	}
 
+	if len(project.IconFile) > 0 {
+		err = models.SetProjectIcon(s, &project.Project, bytes.NewReader(project.IconFile), "icon.png", user)
+		if err != nil {
+			log.Errorf("[creating structure] Could not create icon for project %d, error was %v", project.ID, err)
+		}
+	}
+
 	// Create all buckets
 	bucketsByOldID := make(map[int64]*models.Bucket) // old bucket id is the key
 	if len(project.Buckets) > 0 {
@@ -490,6 +505,12 @@ func createProjectWithEverything(s *xorm.Session, project *models.ProjectWithTas
 		}
 	}
 
+	// The task events are dispatched before the import is done, the search index needs the final tasks
+	err = models.IndexTasksByID(s, newTaskIDs)
+	if err != nil {
+		return
+	}
+
 	project.Tasks = tasks
 	project.Buckets = originalBuckets
 
@@ -0,0 +1,98 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package vikunjafile
+
+import (
+	"fmt"
+	"io"
+
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/api/pkg/modules/migration"
+	"code.vikunja.io/api/pkg/user"
+)
+
+// ImportReport holds what an import of an export archive created - or would create when it was a dry run.
+type ImportReport struct {
+	// Whether this was a dry run. If true, nothing was actually created.
+	DryRun bool `json:"dry_run"`
+
+	Projects    int `json:"projects"`
+	Views       int `json:"views"`
+	Buckets     int `json:"buckets"`
+	Tasks       int `json:"tasks"`
+	Labels      int `json:"labels"`
+	Comments    int `json:"comments"`
+	Attachments int `json:"attachments"`
+	Relations   int `json:"relations"`
+}
+
+// ImportProjects imports all projects from a Vikunja export archive below the given parent project.
+// All ids in the archive are remapped to newly created entities. With dryRun set, the archive is only parsed
+// and the report contains what would have been created.
+func ImportProjects(u *user.User, file io.ReaderAt, size int64, parentProjectID int64, dryRun bool) (report *ImportReport, err error) {
+	projects, _, err := readArchive(file, size)
+	if err != nil {
+		return nil, err
+	}
+
+	report = &ImportReport{DryRun: dryRun}
+	labels := make(map[string]bool)
+	for _, p := range projects {
+		report.add(p, labels)
+	}
+	report.Labels = len(labels)
+
+	if dryRun {
+		return report, nil
+	}
+
+	err = migration.InsertFromStructureIntoParent(projects, u, parentProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("could not insert data: %w", err)
+	}
+
+	return report, nil
+}
+
+func (r *ImportReport) add(p *models.ProjectWithTasksAndBuckets, labels map[string]bool) {
+	r.Projects++
+	r.Views += len(p.Views)
+	r.Buckets += len(p.Buckets)
+	r.Tasks += len(p.Tasks)
+
+	for _, t := range p.Tasks {
+		r.Comments += len(t.Comments)
+		for _, a := range t.Attachments {
+			if a.File != nil && len(a.File.FileContent) > 0 {
+				r.Attachments++
+			}
+		}
+		for _, related := range t.RelatedTasks {
+			r.Relations += len(related)
+		}
+		for _, l := range t.Labels {
+			if l != nil {
+				// Labels with the same title and color are only created once
+				labels[l.Title+l.HexColor] = true
+			}
+		}
+	}
+
+	for _, cp := range p.ChildProjects {
+		r.add(cp, labels)
+	}
+}
@@ -65,15 +65,67 @@ func (v *FileMigrator) Name() string {
 // @Failure 500 {object} models.Message "Internal server error"
 // @Router /migration/vikunja-file/migrate [post]
 func (v *FileMigrator) Migrate(user *user.User, file io.ReaderAt, size int64) error {
+	projects, filterFile, err := readArchive(file, size)
+	if err != nil {
+		return err
+	}
+
+	err = migration.InsertFromStructure(projects, user)
+	if err != nil {
+		return fmt.Errorf("could not insert data: %w", err)
+	}
+
+	if filterFile == nil {
+		log.Debugf(logPrefix + "No filter file found")
+		return nil
+	}
+
+	///////
+	// Import filters
+	ff, err := filterFile.Open()
+	if err != nil {
+		return fmt.Errorf("could not open filters file: %w", err)
+	}
+	defer ff.Close()
+
+	var bufFilter bytes.Buffer
+	if _, err := bufFilter.ReadFrom(ff); err != nil {
+		return fmt.Errorf("could not read filters file: %w", err)
+	}
+
+	filters := []*models.SavedFilter{}
+	if err := json.Unmarshal(bufFilter.Bytes(), &filters); err != nil {
+		return fmt.Errorf("could not read filter data: %w", err)
+	}
+
+	log.Debugf(logPrefix+"Importing %d saved filters", len(filters))
+
+	s := db.NewSession()
+	defer s.Close()
+
+	for _, f := range filters {
+		f.ID = 0
+		err = f.Create(s, user)
+		if err != nil {
+			_ = s.Rollback()
+			return err
+		}
+	}
+
+	return s.Commit()
+}
+
+// readArchive parses a Vikunja export archive and returns the projects in it, with all attachment and background
+// files already read into them.
+func readArchive(file io.ReaderAt, size int64) (projects []*models.ProjectWithTasksAndBuckets, filterFile *zip.File, err error) {
 	r, err := zip.NewReader(file, size)
 	if err != nil {
-		return fmt.Errorf("could not open import file: %w", err)
+		return nil, nil, fmt.Errorf("could not open import file: %w", err)
 	}
 
 	log.Debugf(logPrefix+"Importing a zip file containing %d files", len(r.File))
 
 	var dataFile *zip.File
-	var filterFile *zip.File
 	var versionFile *zip.File
 	storedFiles := make(map[int64]*zip.File)
 	for _, f := range r.File {
@@ -81,7 +133,7 @@ func (v *FileMigrator) Migrate(user *user.User, file io.ReaderAt, size int64) er
 			fname := strings.ReplaceAll(f.Name, "files/", "")
 			id, err := strconv.ParseInt(fname, 10, 64)
 			if err != nil {
-				return fmt.Errorf("could not convert file id: %w", err)
+				return nil, nil, fmt.Errorf("could not convert file id: %w", err)
 			}
 			storedFiles[id] = f
 			log.Debugf(logPrefix + "Found a blob file")
@@ -103,7 +155,7 @@ func (v *FileMigrator) Migrate(user *user.User, file io.ReaderAt, size int64) er
 	}
 
 	if dataFile == nil {
-		return fmt.Errorf("no data file provided")
+		return nil, nil, fmt.Errorf("no data file provided")
 	}
 
 	log.Debugf(logPrefix + "")
@@ -111,16 +163,16 @@ func (v *FileMigrator) Migrate(user *user.User, file io.ReaderAt, size int64) er
 	//////
 	// Check if we're able to import this dump
 	if versionFile == nil {
-		return fmt.Errorf("dump does not seem to contain a version file")
+		return nil, nil, fmt.Errorf("dump does not seem to contain a version file")
 	}
 	vf, err := versionFile.Open()
 	if err != nil {
-		return fmt.Errorf("could not open version file: %w", err)
+		return nil, nil, fmt.Errorf("could not open version file: %w", err)
 	}
 
 	var bufVersion bytes.Buffer
 	if _, err := bufVersion.ReadFrom(vf); err != nil {
-		return fmt.Errorf("could not read version file: %w", err)
+		return nil, nil, fmt.Errorf("could not read version file: %w", err)
 	}
 
 	versionString := bufVersion.String()
@@ -129,15 +181,15 @@ func (v *FileMigrator) Migrate(user *user.User, file io.ReaderAt, size int64) er
 	} else {
 		dumpedVersion, err := version.NewVersion(bufVersion.String())
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 		minVersion, err := version.NewVersion("0.20.1+61")
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 
 		if dumpedVersion.LessThan(minVersion) {
-			return fmt.Errorf("export was created with an older version, need at least %s but the export needs at least %s", dumpedVersion, minVersion)
+			return nil, nil, fmt.Errorf("export was created with an older version, need at least %s but the export needs at least %s", dumpedVersion, minVersion)
 		}
 	}
 
@@ -145,70 +197,28 @@ func (v *FileMigrator) Migrate(user *user.User, file io.ReaderAt, size int64) er
 	// Import the bulk of Vikunja data
 	df, err := dataFile.Open()
 	if err != nil {
-		return fmt.Errorf("could not open data file: %w", err)
+		return nil, nil, fmt.Errorf("could not open data file: %w", err)
 	}
 	defer df.Close()
 
 	var bufData bytes.Buffer
 	if _, err := bufData.ReadFrom(df); err != nil {
-		return fmt.Errorf("could not read data file: %w", err)
+		return nil, nil, fmt.Errorf("could not read data file: %w", err)
 	}
 
-	projects := []*models.ProjectWithTasksAndBuckets{}
+	projects = []*models.ProjectWithTasksAndBuckets{}
 	if err := json.Unmarshal(bufData.Bytes(), &projects); err != nil {
-		return fmt.Errorf("could not read data: %w", err)
+		return nil, nil, fmt.Errorf("could not read data: %w", err)
 	}
 
 	for _, p := range projects {
 		err = addDetailsToProjectAndChildren(p, storedFiles)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 	}
 
-	err = migration.InsertFromStructure(projects, user)
-	if err != nil {
-		return fmt.Errorf("could not insert data: %w", err)
-	}
-
-	if filterFile == nil {
-		log.Debugf(logPrefix + "No filter file found")
-		return nil
-	}
-
-	///////
-	// Import filters
-	ff, err := filterFile.Open()
-	if err != nil {
-		return fmt.Errorf("could not open filters file: %w", err)
-	}
-	defer ff.Close()
-
-	var bufFilter bytes.Buffer
-	if _, err := bufFilter.ReadFrom(ff); err != nil {
-		return fmt.Errorf("could not read filters file: %w", err)
-	}
-
-	filters := []*models.SavedFilter{}
-	if err := json.Unmarshal(bufFilter.Bytes(), &filters); err != nil {
-		return fmt.Errorf("could not read filter data: %w", err)
-	}
-
-	log.Debugf(logPrefix+"Importing %d saved filters", len(filters))
-
-	s := db.NewSession()
-	defer s.Close()
-
-	for _, f := range filters {
-		f.ID = 0
-		err = f.Create(s, user)
-		if err != nil {
-			_ = s.Rollback()
-			return err
-		}
-	}
-
-	return s.Commit()
+	return projects, filterFile, nil
 }
 
 func addDetailsToProjectAndChildren(p *models.ProjectWithTasksAndBuckets, storedFiles map[int64]*zip.File) (err error) {
@@ -241,6 +251,19 @@ func addDetailsToProject(l *models.ProjectWithTasksAndBuckets, storedFiles map[i
 		l.BackgroundInformation = &buf
 	}
 
+	if f, exists := storedFiles[l.IconFileID]; exists {
+		icf, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("could not open project icon file %d for reading: %w", l.IconFileID, err)
+		}
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(icf); err != nil {
+			return fmt.Errorf("could not read project icon file %d: %w", l.IconFileID, err)
+		}
+
+		l.IconFile = buf.Bytes()
+	}
+
 	for _, t := range l.Tasks {
 		for _, label := range t.Labels {
 			label.ID = 0
@@ -24,6 +24,7 @@ import (
 	"code.vikunja.io/api/pkg/db"
 	"code.vikunja.io/api/pkg/user"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -96,3 +97,48 @@ func TestVikunjaFileMigrator_Migrate(t *testing.T) {
 		require.ErrorContainsf(t, err, "export was created with an older version", "Invalid error message")
 	})
 }
+
+func TestImportProjects(t *testing.T) {
+	openExport := func(t *testing.T) (*os.File, int64) {
+		f, err := os.Open(config.ServiceRootpath.GetString() + "/pkg/modules/migration/vikunja-file/export.zip")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = f.Close() })
+		s, err := f.Stat()
+		require.NoError(t, err)
+		return f, s.Size()
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		u := &user.User{ID: 1}
+		f, size := openExport(t)
+
+		report, err := ImportProjects(u, f, size, 1, true)
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 2, report.Projects)
+		assert.Equal(t, 2, report.Tasks)
+		assert.Equal(t, 2, report.Buckets)
+		db.AssertMissing(t, "projects", map[string]interface{}{
+			"title": "test project",
+		})
+	})
+	t.Run("into parent project", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		u := &user.User{ID: 1}
+		f, size := openExport(t)
+
+		report, err := ImportProjects(u, f, size, 1, false)
+		require.NoError(t, err)
+		assert.False(t, report.DryRun)
+		db.AssertExists(t, "projects", map[string]interface{}{
+			"title":             "test project",
+			"parent_project_id": 1,
+			"owner_id":          u.ID,
+		}, false)
+		db.AssertExists(t, "tasks", map[string]interface{}{
+			"title":         "some other task",
+			"created_by_id": u.ID,
+		}, false)
+	})
+}
@@ -0,0 +1,110 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+)
+
+// BatchableNotification is a notification which is collected with others of the same kind for a short time before
+// it is delivered, so that bulk edits or imports don't flood the notifiable. Duplicates in a batch are dropped and
+// batches with more than one notification are delivered as one combined notification.
+type BatchableNotification interface {
+	Notification
+	// BatchKey groups notifications which can be combined, for example all deleted tasks of a project.
+	BatchKey() string
+	// DeduplicationKey identifies notifications about the same thing. Only the last of them is kept.
+	DeduplicationKey() string
+	// Combine returns one notification summarizing the batch, which always contains at least two notifications.
+	Combine(batch []BatchableNotification) Notification
+}
+
+type notificationBatch struct {
+	notifiable    Notifiable
+	notifications []BatchableNotification
+}
+
+var (
+	batches      = make(map[string]*notificationBatch)
+	batchesMutex sync.Mutex
+)
+
+func batchWindow() time.Duration {
+	return time.Duration(config.ServiceNotificationBatchWindow.GetInt64()) * time.Second
+}
+
+// addToBatch collects a notification and starts a timer for the batch when it is the first one.
+func addToBatch(notifiable Notifiable, notification BatchableNotification) {
+	key := strconv.FormatInt(notifiable.RouteForDB(), 10) + "|" + notification.Name() + "|" + notification.BatchKey()
+
+	batchesMutex.Lock()
+	defer batchesMutex.Unlock()
+
+	batch, exists := batches[key]
+	if !exists {
+		batch = &notificationBatch{notifiable: notifiable}
+		batches[key] = batch
+		time.AfterFunc(batchWindow(), func() {
+			flushBatch(key)
+		})
+	}
+	batch.notifications = append(batch.notifications, notification)
+}
+
+// deduplicate drops all but the last of notifications with the same deduplication key, keeping their order.
+func deduplicate(notifications []BatchableNotification) []BatchableNotification {
+	last := make(map[string]int, len(notifications))
+	for i, n := range notifications {
+		last[n.DeduplicationKey()] = i
+	}
+
+	deduplicated := make([]BatchableNotification, 0, len(last))
+	for i, n := range notifications {
+		if last[n.DeduplicationKey()] == i {
+			deduplicated = append(deduplicated, n)
+		}
+	}
+	return deduplicated
+}
+
+func flushBatch(key string) {
+	batchesMutex.Lock()
+	batch, exists := batches[key]
+	delete(batches, key)
+	batchesMutex.Unlock()
+
+	if !exists {
+		return
+	}
+
+	ns := deduplicate(batch.notifications)
+
+	var notification Notification = ns[0]
+	if len(ns) > 1 {
+		notification = ns[len(ns)-1].Combine(ns)
+	}
+
+	err := notify(batch.notifiable, notification)
+	if err != nil {
+		log.Errorf("Could not send batched notification %s to %d: %s", notification.Name(), batch.notifiable.RouteForDB(), err)
+	}
+}
@@ -0,0 +1,85 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBatchableNotification struct {
+	testNotification
+	taskID int64
+}
+
+func (n *testBatchableNotification) BatchKey() string {
+	return "project-1"
+}
+
+func (n *testBatchableNotification) DeduplicationKey() string {
+	return strconv.FormatInt(n.taskID, 10)
+}
+
+func (n *testBatchableNotification) Combine(batch []BatchableNotification) Notification {
+	return &testNotification{
+		Test:       strconv.Itoa(len(batch)) + " tasks were updated",
+		OtherValue: int64(len(batch)),
+	}
+}
+
+func TestNotifyBatched(t *testing.T) {
+	transport := &testTransport{}
+	RegisterTransport(transport)
+	defer func() {
+		transports = nil
+		batches = make(map[string]*notificationBatch)
+	}()
+
+	tnf := &testNotifiable{ShouldSendNotification: true}
+	for _, taskID := range []int64{1, 2, 2, 3} {
+		err := Notify(tnf, &testBatchableNotification{taskID: taskID})
+		require.NoError(t, err)
+	}
+
+	require.Empty(t, transport.sent)
+	require.Len(t, batches, 1)
+
+	for key := range batches {
+		flushBatch(key)
+	}
+
+	require.Len(t, transport.sent, 1)
+	combined, is := transport.sent[0].(*testNotification)
+	require.True(t, is)
+	// The duplicate notification for task 2 is dropped
+	assert.Equal(t, "3 tasks were updated", combined.Test)
+	assert.Empty(t, batches)
+}
+
+func TestDeduplicate(t *testing.T) {
+	first := &testBatchableNotification{taskID: 1}
+	second := &testBatchableNotification{taskID: 2}
+	last := &testBatchableNotification{taskID: 1, testNotification: testNotification{Test: "last"}}
+
+	deduplicated := deduplicate([]BatchableNotification{first, second, last})
+	require.Len(t, deduplicated, 2)
+	assert.Same(t, second, deduplicated[0])
+	assert.Same(t, last, deduplicated[1])
+}
@@ -0,0 +1,149 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"code.vikunja.io/api/pkg/version"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// ChatMessage is a notification in a form chat services like Slack or Mattermost can show.
+// Lines are markdown and use **bold** text for emphasis.
+type ChatMessage struct {
+	Title      string
+	Lines      []string
+	ActionText string
+	ActionURL  string
+}
+
+// NewChatMessage creates a chat message with the same content as the mail of a notification in the language lang.
+// Returns nil if the notification has no mail.
+func NewChatMessage(notification Notification, lang string) *ChatMessage {
+	mail := notification.ToMail(lang)
+	if mail == nil {
+		return nil
+	}
+	return NewChatMessageFromMail(mail)
+}
+
+// NewChatMessageFromMail creates a chat message from a mail. Html lines are converted to plain text.
+func NewChatMessageFromMail(mail *Mail) *ChatMessage {
+	msg := &ChatMessage{
+		Title:      mail.subject,
+		ActionText: mail.actionText,
+		ActionURL:  mail.actionURL,
+	}
+
+	strip := bluemonday.StrictPolicy()
+	for _, line := range append(mail.introLines, mail.outroLines...) {
+		text := line.Text
+		if line.isHTML {
+			text = html.UnescapeString(strip.Sanitize(text))
+		}
+		text = strings.TrimSpace(text)
+		if text != "" {
+			msg.Lines = append(msg.Lines, text)
+		}
+	}
+
+	return msg
+}
+
+// Markdown formats the message as markdown, which Mattermost and Matrix understand
+func (m *ChatMessage) Markdown() string {
+	parts := []string{"**" + m.Title + "**"}
+	parts = append(parts, m.Lines...)
+	if m.ActionURL != "" {
+		parts = append(parts, "["+m.ActionText+"]("+m.ActionURL+")")
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+var markdownBold = regexp.MustCompile(`\*\*(.+?)\*\*`)
+
+// Slack formats the message in Slack's own markdown flavour, which uses single stars for bold text and a
+// different syntax for links.
+func (m *ChatMessage) Slack() string {
+	parts := []string{"*" + m.Title + "*"}
+	for _, line := range m.Lines {
+		parts = append(parts, markdownBold.ReplaceAllString(line, "*$1*"))
+	}
+	if m.ActionURL != "" {
+		parts = append(parts, "<"+m.ActionURL+"|"+m.ActionText+">")
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// PlainText returns the lines of the message without any formatting, for places which can't show markdown
+func (m *ChatMessage) PlainText() string {
+	lines := make([]string, 0, len(m.Lines))
+	for _, line := range m.Lines {
+		lines = append(lines, markdownBold.ReplaceAllString(line, "$1"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HTML formats the message as html, which Matrix clients show instead of the plain markdown body
+func (m *ChatMessage) HTML() string {
+	parts := []string{"<strong>" + html.EscapeString(m.Title) + "</strong>"}
+	for _, line := range m.Lines {
+		parts = append(parts, markdownBold.ReplaceAllString(html.EscapeString(line), "<strong>$1</strong>"))
+	}
+	if m.ActionURL != "" {
+		parts = append(parts, `<a href="`+html.EscapeString(m.ActionURL)+`">`+html.EscapeString(m.ActionText)+"</a>")
+	}
+	return "<p>" + strings.Join(parts, "</p><p>") + "</p>"
+}
+
+// PostChatWebhook posts a message to an incoming webhook of Slack or Mattermost. Both expect the message as "text"
+// field, only the formatting differs.
+func PostChatWebhook(client *http.Client, webhookURL string, text string) error {
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("User-Agent", "Vikunja/"+version.Version)
+	req.Header.Add("Content-Type", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 399 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("chat webhook responded with status %d: %s", res.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatMessage(t *testing.T) {
+	mail := NewMail().
+		Subject("Task (#1)").
+		Line("**user1** commented:").
+		HTML("<p>Looks <b>good</b> &amp; done</p>").
+		Action("View Task", "https://example.com/tasks/1")
+	msg := NewChatMessageFromMail(mail)
+
+	t.Run("markdown", func(t *testing.T) {
+		assert.Equal(t, "**Task (#1)**\n\n**user1** commented:\n\nLooks good & done\n\n[View Task](https://example.com/tasks/1)", msg.Markdown())
+	})
+	t.Run("slack", func(t *testing.T) {
+		assert.Equal(t, "*Task (#1)*\n\n*user1* commented:\n\nLooks good & done\n\n<https://example.com/tasks/1|View Task>", msg.Slack())
+	})
+	t.Run("plain text", func(t *testing.T) {
+		assert.Equal(t, "user1 commented:\nLooks good & done", msg.PlainText())
+	})
+	t.Run("html", func(t *testing.T) {
+		assert.Equal(t, `<p><strong>Task (#1)</strong></p><p><strong>user1</strong> commented:</p><p>Looks good &amp; done</p><p><a href="https://example.com/tasks/1">View Task</a></p>`, msg.HTML())
+	})
+}
+
+func TestPostChatWebhook(t *testing.T) {
+	t.Run("posts the text", func(t *testing.T) {
+		var received map[string]string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &received)
+		}))
+		defer srv.Close()
+
+		err := PostChatWebhook(srv.Client(), srv.URL, "hello")
+		require.NoError(t, err)
+		assert.Equal(t, "hello", received["text"])
+	})
+	t.Run("error status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "invalid_token", http.StatusForbidden)
+		}))
+		defer srv.Close()
+
+		err := PostChatWebhook(srv.Client(), srv.URL, "hello")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_token")
+	})
+}
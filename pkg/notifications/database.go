@@ -19,6 +19,7 @@ package notifications
 import (
 	"time"
 
+	"xorm.io/builder"
 	"xorm.io/xorm"
 )
 
@@ -35,6 +36,8 @@ type DatabaseNotification struct {
 	Name string `xorm:"varchar(250) index not null" json:"name"`
 	// The thing the notification is about. Used to check if a notification for this thing already happened or not.
 	SubjectID int64 `xorm:"bigint null" json:"-"`
+	// The project the notification is about, if any. Used to filter notifications.
+	ProjectID int64 `xorm:"bigint null index" json:"project_id"`
 
 	// When this notification is marked as read, this will be updated with the current timestamp.
 	ReadAt time.Time `xorm:"datetime null" json:"read_at"`
@@ -48,12 +51,39 @@ func (d *DatabaseNotification) TableName() string {
 	return "notifications"
 }
 
-// GetNotificationsForUser returns all notifications for a user. It is possible to limit the amount of notifications
-// to return with the limit and start parameters.
+// Filter restricts which notifications of a notifiable are returned or marked as read.
+type Filter struct {
+	// Only notifications with one of these names, for example task.comment. All names if empty.
+	Names []string `json:"names"`
+	// Only notifications about this project. All projects if 0.
+	ProjectID int64 `json:"project_id"`
+	// Only notifications which were not read yet.
+	UnreadOnly bool `json:"unread_only"`
+}
+
+func (f *Filter) toCond(notifiableID int64) builder.Cond {
+	cond := builder.NewCond().And(builder.Eq{"notifiable_id": notifiableID})
+	if f == nil {
+		return cond
+	}
+	if len(f.Names) > 0 {
+		cond = cond.And(builder.In("name", f.Names))
+	}
+	if f.ProjectID != 0 {
+		cond = cond.And(builder.Eq{"project_id": f.ProjectID})
+	}
+	if f.UnreadOnly {
+		cond = cond.And(builder.IsNull{"read_at"})
+	}
+	return cond
+}
+
+// GetNotificationsForUser returns all notifications for a user matching the filter. It is possible to limit the
+// amount of notifications to return with the limit and start parameters.
 // We're not passing a user object in directly because every other package imports this one so we'd get import cycles.
-func GetNotificationsForUser(s *xorm.Session, notifiableID int64, limit, start int) (notifications []*DatabaseNotification, resultCount int, total int64, err error) {
+func GetNotificationsForUser(s *xorm.Session, notifiableID int64, filter *Filter, limit, start int) (notifications []*DatabaseNotification, resultCount int, total int64, err error) {
 	err = s.
-		Where("notifiable_id = ?", notifiableID).
+		Where(filter.toCond(notifiableID)).
 		Limit(limit, start).
 		OrderBy("id DESC").
 		Find(&notifications)
@@ -62,7 +92,7 @@ func GetNotificationsForUser(s *xorm.Session, notifiableID int64, limit, start i
 	}
 
 	total, err = s.
-		Where("notifiable_id = ?", notifiableID).
+		Where(filter.toCond(notifiableID)).
 		Count(&DatabaseNotification{})
 	return notifications, len(notifications), total, err
 }
@@ -98,10 +128,19 @@ func MarkNotificationAsRead(s *xorm.Session, notification *DatabaseNotification,
 	return
 }
 
-func MarkAllNotificationsAsRead(s *xorm.Session, userID int64) (err error) {
+// MarkAllNotificationsAsRead marks all notifications of a user matching the filter as read.
+func MarkAllNotificationsAsRead(s *xorm.Session, userID int64, filter *Filter) (err error) {
 	_, err = s.
-		Where("notifiable_id = ?", userID).
+		Where(filter.toCond(userID)).
 		Cols("read_at").
 		Update(&DatabaseNotification{ReadAt: time.Now()})
 	return
 }
+
+// DeleteReadNotificationsBefore deletes all notifications which were read before the given time and returns how
+// many were deleted. Unread notifications are always kept.
+func DeleteReadNotificationsBefore(s *xorm.Session, before time.Time) (deleted int64, err error) {
+	return s.
+		Where("read_at IS NOT NULL AND read_at < ?", before).
+		Delete(&DatabaseNotification{})
+}
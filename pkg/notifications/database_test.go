@@ -0,0 +1,117 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertTestNotifications(t *testing.T) {
+	s := db.NewSession()
+	defer s.Close()
+	_, err := s.Exec("delete from notifications")
+	require.NoError(t, err)
+
+	_, err = s.Insert([]*DatabaseNotification{
+		{NotifiableID: 1, Notification: `{}`, Name: "task.comment", ProjectID: 1},
+		{NotifiableID: 1, Notification: `{}`, Name: "task.assigned", ProjectID: 1, ReadAt: time.Now().Add(-48 * time.Hour)},
+		{NotifiableID: 1, Notification: `{}`, Name: "task.comment", ProjectID: 2},
+		{NotifiableID: 2, Notification: `{}`, Name: "task.comment", ProjectID: 1},
+	})
+	require.NoError(t, err)
+}
+
+func TestGetNotificationsForUser(t *testing.T) {
+	t.Run("no filter", func(t *testing.T) {
+		insertTestNotifications(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, count, total, err := GetNotificationsForUser(s, 1, &Filter{}, 50, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.Equal(t, int64(3), total)
+	})
+	t.Run("by name", func(t *testing.T) {
+		insertTestNotifications(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		notifications, _, total, err := GetNotificationsForUser(s, 1, &Filter{Names: []string{"task.assigned"}}, 50, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "task.assigned", notifications[0].Name)
+	})
+	t.Run("by project", func(t *testing.T) {
+		insertTestNotifications(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		notifications, _, total, err := GetNotificationsForUser(s, 1, &Filter{ProjectID: 2}, 50, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, int64(2), notifications[0].ProjectID)
+	})
+	t.Run("unread only", func(t *testing.T) {
+		insertTestNotifications(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		_, _, total, err := GetNotificationsForUser(s, 1, &Filter{UnreadOnly: true, ProjectID: 1}, 50, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+	})
+}
+
+func TestMarkAllNotificationsAsRead(t *testing.T) {
+	insertTestNotifications(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	err := MarkAllNotificationsAsRead(s, 1, &Filter{ProjectID: 2})
+	require.NoError(t, err)
+
+	_, _, unread, err := GetNotificationsForUser(s, 1, &Filter{UnreadOnly: true}, 50, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), unread)
+	_, _, unread, err = GetNotificationsForUser(s, 2, &Filter{UnreadOnly: true}, 50, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), unread)
+}
+
+func TestDeleteReadNotificationsBefore(t *testing.T) {
+	insertTestNotifications(t)
+	s := db.NewSession()
+	defer s.Close()
+
+	deleted, err := DeleteReadNotificationsBefore(s, time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	db.AssertMissing(t, "notifications", map[string]interface{}{
+		"name": "task.assigned",
+	})
+
+	deleted, err = DeleteReadNotificationsBefore(s, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+}
@@ -20,5 +20,6 @@ package notifications
 func GetTables() []interface{} {
 	return []interface{}{
 		&DatabaseNotification{},
+		&DeferredNotification{},
 	}
 }
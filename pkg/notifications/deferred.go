@@ -0,0 +1,255 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+)
+
+// DeferredNotification is a notification whose delivery waits until its notifiable can be disturbed again.
+// Only the mail rendered when the notification was created is saved, all other channels show the same content.
+type DeferredNotification struct {
+	// The unique, numeric id of this deferred notification.
+	ID int64 `xorm:"bigint autoincr not null unique pk"`
+	// The ID of the notifiable this notification is delivered to.
+	NotifiableID int64 `xorm:"bigint not null index"`
+	// The name of the notification
+	Name string `xorm:"varchar(250) not null"`
+	// The name of the notification whose preferences decide through which channels this one is delivered.
+	PreferenceName string `xorm:"varchar(250) not null"`
+	// The mail of the notification, null if it has none.
+	Mail *deferredMail `xorm:"json null"`
+	// The channels of all transports which send this kind of notification.
+	Channels []Channel `xorm:"json null"`
+	// A timestamp when this notification was created.
+	Created time.Time `xorm:"created not null"`
+}
+
+// TableName returns the table name for deferred notifications
+func (d *DeferredNotification) TableName() string {
+	return "deferred_notifications"
+}
+
+type deferredMailLine struct {
+	Text   string `json:"text"`
+	IsHTML bool   `json:"is_html"`
+}
+
+type deferredMail struct {
+	From       string              `json:"from"`
+	ReplyTo    string              `json:"reply_to"`
+	Subject    string              `json:"subject"`
+	ActionText string              `json:"action_text"`
+	ActionURL  string              `json:"action_url"`
+	Greeting   string              `json:"greeting"`
+	IntroLines []*deferredMailLine `json:"intro_lines"`
+	OutroLines []*deferredMailLine `json:"outro_lines"`
+	Language   string              `json:"language"`
+}
+
+func toDeferredMailLines(lines []*mailLine) []*deferredMailLine {
+	deferred := make([]*deferredMailLine, 0, len(lines))
+	for _, line := range lines {
+		deferred = append(deferred, &deferredMailLine{Text: line.Text, IsHTML: line.isHTML})
+	}
+	return deferred
+}
+
+func fromDeferredMailLines(deferred []*deferredMailLine) []*mailLine {
+	lines := make([]*mailLine, 0, len(deferred))
+	for _, line := range deferred {
+		lines = append(lines, &mailLine{Text: line.Text, isHTML: line.IsHTML})
+	}
+	return lines
+}
+
+func newDeferredMail(mail *Mail) *deferredMail {
+	if mail == nil {
+		return nil
+	}
+	return &deferredMail{
+		From:       mail.from,
+		ReplyTo:    mail.replyTo,
+		Subject:    mail.subject,
+		ActionText: mail.actionText,
+		ActionURL:  mail.actionURL,
+		Greeting:   mail.greeting,
+		IntroLines: toDeferredMailLines(mail.introLines),
+		OutroLines: toDeferredMailLines(mail.outroLines),
+		Language:   mail.language,
+	}
+}
+
+func (m *deferredMail) toMail() *Mail {
+	if m == nil {
+		return nil
+	}
+	return &Mail{
+		from:       m.From,
+		replyTo:    m.ReplyTo,
+		subject:    m.Subject,
+		actionText: m.ActionText,
+		actionURL:  m.ActionURL,
+		greeting:   m.Greeting,
+		introLines: fromDeferredMailLines(m.IntroLines),
+		outroLines: fromDeferredMailLines(m.OutroLines),
+		language:   m.Language,
+	}
+}
+
+// savedNotification is a deferred notification loaded from the database. It is passed to the transports instead of
+// the original notification, which can't be restored.
+type savedNotification struct {
+	name           string
+	preferenceName string
+	mail           *Mail
+}
+
+func (n *savedNotification) ToMail(_ string) *Mail {
+	return n.mail
+}
+
+func (n *savedNotification) ToDB() interface{} {
+	return nil
+}
+
+func (n *savedNotification) Name() string {
+	return n.name
+}
+
+func (n *savedNotification) PreferenceName() string {
+	return n.preferenceName
+}
+
+// NotifiableLoader loads the notifiable with the id RouteForDB returned for it. It returns nil if the notifiable
+// does not exist anymore.
+type NotifiableLoader func(id int64) (Notifiable, error)
+
+func deferDelivery(notifiable Notifiable, notification Notification, mail *Mail) error {
+	// The reply address is set when the mail is sent and would be lost otherwise
+	if n, is := notification.(NotificationWithReplyTo); is && mail != nil {
+		mail.ReplyTo(n.ReplyTo(notifiable.RouteForDB()))
+	}
+
+	deferred := &DeferredNotification{
+		NotifiableID:   notifiable.RouteForDB(),
+		Name:           notification.Name(),
+		PreferenceName: preferenceName(notification),
+		Mail:           newDeferredMail(mail),
+	}
+	for _, transport := range handlingTransports(notification) {
+		deferred.Channels = append(deferred.Channels, transport.Channel())
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	_, err := s.Insert(deferred)
+	return err
+}
+
+// DeliverDeferredNotifications delivers all deferred notifications whose notifiable can be disturbed again.
+// The do not disturb time is checked again for every notifiable so that ending a snooze early or extending it
+// takes effect immediately.
+func DeliverDeferredNotifications(now time.Time, load NotifiableLoader) {
+	s := db.NewSession()
+	defer s.Close()
+
+	deferred := []*DeferredNotification{}
+	err := s.OrderBy("id asc").Find(&deferred)
+	if err != nil {
+		log.Errorf("Could not get deferred notifications: %s", err)
+		return
+	}
+
+	notifiables := make(map[int64]Notifiable)
+	disturbable := make(map[int64]bool)
+	for _, d := range deferred {
+		can, checked := disturbable[d.NotifiableID]
+		if !checked {
+			notifiable, err := load(d.NotifiableID)
+			if err != nil {
+				log.Errorf("Could not load notifiable %d: %s", d.NotifiableID, err)
+			}
+			// Notifications of notifiables which don't exist anymore are only deleted
+			can = err == nil
+			if notifiable != nil && can {
+				until, err := doNotDisturbUntil(notifiable, now)
+				if err != nil {
+					log.Errorf("Could not check whether %d can be notified: %s", d.NotifiableID, err)
+				}
+				can = err == nil && until.IsZero()
+			}
+			notifiables[d.NotifiableID] = notifiable
+			disturbable[d.NotifiableID] = can
+		}
+
+		if !can {
+			continue
+		}
+
+		// Every instance runs this cron. Only the one which deleted the notification delivers it.
+		deleted, err := s.Where("id = ?", d.ID).Delete(&DeferredNotification{})
+		if err != nil {
+			log.Errorf("Could not delete deferred notification %d: %s", d.ID, err)
+			continue
+		}
+		notifiable := notifiables[d.NotifiableID]
+		if deleted == 0 || notifiable == nil {
+			continue
+		}
+
+		err = d.deliver(notifiable)
+		if err != nil {
+			log.Errorf("Could not deliver deferred notification %s to %d: %s", d.Name, d.NotifiableID, err)
+		}
+	}
+}
+
+func (d *DeferredNotification) deliver(notifiable Notifiable) error {
+	notification := &savedNotification{
+		name:           d.Name,
+		preferenceName: d.PreferenceName,
+		mail:           d.Mail.toMail(),
+	}
+
+	handling := []Transport{}
+	for _, transport := range transports {
+		for _, channel := range d.Channels {
+			if transport.Channel() == channel {
+				handling = append(handling, transport)
+				break
+			}
+		}
+	}
+
+	return deliverVia(notifiable, notification, notification.mail, handling)
+}
+
+// RegisterDeferredNotificationsCron checks every minute for deferred notifications which can be delivered.
+func RegisterDeferredNotificationsCron(load NotifiableLoader) {
+	err := cron.Schedule("* * * * *", func() {
+		DeliverDeferredNotifications(time.Now(), load)
+	})
+	if err != nil {
+		log.Fatalf("Could not register deferred notifications cron: %s", err)
+	}
+}
@@ -22,12 +22,15 @@ import "code.vikunja.io/api/pkg/mail"
 type Mail struct {
 	from       string
 	to         string
+	replyTo    string
 	subject    string
 	actionText string
 	actionURL  string
 	greeting   string
 	introLines []*mailLine
 	outroLines []*mailLine
+	// The language of the parts of the mail which are the same for all mails
+	language string
 }
 
 type mailLine struct {
@@ -52,6 +55,12 @@ func (m *Mail) To(to string) *Mail {
 	return m
 }
 
+// ReplyTo sets the address replies to the mail message should go to
+func (m *Mail) ReplyTo(replyTo string) *Mail {
+	m.replyTo = replyTo
+	return m
+}
+
 // Subject sets the subject of the mail message
 func (m *Mail) Subject(subject string) *Mail {
 	m.subject = subject
@@ -26,6 +26,7 @@ import (
 	"github.com/microcosm-cc/bluemonday"
 
 	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/i18n"
 	"code.vikunja.io/api/pkg/mail"
 	"code.vikunja.io/api/pkg/utils"
 
@@ -77,7 +78,7 @@ const mailTemplateHTML = `
 
 {{ if .ActionURL }}
 	<p style="color: #9CA3AF;font-size:12px;border-top: 1px solid #dbdbdb;margin-top:20px;padding-top:20px;">
-		If the button above doesn't work, copy the url below and paste it in your browser's address bar:<br/>
+		{{ .CopyURLHint }}<br/>
 		{{ .ActionURL }}
 	</p>
 {{ end }}
@@ -118,6 +119,7 @@ func RenderMail(m *Mail) (mailOpts *mail.Opts, err error) {
 	data["ActionURL"] = m.actionURL
 	data["Boundary"] = boundary
 	data["FrontendURL"] = config.ServicePublicURL.GetString()
+	data["CopyURLHint"] = i18n.T(m.language, "notifications.common.copy_url")
 
 	p := bluemonday.UGCPolicy()
 
@@ -170,6 +172,7 @@ func RenderMail(m *Mail) (mailOpts *mail.Opts, err error) {
 
 	mailOpts = &mail.Opts{
 		From:        m.from,
+		ReplyTo:     m.replyTo,
 		To:          m.to,
 		Subject:     m.subject,
 		ContentType: mail.ContentTypeMultipart,
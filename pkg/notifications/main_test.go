@@ -34,7 +34,7 @@ func SetupTests() {
 		log.Fatal(err)
 	}
 
-	err = x.Sync2(&DatabaseNotification{})
+	err = x.Sync2(&DatabaseNotification{}, &DeferredNotification{})
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,81 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"code.vikunja.io/api/pkg/utils"
+	"code.vikunja.io/api/pkg/version"
+)
+
+type matrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+// SendMatrixMessage sends a message to a matrix room. The message is sent by the matrix user the access token
+// belongs to, who needs to have joined the room already.
+func SendMatrixMessage(client *http.Client, homeserver, accessToken, roomID string, msg *ChatMessage) error {
+	payload, err := json.Marshal(&matrixMessage{
+		MsgType:       "m.text",
+		Body:          msg.Markdown(),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: msg.HTML(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Matrix uses the transaction id to deduplicate retried requests, it only needs to be unique per access token
+	txnID, err := utils.CryptoRandomString(32)
+	if err != nil {
+		return err
+	}
+	sendURL := strings.TrimRight(homeserver, "/") +
+		"/_matrix/client/v3/rooms/" + url.PathEscape(roomID) +
+		"/send/m.room.message/" + txnID
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPut, sendURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("User-Agent", "Vikunja/"+version.Version)
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", "Bearer "+accessToken)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 399 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("matrix homeserver responded with status %d: %s", res.StatusCode, body)
+	}
+	return nil
+}
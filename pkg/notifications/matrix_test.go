@@ -0,0 +1,63 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendMatrixMessage(t *testing.T) {
+	msg := &ChatMessage{Title: "Task (#1)", Lines: []string{"**user1** created this task."}}
+
+	t.Run("sends the message", func(t *testing.T) {
+		var received map[string]string
+		var path, auth string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.EscapedPath()
+			auth = r.Header.Get("Authorization")
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &received)
+		}))
+		defer srv.Close()
+
+		err := SendMatrixMessage(srv.Client(), srv.URL+"/", "token", "!room:example.com", msg)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(path, "/_matrix/client/v3/rooms/%21room:example.com/send/m.room.message/"))
+		assert.Equal(t, "Bearer token", auth)
+		assert.Equal(t, "m.text", received["msgtype"])
+		assert.Equal(t, "**Task (#1)**\n\n**user1** created this task.", received["body"])
+		assert.Equal(t, "<p><strong>Task (#1)</strong></p><p><strong>user1</strong> created this task.</p>", received["formatted_body"])
+	})
+	t.Run("error status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"errcode":"M_FORBIDDEN"}`, http.StatusForbidden)
+		}))
+		defer srv.Close()
+
+		err := SendMatrixMessage(srv.Client(), srv.URL, "token", "!room:example.com", msg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "M_FORBIDDEN")
+	})
+}
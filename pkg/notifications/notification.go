@@ -18,6 +18,7 @@ package notifications
 
 import (
 	"encoding/json"
+	"time"
 
 	"code.vikunja.io/api/pkg/db"
 	"code.vikunja.io/api/pkg/log"
@@ -25,7 +26,8 @@ import (
 
 // Notification is a notification which can be sent via mail or db.
 type Notification interface {
-	ToMail() *Mail
+	// ToMail returns the mail for the notification in the language lang, or nil if it has none.
+	ToMail(lang string) *Mail
 	ToDB() interface{}
 	Name() string
 }
@@ -39,6 +41,20 @@ type NotificationWithSubject interface {
 	SubjectID
 }
 
+// NotificationWithProject is a notification about a project. The project is saved with the notification in the
+// database so notifications can be filtered by it.
+type NotificationWithProject interface {
+	Notification
+	ProjectID() int64
+}
+
+// NotificationWithReplyTo is a notification whose mail can be answered. The reply address may be different for
+// every recipient, an empty address means replies go to the sender as usual.
+type NotificationWithReplyTo interface {
+	Notification
+	ReplyTo(notifiableID int64) string
+}
+
 // Notifiable is an entity which can be notified. Usually a user.
 type Notifiable interface {
 	// RouteForMail should return the email address this notifiable has.
@@ -48,6 +64,91 @@ type Notifiable interface {
 	// ShouldNotify provides a last-minute way to cancel a notification. It will be called immediately before
 	// sending a notification.
 	ShouldNotify() (should bool, err error)
+	// Lang returns the language notifications should be sent in. If empty or not translated, the default
+	// language is used.
+	Lang() string
+}
+
+// Channel is a way a notification can reach a notifiable
+type Channel string
+
+const (
+	ChannelMail     Channel = "email"
+	ChannelDB       Channel = "in_app"
+	ChannelPush     Channel = "push"
+	ChannelWebhook  Channel = "webhook"
+	ChannelTelegram Channel = "telegram"
+)
+
+// Channels are all channels notifications can be sent through
+var Channels = []Channel{ChannelMail, ChannelDB, ChannelPush, ChannelWebhook, ChannelTelegram}
+
+// NotifiableWithPreferences is a notifiable which decides itself through which channels it wants to receive which
+// notifications. Notifiables without preferences get all notifications through all channels.
+type NotifiableWithPreferences interface {
+	Notifiable
+	ShouldNotifyVia(channel Channel, notificationName string) (should bool, err error)
+}
+
+// Transport delivers notifications through a channel other than mail or the database
+type Transport interface {
+	Channel() Channel
+	// Handles returns whether the transport sends this kind of notification at all
+	Handles(notification Notification) bool
+	// Send is only called with notifications the transport handles. Deferred notifications are not passed as their
+	// original type but as a notification which only has the mail rendered when they were created.
+	Send(notifiable Notifiable, notification Notification) error
+}
+
+var transports []Transport
+
+// RegisterTransport adds a transport every notification is sent through in addition to mail and the database
+func RegisterTransport(transport Transport) {
+	transports = append(transports, transport)
+}
+
+// NotifiableWithQuietHours is a notifiable which does not want to be disturbed at times. Notifications are still
+// created and saved in the database right away, but their delivery through all other channels waits until the
+// notifiable can be disturbed again.
+type NotifiableWithQuietHours interface {
+	Notifiable
+	// DoNotDisturbUntil returns until when the notifiable does not want to be disturbed, or the zero time if
+	// notifications can be delivered right now.
+	DoNotDisturbUntil(now time.Time) (until time.Time, err error)
+}
+
+func doNotDisturbUntil(notifiable Notifiable, now time.Time) (time.Time, error) {
+	n, has := notifiable.(NotifiableWithQuietHours)
+	if !has {
+		return time.Time{}, nil
+	}
+	until, err := n.DoNotDisturbUntil(now)
+	if err != nil || !until.After(now) {
+		return time.Time{}, err
+	}
+	return until, nil
+}
+
+// NotificationWithPreferenceName is a notification which uses the preferences of another notification, for example
+// because it combines several of them.
+type NotificationWithPreferenceName interface {
+	Notification
+	PreferenceName() string
+}
+
+func preferenceName(notification Notification) string {
+	if p, is := notification.(NotificationWithPreferenceName); is {
+		return p.PreferenceName()
+	}
+	return notification.Name()
+}
+
+func shouldNotifyVia(notifiable Notifiable, channel Channel, notification Notification) (bool, error) {
+	n, has := notifiable.(NotifiableWithPreferences)
+	if !has {
+		return true, nil
+	}
+	return n.ShouldNotifyVia(channel, preferenceName(notification))
 }
 
 // Notify notifies a notifiable of a notification
@@ -57,22 +158,96 @@ func Notify(notifiable Notifiable, notification Notification) (err error) {
 		return nil
 	}
 
+	if n, is := notification.(BatchableNotification); is && batchWindow() > 0 {
+		addToBatch(notifiable, n)
+		return nil
+	}
+
+	return notify(notifiable, notification)
+}
+
+func notify(notifiable Notifiable, notification Notification) (err error) {
 	should, err := notifiable.ShouldNotify()
 	if err != nil || !should {
 		log.Debugf("Not notifying user %d because they are disabled", notifiable.RouteForDB())
 		return err
 	}
 
-	err = notifyMail(notifiable, notification)
+	until, err := doNotDisturbUntil(notifiable, time.Now())
 	if err != nil {
-		return
+		return err
+	}
+
+	// The mail is rendered right away so that a deferred notification shows the state at the time it was created.
+	lang := notifiable.Lang()
+	mail := notification.ToMail(lang)
+	if mail != nil {
+		mail.language = lang
+	}
+	if until.IsZero() {
+		err = deliver(notifiable, notification, mail)
+	} else {
+		err = deferDelivery(notifiable, notification, mail)
+	}
+	if err != nil {
+		return err
+	}
+
+	should, err = shouldNotifyVia(notifiable, ChannelDB, notification)
+	if err != nil || !should {
+		return err
 	}
 
 	return notifyDB(notifiable, notification)
 }
 
-func notifyMail(notifiable Notifiable, notification Notification) error {
-	mail := notification.ToMail()
+// handlingTransports returns all transports which send this kind of notification
+func handlingTransports(notification Notification) []Transport {
+	handling := []Transport{}
+	for _, transport := range transports {
+		if transport.Handles(notification) {
+			handling = append(handling, transport)
+		}
+	}
+	return handling
+}
+
+// deliver sends a notification through all channels except the database
+func deliver(notifiable Notifiable, notification Notification, mail *Mail) error {
+	return deliverVia(notifiable, notification, mail, handlingTransports(notification))
+}
+
+func deliverVia(notifiable Notifiable, notification Notification, mail *Mail, via []Transport) error {
+	should, err := shouldNotifyVia(notifiable, ChannelMail, notification)
+	if err != nil {
+		return err
+	}
+	if should {
+		err = notifyMail(notifiable, notification, mail)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, transport := range via {
+		should, err = shouldNotifyVia(notifiable, transport.Channel(), notification)
+		if err != nil {
+			return err
+		}
+		if !should {
+			continue
+		}
+		// One unreachable transport should not keep the notification from being delivered through the others
+		err = transport.Send(notifiable, notification)
+		if err != nil {
+			log.Errorf("Could not send notification %s to %d via %s: %s", notification.Name(), notifiable.RouteForDB(), transport.Channel(), err)
+		}
+	}
+
+	return nil
+}
+
+func notifyMail(notifiable Notifiable, notification Notification, mail *Mail) error {
 	if mail == nil {
 		return nil
 	}
@@ -83,6 +258,10 @@ func notifyMail(notifiable Notifiable, notification Notification) error {
 	}
 	mail.To(to)
 
+	if n, is := notification.(NotificationWithReplyTo); is {
+		mail.ReplyTo(n.ReplyTo(notifiable.RouteForDB()))
+	}
+
 	return SendMail(mail)
 }
 
@@ -109,6 +288,10 @@ func notifyDB(notifiable Notifiable, notification Notification) (err error) {
 		dbNotification.SubjectID = subject.SubjectID()
 	}
 
+	if project, is := notification.(NotificationWithProject); is {
+		dbNotification.ProjectID = project.ProjectID()
+	}
+
 	_, err = s.Insert(dbNotification)
 	if err != nil {
 		_ = s.Rollback()
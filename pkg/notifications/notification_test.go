@@ -18,9 +18,11 @@ package notifications
 
 import (
 	"testing"
+	"time"
 
 	"code.vikunja.io/api/pkg/db"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"xorm.io/xorm/schemas"
 )
@@ -31,7 +33,7 @@ type testNotification struct {
 }
 
 // ToMail returns the mail notification for testNotification
-func (n *testNotification) ToMail() *Mail {
+func (n *testNotification) ToMail(_ string) *Mail {
 	return NewMail().
 		Subject("Test Notification").
 		Line(n.Test)
@@ -68,6 +70,45 @@ func (t *testNotifiable) ShouldNotify() (should bool, err error) {
 	return t.ShouldSendNotification, nil
 }
 
+func (t *testNotifiable) Lang() string {
+	return "en"
+}
+
+type testNotifiableWithPreferences struct {
+	testNotifiable
+	disabled Channel
+}
+
+func (t *testNotifiableWithPreferences) ShouldNotifyVia(channel Channel, _ string) (should bool, err error) {
+	return channel != t.disabled, nil
+}
+
+type testNotifiableWithQuietHours struct {
+	testNotifiable
+	until time.Time
+}
+
+func (t *testNotifiableWithQuietHours) DoNotDisturbUntil(_ time.Time) (until time.Time, err error) {
+	return t.until, nil
+}
+
+type testTransport struct {
+	sent []Notification
+}
+
+func (t *testTransport) Channel() Channel {
+	return ChannelPush
+}
+
+func (t *testTransport) Handles(_ Notification) bool {
+	return true
+}
+
+func (t *testTransport) Send(_ Notifiable, notification Notification) error {
+	t.sent = append(t.sent, notification)
+	return nil
+}
+
 func TestNotify(t *testing.T) {
 	t.Run("normal", func(t *testing.T) {
 
@@ -124,4 +165,123 @@ func TestNotify(t *testing.T) {
 			"notifiable_id": 42,
 		})
 	})
+	t.Run("preferences", func(t *testing.T) {
+
+		s := db.NewSession()
+		defer s.Close()
+		_, err := s.Exec("delete from notifications")
+		require.NoError(t, err)
+
+		transport := &testTransport{}
+		RegisterTransport(transport)
+		defer func() {
+			transports = nil
+		}()
+
+		tn := &testNotification{
+			Test:       "somethingsomething",
+			OtherValue: 42,
+		}
+		tnf := &testNotifiableWithPreferences{
+			testNotifiable: testNotifiable{ShouldSendNotification: true},
+			disabled:       ChannelDB,
+		}
+
+		err = Notify(tnf, tn)
+		require.NoError(t, err)
+		db.AssertMissing(t, "notifications", map[string]interface{}{
+			"notifiable_id": 42,
+		})
+		require.Len(t, transport.sent, 1)
+
+		tnf.disabled = ChannelPush
+		err = Notify(tnf, tn)
+		require.NoError(t, err)
+		db.AssertExists(t, "notifications", map[string]interface{}{
+			"notifiable_id": 42,
+		}, false)
+		require.Len(t, transport.sent, 1)
+	})
+	t.Run("do not disturb", func(t *testing.T) {
+
+		s := db.NewSession()
+		defer s.Close()
+		_, err := s.Exec("delete from notifications")
+		require.NoError(t, err)
+
+		transport := &testTransport{}
+		RegisterTransport(transport)
+		defer func() {
+			transports = nil
+			_, _ = s.Exec("delete from deferred_notifications")
+		}()
+
+		tn := &testNotification{
+			Test:       "somethingsomething",
+			OtherValue: 42,
+		}
+		tnf := &testNotifiableWithQuietHours{
+			testNotifiable: testNotifiable{ShouldSendNotification: true},
+			until:          time.Now().Add(time.Hour),
+		}
+		load := func(id int64) (Notifiable, error) {
+			require.Equal(t, int64(42), id)
+			return tnf, nil
+		}
+
+		err = Notify(tnf, tn)
+		require.NoError(t, err)
+		// Only the delivery is deferred, the notification is created right away
+		db.AssertExists(t, "notifications", map[string]interface{}{
+			"notifiable_id": 42,
+		}, false)
+		db.AssertExists(t, "deferred_notifications", map[string]interface{}{
+			"notifiable_id":   42,
+			"name":            "test.notification",
+			"preference_name": "test.notification",
+		}, false)
+		require.Empty(t, transport.sent)
+
+		DeliverDeferredNotifications(time.Now(), load)
+		require.Empty(t, transport.sent)
+
+		tnf.until = time.Time{}
+		DeliverDeferredNotifications(time.Now(), load)
+		require.Len(t, transport.sent, 1)
+		assert.Equal(t, "test.notification", transport.sent[0].Name())
+		mail := transport.sent[0].ToMail("en")
+		require.NotNil(t, mail)
+		assert.Equal(t, "Test Notification", mail.subject)
+		assert.Equal(t, "somethingsomething", mail.introLines[0].Text)
+		db.AssertMissing(t, "deferred_notifications", map[string]interface{}{
+			"notifiable_id": 42,
+		})
+	})
+	t.Run("deferred notifiable deleted", func(t *testing.T) {
+
+		s := db.NewSession()
+		defer s.Close()
+
+		transport := &testTransport{}
+		RegisterTransport(transport)
+		defer func() {
+			transports = nil
+		}()
+
+		tnf := &testNotifiableWithQuietHours{
+			testNotifiable: testNotifiable{ShouldSendNotification: true},
+			until:          time.Now().Add(time.Hour),
+		}
+
+		err := Notify(tnf, &testNotification{Test: "somethingsomething"})
+		require.NoError(t, err)
+
+		DeliverDeferredNotifications(time.Now(), func(_ int64) (Notifiable, error) {
+			return nil, nil
+		})
+		require.Empty(t, transport.sent)
+		db.AssertMissing(t, "deferred_notifications", map[string]interface{}{
+			"notifiable_id": 42,
+		})
+	})
 }
@@ -0,0 +1,54 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+)
+
+// RegisterNotificationRetentionCron deletes read notifications once they are older than the configured retention.
+// Unread notifications are never deleted.
+func RegisterNotificationRetentionCron() {
+	if config.ServiceNotificationRetentionDays.GetInt() <= 0 {
+		return
+	}
+
+	const logPrefix = "[Notification Retention Cron] "
+
+	err := cron.Schedule("0 * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		retention := time.Duration(config.ServiceNotificationRetentionDays.GetInt()) * 24 * time.Hour
+		deleted, err := DeleteReadNotificationsBefore(s, time.Now().Add(-retention))
+		if err != nil {
+			log.Errorf(logPrefix+"Could not delete old notifications: %s", err)
+			return
+		}
+		if deleted > 0 {
+			log.Debugf(logPrefix+"Deleted %d old read notifications", deleted)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register notification retention cron: %s", err)
+	}
+}
@@ -0,0 +1,118 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"code.vikunja.io/api/pkg/version"
+)
+
+var telegramAPIURL = "https://api.telegram.org"
+
+// TelegramUpdate is what the telegram bot api sends to the webhook of a bot. Vikunja only cares about messages.
+type TelegramUpdate struct {
+	UpdateID int64            `json:"update_id"`
+	Message  *TelegramMessage `json:"message"`
+}
+
+// TelegramMessage is a message someone sent to the bot
+type TelegramMessage struct {
+	MessageID int64 `json:"message_id"`
+	Chat      struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+	Text     string `json:"text"`
+	Entities []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"entities"`
+	// The message this one is a reply to, if any
+	ReplyToMessage *TelegramMessage `json:"reply_to_message"`
+}
+
+// LinkURLs returns the targets of all html links in the message
+func (m *TelegramMessage) LinkURLs() (urls []string) {
+	for _, entity := range m.Entities {
+		if entity.Type == "text_link" {
+			urls = append(urls, entity.URL)
+		}
+	}
+	return
+}
+
+// TelegramSendMessage calls the sendMessage method of the bot api. A bot can also return it as response to a
+// webhook request with Method set, telegram then sends the message without another request.
+type TelegramSendMessage struct {
+	Method    string `json:"method,omitempty"`
+	ChatID    int64  `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode,omitempty"`
+}
+
+// Telegram formats the message in the subset of html telegram understands
+func (m *ChatMessage) Telegram() string {
+	parts := []string{"<b>" + html.EscapeString(m.Title) + "</b>"}
+	for _, line := range m.Lines {
+		parts = append(parts, markdownBold.ReplaceAllString(html.EscapeString(line), "<b>$1</b>"))
+	}
+	if m.ActionURL != "" {
+		parts = append(parts, `<a href="`+html.EscapeString(m.ActionURL)+`">`+html.EscapeString(m.ActionText)+"</a>")
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// SendTelegramMessage sends a message through the bot the token belongs to
+func SendTelegramMessage(client *http.Client, botToken string, msg *TelegramSendMessage) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, telegramAPIURL+"/bot"+botToken+"/sendMessage", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("User-Agent", "Vikunja/"+version.Version)
+	req.Header.Add("Content-Type", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		// The request url contains the bot token which should not end up in the logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("could not reach the telegram bot api: %w", urlErr.Err)
+		}
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 399 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("telegram bot api responded with status %d: %s", res.StatusCode, body)
+	}
+	return nil
+}
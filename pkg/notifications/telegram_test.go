@@ -0,0 +1,69 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatMessage_Telegram(t *testing.T) {
+	msg := &ChatMessage{
+		Title:      "Reminder for <task>",
+		Lines:      []string{"**user1** assigned you."},
+		ActionText: "View Task",
+		ActionURL:  "https://example.com/tasks/1",
+	}
+	assert.Equal(t, "<b>Reminder for &lt;task&gt;</b>\n\n<b>user1</b> assigned you.\n\n<a href=\"https://example.com/tasks/1\">View Task</a>", msg.Telegram())
+}
+
+func TestSendTelegramMessage(t *testing.T) {
+	var path string
+	var received TelegramSendMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		if received.ChatID == 0 {
+			http.Error(w, `{"ok":false,"description":"Bad Request: chat not found"}`, http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	oldURL := telegramAPIURL
+	telegramAPIURL = srv.URL
+	defer func() { telegramAPIURL = oldURL }()
+
+	t.Run("sends the message", func(t *testing.T) {
+		err := SendTelegramMessage(srv.Client(), "123:token", &TelegramSendMessage{ChatID: 42, Text: "hello", ParseMode: "HTML"})
+		require.NoError(t, err)
+		assert.Equal(t, "/bot123:token/sendMessage", path)
+		assert.Equal(t, int64(42), received.ChatID)
+		assert.Equal(t, "hello", received.Text)
+	})
+	t.Run("error status", func(t *testing.T) {
+		err := SendTelegramMessage(srv.Client(), "123:token", &TelegramSendMessage{Text: "hello"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "chat not found")
+	})
+}
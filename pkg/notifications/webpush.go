@@ -0,0 +1,84 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"code.vikunja.io/api/pkg/version"
+
+	"github.com/SherClockHolmes/webpush-go"
+)
+
+// WebPushSubscription is what the browser returns when subscribing to push messages
+type WebPushSubscription struct {
+	Endpoint string
+	// The public key of the browser, base64url encoded
+	P256dh string
+	// The authentication secret of the browser, base64url encoded
+	Auth string
+}
+
+// GenerateVAPIDKeys creates a new key pair to identify the server to push services. Both keys are base64url
+// encoded, the same format other web push libraries use.
+func GenerateVAPIDKeys() (publicKey, privateKey string, err error) {
+	privateKey, publicKey, err = webpush.GenerateVAPIDKeys()
+	return publicKey, privateKey, err
+}
+
+// userAgentClient sets the user agent on the requests to the push services, the web push library does not.
+type userAgentClient struct {
+	client *http.Client
+}
+
+func (c *userAgentClient) Do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", "Vikunja/"+version.Version)
+	return c.client.Do(req)
+}
+
+// SendWebPush encrypts a payload and sends it to the push service of a subscription. Returns the status code of
+// the push service, 404 or 410 mean the subscription has expired and should be removed.
+func SendWebPush(client *http.Client, sub *WebPushSubscription, payload []byte, subject, vapidPublicKey, vapidPrivateKey string) (statusCode int, err error) {
+	res, err := webpush.SendNotificationWithContext(context.Background(), payload, &webpush.Subscription{
+		Endpoint: sub.Endpoint,
+		Keys: webpush.Keys{
+			Auth:   sub.Auth,
+			P256dh: sub.P256dh,
+		},
+	}, &webpush.Options{
+		HTTPClient: &userAgentClient{client: client},
+		// The library adds the mailto: itself to everything which is not an https url
+		Subscriber:      strings.TrimPrefix(subject, "mailto:"),
+		TTL:             86400,
+		VAPIDPublicKey:  vapidPublicKey,
+		VAPIDPrivateKey: vapidPrivateKey,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 399 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return res.StatusCode, fmt.Errorf("push service responded with status %d: %s", res.StatusCode, msg)
+	}
+	return res.StatusCode, nil
+}
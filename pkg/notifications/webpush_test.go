@@ -0,0 +1,150 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notifications
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SherClockHolmes/webpush-go"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/hkdf"
+)
+
+func hkdfExpand(t *testing.T, secret, salt, info []byte, length int) []byte {
+	out := make([]byte, length)
+	_, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), out)
+	require.NoError(t, err)
+	return out
+}
+
+// decryptWebPushPayload does what the browser does with a push message
+func decryptWebPushPayload(t *testing.T, uaPrivate *ecdh.PrivateKey, authSecret, body []byte) []byte {
+	salt := body[:16]
+	rs := binary.BigEndian.Uint32(body[16:20])
+	assert.Equal(t, uint32(webpush.MaxRecordSize), rs)
+	keyLength := int(body[20])
+	asPublicRaw := body[21 : 21+keyLength]
+	ciphertext := body[21+keyLength:]
+
+	asPublic, err := ecdh.P256().NewPublicKey(asPublicRaw)
+	require.NoError(t, err)
+	ecdhSecret, err := uaPrivate.ECDH(asPublic)
+	require.NoError(t, err)
+
+	keyInfo := append([]byte("WebPush: info\x00"), uaPrivate.PublicKey().Bytes()...)
+	keyInfo = append(keyInfo, asPublicRaw...)
+	ikm := hkdfExpand(t, ecdhSecret, authSecret, keyInfo, 32)
+	cek := hkdfExpand(t, ikm, salt, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdfExpand(t, ikm, salt, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(cek)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	require.NoError(t, err)
+
+	// The payload is followed by the 0x02 delimiter and zero padding
+	plaintext = bytes.TrimRight(plaintext, "\x00")
+	require.Equal(t, byte(0x02), plaintext[len(plaintext)-1])
+	return plaintext[:len(plaintext)-1]
+}
+
+func TestSendWebPush(t *testing.T) {
+	uaPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	authSecret := make([]byte, 16)
+	_, err = rand.Read(authSecret)
+	require.NoError(t, err)
+
+	vapidPublic, vapidPrivate, err := GenerateVAPIDKeys()
+	require.NoError(t, err)
+
+	var body []byte
+	var headers http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		body, _ = io.ReadAll(r.Body)
+		if strings.HasSuffix(r.URL.Path, "/expired") {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	sub := &WebPushSubscription{
+		Endpoint: srv.URL + "/push/1",
+		P256dh:   base64.RawURLEncoding.EncodeToString(uaPrivate.PublicKey().Bytes()),
+		// Browsers sometimes include padding
+		Auth: base64.URLEncoding.EncodeToString(authSecret),
+	}
+
+	t.Run("sends the encrypted payload", func(t *testing.T) {
+		status, err := SendWebPush(srv.Client(), sub, []byte(`{"title":"Reminder"}`), "mailto:admin@example.com", vapidPublic, vapidPrivate)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, status)
+		assert.Equal(t, "aes128gcm", headers.Get("Content-Encoding"))
+		assert.True(t, strings.HasPrefix(headers.Get("User-Agent"), "Vikunja/"))
+
+		assert.Equal(t, `{"title":"Reminder"}`, string(decryptWebPushPayload(t, uaPrivate, authSecret, body)))
+
+		authorization := headers.Get("Authorization")
+		require.True(t, strings.HasPrefix(authorization, "vapid t="))
+		token, key, _ := strings.Cut(strings.TrimPrefix(authorization, "vapid t="), ", k=")
+		assert.Equal(t, vapidPublic, key)
+
+		// The uncompressed public key is 0x04 || X || Y
+		publicKey, err := base64.RawURLEncoding.DecodeString(vapidPublic)
+		require.NoError(t, err)
+		claims := jwt.MapClaims{}
+		_, err = jwt.ParseWithClaims(token, claims, func(_ *jwt.Token) (interface{}, error) {
+			return &ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(publicKey[1:33]),
+				Y:     new(big.Int).SetBytes(publicKey[33:]),
+			}, nil
+		}, jwt.WithValidMethods([]string{"ES256"}))
+		require.NoError(t, err)
+		assert.Equal(t, srv.URL, claims["aud"])
+		assert.Equal(t, "mailto:admin@example.com", claims["sub"])
+	})
+	t.Run("expired subscription", func(t *testing.T) {
+		expired := *sub
+		expired.Endpoint = srv.URL + "/push/expired"
+		status, err := SendWebPush(srv.Client(), &expired, []byte(`{}`), "mailto:admin@example.com", vapidPublic, vapidPrivate)
+		require.Error(t, err)
+		assert.Equal(t, http.StatusGone, status)
+	})
+}
@@ -0,0 +1,612 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
+	auth2 "code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/api/pkg/notifications"
+	"code.vikunja.io/api/pkg/user"
+
+	"code.vikunja.io/web/handler"
+	"github.com/asaskevich/govalidator"
+	"github.com/labstack/echo/v4"
+	"xorm.io/xorm"
+)
+
+// AdminUser is a user as instance admins see it.
+type AdminUser struct {
+	// The unique, numeric id of this user.
+	ID int64 `json:"id"`
+	// The full name of the user.
+	Name string `json:"name"`
+	// The username of the user.
+	Username string `json:"username"`
+	// The user's email address.
+	Email string `json:"email"`
+	// The status of the user. 0 means active, 1 means the user still needs to confirm their email address and 2 means the account is disabled.
+	Status user.Status `json:"status"`
+	// Where the user authenticates, for example "local", "ldap" or the issuer url of an openid provider.
+	Issuer string `json:"issuer"`
+	// Whether the user is an instance admin.
+	IsAdmin bool `json:"is_admin"`
+	// If the user requested the deletion of their account, this is when it will be deleted.
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+	// A timestamp when this user was created.
+	Created time.Time `json:"created"`
+	// A timestamp when this user was last updated.
+	Updated time.Time `json:"updated"`
+}
+
+func newAdminUser(u *user.User) *AdminUser {
+	return &AdminUser{
+		ID:                  u.ID,
+		Name:                u.Name,
+		Username:            u.Username,
+		Email:               u.Email,
+		Status:              u.Status,
+		Issuer:              u.Issuer,
+		IsAdmin:             u.IsAdmin,
+		DeletionScheduledAt: u.DeletionScheduledAt,
+		Created:             u.Created,
+		Updated:             u.Updated,
+	}
+}
+
+// AdminUserStatus is used to enable or disable a user.
+type AdminUserStatus struct {
+	// If true, the user can no longer log in.
+	Disabled bool `json:"disabled"`
+}
+
+// AdminUserPassword is used to reset the password of a user.
+type AdminUserPassword struct {
+	// The new password of the user. If empty, the user gets a password reset email instead.
+	Password string `json:"password"`
+}
+
+// AdminUserEmail is used to change the email address of a user.
+type AdminUserEmail struct {
+	// The new email address of the user.
+	Email string `json:"email"`
+}
+
+// RequireInstanceAdmin is a middleware which only lets instance admins through.
+func RequireInstanceAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		a, err := auth2.GetAuthFromClaims(c)
+		if err != nil {
+			return handler.HandleHTTPError(err, c)
+		}
+		u, is := a.(*user.User)
+		if !is {
+			return handler.HandleHTTPError(&user.ErrUserIsNotAdmin{}, c)
+		}
+
+		s := db.NewSession()
+		defer s.Close()
+
+		err = user.CheckUserIsAdmin(s, u)
+		if err != nil {
+			return handler.HandleHTTPError(err, c)
+		}
+
+		return next(c)
+	}
+}
+
+func getUserFromParam(s *xorm.Session, c echo.Context) (*user.User, error) {
+	userID, err := strconv.ParseInt(c.Param("user"), 10, 64)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID provided")
+	}
+	return user.GetUserWithEmail(s, &user.User{ID: userID})
+}
+
+func bindAdminRequest(c echo.Context, i interface{}) error {
+	if err := c.Bind(i); err != nil {
+		log.Debugf("Invalid model error. Internal error was: %s", err.Error())
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid model provided. Error was: %s", he.Message))
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid model provided.")
+	}
+	return nil
+}
+
+// checkNotSelf prevents admins from locking themselves out.
+func checkNotSelf(c echo.Context, u *user.User) error {
+	a, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return err
+	}
+	if a.GetID() == u.ID {
+		return &user.ErrAdminCannotManageSelf{UserID: u.ID}
+	}
+	return nil
+}
+
+// AdminListUsers returns all users of the instance
+// @Summary List all users
+// @Description Lists all users of this instance, including their email addresses. Only available to instance admins.
+// @tags admin
+// @Produce json
+// @Security JWTKeyAuth
+// @Param s query string false "Only return users whose username, name or email address contain this string."
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Success 200 {array} v1.AdminUser "The users."
+// @Failure 403 {object} web.HTTPError "The user is not an instance admin."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /admin/users [get]
+func AdminListUsers(c echo.Context) error {
+	page, err := strconv.Atoi(c.QueryParam("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	maxPerPage := config.ServiceMaxItemsPerPage.GetInt()
+	perPage, err := strconv.Atoi(c.QueryParam("per_page"))
+	if err != nil || perPage < 1 || perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	users, totalItems, err := user.ListUsersForAdmin(s, c.QueryParam("s"), page, perPage)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	result := make([]*AdminUser, 0, len(users))
+	for _, u := range users {
+		result = append(result, newAdminUser(u))
+	}
+
+	totalPages := int64(math.Ceil(float64(totalItems) / float64(perPage)))
+	c.Response().Header().Set("x-pagination-total-pages", strconv.FormatInt(totalPages, 10))
+	c.Response().Header().Set("x-pagination-result-count", strconv.Itoa(len(result)))
+	c.Response().Header().Set("Access-Control-Expose-Headers", "x-pagination-total-pages, x-pagination-result-count")
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// AdminGetUser returns a single user
+// @Summary Get a user
+// @Description Returns a single user of this instance. Only available to instance admins.
+// @tags admin
+// @Produce json
+// @Security JWTKeyAuth
+// @Param user path int true "User ID"
+// @Success 200 {object} v1.AdminUser "The user."
+// @Failure 403 {object} web.HTTPError "The user is not an instance admin."
+// @Failure 404 {object} web.HTTPError "The user does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /admin/users/{user} [get]
+func AdminGetUser(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	u, err := getUserFromParam(s, c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, newAdminUser(u))
+}
+
+// AdminGetUserStorage returns how much storage a user uses
+// @Summary Get the storage usage of a user
+// @Description Returns the combined size of all attachments a user uploaded and the configured per-user limit. Only available to instance admins.
+// @tags admin
+// @Produce json
+// @Security JWTKeyAuth
+// @Param user path int true "User ID"
+// @Success 200 {object} models.AttachmentsUsage "The storage usage."
+// @Failure 403 {object} web.HTTPError "The user is not an instance admin."
+// @Failure 404 {object} web.HTTPError "The user does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /admin/users/{user}/storage [get]
+func AdminGetUserStorage(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	u, err := getUserFromParam(s, c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	usage, err := models.GetUserAttachmentsUsage(s, u.ID)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, usage)
+}
+
+// AdminSetUserStatus enables or disables a user
+// @Summary Enable or disable a user
+// @Description Disabled users can't log in anymore and are logged out on all devices, their api tokens are deleted. Their projects and tasks are kept. Admins can't disable their own account. Only available to instance admins.
+// @tags admin
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param user path int true "User ID"
+// @Param status body v1.AdminUserStatus true "Whether the user should be disabled."
+// @Success 200 {object} v1.AdminUser "The updated user."
+// @Failure 403 {object} web.HTTPError "The user is not an instance admin."
+// @Failure 404 {object} web.HTTPError "The user does not exist."
+// @Failure 412 {object} web.HTTPError "Admins can't disable themselves."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /admin/users/{user}/status [post]
+func AdminSetUserStatus(c echo.Context) error {
+	status := &AdminUserStatus{}
+	if err := bindAdminRequest(c, status); err != nil {
+		return err
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	u, err := getUserFromParam(s, c)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	u.Status = user.StatusActive
+	if status.Disabled {
+		err = checkNotSelf(c, u)
+		if err != nil {
+			_ = s.Rollback()
+			return handler.HandleHTTPError(err, c)
+		}
+		u.Status = user.StatusDisabled
+	}
+
+	err = user.SetUserStatus(s, u, u.Status)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	// Disabled users should not be able to keep using the account with a token they got before
+	if status.Disabled {
+		err = user.RevokeAllSessionsExcept(s, u, "")
+		if err != nil {
+			_ = s.Rollback()
+			return handler.HandleHTTPError(err, c)
+		}
+		err = models.DeleteAllAPITokensOfUser(s, u.ID)
+		if err != nil {
+			_ = s.Rollback()
+			return handler.HandleHTTPError(err, c)
+		}
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, newAdminUser(u))
+}
+
+// AdminResetUserPassword resets the password of a user
+// @Summary Reset the password of a user
+// @Description Sets a new password for a user and logs them out on all devices. If no password is provided, the user gets an email with a link to set a new one themselves. Only available for users who log in with a password, only available to instance admins.
+// @tags admin
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param user path int true "User ID"
+// @Param password body v1.AdminUserPassword true "The new password."
+// @Success 200 {object} models.Message "The password was reset."
+// @Failure 403 {object} web.HTTPError "The user is not an instance admin."
+// @Failure 404 {object} web.HTTPError "The user does not exist."
+// @Failure 412 {object} web.HTTPError "The user does not log in with a password."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /admin/users/{user}/password [post]
+func AdminResetUserPassword(c echo.Context) error {
+	password := &AdminUserPassword{}
+	if err := bindAdminRequest(c, password); err != nil {
+		return err
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	u, err := getUserFromParam(s, c)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if u.Issuer != user.IssuerLocal {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(&user.ErrAccountIsNotLocal{UserID: u.ID}, c)
+	}
+
+	message := "The password was updated successfully."
+	if password.Password == "" {
+		err = user.RequestUserPasswordResetToken(s, u)
+		message = "The user was sent an email to reset their password."
+	} else {
+		err = user.UpdateUserPassword(s, u, password.Password)
+	}
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	err = user.RevokeAllSessionsExcept(s, u, "")
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, models.Message{Message: message})
+}
+
+// AdminUpdateUserEmail changes the email address of a user
+// @Summary Change the email address of a user
+// @Description Changes the email address of a user right away, without asking the user to confirm it. Only available to instance admins.
+// @tags admin
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param user path int true "User ID"
+// @Param email body v1.AdminUserEmail true "The new email address."
+// @Success 200 {object} v1.AdminUser "The updated user."
+// @Failure 400 {object} web.HTTPError "The email address is invalid or already taken."
+// @Failure 403 {object} web.HTTPError "The user is not an instance admin."
+// @Failure 404 {object} web.HTTPError "The user does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /admin/users/{user}/email [post]
+func AdminUpdateUserEmail(c echo.Context) error {
+	email := &AdminUserEmail{}
+	if err := bindAdminRequest(c, email); err != nil {
+		return err
+	}
+
+	if !govalidator.IsEmail(email.Email) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid email address provided.")
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	u, err := getUserFromParam(s, c)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	u.Email = email.Email
+	updated, err := user.UpdateUser(s, u, false)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, newAdminUser(updated))
+}
+
+// AdminDeleteUser deletes a user
+// @Summary Delete a user
+// @Description Deletes a user right away. Projects shared with other users or teams are handed over to them, all other projects of the user are deleted. If transfer_to is provided, all projects owned by the user are transferred to that user first. Admins can't delete their own account. Only available to instance admins.
+// @tags admin
+// @Produce json
+// @Security JWTKeyAuth
+// @Param user path int true "User ID"
+// @Param transfer_to query int false "The id of the user who should become the owner of the deleted user's projects."
+// @Success 200 {object} models.Message "The user was deleted."
+// @Failure 403 {object} web.HTTPError "The user is not an instance admin."
+// @Failure 404 {object} web.HTTPError "The user does not exist."
+// @Failure 412 {object} web.HTTPError "Admins can't delete themselves or the new owner can't own that many more projects."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /admin/users/{user} [delete]
+func AdminDeleteUser(c echo.Context) error {
+	var transferToID int64
+	if c.QueryParam("transfer_to") != "" {
+		var err error
+		transferToID, err = strconv.ParseInt(c.QueryParam("transfer_to"), 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid transfer_to user ID provided")
+		}
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	u, err := getUserFromParam(s, c)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	err = checkNotSelf(c, u)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if transferToID != 0 {
+		newOwner, err := user.GetUserByID(s, transferToID)
+		if err != nil {
+			_ = s.Rollback()
+			return handler.HandleHTTPError(err, c)
+		}
+
+		err = models.TransferOwnedProjects(s, u, newOwner)
+		if err != nil {
+			_ = s.Rollback()
+			return handler.HandleHTTPError(err, c)
+		}
+	}
+
+	err = models.DeleteUser(s, u)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, models.Message{Message: "The user was deleted successfully."})
+}
+
+// AdminImpersonateUser gives an admin a token to act as another user
+// @Summary Impersonate a user
+// @Description Returns a short-lived token to use Vikunja as another user, for example to debug an issue they have. The token can't be renewed and can't be used to change login credentials. The user is notified and every request made with the token is recorded in the audit log. Admins and disabled users can't be impersonated. Only available to instance admins.
+// @tags admin
+// @Produce json
+// @Security JWTKeyAuth
+// @Param user path int true "User ID"
+// @Success 200 {object} auth.Token "The impersonation token."
+// @Failure 403 {object} web.HTTPError "The user is not an instance admin."
+// @Failure 404 {object} web.HTTPError "The user does not exist."
+// @Failure 412 {object} web.HTTPError "The user can't be impersonated."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /admin/users/{user}/impersonate [post]
+func AdminImpersonateUser(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	u, err := getUserFromParam(s, c)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if u.IsAdmin || u.Status == user.StatusDisabled {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(&user.ErrCannotImpersonateUser{UserID: u.ID}, c)
+	}
+
+	admin, err := user.GetCurrentUserFromDB(s, c)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	token, ttl, err := auth2.NewImpersonationToken(s, admin, u, c)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	err = models.CreateAuditLogEntry(s, &models.AuditLogEntry{
+		UserID:         u.ID,
+		ImpersonatorID: admin.ID,
+		Action:         models.AuditActionImpersonationStarted,
+		IPAddress:      c.RealIP(),
+	})
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	err = notifications.Notify(u, &user.ImpersonationStartedNotification{
+		User:         u,
+		Impersonator: admin,
+		Duration:     ttl,
+	})
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, auth2.Token{Token: token})
+}
+
+// AdminGetAuditLog returns the audit log
+// @Summary Get the audit log
+// @Description Returns everything instance admins did while impersonating other users, newest first. Only available to instance admins.
+// @tags admin
+// @Produce json
+// @Security JWTKeyAuth
+// @Param user query int false "Only return entries of actions performed as or by this user."
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Success 200 {array} models.AuditLogEntry "The audit log entries."
+// @Failure 403 {object} web.HTTPError "The user is not an instance admin."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /admin/audit-log [get]
+func AdminGetAuditLog(c echo.Context) error {
+	page, err := strconv.Atoi(c.QueryParam("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	maxPerPage := config.ServiceMaxItemsPerPage.GetInt()
+	perPage, err := strconv.Atoi(c.QueryParam("per_page"))
+	if err != nil || perPage < 1 || perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+
+	var userID int64
+	if c.QueryParam("user") != "" {
+		userID, err = strconv.ParseInt(c.QueryParam("user"), 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID provided")
+		}
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	entries, totalItems, err := models.GetAuditLogEntries(s, userID, page, perPage)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	totalPages := int64(math.Ceil(float64(totalItems) / float64(perPage)))
+	c.Response().Header().Set("x-pagination-total-pages", strconv.FormatInt(totalPages, 10))
+	c.Response().Header().Set("x-pagination-result-count", strconv.Itoa(len(entries)))
+	c.Response().Header().Set("Access-Control-Expose-Headers", "x-pagination-total-pages, x-pagination-result-count")
+
+	return c.JSON(http.StatusOK, entries)
+}
@@ -164,6 +164,17 @@ func UploadAvatar(c echo.Context) (err error) {
 	}
 	_, _ = src.Seek(0, io.SeekStart)
 
+	// The avatar is converted before it is stored, the virus scanner needs to see the original upload
+	err = files.Scan(src, file.Filename, u)
+	if err != nil {
+		_ = s.Rollback()
+		if files.IsErrFileIsInfected(err) {
+			return c.JSON(http.StatusBadRequest, models.Message{Message: "The uploaded file contains a virus."})
+		}
+		return handler.HandleHTTPError(err, c)
+	}
+	_, _ = src.Seek(0, io.SeekStart)
+
 	// Remove the old file if one exists
 	if u.AvatarFileID != 0 {
 		f := &files.File{ID: u.AvatarFileID}
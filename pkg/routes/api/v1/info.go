@@ -43,6 +43,7 @@ type vikunjaInfos struct {
 	TaskAttachmentsEnabled     bool      `json:"task_attachments_enabled"`
 	EnabledBackgroundProviders []string  `json:"enabled_background_providers"`
 	TotpEnabled                bool      `json:"totp_enabled"`
+	WebAuthnEnabled            bool      `json:"webauthn_enabled"`
 	Legal                      legalInfo `json:"legal"`
 	CaldavEnabled              bool      `json:"caldav_enabled"`
 	AuthInfo                   authInfo  `json:"auth"`
@@ -52,10 +53,14 @@ type vikunjaInfos struct {
 	DemoModeEnabled            bool      `json:"demo_mode_enabled"`
 	WebhooksEnabled            bool      `json:"webhooks_enabled"`
 	PublicTeamsEnabled         bool      `json:"public_teams_enabled"`
+	PublicProjectsEnabled      bool      `json:"public_projects_enabled"`
+	TaskFormsEnabled           bool      `json:"task_forms_enabled"`
+	WebPushPublicKey           string    `json:"web_push_public_key"`
 }
 
 type authInfo struct {
 	Local         localAuthInfo  `json:"local"`
+	Ldap          ldapAuthInfo   `json:"ldap"`
 	OpenIDConnect openIDAuthInfo `json:"openid_connect"`
 }
 
@@ -63,6 +68,10 @@ type localAuthInfo struct {
 	Enabled bool `json:"enabled"`
 }
 
+type ldapAuthInfo struct {
+	Enabled bool `json:"enabled"`
+}
+
 type openIDAuthInfo struct {
 	Enabled   bool               `json:"enabled"`
 	Providers []*openid.Provider `json:"providers"`
@@ -90,6 +99,7 @@ func Info(c echo.Context) error {
 		RegistrationEnabled:    config.ServiceEnableRegistration.GetBool(),
 		TaskAttachmentsEnabled: config.ServiceEnableTaskAttachments.GetBool(),
 		TotpEnabled:            config.ServiceEnableTotp.GetBool(),
+		WebAuthnEnabled:        config.ServiceEnableWebAuthn.GetBool(),
 		CaldavEnabled:          config.ServiceEnableCaldav.GetBool(),
 		EmailRemindersEnabled:  config.ServiceEnableEmailReminders.GetBool(),
 		UserDeletionEnabled:    config.ServiceEnableUserDeletion.GetBool(),
@@ -97,6 +107,8 @@ func Info(c echo.Context) error {
 		DemoModeEnabled:        config.ServiceDemoMode.GetBool(),
 		WebhooksEnabled:        config.WebhooksEnabled.GetBool(),
 		PublicTeamsEnabled:     config.ServiceEnablePublicTeams.GetBool(),
+		PublicProjectsEnabled:  config.ServiceEnablePublicProjects.GetBool(),
+		TaskFormsEnabled:       config.TaskFormsEnabled.GetBool(),
 		AvailableMigrators: []string{
 			(&vikunja_file.FileMigrator{}).Name(),
 			(&ticktick.Migrator{}).Name(),
@@ -109,12 +121,19 @@ func Info(c echo.Context) error {
 			Local: localAuthInfo{
 				Enabled: config.AuthLocalEnabled.GetBool(),
 			},
+			Ldap: ldapAuthInfo{
+				Enabled: config.AuthLdapEnabled.GetBool(),
+			},
 			OpenIDConnect: openIDAuthInfo{
 				Enabled: config.AuthOpenIDEnabled.GetBool(),
 			},
 		},
 	}
 
+	if config.WebPushEnabled.GetBool() {
+		info.WebPushPublicKey = config.WebPushVAPIDPublicKey.GetString()
+	}
+
 	providers, err := openid.GetAllProviders()
 	if err != nil {
 		log.Errorf("Error while getting openid providers for /info: %s", err)
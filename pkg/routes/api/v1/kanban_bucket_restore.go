@@ -0,0 +1,80 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+	auth2 "code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/web/handler"
+	"github.com/labstack/echo/v4"
+)
+
+// RestoreBucket restores an archived bucket
+// @Summary Restore an archived bucket
+// @Description Restores an archived kanban bucket. The bucket and its tasks show up on the board again at the positions they had when the bucket was archived. To get all archived buckets, use the buckets endpoint with `archived=true`.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Param view path int true "Project view ID"
+// @Param bucket path int true "Bucket ID"
+// @Success 200 {object} models.Bucket "The restored bucket."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the bucket."
+// @Failure 404 {object} web.HTTPError "The bucket does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/views/{view}/buckets/{bucket}/restore [post]
+func RestoreBucket(c echo.Context) error {
+	bucket := &models.Bucket{}
+	var err error
+	bucket.ProjectID, err = strconv.ParseInt(c.Param("project"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid project ID provided")
+	}
+	bucket.ProjectViewID, err = strconv.ParseInt(c.Param("view"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid view ID provided")
+	}
+	bucket.ID, err = strconv.ParseInt(c.Param("bucket"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid bucket ID provided")
+	}
+
+	auth, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	err = models.RestoreBucket(s, bucket, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, bucket)
+}
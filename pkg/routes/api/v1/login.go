@@ -21,9 +21,12 @@ import (
 
 	"code.vikunja.io/api/pkg/modules/keyvalue"
 
+	"code.vikunja.io/api/pkg/config"
 	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
 	"code.vikunja.io/api/pkg/models"
 	"code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/api/pkg/modules/auth/ldap"
 	user2 "code.vikunja.io/api/pkg/user"
 	"code.vikunja.io/web/handler"
 
@@ -53,10 +56,34 @@ func Login(c echo.Context) error {
 	defer s.Close()
 
 	// Check user
-	user, err := user2.CheckUserCredentials(s, &u)
-	if err != nil {
-		_ = s.Rollback()
-		return handler.HandleHTTPError(err, c)
+	var user *user2.User
+	var err error
+	if config.AuthLdapEnabled.GetBool() {
+		user, err = ldap.AuthenticateUserInLDAP(s, u.Username, u.Password)
+		if err != nil && !config.AuthLocalEnabled.GetBool() {
+			_ = s.Rollback()
+			return handler.HandleHTTPError(err, c)
+		}
+		if err != nil {
+			// Local users must still be able to log in when the ldap server is not reachable
+			if !user2.IsErrWrongUsernameOrPassword(err) {
+				log.Errorf("Could not authenticate user %s with ldap, trying local authentication: %s", u.Username, err)
+			}
+			user = nil
+		}
+	}
+
+	// Users who are not in ldap might still have a local account
+	if user == nil {
+		user, err = user2.CheckUserCredentials(s, &u)
+		if user2.IsErrAccountIsNotLocal(err) && config.AuthLdapEnabled.GetBool() {
+			// ldap users who entered a wrong password
+			err = user2.ErrWrongUsernameOrPassword{}
+		}
+		if err != nil {
+			_ = s.Rollback()
+			return handler.HandleHTTPError(err, c)
+		}
 	}
 
 	if user.Status == user2.StatusDisabled {
@@ -70,7 +97,21 @@ func Login(c echo.Context) error {
 		return handler.HandleHTTPError(err, c)
 	}
 
-	if totpEnabled {
+	// A registered passkey or security key is a second factor on its own, it can be used instead of a totp passcode
+	webAuthnEnabled, err := user2.WebAuthnEnabledForUser(s, user)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	switch {
+	case webAuthnEnabled && u.WebAuthn != nil:
+		_, err = user2.FinishWebAuthnLogin(s, user, u.WebAuthn)
+		if err != nil {
+			_ = s.Rollback()
+			return handler.HandleHTTPError(err, c)
+		}
+	case totpEnabled:
 		if u.TOTPPasscode == "" {
 			_ = s.Rollback()
 			return handler.HandleHTTPError(user2.ErrInvalidTOTPPasscode{}, c)
@@ -87,6 +128,9 @@ func Login(c echo.Context) error {
 			_ = s.Rollback()
 			return handler.HandleHTTPError(err, c)
 		}
+	case webAuthnEnabled:
+		_ = s.Rollback()
+		return handler.HandleHTTPError(&user2.ErrWebAuthnRequired{}, c)
 	}
 
 	if err := keyvalue.Del(user.GetFailedTOTPAttemptsKey()); err != nil {
@@ -114,6 +158,7 @@ func Login(c echo.Context) error {
 // @Produce json
 // @Success 200 {object} auth.Token
 // @Failure 400 {object} models.Message "Only user token are available for renew."
+// @Failure 403 {object} models.Message "Impersonation tokens can't be renewed."
 // @Router /user/token [post]
 func RenewToken(c echo.Context) (err error) {
 
@@ -139,6 +184,10 @@ func RenewToken(c echo.Context) (err error) {
 		return c.JSON(http.StatusOK, auth.Token{Token: t})
 	}
 
+	if auth.GetImpersonatorIDFromClaims(c) != 0 {
+		return echo.NewHTTPError(http.StatusForbidden, "Impersonation tokens can't be renewed.")
+	}
+
 	u, err := user2.GetUserFromClaims(claims)
 	if err != nil {
 		_ = s.Rollback()
@@ -163,5 +212,9 @@ func RenewToken(c echo.Context) (err error) {
 	}
 
 	// Create token
-	return auth.NewUserAuthTokenResponse(user, c, long)
+	err = auth.RenewUserAuthTokenResponse(user, c, auth.GetSessionIDFromClaims(c), long)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+	return nil
 }
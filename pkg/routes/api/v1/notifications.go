@@ -28,10 +28,13 @@ import (
 
 // MarkAllNotificationsAsRead marks all notifications of a user as read
 // @Summary Mark all notifications of a user as read
+// @Description Marks all notifications of the current user as read. If a filter is passed, only the notifications matching it are marked as read.
 // @tags sharing
 // @Accept json
 // @Produce json
+// @Param filter body notifications.Filter false "Only mark notifications matching this filter as read."
 // @Success 200 {object} models.Message "All notifications marked as read."
+// @Failure 400 {object} models.Message "Invalid notification filter."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /notifications [post]
 func MarkAllNotificationsAsRead(c echo.Context) error {
@@ -47,7 +50,12 @@ func MarkAllNotificationsAsRead(c echo.Context) error {
 		return echo.ErrForbidden
 	}
 
-	err = notifications.MarkAllNotificationsAsRead(s, a.GetID())
+	filter := &notifications.Filter{}
+	if err := c.Bind(filter); err != nil {
+		return c.JSON(http.StatusBadRequest, models.Message{Message: "Invalid notification filter."})
+	}
+
+	err = notifications.MarkAllNotificationsAsRead(s, a.GetID(), filter)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,69 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+	auth2 "code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/web/handler"
+	"github.com/labstack/echo/v4"
+)
+
+// UnarchiveProject unarchives a project and everything archived with it
+// @Summary Unarchive a project
+// @Description Unarchives a project together with all of its child projects which were archived because the project was archived. Child projects which were archived on their own stay archived. Reminders and webhooks of the restored projects are sent again. Fails if a parent project of the project is archived.
+// @tags project
+// @Produce json
+// @Security JWTKeyAuth
+// @Param project path int true "Project ID"
+// @Success 200 {object} models.Project "The unarchived project."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The project does not exist."
+// @Failure 412 {object} web.HTTPError "A parent project of the project is archived."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{project}/unarchive [post]
+func UnarchiveProject(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("project"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid project ID provided")
+	}
+
+	auth, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	project, err := models.UnarchiveProject(s, projectID, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, project)
+}
@@ -0,0 +1,73 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+
+	"code.vikunja.io/web/handler"
+	"github.com/c2h5oh/datasize"
+	"github.com/labstack/echo/v4"
+)
+
+// ReceiveIntakeEmail creates tasks from a mail forwarded by a mail server
+// @Summary Receive an intake email
+// @Description Creates a new task for every project intake address the mail was sent to. Replies to notification mails are added as comments to their task instead. The body must be the raw mail as received by the mail server. Requires the configured intake secret as bearer token.
+// @tags project
+// @Accept plain
+// @Produce json
+// @Success 201 {object} models.Message "The tasks were created."
+// @Failure 400 {object} web.HTTPError "The mail could not be processed or is larger than the configured limit."
+// @Failure 401 {object} web.HTTPError "The intake secret is missing or wrong."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /email-intake [post]
+func ReceiveIntakeEmail(c echo.Context) error {
+	secret := config.EmailIntakeSecret.GetString()
+	token := strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+	if secret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid intake secret.")
+	}
+
+	var maxSize datasize.ByteSize
+	err := maxSize.UnmarshalText([]byte(config.EmailIntakeMaxSize.GetString()))
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, int64(maxSize.Bytes()))
+
+	s := db.NewSession()
+	defer s.Close()
+
+	_, err = models.ReceiveIntakeEmail(s, body)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusCreated, models.Message{Message: "The mail was processed successfully."})
+}
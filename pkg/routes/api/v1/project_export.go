@@ -0,0 +1,86 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
+	auth2 "code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/web/handler"
+	"github.com/labstack/echo/v4"
+)
+
+// ExportProject packs a whole project into a downloadable zip archive
+// @Summary Export a project
+// @Description Returns a zip archive with the project, its views, buckets, tasks, comments, attachments and background. The archive can be imported again. **Returns json on error.**
+// @tags project
+// @Produce octet-stream
+// @Security JWTKeyAuth
+// @Param id path int true "Project ID"
+// @Success 200 {file} blob "The exported project."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the project."
+// @Failure 404 {object} web.HTTPError "The project does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/export [get]
+func ExportProject(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("project"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid project ID provided")
+	}
+
+	auth, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	project := &models.Project{ID: projectID}
+	can, _, err := project.CanRead(s, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+	if !can {
+		return echo.ErrForbidden
+	}
+
+	// The archive is streamed to the client while it is created, large projects would not fit in memory otherwise.
+	filename := models.ProjectExportFilename(projectID)
+	c.Response().Header().Set(echo.HeaderContentType, "application/zip")
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	c.Response().WriteHeader(http.StatusOK)
+
+	err = models.ExportProject(s, projectID, auth, c.Response())
+	if err != nil {
+		_ = s.Rollback()
+		// The response was already started, all we can do is to stop sending the archive
+		log.Errorf("Could not export project %d: %s", projectID, err)
+		return nil
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		log.Errorf("Could not export project %d: %s", projectID, err)
+	}
+	return nil
+}
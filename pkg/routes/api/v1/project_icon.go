@@ -0,0 +1,214 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/files"
+	"code.vikunja.io/api/pkg/models"
+	auth2 "code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/web"
+	"code.vikunja.io/web/handler"
+
+	"github.com/labstack/echo/v4"
+	"xorm.io/xorm"
+)
+
+// getProjectForIcon loads the project from the request after checking the doer is allowed to see it or, if
+// write is true, to change it.
+func getProjectForIcon(s *xorm.Session, c echo.Context, write bool) (project *models.Project, auth web.Auth, err error) {
+	auth, err = auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid auth token: "+err.Error())
+	}
+
+	projectID, err := strconv.ParseInt(c.Param("project"), 10, 64)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid project ID: "+err.Error())
+	}
+
+	project = &models.Project{ID: projectID}
+	var can bool
+	if write {
+		can, err = project.CanWrite(s, auth)
+	} else {
+		can, _, err = project.CanRead(s, auth)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !can {
+		return nil, nil, echo.ErrForbidden
+	}
+
+	project, err = models.GetProjectSimpleByID(s, projectID)
+	return project, auth, err
+}
+
+// UploadProjectIcon sets an uploaded image as project icon
+// @Summary Upload a project icon
+// @Description Uploads an image and sets it as the icon of the project. The image is scaled down to fit into 256x256 pixels and stored as png. A previously uploaded icon is replaced.
+// @tags project
+// @Accept mpfd
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param icon formData string true "The icon as single file."
+// @Security JWTKeyAuth
+// @Success 200 {object} models.Project "The updated project."
+// @Failure 400 {object} web.HTTPError "The file is no supported image."
+// @Failure 403 {object} models.Message "No access to this project."
+// @Failure 404 {object} models.Message "The project does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/icon [put]
+func UploadProjectIcon(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	project, auth, err := getProjectForIcon(s, c, true)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	file, err := c.FormFile("icon")
+	if err != nil {
+		_ = s.Rollback()
+		return echo.NewHTTPError(http.StatusBadRequest, "No icon file provided.")
+	}
+	src, err := file.Open()
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+	defer src.Close()
+
+	err = models.SetProjectIcon(s, project, src, file.Filename, auth)
+	if err != nil {
+		_ = s.Rollback()
+		if files.IsErrFileIsTooLarge(err) {
+			return echo.ErrBadRequest
+		}
+		if files.IsErrFileIsInfected(err) {
+			return c.JSON(http.StatusBadRequest, models.Message{Message: "The uploaded file contains a virus."})
+		}
+		return handler.HandleHTTPError(err, c)
+	}
+
+	err = project.ReadOne(s, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, project)
+}
+
+// GetProjectIcon serves the uploaded icon of a project
+// @Summary Get the project icon
+// @Description Returns the uploaded icon image of a project. **Returns json on error.**
+// @tags project
+// @Produce octet-stream
+// @Param id path int true "Project ID"
+// @Security JWTKeyAuth
+// @Success 200 {file} blob "The project icon as png."
+// @Failure 403 {object} models.Message "No access to this project."
+// @Failure 404 {object} models.Message "The project does not exist or has no icon image."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/icon [get]
+func GetProjectIcon(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	project, _, err := getProjectForIcon(s, c, false)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if project.IconFileID == 0 {
+		_ = s.Rollback()
+		return echo.NotFoundHandler(c)
+	}
+
+	icon := &files.File{ID: project.IconFileID}
+	if err := icon.LoadFileByID(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+	defer icon.File.Close()
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if stat, err := icon.File.Stat(); err == nil && stat != nil {
+		c.Response().Header().Set(echo.HeaderLastModified, stat.ModTime().UTC().Format(http.TimeFormat))
+	}
+
+	return c.Stream(http.StatusOK, "image/png", icon.File)
+}
+
+// RemoveProjectIcon removes the uploaded icon of a project
+// @Summary Remove the project icon
+// @Description Removes the uploaded icon image of a project. An emoji icon set on the project is kept. Does not return an error if the project has no icon image.
+// @tags project
+// @Produce json
+// @Param id path int true "Project ID"
+// @Security JWTKeyAuth
+// @Success 200 {object} models.Project "The updated project."
+// @Failure 403 {object} models.Message "No access to this project."
+// @Failure 404 {object} models.Message "The project does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/{id}/icon [delete]
+func RemoveProjectIcon(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	project, auth, err := getProjectForIcon(s, c, true)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	err = models.RemoveProjectIcon(s, project)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	err = project.ReadOne(s, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, project)
+}
@@ -0,0 +1,94 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+	vikunjafile "code.vikunja.io/api/pkg/modules/migration/vikunja-file"
+	user2 "code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web/handler"
+	"github.com/labstack/echo/v4"
+)
+
+// ImportProject creates projects from a project export archive
+// @Summary Import a project export
+// @Description Imports a zip archive created by the project export endpoint. All projects, views, buckets, tasks, labels, comments, attachments and relations in it are created with new ids below the given parent project. Set `dry_run` to only get a report of what would be created.
+// @tags project
+// @Accept mpfd
+// @Produce json
+// @Security JWTKeyAuth
+// @Param import formData file true "The project export zip file."
+// @Param parent_project_id formData int false "The project to create the imported projects in. If not provided, they are created at the top level."
+// @Param dry_run formData bool false "If true, nothing is created and the response only contains what would have been."
+// @Success 200 {object} vikunjafile.ImportReport "What was (or would be) created."
+// @Failure 400 {object} web.HTTPError "Invalid archive or parameters."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the parent project."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /projects/import [put]
+func ImportProject(c echo.Context) error {
+	u, err := user2.GetCurrentUser(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	var parentProjectID int64
+	if raw := c.FormValue("parent_project_id"); raw != "" {
+		parentProjectID, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid parent project ID provided")
+		}
+	}
+
+	var dryRun bool
+	if raw := c.FormValue("dry_run"); raw != "" {
+		dryRun, err = strconv.ParseBool(raw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid dry_run value provided")
+		}
+	}
+
+	s := db.NewSession()
+	can, err := (&models.Project{ParentProjectID: parentProjectID}).CanCreate(s, u)
+	s.Close()
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+	if !can {
+		return echo.ErrForbidden
+	}
+
+	file, err := c.FormFile("import")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "No import file provided")
+	}
+	src, err := file.Open()
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+	defer src.Close()
+
+	report, err := vikunjafile.ImportProjects(u, src, file.Size, parentProjectID, dryRun)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
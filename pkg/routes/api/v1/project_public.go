@@ -0,0 +1,106 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+
+	"code.vikunja.io/web/handler"
+	"github.com/labstack/echo/v4"
+)
+
+// GetPublicProject returns a public project
+// @Summary Get a public project
+// @Description Returns the title, description and views of a project which was made public. Does not require authentication.
+// @tags project
+// @Produce json
+// @Param project path int true "Project ID"
+// @Success 200 {object} models.PublicProject "The project."
+// @Failure 404 {object} web.HTTPError "The project does not exist or is not public."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /public/projects/{project} [get]
+func GetPublicProject(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("project"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid project ID provided")
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	project, err := models.GetPublicProject(s, projectID)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, project)
+}
+
+// GetPublicProjectViewTasks returns the tasks of a view of a public project
+// @Summary Get the tasks of a public project view
+// @Description Returns all tasks of a view of a public project. For kanban views, the tasks are returned in their buckets. Comments and email addresses are never included. Does not require authentication.
+// @tags project
+// @Produce json
+// @Param project path int true "Project ID"
+// @Param view path int true "Project view ID"
+// @Param page query int false "The page number. Used for pagination. If not provided, the first page of results is returned."
+// @Param per_page query int false "The maximum number of items per page. Note this parameter is limited by the configured maximum of items per page."
+// @Success 200 {array} models.Task "The tasks"
+// @Failure 404 {object} web.HTTPError "The project does not exist or is not public."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /public/projects/{project}/views/{view}/tasks [get]
+func GetPublicProjectViewTasks(c echo.Context) error {
+	projectID, err := strconv.ParseInt(c.Param("project"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid project ID provided")
+	}
+	viewID, err := strconv.ParseInt(c.Param("view"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid view ID provided")
+	}
+
+	page, err := strconv.Atoi(c.QueryParam("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	maxPerPage := config.ServiceMaxItemsPerPage.GetInt()
+	perPage, err := strconv.Atoi(c.QueryParam("per_page"))
+	if err != nil || perPage < 1 || perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	result, resultCount, totalItems, err := models.GetPublicProjectViewTasks(s, projectID, viewID, page, perPage)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	totalPages := int64(math.Ceil(float64(totalItems) / float64(perPage)))
+	c.Response().Header().Set("x-pagination-total-pages", strconv.FormatInt(totalPages, 10))
+	c.Response().Header().Set("x-pagination-result-count", strconv.Itoa(resultCount))
+	c.Response().Header().Set("Access-Control-Expose-Headers", "x-pagination-total-pages, x-pagination-result-count")
+
+	return c.JSON(http.StatusOK, result)
+}
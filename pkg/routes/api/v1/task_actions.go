@@ -0,0 +1,116 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/i18n"
+	"code.vikunja.io/api/pkg/models"
+
+	"code.vikunja.io/web/handler"
+	"github.com/labstack/echo/v4"
+)
+
+// Mail clients and link scanners open links in mails on their own, so opening a task action link only asks the
+// user to confirm the action. The form posts to the same url, which then runs it.
+var taskActionConfirmationTemplate = template.Must(template.New("task-action").Parse(`<!DOCTYPE html>
+<html lang="{{ .Lang }}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{ .Question }}</title>
+</head>
+<body style="font-family: sans-serif; text-align: center; padding: 2rem;">
+<p>{{ .Question }}</p>
+<form method="post"><button type="submit">{{ .Button }}</button></form>
+</body>
+</html>
+`))
+
+// ShowTaskAction asks to confirm the action of a task action link
+// @Summary Confirm a task action
+// @Description Shows a page asking to confirm marking a task as done or snoozing it, using the signed link from an overdue tasks email. Confirming sends a POST request to the same url.
+// @tags task
+// @Produce html
+// @Param token path string true "The task action token"
+// @Success 200 "The confirmation page."
+// @Failure 400 {object} web.HTTPError "The link is invalid, expired or was already used."
+// @Failure 403 {object} web.HTTPError "The user can no longer edit the task."
+// @Failure 412 {object} web.HTTPError "The user is disabled."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /task-actions/{token} [get]
+func ShowTaskAction(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	request, err := models.GetTaskActionRequest(s, c.Param("token"))
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	lang := request.User.Lang()
+	data := map[string]string{
+		"Lang":     lang,
+		"Question": i18n.T(lang, "notifications.task.overdue.confirm_"+string(request.Action), request.Task.Title),
+		"Button":   i18n.T(lang, "notifications.task.overdue.mark_done"),
+	}
+	if request.Action == models.TaskActionSnooze {
+		data["Button"] = i18n.T(lang, "notifications.task.overdue.snooze")
+	}
+
+	page := &strings.Builder{}
+	err = taskActionConfirmationTemplate.Execute(page, data)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.HTML(http.StatusOK, page.String())
+}
+
+// RunTaskAction runs the action of a task action link
+// @Summary Run a task action
+// @Description Marks a task as done or snoozes it until tomorrow, using the signed link from an overdue tasks email. Every link can only be used once. Redirects to the task afterwards.
+// @tags task
+// @Param token path string true "The task action token"
+// @Success 303 "Redirect to the task."
+// @Failure 400 {object} web.HTTPError "The link is invalid, expired or was already used."
+// @Failure 403 {object} web.HTTPError "The user can no longer edit the task."
+// @Failure 412 {object} web.HTTPError "The user is disabled."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /task-actions/{token} [post]
+func RunTaskAction(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	task, err := models.RunTaskAction(s, c.Param("token"))
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.Redirect(http.StatusSeeOther, task.GetFrontendURL())
+}
@@ -29,11 +29,12 @@ import (
 
 // UploadTaskAttachment handles everything needed for the upload of a task attachment
 // @Summary Upload a task attachment
-// @Description Upload a task attachment. You can pass multiple files with the files form param.
+// @Description Upload a task attachment. You can pass multiple files with the files form param. Files uploaded to a comment are attached to the comment and listed in the task attachments as well, only the author of the comment can do this.
 // @tags task
 // @Accept mpfd
 // @Produce json
 // @Param id path int true "Task ID"
+// @Param commentID path int false "Comment ID, only when uploading to a comment"
 // @Param files formData string true "The file, as multipart form file. You can pass multiple."
 // @Security JWTKeyAuth
 // @Success 200 {object} models.Message "Attachments were uploaded successfully."
@@ -41,6 +42,7 @@ import (
 // @Failure 404 {object} models.Message "The task does not exist."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /tasks/{id}/attachments [put]
+// @Router /tasks/{id}/comments/{commentID}/attachments [put]
 func UploadTaskAttachment(c echo.Context) error {
 
 	var taskAttachment models.TaskAttachment
@@ -82,7 +84,8 @@ func UploadTaskAttachment(c echo.Context) error {
 	for _, file := range fileHeaders {
 		// We create a new attachment object here to have a clean start
 		ta := &models.TaskAttachment{
-			TaskID: taskAttachment.TaskID,
+			TaskID:    taskAttachment.TaskID,
+			CommentID: taskAttachment.CommentID,
 		}
 
 		f, err := file.Open()
@@ -117,6 +120,7 @@ func UploadTaskAttachment(c echo.Context) error {
 // @Param attachmentID path int true "Attachment ID"
 // @Security JWTKeyAuth
 // @Success 200 {file} blob "The attachment file."
+// @Success 302 "Redirect to a pre-signed download url if the file storage supports it or to the url of a link attachment."
 // @Failure 403 {object} models.Message "No access to this task."
 // @Failure 404 {object} models.Message "The task does not exist."
 // @Failure 500 {object} models.Message "Internal error"
@@ -153,8 +157,78 @@ func GetTaskAttachment(c echo.Context) error {
 		return handler.HandleHTTPError(err, c)
 	}
 
-	// Open an send the file to the client
-	err = taskAttachment.File.LoadFileByID()
+	if taskAttachment.IsLink() {
+		if err := s.Commit(); err != nil {
+			_ = s.Rollback()
+			return handler.HandleHTTPError(err, c)
+		}
+		return c.Redirect(http.StatusFound, taskAttachment.URL)
+	}
+
+	// Clients can download the file directly from the object storage if it is configured like that
+	presignedURL := taskAttachment.File.PresignedURL()
+	if presignedURL == "" {
+		// Open an send the file to the client
+		err = taskAttachment.File.LoadFileByID()
+		if err != nil {
+			_ = s.Rollback()
+			return handler.HandleHTTPError(err, c)
+		}
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if presignedURL != "" {
+		return c.Redirect(http.StatusFound, presignedURL)
+	}
+
+	http.ServeContent(c.Response(), c.Request(), taskAttachment.File.Name, taskAttachment.File.Created, taskAttachment.File.File)
+	return nil
+}
+
+// GetTaskAttachmentThumbnail returns a scaled down version of an image attachment
+// @Summary Get the thumbnail of an attachment.
+// @Description Returns a scaled down version of an image attachment, like the cover image of a task. Thumbnails are generated on first use and cached afterwards. **Returns json on error.**
+// @tags task
+// @Produce octet-stream
+// @Param id path int true "Task ID"
+// @Param attachmentID path int true "Attachment ID"
+// @Param size query string false "The size of the thumbnail, either small (150px), medium (400px) or large (800px). Defaults to medium."
+// @Security JWTKeyAuth
+// @Success 200 {file} blob "The thumbnail as jpeg or png."
+// @Failure 400 {object} models.Message "The attachment is not an image or the size does not exist."
+// @Failure 403 {object} models.Message "No access to this task."
+// @Failure 404 {object} models.Message "The task does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/attachments/{attachmentID}/thumb [get]
+func GetTaskAttachmentThumbnail(c echo.Context) error {
+
+	var taskAttachment models.TaskAttachment
+	if err := c.Bind(&taskAttachment); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "No task ID provided")
+	}
+
+	auth, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	can, _, err := taskAttachment.CanRead(s, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+	if !can {
+		return echo.ErrForbidden
+	}
+
+	err = taskAttachment.ReadOne(s, auth)
 	if err != nil {
 		_ = s.Rollback()
 		return handler.HandleHTTPError(err, c)
@@ -165,6 +239,206 @@ func GetTaskAttachment(c echo.Context) error {
 		return handler.HandleHTTPError(err, c)
 	}
 
-	http.ServeContent(c.Response(), c.Request(), taskAttachment.File.Name, taskAttachment.File.Created, taskAttachment.File.File)
+	thumbnail, mime, err := taskAttachment.Thumbnail(c.QueryParam("size"))
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+	defer thumbnail.Close()
+
+	c.Response().Header().Set(echo.HeaderContentType, mime)
+	http.ServeContent(c.Response(), c.Request(), taskAttachment.File.Name, taskAttachment.File.Created, thumbnail)
+	return nil
+}
+
+// GetTaskAttachmentPreview returns a preview of a document attachment
+// @Summary Get the preview of an attachment.
+// @Description Returns the first page of a pdf attachment as png or the beginning of a plain text or markdown attachment, so that clients can show the content without downloading the whole file. Pdf previews are generated on first use and cached afterwards. **Returns json on error.**
+// @tags task
+// @Produce octet-stream
+// @Param id path int true "Task ID"
+// @Param attachmentID path int true "Attachment ID"
+// @Security JWTKeyAuth
+// @Success 200 {file} blob "The preview as png or text."
+// @Failure 400 {object} models.Message "There is no preview for this attachment."
+// @Failure 403 {object} models.Message "No access to this task."
+// @Failure 404 {object} models.Message "The task does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/attachments/{attachmentID}/preview [get]
+func GetTaskAttachmentPreview(c echo.Context) error {
+
+	var taskAttachment models.TaskAttachment
+	if err := c.Bind(&taskAttachment); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "No task ID provided")
+	}
+
+	auth, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	can, _, err := taskAttachment.CanRead(s, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+	if !can {
+		return echo.ErrForbidden
+	}
+
+	err = taskAttachment.ReadOne(s, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	preview, mime, err := taskAttachment.Preview()
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+	defer preview.Close()
+
+	c.Response().Header().Set(echo.HeaderContentType, mime)
+	http.ServeContent(c.Response(), c.Request(), taskAttachment.File.Name, taskAttachment.File.Created, preview)
+	return nil
+}
+
+// UploadTaskAttachmentVersion replaces the file of an attachment with a new version
+// @Summary Upload a new version of an attachment
+// @Description Replaces the file of an attachment with a new one. The previous file is kept as version of the attachment and can still be downloaded or restored.
+// @tags task
+// @Accept mpfd
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param attachmentID path int true "Attachment ID"
+// @Param file formData string true "The new file, as multipart form file."
+// @Security JWTKeyAuth
+// @Success 200 {object} models.TaskAttachment "The attachment with the new file."
+// @Failure 400 {object} models.Message "No file was provided."
+// @Failure 403 {object} models.Message "No access to the task."
+// @Failure 404 {object} models.Message "The attachment does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/attachments/{attachmentID}/versions [put]
+func UploadTaskAttachmentVersion(c echo.Context) error {
+
+	var taskAttachment models.TaskAttachment
+	if err := c.Bind(&taskAttachment); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "No task or attachment ID provided")
+	}
+
+	auth, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "No file provided")
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	can, err := taskAttachment.CanUpdate(s, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+	if !can {
+		return echo.ErrForbidden
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+	defer f.Close()
+
+	err = taskAttachment.NewVersion(s, f, file.Filename, uint64(file.Size), auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, taskAttachment)
+}
+
+// GetTaskAttachmentVersion returns the file of a previous version of an attachment
+// @Summary Download a version of an attachment.
+// @Description Returns the file of a previous version of an attachment. **Returns json on error.**
+// @tags task
+// @Produce octet-stream
+// @Param id path int true "Task ID"
+// @Param attachmentID path int true "Attachment ID"
+// @Param version path int true "The version"
+// @Security JWTKeyAuth
+// @Success 200 {file} blob "The file of this version."
+// @Success 302 "Redirect to a pre-signed download url if the file storage supports it."
+// @Failure 403 {object} models.Message "No access to this task."
+// @Failure 404 {object} models.Message "The attachment or version does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/attachments/{attachmentID}/versions/{version} [get]
+func GetTaskAttachmentVersion(c echo.Context) error {
+
+	var version models.TaskAttachmentVersion
+	if err := c.Bind(&version); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "No task, attachment or version provided")
+	}
+
+	auth, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	can, _, err := version.CanRead(s, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+	if !can {
+		return echo.ErrForbidden
+	}
+
+	err = version.ReadOne(s, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	presignedURL := version.File.PresignedURL()
+	if presignedURL == "" {
+		err = version.File.LoadFileByID()
+		if err != nil {
+			_ = s.Rollback()
+			return handler.HandleHTTPError(err, c)
+		}
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if presignedURL != "" {
+		return c.Redirect(http.StatusFound, presignedURL)
+	}
+
+	http.ServeContent(c.Response(), c.Request(), version.File.Name, version.File.Created, version.File.File)
 	return nil
 }
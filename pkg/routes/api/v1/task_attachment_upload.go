@@ -0,0 +1,95 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+	auth2 "code.vikunja.io/api/pkg/modules/auth"
+
+	"code.vikunja.io/web/handler"
+	"github.com/labstack/echo/v4"
+)
+
+// UploadTaskAttachmentChunk adds a chunk to a resumable attachment upload
+// @Summary Upload a chunk of an attachment
+// @Description Appends the raw request body to a resumable upload. The `Upload-Offset` header must match the number of bytes received so far, get the upload to find out where to continue after an interrupted request. The response to the last chunk contains the created attachment.
+// @tags task
+// @Accept octet-stream
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param uploadID path int true "Upload ID"
+// @Param Upload-Offset header int true "The offset of this chunk in the file."
+// @Security JWTKeyAuth
+// @Success 200 {object} models.TaskAttachmentUpload "The upload after adding the chunk."
+// @Failure 400 {object} web.HTTPError "The chunk is larger than the rest of the file."
+// @Failure 403 {object} models.Message "The upload was not started by the user."
+// @Failure 404 {object} models.Message "The upload does not exist or has expired."
+// @Failure 409 {object} web.HTTPError "The chunk does not start at the current offset of the upload."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/attachments/uploads/{uploadID} [patch]
+func UploadTaskAttachmentChunk(c echo.Context) error {
+	// The body is the raw chunk, which is why the ids can't be bound the usual way
+	upload := &models.TaskAttachmentUpload{}
+	var err error
+	upload.TaskID, err = strconv.ParseInt(c.Param("task"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid task ID provided")
+	}
+	upload.ID, err = strconv.ParseInt(c.Param("upload"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid upload ID provided")
+	}
+
+	offset, err := strconv.ParseUint(c.Request().Header.Get("Upload-Offset"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid or missing Upload-Offset header")
+	}
+
+	auth, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	can, err := upload.CanUpdate(s, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+	if !can {
+		return echo.ErrForbidden
+	}
+
+	err = upload.AddChunk(s, offset, c.Request().Body, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, upload)
+}
@@ -0,0 +1,90 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+	auth2 "code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/web/handler"
+	"github.com/labstack/echo/v4"
+)
+
+// ExportTask renders a single task into a downloadable document
+// @Summary Export a task
+// @Description Renders the task with its checklist, comments and the list of its attachments into a markdown or pdf document. **Returns json on error.**
+// @tags task
+// @Produce octet-stream
+// @Security JWTKeyAuth
+// @Param id path int true "Task ID"
+// @Param format query string false "The format of the document. Either `markdown` or `pdf`. Defaults to markdown."
+// @Success 200 {file} blob "The exported task."
+// @Failure 400 {object} web.HTTPError "The export format is invalid."
+// @Failure 403 {object} web.HTTPError "The user does not have access to the task."
+// @Failure 404 {object} web.HTTPError "The task does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /tasks/{id}/export [get]
+func ExportTask(c echo.Context) error {
+	taskID, err := strconv.ParseInt(c.Param("projecttask"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid task ID provided")
+	}
+
+	format := models.TaskExportFormat(c.QueryParam("format"))
+	if format == "" {
+		format = models.TaskExportFormatMarkdown
+	}
+
+	auth, err := auth2.GetAuthFromClaims(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	task := &models.Task{ID: taskID}
+	can, _, err := task.CanRead(s, auth)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+	if !can {
+		return echo.ErrForbidden
+	}
+
+	export, err := models.ExportTask(s, taskID, auth, format)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, export.MimeType)
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+export.Filename+`"`)
+	http.ServeContent(c.Response(), c.Request(), export.Filename, time.Now(), bytes.NewReader(export.Content))
+	return nil
+}
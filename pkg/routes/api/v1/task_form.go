@@ -0,0 +1,93 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"net/http"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+
+	"code.vikunja.io/web/handler"
+	"github.com/labstack/echo/v4"
+)
+
+// GetPublicTaskForm returns the public parts of a task form
+// @Summary Get a public task form
+// @Description Returns everything needed to render a task form. Does not require authentication.
+// @tags project
+// @Produce json
+// @Param hash path string true "The form hash"
+// @Success 200 {object} models.PublicTaskForm "The task form."
+// @Failure 404 {object} web.HTTPError "The task form does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /forms/{hash} [get]
+func GetPublicTaskForm(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	form, err := models.GetTaskFormByHash(s, c.Param("hash"))
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if !form.Enabled {
+		return handler.HandleHTTPError(&models.ErrTaskFormDoesNotExist{Hash: form.Hash}, c)
+	}
+
+	return c.JSON(http.StatusOK, form.ToPublic())
+}
+
+// SubmitTaskForm creates a new task through a public task form
+// @Summary Submit a task form
+// @Description Creates a new task in the project of the form. Only the fields allowed by the form will be used. Does not require authentication.
+// @tags project
+// @Accept json
+// @Produce json
+// @Param hash path string true "The form hash"
+// @Param submission body models.TaskFormSubmission true "The form submission"
+// @Success 201 {object} models.Message "The task was created."
+// @Failure 400 {object} web.HTTPError "Invalid submission or captcha."
+// @Failure 404 {object} web.HTTPError "The task form does not exist."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /forms/{hash}/submit [put]
+func SubmitTaskForm(c echo.Context) error {
+	submission := &models.TaskFormSubmission{}
+	if err := c.Bind(submission); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid model provided.")
+	}
+
+	if err := c.Validate(submission); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	_, err := models.SubmitTaskForm(s, submission, c.RealIP())
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusCreated, models.Message{Message: "The task was created successfully."})
+}
@@ -0,0 +1,73 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/api/pkg/notifications"
+
+	"code.vikunja.io/web/handler"
+	"github.com/labstack/echo/v4"
+)
+
+// ReceiveTelegramUpdate handles the messages users send to the telegram bot
+// @Summary Receive a telegram update
+// @Description The webhook of the telegram bot. Links telegram accounts, marks tasks as done or snoozes them when users reply to a notification and creates tasks from all other messages. The answer to the user is returned as sendMessage call. Requires the configured secret in the `X-Telegram-Bot-Api-Secret-Token` header.
+// @tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} notifications.TelegramSendMessage "The answer to the user."
+// @Failure 400 {object} web.HTTPError "The update is invalid."
+// @Failure 401 {object} web.HTTPError "The secret is missing or wrong."
+// @Failure 500 {object} models.Message "Internal error"
+// @Router /telegram/webhook [post]
+func ReceiveTelegramUpdate(c echo.Context) error {
+	secret := config.TelegramSecret.GetString()
+	token := c.Request().Header.Get("X-Telegram-Bot-Api-Secret-Token")
+	if secret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid telegram secret.")
+	}
+
+	update := &notifications.TelegramUpdate{}
+	if err := c.Bind(update); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid update provided.")
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	answer, err := models.HandleTelegramUpdate(s, update)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if answer == nil {
+		return c.NoContent(http.StatusOK)
+	}
+	return c.JSON(http.StatusOK, answer)
+}
@@ -0,0 +1,170 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+	user2 "code.vikunja.io/api/pkg/user"
+
+	"code.vikunja.io/web/handler"
+	"github.com/labstack/echo/v4"
+)
+
+// UserNotificationPreferences holds which notifications a user receives through which channel
+type UserNotificationPreferences struct {
+	// All preferences of the user. Notifications without a matching preference are sent through all channels.
+	Preferences []*user2.NotificationPreference `json:"preferences"`
+}
+
+// GetUserNotificationPreferences returns the notification preferences of the current user
+// @Summary Return the notification preferences
+// @Description Returns through which channels the current user receives which notifications.
+// @tags user
+// @Produce json
+// @Security JWTKeyAuth
+// @Success 200 {object} v1.UserNotificationPreferences
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /user/settings/notifications [get]
+func GetUserNotificationPreferences(c echo.Context) error {
+	u, err := user2.GetCurrentUser(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	preferences, err := user2.GetNotificationPreferences(s, u)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, &UserNotificationPreferences{Preferences: preferences})
+}
+
+// UpdateUserNotificationPreferences replaces the notification preferences of the current user
+// @Summary Change the notification preferences
+// @Description Replaces all notification preferences of the current user. A preference for a single notification, like `task.comment`, takes precedence over one for all notifications (`*`). Valid channels are `email`, `in_app`, `push`, `webhook` and `telegram`.
+// @tags user
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param preferences body v1.UserNotificationPreferences true "The new notification preferences"
+// @Success 200 {object} models.Message
+// @Failure 400 {object} web.HTTPError "Something's invalid."
+// @Failure 412 {object} web.HTTPError "A channel does not exist."
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /user/settings/notifications [post]
+func UpdateUserNotificationPreferences(c echo.Context) error {
+	preferences := &UserNotificationPreferences{}
+	err := c.Bind(preferences)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid model provided.")
+	}
+
+	u, err := user2.GetCurrentUser(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	err = user2.SetNotificationPreferences(s, u, preferences.Preferences)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, &models.Message{Message: "The notification preferences were updated successfully."})
+}
+
+// NotificationSnooze holds until when all notifications of a user are snoozed
+type NotificationSnooze struct {
+	// Notifications created before this time are delivered once it has passed.
+	Until time.Time `json:"until"`
+}
+
+// SnoozeUserNotifications snoozes all notifications of the current user
+// @Summary Snooze all notifications
+// @Description Defers the delivery of all notifications of the current user until the given time. Notifications are still created and shown in the app right away.
+// @tags user
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param snooze body v1.NotificationSnooze true "Until when notifications should be snoozed"
+// @Success 200 {object} v1.NotificationSnooze
+// @Failure 400 {object} web.HTTPError "Something's invalid."
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /user/settings/notifications/snooze [post]
+func SnoozeUserNotifications(c echo.Context) error {
+	snooze := &NotificationSnooze{}
+	err := c.Bind(snooze)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid model provided.")
+	}
+	if !snooze.Until.After(time.Now()) {
+		return echo.NewHTTPError(http.StatusBadRequest, "The snooze must end in the future.")
+	}
+
+	return setUserNotificationSnooze(c, snooze)
+}
+
+// EndUserNotificationSnooze ends the notification snooze of the current user
+// @Summary End the notification snooze
+// @Description Delivers all notifications again right away. Snoozed notifications are delivered within the next minute.
+// @tags user
+// @Produce json
+// @Security JWTKeyAuth
+// @Success 200 {object} v1.NotificationSnooze
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /user/settings/notifications/snooze [delete]
+func EndUserNotificationSnooze(c echo.Context) error {
+	return setUserNotificationSnooze(c, &NotificationSnooze{})
+}
+
+func setUserNotificationSnooze(c echo.Context, snooze *NotificationSnooze) error {
+	u, err := user2.GetCurrentUser(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	err = user2.SnoozeNotifications(s, u, snooze.Until)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, snooze)
+}
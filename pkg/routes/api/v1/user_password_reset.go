@@ -36,6 +36,7 @@ import (
 // @Param credentials body user.PasswordReset true "The token with the new password."
 // @Success 200 {object} models.Message
 // @Failure 400 {object} web.HTTPError "Bad token provided."
+// @Failure 412 {object} web.HTTPError "The new password does not match the password policy."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /user/password/reset [post]
 func UserResetPassword(c echo.Context) error {
@@ -38,6 +38,7 @@ import (
 // @Param credentials body user.APIUserPassword true "The user credentials"
 // @Success 200 {object} user.User
 // @Failure 400 {object} web.HTTPError "No or invalid user register object provided / User already exists."
+// @Failure 412 {object} web.HTTPError "The password does not match the password policy."
 // @Failure 500 {object} models.Message "Internal error"
 // @Router /register [post]
 func RegisterUser(c echo.Context) error {
@@ -61,11 +62,18 @@ func RegisterUser(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, models.Message{Message: "No or invalid user model provided."})
 	}
 
+	newUser := userIn.APIFormat()
+	if newUser.Password != "" {
+		if err := user.CheckPasswordPolicy(newUser.Password, newUser); err != nil {
+			return handler.HandleHTTPError(err, c)
+		}
+	}
+
 	s := db.NewSession()
 	defer s.Close()
 
 	// Insert the user
-	newUser, err := user.CreateUser(s, userIn.APIFormat())
+	newUser, err := user.CreateUser(s, newUser)
 	if err != nil {
 		_ = s.Rollback()
 		return handler.HandleHTTPError(err, c)
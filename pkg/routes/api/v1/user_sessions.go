@@ -0,0 +1,121 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"net/http"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/api/pkg/user"
+
+	"code.vikunja.io/web/handler"
+	"github.com/labstack/echo/v4"
+)
+
+// GetUserSessions returns all sessions of the current user
+// @Summary Get all sessions of the current user
+// @Description Returns all devices the current user is logged in on, the most recently used first. The session the request was made with is marked as current.
+// @tags user
+// @Produce json
+// @Security JWTKeyAuth
+// @Success 200 {array} user.Session "The sessions."
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /user/sessions [get]
+func GetUserSessions(c echo.Context) error {
+	u, err := user.GetCurrentUser(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	sessions, err := user.GetSessions(s, u, auth.GetSessionIDFromClaims(c))
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeUserSession logs the current user out on one device
+// @Summary Revoke a session
+// @Description Revokes a session of the current user. All tokens of the session are rejected from now on.
+// @tags user
+// @Produce json
+// @Security JWTKeyAuth
+// @Param session path string true "The id of the session to revoke."
+// @Success 200 {object} models.Message "The session was revoked."
+// @Failure 404 {object} web.HTTPError "The session does not exist."
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /user/sessions/{session} [delete]
+func RevokeUserSession(c echo.Context) error {
+	u, err := user.GetCurrentUser(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	err = user.RevokeSession(s, u, c.Param("session"))
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, models.Message{Message: "The session was revoked successfully."})
+}
+
+// RevokeOtherUserSessions logs the current user out on all other devices
+// @Summary Revoke all other sessions
+// @Description Revokes all sessions of the current user except the one the request was made with.
+// @tags user
+// @Produce json
+// @Security JWTKeyAuth
+// @Success 200 {object} models.Message "The sessions were revoked."
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /user/sessions [delete]
+func RevokeOtherUserSessions(c echo.Context) error {
+	u, err := user.GetCurrentUser(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	err = user.RevokeAllSessionsExcept(s, u, auth.GetSessionIDFromClaims(c))
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, models.Message{Message: "All other sessions were revoked successfully."})
+}
@@ -59,6 +59,15 @@ type UserSettings struct {
 	Language string `json:"language"`
 	// The user's time zone. Used to send task reminders in the time zone of the user.
 	Timezone string `json:"timezone"`
+	// How often the user gets a digest email with the tasks due soon, overdue tasks and the recent activity in
+	// the projects they subscribed to. One of `none`, `daily` or `weekly`. Weekly digests are sent on the first day of the week.
+	DigestFrequency string `json:"digest_frequency" valid:"in(none|daily|weekly)"`
+	// The time when the digest email will be sent.
+	DigestTime string `json:"digest_time" valid:"time"`
+	// Notifications will not be delivered between the start and the end of the quiet hours, in the time zone of
+	// the user. They are delivered once the quiet hours are over instead. Leave empty to disable quiet hours.
+	QuietHoursStart string `json:"quiet_hours_start" valid:"time"`
+	QuietHoursEnd   string `json:"quiet_hours_end" valid:"time"`
 	// Additional settings only used by the frontend
 	FrontendSettings interface{} `json:"frontend_settings"`
 }
@@ -200,6 +209,10 @@ func UpdateGeneralUserSettings(c echo.Context) error {
 	user.Language = us.Language
 	user.Timezone = us.Timezone
 	user.OverdueTasksRemindersTime = us.OverdueTasksRemindersTime
+	user.DigestFrequency = us.DigestFrequency
+	user.DigestTime = us.DigestTime
+	user.QuietHoursStart = us.QuietHoursStart
+	user.QuietHoursEnd = us.QuietHoursEnd
 	user.FrontendSettings = us.FrontendSettings
 
 	_, err = user2.UpdateUser(s, user, true)
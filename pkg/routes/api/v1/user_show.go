@@ -36,6 +36,12 @@ type userWithSettings struct {
 	Settings            *UserSettings `json:"settings"`
 	DeletionScheduledAt time.Time     `json:"deletion_scheduled_at"`
 	IsLocalUser         bool          `json:"is_local_user"`
+	// Whether this user is an instance admin and can use the admin endpoints.
+	IsAdmin bool `json:"is_admin"`
+	// Until when all notifications of this user are snoozed.
+	NotificationsSnoozedUntil time.Time `json:"notifications_snoozed_until"`
+	// How much storage all attachments uploaded by this user use and how much they may use. Not returned for link shares.
+	AttachmentsUsage *models.AttachmentsUsage `json:"attachments_usage,omitempty"`
 }
 
 // UserShow gets all informations about the current user
@@ -76,10 +82,24 @@ func UserShow(c echo.Context) error {
 			Language:                     u.Language,
 			Timezone:                     u.Timezone,
 			OverdueTasksRemindersTime:    u.OverdueTasksRemindersTime,
+			DigestFrequency:              u.DigestFrequency,
+			DigestTime:                   u.DigestTime,
+			QuietHoursStart:              u.QuietHoursStart,
+			QuietHoursEnd:                u.QuietHoursEnd,
 			FrontendSettings:             u.FrontendSettings,
 		},
 		DeletionScheduledAt: u.DeletionScheduledAt,
 		IsLocalUser:         u.Issuer == user.IssuerLocal,
+		IsAdmin:             u.IsAdmin,
+
+		NotificationsSnoozedUntil: u.NotificationsSnoozedUntil,
+	}
+
+	if _, is := a.(*models.LinkSharing); !is {
+		us.AttachmentsUsage, err = models.GetUserAttachmentsUsage(s, u.ID)
+		if err != nil {
+			return handler.HandleHTTPError(err, c)
+		}
 	}
 
 	return c.JSON(http.StatusOK, us)
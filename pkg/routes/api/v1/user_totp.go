@@ -67,6 +67,13 @@ func UserTOTPEnroll(c echo.Context) error {
 	return c.JSON(http.StatusOK, t)
 }
 
+// TOTPEnabledResponse is returned after totp was enabled successfully.
+type TOTPEnabledResponse struct {
+	models.Message
+	// One-time recovery codes the user can log in with instead of a totp passcode. They are only shown once.
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
 // UserTOTPEnable is the handler to enable totp for a user
 // @Summary Enable a previously enrolled totp setting.
 // @Description Enables a previously enrolled totp setting by providing a totp passcode.
@@ -75,7 +82,7 @@ func UserTOTPEnroll(c echo.Context) error {
 // @Produce json
 // @Param totp body user.TOTPPasscode true "The totp passcode."
 // @Security JWTKeyAuth
-// @Success 200 {object} models.Message "Successfully enabled"
+// @Success 200 {object} v1.TOTPEnabledResponse "Successfully enabled, contains the recovery codes."
 // @Failure 400 {object} web.HTTPError "Something's invalid."
 // @Failure 404 {object} web.HTTPError "User does not exist."
 // @Failure 412 {object} web.HTTPError "TOTP is not enrolled."
@@ -102,7 +109,7 @@ func UserTOTPEnable(c echo.Context) error {
 	s := db.NewSession()
 	defer s.Close()
 
-	err = user.EnableTOTP(s, passcode)
+	codes, err := user.EnableTOTP(s, passcode)
 	if err != nil {
 		_ = s.Rollback()
 		return handler.HandleHTTPError(err, c)
@@ -113,7 +120,10 @@ func UserTOTPEnable(c echo.Context) error {
 		return handler.HandleHTTPError(err, c)
 	}
 
-	return c.JSON(http.StatusOK, models.Message{Message: "TOTP was enabled successfully."})
+	return c.JSON(http.StatusOK, &TOTPEnabledResponse{
+		Message:       models.Message{Message: "TOTP was enabled successfully."},
+		RecoveryCodes: codes.Codes,
+	})
 }
 
 // UserTOTPDisable disables totp settings for the current user.
@@ -246,3 +256,104 @@ func UserTOTP(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, t)
 }
+
+// UserTOTPRecoveryCodes returns how many unused recovery codes the current user has left.
+// @Summary Count the remaining totp recovery codes
+// @Description Returns how many of the current user's totp recovery codes were not used yet. The codes themselves are only shown once when they are generated.
+// @tags user
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Success 200 {object} user.TOTPRecoveryCodes "The number of remaining recovery codes."
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /user/settings/totp/recovery-codes [get]
+func UserTOTPRecoveryCodes(c echo.Context) error {
+	u, err := user.GetCurrentUser(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	codes, err := user.GetTOTPRecoveryCodesCount(s, u)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, codes)
+}
+
+// RegenerateUserTOTPRecoveryCodes replaces the totp recovery codes of the current user with new ones.
+// @Summary Regenerate totp recovery codes
+// @Description Invalidates all existing totp recovery codes of the current user and returns a new set. Requires totp to be enabled.
+// @tags user
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param totp body user.Login true "The current user's password (only password is enough)."
+// @Success 200 {object} user.TOTPRecoveryCodes "The new recovery codes."
+// @Failure 400 {object} web.HTTPError "Something's invalid."
+// @Failure 412 {object} web.HTTPError "TOTP is not enabled."
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /user/settings/totp/recovery-codes [post]
+func RegenerateUserTOTPRecoveryCodes(c echo.Context) error {
+	login := &user.Login{}
+	if err := c.Bind(login); err != nil {
+		log.Debugf("Invalid model error. Internal error was: %s", err.Error())
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid model provided. Error was: %s", he.Message))
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid model provided.")
+	}
+
+	u, err := user.GetCurrentUser(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	u, err = user.GetUserByID(s, u.ID)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	err = user.CheckUserPassword(u, login.Password)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	enabled, err := user.TOTPEnabledForUser(s, u)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+	if !enabled {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(user.ErrTOTPNotEnabled{}, c)
+	}
+
+	codes, err := user.GenerateTOTPRecoveryCodes(s, u)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, codes)
+}
@@ -44,6 +44,7 @@ type UserPassword struct {
 // @Success 200 {object} models.Message
 // @Failure 400 {object} web.HTTPError "Something's invalid."
 // @Failure 404 {object} web.HTTPError "User does not exist."
+// @Failure 412 {object} web.HTTPError "The new password does not match the password policy."
 // @Failure 500 {object} models.Message "Internal server error."
 // @Router /user/password [post]
 func UserChangePassword(c echo.Context) error {
@@ -0,0 +1,276 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/api/pkg/modules/keyvalue"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/web/handler"
+
+	"github.com/labstack/echo/v4"
+)
+
+// WebAuthnLoginRequest optionally names the user who wants to log in
+type WebAuthnLoginRequest struct {
+	// The username of the user who wants to log in. If empty, any passkey on the authenticator can be used.
+	Username string `json:"username"`
+}
+
+func bindWebAuthnModel(c echo.Context, model interface{}) error {
+	if err := c.Bind(model); err != nil {
+		log.Debugf("Invalid model error. Internal error was: %s", err.Error())
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid model provided. Error was: %s", he.Message))
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid model provided.")
+	}
+	return nil
+}
+
+// GetUserWebAuthnCredentials returns all passkeys of the current user
+// @Summary Get all passkeys
+// @Description Returns all passkeys and security keys the current user registered.
+// @tags user
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Success 200 {array} user.WebAuthnCredential
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /user/settings/webauthn [get]
+func GetUserWebAuthnCredentials(c echo.Context) error {
+	u, err := user.GetCurrentUser(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	credentials, err := user.GetWebAuthnCredentials(s, u)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, credentials)
+}
+
+// BeginUserWebAuthnRegistration starts registering a new passkey
+// @Summary Start registering a passkey
+// @Description Returns the options to pass to navigator.credentials.create() in the browser. All binary values are base64url encoded. The result has to be sent to the "finish passkey registration" endpoint within five minutes.
+// @tags user
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Success 200 {object} user.WebAuthnRegistrationOptions
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /user/settings/webauthn/register/begin [post]
+func BeginUserWebAuthnRegistration(c echo.Context) error {
+	u, err := user.GetCurrentUser(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	u, err = user.GetUserByID(s, u.ID)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	options, err := user.BeginWebAuthnRegistration(s, u)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, options)
+}
+
+// FinishUserWebAuthnRegistration saves a new passkey
+// @Summary Finish registering a passkey
+// @Description Verifies the result of navigator.credentials.create() and saves the new passkey. All binary values need to be base64url encoded.
+// @tags user
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param registration body user.WebAuthnRegistration true "The new credential."
+// @Success 200 {object} user.WebAuthnCredential
+// @Failure 400 {object} web.HTTPError "Something's invalid."
+// @Failure 412 {object} web.HTTPError "The credential could not be verified."
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /user/settings/webauthn/register/finish [post]
+func FinishUserWebAuthnRegistration(c echo.Context) error {
+	registration := &user.WebAuthnRegistration{}
+	if err := bindWebAuthnModel(c, registration); err != nil {
+		return err
+	}
+
+	u, err := user.GetCurrentUser(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	credential, err := user.FinishWebAuthnRegistration(s, u, registration)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, credential)
+}
+
+// DeleteUserWebAuthnCredential removes a passkey
+// @Summary Delete a passkey
+// @Description Removes a passkey or security key of the current user.
+// @tags user
+// @Accept json
+// @Produce json
+// @Security JWTKeyAuth
+// @Param credential path int true "Credential ID"
+// @Success 200 {object} models.Message "Successfully deleted."
+// @Failure 404 {object} web.HTTPError "The passkey does not exist."
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /user/settings/webauthn/{credential} [delete]
+func DeleteUserWebAuthnCredential(c echo.Context) error {
+	credentialID, err := strconv.ParseInt(c.Param("credential"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid credential id provided.")
+	}
+
+	u, err := user.GetCurrentUser(c)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	err = user.DeleteWebAuthnCredential(s, u, credentialID)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, models.Message{Message: "Successfully deleted."})
+}
+
+// BeginWebAuthnLogin starts logging in with a passkey
+// @Summary Start logging in with a passkey
+// @Description Returns the options to pass to navigator.credentials.get() in the browser. All binary values are base64url encoded. The result can either be sent to the "log in with a passkey" endpoint or to the login endpoint instead of a totp passcode.
+// @tags auth
+// @Accept json
+// @Produce json
+// @Param login body v1.WebAuthnLoginRequest false "The user who wants to log in."
+// @Success 200 {object} user.WebAuthnLoginOptions
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /login/webauthn/begin [post]
+func BeginWebAuthnLogin(c echo.Context) error {
+	request := &WebAuthnLoginRequest{}
+	if err := bindWebAuthnModel(c, request); err != nil {
+		return err
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	var u *user.User
+	if request.Username != "" {
+		var err error
+		u, err = user.GetUserByUsername(s, request.Username)
+		// Unknown users get options without credentials to not reveal which users exist
+		if user.IsErrUserDoesNotExist(err) {
+			u, err = &user.User{}, nil
+		}
+		if err != nil {
+			return handler.HandleHTTPError(err, c)
+		}
+	}
+
+	options, err := user.BeginWebAuthnLogin(s, u)
+	if err != nil {
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return c.JSON(http.StatusOK, options)
+}
+
+// FinishWebAuthnLogin logs a user in with a passkey
+// @Summary Log in with a passkey
+// @Description Verifies the result of navigator.credentials.get() and logs the user in without a password. The authenticator must have verified the user, for example with a fingerprint or pin. All binary values need to be base64url encoded.
+// @tags auth
+// @Accept json
+// @Produce json
+// @Param assertion body user.WebAuthnAssertion true "The assertion of the authenticator."
+// @Success 200 {object} auth.Token
+// @Failure 400 {object} web.HTTPError "Something's invalid."
+// @Failure 412 {object} web.HTTPError "The passkey could not be verified."
+// @Failure 500 {object} models.Message "Internal server error."
+// @Router /login/webauthn/finish [post]
+func FinishWebAuthnLogin(c echo.Context) error {
+	assertion := &user.WebAuthnAssertion{}
+	if err := bindWebAuthnModel(c, assertion); err != nil {
+		return err
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	u, err := user.FinishWebAuthnLogin(s, nil, assertion)
+	if err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	if u.Status == user.StatusDisabled {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(&user.ErrAccountDisabled{UserID: u.ID}, c)
+	}
+
+	if err := keyvalue.Del(u.GetFailedPasswordAttemptsKey()); err != nil {
+		return err
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return handler.HandleHTTPError(err, c)
+	}
+
+	return auth.NewUserAuthTokenResponse(u, c, assertion.LongToken)
+}
@@ -71,10 +71,24 @@ func checkAPITokenAndPutItInContext(tokenHeaderValue string, c echo.Context) err
 		return echo.NewHTTPError(http.StatusUnauthorized)
 	}
 
+	if token.ReadOnly && c.Request().Method != http.MethodGet {
+		log.Debugf("[auth] Tried using read-only token %d for %s %s", token.ID, c.Request().Method, c.Path())
+		return echo.NewHTTPError(http.StatusUnauthorized)
+	}
+
 	if !models.CanDoAPIRoute(c, token) {
 		return echo.NewHTTPError(http.StatusUnauthorized)
 	}
 
+	can, err := token.CanAccessRouteProject(s, c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	if !can {
+		log.Debugf("[auth] Tried using token %d limited to projects %v for route %s", token.ID, token.ProjectIDs, c.Path())
+		return echo.NewHTTPError(http.StatusUnauthorized)
+	}
+
 	c.Set("api_token", token)
 
 	return nil
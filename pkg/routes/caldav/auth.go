@@ -49,6 +49,7 @@ func BasicAuth(username, password string, c echo.Context) (bool, error) {
 		}
 	}
 	if u != nil && err == nil {
+		u.AuthSource = user.AuthSourceCaldav
 		c.Set("userBasicAuth", u)
 		return true, nil
 	}
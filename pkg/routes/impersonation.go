@@ -0,0 +1,116 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/api/pkg/modules/auth"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Impersonating admins should be able to debug an issue, not take over the account. These routes change how the
+// user logs in or create credentials which outlive the impersonation token and are therefore not available.
+var impersonationForbiddenRoutes = []string{
+	"/api/v1/admin",
+	"/api/v1/tokens",
+	"/api/v1/user/password",
+	"/api/v1/user/deletion",
+	"/api/v1/user/export",
+	"/api/v1/user/sessions",
+	"/api/v1/user/settings/email",
+	"/api/v1/user/settings/totp",
+	"/api/v1/user/settings/webauthn",
+	"/api/v1/user/settings/token",
+	"/api/v1/user/settings/telegram",
+	"/api/v1/user/settings/push-subscriptions",
+	"/api/v1/user/settings/chat-integrations",
+}
+
+// Link shares, webhooks and the other integrations of a project keep working after the impersonation ended and
+// could be used to access the data of the user later on.
+var impersonationForbiddenProjectRoutes = []string{
+	"/shares",
+	"/webhooks",
+	"/chat-integrations",
+	"/forms",
+	"/email-intake",
+}
+
+// auditImpersonatedRequests records every request made with an impersonation token in the audit log and keeps
+// impersonating admins away from sensitive account settings.
+func auditImpersonatedRequests(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		impersonatorID := auth.GetImpersonatorIDFromClaims(c)
+		if impersonatorID == 0 {
+			return next(c)
+		}
+
+		if isForbiddenWhileImpersonating(c.Path()) {
+			return echo.NewHTTPError(http.StatusForbidden, "This is not available while impersonating a user.")
+		}
+
+		a, err := auth.GetAuthFromClaims(c)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized).SetInternal(err)
+		}
+
+		// The error is handled here already to know the status code the request was answered with
+		if err := next(c); err != nil {
+			c.Error(err)
+		}
+
+		s := db.NewSession()
+		defer s.Close()
+
+		err = models.CreateAuditLogEntry(s, &models.AuditLogEntry{
+			UserID:         a.GetID(),
+			ImpersonatorID: impersonatorID,
+			Action:         models.AuditActionImpersonatedRequest,
+			Method:         c.Request().Method,
+			Path:           c.Request().URL.RequestURI(),
+			StatusCode:     c.Response().Status,
+			IPAddress:      c.RealIP(),
+		})
+		if err != nil {
+			_ = s.Rollback()
+			log.Errorf("Could not save impersonated request of user %d by admin %d to the audit log: %s", a.GetID(), impersonatorID, err)
+			return nil
+		}
+
+		return s.Commit()
+	}
+}
+
+func isForbiddenWhileImpersonating(path string) bool {
+	for _, route := range impersonationForbiddenRoutes {
+		if strings.HasPrefix(path, route) {
+			return true
+		}
+	}
+	for _, route := range impersonationForbiddenProjectRoutes {
+		if strings.HasPrefix(path, "/api/v1/projects/:project"+route) {
+			return true
+		}
+	}
+	return false
+}
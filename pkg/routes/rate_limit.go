@@ -39,6 +39,9 @@ func RateLimit(rateLimiter *limiter.Limiter, rateLimitKind string) echo.Middlewa
 			switch rateLimitKind {
 			case "ip":
 				rateLimitKey = c.RealIP()
+			case "form":
+				// Prefixed so form submissions don't count towards the other limits of an ip when using a shared store
+				rateLimitKey = "form_" + c.RealIP()
 			case "user":
 				auth, err := auth2.GetAuthFromClaims(c)
 				if err != nil {
@@ -76,6 +76,7 @@ import (
 	vikunja_file "code.vikunja.io/api/pkg/modules/migration/vikunja-file"
 	apiv1 "code.vikunja.io/api/pkg/routes/api/v1"
 	"code.vikunja.io/api/pkg/routes/caldav"
+	"code.vikunja.io/api/pkg/routes/scim"
 	"code.vikunja.io/api/pkg/version"
 	"code.vikunja.io/web"
 	"code.vikunja.io/web/handler"
@@ -259,15 +260,23 @@ func registerAPIRoutes(a *echo.Group) {
 	rateLimiter := createRateLimiter(rate)
 	ur.Use(RateLimit(rateLimiter, "ip"))
 
+	if config.AuthLocalEnabled.GetBool() || config.AuthLdapEnabled.GetBool() {
+		ur.POST("/login", apiv1.Login)
+	}
+
 	if config.AuthLocalEnabled.GetBool() {
 		// User stuff
-		ur.POST("/login", apiv1.Login)
 		ur.POST("/register", apiv1.RegisterUser)
 		ur.POST("/user/password/token", apiv1.UserRequestResetPasswordToken)
 		ur.POST("/user/password/reset", apiv1.UserResetPassword)
 		ur.POST("/user/confirm", apiv1.UserConfirmEmail)
 	}
 
+	if config.ServiceEnableWebAuthn.GetBool() {
+		ur.POST("/login/webauthn/begin", apiv1.BeginWebAuthnLogin)
+		ur.POST("/login/webauthn/finish", apiv1.FinishWebAuthnLogin)
+	}
+
 	if config.AuthOpenIDEnabled.GetBool() {
 		ur.POST("/auth/openid/:provider/callback", openid.HandleCallback)
 	}
@@ -288,8 +297,47 @@ func registerAPIRoutes(a *echo.Group) {
 		ur.POST("/shares/:share/auth", apiv1.AuthenticateLinkShare)
 	}
 
+	// Public task forms, submissions have their own rate limit to prevent spam
+	if config.TaskFormsEnabled.GetBool() {
+		formRate := limiter.Rate{
+			Period: 60 * time.Second,
+			Limit:  config.TaskFormsRateLimit.GetInt64(),
+		}
+		a.GET("/forms/:hash", apiv1.GetPublicTaskForm)
+		a.PUT("/forms/:hash/submit", apiv1.SubmitTaskForm, RateLimit(createRateLimiter(formRate), "form"))
+	}
+
+	// Public projects
+	if config.ServiceEnablePublicProjects.GetBool() {
+		ur.GET("/public/projects/:project", apiv1.GetPublicProject)
+		ur.GET("/public/projects/:project/views/:view/tasks", apiv1.GetPublicProjectViewTasks)
+	}
+
+	// Task action links from overdue task emails, authenticated by their signed token
+	if config.ServiceEnableEmailReminders.GetBool() {
+		ur.GET("/task-actions/:token", apiv1.ShowTaskAction)
+		ur.POST("/task-actions/:token", apiv1.RunTaskAction)
+	}
+
+	// Email intake hook, called by the mail server with its own secret
+	if config.EmailIntakeEnabled.GetBool() {
+		a.POST("/email-intake", apiv1.ReceiveIntakeEmail)
+	}
+
+	// Telegram bot webhook, called by telegram with the configured secret
+	if config.TelegramEnabled.GetBool() {
+		a.POST("/telegram/webhook", apiv1.ReceiveTelegramUpdate)
+	}
+
+	// SCIM provisioning, authenticated with its own token
+	if config.SCIMEnabled.GetBool() {
+		registerSCIMRoutes(a.Group("/scim/v2"))
+	}
+
 	// ===== Routes with Authentication =====
 	a.Use(SetupTokenMiddleware())
+	a.Use(checkSessionNotRevoked)
+	a.Use(auditImpersonatedRequests)
 
 	// Rate limit
 	setupRateLimit(a, config.RateLimitKind.GetString())
@@ -312,19 +360,79 @@ func registerAPIRoutes(a *echo.Group) {
 	u.POST("/settings/avatar", apiv1.ChangeUserAvatarProvider)
 	u.PUT("/settings/avatar/upload", apiv1.UploadAvatar)
 	u.POST("/settings/general", apiv1.UpdateGeneralUserSettings)
+	u.GET("/settings/notifications", apiv1.GetUserNotificationPreferences)
+	u.POST("/settings/notifications", apiv1.UpdateUserNotificationPreferences)
+	u.POST("/settings/notifications/snooze", apiv1.SnoozeUserNotifications)
+	u.DELETE("/settings/notifications/snooze", apiv1.EndUserNotificationSnooze)
 	u.POST("/export/request", apiv1.RequestUserDataExport)
 	u.POST("/export/download", apiv1.DownloadUserDataExport)
 	u.GET("/timezones", apiv1.GetAvailableTimezones)
+	u.GET("/sessions", apiv1.GetUserSessions)
+	u.DELETE("/sessions", apiv1.RevokeOtherUserSessions)
+	u.DELETE("/sessions/:session", apiv1.RevokeUserSession)
+
+	inboxHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.InboxItem{}
+		},
+	}
+	u.GET("/inbox", inboxHandler.ReadAllWeb)
+	u.POST("/inbox/:inboxitem", inboxHandler.UpdateWeb)
+	inboxReadHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.InboxRead{}
+		},
+	}
+	u.POST("/inbox/read", inboxReadHandler.CreateWeb)
 	u.PUT("/settings/token/caldav", apiv1.GenerateCaldavToken)
 	u.GET("/settings/token/caldav", apiv1.GetCaldavTokens)
 	u.DELETE("/settings/token/caldav/:id", apiv1.DeleteCaldavToken)
 
+	myDayHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.MyDay{}
+		},
+	}
+	u.GET("/myday", myDayHandler.ReadAllWeb)
+	u.PUT("/myday", myDayHandler.CreateWeb)
+
 	if config.ServiceEnableTotp.GetBool() {
 		u.GET("/settings/totp", apiv1.UserTOTP)
 		u.POST("/settings/totp/enroll", apiv1.UserTOTPEnroll)
 		u.POST("/settings/totp/enable", apiv1.UserTOTPEnable)
 		u.POST("/settings/totp/disable", apiv1.UserTOTPDisable)
 		u.GET("/settings/totp/qrcode", apiv1.UserTOTPQrCode)
+		u.GET("/settings/totp/recovery-codes", apiv1.UserTOTPRecoveryCodes)
+		u.POST("/settings/totp/recovery-codes", apiv1.RegenerateUserTOTPRecoveryCodes)
+	}
+
+	if config.ServiceEnableWebAuthn.GetBool() {
+		u.GET("/settings/webauthn", apiv1.GetUserWebAuthnCredentials)
+		u.POST("/settings/webauthn/register/begin", apiv1.BeginUserWebAuthnRegistration)
+		u.POST("/settings/webauthn/register/finish", apiv1.FinishUserWebAuthnRegistration)
+		u.DELETE("/settings/webauthn/:credential", apiv1.DeleteUserWebAuthnCredential)
+	}
+
+	if config.TelegramEnabled.GetBool() {
+		telegramChatHandler := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.TelegramChat{}
+			},
+		}
+		u.GET("/settings/telegram", telegramChatHandler.ReadOneWeb)
+		u.PUT("/settings/telegram", telegramChatHandler.CreateWeb)
+		u.DELETE("/settings/telegram", telegramChatHandler.DeleteWeb)
+	}
+
+	if config.WebPushEnabled.GetBool() {
+		pushSubscriptionHandler := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.PushSubscription{}
+			},
+		}
+		u.GET("/settings/push-subscriptions", pushSubscriptionHandler.ReadAllWeb)
+		u.PUT("/settings/push-subscriptions", pushSubscriptionHandler.CreateWeb)
+		u.DELETE("/settings/push-subscriptions/:subscription", pushSubscriptionHandler.DeleteWeb)
 	}
 
 	// User deletion
@@ -375,6 +483,48 @@ func registerAPIRoutes(a *echo.Group) {
 	a.PUT("/projects/:project/views/:view/buckets", kanbanBucketHandler.CreateWeb)
 	a.POST("/projects/:project/views/:view/buckets/:bucket", kanbanBucketHandler.UpdateWeb)
 	a.DELETE("/projects/:project/views/:view/buckets/:bucket", kanbanBucketHandler.DeleteWeb)
+	a.POST("/projects/:project/views/:view/buckets/:bucket/restore", apiv1.RestoreBucket)
+
+	bucketTemplateProvider := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.BucketTemplate{}
+		},
+	}
+	a.GET("/projects/:project/bucket-templates", bucketTemplateProvider.ReadAllWeb)
+	a.PUT("/projects/:project/bucket-templates", bucketTemplateProvider.CreateWeb)
+	a.POST("/projects/:project/bucket-templates/:bucketTemplate", bucketTemplateProvider.UpdateWeb)
+	a.DELETE("/projects/:project/bucket-templates/:bucketTemplate", bucketTemplateProvider.DeleteWeb)
+
+	projectViewPreferencesProvider := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectViewPreferences{}
+		},
+	}
+	a.GET("/projects/:project/preferences", projectViewPreferencesProvider.ReadOneWeb)
+	a.POST("/projects/:project/preferences", projectViewPreferencesProvider.UpdateWeb)
+
+	projectStatisticsHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectStatistics{}
+		},
+	}
+	a.GET("/projects/:project/stats", projectStatisticsHandler.ReadOneWeb)
+
+	projectActivityHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectActivity{}
+		},
+	}
+	a.GET("/projects/:project/activity", projectActivityHandler.ReadAllWeb)
+
+	roleHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.Role{}
+		},
+	}
+	a.GET("/roles", roleHandler.ReadAllWeb)
+	a.GET("/projects/:project/export", apiv1.ExportProject)
+	a.PUT("/projects/import", apiv1.ImportProject)
 
 	projectDuplicateHandler := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
@@ -383,16 +533,63 @@ func registerAPIRoutes(a *echo.Group) {
 	}
 	a.PUT("/projects/:projectid/duplicate", projectDuplicateHandler.CreateWeb)
 
+	projectDuplicateJobHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectDuplicateJob{}
+		},
+	}
+	a.GET("/projects/duplicate-jobs/:job", projectDuplicateJobHandler.ReadOneWeb)
+
+	projectMergeHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectMerge{}
+		},
+	}
+	a.PUT("/projects/:project/merge", projectMergeHandler.CreateWeb)
+
+	projectTransferHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectTransfer{}
+		},
+	}
+	a.GET("/projects/:project/transfer", projectTransferHandler.ReadOneWeb)
+	a.POST("/projects/:project/transfer", projectTransferHandler.CreateWeb)
+	a.DELETE("/projects/:project/transfer", projectTransferHandler.DeleteWeb)
+	a.POST("/projects/:project/transfer/accept", apiv1.AcceptProjectTransfer)
+	a.POST("/projects/:project/unarchive", apiv1.UnarchiveProject)
+
+	projectFromTemplateHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectFromTemplate{}
+		},
+	}
+	a.PUT("/projects/from-template/:template", projectFromTemplateHandler.CreateWeb)
+
 	taskHandler := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
 			return &models.Task{}
 		},
 	}
 	a.PUT("/projects/:project/tasks", taskHandler.CreateWeb)
-	a.GET("/tasks/:projecttask", taskHandler.ReadOneWeb)
+	a.GET("/tasks/:projecttask", taskHandler.ReadOneWeb, MarkTaskAsSeen)
 	a.GET("/tasks/all", taskCollectionHandler.ReadAllWeb)
 	a.DELETE("/tasks/:projecttask", taskHandler.DeleteWeb)
 	a.POST("/tasks/:projecttask", taskHandler.UpdateWeb)
+	a.GET("/tasks/:projecttask/export", apiv1.ExportTask)
+
+	bulkTaskCreateHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.BulkTaskCreate{}
+		},
+	}
+	a.PUT("/projects/:project/tasks/bulk", bulkTaskCreateHandler.CreateWeb)
+
+	projectTasksReadHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectTasksRead{}
+		},
+	}
+	a.POST("/projects/:project/tasks/read", projectTasksReadHandler.CreateWeb)
 
 	taskPositionHandler := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
@@ -401,6 +598,13 @@ func registerAPIRoutes(a *echo.Group) {
 	}
 	a.POST("/tasks/:task/position", taskPositionHandler.UpdateWeb)
 
+	taskSwimlanePositionHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskSwimlanePosition{}
+		},
+	}
+	a.POST("/tasks/:task/swimlane-position", taskSwimlanePositionHandler.UpdateWeb)
+
 	bulkTaskHandler := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
 			return &models.BulkTask{}
@@ -448,6 +652,13 @@ func registerAPIRoutes(a *echo.Group) {
 	a.PUT("/tasks/:task/relations", taskRelationHandler.CreateWeb)
 	a.DELETE("/tasks/:task/relations/:relationKind/:otherTask", taskRelationHandler.DeleteWeb)
 
+	taskProjectConversionHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskProjectConversion{}
+		},
+	}
+	a.POST("/tasks/:task/promote", taskProjectConversionHandler.CreateWeb)
+
 	if config.ServiceEnableTaskAttachments.GetBool() {
 		taskAttachmentHandler := &handler.WebHandler{
 			EmptyStruct: func() handler.CObject {
@@ -455,9 +666,37 @@ func registerAPIRoutes(a *echo.Group) {
 			},
 		}
 		a.GET("/tasks/:task/attachments", taskAttachmentHandler.ReadAllWeb)
+		a.PUT("/tasks/:task/attachments/links", taskAttachmentHandler.CreateWeb)
 		a.DELETE("/tasks/:task/attachments/:attachment", taskAttachmentHandler.DeleteWeb)
 		a.PUT("/tasks/:task/attachments", apiv1.UploadTaskAttachment)
 		a.GET("/tasks/:task/attachments/:attachment", apiv1.GetTaskAttachment)
+		a.GET("/tasks/:task/attachments/:attachment/thumb", apiv1.GetTaskAttachmentThumbnail)
+		a.GET("/tasks/:task/attachments/:attachment/preview", apiv1.GetTaskAttachmentPreview)
+
+		taskAttachmentVersionHandler := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.TaskAttachmentVersion{}
+			},
+		}
+		a.GET("/tasks/:task/attachments/:attachment/versions", taskAttachmentVersionHandler.ReadAllWeb)
+		a.PUT("/tasks/:task/attachments/:attachment/versions", apiv1.UploadTaskAttachmentVersion)
+		a.GET("/tasks/:task/attachments/:attachment/versions/:version", apiv1.GetTaskAttachmentVersion)
+		taskAttachmentVersionRestoreHandler := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.TaskAttachmentVersionRestore{}
+			},
+		}
+		a.POST("/tasks/:task/attachments/:attachment/versions/:version/restore", taskAttachmentVersionRestoreHandler.CreateWeb)
+
+		taskAttachmentUploadHandler := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.TaskAttachmentUpload{}
+			},
+		}
+		a.PUT("/tasks/:task/attachments/uploads", taskAttachmentUploadHandler.CreateWeb)
+		a.GET("/tasks/:task/attachments/uploads/:upload", taskAttachmentUploadHandler.ReadOneWeb)
+		a.PATCH("/tasks/:task/attachments/uploads/:upload", apiv1.UploadTaskAttachmentChunk)
+		a.DELETE("/tasks/:task/attachments/uploads/:upload", taskAttachmentUploadHandler.DeleteWeb)
 	}
 
 	if config.ServiceEnableTaskComments.GetBool() {
@@ -471,7 +710,71 @@ func registerAPIRoutes(a *echo.Group) {
 		a.DELETE("/tasks/:task/comments/:commentid", taskCommentHandler.DeleteWeb)
 		a.POST("/tasks/:task/comments/:commentid", taskCommentHandler.UpdateWeb)
 		a.GET("/tasks/:task/comments/:commentid", taskCommentHandler.ReadOneWeb)
+
+		taskCommentConversionHandler := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.TaskCommentConversion{}
+			},
+		}
+		a.POST("/tasks/:task/comments/:commentid/convert", taskCommentConversionHandler.CreateWeb)
+
+		taskCommentThreadHandler := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.TaskCommentThread{}
+			},
+		}
+		a.GET("/tasks/:task/comments/:commentid/thread", taskCommentThreadHandler.ReadOneWeb)
+
+		taskCommentPinHandler := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.TaskCommentPin{}
+			},
+		}
+		a.PUT("/tasks/:task/comments/:commentid/pin", taskCommentPinHandler.CreateWeb)
+		a.DELETE("/tasks/:task/comments/:commentid/pin", taskCommentPinHandler.DeleteWeb)
+
+		if config.ServiceEnableTaskAttachments.GetBool() {
+			a.PUT("/tasks/:task/comments/:commentid/attachments", apiv1.UploadTaskAttachment)
+
+			commentAttachmentLinkHandler := &handler.WebHandler{
+				EmptyStruct: func() handler.CObject {
+					return &models.TaskAttachment{}
+				},
+			}
+			a.PUT("/tasks/:task/comments/:commentid/attachments/links", commentAttachmentLinkHandler.CreateWeb)
+		}
+
+		taskCommentRevisionHandler := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.TaskCommentRevision{}
+			},
+		}
+		a.GET("/tasks/:task/comments/:commentid/revisions", taskCommentRevisionHandler.ReadAllWeb)
+	}
+
+	taskDescriptionRevisionHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskDescriptionRevision{}
+		},
+	}
+	a.GET("/tasks/:task/description/revisions", taskDescriptionRevisionHandler.ReadAllWeb)
+	a.GET("/tasks/:task/description/revisions/:revision", taskDescriptionRevisionHandler.ReadOneWeb)
+
+	taskDescriptionRevisionRestoreHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskDescriptionRevisionRestore{}
+		},
+	}
+	a.POST("/tasks/:task/description/revisions/:revision/restore", taskDescriptionRevisionRestoreHandler.UpdateWeb)
+
+	taskVoteHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskVote{}
+		},
 	}
+	a.GET("/tasks/:task/votes", taskVoteHandler.ReadAllWeb)
+	a.PUT("/tasks/:task/votes", taskVoteHandler.CreateWeb)
+	a.DELETE("/tasks/:task/votes", taskVoteHandler.DeleteWeb)
 
 	labelHandler := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
@@ -484,6 +787,31 @@ func registerAPIRoutes(a *echo.Group) {
 	a.DELETE("/labels/:label", labelHandler.DeleteWeb)
 	a.POST("/labels/:label", labelHandler.UpdateWeb)
 
+	labelMergeHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.LabelMerge{}
+		},
+	}
+	a.POST("/labels/:label/merge/:otherlabel", labelMergeHandler.CreateWeb)
+
+	labelStatisticsHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.LabelStatistics{}
+		},
+	}
+	a.GET("/labels/stats", labelStatisticsHandler.ReadOneWeb)
+
+	labelGroupHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.LabelGroup{}
+		},
+	}
+	a.GET("/labels/groups", labelGroupHandler.ReadAllWeb)
+	a.GET("/labels/groups/:labelgroup", labelGroupHandler.ReadOneWeb)
+	a.PUT("/labels/groups", labelGroupHandler.CreateWeb)
+	a.DELETE("/labels/groups/:labelgroup", labelGroupHandler.DeleteWeb)
+	a.POST("/labels/groups/:labelgroup", labelGroupHandler.UpdateWeb)
+
 	projectTeamHandler := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
 			return &models.TeamProject{}
@@ -514,6 +842,16 @@ func registerAPIRoutes(a *echo.Group) {
 	a.DELETE("/filters/:filter", savedFiltersHandler.DeleteWeb)
 	a.POST("/filters/:filter", savedFiltersHandler.UpdateWeb)
 
+	savedFilterShareHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.SavedFilterShare{}
+		},
+	}
+	a.GET("/filters/:filter/shares", savedFilterShareHandler.ReadAllWeb)
+	a.PUT("/filters/:filter/shares", savedFilterShareHandler.CreateWeb)
+	a.POST("/filters/:filter/shares/:share", savedFilterShareHandler.UpdateWeb)
+	a.DELETE("/filters/:filter/shares/:share", savedFilterShareHandler.DeleteWeb)
+
 	teamHandler := &handler.WebHandler{
 		EmptyStruct: func() handler.CObject {
 			return &models.Team{}
@@ -553,10 +891,27 @@ func registerAPIRoutes(a *echo.Group) {
 	a.POST("/notifications/:notificationid", notificationHandler.UpdateWeb)
 	a.POST("/notifications", apiv1.MarkAllNotificationsAsRead)
 
+	// Instance administration
+	ad := a.Group("/admin", apiv1.RequireInstanceAdmin)
+	ad.GET("/users", apiv1.AdminListUsers)
+	ad.GET("/users/:user", apiv1.AdminGetUser)
+	ad.DELETE("/users/:user", apiv1.AdminDeleteUser)
+	ad.GET("/users/:user/storage", apiv1.AdminGetUserStorage)
+	ad.POST("/users/:user/status", apiv1.AdminSetUserStatus)
+	ad.POST("/users/:user/password", apiv1.AdminResetUserPassword)
+	ad.POST("/users/:user/email", apiv1.AdminUpdateUserEmail)
+	ad.POST("/users/:user/impersonate", apiv1.AdminImpersonateUser)
+	ad.GET("/audit-log", apiv1.AdminGetAuditLog)
+
 	// Migrations
 	m := a.Group("/migration")
 	registerMigrations(m)
 
+	// Project Icons
+	a.GET("/projects/:project/icon", apiv1.GetProjectIcon)
+	a.PUT("/projects/:project/icon", apiv1.UploadProjectIcon)
+	a.DELETE("/projects/:project/icon", apiv1.RemoveProjectIcon)
+
 	// Project Backgrounds
 	if config.BackgroundsEnabled.GetBool() {
 		a.GET("/projects/:project/background", backgroundHandler.GetProjectBackground)
@@ -603,7 +958,25 @@ func registerAPIRoutes(a *echo.Group) {
 		a.PUT("/projects/:project/webhooks", webhookProvider.CreateWeb)
 		a.DELETE("/projects/:project/webhooks/:webhook", webhookProvider.DeleteWeb)
 		a.POST("/projects/:project/webhooks/:webhook", webhookProvider.UpdateWeb)
+		webhookDeliveryProvider := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.WebhookDelivery{}
+			},
+		}
+		a.GET("/projects/:project/webhooks/:webhook/deliveries", webhookDeliveryProvider.ReadAllWeb)
 		a.GET("/webhooks/events", apiv1.GetAvailableWebhookEvents)
+
+		chatIntegrationProvider := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.ChatIntegration{}
+			},
+		}
+		a.GET("/projects/:project/chat-integrations", chatIntegrationProvider.ReadAllWeb)
+		a.PUT("/projects/:project/chat-integrations", chatIntegrationProvider.CreateWeb)
+		a.DELETE("/projects/:project/chat-integrations/:chatintegration", chatIntegrationProvider.DeleteWeb)
+		a.GET("/user/settings/chat-integrations", chatIntegrationProvider.ReadAllWeb)
+		a.PUT("/user/settings/chat-integrations", chatIntegrationProvider.CreateWeb)
+		a.DELETE("/user/settings/chat-integrations/:chatintegration", chatIntegrationProvider.DeleteWeb)
 	}
 
 	// Reactions
@@ -635,6 +1008,66 @@ func registerAPIRoutes(a *echo.Group) {
 		},
 	}
 	a.POST("/projects/:project/views/:view/buckets/:bucket/tasks", taskBucketProvider.UpdateWeb)
+
+	// Task forms
+	if config.TaskFormsEnabled.GetBool() {
+		taskFormProvider := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.TaskForm{}
+			},
+		}
+		a.GET("/projects/:project/forms", taskFormProvider.ReadAllWeb)
+		a.GET("/projects/:project/forms/:form", taskFormProvider.ReadOneWeb)
+		a.PUT("/projects/:project/forms", taskFormProvider.CreateWeb)
+		a.DELETE("/projects/:project/forms/:form", taskFormProvider.DeleteWeb)
+		a.POST("/projects/:project/forms/:form", taskFormProvider.UpdateWeb)
+	}
+
+	// Email intake addresses
+	if config.EmailIntakeEnabled.GetBool() {
+		emailIntakeProvider := &handler.WebHandler{
+			EmptyStruct: func() handler.CObject {
+				return &models.ProjectEmailIntake{}
+			},
+		}
+		a.GET("/projects/:project/email-intake", emailIntakeProvider.ReadOneWeb)
+		a.PUT("/projects/:project/email-intake", emailIntakeProvider.CreateWeb)
+		a.DELETE("/projects/:project/email-intake", emailIntakeProvider.DeleteWeb)
+	}
+
+	// Task sla rules
+	taskSLARuleProvider := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskSLARule{}
+		},
+	}
+	a.GET("/projects/:project/slarules", taskSLARuleProvider.ReadAllWeb)
+	a.GET("/projects/:project/slarules/:slarule", taskSLARuleProvider.ReadOneWeb)
+	a.PUT("/projects/:project/slarules", taskSLARuleProvider.CreateWeb)
+	a.DELETE("/projects/:project/slarules/:slarule", taskSLARuleProvider.DeleteWeb)
+	a.POST("/projects/:project/slarules/:slarule", taskSLARuleProvider.UpdateWeb)
+
+	projectApproverHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.ProjectApprover{}
+		},
+	}
+	a.GET("/projects/:project/approvers", projectApproverHandler.ReadAllWeb)
+	a.PUT("/projects/:project/approvers", projectApproverHandler.CreateWeb)
+	a.DELETE("/projects/:project/approvers/:user", projectApproverHandler.DeleteWeb)
+
+	taskApproveHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskApproval{Approve: true}
+		},
+	}
+	taskRejectHandler := &handler.WebHandler{
+		EmptyStruct: func() handler.CObject {
+			return &models.TaskApproval{Approve: false}
+		},
+	}
+	a.POST("/tasks/:task/approve", taskApproveHandler.CreateWeb)
+	a.POST("/tasks/:task/reject", taskRejectHandler.CreateWeb)
 }
 
 func registerMigrations(m *echo.Group) {
@@ -685,6 +1118,27 @@ func registerMigrations(m *echo.Group) {
 	tickTickFileMigrator.RegisterRoutes(m)
 }
 
+func registerSCIMRoutes(sc *echo.Group) {
+	sc.Use(scim.TokenAuth)
+
+	sc.GET("/ServiceProviderConfig", scim.GetServiceProviderConfig)
+	sc.GET("/ResourceTypes", scim.GetResourceTypes)
+
+	sc.GET("/Users", scim.GetUsers)
+	sc.POST("/Users", scim.CreateUser)
+	sc.GET("/Users/:id", scim.GetUser)
+	sc.PUT("/Users/:id", scim.ReplaceUser)
+	sc.PATCH("/Users/:id", scim.PatchUser)
+	sc.DELETE("/Users/:id", scim.DeleteUser)
+
+	sc.GET("/Groups", scim.GetGroups)
+	sc.POST("/Groups", scim.CreateGroup)
+	sc.GET("/Groups/:id", scim.GetGroup)
+	sc.PUT("/Groups/:id", scim.ReplaceGroup)
+	sc.PATCH("/Groups/:id", scim.PatchGroup)
+	sc.DELETE("/Groups/:id", scim.DeleteGroup)
+}
+
 func registerCalDavRoutes(c *echo.Group) {
 
 	// Basic auth middleware
@@ -0,0 +1,523 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/labstack/echo/v4"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// The issuer of all teams created through scim. Only those teams can be managed through scim.
+const teamIssuer = "scim"
+
+// Member is a member of a group.
+type Member struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// Group is the scim representation of a Vikunja team.
+type Group struct {
+	Schemas     []string  `json:"schemas"`
+	ID          string    `json:"id,omitempty"`
+	ExternalID  string    `json:"externalId,omitempty"`
+	DisplayName string    `json:"displayName"`
+	Members     []*Member `json:"members"`
+	Meta        *Meta     `json:"meta,omitempty"`
+}
+
+var errInvalidMember = errors.New("invalid group member")
+
+func teamToSCIM(s *xorm.Session, team *models.Team) (*Group, error) {
+	members := []*user.User{}
+	err := s.
+		Table("users").
+		Select("users.*").
+		Join("INNER", "team_members", "team_members.user_id = users.id").
+		Where("team_members.team_id = ?", team.ID).
+		OrderBy("users.id asc").
+		Find(&members)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &Group{
+		Schemas:     []string{schemaGroup},
+		ID:          strconv.FormatInt(team.ID, 10),
+		ExternalID:  team.OidcID,
+		DisplayName: team.Name,
+		Members:     make([]*Member, 0, len(members)),
+		Meta: &Meta{
+			ResourceType: "Group",
+			Created:      team.Created,
+			LastModified: team.Updated,
+			Location:     location("Groups", team.ID),
+		},
+	}
+	for _, member := range members {
+		group.Members = append(group.Members, &Member{
+			Value:   strconv.FormatInt(member.ID, 10),
+			Display: member.Username,
+			Ref:     location("Users", member.ID),
+		})
+	}
+
+	return group, nil
+}
+
+func getTeam(s *xorm.Session, c echo.Context) (*models.Team, error) {
+	id, ok := parseID(c)
+	if !ok {
+		return nil, models.ErrTeamDoesNotExist{}
+	}
+
+	team := &models.Team{}
+	exists, err := s.
+		Where("id = ? AND issuer = ?", id, teamIssuer).
+		Get(team)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, models.ErrTeamDoesNotExist{TeamID: id}
+	}
+	return team, nil
+}
+
+func handleGroupError(c echo.Context, err error) error {
+	switch {
+	case models.IsErrTeamDoesNotExist(err):
+		return sendError(c, http.StatusNotFound, "", "The group does not exist.")
+	case errors.Is(err, errInvalidMember):
+		return sendError(c, http.StatusBadRequest, "invalidValue", "A group member does not exist.")
+	}
+	return sendInternalError(c, err)
+}
+
+// memberIDs returns the user ids of the members and makes sure they all exist.
+func memberIDs(s *xorm.Session, members []*Member) ([]int64, error) {
+	ids := make([]int64, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseInt(member.Value, 10, 64)
+		if err != nil {
+			return nil, errInvalidMember
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	count, err := s.In("id", ids).Count(&user.User{})
+	if err != nil {
+		return nil, err
+	}
+	if count != int64(len(ids)) {
+		return nil, errInvalidMember
+	}
+
+	return ids, nil
+}
+
+func addTeamMembers(s *xorm.Session, teamID int64, userIDs []int64) error {
+	for _, userID := range userIDs {
+		tm := &models.TeamMember{TeamID: teamID, UserID: userID}
+		exists, err := tm.MembershipExists(s)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		// Members of groups from the identity provider are never team admins, the team is managed through scim.
+		_, err = s.Insert(tm)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeTeamMembers(s *xorm.Session, teamID int64, userIDs []int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	_, err := s.
+		Where("team_id = ?", teamID).
+		In("user_id", userIDs).
+		Delete(&models.TeamMember{})
+	return err
+}
+
+func setTeamMembers(s *xorm.Session, teamID int64, userIDs []int64) error {
+	cond := builder.Eq{"team_id": teamID}
+	if len(userIDs) > 0 {
+		cond = builder.And(cond, builder.NotIn("user_id", userIDs))
+	}
+	_, err := s.Where(cond).Delete(&models.TeamMember{})
+	if err != nil {
+		return err
+	}
+	return addTeamMembers(s, teamID, userIDs)
+}
+
+// GetGroups lists all teams managed through scim.
+func GetGroups(c echo.Context) error {
+	cond := builder.Eq{"issuer": teamIssuer}
+	if filter := c.QueryParam("filter"); filter != "" {
+		attribute, value, err := parseFilter(filter)
+		if err != nil {
+			return sendError(c, http.StatusBadRequest, "invalidFilter", err.Error())
+		}
+
+		switch attribute {
+		case "id":
+			cond["id"] = value
+		case "displayname":
+			cond["name"] = value
+		case "externalid":
+			cond["oidc_id"] = value
+		default:
+			return sendError(c, http.StatusBadRequest, "invalidFilter", "Filtering by "+attribute+" is not supported.")
+		}
+	}
+
+	startIndex, count := pagination(c)
+
+	s := db.NewSession()
+	defer s.Close()
+
+	total, err := s.Where(cond).Count(&models.Team{})
+	if err != nil {
+		return sendInternalError(c, err)
+	}
+
+	teams := []*models.Team{}
+	err = s.
+		Where(cond).
+		OrderBy("id asc").
+		Limit(count, startIndex-1).
+		Find(&teams)
+	if err != nil {
+		return sendInternalError(c, err)
+	}
+
+	resources := make([]*Group, 0, len(teams))
+	for _, team := range teams {
+		group, err := teamToSCIM(s, team)
+		if err != nil {
+			return sendInternalError(c, err)
+		}
+		resources = append(resources, group)
+	}
+
+	return sendJSON(c, http.StatusOK, &ListResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// GetGroup returns a single team.
+func GetGroup(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	team, err := getTeam(s, c)
+	if err != nil {
+		return handleGroupError(c, err)
+	}
+
+	group, err := teamToSCIM(s, team)
+	if err != nil {
+		return sendInternalError(c, err)
+	}
+
+	return sendJSON(c, http.StatusOK, group)
+}
+
+// CreateGroup creates a new team.
+func CreateGroup(c echo.Context) error {
+	group := &Group{}
+	if err := bind(c, group); err != nil {
+		return err
+	}
+	if group.DisplayName == "" {
+		return sendError(c, http.StatusBadRequest, "invalidValue", "displayName is required.")
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	userIDs, err := memberIDs(s, group.Members)
+	if err != nil {
+		_ = s.Rollback()
+		return handleGroupError(c, err)
+	}
+
+	// Teams from scim have no creator, they belong to the identity provider
+	team := &models.Team{
+		Name:   group.DisplayName,
+		OidcID: group.ExternalID,
+		Issuer: teamIssuer,
+	}
+	_, err = s.Insert(team)
+	if err != nil {
+		_ = s.Rollback()
+		return sendInternalError(c, err)
+	}
+
+	err = addTeamMembers(s, team.ID, userIDs)
+	if err != nil {
+		_ = s.Rollback()
+		return sendInternalError(c, err)
+	}
+
+	err = events.Dispatch(&models.TeamCreatedEvent{Team: team})
+	if err != nil {
+		_ = s.Rollback()
+		return sendInternalError(c, err)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return sendInternalError(c, err)
+	}
+
+	log.Infof("Created team %d through scim", team.ID)
+
+	result, err := teamToSCIM(s, team)
+	if err != nil {
+		return sendInternalError(c, err)
+	}
+	return sendJSON(c, http.StatusCreated, result)
+}
+
+func updateTeam(s *xorm.Session, team *models.Team) error {
+	_, err := s.
+		Where("id = ?", team.ID).
+		Cols("name", "oidc_id").
+		Update(team)
+	return err
+}
+
+// ReplaceGroup replaces the name and all members of a team.
+func ReplaceGroup(c echo.Context) error {
+	group := &Group{}
+	if err := bind(c, group); err != nil {
+		return err
+	}
+	if group.DisplayName == "" {
+		return sendError(c, http.StatusBadRequest, "invalidValue", "displayName is required.")
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	team, err := getTeam(s, c)
+	if err != nil {
+		_ = s.Rollback()
+		return handleGroupError(c, err)
+	}
+
+	userIDs, err := memberIDs(s, group.Members)
+	if err != nil {
+		_ = s.Rollback()
+		return handleGroupError(c, err)
+	}
+
+	team.Name = group.DisplayName
+	team.OidcID = group.ExternalID
+	err = updateTeam(s, team)
+	if err != nil {
+		_ = s.Rollback()
+		return sendInternalError(c, err)
+	}
+
+	err = setTeamMembers(s, team.ID, userIDs)
+	if err != nil {
+		_ = s.Rollback()
+		return sendInternalError(c, err)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return sendInternalError(c, err)
+	}
+
+	result, err := teamToSCIM(s, team)
+	if err != nil {
+		return sendInternalError(c, err)
+	}
+	return sendJSON(c, http.StatusOK, result)
+}
+
+// Matches paths like members[value eq "42"] used to remove single members.
+var memberPathRegex = regexp.MustCompile(`^members\[\s*value\s+(?i:eq)\s+"([^"]*)"\s*\]$`)
+
+func applyGroupOperation(s *xorm.Session, team *models.Team, operation *PatchOperation) (scimType string, err error) {
+	op := strings.ToLower(operation.Op)
+	path := strings.TrimSpace(operation.Path)
+
+	if matches := memberPathRegex.FindStringSubmatch(path); matches != nil {
+		if op != "remove" {
+			return "invalidPath", errors.New("only remove is supported for single members")
+		}
+		userIDs, err := memberIDs(s, []*Member{{Value: matches[1]}})
+		if err != nil {
+			return "invalidValue", err
+		}
+		return "", removeTeamMembers(s, team.ID, userIDs)
+	}
+
+	switch strings.ToLower(path) {
+	case "members":
+		members := []*Member{}
+		if len(operation.Value) > 0 {
+			err = json.Unmarshal(operation.Value, &members)
+			if err != nil {
+				return "invalidValue", err
+			}
+		}
+		userIDs, err := memberIDs(s, members)
+		if err != nil {
+			return "invalidValue", err
+		}
+
+		switch op {
+		case "add":
+			return "", addTeamMembers(s, team.ID, userIDs)
+		case "remove":
+			if len(members) == 0 {
+				// Removing without a value removes all members
+				return "", setTeamMembers(s, team.ID, []int64{})
+			}
+			return "", removeTeamMembers(s, team.ID, userIDs)
+		case "replace":
+			return "", setTeamMembers(s, team.ID, userIDs)
+		}
+	case "displayname", "externalid":
+		var value string
+		err = json.Unmarshal(operation.Value, &value)
+		if err != nil {
+			return "invalidValue", err
+		}
+		if strings.EqualFold(path, "displayName") {
+			team.Name = value
+		} else {
+			team.OidcID = value
+		}
+		return "", updateTeam(s, team)
+	case "":
+		// The value is an object with all attributes to replace
+		values := map[string]json.RawMessage{}
+		err = json.Unmarshal(operation.Value, &values)
+		if err != nil {
+			return "invalidSyntax", err
+		}
+		for attribute, value := range values {
+			scimType, err = applyGroupOperation(s, team, &PatchOperation{Op: operation.Op, Path: attribute, Value: value})
+			if err != nil {
+				return scimType, err
+			}
+		}
+		return "", nil
+	}
+
+	return "invalidPath", errors.New("unsupported patch operation " + operation.Op + " " + path)
+}
+
+// PatchGroup changes the name or members of a team.
+func PatchGroup(c echo.Context) error {
+	patch := &PatchRequest{}
+	if err := bind(c, patch); err != nil {
+		return err
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	team, err := getTeam(s, c)
+	if err != nil {
+		_ = s.Rollback()
+		return handleGroupError(c, err)
+	}
+
+	for _, operation := range patch.Operations {
+		scimType, err := applyGroupOperation(s, team, operation)
+		if err != nil {
+			_ = s.Rollback()
+			if scimType != "" {
+				return sendError(c, http.StatusBadRequest, scimType, err.Error())
+			}
+			return sendInternalError(c, err)
+		}
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return sendInternalError(c, err)
+	}
+
+	result, err := teamToSCIM(s, team)
+	if err != nil {
+		return sendInternalError(c, err)
+	}
+	return sendJSON(c, http.StatusOK, result)
+}
+
+// DeleteGroup deletes a team. Projects shared with the team stay with their owners.
+func DeleteGroup(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	team, err := getTeam(s, c)
+	if err != nil {
+		_ = s.Rollback()
+		return handleGroupError(c, err)
+	}
+
+	err = team.Delete(s, nil)
+	if err != nil {
+		_ = s.Rollback()
+		return sendInternalError(c, err)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return sendInternalError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,236 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package scim implements a SCIM 2.0 (RFC 7643 and RFC 7644) server so identity providers can provision users and
+// groups. Groups are mapped to teams.
+package scim
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	schemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	schemaGroup        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	schemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	schemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	schemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+	schemaConfig       = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	schemaResourceType = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+
+	mimeSCIM = "application/scim+json"
+
+	defaultPageSize = 100
+)
+
+// Meta holds the metadata of a resource.
+type Meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+// ListResponse is returned for all list requests.
+type ListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int64       `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+// Error is the scim representation of an error.
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+// PatchOperation is a single operation of a patch request.
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// PatchRequest contains all operations of a patch request.
+type PatchRequest struct {
+	Schemas    []string          `json:"schemas"`
+	Operations []*PatchOperation `json:"Operations"`
+}
+
+// TokenAuth only lets requests with the configured scim token through.
+func TokenAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := config.SCIMToken.GetString()
+		provided := strings.TrimPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			return sendError(c, http.StatusUnauthorized, "", "Invalid scim token.")
+		}
+		return next(c)
+	}
+}
+
+func sendJSON(c echo.Context, status int, value interface{}) error {
+	c.Response().Header().Set(echo.HeaderContentType, mimeSCIM)
+	c.Response().WriteHeader(status)
+	return json.NewEncoder(c.Response()).Encode(value)
+}
+
+func sendError(c echo.Context, status int, scimType string, detail string) error {
+	return sendJSON(c, status, &Error{
+		Schemas:  []string{schemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+func sendInternalError(c echo.Context, err error) error {
+	log.Errorf("Error handling scim request %s %s: %s", c.Request().Method, c.Request().URL.Path, err)
+	return sendError(c, http.StatusInternalServerError, "", "Internal server error.")
+}
+
+func bind(c echo.Context, value interface{}) error {
+	// echo only binds application/json, scim clients send application/scim+json
+	err := json.NewDecoder(c.Request().Body).Decode(value)
+	if err != nil {
+		log.Debugf("Invalid scim request body: %s", err)
+		return sendError(c, http.StatusBadRequest, "invalidSyntax", "The request body is not valid json.")
+	}
+	return nil
+}
+
+func location(resource string, id int64) string {
+	return strings.TrimSuffix(config.ServicePublicURL.GetString(), "/") + "/api/v1/scim/v2/" + resource + "/" + strconv.FormatInt(id, 10)
+}
+
+func parseID(c echo.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// pagination returns the start index (starting at 1) and page size of a list request.
+func pagination(c echo.Context) (startIndex int, count int) {
+	startIndex, err := strconv.Atoi(c.QueryParam("startIndex"))
+	if err != nil || startIndex < 1 {
+		startIndex = 1
+	}
+	count, err = strconv.Atoi(c.QueryParam("count"))
+	if err != nil || count < 0 {
+		count = defaultPageSize
+	}
+	if maxItems := config.ServiceMaxItemsPerPage.GetInt(); maxItems > 0 && count > maxItems {
+		count = maxItems
+	}
+	return
+}
+
+var filterRegex = regexp.MustCompile(`^\s*([A-Za-z.]+)\s+(?i:eq)\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// parseFilter parses the only kind of filter identity providers use to look up resources: `attribute eq "value"`.
+func parseFilter(filter string) (attribute string, value string, err error) {
+	matches := filterRegex.FindStringSubmatch(filter)
+	if matches == nil {
+		return "", "", fmt.Errorf("unsupported filter %q", filter)
+	}
+
+	value, err = strconv.Unquote(`"` + matches[2] + `"`)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid filter value in %q", filter)
+	}
+
+	return strings.ToLower(matches[1]), value, nil
+}
+
+// parseBool accepts both real booleans and strings, because some identity providers send "True" and "False".
+func parseBool(raw json.RawMessage) (bool, error) {
+	var value bool
+	err := json.Unmarshal(raw, &value)
+	if err == nil {
+		return value, nil
+	}
+
+	var str string
+	err = json.Unmarshal(raw, &str)
+	if err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(strings.ToLower(str))
+}
+
+// GetServiceProviderConfig returns what parts of scim Vikunja supports.
+func GetServiceProviderConfig(c echo.Context) error {
+	return sendJSON(c, http.StatusOK, map[string]interface{}{
+		"schemas":        []string{schemaConfig},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": config.ServiceMaxItemsPerPage.GetInt()},
+		"changePassword": map[string]bool{"supported": false},
+		"sort":           map[string]bool{"supported": false},
+		"etag":           map[string]bool{"supported": false},
+		"authenticationSchemes": []map[string]interface{}{
+			{
+				"type":        "oauthbearertoken",
+				"name":        "Bearer Token",
+				"description": "Authentication with the token configured in scim.token",
+				"primary":     true,
+			},
+		},
+	})
+}
+
+// GetResourceTypes returns the resources Vikunja supports.
+func GetResourceTypes(c echo.Context) error {
+	resourceTypes := []map[string]interface{}{
+		{
+			"schemas":  []string{schemaResourceType},
+			"id":       "User",
+			"name":     "User",
+			"endpoint": "/Users",
+			"schema":   schemaUser,
+		},
+		{
+			"schemas":  []string{schemaResourceType},
+			"id":       "Group",
+			"name":     "Group",
+			"endpoint": "/Groups",
+			"schema":   schemaGroup,
+		},
+	}
+
+	return sendJSON(c, http.StatusOK, &ListResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: int64(len(resourceTypes)),
+		StartIndex:   1,
+		ItemsPerPage: len(resourceTypes),
+		Resources:    resourceTypes,
+	})
+}
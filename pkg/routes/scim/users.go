@@ -0,0 +1,524 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
+	"code.vikunja.io/api/pkg/user"
+	"code.vikunja.io/api/pkg/utils"
+
+	"github.com/labstack/echo/v4"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// Name is the name of a user.
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// Email is an email address of a user.
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// User is the scim representation of a Vikunja user.
+type User struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	UserName    string   `json:"userName"`
+	Name        *Name    `json:"name,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Emails      []*Email `json:"emails,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+	// Only used when creating users, never returned.
+	Password string `json:"password,omitempty"`
+	Meta     *Meta  `json:"meta,omitempty"`
+}
+
+// issuer returns the issuer of users created through scim.
+func issuer() string {
+	if config.SCIMIssuer.GetString() == "" {
+		return user.IssuerLocal
+	}
+	return config.SCIMIssuer.GetString()
+}
+
+func userToSCIM(u *user.User) *User {
+	active := u.Status != user.StatusDisabled
+	su := &User{
+		Schemas:     []string{schemaUser},
+		ID:          strconv.FormatInt(u.ID, 10),
+		UserName:    u.Username,
+		DisplayName: u.Name,
+		Active:      &active,
+		Meta: &Meta{
+			ResourceType: "User",
+			Created:      u.Created,
+			LastModified: u.Updated,
+			Location:     location("Users", u.ID),
+		},
+	}
+	if u.Name != "" {
+		su.Name = &Name{Formatted: u.Name}
+	}
+	if u.Email != "" {
+		su.Emails = []*Email{{Value: u.Email, Type: "work", Primary: true}}
+	}
+	if u.Issuer != user.IssuerLocal && u.Issuer == issuer() {
+		su.ExternalID = u.Subject
+	}
+	return su
+}
+
+func (su *User) email() string {
+	for _, email := range su.Emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if len(su.Emails) > 0 {
+		return su.Emails[0].Value
+	}
+	return ""
+}
+
+func (su *User) displayName() string {
+	if su.DisplayName != "" {
+		return su.DisplayName
+	}
+	if su.Name == nil {
+		return ""
+	}
+	if su.Name.Formatted != "" {
+		return su.Name.Formatted
+	}
+	return strings.TrimSpace(su.Name.GivenName + " " + su.Name.FamilyName)
+}
+
+// handleUserError turns errors from the user package into scim errors.
+func handleUserError(c echo.Context, err error) error {
+	// Creating users returns these errors as values, updating them as pointers
+	_, usernameExists := err.(*user.ErrUsernameExists)
+	_, emailExists := err.(*user.ErrUserEmailExists)
+
+	switch {
+	case user.IsErrUserDoesNotExist(err):
+		return sendError(c, http.StatusNotFound, "", "The user does not exist.")
+	case user.IsErrUsernameExists(err) || usernameExists:
+		return sendError(c, http.StatusConflict, "uniqueness", "A user with this username already exists.")
+	case user.IsErrUserEmailExists(err) || emailExists:
+		return sendError(c, http.StatusConflict, "uniqueness", "A user with this email address already exists.")
+	case user.IsErrNoUsernamePassword(err):
+		return sendError(c, http.StatusBadRequest, "invalidValue", "userName and an email address are required.")
+	case user.IsErrUsernameMustNotContainSpaces(err):
+		return sendError(c, http.StatusBadRequest, "invalidValue", "The userName must not contain spaces.")
+	}
+	return sendInternalError(c, err)
+}
+
+func getUser(s *xorm.Session, c echo.Context) (*user.User, error) {
+	id, ok := parseID(c)
+	if !ok {
+		return nil, user.ErrUserDoesNotExist{}
+	}
+	return user.GetUserWithEmail(s, &user.User{ID: id})
+}
+
+// GetUsers lists all users, optionally filtered.
+func GetUsers(c echo.Context) error {
+	cond := builder.NewCond()
+	if filter := c.QueryParam("filter"); filter != "" {
+		attribute, value, err := parseFilter(filter)
+		if err != nil {
+			return sendError(c, http.StatusBadRequest, "invalidFilter", err.Error())
+		}
+
+		switch attribute {
+		case "id":
+			cond = builder.Eq{"id": value}
+		case "username":
+			cond = builder.Eq{"username": value}
+		case "externalid":
+			cond = builder.Eq{"issuer": issuer(), "subject": value}
+		case "emails", "emails.value":
+			cond = builder.Eq{"email": value}
+		default:
+			return sendError(c, http.StatusBadRequest, "invalidFilter", "Filtering by "+attribute+" is not supported.")
+		}
+	}
+
+	startIndex, count := pagination(c)
+
+	s := db.NewSession()
+	defer s.Close()
+
+	total, err := s.Where(cond).Count(&user.User{})
+	if err != nil {
+		return sendInternalError(c, err)
+	}
+
+	users := []*user.User{}
+	err = s.
+		Where(cond).
+		OrderBy("id asc").
+		Limit(count, startIndex-1).
+		Find(&users)
+	if err != nil {
+		return sendInternalError(c, err)
+	}
+
+	resources := make([]*User, 0, len(users))
+	for _, u := range users {
+		resources = append(resources, userToSCIM(u))
+	}
+
+	return sendJSON(c, http.StatusOK, &ListResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// GetUser returns a single user.
+func GetUser(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	u, err := getUser(s, c)
+	if err != nil {
+		return handleUserError(c, err)
+	}
+
+	return sendJSON(c, http.StatusOK, userToSCIM(u))
+}
+
+// CreateUser provisions a new user.
+func CreateUser(c echo.Context) error {
+	su := &User{}
+	if err := bind(c, su); err != nil {
+		return err
+	}
+
+	newUser := &user.User{
+		Username: su.UserName,
+		Email:    su.email(),
+		Name:     su.displayName(),
+		Issuer:   issuer(),
+	}
+	if newUser.Issuer == user.IssuerLocal {
+		// Users without a password from their identity provider can set one with the password reset
+		newUser.Password = su.Password
+		if newUser.Password == "" {
+			password, err := utils.CryptoRandomString(32)
+			if err != nil {
+				return sendInternalError(c, err)
+			}
+			newUser.Password = password
+		}
+	} else {
+		newUser.Subject = su.ExternalID
+		if newUser.Subject == "" {
+			newUser.Subject = su.UserName
+		}
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	u, err := user.CreateUser(s, newUser)
+	if err != nil {
+		_ = s.Rollback()
+		return handleUserError(c, err)
+	}
+
+	err = models.CreateNewProjectForUser(s, u)
+	if err != nil {
+		_ = s.Rollback()
+		return sendInternalError(c, err)
+	}
+
+	if su.Active != nil && !*su.Active {
+		err = user.SetUserStatus(s, u, user.StatusDisabled)
+		if err != nil {
+			_ = s.Rollback()
+			return sendInternalError(c, err)
+		}
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return sendInternalError(c, err)
+	}
+
+	u, err = user.GetUserWithEmail(s, &user.User{ID: u.ID})
+	if err != nil {
+		return sendInternalError(c, err)
+	}
+
+	log.Infof("Created user %d through scim", u.ID)
+	return sendJSON(c, http.StatusCreated, userToSCIM(u))
+}
+
+// setActive deactivates or reactivates a user. Deactivating does not touch any projects of the user so they stay
+// available to everyone they are shared with and the user finds everything in place once they are reactivated.
+// It does log the user out everywhere and removes their api tokens, since those are not checked against the status.
+func setActive(s *xorm.Session, u *user.User, active bool) error {
+	if active && u.Status == user.StatusDisabled {
+		log.Infof("Reactivating user %d through scim", u.ID)
+		u.Status = user.StatusActive
+		return user.SetUserStatus(s, u, user.StatusActive)
+	}
+	if !active && u.Status != user.StatusDisabled {
+		log.Infof("Deactivating user %d through scim", u.ID)
+		u.Status = user.StatusDisabled
+		err := user.SetUserStatus(s, u, user.StatusDisabled)
+		if err != nil {
+			return err
+		}
+		err = user.RevokeAllSessionsExcept(s, u, "")
+		if err != nil {
+			return err
+		}
+		return models.DeleteAllAPITokensOfUser(s, u.ID)
+	}
+	return nil
+}
+
+func updateUser(s *xorm.Session, u *user.User, su *User) (*user.User, error) {
+	if su.UserName != "" {
+		u.Username = su.UserName
+	}
+	if email := su.email(); email != "" {
+		u.Email = email
+	}
+	if name := su.displayName(); name != "" {
+		u.Name = name
+	}
+	if su.ExternalID != "" && u.Issuer != user.IssuerLocal && u.Issuer == issuer() {
+		u.Subject = su.ExternalID
+		_, err := s.Where("id = ?", u.ID).Cols("subject").Update(&user.User{Subject: su.ExternalID})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := user.UpdateUser(s, u, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if su.Active != nil {
+		err = setActive(s, updated, *su.Active)
+	}
+	return updated, err
+}
+
+// ReplaceUser replaces the attributes of a user.
+func ReplaceUser(c echo.Context) error {
+	su := &User{}
+	if err := bind(c, su); err != nil {
+		return err
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	u, err := getUser(s, c)
+	if err != nil {
+		_ = s.Rollback()
+		return handleUserError(c, err)
+	}
+
+	u, err = updateUser(s, u, su)
+	if err != nil {
+		_ = s.Rollback()
+		return handleUserError(c, err)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return sendInternalError(c, err)
+	}
+
+	u, err = user.GetUserWithEmail(s, &user.User{ID: u.ID})
+	if err != nil {
+		return sendInternalError(c, err)
+	}
+
+	return sendJSON(c, http.StatusOK, userToSCIM(u))
+}
+
+// applyUserPatch applies the operations of a patch request to the scim representation of a user.
+func applyUserPatch(su *User, operations []*PatchOperation) (scimType string, err error) {
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		if op != "add" && op != "replace" {
+			return "invalidValue", errors.New("only add and replace operations are supported for users")
+		}
+
+		if operation.Path == "" {
+			// The value is an object with all attributes to replace
+			values := map[string]json.RawMessage{}
+			err = json.Unmarshal(operation.Value, &values)
+			if err != nil {
+				return "invalidSyntax", err
+			}
+			for path, value := range values {
+				scimType, err = applyUserAttribute(su, path, value)
+				if err != nil {
+					return scimType, err
+				}
+			}
+			continue
+		}
+
+		scimType, err = applyUserAttribute(su, operation.Path, operation.Value)
+		if err != nil {
+			return scimType, err
+		}
+	}
+
+	return "", nil
+}
+
+func applyUserAttribute(su *User, path string, value json.RawMessage) (scimType string, err error) {
+	switch strings.ToLower(path) {
+	case "active":
+		active, err := parseBool(value)
+		if err != nil {
+			return "invalidValue", err
+		}
+		su.Active = &active
+		return "", nil
+	case "username":
+		err = json.Unmarshal(value, &su.UserName)
+	case "displayname":
+		err = json.Unmarshal(value, &su.DisplayName)
+	case "externalid":
+		err = json.Unmarshal(value, &su.ExternalID)
+	case "name":
+		su.Name = &Name{}
+		err = json.Unmarshal(value, su.Name)
+	case "name.formatted":
+		su.Name = &Name{}
+		err = json.Unmarshal(value, &su.Name.Formatted)
+	case "emails":
+		err = json.Unmarshal(value, &su.Emails)
+	case `emails[type eq "work"].value`, "emails.value":
+		var email string
+		err = json.Unmarshal(value, &email)
+		su.Emails = []*Email{{Value: email, Primary: true}}
+	default:
+		// Attributes Vikunja does not know about are ignored, as the spec allows
+		log.Debugf("Ignoring unknown scim user attribute %s", path)
+		return "", nil
+	}
+
+	if err != nil {
+		return "invalidValue", err
+	}
+	return "", nil
+}
+
+// PatchUser changes single attributes of a user. This is what identity providers use to deactivate users.
+func PatchUser(c echo.Context) error {
+	patch := &PatchRequest{}
+	if err := bind(c, patch); err != nil {
+		return err
+	}
+
+	su := &User{}
+	scimType, err := applyUserPatch(su, patch.Operations)
+	if err != nil {
+		return sendError(c, http.StatusBadRequest, scimType, err.Error())
+	}
+
+	s := db.NewSession()
+	defer s.Close()
+
+	u, err := getUser(s, c)
+	if err != nil {
+		_ = s.Rollback()
+		return handleUserError(c, err)
+	}
+
+	u, err = updateUser(s, u, su)
+	if err != nil {
+		_ = s.Rollback()
+		return handleUserError(c, err)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return sendInternalError(c, err)
+	}
+
+	u, err = user.GetUserWithEmail(s, &user.User{ID: u.ID})
+	if err != nil {
+		return sendInternalError(c, err)
+	}
+
+	return sendJSON(c, http.StatusOK, userToSCIM(u))
+}
+
+// DeleteUser deprovisions a user. Unless deleting users is enabled, the user is only deactivated to not lose any
+// of their projects.
+func DeleteUser(c echo.Context) error {
+	s := db.NewSession()
+	defer s.Close()
+
+	u, err := getUser(s, c)
+	if err != nil {
+		_ = s.Rollback()
+		return handleUserError(c, err)
+	}
+
+	if config.SCIMDeleteUsers.GetBool() {
+		log.Infof("Deleting user %d through scim", u.ID)
+		err = models.DeleteUser(s, u)
+	} else {
+		err = setActive(s, u, false)
+	}
+	if err != nil {
+		_ = s.Rollback()
+		return sendInternalError(c, err)
+	}
+
+	if err := s.Commit(); err != nil {
+		_ = s.Rollback()
+		return sendInternalError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,58 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package routes
+
+import (
+	"net/http"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/modules/auth"
+	"code.vikunja.io/api/pkg/user"
+
+	"github.com/labstack/echo/v4"
+)
+
+// checkSessionNotRevoked rejects tokens of sessions on the token denylist and keeps track of when and from where
+// a session was last used.
+func checkSessionNotRevoked(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		sessionID := auth.GetSessionIDFromClaims(c)
+		if sessionID == "" {
+			return next(c)
+		}
+
+		s := db.NewSession()
+		defer s.Close()
+
+		revoked, err := user.IsSessionRevoked(s, sessionID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+		}
+		if revoked {
+			log.Debugf("[auth] Tried using a token of revoked session %s", sessionID)
+			return echo.NewHTTPError(http.StatusUnauthorized, "missing, malformed, expired or otherwise invalid token provided")
+		}
+
+		err = user.UpdateSessionActivity(s, sessionID, c.RealIP())
+		if err != nil {
+			log.Errorf("[auth] Could not update the last activity of session %s: %s", sessionID, err)
+		}
+
+		return next(c)
+	}
+}
@@ -0,0 +1,64 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package routes
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/models"
+	auth2 "code.vikunja.io/api/pkg/modules/auth"
+	"github.com/labstack/echo/v4"
+)
+
+// MarkTaskAsSeen remembers that the user opened a task once it was returned to them successfully.
+// This only happens when a user actually looks at a task, not every time a task is loaded internally.
+func MarkTaskAsSeen(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		err := next(c)
+		if err != nil || c.Response().Status != http.StatusOK {
+			return err
+		}
+
+		taskID, err := strconv.ParseInt(c.Param("projecttask"), 10, 64)
+		if err != nil {
+			return nil
+		}
+
+		auth, err := auth2.GetAuthFromClaims(c)
+		if err != nil {
+			return nil
+		}
+
+		s := db.NewSession()
+		defer s.Close()
+
+		err = models.MarkTaskAsSeen(s, auth, taskID)
+		if err != nil {
+			_ = s.Rollback()
+			log.Errorf("Could not mark task %d as seen: %s", taskID, err)
+			return nil
+		}
+
+		if err := s.Commit(); err != nil {
+			log.Errorf("Could not mark task %d as seen: %s", taskID, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"strings"
+
+	"code.vikunja.io/api/pkg/db"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// SetUserAdmin grants or revokes the instance admin privileges of a user
+func SetUserAdmin(s *xorm.Session, user *User, isAdmin bool) (err error) {
+	_, err = s.Where("id = ?", user.ID).
+		Cols("is_admin").
+		Update(&User{IsAdmin: isAdmin})
+	return
+}
+
+// CheckUserIsAdmin returns an error if the user is not an instance admin
+func CheckUserIsAdmin(s *xorm.Session, u *User) error {
+	fullUser, err := GetUserByID(s, u.ID)
+	if err != nil {
+		return err
+	}
+	if !fullUser.IsAdmin {
+		return &ErrUserIsNotAdmin{UserID: u.ID}
+	}
+	return nil
+}
+
+// ListUsersForAdmin returns all users of the instance, paginated. If a search string is provided, only users
+// whose username, name or email contain it are returned. Other than ListUsers, email addresses are always included.
+func ListUsersForAdmin(s *xorm.Session, search string, page, perPage int) (users []*User, totalCount int64, err error) {
+	var cond builder.Cond = builder.NewCond()
+
+	search = strings.TrimSpace(strings.ReplaceAll(search, "%", ""))
+	if search != "" {
+		cond = builder.Or(
+			db.ILIKE("username", search),
+			db.ILIKE("name", search),
+			db.ILIKE("email", search),
+		)
+	}
+
+	users = []*User{}
+	query := s.Where(cond).OrderBy("id asc")
+	if page > 0 && perPage > 0 {
+		query = query.Limit(perPage, (page-1)*perPage)
+	}
+	err = query.Find(&users)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	totalCount, err = s.Where(cond).Count(&User{})
+	return users, totalCount, err
+}
@@ -21,6 +21,11 @@ func GetTables() []interface{} {
 	return []interface{}{
 		&User{},
 		&TOTP{},
+		&TOTPRecoveryCode{},
 		&Token{},
+		&NotificationPreference{},
+		&WebAuthnCredential{},
+		&Session{},
+		&RevokedSession{},
 	}
 }
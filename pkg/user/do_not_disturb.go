@@ -0,0 +1,120 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/notifications"
+
+	"xorm.io/xorm"
+)
+
+// quietHoursEnd returns when the quiet hours of the user which include now end, or the zero time if now is not in
+// the quiet hours. Quiet hours may span midnight, for example from 22:00 until 07:00.
+func (u *User) quietHoursEnd(now time.Time) (end time.Time, err error) {
+	if u.QuietHoursStart == "" || u.QuietHoursEnd == "" || u.QuietHoursStart == u.QuietHoursEnd {
+		return time.Time{}, nil
+	}
+
+	tz := config.GetTimeZone()
+	if u.Timezone != "" {
+		tz, err = time.LoadLocation(u.Timezone)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	startTime, err := time.Parse("15:04", u.QuietHoursStart)
+	if err != nil {
+		return time.Time{}, err
+	}
+	endTime, err := time.Parse("15:04", u.QuietHoursEnd)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	local := now.In(tz)
+	start := time.Date(local.Year(), local.Month(), local.Day(), startTime.Hour(), startTime.Minute(), 0, 0, tz)
+	end = time.Date(local.Year(), local.Month(), local.Day(), endTime.Hour(), endTime.Minute(), 0, 0, tz)
+
+	if start.Before(end) {
+		if !local.Before(start) && local.Before(end) {
+			return end, nil
+		}
+		return time.Time{}, nil
+	}
+
+	// Quiet hours over midnight
+	if !local.Before(start) {
+		return end.AddDate(0, 0, 1), nil
+	}
+	if local.Before(end) {
+		return end, nil
+	}
+	return time.Time{}, nil
+}
+
+// DoNotDisturbUntil implements notifications.NotifiableWithQuietHours. Users are not disturbed during their quiet
+// hours and while they snoozed all notifications.
+func (u *User) DoNotDisturbUntil(now time.Time) (until time.Time, err error) {
+	s := db.NewSession()
+	defer s.Close()
+	user, err := getUser(s, &User{ID: u.ID}, false)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	until, err = user.quietHoursEnd(now)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if user.NotificationsSnoozedUntil.After(now) && user.NotificationsSnoozedUntil.After(until) {
+		until = user.NotificationsSnoozedUntil
+	}
+	return until, nil
+}
+
+// GetNotifiableByID loads a user to deliver the notifications which were deferred during their quiet hours.
+// Returns nil if the user does not exist anymore.
+func GetNotifiableByID(id int64) (notifications.Notifiable, error) {
+	s := db.NewSession()
+	defer s.Close()
+
+	u, err := GetUserByID(s, id)
+	if IsErrUserDoesNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// SnoozeNotifications defers the delivery of all notifications of a user until the given time.
+// Pass the zero time to end the snooze.
+func SnoozeNotifications(s *xorm.Session, u *User, until time.Time) (err error) {
+	u.NotificationsSnoozedUntil = until
+	_, err = s.
+		Where("id = ?", u.ID).
+		Cols("notifications_snoozed_until").
+		Update(u)
+	return
+}
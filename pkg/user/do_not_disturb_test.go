@@ -0,0 +1,69 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser_quietHoursEnd(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 3, 10, hour, minute, 0, 0, time.UTC)
+	}
+
+	t.Run("no quiet hours", func(t *testing.T) {
+		u := &User{Timezone: "UTC"}
+		end, err := u.quietHoursEnd(at(12, 0))
+		require.NoError(t, err)
+		assert.True(t, end.IsZero())
+	})
+	t.Run("during quiet hours", func(t *testing.T) {
+		u := &User{Timezone: "UTC", QuietHoursStart: "12:00", QuietHoursEnd: "14:00"}
+		end, err := u.quietHoursEnd(at(12, 0))
+		require.NoError(t, err)
+		assert.Equal(t, at(14, 0), end)
+	})
+	t.Run("after quiet hours", func(t *testing.T) {
+		u := &User{Timezone: "UTC", QuietHoursStart: "12:00", QuietHoursEnd: "14:00"}
+		end, err := u.quietHoursEnd(at(14, 0))
+		require.NoError(t, err)
+		assert.True(t, end.IsZero())
+	})
+	t.Run("over midnight before midnight", func(t *testing.T) {
+		u := &User{Timezone: "UTC", QuietHoursStart: "22:00", QuietHoursEnd: "07:00"}
+		end, err := u.quietHoursEnd(at(23, 30))
+		require.NoError(t, err)
+		assert.Equal(t, at(7, 0).AddDate(0, 0, 1), end)
+	})
+	t.Run("over midnight after midnight", func(t *testing.T) {
+		u := &User{Timezone: "UTC", QuietHoursStart: "22:00", QuietHoursEnd: "07:00"}
+		end, err := u.quietHoursEnd(at(6, 59))
+		require.NoError(t, err)
+		assert.Equal(t, at(7, 0), end)
+	})
+	t.Run("in the time zone of the user", func(t *testing.T) {
+		u := &User{Timezone: "Europe/Berlin", QuietHoursStart: "12:00", QuietHoursEnd: "14:00"}
+		// 11:30 UTC is 12:30 in Berlin
+		end, err := u.quietHoursEnd(at(11, 30))
+		require.NoError(t, err)
+		assert.True(t, at(13, 0).Equal(end))
+	})
+}
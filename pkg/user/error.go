@@ -532,3 +532,323 @@ func (err *ErrUsernameMustNotContainSpaces) HTTPError() web.HTTPError {
 		Message:  "The username must not contain spaces.",
 	}
 }
+
+// ErrInvalidNotificationChannel represents an error where a notification preference uses a channel which does not exist.
+type ErrInvalidNotificationChannel struct {
+	Channel string
+}
+
+// IsErrInvalidNotificationChannel checks if an error is a ErrInvalidNotificationChannel.
+func IsErrInvalidNotificationChannel(err error) bool {
+	_, ok := err.(*ErrInvalidNotificationChannel)
+	return ok
+}
+
+func (err *ErrInvalidNotificationChannel) Error() string {
+	return "invalid notification channel " + err.Channel
+}
+
+// ErrCodeInvalidNotificationChannel holds the unique world-error code of this error
+const ErrCodeInvalidNotificationChannel = 1023
+
+// HTTPError holds the http error description
+func (err *ErrInvalidNotificationChannel) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeInvalidNotificationChannel,
+		Message:  "The notification channel " + err.Channel + " does not exist. Valid channels are email, in_app, push and webhook.",
+	}
+}
+
+// ErrInvalidWebAuthnResponse represents an error where a response of a webauthn authenticator could not be verified.
+type ErrInvalidWebAuthnResponse struct {
+	Reason string
+}
+
+// IsErrInvalidWebAuthnResponse checks if an error is a ErrInvalidWebAuthnResponse.
+func IsErrInvalidWebAuthnResponse(err error) bool {
+	_, ok := err.(*ErrInvalidWebAuthnResponse)
+	return ok
+}
+
+func (err *ErrInvalidWebAuthnResponse) Error() string {
+	return "invalid webauthn response: " + err.Reason
+}
+
+// ErrCodeInvalidWebAuthnResponse holds the unique world-error code of this error
+const ErrCodeInvalidWebAuthnResponse = 1024
+
+// HTTPError holds the http error description
+func (err *ErrInvalidWebAuthnResponse) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeInvalidWebAuthnResponse,
+		Message:  "The passkey or security key could not be verified.",
+	}
+}
+
+// ErrWebAuthnCredentialDoesNotExist represents an error where a webauthn credential does not exist.
+type ErrWebAuthnCredentialDoesNotExist struct {
+	ID int64
+}
+
+// IsErrWebAuthnCredentialDoesNotExist checks if an error is a ErrWebAuthnCredentialDoesNotExist.
+func IsErrWebAuthnCredentialDoesNotExist(err error) bool {
+	_, ok := err.(*ErrWebAuthnCredentialDoesNotExist)
+	return ok
+}
+
+func (err *ErrWebAuthnCredentialDoesNotExist) Error() string {
+	return fmt.Sprintf("webauthn credential does not exist [ID: %d]", err.ID)
+}
+
+// ErrCodeWebAuthnCredentialDoesNotExist holds the unique world-error code of this error
+const ErrCodeWebAuthnCredentialDoesNotExist = 1025
+
+// HTTPError holds the http error description
+func (err *ErrWebAuthnCredentialDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeWebAuthnCredentialDoesNotExist,
+		Message:  "This passkey does not exist.",
+	}
+}
+
+// ErrWebAuthnRequired represents an error where a user with a passkey or security key tried to log in without it.
+type ErrWebAuthnRequired struct{}
+
+// IsErrWebAuthnRequired checks if an error is a ErrWebAuthnRequired.
+func IsErrWebAuthnRequired(err error) bool {
+	_, ok := err.(*ErrWebAuthnRequired)
+	return ok
+}
+
+func (err *ErrWebAuthnRequired) Error() string {
+	return "webauthn is required to log in"
+}
+
+// ErrCodeWebAuthnRequired holds the unique world-error code of this error
+const ErrCodeWebAuthnRequired = 1034
+
+// HTTPError holds the http error description
+func (err *ErrWebAuthnRequired) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeWebAuthnRequired,
+		Message:  "Please use your passkey or security key to log in.",
+	}
+}
+
+// ErrNoLDAPEmailProvided represents an error where the ldap entry of a user does not contain an email address.
+type ErrNoLDAPEmailProvided struct {
+	Username string
+}
+
+// IsErrNoLDAPEmailProvided checks if an error is a ErrNoLDAPEmailProvided.
+func IsErrNoLDAPEmailProvided(err error) bool {
+	_, ok := err.(*ErrNoLDAPEmailProvided)
+	return ok
+}
+
+func (err *ErrNoLDAPEmailProvided) Error() string {
+	return fmt.Sprintf("No email provided in ldap [Username: %s]", err.Username)
+}
+
+// ErrCodeNoLDAPEmailProvided holds the unique world-error code of this error
+const ErrCodeNoLDAPEmailProvided = 1026
+
+// HTTPError holds the http error description
+func (err *ErrNoLDAPEmailProvided) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeNoLDAPEmailProvided,
+		Message:  "No email address available. Please ask your administrator to add an email address to your ldap account.",
+	}
+}
+
+// ErrUserIsNotAdmin represents an error where a user tries to use an endpoint only instance admins can use.
+type ErrUserIsNotAdmin struct {
+	UserID int64
+}
+
+// IsErrUserIsNotAdmin checks if an error is a ErrUserIsNotAdmin.
+func IsErrUserIsNotAdmin(err error) bool {
+	_, ok := err.(*ErrUserIsNotAdmin)
+	return ok
+}
+
+func (err *ErrUserIsNotAdmin) Error() string {
+	return fmt.Sprintf("User is not an admin [UserID: %d]", err.UserID)
+}
+
+// ErrCodeUserIsNotAdmin holds the unique world-error code of this error
+const ErrCodeUserIsNotAdmin = 1027
+
+// HTTPError holds the http error description
+func (err *ErrUserIsNotAdmin) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusForbidden,
+		Code:     ErrCodeUserIsNotAdmin,
+		Message:  "Only instance admins can do this.",
+	}
+}
+
+// ErrAdminCannotManageSelf represents an error where an admin tries to disable or delete their own account
+// through the admin endpoints.
+type ErrAdminCannotManageSelf struct {
+	UserID int64
+}
+
+// IsErrAdminCannotManageSelf checks if an error is a ErrAdminCannotManageSelf.
+func IsErrAdminCannotManageSelf(err error) bool {
+	_, ok := err.(*ErrAdminCannotManageSelf)
+	return ok
+}
+
+func (err *ErrAdminCannotManageSelf) Error() string {
+	return fmt.Sprintf("Admin cannot disable or delete their own account [UserID: %d]", err.UserID)
+}
+
+// ErrCodeAdminCannotManageSelf holds the unique world-error code of this error
+const ErrCodeAdminCannotManageSelf = 1028
+
+// HTTPError holds the http error description
+func (err *ErrAdminCannotManageSelf) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeAdminCannotManageSelf,
+		Message:  "You cannot disable or delete your own account as an admin.",
+	}
+}
+
+// ErrSessionDoesNotExist represents an error where a session does not exist.
+type ErrSessionDoesNotExist struct {
+	SessionID string
+}
+
+// IsErrSessionDoesNotExist checks if an error is a ErrSessionDoesNotExist.
+func IsErrSessionDoesNotExist(err error) bool {
+	_, ok := err.(*ErrSessionDoesNotExist)
+	return ok
+}
+
+func (err *ErrSessionDoesNotExist) Error() string {
+	return fmt.Sprintf("Session does not exist [SessionID: %s]", err.SessionID)
+}
+
+// ErrCodeSessionDoesNotExist holds the unique world-error code of this error
+const ErrCodeSessionDoesNotExist = 1029
+
+// HTTPError holds the http error description
+func (err *ErrSessionDoesNotExist) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusNotFound,
+		Code:     ErrCodeSessionDoesNotExist,
+		Message:  "This session does not exist.",
+	}
+}
+
+// ErrCannotImpersonateUser represents an error where an admin tries to impersonate themselves, another admin or
+// a disabled user.
+type ErrCannotImpersonateUser struct {
+	UserID int64
+}
+
+// IsErrCannotImpersonateUser checks if an error is a ErrCannotImpersonateUser.
+func IsErrCannotImpersonateUser(err error) bool {
+	_, ok := err.(*ErrCannotImpersonateUser)
+	return ok
+}
+
+func (err *ErrCannotImpersonateUser) Error() string {
+	return fmt.Sprintf("User cannot be impersonated [UserID: %d]", err.UserID)
+}
+
+// ErrCodeCannotImpersonateUser holds the unique world-error code of this error
+const ErrCodeCannotImpersonateUser = 1030
+
+// HTTPError holds the http error description
+func (err *ErrCannotImpersonateUser) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodeCannotImpersonateUser,
+		Message:  "You cannot impersonate yourself, other admins or disabled users.",
+	}
+}
+
+// ErrPasswordTooShort represents an error where a new password is shorter than the configured minimum length.
+type ErrPasswordTooShort struct {
+	MinLength int
+}
+
+// IsErrPasswordTooShort checks if an error is a ErrPasswordTooShort.
+func IsErrPasswordTooShort(err error) bool {
+	_, ok := err.(*ErrPasswordTooShort)
+	return ok
+}
+
+func (err *ErrPasswordTooShort) Error() string {
+	return fmt.Sprintf("Password is too short [MinLength: %d]", err.MinLength)
+}
+
+// ErrCodePasswordTooShort holds the unique world-error code of this error
+const ErrCodePasswordTooShort = 1031
+
+// HTTPError holds the http error description
+func (err *ErrPasswordTooShort) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodePasswordTooShort,
+		Message:  fmt.Sprintf("The password must be at least %d characters long.", err.MinLength),
+	}
+}
+
+// ErrPasswordContainsUsername represents an error where a new password contains the username of its user.
+type ErrPasswordContainsUsername struct{}
+
+// IsErrPasswordContainsUsername checks if an error is a ErrPasswordContainsUsername.
+func IsErrPasswordContainsUsername(err error) bool {
+	_, ok := err.(*ErrPasswordContainsUsername)
+	return ok
+}
+
+func (err *ErrPasswordContainsUsername) Error() string {
+	return "Password contains the username"
+}
+
+// ErrCodePasswordContainsUsername holds the unique world-error code of this error
+const ErrCodePasswordContainsUsername = 1032
+
+// HTTPError holds the http error description
+func (err *ErrPasswordContainsUsername) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodePasswordContainsUsername,
+		Message:  "The password must not contain your username.",
+	}
+}
+
+// ErrPasswordBreached represents an error where a new password was found in a known data breach.
+type ErrPasswordBreached struct{}
+
+// IsErrPasswordBreached checks if an error is a ErrPasswordBreached.
+func IsErrPasswordBreached(err error) bool {
+	_, ok := err.(*ErrPasswordBreached)
+	return ok
+}
+
+func (err *ErrPasswordBreached) Error() string {
+	return "Password was found in a data breach"
+}
+
+// ErrCodePasswordBreached holds the unique world-error code of this error
+const ErrCodePasswordBreached = 1033
+
+// HTTPError holds the http error description
+func (err *ErrPasswordBreached) HTTPError() web.HTTPError {
+	return web.HTTPError{
+		HTTPCode: http.StatusPreconditionFailed,
+		Code:     ErrCodePasswordBreached,
+		Message:  "This password has appeared in a data breach and can't be used. Please choose a different one.",
+	}
+}
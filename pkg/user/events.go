@@ -25,3 +25,15 @@ type CreatedEvent struct {
 func (t *CreatedEvent) Name() string {
 	return "user.created"
 }
+
+// NewLoginEvent represents a NewLoginEvent event, fired after every login. The listener decides if the user logged
+// in from a new device or location.
+type NewLoginEvent struct {
+	User    *User
+	Session *Session
+}
+
+// Name defines the name for NewLoginEvent
+func (t *NewLoginEvent) Name() string {
+	return "user.new.login"
+}
@@ -17,14 +17,20 @@
 package user
 
 import (
+	"encoding/json"
+
+	"code.vikunja.io/api/pkg/db"
 	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/log"
 	"code.vikunja.io/api/pkg/metrics"
 	"code.vikunja.io/api/pkg/modules/keyvalue"
+	"code.vikunja.io/api/pkg/notifications"
 	"github.com/ThreeDotsLabs/watermill/message"
 )
 
 func RegisterListeners() {
 	events.RegisterListener((&CreatedEvent{}).Name(), &IncreaseUserCounter{})
+	events.RegisterListener((&NewLoginEvent{}).Name(), &SendNewLoginNotification{})
 }
 
 ///////
@@ -43,3 +49,51 @@ func (s *IncreaseUserCounter) Name() string {
 func (s *IncreaseUserCounter) Handle(_ *message.Message) (err error) {
 	return keyvalue.IncrBy(metrics.UserCountKey, 1)
 }
+
+// SendNewLoginNotification represents a listener
+type SendNewLoginNotification struct {
+}
+
+// Name defines the name for the SendNewLoginNotification listener
+func (s *SendNewLoginNotification) Name() string {
+	return "send.new.login.notification"
+}
+
+// Handle is executed when the event SendNewLoginNotification listens on is fired
+func (s *SendNewLoginNotification) Handle(msg *message.Message) (err error) {
+	event := &NewLoginEvent{}
+	err = json.Unmarshal(msg.Payload, event)
+	if err != nil {
+		return err
+	}
+
+	sess := db.NewSession()
+	defer sess.Close()
+
+	// Not knowing the location should never keep the user from learning about the login
+	location, err := lookupLoginLocation(event.Session.IPAddress)
+	if err != nil {
+		log.Errorf("Could not look up location of ip address for new login notification: %s", err)
+	}
+	if location != "" {
+		event.Session.Location = location
+		_, err = sess.
+			Where("id = ?", event.Session.ID).
+			Cols("location").
+			Update(event.Session)
+		if err != nil {
+			return err
+		}
+	}
+
+	isNew, err := isNewLogin(sess, event.User, event.Session)
+	if err != nil || !isNew {
+		return err
+	}
+
+	return notifications.Notify(event.User, &NewLoginNotification{
+		User:     event.User,
+		Session:  event.Session,
+		Location: location,
+	})
+}
@@ -0,0 +1,86 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+)
+
+type locationLookupResponse struct {
+	City       string `json:"city"`
+	RegionName string `json:"regionName"`
+	Country    string `json:"country"`
+}
+
+// lookupLoginLocation returns the approximate location of an ip address, like "Berlin, Land Berlin, Germany", using
+// the configured geolocation service. It returns an empty string if no service is configured or the ip address is
+// not a public one.
+func lookupLoginLocation(ip string) (location string, err error) {
+	lookupURL := config.LoginNotificationsLocationURL.GetString()
+	if lookupURL == "" {
+		return "", nil
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil || parsed.IsPrivate() || parsed.IsLoopback() || parsed.IsUnspecified() {
+		return "", nil
+	}
+
+	req, err := http.NewRequestWithContext(
+		context.Background(),
+		http.MethodGet,
+		strings.ReplaceAll(lookupURL, "{ip}", url.PathEscape(ip)),
+		nil,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("location lookup returned status %d", res.StatusCode)
+	}
+
+	result := &locationLookupResponse{}
+	err = json.NewDecoder(res.Body).Decode(result)
+	if err != nil {
+		return "", err
+	}
+
+	parts := []string{}
+	for _, part := range []string{result.City, result.RegionName, result.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", "), nil
+}
@@ -0,0 +1,121 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/notifications"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// AllNotifications can be used as notification name of a preference to make it apply to all notifications which
+// don't have a preference of their own.
+const AllNotifications = "*"
+
+// NotificationPreference decides whether a user receives a notification through a channel.
+// Without a preference, users receive all notifications through all channels.
+type NotificationPreference struct {
+	ID     int64 `xorm:"bigint autoincr not null unique pk" json:"-"`
+	UserID int64 `xorm:"bigint not null INDEX" json:"-"`
+	// The name of the notification, for example `task.comment` or `task.assigned`. Use `*` for all notifications
+	// without a preference of their own.
+	Notification string `xorm:"varchar(250) not null" json:"notification"`
+	// The channel this preference applies to. One of `email`, `in_app`, `push`, `webhook` or `telegram`.
+	Channel notifications.Channel `xorm:"varchar(50) not null" json:"channel"`
+	// Whether the user wants to receive the notification through the channel.
+	Enabled bool `xorm:"bool not null default true" json:"enabled"`
+}
+
+// TableName returns the table name for notification preferences
+func (*NotificationPreference) TableName() string {
+	return "user_notification_preferences"
+}
+
+// GetNotificationPreferences returns all notification preferences of a user
+func GetNotificationPreferences(s *xorm.Session, u *User) (preferences []*NotificationPreference, err error) {
+	preferences = []*NotificationPreference{}
+	err = s.
+		Where("user_id = ?", u.ID).
+		OrderBy("notification asc, channel asc").
+		Find(&preferences)
+	return
+}
+
+// SetNotificationPreferences replaces all notification preferences of a user
+func SetNotificationPreferences(s *xorm.Session, u *User, preferences []*NotificationPreference) (err error) {
+	for _, preference := range preferences {
+		if !isValidChannel(preference.Channel) {
+			return &ErrInvalidNotificationChannel{Channel: string(preference.Channel)}
+		}
+		if preference.Notification == "" {
+			preference.Notification = AllNotifications
+		}
+		preference.ID = 0
+		preference.UserID = u.ID
+	}
+
+	_, err = s.Where("user_id = ?", u.ID).Delete(&NotificationPreference{})
+	if err != nil {
+		return err
+	}
+
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	_, err = s.Insert(&preferences)
+	return err
+}
+
+func isValidChannel(channel notifications.Channel) bool {
+	for _, c := range notifications.Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// ShouldNotifyVia implements notifications.NotifiableWithPreferences. A preference for the notification itself takes
+// precedence over one for all notifications.
+func (u *User) ShouldNotifyVia(channel notifications.Channel, notificationName string) (should bool, err error) {
+	s := db.NewSession()
+	defer s.Close()
+
+	preferences := []*NotificationPreference{}
+	err = s.
+		Where(builder.And(
+			builder.Eq{"user_id": u.ID},
+			builder.Eq{"channel": channel},
+			builder.In("notification", notificationName, AllNotifications),
+		)).
+		Find(&preferences)
+	if err != nil {
+		return false, err
+	}
+
+	should = true
+	for _, preference := range preferences {
+		if preference.Notification == notificationName {
+			return preference.Enabled, nil
+		}
+		should = preference.Enabled
+	}
+	return should, nil
+}
@@ -0,0 +1,91 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/notifications"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationPreferences(t *testing.T) {
+	t.Run("email only for mentions", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &User{ID: 1}
+		err := SetNotificationPreferences(s, u, []*NotificationPreference{
+			{Notification: AllNotifications, Channel: notifications.ChannelMail, Enabled: false},
+			{Notification: "task.mentioned", Channel: notifications.ChannelMail, Enabled: true},
+		})
+		require.NoError(t, err)
+		require.NoError(t, s.Commit())
+
+		should, err := u.ShouldNotifyVia(notifications.ChannelMail, "task.mentioned")
+		require.NoError(t, err)
+		assert.True(t, should)
+
+		should, err = u.ShouldNotifyVia(notifications.ChannelMail, "task.comment")
+		require.NoError(t, err)
+		assert.False(t, should)
+
+		should, err = u.ShouldNotifyVia(notifications.ChannelDB, "task.comment")
+		require.NoError(t, err)
+		assert.True(t, should)
+
+		// Other users are not affected
+		should, err = (&User{ID: 2}).ShouldNotifyVia(notifications.ChannelMail, "task.comment")
+		require.NoError(t, err)
+		assert.True(t, should)
+	})
+	t.Run("replaces existing preferences", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		u := &User{ID: 1}
+		err := SetNotificationPreferences(s, u, []*NotificationPreference{
+			{Notification: "task.comment", Channel: notifications.ChannelDB, Enabled: false},
+		})
+		require.NoError(t, err)
+		err = SetNotificationPreferences(s, u, []*NotificationPreference{
+			{Notification: "task.assigned", Channel: notifications.ChannelDB, Enabled: false},
+		})
+		require.NoError(t, err)
+
+		preferences, err := GetNotificationPreferences(s, u)
+		require.NoError(t, err)
+		require.Len(t, preferences, 1)
+		assert.Equal(t, "task.assigned", preferences[0].Notification)
+	})
+	t.Run("invalid channel", func(t *testing.T) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		defer s.Close()
+
+		err := SetNotificationPreferences(s, &User{ID: 1}, []*NotificationPreference{
+			{Notification: "task.comment", Channel: "carrier-pigeon"},
+		})
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidNotificationChannel(err))
+	})
+}
@@ -0,0 +1,109 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1" // #nosec
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+)
+
+// CheckPasswordPolicy checks a new password against the password rules configured for this instance.
+// The user is used to check if the password contains their username and may be nil if that check should be skipped.
+func CheckPasswordPolicy(password string, u *User) error {
+	minLength := config.PasswordPolicyMinLength.GetInt()
+	if utf8.RuneCountInString(password) < minLength {
+		return &ErrPasswordTooShort{MinLength: minLength}
+	}
+
+	if config.PasswordPolicyDisallowUsername.GetBool() && u != nil && u.Username != "" &&
+		strings.Contains(strings.ToLower(password), strings.ToLower(u.Username)) {
+		return &ErrPasswordContainsUsername{}
+	}
+
+	if config.PasswordPolicyCheckBreached.GetBool() {
+		breached, err := isPasswordBreached(password)
+		if err != nil {
+			// We don't want to lock people out of changing their password only because the api is down.
+			log.Errorf("Could not check if password was breached, accepting it: %s", err)
+			return nil
+		}
+		if breached {
+			return &ErrPasswordBreached{}
+		}
+	}
+
+	return nil
+}
+
+// isPasswordBreached uses the k-anonymity range api of Have I Been Pwned to check if a password appeared in a
+// known data breach. Only the first five characters of the password's hash are sent to the api.
+func isPasswordBreached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password)) // #nosec
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(
+		context.Background(),
+		http.MethodGet,
+		config.PasswordPolicyBreachedCheckURL.GetString()+prefix,
+		nil,
+	)
+	if err != nil {
+		return false, err
+	}
+	// Padding makes sure the size of the response does not leak anything about the prefix
+	req.Header.Set("Add-Padding", "true")
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("breached password check returned status %d", res.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		hashSuffix, count, found := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !found || !strings.EqualFold(hashSuffix, suffix) {
+			continue
+		}
+
+		// Padding entries have a count of 0
+		n, err := strconv.Atoi(count)
+		if err != nil {
+			return false, err
+		}
+		return n > 0, nil
+	}
+
+	return false, scanner.Err()
+}
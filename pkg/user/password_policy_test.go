@@ -0,0 +1,97 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPasswordPolicy(t *testing.T) {
+	u := &User{ID: 1, Username: "user1"}
+
+	t.Run("min length", func(t *testing.T) {
+		config.PasswordPolicyMinLength.Set(10)
+		defer config.PasswordPolicyMinLength.Set(8)
+
+		err := CheckPasswordPolicy("123456789", u)
+		require.Error(t, err)
+		assert.True(t, IsErrPasswordTooShort(err))
+		require.NoError(t, CheckPasswordPolicy("1234567890", u))
+	})
+	t.Run("contains username", func(t *testing.T) {
+		require.NoError(t, CheckPasswordPolicy("myUser1password", u))
+
+		config.PasswordPolicyDisallowUsername.Set(true)
+		defer config.PasswordPolicyDisallowUsername.Set(false)
+
+		err := CheckPasswordPolicy("myUser1password", u)
+		require.Error(t, err)
+		assert.True(t, IsErrPasswordContainsUsername(err))
+		require.NoError(t, CheckPasswordPolicy("somethingelse", u))
+		require.NoError(t, CheckPasswordPolicy("myUser1password", nil))
+	})
+	t.Run("breached", func(t *testing.T) {
+		// sha1("password") = 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+		var requestedPath string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestedPath = r.URL.Path
+			_, _ = w.Write([]byte(strings.Join([]string{
+				"003D68EB55068C33ACE09247EE4C639306B:3",
+				"1E4C9B93F3F0682250B6CF8331B7EE68FD8:9659365",
+				"0A8B0F3E9C8AB2B3EA1D4C3A84A9E0B5F3C:0",
+			}, "\r\n")))
+		}))
+		defer server.Close()
+
+		config.PasswordPolicyCheckBreached.Set(true)
+		config.PasswordPolicyBreachedCheckURL.Set(server.URL + "/range/")
+		defer func() {
+			config.PasswordPolicyCheckBreached.Set(false)
+			config.PasswordPolicyBreachedCheckURL.Set("https://api.pwnedpasswords.com/range/")
+		}()
+
+		err := CheckPasswordPolicy("password", u)
+		require.Error(t, err)
+		assert.True(t, IsErrPasswordBreached(err))
+		assert.Equal(t, "/range/5BAA6", requestedPath)
+
+		require.NoError(t, CheckPasswordPolicy("a very unique password", u))
+	})
+	t.Run("breached check unavailable", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		config.PasswordPolicyCheckBreached.Set(true)
+		config.PasswordPolicyBreachedCheckURL.Set(server.URL + "/range/")
+		defer func() {
+			config.PasswordPolicyCheckBreached.Set(false)
+			config.PasswordPolicyBreachedCheckURL.Set("https://api.pwnedpasswords.com/range/")
+		}()
+
+		require.NoError(t, CheckPasswordPolicy("password", u))
+	})
+}
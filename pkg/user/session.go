@@ -0,0 +1,274 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"net"
+	"time"
+
+	"code.vikunja.io/api/pkg/cron"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/log"
+
+	"github.com/google/uuid"
+	"xorm.io/xorm"
+)
+
+// Session is a login of a user on one device. Every jwt issued to a user after logging in references the session
+// it belongs to, renewing the token keeps the session.
+type Session struct {
+	// The unique id of this session.
+	ID     string `xorm:"varchar(36) not null unique pk" json:"id"`
+	UserID int64  `xorm:"bigint not null index" json:"-"`
+
+	// The user agent of the client which created the session.
+	DeviceInfo string `xorm:"text null" json:"device_info"`
+	// The ip address the session was last used from.
+	IPAddress string `xorm:"varchar(255) null" json:"ip_address"`
+	// The approximate location the session was created from, if a geolocation service is configured.
+	Location string `xorm:"varchar(250) null" json:"location"`
+	// If true, the session was created with "remember me" and stays valid longer.
+	IsLongSession bool `xorm:"bool not null default false" json:"is_long_session"`
+	// Whether this is the session the request listing the sessions was made with.
+	IsCurrent bool `xorm:"-" json:"is_current"`
+
+	// When the session was last used to make a request.
+	LastActive time.Time `xorm:"datetime not null" json:"last_active"`
+	// When the current token of the session expires unless it is renewed.
+	ExpiresAt time.Time `xorm:"datetime not null index" json:"expires_at"`
+	// A timestamp when this session was created. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+}
+
+// TableName returns the table name for user sessions
+func (*Session) TableName() string {
+	return "user_sessions"
+}
+
+// RevokedSession is an entry in the token denylist. Tokens of a revoked session are rejected until they expire.
+type RevokedSession struct {
+	SessionID string    `xorm:"varchar(36) not null unique pk"`
+	ExpiresAt time.Time `xorm:"datetime not null index"`
+}
+
+// TableName returns the table name for revoked sessions
+func (*RevokedSession) TableName() string {
+	return "revoked_sessions"
+}
+
+// lastActiveThreshold is how old the last activity of a session has to be before it is updated again,
+// to avoid writing to the database on every request.
+const lastActiveThreshold = time.Minute
+
+// knownSessionRetention is how long expired sessions are kept around to recognize devices a user logged in from
+// before. Revoked sessions are removed right away.
+const knownSessionRetention = 90 * 24 * time.Hour
+
+// CreateSession creates a new session for a user who just logged in
+func CreateSession(s *xorm.Session, u *User, deviceInfo, ip string, long bool, expiresAt time.Time) (session *Session, err error) {
+	session = &Session{
+		ID:            uuid.NewString(),
+		UserID:        u.ID,
+		DeviceInfo:    deviceInfo,
+		IPAddress:     ip,
+		IsLongSession: long,
+		LastActive:    time.Now(),
+		ExpiresAt:     expiresAt,
+	}
+
+	_, err = s.Insert(session)
+	return
+}
+
+// isNewLogin checks if none of the other sessions of a user from the last 90 days were created with the same browser
+// and operating system from the same location. If the location of a session is unknown, the network of its ip address
+// is compared instead, so a new ip address from the same provider is not considered new.
+// The very first login of a user is not considered new, since there is nothing to compare it to.
+func isNewLogin(s *xorm.Session, u *User, session *Session) (isNew bool, err error) {
+	others := []*Session{}
+	err = s.
+		Where("user_id = ? AND id != ?", u.ID, session.ID).
+		Find(&others)
+	if err != nil || len(others) == 0 {
+		return false, err
+	}
+
+	device := normalizeUserAgent(session.DeviceInfo)
+	for _, other := range others {
+		if normalizeUserAgent(other.DeviceInfo) == device && isSameLocation(other, session) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func isSameLocation(a, b *Session) bool {
+	if a.Location != "" && b.Location != "" {
+		return a.Location == b.Location
+	}
+	return ipNetwork(a.IPAddress) == ipNetwork(b.IPAddress)
+}
+
+// ipNetwork returns the /24 network of an ipv4 address or the /48 network of an ipv6 address.
+func ipNetwork(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return parsed.Mask(net.CIDRMask(48, 128)).String()
+}
+
+// GetSessionByID returns a session of a user
+func GetSessionByID(s *xorm.Session, u *User, id string) (session *Session, err error) {
+	session = &Session{}
+	exists, err := s.
+		Where("id = ? AND user_id = ?", id, u.ID).
+		Get(session)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrSessionDoesNotExist{SessionID: id}
+	}
+	return
+}
+
+// GetSessions returns all active sessions of a user, the most recently used first
+func GetSessions(s *xorm.Session, u *User, currentSessionID string) (sessions []*Session, err error) {
+	sessions = []*Session{}
+	err = s.
+		Where("user_id = ? AND expires_at > ?", u.ID, time.Now()).
+		OrderBy("last_active desc").
+		Find(&sessions)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		session.IsCurrent = session.ID == currentSessionID
+	}
+	return
+}
+
+// RenewSession extends a session when its token is renewed
+func RenewSession(s *xorm.Session, session *Session, expiresAt time.Time) (err error) {
+	session.ExpiresAt = expiresAt
+	_, err = s.
+		ID(session.ID).
+		Cols("expires_at").
+		Update(session)
+	return
+}
+
+// IsSessionRevoked checks if a session is on the token denylist
+func IsSessionRevoked(s *xorm.Session, id string) (revoked bool, err error) {
+	return s.Where("session_id = ?", id).Exist(&RevokedSession{})
+}
+
+// UpdateSessionActivity records that a session was just used from an ip address. To keep this cheap enough to
+// do on every request, sessions are only updated if their last activity is older than a minute.
+func UpdateSessionActivity(s *xorm.Session, id, ip string) (err error) {
+	now := time.Now()
+	_, err = s.
+		Where("id = ? AND last_active < ?", id, now.Add(-lastActiveThreshold)).
+		Cols("last_active", "ip_address").
+		Update(&Session{LastActive: now, IPAddress: ip})
+	return
+}
+
+func revokeSessions(s *xorm.Session, sessions []*Session) (err error) {
+	for _, session := range sessions {
+		_, err = s.Insert(&RevokedSession{
+			SessionID: session.ID,
+			ExpiresAt: session.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = s.Where("id = ?", session.ID).Delete(&Session{})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RevokeSession logs a user out on one device. Tokens of the session are rejected from now on.
+func RevokeSession(s *xorm.Session, u *User, id string) (err error) {
+	session, err := GetSessionByID(s, u, id)
+	if err != nil {
+		return err
+	}
+
+	return revokeSessions(s, []*Session{session})
+}
+
+// RevokeAllSessionsExcept logs a user out on all devices but the one with the given session. If the session id
+// is empty, all sessions are revoked.
+func RevokeAllSessionsExcept(s *xorm.Session, u *User, keepSessionID string) (err error) {
+	sessions := []*Session{}
+	err = s.
+		Where("user_id = ? AND id != ?", u.ID, keepSessionID).
+		Find(&sessions)
+	if err != nil {
+		return err
+	}
+
+	return revokeSessions(s, sessions)
+}
+
+// RegisterSessionCleanupCron registers a cron function to remove sessions which expired more than 90 days ago and
+// denylist entries whose tokens expired anyway
+func RegisterSessionCleanupCron() {
+	const logPrefix = "[User Session Cleanup Cron] "
+
+	err := cron.Schedule("0 * * * *", func() {
+		s := db.NewSession()
+		defer s.Close()
+
+		now := time.Now()
+		deleted, err := s.
+			Where("expires_at < ?", now.Add(-knownSessionRetention)).
+			Delete(&Session{})
+		if err != nil {
+			log.Errorf(logPrefix+"Error removing expired sessions: %s", err)
+			return
+		}
+		if deleted > 0 {
+			log.Debugf(logPrefix+"Deleted %d expired sessions", deleted)
+		}
+
+		deleted, err = s.
+			Where("expires_at < ?", now).
+			Delete(&RevokedSession{})
+		if err != nil {
+			log.Errorf(logPrefix+"Error removing expired revoked sessions: %s", err)
+			return
+		}
+		if deleted > 0 {
+			log.Debugf(logPrefix+"Deleted %d expired revoked sessions", deleted)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Could not register session cleanup cron: %s", err)
+	}
+}
@@ -0,0 +1,239 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+	"code.vikunja.io/api/pkg/events"
+	"code.vikunja.io/api/pkg/notifications"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func TestSessions(t *testing.T) {
+	setup := func(t *testing.T) (*xorm.Session, *User) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		_, err := s.Exec("delete from user_sessions")
+		require.NoError(t, err)
+		_, err = s.Exec("delete from revoked_sessions")
+		require.NoError(t, err)
+		return s, &User{ID: 1}
+	}
+	expiry := time.Now().Add(time.Hour)
+
+	t.Run("list", func(t *testing.T) {
+		s, u := setup(t)
+		defer s.Close()
+
+		first, err := CreateSession(s, u, "Firefox", "127.0.0.1", false, expiry)
+		require.NoError(t, err)
+		_, err = CreateSession(s, u, "Chrome", "127.0.0.2", true, expiry)
+		require.NoError(t, err)
+		_, err = CreateSession(s, &User{ID: 2}, "Safari", "127.0.0.3", false, expiry)
+		require.NoError(t, err)
+		_, err = CreateSession(s, u, "Expired", "127.0.0.4", false, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+
+		sessions, err := GetSessions(s, u, first.ID)
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+		for _, session := range sessions {
+			assert.Equal(t, session.ID == first.ID, session.IsCurrent)
+		}
+	})
+	t.Run("revoke", func(t *testing.T) {
+		s, u := setup(t)
+		defer s.Close()
+
+		session, err := CreateSession(s, u, "Firefox", "127.0.0.1", false, expiry)
+		require.NoError(t, err)
+
+		err = RevokeSession(s, u, session.ID)
+		require.NoError(t, err)
+
+		revoked, err := IsSessionRevoked(s, session.ID)
+		require.NoError(t, err)
+		assert.True(t, revoked)
+		db.AssertMissing(t, "user_sessions", map[string]interface{}{"id": session.ID})
+	})
+	t.Run("revoke a session of another user", func(t *testing.T) {
+		s, u := setup(t)
+		defer s.Close()
+
+		session, err := CreateSession(s, &User{ID: 2}, "Firefox", "127.0.0.1", false, expiry)
+		require.NoError(t, err)
+
+		err = RevokeSession(s, u, session.ID)
+		require.Error(t, err)
+		assert.True(t, IsErrSessionDoesNotExist(err))
+
+		revoked, err := IsSessionRevoked(s, session.ID)
+		require.NoError(t, err)
+		assert.False(t, revoked)
+	})
+	t.Run("revoke all other sessions", func(t *testing.T) {
+		s, u := setup(t)
+		defer s.Close()
+
+		current, err := CreateSession(s, u, "Firefox", "127.0.0.1", false, expiry)
+		require.NoError(t, err)
+		other, err := CreateSession(s, u, "Chrome", "127.0.0.2", false, expiry)
+		require.NoError(t, err)
+
+		err = RevokeAllSessionsExcept(s, u, current.ID)
+		require.NoError(t, err)
+
+		revoked, err := IsSessionRevoked(s, other.ID)
+		require.NoError(t, err)
+		assert.True(t, revoked)
+		revoked, err = IsSessionRevoked(s, current.ID)
+		require.NoError(t, err)
+		assert.False(t, revoked)
+	})
+	t.Run("update activity", func(t *testing.T) {
+		s, u := setup(t)
+		defer s.Close()
+
+		session, err := CreateSession(s, u, "Firefox", "127.0.0.1", false, expiry)
+		require.NoError(t, err)
+
+		// Recent activity is not updated again
+		err = UpdateSessionActivity(s, session.ID, "127.0.0.2")
+		require.NoError(t, err)
+		db.AssertExists(t, "user_sessions", map[string]interface{}{
+			"id":         session.ID,
+			"ip_address": "127.0.0.1",
+		}, false)
+
+		_, err = s.ID(session.ID).Cols("last_active").Update(&Session{LastActive: time.Now().Add(-time.Hour)})
+		require.NoError(t, err)
+		err = UpdateSessionActivity(s, session.ID, "127.0.0.2")
+		require.NoError(t, err)
+		db.AssertExists(t, "user_sessions", map[string]interface{}{
+			"id":         session.ID,
+			"ip_address": "127.0.0.2",
+		}, false)
+	})
+	t.Run("new login", func(t *testing.T) {
+		s, u := setup(t)
+		defer s.Close()
+
+		firefox := "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0"
+
+		// The first login of a user is never new
+		first, err := CreateSession(s, u, firefox, "203.0.113.7", false, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		isNew, err := isNewLogin(s, u, first)
+		require.NoError(t, err)
+		assert.False(t, isNew)
+
+		// A browser update or another address in the same network is the same device and location
+		isNew, err = isNewLogin(s, u, &Session{
+			DeviceInfo: "Mozilla/5.0 (X11; Linux x86_64; rv:129.0) Gecko/20100101 Firefox/129.0",
+			IPAddress:  "203.0.113.42",
+		})
+		require.NoError(t, err)
+		assert.False(t, isNew)
+
+		isNew, err = isNewLogin(s, u, &Session{DeviceInfo: firefox, IPAddress: "198.51.100.7"})
+		require.NoError(t, err)
+		assert.True(t, isNew)
+		isNew, err = isNewLogin(s, u, &Session{
+			DeviceInfo: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36",
+			IPAddress:  "203.0.113.7",
+		})
+		require.NoError(t, err)
+		assert.True(t, isNew)
+
+		// Known locations are compared instead of the ip address
+		_, err = s.Where("id = ?", first.ID).Cols("location").Update(&Session{Location: "Berlin, Berlin, Germany"})
+		require.NoError(t, err)
+		isNew, err = isNewLogin(s, u, &Session{DeviceInfo: firefox, IPAddress: "198.51.100.7", Location: "Berlin, Berlin, Germany"})
+		require.NoError(t, err)
+		assert.False(t, isNew)
+		isNew, err = isNewLogin(s, u, &Session{DeviceInfo: firefox, IPAddress: "203.0.113.7", Location: "Paris, Île-de-France, France"})
+		require.NoError(t, err)
+		assert.True(t, isNew)
+	})
+}
+
+func TestNormalizeUserAgent(t *testing.T) {
+	for userAgent, expected := range map[string]string{
+		"Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0":                                                                  "Firefox on Linux",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36 Edg/126.0.0.0":           "Edge on Windows",
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Mobile Safari/537.36":                   "Chrome on Android",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1": "Safari on iOS",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Safari/605.1.15":                   "Safari on macOS",
+		"curl/8.8.0": "curl",
+		"":           "",
+	} {
+		assert.Equal(t, expected, normalizeUserAgent(userAgent), userAgent)
+	}
+}
+
+func TestSendNewLoginNotification(t *testing.T) {
+	db.LoadAndAssertFixtures(t)
+	s := db.NewSession()
+	defer s.Close()
+	_, err := s.Exec("delete from user_sessions")
+	require.NoError(t, err)
+
+	var requestedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		_, _ = w.Write([]byte(`{"status":"success","country":"Germany","regionName":"Berlin","city":"Berlin"}`))
+	}))
+	defer server.Close()
+
+	config.LoginNotificationsLocationURL.Set(server.URL + "/json/{ip}")
+	defer config.LoginNotificationsLocationURL.Set("")
+
+	location, err := lookupLoginLocation("203.0.113.7")
+	require.NoError(t, err)
+	assert.Equal(t, "Berlin, Berlin, Germany", location)
+	assert.Equal(t, "/json/203.0.113.7", requestedPath)
+
+	// Private addresses are never sent to the service
+	requestedPath = ""
+	location, err = lookupLoginLocation("192.168.1.10")
+	require.NoError(t, err)
+	assert.Empty(t, location)
+	assert.Empty(t, requestedPath)
+
+	u := &User{ID: 1, Username: "user1"}
+	_, err = CreateSession(s, u, "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0", "198.51.100.7", false, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	session, err := CreateSession(s, u, "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36", "203.0.113.7", false, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	notifications.Fake()
+	events.TestListener(t, &NewLoginEvent{User: u, Session: session}, &SendNewLoginNotification{})
+	notifications.AssertSent(t, &NewLoginNotification{})
+	db.AssertExists(t, "user_sessions", map[string]interface{}{
+		"id":       session.ID,
+		"location": "Berlin, Berlin, Germany",
+	}, false)
+}
@@ -104,17 +104,27 @@ func EnrollTOTP(s *xorm.Session, user *User) (t *TOTP, err error) {
 }
 
 // EnableTOTP enables totp for a user. The provided passcode is used to verify the user has a working totp setup.
-func EnableTOTP(s *xorm.Session, passcode *TOTPPasscode) (err error) {
-	t, err := ValidateTOTPPasscode(s, passcode)
+// It returns a new set of recovery codes the user can log in with if they lose access to their totp device.
+func EnableTOTP(s *xorm.Session, passcode *TOTPPasscode) (codes *TOTPRecoveryCodes, err error) {
+	t, err := GetTOTPForUser(s, passcode.User)
 	if err != nil {
 		return
 	}
 
+	// Recovery codes must not be usable to enable totp
+	if !totp.Validate(passcode.Passcode, t.Secret) {
+		return nil, ErrInvalidTOTPPasscode{Passcode: passcode.Passcode}
+	}
+
 	_, err = s.
 		Where("id = ?", t.ID).
 		Cols("enabled").
 		Update(&TOTP{Enabled: true})
-	return
+	if err != nil {
+		return
+	}
+
+	return GenerateTOTPRecoveryCodes(s, passcode.User)
 }
 
 // DisableTOTP removes all totp settings for a user.
@@ -122,20 +132,34 @@ func DisableTOTP(s *xorm.Session, user *User) (err error) {
 	_, err = s.
 		Where("user_id = ?", user.ID).
 		Delete(&TOTP{})
-	return
+	if err != nil {
+		return
+	}
+
+	return deleteTOTPRecoveryCodes(s, user)
 }
 
-// ValidateTOTPPasscode validated totp codes of users.
+// ValidateTOTPPasscode validated totp codes of users. Instead of a passcode, one of the user's recovery codes can be
+// used. Each recovery code is only valid once.
 func ValidateTOTPPasscode(s *xorm.Session, passcode *TOTPPasscode) (t *TOTP, err error) {
 	t, err = GetTOTPForUser(s, passcode.User)
 	if err != nil {
 		return
 	}
 
-	if !totp.Validate(passcode.Passcode, t.Secret) {
+	if totp.Validate(passcode.Passcode, t.Secret) {
+		return
+	}
+
+	used, err := useTOTPRecoveryCode(s, passcode.User, passcode.Passcode)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
 		return nil, ErrInvalidTOTPPasscode{Passcode: passcode.Passcode}
 	}
 
+	log.Infof("User %d logged in with a totp recovery code", passcode.User.ID)
 	return
 }
 
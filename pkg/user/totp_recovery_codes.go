@@ -0,0 +1,114 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"code.vikunja.io/api/pkg/utils"
+
+	"xorm.io/xorm"
+)
+
+// TOTPRecoveryCode is a one-time code a user can log in with instead of a totp passcode, for example when they lost
+// their phone. Only a hash of the code is stored.
+type TOTPRecoveryCode struct {
+	ID       int64  `xorm:"bigint autoincr not null unique pk" json:"-"`
+	UserID   int64  `xorm:"bigint not null index" json:"-"`
+	CodeHash string `xorm:"varchar(64) not null" json:"-"`
+
+	Created time.Time `xorm:"created not null" json:"-"`
+}
+
+// TableName holds the table name for totp recovery codes
+func (t *TOTPRecoveryCode) TableName() string {
+	return "totp_recovery_codes"
+}
+
+// TOTPRecoveryCodes holds the newly generated recovery codes of a user or how many are left.
+type TOTPRecoveryCodes struct {
+	// The recovery codes in plain text. Only returned right after they were generated.
+	Codes []string `json:"codes,omitempty"`
+	// How many recovery codes are left.
+	Remaining int64 `json:"remaining"`
+}
+
+const totpRecoveryCodeCount = 10
+
+func hashTOTPRecoveryCode(code string) string {
+	// Recovery codes are random and long enough that a fast hash is fine. Dashes and case are ignored to make
+	// typing them in easier.
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
+
+// GenerateTOTPRecoveryCodes replaces all recovery codes of a user with new ones and returns them in plain text.
+func GenerateTOTPRecoveryCodes(s *xorm.Session, user *User) (codes *TOTPRecoveryCodes, err error) {
+	err = deleteTOTPRecoveryCodes(s, user)
+	if err != nil {
+		return nil, err
+	}
+
+	codes = &TOTPRecoveryCodes{
+		Codes:     make([]string, 0, totpRecoveryCodeCount),
+		Remaining: totpRecoveryCodeCount,
+	}
+	recoveryCodes := make([]*TOTPRecoveryCode, 0, totpRecoveryCodeCount)
+	for i := 0; i < totpRecoveryCodeCount; i++ {
+		random, err := utils.CryptoRandomString(10)
+		if err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(random[:5] + "-" + random[5:])
+		codes.Codes = append(codes.Codes, code)
+		recoveryCodes = append(recoveryCodes, &TOTPRecoveryCode{
+			UserID:   user.ID,
+			CodeHash: hashTOTPRecoveryCode(code),
+		})
+	}
+
+	_, err = s.Insert(&recoveryCodes)
+	return codes, err
+}
+
+// GetTOTPRecoveryCodesCount returns how many unused recovery codes a user has left.
+func GetTOTPRecoveryCodesCount(s *xorm.Session, user *User) (codes *TOTPRecoveryCodes, err error) {
+	remaining, err := s.Where("user_id = ?", user.ID).Count(&TOTPRecoveryCode{})
+	if err != nil {
+		return nil, err
+	}
+	return &TOTPRecoveryCodes{Remaining: remaining}, nil
+}
+
+// useTOTPRecoveryCode checks if the code is one of the user's recovery codes and invalidates it if it is.
+func useTOTPRecoveryCode(s *xorm.Session, user *User, code string) (used bool, err error) {
+	deleted, err := s.
+		Where("user_id = ? AND code_hash = ?", user.ID, hashTOTPRecoveryCode(code)).
+		Delete(&TOTPRecoveryCode{})
+	return deleted > 0, err
+}
+
+func deleteTOTPRecoveryCodes(s *xorm.Session, user *User) (err error) {
+	_, err = s.
+		Where("user_id = ?", user.ID).
+		Delete(&TOTPRecoveryCode{})
+	return
+}
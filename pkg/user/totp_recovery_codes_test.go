@@ -0,0 +1,116 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"strings"
+	"testing"
+
+	"code.vikunja.io/api/pkg/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func TestTOTPRecoveryCodes(t *testing.T) {
+	setup := func(t *testing.T) (*xorm.Session, *User) {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		_, err := s.Exec("delete from totp_recovery_codes")
+		require.NoError(t, err)
+		_, err = s.Exec("delete from totp")
+		require.NoError(t, err)
+		u := &User{ID: 1, Username: "user1"}
+		_, err = s.Insert(&TOTP{UserID: u.ID, Secret: "JBSWY3DPEHPK3PXP", Enabled: true})
+		require.NoError(t, err)
+		return s, u
+	}
+
+	t.Run("generate", func(t *testing.T) {
+		s, u := setup(t)
+		defer s.Close()
+
+		codes, err := GenerateTOTPRecoveryCodes(s, u)
+		require.NoError(t, err)
+		assert.Len(t, codes.Codes, totpRecoveryCodeCount)
+		assert.Equal(t, int64(totpRecoveryCodeCount), codes.Remaining)
+		assert.Len(t, codes.Codes[0], 11)
+
+		db.AssertMissing(t, "totp_recovery_codes", map[string]interface{}{
+			"user_id":   u.ID,
+			"code_hash": codes.Codes[0],
+		})
+	})
+	t.Run("regenerating invalidates old codes", func(t *testing.T) {
+		s, u := setup(t)
+		defer s.Close()
+
+		old, err := GenerateTOTPRecoveryCodes(s, u)
+		require.NoError(t, err)
+		_, err = GenerateTOTPRecoveryCodes(s, u)
+		require.NoError(t, err)
+
+		remaining, err := GetTOTPRecoveryCodesCount(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(totpRecoveryCodeCount), remaining.Remaining)
+
+		_, err = ValidateTOTPPasscode(s, &TOTPPasscode{User: u, Passcode: old.Codes[0]})
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTOTPPasscode(err))
+	})
+	t.Run("use a code instead of a passcode", func(t *testing.T) {
+		s, u := setup(t)
+		defer s.Close()
+
+		codes, err := GenerateTOTPRecoveryCodes(s, u)
+		require.NoError(t, err)
+
+		_, err = ValidateTOTPPasscode(s, &TOTPPasscode{User: u, Passcode: strings.ToUpper(codes.Codes[3])})
+		require.NoError(t, err)
+
+		remaining, err := GetTOTPRecoveryCodesCount(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(totpRecoveryCodeCount-1), remaining.Remaining)
+	})
+	t.Run("codes can only be used once", func(t *testing.T) {
+		s, u := setup(t)
+		defer s.Close()
+
+		codes, err := GenerateTOTPRecoveryCodes(s, u)
+		require.NoError(t, err)
+
+		_, err = ValidateTOTPPasscode(s, &TOTPPasscode{User: u, Passcode: codes.Codes[0]})
+		require.NoError(t, err)
+		_, err = ValidateTOTPPasscode(s, &TOTPPasscode{User: u, Passcode: codes.Codes[0]})
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidTOTPPasscode(err))
+	})
+	t.Run("disabling totp removes the codes", func(t *testing.T) {
+		s, u := setup(t)
+		defer s.Close()
+
+		_, err := GenerateTOTPRecoveryCodes(s, u)
+		require.NoError(t, err)
+		err = DisableTOTP(s, u)
+		require.NoError(t, err)
+
+		remaining, err := GetTOTPRecoveryCodesCount(s, u)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), remaining.Remaining)
+	})
+}
@@ -44,8 +44,10 @@ type Login struct {
 	Username string `json:"username"`
 	// The password for the user.
 	Password string `json:"password"`
-	// The totp passcode of a user. Only needs to be provided when enabled.
+	// The totp passcode of a user. Only needs to be provided when enabled. Can also be one of the user's recovery codes.
 	TOTPPasscode string `json:"totp_passcode"`
+	// A passkey or security key assertion which can be provided instead of the totp passcode.
+	WebAuthn *WebAuthnAssertion `json:"webauthn"`
 	// If true, the token returned will be valid a lot longer than default. Useful for "remember me" style logins.
 	LongToken bool `json:"long_token"`
 }
@@ -71,6 +73,21 @@ const (
 	StatusDisabled
 )
 
+// AuthSource describes the way a user authenticated for a request.
+type AuthSource string
+
+const (
+	AuthSourceAPIToken AuthSource = "api_token"
+	AuthSourceCaldav   AuthSource = "caldav"
+)
+
+// How often a user gets a digest email
+const (
+	DigestFrequencyNone   = "none"
+	DigestFrequencyDaily  = "daily"
+	DigestFrequencyWeekly = "weekly"
+)
+
 // User holds information about an user
 type User struct {
 	// The unique, numeric id of this user.
@@ -84,6 +101,8 @@ type User struct {
 	Email string `xorm:"varchar(250) null" json:"email,omitempty" valid:"email,length(0|250)" maxLength:"250"`
 
 	Status Status `xorm:"default 0" json:"-"`
+	// Instance admins can manage all other users through the admin api.
+	IsAdmin bool `xorm:"bool not null default false" json:"-"`
 
 	AvatarProvider string `xorm:"varchar(255) null" json:"-"`
 	AvatarFileID   int64  `xorm:"null" json:"-"`
@@ -101,6 +120,14 @@ type User struct {
 	WeekStart                    int    `xorm:"null" json:"-"`
 	Language                     string `xorm:"varchar(50) null" json:"-"`
 	Timezone                     string `xorm:"varchar(255) null" json:"-"`
+	DigestFrequency              string `xorm:"varchar(10) not null default 'none' index" json:"-"`
+	DigestTime                   string `xorm:"varchar(5) not null default '08:00'" json:"-"`
+
+	DigestLastSent time.Time `xorm:"datetime null" json:"-"`
+
+	QuietHoursStart           string    `xorm:"varchar(5) null" json:"-"`
+	QuietHoursEnd             string    `xorm:"varchar(5) null" json:"-"`
+	NotificationsSnoozedUntil time.Time `xorm:"datetime null" json:"-"`
 
 	DeletionScheduledAt      time.Time `xorm:"datetime null" json:"-"`
 	DeletionLastReminderSent time.Time `xorm:"datetime null" json:"-"`
@@ -109,6 +136,13 @@ type User struct {
 
 	ExportFileID int64 `xorm:"bigint null" json:"-"`
 
+	// AuthSource holds the client the user authenticated with for the current request.
+	// It is not persisted and empty for regular web sessions.
+	AuthSource AuthSource `xorm:"-" json:"-"`
+	// APITokenProjectIDs holds the projects the api token used for the current request is limited to.
+	// It is empty if the token is not limited or the user did not authenticate with a token.
+	APITokenProjectIDs []int64 `xorm:"-" json:"-"`
+
 	// A timestamp when this task was created. You cannot change this value.
 	Created time.Time `xorm:"created not null" json:"created"`
 	// A timestamp when this task was last updated. You cannot change this value.
@@ -149,6 +183,14 @@ func (u *User) ShouldNotify() (bool, error) {
 	return user.Status != StatusDisabled, err
 }
 
+// Lang returns the language the user has set, or the default language of the instance
+func (u *User) Lang() string {
+	if u.Language == "" {
+		return config.DefaultSettingsLanguage.GetString()
+	}
+	return u.Language
+}
+
 // GetID implements the Auth interface
 func (u *User) GetID() int64 {
 	return u.ID
@@ -197,8 +239,8 @@ type APIUserPassword struct {
 	ID int64 `json:"id"`
 	// The user's username. Cannot contain anything that looks like an url or whitespaces.
 	Username string `json:"username" valid:"length(3|250),username" minLength:"3" maxLength:"250"`
-	// The user's password in clear text. Only used when registering the user.
-	Password string `json:"password" valid:"length(8|250)" minLength:"8" maxLength:"250"`
+	// The user's password in clear text. Only used when registering the user. The minimum length depends on the password policy of the instance.
+	Password string `json:"password" valid:"length(1|250)" maxLength:"250"`
 	// The user's email address
 	Email string `json:"email" valid:"email,length(0|250)" maxLength:"250"`
 }
@@ -302,6 +344,9 @@ func getUser(s *xorm.Session, user *User, withEmail bool) (userOut *User, err er
 	if userOut.OverdueTasksRemindersTime == "" {
 		userOut.OverdueTasksRemindersTime = "9:00"
 	}
+	if userOut.DigestTime == "" {
+		userOut.DigestTime = "08:00"
+	}
 
 	return userOut, err
 }
@@ -497,6 +542,13 @@ func UpdateUser(s *xorm.Session, user *User, forceOverride bool) (updatedUser *U
 		}
 	}
 
+	if user.DigestFrequency == "" {
+		user.DigestFrequency = DigestFrequencyNone
+	}
+	if user.DigestTime == "" {
+		user.DigestTime = "08:00"
+	}
+
 	// Check if we have a valid time zone
 	if user.Timezone == "" {
 		user.Timezone = config.GetTimeZone().String()
@@ -532,6 +584,10 @@ func UpdateUser(s *xorm.Session, user *User, forceOverride bool) (updatedUser *U
 			"language",
 			"timezone",
 			"overdue_tasks_reminders_time",
+			"digest_frequency",
+			"digest_time",
+			"quiet_hours_start",
+			"quiet_hours_end",
 			"frontend_settings",
 		).
 		Update(user)
@@ -568,6 +624,10 @@ func UpdateUserPassword(s *xorm.Session, user *User, newPassword string) (err er
 		return err
 	}
 
+	if err := CheckPasswordPolicy(newPassword, theUser); err != nil {
+		return err
+	}
+
 	// Hash the new password and set it
 	hashed, err := HashPassword(newPassword)
 	if err != nil {
@@ -0,0 +1,81 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package user
+
+import "strings"
+
+type userAgentPattern struct {
+	token string
+	name  string
+}
+
+// The order matters: most browsers include the tokens of the browsers they are based on, Edge for example sends
+// "Chrome/…" and "Safari/…" as well, and Android user agents also contain "Linux".
+var (
+	browserPatterns = []userAgentPattern{
+		{"Edg", "Edge"},
+		{"OPR/", "Opera"},
+		{"Opera", "Opera"},
+		{"SamsungBrowser/", "Samsung Internet"},
+		{"Vivaldi/", "Vivaldi"},
+		{"Firefox/", "Firefox"},
+		{"FxiOS/", "Firefox"},
+		{"CriOS/", "Chrome"},
+		{"Chromium/", "Chromium"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+	}
+	osPatterns = []userAgentPattern{
+		{"Windows", "Windows"},
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iOS"},
+		{"CrOS", "ChromeOS"},
+		{"Mac OS X", "macOS"},
+		{"Macintosh", "macOS"},
+		{"Linux", "Linux"},
+	}
+)
+
+func matchUserAgent(userAgent string, patterns []userAgentPattern) string {
+	for _, pattern := range patterns {
+		if strings.Contains(userAgent, pattern.token) {
+			return pattern.name
+		}
+	}
+	return ""
+}
+
+// normalizeUserAgent reduces a user agent to the browser family and operating system, like "Firefox on Linux", so
+// that a browser update doesn't look like a new device. Clients which are not a known browser are identified by the
+// product name at the start of the user agent instead.
+func normalizeUserAgent(userAgent string) string {
+	browser := matchUserAgent(userAgent, browserPatterns)
+	if browser == "" {
+		browser, _, _ = strings.Cut(userAgent, "/")
+		browser, _, _ = strings.Cut(browser, " ")
+	}
+
+	os := matchUserAgent(userAgent, osPatterns)
+	if os == "" {
+		return browser
+	}
+	if browser == "" {
+		return os
+	}
+	return browser + " on " + os
+}
@@ -28,6 +28,9 @@ import (
 
 const IssuerLocal = `local`
 
+// IssuerLDAP is the issuer of all users who authenticate through ldap
+const IssuerLDAP = `ldap`
+
 // CreateUser creates a new user and inserts it into the database
 func CreateUser(s *xorm.Session, user *User) (newUser *User, err error) {
 
@@ -67,6 +70,8 @@ func CreateUser(s *xorm.Session, user *User) (newUser *User, err error) {
 	user.WeekStart = config.DefaultSettingsWeekStart.GetInt()
 	user.Language = config.DefaultSettingsLanguage.GetString()
 	user.Timezone = config.DefaultSettingsTimezone.GetString()
+	user.DigestFrequency = DigestFrequencyNone
+	user.DigestTime = "08:00"
 
 	// Insert it
 	_, err = s.Insert(user)
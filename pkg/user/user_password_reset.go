@@ -56,6 +56,11 @@ func ResetPassword(s *xorm.Session, reset *PasswordReset) (err error) {
 		return
 	}
 
+	err = CheckPasswordPolicy(reset.NewPassword, user)
+	if err != nil {
+		return
+	}
+
 	// Hash the password
 	user.Password, err = HashPassword(reset.NewPassword)
 	if err != nil {
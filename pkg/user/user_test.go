@@ -345,7 +345,7 @@ func TestUpdateUserPassword(t *testing.T) {
 
 		err := UpdateUserPassword(s, &User{
 			ID: 1,
-		}, "12345")
+		}, "12345678")
 		require.NoError(t, err)
 	})
 	t.Run("nonexistant user", func(t *testing.T) {
@@ -400,7 +400,7 @@ func TestListUsers(t *testing.T) {
 
 		all, err := ListAllUsers(s)
 		require.NoError(t, err)
-		assert.Len(t, all, 16)
+		assert.Len(t, all, 17)
 	})
 	t.Run("no search term", func(t *testing.T) {
 		db.LoadAndAssertFixtures(t)
@@ -513,7 +513,7 @@ func TestListUsers(t *testing.T) {
 			MatchFuzzily: true,
 		})
 		require.NoError(t, err)
-		assert.Len(t, all, 16)
+		assert.Len(t, all, 17)
 	})
 }
 
@@ -525,7 +525,7 @@ func TestUserPasswordReset(t *testing.T) {
 
 		reset := &PasswordReset{
 			Token:       "passwordresettesttoken",
-			NewPassword: "12345",
+			NewPassword: "12345678",
 		}
 		err := ResetPassword(s, reset)
 		require.NoError(t, err)
@@ -0,0 +1,443 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"net/url"
+	"time"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/log"
+	"code.vikunja.io/api/pkg/modules/keyvalue"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"xorm.io/xorm"
+)
+
+// WebAuthnCredential is a passkey or hardware key a user can log in with.
+type WebAuthnCredential struct {
+	// The unique, numeric id of this credential.
+	ID     int64 `xorm:"bigint autoincr not null unique pk" json:"id" param:"credential"`
+	UserID int64 `xorm:"bigint not null index" json:"-"`
+	// A name to tell the credentials of a user apart, for example "Phone" or "Security key".
+	Name string `xorm:"varchar(250) not null" json:"name"`
+	// The id the authenticator assigned to this credential, base64url encoded.
+	CredentialID string `xorm:"varchar(500) not null unique" json:"credential_id"`
+	// The public key of the credential in COSE format, base64url encoded.
+	PublicKey string `xorm:"text not null" json:"-"`
+	// How often the credential was used according to the authenticator. Used to detect cloned authenticators.
+	SignCount int64 `xorm:"bigint not null default 0" json:"-"`
+
+	// A timestamp when this credential was registered. You cannot change this value.
+	Created time.Time `xorm:"created not null" json:"created"`
+	// A timestamp when this credential was last used to log in.
+	LastUsed time.Time `xorm:"datetime null" json:"last_used"`
+}
+
+// TableName holds the table name for webauthn credentials
+func (c *WebAuthnCredential) TableName() string {
+	return "webauthn_credentials"
+}
+
+// WebAuthnRegistrationOptions are passed to navigator.credentials.create() in the browser. All binary values are
+// base64url encoded.
+type WebAuthnRegistrationOptions = protocol.PublicKeyCredentialCreationOptions
+
+// WebAuthnLoginOptions are passed to navigator.credentials.get() in the browser. All binary values are base64url
+// encoded.
+type WebAuthnLoginOptions = protocol.PublicKeyCredentialRequestOptions
+
+// WebAuthnRegistration is the result of navigator.credentials.create() in the browser
+type WebAuthnRegistration struct {
+	// A name to tell the credentials of a user apart, for example "Phone" or "Security key".
+	Name string `json:"name"`
+	protocol.CredentialCreationResponse
+}
+
+// WebAuthnAssertion is the result of navigator.credentials.get() in the browser
+type WebAuthnAssertion struct {
+	protocol.CredentialAssertionResponse
+	// If true, the token returned will be valid a lot longer than default. Useful for "remember me" style logins.
+	// Only used when logging in with a passkey instead of a password.
+	LongToken bool `json:"long_token"`
+}
+
+const webAuthnTimeout = 5 * time.Minute
+
+type webAuthnChallenge struct {
+	Registration bool
+	Session      webauthn.SessionData
+}
+
+func webAuthnChallengeKey(challenge string) string {
+	return "webauthn-challenge-" + challenge
+}
+
+// newWebAuthn configures the relying party with the public url of the frontend, which authenticators bind
+// credentials to.
+func newWebAuthn() (*webauthn.WebAuthn, error) {
+	publicURL, err := url.Parse(config.ServicePublicURL.GetString())
+	if err != nil {
+		return nil, err
+	}
+
+	return webauthn.New(&webauthn.Config{
+		RPID:                  publicURL.Hostname(),
+		RPDisplayName:         "Vikunja",
+		RPOrigins:             []string{publicURL.Scheme + "://" + publicURL.Host},
+		AttestationPreference: protocol.PreferNoAttestation,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementPreferred,
+			UserVerification: protocol.VerificationPreferred,
+		},
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Enforce: true, Timeout: webAuthnTimeout, TimeoutUVD: webAuthnTimeout},
+			Registration: webauthn.TimeoutConfig{Enforce: true, Timeout: webAuthnTimeout, TimeoutUVD: webAuthnTimeout},
+		},
+	})
+}
+
+// webAuthnUser makes a user and their credentials usable by the webauthn library.
+type webAuthnUser struct {
+	user        *User
+	credentials []*WebAuthnCredential
+}
+
+func newWebAuthnUser(s *xorm.Session, u *User) (*webAuthnUser, error) {
+	credentials, err := GetWebAuthnCredentials(s, u)
+	if err != nil {
+		return nil, err
+	}
+	return &webAuthnUser{user: u, credentials: credentials}, nil
+}
+
+func (wu *webAuthnUser) WebAuthnID() []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(wu.user.ID))
+}
+
+func (wu *webAuthnUser) WebAuthnName() string {
+	return wu.user.Username
+}
+
+func (wu *webAuthnUser) WebAuthnDisplayName() string {
+	return wu.user.GetName()
+}
+
+func (wu *webAuthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (wu *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(wu.credentials))
+	for _, c := range wu.credentials {
+		id, err := base64.RawURLEncoding.DecodeString(c.CredentialID)
+		if err != nil {
+			continue
+		}
+		publicKey, err := base64.RawURLEncoding.DecodeString(c.PublicKey)
+		if err != nil {
+			continue
+		}
+		credentials = append(credentials, webauthn.Credential{
+			ID:            id,
+			PublicKey:     publicKey,
+			Authenticator: webauthn.Authenticator{SignCount: uint32(c.SignCount)},
+		})
+	}
+	return credentials
+}
+
+// webAuthnError turns errors about the response of the authenticator into an ErrInvalidWebAuthnResponse
+func webAuthnError(err error) error {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) {
+		reason := protocolErr.Details
+		if reason == "" {
+			reason = protocolErr.Type
+		}
+		return &ErrInvalidWebAuthnResponse{Reason: reason}
+	}
+	return err
+}
+
+func saveWebAuthnChallenge(session *webauthn.SessionData, registration bool) error {
+	return keyvalue.Put(webAuthnChallengeKey(session.Challenge), webAuthnChallenge{
+		Registration: registration,
+		Session:      *session,
+	})
+}
+
+// consumeWebAuthnChallenge checks a challenge was issued by Vikunja and invalidates it so it can only be used once.
+func consumeWebAuthnChallenge(challenge string, registration bool) (session *webauthn.SessionData, err error) {
+	c := &webAuthnChallenge{}
+	exists, err := keyvalue.GetWithValue(webAuthnChallengeKey(challenge), c)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrInvalidWebAuthnResponse{Reason: "unknown challenge"}
+	}
+
+	err = keyvalue.Del(webAuthnChallengeKey(challenge))
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Registration != registration || time.Now().After(c.Session.Expires) {
+		return nil, &ErrInvalidWebAuthnResponse{Reason: "expired challenge"}
+	}
+
+	return &c.Session, nil
+}
+
+// GetWebAuthnCredentials returns all credentials of a user
+func GetWebAuthnCredentials(s *xorm.Session, u *User) (credentials []*WebAuthnCredential, err error) {
+	credentials = []*WebAuthnCredential{}
+	err = s.
+		Where("user_id = ?", u.ID).
+		OrderBy("id ASC").
+		Find(&credentials)
+	return
+}
+
+// WebAuthnEnabledForUser checks if a user has registered at least one credential.
+func WebAuthnEnabledForUser(s *xorm.Session, u *User) (bool, error) {
+	if !config.ServiceEnableWebAuthn.GetBool() {
+		return false, nil
+	}
+	return s.Where("user_id = ?", u.ID).Exist(&WebAuthnCredential{})
+}
+
+// BeginWebAuthnRegistration returns the options to create a new credential for a user in the browser.
+func BeginWebAuthnRegistration(s *xorm.Session, u *User) (options *WebAuthnRegistrationOptions, err error) {
+	w, err := newWebAuthn()
+	if err != nil {
+		return nil, err
+	}
+
+	wu, err := newWebAuthnUser(s, u)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := []protocol.CredentialDescriptor{}
+	for _, c := range wu.WebAuthnCredentials() {
+		existing = append(existing, c.Descriptor())
+	}
+
+	creation, session, err := w.BeginRegistration(wu, webauthn.WithExclusions(existing))
+	if err != nil {
+		return nil, err
+	}
+
+	err = saveWebAuthnChallenge(session, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &creation.Response, nil
+}
+
+// FinishWebAuthnRegistration verifies the response of the authenticator and saves the new credential.
+func FinishWebAuthnRegistration(s *xorm.Session, u *User, registration *WebAuthnRegistration) (credential *WebAuthnCredential, err error) {
+	w, err := newWebAuthn()
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := registration.CredentialCreationResponse.Parse()
+	if err != nil {
+		return nil, webAuthnError(err)
+	}
+
+	session, err := consumeWebAuthnChallenge(parsed.Response.CollectedClientData.Challenge, true)
+	if err != nil {
+		return nil, err
+	}
+
+	wu, err := newWebAuthnUser(s, u)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := w.CreateCredential(wu, *session, parsed)
+	if err != nil {
+		return nil, webAuthnError(err)
+	}
+
+	credential = &WebAuthnCredential{
+		UserID:       u.ID,
+		Name:         registration.Name,
+		CredentialID: base64.RawURLEncoding.EncodeToString(created.ID),
+		PublicKey:    base64.RawURLEncoding.EncodeToString(created.PublicKey),
+		SignCount:    int64(created.Authenticator.SignCount),
+	}
+	if len(credential.CredentialID) > 500 {
+		return nil, &ErrInvalidWebAuthnResponse{Reason: "credential id is too long"}
+	}
+	if credential.Name == "" {
+		credential.Name = "Passkey"
+	}
+
+	exists, err := s.Where("credential_id = ?", credential.CredentialID).Exist(&WebAuthnCredential{})
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, &ErrInvalidWebAuthnResponse{Reason: "credential is already registered"}
+	}
+
+	_, err = s.Insert(credential)
+	return credential, err
+}
+
+// BeginWebAuthnLogin returns the options to log in with a credential in the browser. If u is nil or has no
+// credentials, any passkey stored on the authenticator can be used.
+func BeginWebAuthnLogin(s *xorm.Session, u *User) (options *WebAuthnLoginOptions, err error) {
+	w, err := newWebAuthn()
+	if err != nil {
+		return nil, err
+	}
+
+	var assertion *protocol.CredentialAssertion
+	var session *webauthn.SessionData
+	if u != nil {
+		wu, err := newWebAuthnUser(s, u)
+		if err != nil {
+			return nil, err
+		}
+		if len(wu.credentials) > 0 {
+			assertion, session, err = w.BeginLogin(wu)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Unknown users get the same options as a login with any passkey to not reveal which users exist
+	if assertion == nil {
+		assertion, session, err = w.BeginDiscoverableLogin()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = saveWebAuthnChallenge(session, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &assertion.Response, nil
+}
+
+// FinishWebAuthnLogin verifies an assertion and returns the user the credential belongs to. If u is not nil, the
+// credential must belong to that user, which is the case when the credential is used as a second factor. Logging in
+// with a passkey alone requires the authenticator to have verified the user, for example with a fingerprint or pin.
+func FinishWebAuthnLogin(s *xorm.Session, u *User, assertion *WebAuthnAssertion) (user *User, err error) {
+	if assertion == nil {
+		return nil, &ErrInvalidWebAuthnResponse{Reason: "missing response"}
+	}
+
+	w, err := newWebAuthn()
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := assertion.CredentialAssertionResponse.Parse()
+	if err != nil {
+		return nil, webAuthnError(err)
+	}
+
+	session, err := consumeWebAuthnChallenge(parsed.Response.CollectedClientData.Challenge, false)
+	if err != nil {
+		return nil, err
+	}
+
+	credential := &WebAuthnCredential{}
+	exists, err := s.Where("credential_id = ?", base64.RawURLEncoding.EncodeToString(parsed.RawID)).Get(credential)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ErrInvalidWebAuthnResponse{Reason: "unknown credential"}
+	}
+	if u != nil && u.ID != credential.UserID {
+		return nil, &ErrInvalidWebAuthnResponse{Reason: "credential belongs to another user"}
+	}
+
+	owner, err := GetUserByID(s, credential.UserID)
+	if err != nil {
+		return nil, err
+	}
+	wu, err := newWebAuthnUser(s, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	var validated *webauthn.Credential
+	if session.UserID == nil {
+		validated, err = w.ValidateDiscoverableLogin(func(_, _ []byte) (webauthn.User, error) {
+			return wu, nil
+		}, *session, parsed)
+	} else {
+		validated, err = w.ValidateLogin(wu, *session, parsed)
+	}
+	if err != nil {
+		return nil, webAuthnError(err)
+	}
+
+	if u == nil && !parsed.Response.AuthenticatorData.Flags.HasUserVerified() {
+		return nil, &ErrInvalidWebAuthnResponse{Reason: "user was not verified by the authenticator"}
+	}
+
+	if validated.Authenticator.CloneWarning {
+		log.Warningf("WebAuthn credential %d of user %d sent sign count %d, expected more than %d. The authenticator may have been cloned.", credential.ID, credential.UserID, parsed.Response.AuthenticatorData.Counter, credential.SignCount)
+		return nil, &ErrInvalidWebAuthnResponse{Reason: "invalid sign count"}
+	}
+
+	credential.SignCount = int64(validated.Authenticator.SignCount)
+	credential.LastUsed = time.Now()
+	_, err = s.
+		Where("id = ?", credential.ID).
+		Cols("sign_count", "last_used").
+		Update(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	if u != nil {
+		return u, nil
+	}
+	return owner, nil
+}
+
+// DeleteWebAuthnCredential removes a credential of a user.
+func DeleteWebAuthnCredential(s *xorm.Session, u *User, credentialID int64) (err error) {
+	deleted, err := s.
+		Where("id = ? AND user_id = ?", credentialID, u.ID).
+		Delete(&WebAuthnCredential{})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return &ErrWebAuthnCredentialDoesNotExist{ID: credentialID}
+	}
+	return nil
+}
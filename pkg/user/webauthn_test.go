@@ -0,0 +1,256 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"code.vikunja.io/api/pkg/config"
+	"code.vikunja.io/api/pkg/db"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+type testAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   protocol.URLEncodedBase64
+	signCount    uint32
+}
+
+func newTestAuthenticator(t *testing.T) *testAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &testAuthenticator{key: key, credentialID: []byte("test-credential-" + t.Name())}
+}
+
+func (a *testAuthenticator) authData(t *testing.T, flags protocol.AuthenticatorFlags, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte("vikunja.example.com"))
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, byte(flags))
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if !attested {
+		return data
+	}
+
+	publicKey, err := webauthncbor.Marshal(&webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: a.key.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(t, err)
+
+	data = append(data, make([]byte, 16)...)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+	data = append(data, a.credentialID...)
+	return append(data, publicKey...)
+}
+
+func clientDataJSON(t *testing.T, ceremony protocol.CeremonyType, challenge, origin string) []byte {
+	data, err := json.Marshal(&protocol.CollectedClientData{Type: ceremony, Challenge: challenge, Origin: origin})
+	require.NoError(t, err)
+	return data
+}
+
+func (a *testAuthenticator) register(t *testing.T, challenge string, userHandle protocol.URLEncodedBase64) *WebAuthnRegistration {
+	a.userHandle = userHandle
+	attestation, err := webauthncbor.Marshal(&struct {
+		Format       string                 `cbor:"fmt"`
+		AttStatement map[string]interface{} `cbor:"attStmt"`
+		AuthData     []byte                 `cbor:"authData"`
+	}{
+		Format:       "none",
+		AttStatement: map[string]interface{}{},
+		AuthData:     a.authData(t, protocol.FlagUserPresent|protocol.FlagUserVerified|protocol.FlagAttestedCredentialData, true),
+	})
+	require.NoError(t, err)
+
+	registration := &WebAuthnRegistration{Name: "Test key"}
+	registration.PublicKeyCredential = a.publicKeyCredential()
+	registration.AttestationResponse.ClientDataJSON = clientDataJSON(t, protocol.CreateCeremony, challenge, "https://vikunja.example.com")
+	registration.AttestationResponse.AttestationObject = attestation
+	return registration
+}
+
+func (a *testAuthenticator) assert(t *testing.T, challenge string, flags protocol.AuthenticatorFlags) *WebAuthnAssertion {
+	a.signCount++
+	authData := a.authData(t, flags, false)
+	rawClientData := clientDataJSON(t, protocol.AssertCeremony, challenge, "https://vikunja.example.com")
+	clientDataHash := sha256.Sum256(rawClientData)
+	hash := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, hash[:])
+	require.NoError(t, err)
+
+	assertion := &WebAuthnAssertion{}
+	assertion.PublicKeyCredential = a.publicKeyCredential()
+	assertion.AssertionResponse.ClientDataJSON = rawClientData
+	assertion.AssertionResponse.AuthenticatorData = authData
+	assertion.AssertionResponse.Signature = signature
+	assertion.AssertionResponse.UserHandle = a.userHandle
+	return assertion
+}
+
+func (a *testAuthenticator) publicKeyCredential() protocol.PublicKeyCredential {
+	credential := protocol.PublicKeyCredential{RawID: a.credentialID}
+	credential.ID = credential.RawID.String()
+	credential.Type = string(protocol.PublicKeyCredentialType)
+	return credential
+}
+
+func registerTestAuthenticator(t *testing.T, s *xorm.Session, u *User) *testAuthenticator {
+	options, err := BeginWebAuthnRegistration(s, u)
+	require.NoError(t, err)
+	assert.Equal(t, "vikunja.example.com", options.RelyingParty.ID)
+
+	a := newTestAuthenticator(t)
+	credential, err := FinishWebAuthnRegistration(s, u, a.register(t, options.Challenge.String(), options.User.ID.(protocol.URLEncodedBase64)))
+	require.NoError(t, err)
+	assert.Equal(t, "Test key", credential.Name)
+	return a
+}
+
+func TestWebAuthn(t *testing.T) {
+	publicURL := config.ServicePublicURL.GetString()
+	config.ServicePublicURL.Set("https://vikunja.example.com/")
+	defer config.ServicePublicURL.Set(publicURL)
+
+	setup := func(t *testing.T) *xorm.Session {
+		db.LoadAndAssertFixtures(t)
+		s := db.NewSession()
+		_, err := s.Exec("delete from webauthn_credentials")
+		require.NoError(t, err)
+		return s
+	}
+
+	t.Run("register and log in with a passkey", func(t *testing.T) {
+		s := setup(t)
+		defer s.Close()
+		u := &User{ID: 1, Username: "user1"}
+		a := registerTestAuthenticator(t, s, u)
+
+		options, err := BeginWebAuthnLogin(s, nil)
+		require.NoError(t, err)
+		loggedIn, err := FinishWebAuthnLogin(s, nil, a.assert(t, options.Challenge.String(), protocol.FlagUserPresent|protocol.FlagUserVerified))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), loggedIn.ID)
+	})
+	t.Run("second factor", func(t *testing.T) {
+		s := setup(t)
+		defer s.Close()
+		u := &User{ID: 1, Username: "user1"}
+		a := registerTestAuthenticator(t, s, u)
+
+		options, err := BeginWebAuthnLogin(s, u)
+		require.NoError(t, err)
+		require.Len(t, options.AllowedCredentials, 1)
+		// Only the user's presence is required when the password was checked already
+		_, err = FinishWebAuthnLogin(s, u, a.assert(t, options.Challenge.String(), protocol.FlagUserPresent))
+		require.NoError(t, err)
+	})
+	t.Run("second factor of another user", func(t *testing.T) {
+		s := setup(t)
+		defer s.Close()
+		a := registerTestAuthenticator(t, s, &User{ID: 1, Username: "user1"})
+
+		options, err := BeginWebAuthnLogin(s, nil)
+		require.NoError(t, err)
+		_, err = FinishWebAuthnLogin(s, &User{ID: 2}, a.assert(t, options.Challenge.String(), protocol.FlagUserPresent))
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidWebAuthnResponse(err))
+	})
+	t.Run("passkey without user verification", func(t *testing.T) {
+		s := setup(t)
+		defer s.Close()
+		a := registerTestAuthenticator(t, s, &User{ID: 1, Username: "user1"})
+
+		options, err := BeginWebAuthnLogin(s, nil)
+		require.NoError(t, err)
+		_, err = FinishWebAuthnLogin(s, nil, a.assert(t, options.Challenge.String(), protocol.FlagUserPresent))
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidWebAuthnResponse(err))
+	})
+	t.Run("challenge can only be used once", func(t *testing.T) {
+		s := setup(t)
+		defer s.Close()
+		a := registerTestAuthenticator(t, s, &User{ID: 1, Username: "user1"})
+
+		options, err := BeginWebAuthnLogin(s, nil)
+		require.NoError(t, err)
+		_, err = FinishWebAuthnLogin(s, nil, a.assert(t, options.Challenge.String(), protocol.FlagUserPresent|protocol.FlagUserVerified))
+		require.NoError(t, err)
+		_, err = FinishWebAuthnLogin(s, nil, a.assert(t, options.Challenge.String(), protocol.FlagUserPresent|protocol.FlagUserVerified))
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidWebAuthnResponse(err))
+	})
+	t.Run("invalid signature", func(t *testing.T) {
+		s := setup(t)
+		defer s.Close()
+		a := registerTestAuthenticator(t, s, &User{ID: 1, Username: "user1"})
+
+		options, err := BeginWebAuthnLogin(s, nil)
+		require.NoError(t, err)
+		other := newTestAuthenticator(t)
+		a.key = other.key
+		_, err = FinishWebAuthnLogin(s, nil, a.assert(t, options.Challenge.String(), protocol.FlagUserPresent|protocol.FlagUserVerified))
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidWebAuthnResponse(err))
+	})
+	t.Run("wrong origin", func(t *testing.T) {
+		s := setup(t)
+		defer s.Close()
+		u := &User{ID: 1, Username: "user1"}
+
+		options, err := BeginWebAuthnRegistration(s, u)
+		require.NoError(t, err)
+		registration := newTestAuthenticator(t).register(t, options.Challenge.String(), options.User.ID.(protocol.URLEncodedBase64))
+		registration.AttestationResponse.ClientDataJSON = clientDataJSON(t, protocol.CreateCeremony, options.Challenge.String(), "https://evil.example.com")
+		_, err = FinishWebAuthnRegistration(s, u, registration)
+		require.Error(t, err)
+		assert.True(t, IsErrInvalidWebAuthnResponse(err))
+	})
+	t.Run("delete", func(t *testing.T) {
+		s := setup(t)
+		defer s.Close()
+		u := &User{ID: 1, Username: "user1"}
+		registerTestAuthenticator(t, s, u)
+
+		credentials, err := GetWebAuthnCredentials(s, u)
+		require.NoError(t, err)
+		require.Len(t, credentials, 1)
+
+		err = DeleteWebAuthnCredential(s, &User{ID: 2}, credentials[0].ID)
+		require.Error(t, err)
+		assert.True(t, IsErrWebAuthnCredentialDoesNotExist(err))
+		err = DeleteWebAuthnCredential(s, u, credentials[0].ID)
+		require.NoError(t, err)
+	})
+}
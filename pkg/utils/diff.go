@@ -0,0 +1,109 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package utils
+
+import "strings"
+
+// maxLineDiffCells limits the size of the table used to find the longest common subsequence of two texts.
+// Texts with more changed lines than that are diffed as if every changed line was replaced.
+const maxLineDiffCells = 1000 * 1000
+
+// LineDiff compares two texts line by line and returns the differences between them. Every line of the result
+// is prefixed with "- " if it was only present in the old text, with "+ " if it was only present in the new text
+// or with two spaces if it is present in both.
+func LineDiff(oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	a := strings.Split(oldText, "\n")
+	b := strings.Split(newText, "\n")
+
+	// Lines which did not change at the start and end don't need to go through the expensive part
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]string, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		lines = append(lines, "  "+line)
+	}
+	lines = append(lines, diffChangedLines(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, "  "+line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func diffChangedLines(a, b []string) []string {
+	lines := make([]string, 0, len(a)+len(b))
+
+	if (len(a)+1)*(len(b)+1) > maxLineDiffCells {
+		for _, line := range a {
+			lines = append(lines, "- "+line)
+		}
+		for _, line := range b {
+			lines = append(lines, "+ "+line)
+		}
+		return lines
+	}
+
+	// lcs[i][j] holds the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+				continue
+			}
+			lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, "  "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, "- "+a[i])
+			i++
+		default:
+			lines = append(lines, "+ "+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, "- "+a[i])
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, "+ "+b[j])
+	}
+
+	return lines
+}
@@ -0,0 +1,67 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLineDiff(t *testing.T) {
+	type args struct {
+		oldText string
+		newText string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Test same text",
+			args: args{oldText: "foo\nbar", newText: "foo\nbar"},
+			want: "",
+		},
+		{
+			name: "Test added line",
+			args: args{oldText: "foo\nbar", newText: "foo\nbaz\nbar"},
+			want: "  foo\n+ baz\n  bar",
+		},
+		{
+			name: "Test removed line",
+			args: args{oldText: "foo\nbaz\nbar", newText: "foo\nbar"},
+			want: "  foo\n- baz\n  bar",
+		},
+		{
+			name: "Test changed line",
+			args: args{oldText: "foo\nbar", newText: "foo\nbaz"},
+			want: "  foo\n- bar\n+ baz",
+		},
+		{
+			name: "Test too many changed lines",
+			args: args{oldText: "foo\n" + strings.Repeat("a\n", 1000) + "bar", newText: "foo\n" + strings.Repeat("b\n", 1000) + "bar"},
+			want: "  foo\n" + strings.Repeat("- a\n", 1000) + strings.Repeat("+ b\n", 1000) + "  bar",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LineDiff(tt.args.oldText, tt.args.newText); got != tt.want {
+				t.Errorf("LineDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"bytes"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// PDFLine is a single line of text in a document rendered with RenderTextPDF.
+type PDFLine struct {
+	Text string
+	Bold bool
+}
+
+const (
+	pdfMargin     = 50 // in points
+	pdfFontSize   = 10
+	pdfLineHeight = 14
+	pdfFontFamily = "text"
+)
+
+// RenderTextPDF renders the given lines of text into a simple, paginated A4 PDF document. Lines which are too long
+// for the page are wrapped at word boundaries. The text is rendered with the given TrueType font and embedded as
+// unicode, without a font the Go fonts are used, which cover latin, greek and cyrillic characters. The same font is
+// used for bold lines when one is given.
+func RenderTextPDF(lines []PDFLine, font []byte) ([]byte, error) {
+	regular, bold := goregular.TTF, gobold.TTF
+	if len(font) > 0 {
+		regular, bold = font, font
+	}
+
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "", regular)
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "B", bold)
+	pdf.AddPage()
+
+	for _, line := range lines {
+		style := ""
+		if line.Bold {
+			style = "B"
+		}
+		pdf.SetFont(pdfFontFamily, style, pdfFontSize)
+		pdf.MultiCell(0, pdfLineHeight, line.Text, "", "L", false)
+	}
+
+	buf := &bytes.Buffer{}
+	err := pdf.Output(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
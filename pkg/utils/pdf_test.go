@@ -0,0 +1,65 @@
+// Vikunja is a to-do list application to facilitate your life.
+// Copyright 2018-present Vikunja and contributors. All rights reserved.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public Licensee as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public Licensee for more details.
+//
+// You should have received a copy of the GNU Affero General Public Licensee
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRenderTextPDF(t *testing.T) {
+	t.Run("single page", func(t *testing.T) {
+		pdf, err := RenderTextPDF([]PDFLine{
+			{Text: "Title (draft)", Bold: true},
+			{Text: "Some text"},
+		}, nil)
+		if err != nil {
+			t.Fatalf("RenderTextPDF() returned an error: %s", err)
+		}
+		if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+			t.Errorf("RenderTextPDF() does not start with a pdf header")
+		}
+		if !bytes.HasSuffix(bytes.TrimSpace(pdf), []byte("%%EOF")) {
+			t.Errorf("RenderTextPDF() does not end with an EOF marker")
+		}
+		if !bytes.Contains(pdf, []byte("/Count 1")) {
+			t.Errorf("RenderTextPDF() does not contain exactly one page")
+		}
+	})
+	t.Run("multiple pages", func(t *testing.T) {
+		lines := make([]PDFLine, 100)
+		pdf, err := RenderTextPDF(lines, nil)
+		if err != nil {
+			t.Fatalf("RenderTextPDF() returned an error: %s", err)
+		}
+		if !bytes.Contains(pdf, []byte("/Count 2")) {
+			t.Errorf("RenderTextPDF() does not contain two pages")
+		}
+	})
+	t.Run("unicode", func(t *testing.T) {
+		pdf, err := RenderTextPDF([]PDFLine{
+			{Text: "Привет мир", Bold: true},
+			{Text: "Καλημέρα κόσμε"},
+		}, nil)
+		if err != nil {
+			t.Fatalf("RenderTextPDF() returned an error: %s", err)
+		}
+		if !bytes.Contains(pdf, []byte("/CIDFontType2")) {
+			t.Errorf("RenderTextPDF() does not embed a unicode font")
+		}
+	})
+}